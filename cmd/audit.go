@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// Rules 'git flow audit' reports on
+const (
+	auditRuleDirectCommit = "direct-commit"
+	auditRuleMerge        = "merge"
+	auditRuleTagPrefix    = "tag-prefix"
+	auditRuleBackMerge    = "back-merge"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report history that bypasses the branching model",
+	Long: `Analyze the existing history of the base branches for violations of the
+configured branching model, for example when adopting git-flow on an existing
+repository:
+
+  direct-commit  commits on the first-parent history of a base branch that
+                 were not merged or squashed from a branch
+  merge          merges of branches that are neither base branches nor start
+                 with a topic branch prefix
+  tag-prefix     tags that do not start with the tag prefix of a branch type
+                 that creates tags
+  back-merge     tags on a base branch that are not merged into the base
+                 branches updated from it, such as a release tag on main that
+                 never reached develop
+
+Topic branches finished with a fast-forward or a rebase leave no merge commit
+behind, so their commits are reported as direct commits; finish with --no-ff
+to keep them apart. Use --since to audit only the history after adopting
+git-flow. The command exits with 0 when no violations are found and with 6
+otherwise.`,
+	Example:     "  git flow audit\n  git flow audit --since 2024-01-01\n  git flow audit --format json --best-effort",
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+		bestEffort, _ := cmd.Flags().GetBool("best-effort")
+		AuditCommand(loadContextOrExit(), since, format, bestEffort)
+	},
+}
+
+// auditViolation is a commit or tag that violates a rule
+type auditViolation struct {
+	Rule    string `json:"rule"`
+	Branch  string `json:"branch,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Message string `json:"message"`
+}
+
+// auditNote explains why a rule, or part of the history, was not checked
+type auditNote struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// auditReport is the outcome of an audit, as printed by --format json
+type auditReport struct {
+	Branches   []string         `json:"branches"`
+	Since      string           `json:"since,omitempty"`
+	Passed     bool             `json:"passed"`
+	Violations []auditViolation `json:"violations"`
+	Skipped    []auditNote      `json:"skipped"`
+}
+
+// skip records why a rule, or part of the history, was not checked
+func (r *auditReport) skip(rule string, format string, args ...interface{}) {
+	r.Skipped = append(r.Skipped, auditNote{Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// Subjects of the commits that bring in a branch: merges as written by git,
+// git-flow and the forges, and git-flow's squash commits
+var (
+	mergeBranchPattern      = regexp.MustCompile(`^Merge branch '([^']+)'`)
+	mergeRemotePattern      = regexp.MustCompile(`^Merge remote-tracking branch '[^'/]+/([^']+)'`)
+	mergePullRequestPattern = regexp.MustCompile(`^Merge pull request #\d+ from [^/\s]+/(\S+)`)
+	mergedInPattern         = regexp.MustCompile(`^Merged in (\S+) \(pull request #\d+\)`)
+	mergeTagPattern         = regexp.MustCompile(`^Merge tag '([^']+)'`)
+	squashPattern           = regexp.MustCompile(`^Squashed commit of branch '([^']+)'`)
+	squashPullRequest       = regexp.MustCompile(`\(#\d+\)$`)
+)
+
+// AuditCommand is the implementation of the audit command. Violations exit
+// with ExitCodeValidationError.
+func AuditCommand(cfgCtx *config.Context, since, format string, bestEffort bool) {
+	if err := executeAudit(cfgCtx, since, format, bestEffort); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeAudit(cfgCtx *config.Context, since, format string, bestEffort bool) error {
+	if format != checkFormatText && format != checkFormatJSON {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unsupported format '%s' (valid options: %s, %s)", format, checkFormatText, checkFormatJSON)}
+	}
+
+	// Validate that git-flow is initialized, or infer its configuration
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
+	if err != nil {
+		return err
+	}
+
+	report, err := auditHistory(cfg, since)
+	if err != nil {
+		return err
+	}
+
+	if format == checkFormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return &errors.GitError{Operation: "encode audit report", Err: err}
+		}
+		fmt.Println(string(data))
+	} else {
+		printAuditReport(report)
+	}
+
+	if !report.Passed {
+		return &errors.AuditError{Violations: len(report.Violations)}
+	}
+	return nil
+}
+
+// auditHistory runs the rules of the branching model against the history of
+// the base branches and their tags
+func auditHistory(cfg *config.Config, since string) (*auditReport, error) {
+	report := &auditReport{Branches: []string{}, Since: since, Violations: []auditViolation{}, Skipped: []auditNote{}}
+
+	refs := make(map[string]string)
+	for _, base := range config.SortedBaseBranches(cfg) {
+		ref := checkRef(cfg, base)
+		if ref == "" {
+			report.skip(auditRuleDirectCommit, "base branch '%s' does not exist", base)
+			continue
+		}
+		refs[base] = ref
+		report.Branches = append(report.Branches, base)
+	}
+
+	if err := auditBaseHistory(cfg, report, refs, since); err != nil {
+		return nil, err
+	}
+	if err := auditTags(cfg, report, refs, since); err != nil {
+		return nil, err
+	}
+	report.Passed = len(report.Violations) == 0
+	return report, nil
+}
+
+// auditBaseHistory checks the commits on the first-parent history of each
+// base branch. A commit that is also on the history of a base branch further
+// down the hierarchy, such as a release fast-forwarded from develop into main,
+// is only checked there.
+func auditBaseHistory(cfg *config.Config, report *auditReport, refs map[string]string, since string) error {
+	logs := make(map[string][]git.LogEntry)
+	for _, base := range report.Branches {
+		entries, err := git.FirstParentLog(refs[base], since)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("read the history of '%s'", base), Err: err}
+		}
+		logs[base] = entries
+	}
+
+	unrecognized := 0
+	for _, base := range report.Branches {
+		below := make(map[string]bool)
+		for _, other := range report.Branches {
+			if other == base || !isDescendantBase(cfg, other, base) {
+				continue
+			}
+			for _, entry := range logs[other] {
+				below[entry.Hash] = true
+			}
+		}
+
+		for _, entry := range logs[base] {
+			if below[entry.Hash] || entry.Parents == 0 {
+				continue
+			}
+			branch, recognized := mergedBranch(entry)
+			if entry.Parents == 1 && !recognized {
+				report.Violations = append(report.Violations, auditViolation{
+					Rule:    auditRuleDirectCommit,
+					Branch:  base,
+					Commit:  entry.Hash,
+					Message: fmt.Sprintf("committed directly on '%s': %s", base, entry.Subject),
+				})
+				continue
+			}
+			if !recognized {
+				unrecognized++
+				continue
+			}
+			if branch == "" || followsModel(cfg, branch) {
+				continue
+			}
+			report.Violations = append(report.Violations, auditViolation{
+				Rule:    auditRuleMerge,
+				Branch:  base,
+				Commit:  entry.Hash,
+				Message: fmt.Sprintf("'%s' merged into '%s' is neither a base branch nor a topic branch", branch, base),
+			})
+		}
+	}
+	if unrecognized > 0 {
+		report.skip(auditRuleMerge, "%d merge(s) with a custom message could not be matched to a branch", unrecognized)
+	}
+	return nil
+}
+
+// mergedBranch returns the branch a commit brought in, judged by its subject.
+// The branch is "" for commits that were recognized without naming one, such
+// as tag merges and pull requests squashed on a forge.
+func mergedBranch(entry git.LogEntry) (string, bool) {
+	if entry.Parents == 1 {
+		if match := squashPattern.FindStringSubmatch(entry.Subject); match != nil {
+			return match[1], true
+		}
+		return "", squashPullRequest.MatchString(entry.Subject)
+	}
+	if branch, ok := mergeSubjectBranch(entry.Subject); ok {
+		return branch, true
+	}
+	return "", mergeTagPattern.MatchString(entry.Subject)
+}
+
+// mergeSubjectBranch returns the branch named by the subject of a merge commit
+func mergeSubjectBranch(subject string) (string, bool) {
+	for _, pattern := range []*regexp.Regexp{mergeBranchPattern, mergeRemotePattern, mergePullRequestPattern, mergedInPattern} {
+		if match := pattern.FindStringSubmatch(subject); match != nil {
+			return match[1], true
+		}
+	}
+	return "", false
+}
+
+// followsModel reports whether a merged branch is a base branch or a topic
+// branch of a configured type
+func followsModel(cfg *config.Config, branch string) bool {
+	if branchConfig, ok := cfg.Branches[branch]; ok && branchConfig.Type == string(config.BranchTypeBase) {
+		return true
+	}
+	_, _, ok := detectTopicType(cfg, branch)
+	return ok
+}
+
+// isDescendantBase reports whether base is updated from ancestor, directly or
+// through other base branches
+func isDescendantBase(cfg *config.Config, base, ancestor string) bool {
+	seen := make(map[string]bool)
+	for parent := cfg.Branches[base].Parent; parent != "" && !seen[parent]; parent = cfg.Branches[parent].Parent {
+		if parent == ancestor {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// auditTags checks the names of the tags and that tags on a base branch
+// reached the base branches that are updated from it
+func auditTags(cfg *config.Config, report *auditReport, refs map[string]string, since string) error {
+	tags, err := git.TagsSince(since)
+	if err != nil {
+		return &errors.GitError{Operation: "list tags", Err: err}
+	}
+
+	prefixes, reason := auditTagPrefixes(cfg)
+	if reason != "" {
+		report.skip(auditRuleTagPrefix, "%s", reason)
+	}
+	var checked []string
+	for _, tag := range tags {
+		if reason != "" || hasAnyPrefix(tag, prefixes) {
+			checked = append(checked, tag)
+			continue
+		}
+		report.Violations = append(report.Violations, auditViolation{
+			Rule:    auditRuleTagPrefix,
+			Tag:     tag,
+			Message: fmt.Sprintf("tag '%s' does not start with a tag prefix (%s)", tag, strings.Join(prefixes, ", ")),
+		})
+	}
+
+	for _, base := range report.Branches {
+		var children []string
+		for _, child := range report.Branches {
+			if childConfig := cfg.Branches[child]; childConfig.Parent == base && childConfig.AutoUpdate {
+				children = append(children, child)
+			}
+		}
+		if len(children) == 0 {
+			continue
+		}
+		onBase, err := git.MergedTags(refs[base], "*")
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("list the tags of '%s'", base), Err: err}
+		}
+		for _, child := range children {
+			onChild, err := git.MergedTags(refs[child], "*")
+			if err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("list the tags of '%s'", child), Err: err}
+			}
+			for _, tag := range checked {
+				if !slices.Contains(onBase, tag) || slices.Contains(onChild, tag) {
+					continue
+				}
+				report.Violations = append(report.Violations, auditViolation{
+					Rule:    auditRuleBackMerge,
+					Branch:  child,
+					Tag:     tag,
+					Message: fmt.Sprintf("tag '%s' on '%s' is not merged into '%s'", tag, base, child),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// auditTagPrefixes returns the tag prefixes of the branch types that create
+// tags and of the version lines. The reason is set when tag names cannot be
+// checked, because a type creates tags without a prefix.
+func auditTagPrefixes(cfg *config.Config) ([]string, string) {
+	names := make([]string, 0, len(cfg.Branches))
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Tag {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var prefixes []string
+	for _, name := range names {
+		branchConfig := cfg.Branches[name]
+		if branchConfig.TagPrefix == "" {
+			return nil, fmt.Sprintf("'%s' creates tags without a prefix (gitflow.branch.%s.tagprefix)", name, name)
+		}
+		if !slices.Contains(prefixes, branchConfig.TagPrefix) {
+			prefixes = append(prefixes, branchConfig.TagPrefix)
+		}
+	}
+	for _, line := range config.VersionLines(cfg) {
+		if line.TagPrefix != "" && !slices.Contains(prefixes, line.TagPrefix) {
+			prefixes = append(prefixes, line.TagPrefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil, "no branch type creates tags"
+	}
+	return prefixes, ""
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// printAuditReport prints the violations found and what was not checked
+func printAuditReport(report *auditReport) {
+	branches := "'" + strings.Join(report.Branches, "', '") + "'"
+	switch {
+	case len(report.Branches) == 0:
+		fmt.Println("No base branches to audit")
+	case report.Since != "":
+		fmt.Printf("History of %s since %s:\n", branches, report.Since)
+	default:
+		fmt.Printf("History of %s:\n", branches)
+	}
+
+	width := 0
+	for _, violation := range report.Violations {
+		if len(violation.Rule) > width {
+			width = len(violation.Rule)
+		}
+	}
+	for _, violation := range report.Violations {
+		where := violation.Tag
+		if violation.Commit != "" {
+			where = violation.Commit[:7]
+		}
+		fmt.Printf("  %-*s  %-7s  %s\n", width, violation.Rule, where, violation.Message)
+	}
+	if len(report.Violations) == 0 {
+		fmt.Println("  No violations found")
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Println("Not checked:")
+		for _, note := range report.Skipped {
+			fmt.Printf("  %s: %s\n", note.Rule, note.Message)
+		}
+	}
+}
+
+func init() {
+	auditCmd.Flags().String("since", "", "Only audit commits made after this date, e.g. 2024-01-01")
+	auditCmd.Flags().String("format", checkFormatText, "Output format (text|json)")
+	auditCmd.Flags().Bool("best-effort", false, "Infer branch names and prefixes from the branches if git-flow is not initialized")
+	rootCmd.AddCommand(auditCmd)
+}
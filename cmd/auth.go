@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Check the credentials for the hosting service",
+	Long: `Check the credentials git-flow uses for the API of the hosting service.
+
+Integrations with GitHub, GitLab and Bitbucket, such as opening pull requests
+without gh or glab, talk to the API with a token found in the environment
+(GH_TOKEN, GITHUB_TOKEN, GITLAB_TOKEN, BITBUCKET_TOKEN), the Git credential
+helpers, or the configuration of gh, hub and glab.
+
+Examples:
+  git-flow auth status
+  git-flow auth status --remote upstream`,
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Verify the API token for the hosting service of a remote",
+	Long: `Find the API token for the hosting service of a remote and verify it with the
+service, before it is needed in the middle of a finish. The account the token
+belongs to and the remaining rate limit are shown.
+
+Example:
+  git-flow auth status`,
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		remote, _ := cmd.Flags().GetString("remote")
+		AuthStatusCommand(remote)
+	},
+}
+
+// AuthStatusCommand is the implementation of the auth status command
+func AuthStatusCommand(remote string) {
+	if err := executeAuthStatus(remote); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// executeAuthStatus discovers the token for the remote's hosting service and
+// verifies it by asking the service whose it is
+func executeAuthStatus(remote string) error {
+	cfgCtx, err := loadContext()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	cfg := cfgCtx.Config
+	if remote == "" {
+		remote = cfg.Remote
+	}
+
+	repo, err := commands.ForgeRepository(newDeps(), cfg, remote, "check credentials")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Remote '%s': %s/%s (%s)\n", remote, repo.Host, repo.Path, repo.Kind)
+	fmt.Printf("  API:            %s\n", forge.APIURL(repo))
+
+	token := repo.DiscoverToken()
+	if token == nil {
+		fmt.Println("  Token:          not found")
+		return &errors.ForgeAuthError{Host: repo.Host}
+	}
+	fmt.Printf("  Token:          %s (from %s)\n", token.Masked(), token.Source)
+
+	client := forge.NewClient(repo, token, forgeCacheDir())
+	user, err := client.CurrentUser()
+	if apiErr, ok := err.(*forge.APIError); ok && apiErr.Unauthorized() {
+		fmt.Println("  Account:        rejected")
+		return &errors.ForgeAuthError{Host: repo.Host, Source: token.Source, Err: err}
+	} else if err != nil {
+		return fmt.Errorf("failed to verify the token with %s: %w", repo.Host, err)
+	}
+	fmt.Printf("  Account:        %s\n", user)
+	if limit := client.RateLimit; limit.Limit > 0 {
+		fmt.Printf("  Rate limit:     %d of %d requests left, resets at %s\n", limit.Remaining, limit.Limit, limit.Reset.Local().Format("15:04:05"))
+	}
+
+	if client, err := repo.PullRequestClient(); err == nil {
+		fmt.Printf("  Pull requests:  opened with %s\n", client)
+	} else {
+		fmt.Println("  Pull requests:  opened through the API")
+	}
+	return nil
+}
+
+// forgeCacheDir returns the directory caching API responses of the hosting
+// service, shared by all worktrees, or "" outside a repository
+func forgeCacheDir() string {
+	commonDir, err := git.GetCommonGitDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(commonDir, "gitflow", "cache", "forge")
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+
+	authStatusCmd.Flags().String("remote", "", "Remote whose hosting service to check (default: the configured remote)")
+}
@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Formats of the check report
+const (
+	checkFormatText = "text"
+	checkFormatJSON = "json"
+)
+
+// Rules 'git flow check' reports on
+const (
+	checkRulePrefix = "prefix"
+	checkRuleName   = "name"
+	checkRuleParent = "parent"
+	checkRuleBehind = "behind"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check [branch]",
+	Short: "Check a branch against the branching model, for CI",
+	Long: `Check that a branch, or the current branch if none is given, follows the
+branching model, so pipelines can enforce it on pushed branches and pull
+requests:
+
+  prefix  the branch is a base branch or starts with a topic branch prefix
+  name    the name after the prefix matches gitflow.check.namePattern
+  parent  the branch was started from its configured parent or start point
+          and contains no commits of other base branches
+  behind  the branch is at most gitflow.check.maxBehind commits behind its
+          parent (--max-behind)
+
+Branches and parents that only exist on the remote, as in a CI clone, are
+checked through their remote-tracking branches. With --format json the report
+is printed as a JSON object.
+
+The command exits with 0 when all checks pass. A violation exits with the code
+of the first rule that failed, in the order above:
+
+  9   prefix
+  10  name
+  11  parent
+  12  behind`,
+	Example:     "  git flow check\n  git flow check feature/login --max-behind 20\n  git flow check \"$BRANCH\" --format json --best-effort",
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		var branch string
+		if len(args) > 0 {
+			branch = args[0]
+		}
+		var maxBehind *int
+		if cmd.Flags().Changed("max-behind") {
+			value, _ := cmd.Flags().GetInt("max-behind")
+			maxBehind = &value
+		}
+		format, _ := cmd.Flags().GetString("format")
+		bestEffort, _ := cmd.Flags().GetBool("best-effort")
+		CheckCommand(loadContextOrExit(), branch, maxBehind, format, bestEffort)
+	},
+}
+
+// checkResult is the outcome of one rule
+type checkResult struct {
+	Rule    string `json:"rule"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// checkReport is the outcome of checking a branch, as printed by --format json
+type checkReport struct {
+	Branch string        `json:"branch"`
+	Type   string        `json:"type,omitempty"`
+	Parent string        `json:"parent,omitempty"`
+	Passed bool          `json:"passed"`
+	Checks []checkResult `json:"checks"`
+}
+
+// add records the outcome of a rule
+func (r *checkReport) add(rule string, passed bool, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, checkResult{Rule: rule, Passed: passed, Message: fmt.Sprintf(format, args...)})
+}
+
+// CheckCommand is the implementation of the check command. Violations exit
+// with the code of the first rule violated, e.g. ExitCodeCheckPrefix.
+func CheckCommand(cfgCtx *config.Context, branch string, maxBehind *int, format string, bestEffort bool) {
+	if err := executeCheck(cfgCtx, branch, maxBehind, format, bestEffort); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeCheck(cfgCtx *config.Context, branch string, maxBehind *int, format string, bestEffort bool) error {
+	if format != checkFormatText && format != checkFormatJSON {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unsupported format '%s' (valid options: %s, %s)", format, checkFormatText, checkFormatJSON)}
+	}
+	if maxBehind != nil && *maxBehind < 0 {
+		return &errors.InvalidInputError{Message: "--max-behind must not be negative"}
+	}
+
+	// Validate that git-flow is initialized, or infer its configuration
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
+	if err != nil {
+		return err
+	}
+
+	if branch == "" {
+		current, err := commands.CurrentBranchFor(newDeps(), "git flow check <branch>")
+		if err != nil {
+			return err
+		}
+		branch = current
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if checkRef(cfg, branch) == "" {
+		return &errors.BranchNotFoundError{BranchName: branch}
+	}
+
+	report, err := checkBranch(cfg, branch, maxBehind)
+	if err != nil {
+		return err
+	}
+
+	if format == checkFormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return &errors.GitError{Operation: "encode check report", Err: err}
+		}
+		fmt.Println(string(data))
+	} else {
+		printCheckReport(report)
+	}
+	reportCheckToActions(report)
+
+	if !report.Passed {
+		checkErr := &errors.PolicyCheckError{BranchName: branch}
+		for _, result := range report.Checks {
+			if !result.Passed {
+				if checkErr.Rule == "" {
+					checkErr.Rule = result.Rule
+				}
+				checkErr.Problems = append(checkErr.Problems, fmt.Sprintf("%s: %s", result.Rule, result.Message))
+			}
+		}
+		return checkErr
+	}
+	return nil
+}
+
+// checkBranch runs the rules of the branching model against branch
+func checkBranch(cfg *config.Config, branch string, maxBehind *int) (*checkReport, error) {
+	report := &checkReport{Branch: branch, Checks: []checkResult{}}
+
+	if branchConfig, ok := cfg.Branches[branch]; ok && branchConfig.Type == string(config.BranchTypeBase) {
+		report.Type = string(config.BranchTypeBase)
+		report.Parent = branchConfig.Parent
+		report.add(checkRulePrefix, true, "'%s' is a base branch", branch)
+		report.Passed = true
+		return report, nil
+	}
+
+	branchType, prefix, ok := detectTopicType(cfg, branch)
+	if !ok {
+		prefixes := []string{}
+		for _, branchConfig := range cfg.Branches {
+			if branchConfig.Type == string(config.BranchTypeTopic) && branchConfig.Prefix != "" {
+				prefixes = append(prefixes, branchConfig.Prefix)
+			}
+		}
+		sort.Strings(prefixes)
+		report.add(checkRulePrefix, false, "no topic branch prefix matches (expected one of: %s)", strings.Join(prefixes, ", "))
+		return report, nil
+	}
+	branchConfig := cfg.Branches[branchType]
+	shortName := strings.TrimPrefix(branch, prefix)
+	report.Type = branchType
+	report.Parent = branchConfig.Parent
+
+	options, err := config.ResolveCheckOptions(cfg, branchType, maxBehind)
+	if err != nil {
+		return nil, &errors.InvalidInputError{Message: err.Error()}
+	}
+
+	if prefix != branchConfig.Prefix {
+		report.add(checkRulePrefix, true, "'%s' is an alias of the %s prefix '%s'", prefix, branchType, branchConfig.Prefix)
+	} else {
+		report.add(checkRulePrefix, true, "'%s' is the %s prefix", prefix, branchType)
+	}
+
+	if options.NamePattern != nil {
+		if options.NamePattern.MatchString(shortName) {
+			report.add(checkRuleName, true, "'%s' matches '%s'", shortName, options.NamePattern)
+		} else {
+			report.add(checkRuleName, false, "'%s' does not match '%s'", shortName, options.NamePattern)
+		}
+	}
+
+	target := checkParent(cfg, report, branch, branchType, branchConfig)
+	if target != "" {
+		checkBehind(report, checkRef(cfg, branch), target, options.MaxBehind)
+	}
+
+	report.Passed = true
+	for _, result := range report.Checks {
+		report.Passed = report.Passed && result.Passed
+	}
+	return report, nil
+}
+
+// checkParent checks that branch was started from an accepted base and
+// contains no commits of other base branches. It returns the ref of the base
+// the branch is compared with, or "" if there is none.
+func checkParent(cfg *config.Config, report *checkReport, branch, branchType string, branchConfig config.BranchConfig) string {
+	// The parent, the start point and a stored base finish accepts
+	accepted := []string{branchConfig.Parent}
+	if branchConfig.StartPoint != "" && branchConfig.StartPoint != branchConfig.Parent {
+		accepted = append(accepted, branchConfig.StartPoint)
+	}
+	base := branchConfig.Parent
+	if stored, _ := git.GetBaseBranch(branch); stored != "" && stored != branchConfig.Parent {
+		switch {
+		case stored == branchConfig.StartPoint:
+		case stored == commands.ActiveStabilization(newDeps(), cfg) || commands.IsTopicBase(newDeps(), cfg, branchType, stored):
+			accepted = append(accepted, stored)
+			base = stored
+		default:
+			report.add(checkRuleParent, false, "started from '%s', expected '%s'", stored, strings.Join(accepted, "' or '"))
+			return ""
+		}
+	}
+
+	baseRef := checkRef(cfg, base)
+	if baseRef == "" {
+		report.add(checkRuleParent, false, "parent '%s' does not exist", base)
+		return ""
+	}
+	branchRef := checkRef(cfg, branch)
+	if _, err := git.MergeBase(branchRef, baseRef); err != nil {
+		report.add(checkRuleParent, false, "shares no history with '%s'", baseRef)
+		return ""
+	}
+
+	acceptedRefs := []string{}
+	for _, name := range accepted {
+		if ref := checkRef(cfg, name); ref != "" {
+			acceptedRefs = append(acceptedRefs, ref)
+		}
+	}
+	foreign := []string{}
+	for _, name := range config.SortedBaseBranches(cfg) {
+		if slices.Contains(accepted, name) {
+			continue
+		}
+		ref := checkRef(cfg, name)
+		if ref == "" {
+			continue
+		}
+		mergeBase, err := git.MergeBase(branchRef, ref)
+		if err != nil {
+			continue
+		}
+		reachable := false
+		for _, acceptedRef := range acceptedRefs {
+			if git.IsAncestor(mergeBase, acceptedRef) {
+				reachable = true
+				break
+			}
+		}
+		if !reachable {
+			foreign = append(foreign, name)
+		}
+	}
+	if len(foreign) > 0 {
+		report.add(checkRuleParent, false, "contains commits of '%s' that are not in '%s'", strings.Join(foreign, "', '"), strings.Join(accepted, "' or '"))
+		return baseRef
+	}
+
+	report.add(checkRuleParent, true, "based on '%s'", baseRef)
+	return baseRef
+}
+
+// checkBehind checks how many commits branchRef lags behind baseRef. A
+// negative limit only reports the count.
+func checkBehind(report *checkReport, branchRef, baseRef string, limit int) {
+	_, behind, err := git.AheadBehind(branchRef, baseRef)
+	switch {
+	case err != nil:
+		report.add(checkRuleBehind, false, "could not compare with '%s'", baseRef)
+	case limit < 0:
+		report.add(checkRuleBehind, true, "%d commit(s) behind '%s'", behind, baseRef)
+	case behind > limit:
+		report.add(checkRuleBehind, false, "%d commit(s) behind '%s', at most %d allowed", behind, baseRef, limit)
+	default:
+		report.add(checkRuleBehind, true, "%d commit(s) behind '%s', at most %d allowed", behind, baseRef, limit)
+	}
+}
+
+// checkRef returns the ref a branch is checked through: the local branch, or
+// its remote-tracking branch in clones without it. It is "" for neither.
+func checkRef(cfg *config.Config, branch string) string {
+	if git.BranchExists(branch) == nil {
+		return branch
+	}
+	if git.RemoteBranchExists(cfg.Remote, branch) {
+		return cfg.Remote + "/" + branch
+	}
+	return ""
+}
+
+// printCheckReport prints the outcome of each rule
+func printCheckReport(report *checkReport) {
+	switch {
+	case report.Type == string(config.BranchTypeBase):
+		fmt.Printf("Branch '%s' (base branch):\n", report.Branch)
+	case report.Type != "":
+		fmt.Printf("Branch '%s' (%s, parent '%s'):\n", report.Branch, report.Type, report.Parent)
+	default:
+		fmt.Printf("Branch '%s':\n", report.Branch)
+	}
+
+	width := 0
+	for _, result := range report.Checks {
+		if len(result.Rule) > width {
+			width = len(result.Rule)
+		}
+	}
+	for _, result := range report.Checks {
+		status := "ok  "
+		if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("  %s  %-*s  %s\n", status, width, result.Rule, result.Message)
+	}
+}
+
+// reportCheckToActions writes whether the branch passed as GitHub Actions
+// step output and the outcome of each rule as job summary
+func reportCheckToActions(report *checkReport) {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "### Branching model check of `%s`\n\n", report.Branch)
+	summary.WriteString("| Rule | Result | Details |\n|------|--------|---------|\n")
+	for _, result := range report.Checks {
+		status := "ok"
+		if !result.Passed {
+			status = "**FAIL**"
+		}
+		fmt.Fprintf(&summary, "| %s | %s | %s |\n", result.Rule, status, strings.ReplaceAll(result.Message, "|", "\\|"))
+	}
+	output.ReportToActions([]output.ActionOutput{
+		{Name: "branch", Value: report.Branch},
+		{Name: "passed", Value: strconv.FormatBool(report.Passed)},
+	}, summary.String())
+}
+
+func init() {
+	checkCmd.Flags().Int("max-behind", 0, "Fail when the branch is more than this many commits behind its parent")
+	checkCmd.Flags().String("format", checkFormatText, "Output format (text|json)")
+	checkCmd.Flags().Bool("best-effort", false, "Infer branch names and prefixes from the branches if git-flow is not initialized")
+	rootCmd.AddCommand(checkCmd)
+}
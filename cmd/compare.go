@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// CompareCommand is the implementation of the compare command for topic branches.
+// If name is empty, the current branch is compared. With printOnly the URL is
+// written to standard output instead of being opened in the browser.
+func CompareCommand(cfgCtx *config.Context, branchType string, name string, printOnly bool) {
+	if err := compare(cfgCtx, branchType, name, printOnly); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// compare builds the compare URL of a topic branch against its parent and opens or prints it
+func compare(cfgCtx *config.Context, branchType string, name string, printOnly bool) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Get configuration
+	cfg := cfgCtx.Config
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Determine branch name - if empty, use current branch
+	fullBranchName := name
+	if name == "" {
+		currentBranch, err := commands.CurrentBranchFor(newDeps(), fmt.Sprintf("git flow %s compare <name>", branchType))
+		if err != nil {
+			return err
+		}
+		if branchConfig.Prefix != "" && !strings.HasPrefix(currentBranch, branchConfig.Prefix) {
+			return fmt.Errorf("current branch '%s' is not a %s branch", currentBranch, branchType)
+		}
+		fullBranchName = currentBranch
+	} else {
+		var err error
+		if fullBranchName, _, err = commands.ResolveTopicName(newDeps(), cfg, branchType, name); err != nil {
+			return err
+		}
+	}
+
+	if err := git.BranchExists(fullBranchName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: fullBranchName}
+	}
+
+	// Compare against the base the branch was started from, falling back to the configured parent
+	parent := branchConfig.Parent
+	if stored, err := git.GetBaseBranch(fullBranchName); err == nil && stored != "" {
+		parent = stored
+	}
+
+	repo, err := commands.ForgeRepository(newDeps(), cfg, cfg.Remote, "build a compare URL")
+	if err != nil {
+		return err
+	}
+
+	url := repo.CompareURL(parent, fullBranchName)
+	if printOnly {
+		fmt.Println(url)
+		return nil
+	}
+
+	fmt.Printf("Opening %s\n", url)
+	if err := forge.OpenBrowser(url); err != nil {
+		return &errors.GitError{Operation: "open browser", Err: err}
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
 	"github.com/gittower/git-flow-next/internal/util"
 	"github.com/spf13/cobra"
 )
@@ -61,7 +63,8 @@ Examples:
 		downstreamStrategy, _ := cmd.Flags().GetString("downstream-strategy")
 		autoUpdate, _ := cmd.Flags().GetBool("auto-update")
 
-		ConfigAddBaseCommand(name, parent, upstreamStrategy, downstreamStrategy, autoUpdate)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigAddBaseCommand(loadContextOrExit(), name, parent, upstreamStrategy, downstreamStrategy, autoUpdate, dryRun)
 	},
 }
 
@@ -76,7 +79,13 @@ Parent branch must be a configured base branch.
 Examples:
   git-flow config add topic feature develop --prefix=feat/
   git-flow config add topic release main --starting-point=develop --tag=true
-  git-flow config add topic hotfix main --upstream-strategy=squash`,
+  git-flow config add topic hotfix main --upstream-strategy=squash
+  git-flow config add topic spike develop --template=shortlived
+
+With --template, the type inherits from the template defined in
+gitflow.template.<template>.*: its branch properties such as prefix and
+upstreamStrategy are copied unless given as options, and its other options
+such as finish.squash apply wherever gitflow.<type>.* does not set them.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
@@ -86,9 +95,15 @@ Examples:
 		startingPoint, _ := cmd.Flags().GetString("starting-point")
 		upstreamStrategy, _ := cmd.Flags().GetString("upstream-strategy")
 		downstreamStrategy, _ := cmd.Flags().GetString("downstream-strategy")
-		tag, _ := cmd.Flags().GetBool("tag")
+		template, _ := cmd.Flags().GetString("template")
+		var tag *bool
+		if cmd.Flags().Changed("tag") {
+			value, _ := cmd.Flags().GetBool("tag")
+			tag = &value
+		}
 
-		ConfigAddTopicCommand(name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigAddTopicCommand(loadContextOrExit(), name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, template, tag, dryRun)
 	},
 }
 
@@ -114,7 +129,8 @@ Examples:
 		downstreamStrategy, _ := cmd.Flags().GetString("downstream-strategy")
 		autoUpdate, _ := cmd.Flags().GetBool("auto-update")
 
-		ConfigEditBaseCommand(name, upstreamStrategy, downstreamStrategy, autoUpdate)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigEditBaseCommand(loadContextOrExit(), name, upstreamStrategy, downstreamStrategy, autoUpdate, dryRun)
 	},
 }
 
@@ -136,7 +152,8 @@ Examples:
 		downstreamStrategy, _ := cmd.Flags().GetString("downstream-strategy")
 		tag, _ := cmd.Flags().GetBool("tag")
 
-		ConfigEditTopicCommand(name, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigEditTopicCommand(loadContextOrExit(), name, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag, dryRun)
 	},
 }
 
@@ -161,7 +178,8 @@ Examples:
 		oldName := args[0]
 		newName := args[1]
 
-		ConfigRenameBaseCommand(oldName, newName)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigRenameBaseCommand(loadContextOrExit(), oldName, newName, dryRun)
 	},
 }
 
@@ -170,17 +188,22 @@ var configRenameTopicCmd = &cobra.Command{
 	Short: "Rename a topic branch type",
 	Long: `Rename a topic branch type configuration.
 
-This only updates the configuration, not any existing branches.
+By default this only updates the configuration, not any existing branches.
+With --apply-to-branches, a prefix that follows the type name (feature/) is
+renamed along with the type (feat/), and existing branches using the old prefix
+are renamed together with their stored base branch.
 
 Examples:
   git-flow config rename topic feature feat
-  git-flow config rename topic bugfix fix`,
+  git-flow config rename topic bugfix fix --apply-to-branches`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		oldName := args[0]
 		newName := args[1]
 
-		ConfigRenameTopicCommand(oldName, newName)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		applyToBranches, _ := cmd.Flags().GetBool("apply-to-branches")
+		ConfigRenameTopicCommand(loadContextOrExit(), oldName, newName, applyToBranches, dryRun)
 	},
 }
 
@@ -205,7 +228,8 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 
-		ConfigDeleteBaseCommand(name)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigDeleteBaseCommand(loadContextOrExit(), name, dryRun)
 	},
 }
 
@@ -223,7 +247,8 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 
-		ConfigDeleteTopicCommand(name)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ConfigDeleteTopicCommand(loadContextOrExit(), name, dryRun)
 	},
 }
 
@@ -234,146 +259,152 @@ var configListCmd = &cobra.Command{
 
 Shows the branch hierarchy, merge strategies, and other settings.
 
-Example:
-  git-flow config list`,
+Use --type and --name to show only some branches, and --format json or toml
+to get them in a form scripts can read. The machine-readable formats use the
+branches section of 'git flow config export'.
+
+Examples:
+  git-flow config list
+  git-flow config list --type topic
+  git-flow config list --name feature --format json`,
+	Annotations: dataOutputAnnotations,
 	Run: func(cmd *cobra.Command, args []string) {
-		ConfigListCommand()
+		branchType, _ := cmd.Flags().GetString("type")
+		name, _ := cmd.Flags().GetString("name")
+		format, _ := cmd.Flags().GetString("format")
+		ConfigListCommand(loadContextOrExit(), branchType, name, format)
 	},
 }
 
 // ConfigAddBaseCommand adds a base branch configuration
-func ConfigAddBaseCommand(name, parent, upstreamStrategy, downstreamStrategy string, autoUpdate bool) {
-	if err := executeConfigAddBase(name, parent, upstreamStrategy, downstreamStrategy, autoUpdate); err != nil {
+func ConfigAddBaseCommand(cfgCtx *config.Context, name, parent, upstreamStrategy, downstreamStrategy string, autoUpdate bool, dryRun bool) {
+	if err := executeConfigAddBase(cfgCtx, name, parent, upstreamStrategy, downstreamStrategy, autoUpdate, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigAddTopicCommand adds a topic branch type configuration
-func ConfigAddTopicCommand(name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool) {
-	if err := executeConfigAddTopic(name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag); err != nil {
+func ConfigAddTopicCommand(cfgCtx *config.Context, name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, template string, tag *bool, dryRun bool) {
+	if err := executeConfigAddTopic(cfgCtx, name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, template, tag, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigEditBaseCommand edits a base branch configuration
-func ConfigEditBaseCommand(name, upstreamStrategy, downstreamStrategy string, autoUpdate bool) {
-	if err := executeConfigEditBase(name, upstreamStrategy, downstreamStrategy, autoUpdate); err != nil {
+func ConfigEditBaseCommand(cfgCtx *config.Context, name, upstreamStrategy, downstreamStrategy string, autoUpdate bool, dryRun bool) {
+	if err := executeConfigEditBase(cfgCtx, name, upstreamStrategy, downstreamStrategy, autoUpdate, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigEditTopicCommand edits a topic branch type configuration
-func ConfigEditTopicCommand(name, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool) {
-	if err := executeConfigEditTopic(name, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag); err != nil {
+func ConfigEditTopicCommand(cfgCtx *config.Context, name, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool, dryRun bool) {
+	if err := executeConfigEditTopic(cfgCtx, name, prefix, startingPoint, upstreamStrategy, downstreamStrategy, tag, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigRenameBaseCommand renames a base branch
-func ConfigRenameBaseCommand(oldName, newName string) {
-	if err := executeConfigRenameBase(oldName, newName); err != nil {
+func ConfigRenameBaseCommand(cfgCtx *config.Context, oldName, newName string, dryRun bool) {
+	if err := executeConfigRenameBase(cfgCtx, oldName, newName, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigRenameTopicCommand renames a topic branch type
-func ConfigRenameTopicCommand(oldName, newName string) {
-	if err := executeConfigRenameTopic(oldName, newName); err != nil {
+func ConfigRenameTopicCommand(cfgCtx *config.Context, oldName, newName string, applyToBranches bool, dryRun bool) {
+	if err := executeConfigRenameTopic(cfgCtx, oldName, newName, applyToBranches, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigDeleteBaseCommand deletes a base branch configuration
-func ConfigDeleteBaseCommand(name string) {
-	if err := executeConfigDeleteBase(name); err != nil {
+func ConfigDeleteBaseCommand(cfgCtx *config.Context, name string, dryRun bool) {
+	if err := executeConfigDeleteBase(cfgCtx, name, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigDeleteTopicCommand deletes a topic branch type configuration
-func ConfigDeleteTopicCommand(name string) {
-	if err := executeConfigDeleteTopic(name); err != nil {
+func ConfigDeleteTopicCommand(cfgCtx *config.Context, name string, dryRun bool) {
+	if err := executeConfigDeleteTopic(cfgCtx, name, dryRun); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // ConfigListCommand lists the current configuration
-func ConfigListCommand() {
-	if err := executeConfigList(); err != nil {
+func ConfigListCommand(cfgCtx *config.Context, branchType, name, format string) {
+	if err := executeConfigList(cfgCtx, branchType, name, format); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
-func executeConfigAddBase(name, parent, upstreamStrategy, downstreamStrategy string, autoUpdate bool) error {
+func executeConfigAddBase(cfgCtx *config.Context, name, parent, upstreamStrategy, downstreamStrategy string, autoUpdate bool, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
@@ -382,11 +413,8 @@ func executeConfigAddBase(name, parent, upstreamStrategy, downstreamStrategy str
 		return &errors.InvalidBranchNameError{BranchName: name}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if branch name already exists
 	if _, exists := cfg.Branches[name]; exists {
@@ -434,30 +462,33 @@ func executeConfigAddBase(name, parent, upstreamStrategy, downstreamStrategy str
 	cfg.Branches[name] = branchConfig
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, nil, configChange{action: hooks.HookActionConfig, change: "add-base", branch: name}, dryRun); err != nil {
+		return err
 	}
 
 	// Create Git branch if it doesn't exist
 	if err := git.BranchExists(name); err != nil {
+		if dryRun {
+			fmt.Printf("Would create branch '%s'\n", name)
+			return nil
+		}
 		// Branch doesn't exist, create it
 		if err := git.CreateBranch(name, parent); err != nil {
 			return &errors.GitError{Operation: fmt.Sprintf("create branch '%s'", name), Err: err}
 		}
 		fmt.Printf("✓ Created branch '%s'\n", name)
 	}
+	if dryRun {
+		return nil
+	}
 
 	fmt.Printf("✓ Added base branch: %s\n", name)
 	return nil
 }
 
-func executeConfigAddTopic(name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool) error {
+func executeConfigAddTopic(cfgCtx *config.Context, name, parent, prefix, startingPoint, upstreamStrategy, downstreamStrategy, template string, tag *bool, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
@@ -466,22 +497,51 @@ func executeConfigAddTopic(name, parent, prefix, startingPoint, upstreamStrategy
 		return &errors.InvalidBranchNameError{BranchName: name}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if branch name already exists
 	if _, exists := cfg.Branches[name]; exists {
 		return &errors.BranchExistsError{BranchName: name}
 	}
 
+	// Topic types become commands, so they can't share a name with a built-in one
+	if err := validateTopicTypeName(name); err != nil {
+		return err
+	}
+
 	// Validate parent exists
 	if _, exists := cfg.Branches[parent]; !exists {
 		return &errors.BranchNotFoundError{BranchName: parent}
 	}
 
+	// Start from the branch properties of the template, if any
+	var branchConfig config.BranchConfig
+	if template != "" {
+		options := config.TemplateOptions(cfg, template)
+		if len(options) == 0 {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("template '%s' is not defined (set options such as %s)", template, config.TemplateKey(template, config.PropPrefix))}
+		}
+		config.ApplyTemplate(&branchConfig, options)
+	}
+
+	// Options override the template
+	if prefix == "" {
+		prefix = branchConfig.Prefix
+	}
+	if startingPoint == "" {
+		startingPoint = branchConfig.StartPoint
+	}
+	if upstreamStrategy == "" {
+		upstreamStrategy = branchConfig.UpstreamStrategy
+	}
+	if downstreamStrategy == "" {
+		downstreamStrategy = branchConfig.DownstreamStrategy
+	}
+	if tag != nil {
+		branchConfig.Tag = *tag
+	}
+
 	// Set defaults
 	if prefix == "" {
 		prefix = name + "/"
@@ -510,43 +570,37 @@ func executeConfigAddTopic(name, parent, prefix, startingPoint, upstreamStrategy
 	}
 
 	// Create branch configuration
-	branchConfig := config.BranchConfig{
-		Type:               string(config.BranchTypeTopic),
-		Parent:             parent,
-		StartPoint:         startingPoint,
-		UpstreamStrategy:   upstreamStrategy,
-		DownstreamStrategy: downstreamStrategy,
-		Prefix:             prefix,
-		Tag:                tag,
-	}
+	branchConfig.Type = string(config.BranchTypeTopic)
+	branchConfig.Parent = parent
+	branchConfig.StartPoint = startingPoint
+	branchConfig.UpstreamStrategy = upstreamStrategy
+	branchConfig.DownstreamStrategy = downstreamStrategy
+	branchConfig.Prefix = prefix
+	branchConfig.Template = template
 
 	// Add to configuration
 	cfg.Branches[name] = branchConfig
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, nil, configChange{action: hooks.HookActionConfig, change: "add-topic", branch: name}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Added topic branch type: %s\n", name)
 	return nil
 }
 
-func executeConfigEditBase(name, upstreamStrategy, downstreamStrategy string, autoUpdate bool) error {
+func executeConfigEditBase(cfgCtx *config.Context, name, upstreamStrategy, downstreamStrategy string, autoUpdate bool, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if branch exists
 	branchConfig, exists := cfg.Branches[name]
@@ -581,29 +635,25 @@ func executeConfigEditBase(name, upstreamStrategy, downstreamStrategy string, au
 	cfg.Branches[name] = branchConfig
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, nil, configChange{action: hooks.HookActionConfig, change: "edit-base", branch: name}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Updated base branch: %s\n", name)
 	return nil
 }
 
-func executeConfigEditTopic(name, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool) error {
+func executeConfigEditTopic(cfgCtx *config.Context, name, prefix, startingPoint, upstreamStrategy, downstreamStrategy string, tag bool, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if branch exists
 	branchConfig, exists := cfg.Branches[name]
@@ -648,21 +698,20 @@ func executeConfigEditTopic(name, prefix, startingPoint, upstreamStrategy, downs
 	cfg.Branches[name] = branchConfig
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, nil, configChange{action: hooks.HookActionConfig, change: "edit-topic", branch: name}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Updated topic branch type: %s\n", name)
 	return nil
 }
 
-func executeConfigRenameBase(oldName, newName string) error {
+func executeConfigRenameBase(cfgCtx *config.Context, oldName, newName string, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
@@ -671,11 +720,8 @@ func executeConfigRenameBase(oldName, newName string) error {
 		return &errors.InvalidBranchNameError{BranchName: newName}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if old branch exists
 	branchConfig, exists := cfg.Branches[oldName]
@@ -695,15 +741,14 @@ func executeConfigRenameBase(oldName, newName string) error {
 
 	// Rename Git branch if it exists
 	if err := git.BranchExists(oldName); err == nil {
-		if err := git.RenameBranch(oldName, newName); err != nil {
-			return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", oldName, newName), Err: err}
+		if dryRun {
+			fmt.Printf("Would rename Git branch: %s → %s\n", oldName, newName)
+		} else {
+			if err := git.RenameBranch(oldName, newName); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", oldName, newName), Err: err}
+			}
+			fmt.Printf("✓ Renamed Git branch: %s → %s\n", oldName, newName)
 		}
-		fmt.Printf("✓ Renamed Git branch: %s → %s\n", oldName, newName)
-	}
-
-	// Remove old branch config from git config
-	if err := git.UnsetConfigSection(fmt.Sprintf("gitflow.branch.%s", oldName)); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("remove old branch config for '%s'", oldName), Err: err}
 	}
 
 	// Update configuration
@@ -723,21 +768,20 @@ func executeConfigRenameBase(oldName, newName string) error {
 	}
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, []string{oldName}, configChange{action: hooks.HookActionConfig, change: "rename-base", branch: newName, oldBranch: oldName}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Renamed base branch: %s → %s\n", oldName, newName)
 	return nil
 }
 
-func executeConfigRenameTopic(oldName, newName string) error {
+func executeConfigRenameTopic(cfgCtx *config.Context, oldName, newName string, applyToBranches bool, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
@@ -746,11 +790,8 @@ func executeConfigRenameTopic(oldName, newName string) error {
 		return &errors.InvalidBranchNameError{BranchName: newName}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if old branch exists
 	branchConfig, exists := cfg.Branches[oldName]
@@ -768,35 +809,126 @@ func executeConfigRenameTopic(oldName, newName string) error {
 		return &errors.BranchExistsError{BranchName: newName}
 	}
 
+	// Topic types become commands, so they can't share a name with a built-in one
+	if err := validateTopicTypeName(newName); err != nil {
+		return err
+	}
+
+	// A prefix named after the type follows it; a custom prefix is kept as is
+	oldPrefix := branchConfig.Prefix
+	newPrefix := oldPrefix
+	if oldPrefix == oldName+"/" {
+		newPrefix = newName + "/"
+	}
+
+	// Existing branches that still carry the old prefix
+	renames, err := topicBranchRenames(oldPrefix, newPrefix)
+	if err != nil {
+		return err
+	}
+
+	removedBranches := []string{oldName}
+	if applyToBranches {
+		branchConfig.Prefix = newPrefix
+		if err := renameTopicBranches(cfg, renames, dryRun); err != nil {
+			return err
+		}
+		for _, rename := range renames {
+			removedBranches = append(removedBranches, rename.oldBranch)
+		}
+	}
+
 	// Update configuration
 	delete(cfg.Branches, oldName)
 	cfg.Branches[newName] = branchConfig
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, removedBranches, configChange{action: hooks.HookActionConfig, change: "rename-topic", branch: newName, oldBranch: oldName}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Renamed topic branch type: %s → %s\n", oldName, newName)
+	if applyToBranches && newPrefix != oldPrefix {
+		fmt.Printf("✓ Renamed prefix: %s → %s (%d branch(es) renamed)\n", oldPrefix, newPrefix, len(renames))
+	} else if !applyToBranches && newPrefix != oldPrefix && len(renames) > 0 {
+		fmt.Printf("%d existing branch(es) keep the prefix '%s'; use --apply-to-branches to rename them to '%s'\n", len(renames), oldPrefix, newPrefix)
+	}
 	return nil
 }
 
-func executeConfigDeleteBase(name string) error {
-	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
+// topicBranchRename describes a local branch moved to a new topic prefix
+type topicBranchRename struct {
+	oldBranch string
+	newBranch string
+	base      string // Stored base branch, empty if none
+}
+
+// topicBranchRenames lists the local branches that use oldPrefix together with
+// their name under newPrefix. Nothing needs renaming when the prefix is unchanged.
+func topicBranchRenames(oldPrefix, newPrefix string) ([]topicBranchRename, error) {
+	if oldPrefix == newPrefix || oldPrefix == "" {
+		return nil, nil
+	}
+
+	branches, err := git.ListBranches()
 	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+		return nil, &errors.GitError{Operation: "list branches", Err: err}
 	}
-	if !initialized {
-		return &errors.NotInitializedError{}
+
+	renames := []topicBranchRename{}
+	for _, branch := range branches {
+		if !strings.HasPrefix(branch, oldPrefix) {
+			continue
+		}
+		rename := topicBranchRename{oldBranch: branch, newBranch: newPrefix + strings.TrimPrefix(branch, oldPrefix)}
+		rename.base, _ = git.GetBaseBranch(branch)
+		renames = append(renames, rename)
 	}
+	return renames, nil
+}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+// renameTopicBranches renames the given branches and moves their stored base
+// branch to the new name. All target names are checked before anything changes.
+func renameTopicBranches(cfg *config.Config, renames []topicBranchRename, dryRun bool) error {
+	for _, rename := range renames {
+		if err := git.BranchExists(rename.newBranch); err == nil {
+			return &errors.BranchExistsError{BranchName: rename.newBranch}
+		}
+	}
+
+	for _, rename := range renames {
+		// Stored base keys are read as pseudo branch entries; they move below
+		delete(cfg.Branches, rename.oldBranch)
+
+		if dryRun {
+			fmt.Printf("Would rename Git branch: %s → %s\n", rename.oldBranch, rename.newBranch)
+			continue
+		}
+		if err := git.RenameBranch(rename.oldBranch, rename.newBranch); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", rename.oldBranch, rename.newBranch), Err: err}
+		}
+		if rename.base != "" {
+			if err := git.SetBaseBranch(rename.newBranch, rename.base); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("store base branch for '%s'", rename.newBranch), Err: err}
+			}
+		}
+		fmt.Printf("✓ Renamed Git branch: %s → %s\n", rename.oldBranch, rename.newBranch)
+	}
+	return nil
+}
+
+func executeConfigDeleteBase(cfgCtx *config.Context, name string, dryRun bool) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
 	}
 
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
+
 	// Check if branch exists
 	branchConfig, exists := cfg.Branches[name]
 	if !exists {
@@ -818,17 +950,15 @@ func executeConfigDeleteBase(name string) error {
 		}
 	}
 
-	// Remove branch config from git config
-	if err := git.UnsetConfigSection(fmt.Sprintf("gitflow.branch.%s", name)); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("remove branch config for '%s'", name), Err: err}
-	}
-
 	// Remove from configuration
 	delete(cfg.Branches, name)
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, []string{name}, configChange{action: hooks.HookActionConfig, change: "delete-base", branch: name}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Deleted base branch configuration: %s\n", name)
@@ -836,21 +966,14 @@ func executeConfigDeleteBase(name string) error {
 	return nil
 }
 
-func executeConfigDeleteTopic(name string) error {
+func executeConfigDeleteTopic(cfgCtx *config.Context, name string, dryRun bool) error {
 	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
+	if !cfgCtx.Initialized {
 		return &errors.NotInitializedError{}
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
+	// Current configuration
+	cfg := cfgCtx.Config.Clone()
 
 	// Check if branch exists
 	branchConfig, exists := cfg.Branches[name]
@@ -863,42 +986,64 @@ func executeConfigDeleteTopic(name string) error {
 		return &errors.InvalidBranchTypeError{BranchType: branchConfig.Type}
 	}
 
-	// Remove branch config from git config
-	if err := git.UnsetConfigSection(fmt.Sprintf("gitflow.branch.%s", name)); err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("remove branch config for '%s'", name), Err: err}
-	}
-
 	// Remove from configuration
 	delete(cfg.Branches, name)
 
 	// Save configuration
-	if err := config.SaveConfig(cfg); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
+	if err := writeConfig(cfgCtx, cfg, []string{name}, configChange{action: hooks.HookActionConfig, change: "delete-topic", branch: name}, dryRun); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
 	}
 
 	fmt.Printf("✓ Deleted topic branch type: %s\n", name)
 	return nil
 }
 
-func executeConfigList() error {
-	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
-	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
+func executeConfigList(cfgCtx *config.Context, branchType, name, format string) error {
+	if branchType != "" && branchType != string(config.BranchTypeBase) && branchType != string(config.BranchTypeTopic) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("invalid branch type '%s' (valid options: base, topic)", branchType)}
 	}
-	if !initialized {
+	if format != configListFormatText && format != config.FormatJSON && format != config.FormatTOML {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unsupported format '%s' (valid options: %s, %s, %s)", format, configListFormatText, config.FormatJSON, config.FormatTOML)}
+	}
+
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		if format != configListFormatText {
+			return &errors.NotInitializedError{}
+		}
 		fmt.Println("Git-flow is not initialized in this repository.")
 		fmt.Println("Run 'git-flow init' to set up git-flow configuration.")
 		return nil
 	}
 
-	// Load current configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+	// Current configuration, narrowed to the requested branches
+	cfg := filterConfigBranches(cfgCtx.Config, branchType, name)
+	if name != "" && len(cfg.Branches) == 0 {
+		if branchType != "" {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("no %s branch '%s' is configured", branchType, name)}
+		}
+		return &errors.InvalidInputError{Message: fmt.Sprintf("no branch or topic branch type '%s' is configured", name)}
+	}
+
+	if format != configListFormatText {
+		doc := config.NewDocument(cfg)
+		doc.Settings = nil
+		data, err := config.EncodeDocument(doc, format)
+		if err != nil {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("failed to encode configuration: %v", err)}
+		}
+		_, err = os.Stdout.Write(data)
+		return err
 	}
 
 	if len(cfg.Branches) == 0 {
+		if branchType != "" {
+			fmt.Printf("No %s branches configured.\n", branchType)
+			return nil
+		}
 		fmt.Println("No git-flow configuration found.")
 		return nil
 	}
@@ -925,26 +1070,28 @@ func executeConfigList() error {
 	}
 
 	// Display base branches
-	fmt.Println("Base branches:")
-	fmt.Println("--------------")
-
-	// Display trunk branches
-	for _, name := range trunkBranches {
-		fmt.Printf("  %s → (root)\n", name)
-		fmt.Println("    Upstream: none, Downstream: none")
-		fmt.Println()
-	}
-
-	// Display child base branches
-	for _, name := range baseBranches {
-		branch := cfg.Branches[name]
-		fmt.Printf("  %s → %s\n", name, branch.Parent)
-		fmt.Printf("    Upstream: %s, Downstream: %s\n",
-			branch.UpstreamStrategy, branch.DownstreamStrategy)
-		if branch.AutoUpdate {
-			fmt.Println("    Auto-update: enabled")
+	if len(trunkBranches) > 0 || len(baseBranches) > 0 {
+		fmt.Println("Base branches:")
+		fmt.Println("--------------")
+
+		// Display trunk branches
+		for _, name := range trunkBranches {
+			fmt.Printf("  %s → (root)\n", name)
+			fmt.Println("    Upstream: none, Downstream: none")
+			fmt.Println()
+		}
+
+		// Display child base branches
+		for _, name := range baseBranches {
+			branch := cfg.Branches[name]
+			fmt.Printf("  %s → %s\n", name, branch.Parent)
+			fmt.Printf("    Upstream: %s, Downstream: %s\n",
+				branch.UpstreamStrategy, branch.DownstreamStrategy)
+			if branch.AutoUpdate {
+				fmt.Println("    Auto-update: enabled")
+			}
+			fmt.Println()
 		}
-		fmt.Println()
 	}
 
 	// Display topic branch types
@@ -975,10 +1122,18 @@ func executeConfigList() error {
 			} else {
 				fmt.Println("  Creates tags: no")
 			}
+			if branch.Template != "" {
+				fmt.Printf("  Template: %s\n", branch.Template)
+			}
 			fmt.Println()
 		}
 	}
 
+	// The help only fits the full listing
+	if branchType != "" || name != "" {
+		return nil
+	}
+
 	// Print configuration help
 	fmt.Println("──────────────────────────────────────────────────────────")
 	fmt.Println()
@@ -1013,6 +1168,137 @@ func isValidMergeStrategy(strategy string) bool {
 	return false
 }
 
+// saveConfig stores cfg and reloads the command's configuration context, so
+// code running after the write sees the stored state rather than the edited copy
+func saveConfig(cfgCtx *config.Context, cfg *config.Config) error {
+	if err := config.SaveConfig(cfg); err != nil {
+		return &errors.GitError{Operation: "save configuration", Err: err}
+	}
+	if err := cfgCtx.Reload(); err != nil {
+		return &errors.GitError{Operation: "reload configuration", Err: err}
+	}
+	return nil
+}
+
+// writeConfig removes the gitflow.branch.<name> sections of removedBranches and
+// saves cfg between the hooks of change. With dryRun nothing is written and no
+// hooks run; the change to the gitflow configuration is printed as a unified
+// diff instead. Changes the organization policy forbids are refused.
+func writeConfig(cfgCtx *config.Context, cfg *config.Config, removedBranches []string, change configChange, dryRun bool) error {
+	if err := checkConfigPolicy(cfgCtx, cfg, removedBranches, nil); err != nil {
+		return err
+	}
+	if dryRun {
+		before, after, err := config.PreviewSave(cfg, removedBranches)
+		if err != nil {
+			return &errors.GitError{Operation: "read configuration", Err: err}
+		}
+		if diff := util.UnifiedDiff("a/gitflow", "b/gitflow", before, after); diff != "" {
+			fmt.Print(diff)
+		} else {
+			fmt.Println("No configuration changes")
+		}
+		return nil
+	}
+
+	return withConfigHooks(cfg, change, func() error {
+		for _, name := range removedBranches {
+			if err := git.UnsetConfigSection(config.BranchSection(name)); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("remove branch config for '%s'", name), Err: err}
+			}
+		}
+		return saveConfig(cfgCtx, cfg)
+	})
+}
+
+// checkConfigPolicy checks the change writeConfig would make, with settings
+// written besides, against the policy of the current configuration. A
+// repository that is not initialized yet has no configuration to protect.
+func checkConfigPolicy(cfgCtx *config.Context, cfg *config.Config, removedBranches []string, settings map[string]string) error {
+	if !cfgCtx.Initialized {
+		return nil
+	}
+	policy, err := config.LoadPolicy(cfgCtx.Config)
+	if err != nil {
+		return &errors.InvalidInputError{Message: err.Error()}
+	}
+	if policy == nil {
+		return nil
+	}
+
+	current, planned, err := config.PlanSave(cfg, removedBranches)
+	if err != nil {
+		return &errors.GitError{Operation: "read configuration", Err: err}
+	}
+	for key, value := range settings {
+		planned[key] = value
+	}
+	if violations := policy.ChangeViolations(cfgCtx.Config, cfg, current, planned); len(violations) > 0 {
+		return &errors.PolicyViolationError{Policy: policy.Path, Violations: violations}
+	}
+	return nil
+}
+
+// configChange describes a change to the branching model for the init and
+// config hooks. The zero value runs no hooks.
+type configChange struct {
+	action    hooks.HookAction // hooks.HookActionInit or hooks.HookActionConfig
+	change    string           // kind of config change, e.g. "add-base"
+	branch    string           // configured branch the change affects
+	oldBranch string           // previous name of a renamed branch
+}
+
+// withConfigHooks runs operation between the pre and post hooks of change. The
+// hooks find cfg, the configuration resulting from the change, in the YAML file
+// named by CONFIG_FILE.
+func withConfigHooks(cfg *config.Config, change configChange, operation func() error) error {
+	if change.action == "" {
+		return operation()
+	}
+
+	gitDir, err := git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	configFile, err := writeConfigSnapshot(cfg)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile)
+
+	hookCtx := hooks.RepoHookContext{
+		Change:     change.change,
+		Branch:     change.branch,
+		OldBranch:  change.oldBranch,
+		Origin:     cfg.Remote,
+		ConfigFile: configFile,
+	}
+	return hooks.WithRepoHooks(gitDir, change.action, hookCtx, operation)
+}
+
+// writeConfigSnapshot writes cfg as YAML, in the format of 'git flow config export',
+// to a temporary file and returns its name. The caller removes the file.
+func writeConfigSnapshot(cfg *config.Config) (string, error) {
+	data, err := config.EncodeDocument(config.NewDocument(cfg), config.FormatYAML)
+	if err != nil {
+		return "", &errors.GitError{Operation: "encode configuration", Err: err}
+	}
+	file, err := os.CreateTemp("", "git-flow-config-*.yml")
+	if err != nil {
+		return "", &errors.GitError{Operation: "create temporary file", Err: err}
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", &errors.GitError{Operation: "write temporary file", Err: err}
+	}
+	return file.Name(), nil
+}
+
 func validateNoCycle(cfg *config.Config, name, parent string) error {
 	visited := make(map[string]bool)
 
@@ -1046,6 +1332,7 @@ func init() {
 	configCmd.AddCommand(configRenameCmd)
 	configCmd.AddCommand(configDeleteCmd)
 	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configUICmd)
 
 	// Add base/topic subcommands
 	configAddCmd.AddCommand(configAddBaseCmd)
@@ -1057,6 +1344,11 @@ func init() {
 	configDeleteCmd.AddCommand(configDeleteBaseCmd)
 	configDeleteCmd.AddCommand(configDeleteTopicCmd)
 
+	// Preview changes without applying them
+	for _, cmd := range []*cobra.Command{configAddCmd, configEditCmd, configRenameCmd, configDeleteCmd} {
+		cmd.PersistentFlags().Bool("dry-run", false, "Show the configuration changes without applying them")
+	}
+
 	// Add flags for base commands
 	configAddBaseCmd.Flags().String("upstream-strategy", "", "Merge strategy when merging to parent (merge|rebase|squash)")
 	configAddBaseCmd.Flags().String("downstream-strategy", "", "Merge strategy when updating from parent (merge|rebase)")
@@ -1072,10 +1364,46 @@ func init() {
 	configAddTopicCmd.Flags().String("upstream-strategy", "", "Merge strategy when merging to parent (merge|rebase|squash)")
 	configAddTopicCmd.Flags().String("downstream-strategy", "", "Merge strategy when updating from parent (merge|rebase)")
 	configAddTopicCmd.Flags().Bool("tag", false, "Create tags on finish")
+	configAddTopicCmd.Flags().String("template", "", "Template to inherit defaults from (gitflow.template.<template>.*)")
 
 	configEditTopicCmd.Flags().String("prefix", "", "Branch name prefix")
 	configEditTopicCmd.Flags().String("starting-point", "", "Branch to create from")
 	configEditTopicCmd.Flags().String("upstream-strategy", "", "Merge strategy when merging to parent (merge|rebase|squash)")
 	configEditTopicCmd.Flags().String("downstream-strategy", "", "Merge strategy when updating from parent (merge|rebase)")
 	configEditTopicCmd.Flags().Bool("tag", false, "Create tags on finish")
+
+	configRenameTopicCmd.Flags().Bool("apply-to-branches", false, "Rename existing branches with the old prefix along with the type")
+
+	configListCmd.Flags().String("type", "", "Only list base branches or topic branch types (base|topic)")
+	configListCmd.Flags().String("name", "", "Only list the branch or topic branch type with this name")
+	configListCmd.Flags().String("format", configListFormatText, "Output format (text|json|toml)")
+}
+
+// configListFormatText is the human-readable format of 'config list'
+const configListFormatText = "text"
+
+// filterConfigBranches returns a copy of cfg with only the branches of
+// branchType and named name; empty values match all branches
+func filterConfigBranches(cfg *config.Config, branchType, name string) *config.Config {
+	filtered := *cfg
+	filtered.Branches = make(map[string]config.BranchConfig)
+	for branchName, branch := range cfg.Branches {
+		// Entries without a type only hold per-branch state, e.g. a stored base
+		if branch.Type == "" {
+			continue
+		}
+		if (branchType == "" || branch.Type == branchType) && (name == "" || branchName == name) {
+			filtered.Branches[branchName] = branch
+		}
+	}
+	return &filtered
+}
+
+// validateTopicTypeName rejects topic type names that would be shadowed by a
+// built-in command such as 'config' or 'finish'
+func validateTopicTypeName(name string) error {
+	if builtinCommandNames()[name] {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is a built-in git-flow command and can't be used as a topic branch type name", name)}
+	}
+	return nil
 }
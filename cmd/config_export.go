@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the git-flow configuration to a file",
+	Long: `Write the complete git-flow configuration to standard output as YAML, JSON
+or TOML: all base branches and topic branch types, the remote, and all other
+gitflow.* settings. The result can be reviewed like code and applied to other
+repositories with 'git flow config import'.
+
+Examples:
+  git-flow config export > branching.yml
+  git-flow config export --format json > branching.json`,
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		ConfigExportCommand(loadContextOrExit(), format)
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import the git-flow configuration from a file",
+	Long: `Replace the branch configuration with the one from a file written by
+'git flow config export', and apply the remote and settings it contains.
+The format is taken from the file extension (.yml, .yaml, .json, .toml)
+unless --format is given. Use '-' to read from standard input.
+
+Branch types that are not in the file are removed. Missing base branches
+are created unless --no-create-branches is given. The repository does not
+need to be initialized first.
+
+Examples:
+  git-flow config import branching.yml
+  cat branching.json | git-flow config import --format json -`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		noCreateBranches, _ := cmd.Flags().GetBool("no-create-branches")
+		ConfigImportCommand(loadContextOrExit(), args[0], format, !noCreateBranches)
+	},
+}
+
+// ConfigExportCommand writes the configuration to standard output
+func ConfigExportCommand(cfgCtx *config.Context, format string) {
+	if err := executeConfigExport(cfgCtx, format); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// ConfigImportCommand applies the configuration from a file
+func ConfigImportCommand(cfgCtx *config.Context, path, format string, createBranches bool) {
+	if err := executeConfigImport(cfgCtx, path, format, createBranches); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeConfigExport(cfgCtx *config.Context, format string) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+
+	data, err := config.EncodeDocument(config.NewDocument(cfgCtx.Config), format)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to encode configuration: %v", err)}
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func executeConfigImport(cfgCtx *config.Context, path, format string, createBranches bool) error {
+	if format == "" {
+		if path == "-" {
+			return &errors.InvalidInputError{Message: "--format is required when reading from standard input"}
+		}
+		detected, err := config.FormatFromPath(path)
+		if err != nil {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("%v; use --format", err)}
+		}
+		format = detected
+	}
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to read '%s': %v", path, err)}
+	}
+
+	doc, err := config.DecodeDocument(data, format)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to parse '%s': %v", path, err)}
+	}
+	cfg := doc.Config()
+	if err := validateImportedConfig(cfg); err != nil {
+		return err
+	}
+	if err := applyImportedConfig(cfgCtx, cfg, createBranches, configChange{action: hooks.HookActionConfig, change: "import"}); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Imported configuration from %s\n", path)
+	return nil
+}
+
+// applyImportedConfig replaces the branch model with the one in cfg, writes
+// its remote and settings and creates missing base branches if requested. All
+// of it runs between the hooks of change.
+func applyImportedConfig(cfgCtx *config.Context, cfg *config.Config, createBranches bool, change configChange) error {
+	return withConfigHooks(cfg, change, func() error {
+		return replaceConfig(cfgCtx, cfg, createBranches)
+	})
+}
+
+// replaceConfig writes an imported configuration for applyImportedConfig
+func replaceConfig(cfgCtx *config.Context, cfg *config.Config, createBranches bool) error {
+	// Replace the branch model: drop the sections of all current and imported types
+	removed := make([]string, 0, len(cfg.Branches))
+	for name := range cfg.Branches {
+		removed = append(removed, name)
+	}
+	if cfgCtx.Initialized {
+		for name := range cfgCtx.Config.Branches {
+			if _, kept := cfg.Branches[name]; !kept {
+				removed = append(removed, name)
+			}
+		}
+	}
+
+	if err := checkConfigPolicy(cfgCtx, cfg, removed, cfg.CommandConfig); err != nil {
+		return err
+	}
+
+	// Settings and the remote are not part of SaveConfig; write them first
+	// so the reload at the end of writeConfig picks them up
+	for key, value := range cfg.CommandConfig {
+		if err := git.SetConfig(key, value); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("set %s", key), Err: err}
+		}
+	}
+	if cfg.Remote != "" {
+		if err := git.SetConfig(config.KeyOrigin, cfg.Remote); err != nil {
+			return &errors.GitError{Operation: "set " + config.KeyOrigin, Err: err}
+		}
+	}
+	if err := config.MarkRepoInitialized(); err != nil {
+		return &errors.GitError{Operation: "mark repository as initialized", Err: err}
+	}
+	if err := writeConfig(cfgCtx, cfg, removed, configChange{}, false); err != nil {
+		return err
+	}
+
+	if createBranches {
+		if err := createGitFlowBranches(cfg); err != nil {
+			return &errors.GitError{Operation: "create branches", Err: err}
+		}
+	}
+	return nil
+}
+
+// validateExportFormat checks that format is a supported export format
+func validateExportFormat(format string) error {
+	for _, supported := range config.ExportFormats {
+		if format == supported {
+			return nil
+		}
+	}
+	return &errors.InvalidInputError{Message: fmt.Sprintf("unsupported format '%s' (valid options: %s)", format, strings.Join(config.ExportFormats, ", "))}
+}
+
+// validateImportedConfig checks an imported configuration the same way the
+// add commands check a single branch
+func validateImportedConfig(cfg *config.Config) error {
+	if err := validateBranchModel(cfg); err != nil {
+		return err
+	}
+
+	for key := range cfg.CommandConfig {
+		if strings.HasPrefix(key, config.BranchSection("")) {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("setting '%s' belongs in the branches section", strings.TrimPrefix(key, "gitflow."))}
+		}
+	}
+
+	return nil
+}
+
+// validateBranchModel checks that all branches of a configuration have a valid
+// type, name and strategies, and that parents and start points are base branches
+func validateBranchModel(cfg *config.Config) error {
+	if len(cfg.Branches) == 0 {
+		return &errors.InvalidInputError{Message: "no branches are defined"}
+	}
+
+	for name, branch := range cfg.Branches {
+		if err := util.ValidateBranchName(name); err != nil {
+			return &errors.InvalidBranchNameError{BranchName: name}
+		}
+		if branch.Type != string(config.BranchTypeBase) && branch.Type != string(config.BranchTypeTopic) {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("branch '%s' has invalid type '%s' (valid options: base, topic)", name, branch.Type)}
+		}
+		if branch.Type == string(config.BranchTypeTopic) && branch.Parent == "" {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("topic branch type '%s' has no parent", name)}
+		}
+		for _, ref := range []string{branch.Parent, branch.StartPoint} {
+			if ref == "" {
+				continue
+			}
+			if target, exists := cfg.Branches[ref]; !exists || target.Type != string(config.BranchTypeBase) {
+				return &errors.InvalidInputError{Message: fmt.Sprintf("branch '%s' refers to '%s', which is not a configured base branch", name, ref)}
+			}
+		}
+		for _, strategy := range []string{branch.UpstreamStrategy, branch.DownstreamStrategy} {
+			if strategy != "" && !isValidMergeStrategy(strategy) {
+				return &errors.InvalidMergeStrategyError{Strategy: strategy}
+			}
+		}
+		if err := validateNoCycle(cfg, name, branch.Parent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+
+	configExportCmd.Flags().String("format", config.FormatYAML, "Output format (yaml|json|toml)")
+	configImportCmd.Flags().String("format", "", "Input format (yaml|json|toml); detected from the file extension by default")
+	configImportCmd.Flags().Bool("no-create-branches", false, "Don't create missing base branches")
+}
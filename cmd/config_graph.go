@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var configGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Render the branching model as a diagram",
+	Long: `Write the configured base branch hierarchy and topic branch types to
+standard output as a Graphviz DOT or Mermaid diagram, so documentation can
+embed a picture of the branching model that matches the configuration.
+
+Base branches are drawn as boxes, topic branch types as rounded shapes
+labelled with their branch pattern. Edges point from parent to child and
+carry the upstream and downstream merge strategies; a dotted edge marks a
+start point that differs from the parent.
+
+Examples:
+  git-flow config graph | dot -Tsvg > branching.svg
+  git-flow config graph --format mermaid > docs/branching.mmd`,
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		ConfigGraphCommand(loadContextOrExit(), format)
+	},
+}
+
+// ConfigGraphCommand writes a diagram of the branching model to standard output
+func ConfigGraphCommand(cfgCtx *config.Context, format string) {
+	if err := executeConfigGraph(cfgCtx, format); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeConfigGraph(cfgCtx *config.Context, format string) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	graph, err := config.RenderGraph(cfgCtx.Config, format)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unsupported format '%s' (valid options: %s)", format, strings.Join(config.GraphFormats, ", "))}
+	}
+	_, err = fmt.Fprint(os.Stdout, graph)
+	return err
+}
+
+func init() {
+	configCmd.AddCommand(configGraphCmd)
+
+	configGraphCmd.Flags().String("format", config.GraphFormatDOT, "Diagram format (dot|mermaid)")
+}
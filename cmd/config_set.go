@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// settingStabilization routes new topic branches to a release branch while it
+// is being stabilized
+const settingStabilization = "stabilization"
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <setting> <value>",
+	Short: "Turn on a temporary workflow mode",
+	Long: `Turn on a temporary workflow mode.
+
+stabilization <branch>
+  Stabilize the release branch <branch>: topic branches that would start from
+  the branch the release was started from, such as features and bugfixes from
+  develop, start from <branch> instead and are finished into it. The mode is
+  lifted when <branch> is finished, or with 'git flow config unset stabilization'.
+
+Examples:
+  git-flow config set stabilization release/2.0
+  git-flow config unset stabilization`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigSetCommand(loadContextOrExit(), args[0], args[1])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <setting>",
+	Short: "Turn off a temporary workflow mode",
+	Long: `Turn off a temporary workflow mode turned on with 'git flow config set'.
+
+Examples:
+  git-flow config unset stabilization`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigUnsetCommand(loadContextOrExit(), args[0])
+	},
+}
+
+// ConfigSetCommand turns on a workflow mode
+func ConfigSetCommand(cfgCtx *config.Context, setting, value string) {
+	exitOnConfigSetError(executeConfigSet(cfgCtx, setting, value))
+}
+
+// ConfigUnsetCommand turns off a workflow mode
+func ConfigUnsetCommand(cfgCtx *config.Context, setting string) {
+	exitOnConfigSetError(executeConfigUnset(cfgCtx, setting))
+}
+
+func exitOnConfigSetError(err error) {
+	if err == nil {
+		return
+	}
+	var exitCode errors.ExitCode
+	if flowErr, ok := err.(errors.Error); ok {
+		exitCode = flowErr.ExitCode()
+	} else {
+		exitCode = errors.ExitCodeGitError
+	}
+	printError(err)
+	os.Exit(int(exitCode))
+}
+
+func executeConfigSet(cfgCtx *config.Context, setting, value string) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	if setting != settingStabilization {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unknown setting '%s' (valid settings: %s)", setting, settingStabilization)}
+	}
+	cfg := cfgCtx.Config
+
+	if config.TopicBranchType(cfg, value) == "" {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a topic branch, such as a release branch", value)}
+	}
+	if err := git.BranchExists(value); err != nil {
+		return &errors.BranchNotFoundError{BranchName: value}
+	}
+
+	if err := git.SetConfig(config.KeyStabilization, value); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("set %s", config.KeyStabilization), Err: err}
+	}
+	types := commands.StabilizedTypes(newDeps(), cfg, value)
+	if len(types) == 0 {
+		fmt.Printf("Stabilizing '%s', but no topic branch types start from '%s'\n", value, commands.StabilizationSource(newDeps(), cfg, value))
+		return nil
+	}
+	fmt.Printf("Stabilizing '%s': new %s branches start from and finish into it until it is finished\n", value, strings.Join(types, " and "))
+	return nil
+}
+
+func executeConfigUnset(cfgCtx *config.Context, setting string) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	if setting != settingStabilization {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("unknown setting '%s' (valid settings: %s)", setting, settingStabilization)}
+	}
+
+	branch, ok := cfgCtx.Config.GetString(config.KeyStabilization)
+	if !ok {
+		fmt.Println("Stabilization mode is not active")
+		return nil
+	}
+	if err := git.UnsetConfig(config.KeyStabilization); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("unset %s", config.KeyStabilization), Err: err}
+	}
+	fmt.Printf("Stopped stabilizing '%s'\n", branch)
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+}
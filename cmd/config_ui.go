@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/configui"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var configUICmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Edit the configuration in a terminal UI",
+	Long: `Show the branch hierarchy as a tree in the terminal and edit it in place.
+Expanding a branch lists its parent, start point, prefix, merge strategies,
+auto-update and tagging: choices are changed with the arrow keys, prefixes
+are typed into the tree. Base branches and topic branch types can be added
+and deleted.
+
+Nothing is written until you save; the changes are shown as a diff of the
+gitflow.* keys first. Missing base branches are created on save.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ConfigUICommand(loadContextOrExit())
+	},
+}
+
+// ConfigUICommand edits the configuration in the terminal UI
+func ConfigUICommand(cfgCtx *config.Context) {
+	if err := executeConfigUI(cfgCtx); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// InitUICommand initializes git-flow by editing a preset in the terminal UI
+func InitUICommand(createBranches, force bool) {
+	if err := initUI(createBranches, force); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeConfigUI(cfgCtx *config.Context) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	if err := checkTerminalUI(); err != nil {
+		return err
+	}
+
+	result, err := runConfigUI(cfgCtx.Config.Clone(), nil, cfgCtx.Config)
+	if err != nil || !result.Saved {
+		return err
+	}
+
+	if err := writeConfig(cfgCtx, result.Config, result.Removed, configChange{action: hooks.HookActionConfig, change: "ui"}, false); err != nil {
+		return err
+	}
+	if err := createGitFlowBranches(result.Config); err != nil {
+		return &errors.GitError{Operation: "create branches", Err: err}
+	}
+
+	fmt.Println("✓ Configuration saved")
+	return nil
+}
+
+func initUI(createBranches, force bool) error {
+	if !git.IsGitRepo() {
+		return &errors.GitError{Operation: "check if git repository", Err: fmt.Errorf("not a git repository. Please run 'git init' first")}
+	}
+	if err := checkTerminalUI(); err != nil {
+		return err
+	}
+
+	cfgCtx, err := loadContext()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	if cfgCtx.Initialized && !force {
+		fmt.Fprintln(os.Stderr, "Git-flow is already configured in this repository.")
+		return &errors.AlreadyInitializedError{}
+	}
+
+	var starts []configui.Start
+	if cfgCtx.Initialized {
+		starts = append(starts, configui.Start{Name: "current configuration", Config: cfgCtx.Config.Clone()})
+	}
+	starts = append(starts,
+		configui.Start{Name: "Classic GitFlow (main, develop, feature, bugfix, release, hotfix)", Config: config.PresetConfig(config.PresetClassic)},
+		configui.Start{Name: "GitHub Flow (main, feature)", Config: config.PresetConfig(config.PresetGitHub)},
+		configui.Start{Name: "GitLab Flow (production, staging, main, feature, hotfix)", Config: config.PresetConfig(config.PresetGitLab)},
+	)
+	// Only the branch model is edited; settings and the remote stay as they are
+	for _, start := range starts {
+		start.Config.CommandConfig = make(map[string]string)
+		start.Config.Remote = ""
+	}
+
+	result, err := runConfigUI(nil, starts, nil)
+	if err != nil {
+		return err
+	}
+	if !result.Saved {
+		fmt.Println("Initialization cancelled.")
+		return nil
+	}
+
+	if err := applyImportedConfig(cfgCtx, result.Config, createBranches, configChange{action: hooks.HookActionInit}); err != nil {
+		return err
+	}
+
+	fmt.Println("Git flow has been initialized")
+	return nil
+}
+
+// checkTerminalUI fails unless standard input and output are a terminal the
+// UI can be shown on
+func checkTerminalUI() error {
+	if output.IsQuiet() {
+		return &errors.InvalidInputError{Message: "the terminal UI is not available with --quiet"}
+	}
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		if info, err := file.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return &errors.InvalidInputError{Message: "the terminal UI needs a terminal; use 'git flow config add', 'edit' and 'delete' in scripts"}
+		}
+	}
+	return nil
+}
+
+// runConfigUI edits cfg, or one of starts, in the terminal UI. The branch
+// model is validated as by 'git flow config import' and the changes are
+// shown as a diff of the gitflow.* keys before saving. Branches that clear a
+// value of the stored configuration are added to the removed branches, so
+// their sections are written anew.
+func runConfigUI(cfg *config.Config, starts []configui.Start, stored *config.Config) (configui.Result, error) {
+	result, err := configui.Run(cfg, configui.Options{
+		Starts:   starts,
+		Validate: validateBranchModel,
+		Preview: func(cfg *config.Config, removed []string) (string, error) {
+			before, after, err := config.PreviewSave(cfg, append(removed, clearedBranches(stored, cfg)...))
+			if err != nil {
+				return "", err
+			}
+			return util.UnifiedDiff("a/gitflow", "b/gitflow", before, after), nil
+		},
+	})
+	if err != nil {
+		return result, &errors.GitError{Operation: "run the terminal UI", Err: err}
+	}
+	if result.Saved {
+		result.Removed = append(result.Removed, clearedBranches(stored, result.Config)...)
+	}
+	return result, nil
+}
+
+// clearedBranches returns the branches of cfg that no longer have a parent,
+// start point or tag prefix they have in stored. Saving skips empty values,
+// which would leave the stored keys behind.
+func clearedBranches(stored, cfg *config.Config) []string {
+	if stored == nil {
+		return nil
+	}
+	var names []string
+	for name, branch := range cfg.Branches {
+		old, ok := stored.Branches[name]
+		if !ok {
+			continue
+		}
+		if (old.Parent != "" && branch.Parent == "") ||
+			(old.StartPoint != "" && branch.StartPoint == "") ||
+			(old.TagPrefix != "" && branch.TagPrefix == "") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the repository's git-flow setup",
+	Long: `Check the git-flow setup of the repository and report problems:
+
+  branches  the configured base branches exist, locally or on the remote
+  policy    the configuration follows the organization policy named by
+            gitflow.policy: pinned settings have their pinned values and
+            protected branches are configured
+
+The command exits with 0 when no problems are found and with 6 otherwise.`,
+	Example: "  git flow doctor",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		DoctorCommand(loadContextOrExit())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// DoctorCommand checks the repository's git-flow setup. Problems exit with
+// ExitCodeValidationError.
+func DoctorCommand(cfgCtx *config.Context) {
+	if err := executeDoctor(cfgCtx); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeDoctor(cfgCtx *config.Context) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	var problems []string
+	fmt.Println("Base branches:")
+	names := make([]string, 0, len(cfg.Branches))
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(config.BranchTypeBase) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if branchStillExists(cfg, name) {
+			fmt.Printf("  ✓ '%s' exists\n", name)
+			continue
+		}
+		fmt.Printf("  ✗ '%s' does not exist\n", name)
+		problems = append(problems, fmt.Sprintf("base branch '%s' does not exist", name))
+	}
+
+	policy, err := config.LoadPolicy(cfg)
+	switch {
+	case err != nil:
+		fmt.Println("Policy:")
+		fmt.Printf("  ✗ %v\n", err)
+		problems = append(problems, err.Error())
+	case policy == nil:
+		fmt.Printf("Policy: none (%s is not set)\n", config.KeyPolicy)
+	default:
+		fmt.Printf("Policy %s:\n", policy.Path)
+		drift := policy.Drift(cfg)
+		if len(drift) == 0 {
+			fmt.Println("  ✓ the configuration follows the policy")
+		}
+		for _, problem := range drift {
+			fmt.Printf("  ✗ %s\n", problem)
+		}
+		problems = append(problems, drift...)
+	}
+
+	if len(problems) > 0 {
+		return &errors.DoctorError{Problems: problems}
+	}
+	fmt.Println("No problems found")
+	return nil
+}
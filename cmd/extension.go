@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/version"
+	"github.com/spf13/cobra"
+)
+
+// extensionPrefix is the name prefix of executables that provide extension
+// commands: 'git flow foo' runs git-flow-foo from PATH, like git runs git-foo.
+const extensionPrefix = "git-flow-"
+
+// findExtension returns the executable for an extension command named name. Built-in
+// commands and topic branch types always take precedence over extensions.
+func findExtension(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.HasPrefix(name, "__") || strings.ContainsRune(name, os.PathSeparator) {
+		return "", false
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return "", false
+		}
+	}
+	if name == "help" || name == "completion" {
+		return "", false
+	}
+
+	path, err := exec.LookPath(extensionPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// runExtension runs an extension command with args and returns its exit code.
+// Its standard streams are those of git-flow, and it finds the repository
+// context in environment variables:
+//   - GITFLOW_EXEC: path of the git-flow executable
+//   - GITFLOW_VERSION: version of git-flow-next
+//   - GITFLOW_GIT_DIR, GITFLOW_WORK_TREE: git and working tree directories
+//   - GITFLOW_REMOTE: the configured remote
+//   - GITFLOW_CONFIG_FILE: the git-flow configuration as YAML, in the format of 'git flow config export'
+//
+// The repository variables are empty outside of a repository, and the configuration
+// ones when it can't be loaded.
+func runExtension(path string, args []string) int {
+	env := append(os.Environ(), fmt.Sprintf("GITFLOW_VERSION=%s", version.Version))
+	if executable, err := os.Executable(); err == nil {
+		env = append(env, fmt.Sprintf("GITFLOW_EXEC=%s", executable))
+	}
+
+	var gitDir, workTree, remote, configFile string
+	if dir, err := git.GetGitDir(); err == nil {
+		gitDir, _ = filepath.Abs(dir)
+		workTree, _ = git.GetTopLevelDir()
+		if cfg, err := config.LoadConfig(); err == nil {
+			remote = cfg.Remote
+			if file, err := writeConfigSnapshot(cfg); err == nil {
+				configFile = file
+				defer os.Remove(configFile)
+			}
+		}
+	}
+	env = append(env,
+		fmt.Sprintf("GITFLOW_GIT_DIR=%s", gitDir),
+		fmt.Sprintf("GITFLOW_WORK_TREE=%s", workTree),
+		fmt.Sprintf("GITFLOW_REMOTE=%s", remote),
+		fmt.Sprintf("GITFLOW_CONFIG_FILE=%s", configFile),
+	)
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+
+	// The extension handles Ctrl-C itself; wait for it so its exit code is
+	// returned and the configuration file is removed
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if exitErr.ExitCode() < 0 {
+				return int(errors.ExitCodeInterrupted)
+			}
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to run extension '%s': %v\n", filepath.Base(path), err)
+		// Like a shell, 126 means the command was found but could not be run
+		return 126
+	}
+	return 0
+}
+
+// listExtensions returns the names of the extension commands found on PATH that
+// are not shadowed by a built-in command or topic branch type.
+func listExtensions() []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, extensionPrefix+"*"))
+		for _, match := range matches {
+			name := strings.TrimPrefix(filepath.Base(match), extensionPrefix)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if _, ok := findExtension(name); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rootHelp prints the help of the root command followed by the extension
+// commands found on PATH.
+func rootHelp(defaultHelp func(*cobra.Command, []string)) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		defaultHelp(cmd, args)
+		if cmd != rootCmd {
+			return
+		}
+		if extensions := listExtensions(); len(extensions) > 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "\nExtension commands found on PATH:\n")
+			for _, name := range extensions {
+				fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", name)
+			}
+		}
+	}
+}
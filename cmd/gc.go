@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// gcCmd represents the gc command
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove the stored configuration of branches that no longer exist",
+	Long: `Removes the gitflow.branch.<name>.* settings git-flow stores for topic
+branches, such as the base recorded by start, when the branch exists neither
+locally nor on the remote. Finish and delete remove these settings, but
+branches deleted with plain Git, or operations that crashed, leave them
+behind.
+
+Sections that define a branch type (with a type setting) are never removed,
+nor is the section of the branch of an interrupted finish.
+
+Use --dry-run to only list what would be removed.`,
+	Example: "  git flow gc --dry-run\n  git flow gc",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		GCCommand(loadContextOrExit(), dryRun)
+	},
+}
+
+func init() {
+	gcCmd.Flags().BoolP("dry-run", "n", false, "Only list the stale settings, without removing them")
+	rootCmd.AddCommand(gcCmd)
+}
+
+// GCCommand removes the stored configuration of branches that no longer exist
+func GCCommand(cfgCtx *config.Context, dryRun bool) {
+	if err := executeGC(cfgCtx, dryRun); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// staleSection is the gitflow.branch.<name> section of a branch that no longer exists
+type staleSection struct {
+	Branch  string
+	Entries []config.Entry
+}
+
+func executeGC(cfgCtx *config.Context, dryRun bool) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	sections := findStaleSections(cfg)
+	if len(sections) == 0 {
+		fmt.Println("No stale branch configuration found")
+		return nil
+	}
+
+	for _, section := range sections {
+		if dryRun {
+			fmt.Printf("Would remove the configuration of '%s':\n", section.Branch)
+		} else {
+			if err := git.UnsetConfigSection(config.BranchSection(section.Branch)); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("remove the configuration of '%s'", section.Branch), Err: err}
+			}
+			fmt.Printf("Removed the configuration of '%s':\n", section.Branch)
+		}
+		for _, entry := range section.Entries {
+			fmt.Printf("  %s=%s\n", entry.Key, entry.Value)
+		}
+		output.Result("%s", section.Branch)
+	}
+
+	if dryRun {
+		fmt.Printf("%d stale branch section(s); run without --dry-run to remove them\n", len(sections))
+	} else {
+		fmt.Printf("Removed %d stale branch section(s)\n", len(sections))
+	}
+	return nil
+}
+
+// findStaleSections returns the gitflow.branch.* sections of branches that are
+// neither branch type definitions nor existing local or remote branches,
+// ordered by branch name
+func findStaleSections(cfg *config.Config) []staleSection {
+	entries := make(map[string][]config.Entry)
+	for key, value := range cfg.CommandConfig {
+		if !strings.HasPrefix(key, config.BranchSection("")) {
+			continue
+		}
+		last := strings.LastIndex(key, ".")
+		branch := strings.TrimPrefix(key[:last], config.BranchSection(""))
+		if branch == "" {
+			continue
+		}
+		entries[branch] = append(entries[branch], config.Entry{Key: key, Value: value})
+	}
+
+	// The branch of an interrupted finish is deleted before its settings are
+	inProgress := ""
+	if mergestate.IsMergeInProgress() {
+		if state, err := mergestate.LoadMergeState(); err == nil {
+			inProgress = state.FullBranchName
+		}
+	}
+
+	var sections []staleSection
+	for branch, branchEntries := range entries {
+		if branch == inProgress || definesBranchType(branchEntries) || branchStillExists(cfg, branch) {
+			continue
+		}
+		sort.Slice(branchEntries, func(i, j int) bool { return branchEntries[i].Key < branchEntries[j].Key })
+		sections = append(sections, staleSection{Branch: branch, Entries: branchEntries})
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Branch < sections[j].Branch })
+	return sections
+}
+
+// definesBranchType reports whether a section holds a branch type definition
+func definesBranchType(entries []config.Entry) bool {
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Key, "."+strings.ToLower(config.PropType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// branchStillExists reports whether branch exists locally or on the remote
+func branchStillExists(cfg *config.Config, branch string) bool {
+	if git.BranchExists(branch) == nil {
+		return true
+	}
+	return cfg.Remote != "" && git.RemoteBranchExists(cfg.Remote, branch)
+}
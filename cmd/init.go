@@ -10,6 +10,8 @@ import (
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -20,7 +22,7 @@ var initCmd = &cobra.Command{
 This will set up the necessary configuration for git-flow to work.
 
 You can use presets for common workflows:
-  --preset=classic    Traditional GitFlow with main, develop, feature, release, hotfix
+  --preset=classic    Traditional GitFlow with main, develop, feature, bugfix, release, hotfix
   --preset=github     GitHub Flow with main and feature branches
   --preset=gitlab     GitLab Flow with production, staging, main, feature, and hotfix
 
@@ -31,8 +33,44 @@ Configuration scope options control where settings are stored:
   --file=<path>       Store in specified file
 
 Use --custom for interactive custom configuration.
-If git-flow-avh configuration exists, it will be imported.`,
+If git-flow-avh configuration exists, it will be imported.
+
+Use --template to apply an organization's standard setup from a git URL or a
+local directory. The template holds a configuration file written by
+'git flow config export' (gitflow.yml, gitflow.yaml, gitflow.json or
+gitflow.toml) and optionally a hooks/ directory with hook and filter scripts,
+which are installed into the repository's hooks directory.
+
+Use --interactive-ui to pick a preset as starting point and adjust the branch
+hierarchy in the same terminal UI as 'git flow config ui' before it is saved.
+
+Use --infer in a repository with established conventions to guess the main
+and develop branches, the topic branch prefixes and the tag prefix from its
+branches, merge commit messages and tags. The inferred model is shown for
+confirmation and can be adjusted in the terminal UI before it is saved; add
+--defaults to accept it without asking.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		template, _ := cmd.Flags().GetString("template")
+		interactiveUI, _ := cmd.Flags().GetBool("interactive-ui")
+		infer, _ := cmd.Flags().GetBool("infer")
+		if template != "" || interactiveUI || infer {
+			if err := checkExclusiveInitFlags(cmd); err != nil {
+				printError(err)
+				os.Exit(int(errors.ExitCodeInvalidInput))
+			}
+			noCreateBranches, _ := cmd.Flags().GetBool("no-create-branches")
+			force, _ := cmd.Flags().GetBool("force")
+			if interactiveUI {
+				InitUICommand(!noCreateBranches, force)
+			} else if infer {
+				useDefaults, _ := cmd.Flags().GetBool("defaults")
+				InitInferCommand(!noCreateBranches, force, useDefaults)
+			} else {
+				InitTemplateCommand(template, !noCreateBranches, force)
+			}
+			return
+		}
+
 		useDefaults, _ := cmd.Flags().GetBool("defaults")
 		noCreateBranches, _ := cmd.Flags().GetBool("no-create-branches")
 		force, _ := cmd.Flags().GetBool("force")
@@ -50,26 +88,51 @@ If git-flow-avh configuration exists, it will be imported.`,
 		globalScope, _ := cmd.Flags().GetBool("global")
 		systemScope, _ := cmd.Flags().GetBool("system")
 		fileScope, _ := cmd.Flags().GetString("file")
-		InitCommand(useDefaults, !noCreateBranches, force, preset, custom, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix, localScope, globalScope, systemScope, fileScope)
+		adoptDefaultBranch := getBoolPtr(cmd, "adopt-default-branch", "no-adopt-default-branch")
+		InitCommand(useDefaults, !noCreateBranches, force, preset, custom, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix, localScope, globalScope, systemScope, fileScope, adoptDefaultBranch)
 	},
 }
 
+// checkExclusiveInitFlags rejects options that choose or change the branch model
+// when --template, --interactive-ui or --infer already does. With --infer,
+// --defaults accepts the inferred model without asking.
+func checkExclusiveInitFlags(cmd *cobra.Command) error {
+	var modes []string
+	for _, name := range []string{"template", "interactive-ui", "infer"} {
+		if cmd.Flags().Changed(name) {
+			modes = append(modes, name)
+		}
+	}
+	if len(modes) > 1 {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("--%s cannot be combined with --%s", modes[0], modes[1])}
+	}
+	if modes[0] != "infer" && cmd.Flags().Changed("defaults") {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("--%s cannot be combined with --defaults", modes[0])}
+	}
+	for _, name := range []string{"preset", "custom", "main", "develop", "feature", "bugfix", "release", "hotfix", "support", "tag", "global", "system", "file", "adopt-default-branch", "no-adopt-default-branch"} {
+		if cmd.Flags().Changed(name) {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("--%s cannot be combined with --%s", modes[0], name)}
+		}
+	}
+	return nil
+}
+
 // InitCommand is the implementation of the init command
-func InitCommand(useDefaults, createBranches, force bool, preset string, custom bool, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix string, localScope, globalScope, systemScope bool, fileScope string) {
-	if err := initFlow(useDefaults, createBranches, force, preset, custom, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix, localScope, globalScope, systemScope, fileScope); err != nil {
+func InitCommand(useDefaults, createBranches, force bool, preset string, custom bool, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix string, localScope, globalScope, systemScope bool, fileScope string, adoptDefaultBranch *bool) {
+	if err := initFlow(useDefaults, createBranches, force, preset, custom, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix, localScope, globalScope, systemScope, fileScope, adoptDefaultBranch); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // initFlow performs the actual initialization logic and returns any errors
-func initFlow(useDefaults, createBranches, force bool, preset string, custom bool, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix string, localScope, globalScope, systemScope bool, fileScope string) error {
+func initFlow(useDefaults, createBranches, force bool, preset string, custom bool, mainBranch, developBranch, featurePrefix, bugfixPrefix, releasePrefix, hotfixPrefix, supportPrefix, tagPrefix string, localScope, globalScope, systemScope bool, fileScope string, adoptDefaultBranch *bool) error {
 	// Validate mutual exclusivity of scope flags
 	scopeCount := 0
 	if localScope {
@@ -154,8 +217,8 @@ func initFlow(useDefaults, createBranches, force bool, preset string, custom boo
 		}
 
 		// Interactive mode - prompt for confirmation
-		fmt.Println(msg)
-		fmt.Print("Do you want to reconfigure? [y/N]: ")
+		output.Prompt("%s\n", msg)
+		output.Prompt("Do you want to reconfigure? [y/N]: ")
 
 		var response string
 		fmt.Scanln(&response)
@@ -171,6 +234,7 @@ func initFlow(useDefaults, createBranches, force bool, preset string, custom boo
 	}
 
 	var cfg *config.Config
+	trunkChosen := false
 
 	// Check if any configuration options are provided
 	hasConfigFlags := mainBranch != "" || developBranch != "" || featurePrefix != "" || bugfixPrefix != "" || releasePrefix != "" || hotfixPrefix != "" || supportPrefix != "" || tagPrefix != ""
@@ -184,7 +248,14 @@ func initFlow(useDefaults, createBranches, force bool, preset string, custom boo
 			return &errors.GitError{Operation: "import git-flow-avh configuration", Err: err}
 		}
 		fmt.Println("Successfully imported git-flow-avh configuration")
+		trunkChosen = true
 	} else {
+		// Interactive questions cannot be answered when their output is discarded
+		interactive := custom || (preset == "" && !useDefaults && !hasConfigFlags)
+		if interactive && output.IsQuiet() {
+			return &errors.InvalidInputError{Message: "interactive initialization is not available with --quiet; use --defaults, --preset or branch options"}
+		}
+
 		// Determine configuration method
 		if preset != "" {
 			// Use preset configuration
@@ -205,9 +276,13 @@ func initFlow(useDefaults, createBranches, force bool, preset string, custom boo
 			cfg = config.DefaultConfig()
 		} else {
 			// Interactive mode - use legacy interactive config for backward compatibility
+			// The prompt for the main branch offers an existing default
+			// branch such as master, so the answer names the trunk
 			cfg = config.DefaultConfig()
-			interactiveOverrides := interactiveConfig()
+			_, suggestedMain := defaultBranchToAdopt(cfg, adoptDefaultBranch)
+			interactiveOverrides := interactiveConfig(suggestedMain)
 			cfg = config.ApplyOverrides(cfg, interactiveOverrides)
+			trunkChosen = true
 		}
 	}
 
@@ -228,25 +303,123 @@ func initFlow(useDefaults, createBranches, force bool, preset string, custom boo
 		cfg = config.ApplyOverrides(cfg, overrides)
 	}
 
-	// Save configuration with the appropriate scope
-	if err := config.SaveConfigWithScope(cfg, scope, scopeFile); err != nil {
-		return &errors.GitError{Operation: "save configuration", Err: err}
-	}
-	if err := config.MarkRepoInitializedWithScope(scope, scopeFile); err != nil {
-		return &errors.GitError{Operation: "mark repository as initialized", Err: err}
+	// An explicit --main, an imported configuration or the interactive prompt
+	// already names the trunk; otherwise an existing default branch such as
+	// master takes the place of the preset's main
+	if mainBranch == "" && !trunkChosen {
+		adoptRepoDefaultBranch(cfg, adoptDefaultBranch)
 	}
 
-	// Create branches if requested
+	// Refuse before saving anything when the base branches would need an
+	// initial commit that the configuration does not allow
 	if createBranches {
-		if err := createGitFlowBranches(cfg); err != nil {
-			return &errors.GitError{Operation: "create branches", Err: err}
+		if err := checkInitialCommitAllowed(); err != nil {
+			return err
 		}
 	}
 
+	err = withConfigHooks(cfg, configChange{action: hooks.HookActionInit}, func() error {
+		// Save configuration with the appropriate scope
+		if err := config.SaveConfigWithScope(cfg, scope, scopeFile); err != nil {
+			return &errors.GitError{Operation: "save configuration", Err: err}
+		}
+		if err := config.MarkRepoInitializedWithScope(scope, scopeFile); err != nil {
+			return &errors.GitError{Operation: "mark repository as initialized", Err: err}
+		}
+
+		// Create branches if requested
+		if createBranches {
+			if err := createGitFlowBranches(cfg); err != nil {
+				return &errors.GitError{Operation: "create branches", Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("Git flow has been initialized")
 	return nil
 }
 
+// adoptRepoDefaultBranch renames the configured main branch to the
+// repository's existing default branch when main does not exist yet, so a
+// repository on master does not end up with both master and main
+func adoptRepoDefaultBranch(cfg *config.Config, adopt *bool) {
+	trunk, existing := defaultBranchToAdopt(cfg, adopt)
+	if existing == "" {
+		return
+	}
+
+	branchConfig := cfg.Branches[trunk]
+	delete(cfg.Branches, trunk)
+	cfg.Branches[existing] = branchConfig
+	for name, branch := range cfg.Branches {
+		if branch.Parent == trunk {
+			branch.Parent = existing
+		}
+		if branch.StartPoint == trunk {
+			branch.StartPoint = existing
+		}
+		cfg.Branches[name] = branch
+	}
+	fmt.Printf("Using existing branch '%s' instead of '%s'\n", existing, trunk)
+}
+
+// defaultBranchToAdopt returns the configured main branch and the existing
+// default branch to use in its place, or empty names when main already
+// exists, the repository has no other default branch or adopt is false
+func defaultBranchToAdopt(cfg *config.Config, adopt *bool) (trunk string, existing string) {
+	if adopt != nil && !*adopt {
+		return "", ""
+	}
+
+	// Only the presets' main is replaced; it is what hosting services and
+	// newer Git versions call the default branch master used to be
+	trunk = "main"
+	if branch, ok := cfg.Branches[trunk]; !ok || branch.Type != string(config.BranchTypeBase) || git.BranchExists(trunk) == nil {
+		return "", ""
+	}
+
+	existing = repoDefaultBranch(cfg)
+	if existing == "" || existing == trunk {
+		return "", ""
+	}
+	if _, configured := cfg.Branches[existing]; configured {
+		return "", ""
+	}
+	return trunk, existing
+}
+
+// repoDefaultBranch returns the local branch the repository already uses as
+// its default: the branch the remote's HEAD points at, or master
+func repoDefaultBranch(cfg *config.Config) string {
+	for _, candidate := range []string{git.RemoteDefaultBranch(cfg.Remote), "master"} {
+		if candidate != "" && git.BranchExists(candidate) == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// checkInitialCommitAllowed fails when the repository has no commits yet and
+// gitflow.init.createCommit is set to false
+func checkInitialCommitAllowed() error {
+	hasCommits, err := git.HasCommits()
+	if err != nil || hasCommits {
+		return err
+	}
+	value, err := git.GetConfig(config.KeyInitCreateCommit)
+	if err != nil {
+		return nil
+	}
+	if enabled, ok := config.ParseBool(value); ok && !enabled {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("the repository has no commits yet; commit something first or set %s to true", config.KeyInitCreateCommit)}
+	}
+	return nil
+}
+
 // createGitFlowBranches creates the base branches if they don't exist
 func createGitFlowBranches(cfg *config.Config) error {
 	// Check if we have any commits
@@ -298,6 +471,17 @@ func createGitFlowBranches(cfg *config.Config) error {
 		}
 	}
 
+	// An unborn HEAD has nothing to branch from, so the root base branch
+	// gets an empty initial commit
+	if !hasCommits && len(sorted) > 0 {
+		root := sorted[0]
+		if err := git.CreateInitialCommit(root.name); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("create initial commit on '%s'", root.name), Err: err}
+		}
+		fmt.Printf("Created initial empty commit on '%s'\n", root.name)
+		sorted = sorted[1:]
+	}
+
 	// Create branches in dependency order
 	for _, b := range sorted {
 		err := git.CreateBranch(b.name, b.parent)
@@ -356,7 +540,7 @@ func interactivePresetSelection() *config.Config {
 
 	fmt.Println()
 	fmt.Println("? Choose a preset:")
-	fmt.Println("  1. Classic GitFlow (main, develop, feature, release, hotfix)")
+	fmt.Println("  1. Classic GitFlow (main, develop, feature, bugfix, release, hotfix)")
 	fmt.Println("  2. GitHub Flow (main, feature)")
 	fmt.Println("  3. GitLab Flow (production, staging, main, feature, hotfix)")
 	fmt.Print("Enter your choice (1-3): ")
@@ -425,7 +609,7 @@ func customConfiguration() *config.Config {
 
 	// Create minimal config with just the trunk branch
 	cfg := &config.Config{
-		Version:       "1.0",
+		Version:       config.SchemaVersion,
 		Remote:        "origin",
 		CommandConfig: make(map[string]string),
 		Branches: map[string]config.BranchConfig{
@@ -471,6 +655,16 @@ func interactiveClassicCustomization() config.ConfigOverrides {
 		overrides.FeaturePrefix = featurePrefix
 	}
 
+	fmt.Print("? Bugfix prefix [bugfix/]: ")
+	bugfixPrefix, _ := reader.ReadString('\n')
+	bugfixPrefix = strings.TrimSpace(bugfixPrefix)
+	if bugfixPrefix != "" {
+		if !strings.HasSuffix(bugfixPrefix, "/") {
+			bugfixPrefix += "/"
+		}
+		overrides.BugfixPrefix = bugfixPrefix
+	}
+
 	fmt.Print("? Release prefix [release/]: ")
 	releasePrefix, _ := reader.ReadString('\n')
 	releasePrefix = strings.TrimSpace(releasePrefix)
@@ -576,14 +770,20 @@ func interactiveGitLabCustomization() config.ConfigOverrides {
 }
 
 // interactiveConfig prompts the user for configuration values (legacy function)
-func interactiveConfig() config.ConfigOverrides {
+func interactiveConfig(suggestedMain string) config.ConfigOverrides {
 	reader := bufio.NewReader(os.Stdin)
 	overrides := config.ConfigOverrides{}
 
-	// Prompt for main branch name
-	fmt.Print("Branch name for production releases [main]: ")
+	// Prompt for main branch name, defaulting to an existing default branch
+	if suggestedMain == "" {
+		suggestedMain = "main"
+	}
+	fmt.Printf("Branch name for production releases [%s]: ", suggestedMain)
 	mainBranch, _ := reader.ReadString('\n')
 	mainBranch = strings.TrimSpace(mainBranch)
+	if mainBranch == "" && suggestedMain != "main" {
+		mainBranch = suggestedMain
+	}
 	if mainBranch != "" {
 		overrides.MainBranch = mainBranch
 	}
@@ -671,6 +871,9 @@ func init() {
 	initCmd.Flags().Bool("no-create-branches", false, "Don't create branches even if they don't exist")
 	initCmd.Flags().StringP("preset", "p", "", "Use preset configuration (classic|github|gitlab)")
 	initCmd.Flags().Bool("custom", false, "Use custom configuration with interactive setup")
+	initCmd.Flags().Bool("interactive-ui", false, "Edit the branch hierarchy in a terminal UI before saving")
+	initCmd.Flags().Bool("infer", false, "Infer the branch model from the repository's branches, merges and tags")
+	initCmd.Flags().String("template", "", "Apply the configuration and hooks of a template repository or directory")
 	initCmd.Flags().StringP("main", "m", "", "Main branch name")
 	initCmd.Flags().StringP("develop", "e", "", "Develop branch name")
 	initCmd.Flags().String("feature", "", "Feature branch prefix")
@@ -679,6 +882,8 @@ func init() {
 	initCmd.Flags().StringP("hotfix", "x", "", "Hotfix branch prefix")
 	initCmd.Flags().StringP("support", "s", "", "Support branch prefix")
 	initCmd.Flags().StringP("tag", "t", "", "Version tag prefix")
+	initCmd.Flags().Bool("adopt-default-branch", false, "Use an existing default branch such as master as main branch without asking")
+	initCmd.Flags().Bool("no-adopt-default-branch", false, "Create the configured main branch even if the repository uses another default branch")
 
 	// Configuration scope options
 	initCmd.Flags().Bool("local", false, "Store configuration in repository's .git/config")
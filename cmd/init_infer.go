@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/configui"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/output"
+)
+
+// InitInferCommand is the implementation of 'git flow init --infer'
+func InitInferCommand(createBranches, force, assumeYes bool) {
+	if err := initFromHistory(createBranches, force, assumeYes); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// initFromHistory infers the branch model from the repository's branches,
+// merge commits and tags and saves it once the user confirms it, or right
+// away with assumeYes
+func initFromHistory(createBranches, force, assumeYes bool) error {
+	if !git.IsGitRepo() {
+		return &errors.GitError{Operation: "check if git repository", Err: fmt.Errorf("not a git repository. Please run 'git init' first")}
+	}
+	if !assumeYes && output.IsQuiet() {
+		return &errors.InvalidInputError{Message: "confirming the inferred configuration is not available with --quiet; add --defaults to accept it"}
+	}
+
+	cfgCtx, err := loadContext()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	if cfgCtx.Initialized && !force {
+		fmt.Fprintln(os.Stderr, "Git-flow is already configured in this repository.")
+		return &errors.AlreadyInitializedError{}
+	}
+
+	inference, err := inferFromRepository(cfgCtx.Config.Remote)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Inferred from the repository's branches, merges and tags:")
+	for _, finding := range inference.Findings {
+		fmt.Printf("  %s\n", finding)
+	}
+
+	// Only the branch model is inferred; settings and the remote stay as they are
+	cfg := inference.Config
+	cfg.Remote = ""
+
+	if !assumeYes {
+		fmt.Println()
+		fmt.Print(configui.FormatTree(cfg))
+		fmt.Println()
+		answer := strings.ToLower(newDeps().Prompter.Ask("? Use this configuration? [Y/n/e(dit)]: "))
+		confirmed := answer == "" || answer == "y" || answer == "yes"
+		if answer == "e" || answer == "edit" {
+			if err := checkTerminalUI(); err != nil {
+				return err
+			}
+			result, err := runConfigUI(cfg, nil, nil)
+			if err != nil {
+				return err
+			}
+			confirmed, cfg = result.Saved, result.Config
+		}
+		if !confirmed {
+			fmt.Println("Initialization cancelled.")
+			return nil
+		}
+	}
+
+	if err := applyImportedConfig(cfgCtx, cfg, createBranches, configChange{action: hooks.HookActionInit}); err != nil {
+		return err
+	}
+
+	fmt.Println("Git flow has been initialized")
+	return nil
+}
+
+// inferFromRepository collects the local and remote branches, the branches
+// named by merge commit messages and the tags, and infers the branch model
+// from them
+func inferFromRepository(remote string) (*config.Inference, error) {
+	branches, err := git.ListBranches()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "list branches", Err: err}
+	}
+	if remoteBranches, err := git.RemoteBranches(remote); err == nil {
+		branches = append(branches, remoteBranches...)
+	}
+
+	subjects, err := git.MergeSubjects()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "read merge commits", Err: err}
+	}
+	var merged []string
+	for _, subject := range subjects {
+		if branch, ok := mergeSubjectBranch(subject); ok {
+			merged = append(merged, branch)
+		}
+	}
+
+	tags, err := git.ListTags("*")
+	if err != nil {
+		return nil, &errors.GitError{Operation: "list tags", Err: err}
+	}
+	return config.InferFromHistory(branches, merged, tags), nil
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+)
+
+// templateConfigFiles are the configuration file names looked up at the root
+// of a template, in order of preference
+var templateConfigFiles = []string{"gitflow.yml", "gitflow.yaml", "gitflow.json", "gitflow.toml"}
+
+// templateHooksDir is the template directory holding hook and filter scripts
+const templateHooksDir = "hooks"
+
+// templatePolicyFile is the template file holding the organization policy,
+// installed under the same name in the git directory
+const templatePolicyFile = "gitflow-policy.yml"
+
+// InitTemplateCommand initializes git-flow from a template repository or directory
+func InitTemplateCommand(source string, createBranches, force bool) {
+	if err := initFromTemplate(source, createBranches, force); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// initFromTemplate applies the configuration file of a template and installs
+// its hook scripts. The template is a local directory or anything 'git clone'
+// accepts; it is validated completely before the repository is changed.
+func initFromTemplate(source string, createBranches, force bool) error {
+	if !git.IsGitRepo() {
+		return &errors.GitError{Operation: "check if git repository", Err: fmt.Errorf("not a git repository. Please run 'git init' first")}
+	}
+
+	cfgCtx, err := loadContext()
+	if err != nil {
+		return &errors.GitError{Operation: "load configuration", Err: err}
+	}
+	if cfgCtx.Initialized && !force {
+		fmt.Fprintln(os.Stderr, "Git-flow is already configured in this repository.")
+		return &errors.AlreadyInitializedError{}
+	}
+
+	dir, cleanup, err := fetchTemplate(source)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	configFile := findTemplateConfig(dir)
+	if configFile == "" {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("template '%s' has no configuration file (expected one of %s)", source, strings.Join(templateConfigFiles, ", "))}
+	}
+	format, err := config.FormatFromPath(configFile)
+	if err != nil {
+		return &errors.InvalidInputError{Message: err.Error()}
+	}
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to read '%s': %v", filepath.Base(configFile), err)}
+	}
+	doc, err := config.DecodeDocument(data, format)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to parse '%s': %v", filepath.Base(configFile), err)}
+	}
+	cfg := doc.Config()
+	if err := validateImportedConfig(cfg); err != nil {
+		return err
+	}
+	policySource := filepath.Join(dir, templatePolicyFile)
+	hasPolicy := false
+	if _, err := os.Stat(policySource); err == nil {
+		if _, err := config.ReadPolicy(policySource); err != nil {
+			return &errors.InvalidInputError{Message: err.Error()}
+		}
+		hasPolicy = true
+	}
+
+	fmt.Printf("Initializing git-flow from template %s\n", source)
+	if cfgCtx.Initialized {
+		fmt.Println("Reconfiguring git-flow (--force specified)...")
+	}
+
+	// Hooks go first: a conflict with existing scripts must not leave a
+	// half-applied template behind
+	hooksSource := filepath.Join(dir, templateHooksDir)
+	if info, err := os.Stat(hooksSource); err == nil && info.IsDir() {
+		gitDir, err := git.GetGitDir()
+		if err != nil {
+			return &errors.GitError{Operation: "get git directory", Err: err}
+		}
+		installed, err := hooks.InstallScripts(gitDir, hooksSource, force)
+		if err != nil {
+			if !force {
+				err = fmt.Errorf("%w; use --force to replace them", err)
+			}
+			return &errors.GitError{Operation: "install hook scripts", Err: err}
+		}
+		for _, name := range installed {
+			fmt.Printf("Installed hook '%s'\n", name)
+		}
+	}
+
+	if err := applyImportedConfig(cfgCtx, cfg, createBranches, configChange{action: hooks.HookActionInit}); err != nil {
+		return err
+	}
+	if hasPolicy {
+		if err := installPolicy(policySource); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Git flow has been initialized")
+	return nil
+}
+
+// installPolicy copies the policy file of a template into the git directory,
+// read-only, and points gitflow.policy at it. Drift of the configuration from
+// the policy is reported as a warning.
+func installPolicy(source string) error {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("failed to read '%s': %v", templatePolicyFile, err)}
+	}
+	gitDir, err := git.GetCommonGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+	target := filepath.Join(gitDir, templatePolicyFile)
+
+	// The installed policy is read-only; replace it rather than write to it
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return &errors.GitError{Operation: "replace policy", Err: err}
+	}
+	if err := os.WriteFile(target, data, 0444); err != nil {
+		return &errors.GitError{Operation: "install policy", Err: err}
+	}
+	if err := git.SetConfig(config.KeyPolicy, target); err != nil {
+		return &errors.GitError{Operation: "set " + config.KeyPolicy, Err: err}
+	}
+	fmt.Printf("Installed policy '%s'\n", templatePolicyFile)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	if policy, err := config.LoadPolicy(cfg); err == nil && policy != nil {
+		for _, drift := range policy.Drift(cfg) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", drift)
+		}
+	}
+	return nil
+}
+
+// fetchTemplate returns a local directory with the contents of the template.
+// Directories containing a configuration file are used in place; everything
+// else is cloned into a temporary directory that cleanup removes.
+func fetchTemplate(source string) (dir string, cleanup func(), err error) {
+	if info, statErr := os.Stat(source); statErr == nil && info.IsDir() && findTemplateConfig(source) != "" {
+		return source, func() {}, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "git-flow-template-")
+	if err != nil {
+		return "", nil, &errors.GitError{Operation: "create temporary directory", Err: err}
+	}
+	cleanup = func() { os.RemoveAll(tempDir) }
+
+	cloneDir := filepath.Join(tempDir, "template")
+	if err := git.CloneShallow(source, cloneDir); err != nil {
+		cleanup()
+		return "", nil, &errors.GitError{Operation: "fetch template", Err: err}
+	}
+	return cloneDir, cleanup, nil
+}
+
+// findTemplateConfig returns the path of the template's configuration file,
+// or an empty string if it has none
+func findTemplateConfig(dir string) string {
+	for _, name := range templateConfigFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/spf13/cobra"
+)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [branch]",
+	Short: "Show the effective git-flow settings of a branch",
+	Long: `Show everything git-flow knows about an existing branch, or the current branch
+if none is given: its detected type and short name, the configured parent and
+the base stored when it was started, the branch finish would merge it into, the
+effective merge strategies and tagging behavior, whether it is published, and
+whether an interrupted git-flow operation involves it.
+
+The settings combine the branch type configuration, command-specific gitflow.*
+keys such as gitflow.<type>.finish.rebase, and the per-branch key
+gitflow.branch.<branch>.base.`,
+	Example:     "  git flow inspect\n  git flow inspect feature/login",
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		var branch string
+		if len(args) > 0 {
+			branch = args[0]
+		}
+		bestEffort, _ := cmd.Flags().GetBool("best-effort")
+		InspectCommand(loadContextOrExit(), branch, bestEffort)
+	},
+}
+
+// InspectCommand is the implementation of the inspect command
+func InspectCommand(cfgCtx *config.Context, branch string, bestEffort bool) {
+	if err := executeInspect(cfgCtx, branch, bestEffort); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeInspect(cfgCtx *config.Context, branch string, bestEffort bool) error {
+	// Validate that git-flow is initialized, or infer its configuration
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
+	if err != nil {
+		return err
+	}
+
+	if branch == "" {
+		current, err := commands.CurrentBranchFor(newDeps(), "git flow inspect <branch>")
+		if err != nil {
+			return err
+		}
+		branch = current
+	}
+	if err := git.BranchExists(branch); err != nil {
+		return &errors.LocalBranchNotFoundError{BranchName: branch}
+	}
+
+	fmt.Printf("Branch:              %s\n", branch)
+	if branchConfig, ok := cfg.Branches[branch]; ok && branchConfig.Type == string(config.BranchTypeBase) {
+		printBaseBranchSettings(cfg, branch, branchConfig)
+	} else if branchType, prefix, ok := detectTopicType(cfg, branch); ok {
+		printTopicBranchSettings(cfg, branch, branchType, prefix)
+	} else {
+		fmt.Println("Type:                none (not a base branch and no topic branch prefix matches)")
+	}
+
+	printPublishedState(cfg, branch)
+	printPendingOperation(branch)
+	return nil
+}
+
+// detectTopicType returns the topic branch type whose prefix or prefix alias
+// branch starts with. When several match, the longest prefix wins.
+func detectTopicType(cfg *config.Config, branch string) (string, string, bool) {
+	var branchType, prefix string
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type != string(config.BranchTypeTopic) {
+			continue
+		}
+		matched, ok := config.MatchTopicPrefix(branchConfig, branch)
+		if !ok || len(matched) < len(prefix) || (len(matched) == len(prefix) && branchType != "" && name > branchType) {
+			continue
+		}
+		branchType, prefix = name, matched
+	}
+	return branchType, prefix, branchType != ""
+}
+
+// printBaseBranchSettings prints the configuration of a base branch and the
+// branch types that depend on it
+func printBaseBranchSettings(cfg *config.Config, branch string, branchConfig config.BranchConfig) {
+	fmt.Println("Type:                base branch")
+	if branchConfig.Parent == "" {
+		fmt.Println("Parent:              (root)")
+		return
+	}
+	fmt.Printf("Parent:              %s\n", branchConfig.Parent)
+	fmt.Printf("Upstream strategy:   %s\n", valueOrDefault(branchConfig.UpstreamStrategy, string(config.MergeStrategyMerge)))
+	fmt.Printf("Downstream strategy: %s\n", valueOrDefault(branchConfig.DownstreamStrategy, string(config.MergeStrategyMerge)))
+	fmt.Printf("Auto-update:         %s\n", yesNo(branchConfig.AutoUpdate))
+	if branchConfig.ConflictResolution != "" {
+		fmt.Printf("Conflict resolution: %s (%s)\n", branchConfig.ConflictResolution, valueOrDefault(branchConfig.ConflictResolutionPaths, "all files"))
+	}
+
+	var children []string
+	for name, child := range cfg.Branches {
+		if child.Parent != branch || child.Type == "" {
+			continue
+		}
+		if child.Type == string(config.BranchTypeTopic) {
+			name += " (topic)"
+		}
+		children = append(children, name)
+	}
+	if len(children) > 0 {
+		sort.Strings(children)
+		fmt.Printf("Children:            %s\n", strings.Join(children, ", "))
+	}
+}
+
+// printTopicBranchSettings prints the effective settings of a topic branch:
+// the configuration of its type combined with its stored base and the
+// gitflow.<type>.finish.* keys
+func printTopicBranchSettings(cfg *config.Config, branch, branchType, prefix string) {
+	branchConfig := cfg.Branches[branchType]
+	shortName := strings.TrimPrefix(branch, prefix)
+
+	fmt.Printf("Type:                %s (topic branch)\n", branchType)
+	fmt.Printf("Name:                %s\n", shortName)
+	if prefix != branchConfig.Prefix {
+		fmt.Printf("Prefix:              %s (alias of %s)\n", prefix, branchConfig.Prefix)
+	} else {
+		fmt.Printf("Prefix:              %s\n", prefix)
+	}
+	fmt.Printf("Configured parent:   %s\n", branchConfig.Parent)
+	fmt.Printf("Start point:         %s\n", valueOrDefault(branchConfig.StartPoint, branchConfig.Parent))
+
+	stored, _ := git.GetBaseBranch(branch)
+	if stored != "" {
+		fmt.Printf("Stored base:         %s\n", stored)
+	} else {
+		fmt.Println("Stored base:         (none)")
+	}
+
+	// Mirror resolveFinishBase without prompting
+	policy, _ := config.ResolveBaseResolution(cfg, branchType)
+	switch {
+	case stored != "" && stored == commands.ActiveStabilization(newDeps(), cfg):
+		fmt.Printf("Finish target:       %s (stabilization)\n", stored)
+	case stored != branchConfig.Parent && commands.IsTopicBase(newDeps(), cfg, branchType, stored):
+		fmt.Printf("Finish target:       %s (stored topic base)\n", stored)
+	case stored == "" || stored == branchConfig.Parent || policy == config.BaseResolutionConfigured:
+		fmt.Printf("Finish target:       %s (configured parent)\n", branchConfig.Parent)
+	case policy == config.BaseResolutionStored:
+		fmt.Printf("Finish target:       %s (stored base)\n", stored)
+	case policy == config.BaseResolutionPrompt:
+		fmt.Printf("Finish target:       asks for %s (configured parent) or %s (stored base)\n", branchConfig.Parent, stored)
+	default:
+		fmt.Printf("Finish target:       unknown (invalid base resolution '%s')\n", policy)
+	}
+
+	options := config.ResolveFinishOptions(cfg, branchType, shortName, nil, nil, nil, nil, nil, nil)
+	var modifiers []string
+	if options.UseRebase && options.PreserveMerges {
+		modifiers = append(modifiers, "preserve merges")
+	}
+	if options.NoFastForward {
+		modifiers = append(modifiers, "no fast-forward")
+	}
+	if options.FastForwardOnly {
+		modifiers = append(modifiers, "fast-forward only")
+	}
+	if len(modifiers) > 0 {
+		fmt.Printf("Upstream strategy:   %s (%s)\n", options.MergeStrategy, strings.Join(modifiers, ", "))
+	} else {
+		fmt.Printf("Upstream strategy:   %s\n", options.MergeStrategy)
+	}
+	fmt.Printf("Downstream strategy: %s\n", valueOrDefault(branchConfig.DownstreamStrategy, string(config.MergeStrategyMerge)))
+
+	if options.ShouldTag {
+		tagging := fmt.Sprintf("yes, tag '%s'", options.TagName)
+		if options.ShouldSign {
+			tagging += " (signed)"
+		}
+		fmt.Printf("Tagging:             %s\n", tagging)
+	} else {
+		fmt.Println("Tagging:             no")
+	}
+
+	switch {
+	case options.Keep || (options.KeepLocal && options.KeepRemote):
+		fmt.Println("After finish:        branch is kept")
+	case options.KeepLocal:
+		fmt.Println("After finish:        local branch is kept, remote branch is deleted")
+	case options.KeepRemote:
+		fmt.Println("After finish:        local branch is deleted, remote branch is kept")
+	default:
+		fmt.Println("After finish:        branch is deleted")
+	}
+}
+
+// printPublishedState prints whether branch exists on the remote and how it
+// compares to its remote counterpart as of the last fetch
+func printPublishedState(cfg *config.Config, branch string) {
+	remote := cfg.Remote
+
+	tracking, err := git.GetTrackingBranch(branch)
+	if err != nil {
+		if git.RemoteBranchExists(remote, branch) {
+			fmt.Printf("Published:           yes, on %s/%s (not tracked)\n", remote, branch)
+		} else {
+			fmt.Println("Published:           no")
+		}
+		return
+	}
+
+	status, count, err := git.CompareBranchWithRemote(branch)
+	switch {
+	case err != nil:
+		fmt.Printf("Published:           yes, tracking %s\n", tracking)
+	case status == git.SyncStatusEqual:
+		fmt.Printf("Published:           yes, tracking %s (up to date)\n", tracking)
+	case status == git.SyncStatusAhead:
+		fmt.Printf("Published:           yes, tracking %s (%d commit(s) ahead)\n", tracking, count)
+	case status == git.SyncStatusBehind:
+		fmt.Printf("Published:           yes, tracking %s (%d commit(s) behind)\n", tracking, count)
+	default:
+		fmt.Printf("Published:           yes, tracking %s (diverged by %d commit(s))\n", tracking, count)
+	}
+}
+
+// printPendingOperation prints the role of branch in an interrupted git-flow
+// operation, if any
+func printPendingOperation(branch string) {
+	state, err := mergestate.LoadMergeState()
+	if err != nil || state == nil {
+		fmt.Println("Pending operation:   none")
+		return
+	}
+
+	var role string
+	switch {
+	case state.Action == commands.ActionSyncBases && state.CurrentChildBranch == branch:
+		role = fmt.Sprintf("being updated from %s by sync-bases", state.ParentBranch)
+	case state.Action == commands.ActionSyncBases:
+		for _, child := range state.ChildBranches {
+			if child == branch && !state.IsChildUpdated(child) {
+				role = "to be updated by sync-bases"
+				break
+			}
+		}
+	case state.FullBranchName == branch && state.Action == "update":
+		role = fmt.Sprintf("being updated from %s", state.ParentBranch)
+	case state.FullBranchName == branch:
+		role = fmt.Sprintf("being finished into %s", state.ParentBranch)
+	case state.ParentBranch == branch && state.Action == "update":
+		role = fmt.Sprintf("source of the update of %s", state.FullBranchName)
+	case state.ParentBranch == branch:
+		role = fmt.Sprintf("target of the %s of %s", state.Action, state.FullBranchName)
+	case state.CurrentChildBranch == branch:
+		role = fmt.Sprintf("being updated by the %s of %s", state.Action, state.FullBranchName)
+	default:
+		for _, child := range state.ChildBranches {
+			if child == branch {
+				role = fmt.Sprintf("to be updated by the %s of %s", state.Action, state.FullBranchName)
+				break
+			}
+		}
+	}
+	if role == "" {
+		fmt.Printf("Pending operation:   none (a %s of %s is in progress)\n", state.Action, state.FullBranchName)
+		return
+	}
+	fmt.Printf("Pending operation:   %s, stopped at step '%s' (see 'git flow state show')\n", role, state.CurrentStep)
+}
+
+// valueOrDefault returns value, or fallback when value is empty
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// yesNo formats a boolean for display
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().Bool("best-effort", false, "Infer branch names and prefixes from the branches if git-flow is not initialized")
+}
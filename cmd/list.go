@@ -11,34 +11,25 @@ import (
 )
 
 // ListCommand is the implementation of the list command for topic branches
-func ListCommand(branchType string) {
-	if err := list(branchType); err != nil {
+func ListCommand(cfgCtx *config.Context, branchType string, remote, bestEffort bool) {
+	if err := list(cfgCtx, branchType, remote, bestEffort); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // list performs the actual branch listing logic and returns any errors
-func list(branchType string) error {
-	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
+func list(cfgCtx *config.Context, branchType string, remote, bestEffort bool) error {
+	// Validate that git-flow is initialized, or infer its configuration
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
 	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
-		return &errors.NotInitializedError{}
-	}
-
-	// Get configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+		return err
 	}
 
 	// Get branch configuration
@@ -47,9 +38,6 @@ func list(branchType string) error {
 		return &errors.InvalidBranchTypeError{BranchType: branchType}
 	}
 
-	// Get the prefix for this branch type
-	prefix := branchConfig.Prefix
-
 	// Get all branches
 	branches, err := git.ListBranches()
 	if err != nil {
@@ -57,31 +45,63 @@ func list(branchType string) error {
 	}
 
 	// Filter branches by prefix
-	var topicBranches []string
-	for _, branch := range branches {
-		if strings.HasPrefix(branch, prefix) {
-			// Remove the prefix to get the branch name
-			name := strings.TrimPrefix(branch, prefix)
-			topicBranches = append(topicBranches, name)
-		}
+	topicBranches := topicBranchNames(branchConfig, branches)
+
+	// Capitalize the first letter of the branch type
+	branchTypeCapitalized := branchType
+	if len(branchType) > 0 {
+		branchTypeCapitalized = strings.ToUpper(branchType[:1]) + branchType[1:]
 	}
 
 	// Print the branches
 	if len(topicBranches) == 0 {
 		fmt.Printf("No %s branches found\n", branchType)
+	} else {
+		fmt.Printf("%s branches:\n", branchTypeCapitalized)
+		for _, branch := range topicBranches {
+			fmt.Printf("  %s\n", branch)
+		}
+	}
+
+	if !remote {
 		return nil
 	}
 
-	// Capitalize the first letter of the branch type
-	branchTypeCapitalized := branchType
-	if len(branchType) > 0 {
-		branchTypeCapitalized = strings.ToUpper(branchType[:1]) + branchType[1:]
+	// Remote branches as of the last fetch
+	remoteName := cfg.Remote
+	remoteBranches, err := git.RemoteBranches(remoteName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("list branches of remote '%s'", remoteName), Err: err}
 	}
 
-	fmt.Printf("%s branches:\n", branchTypeCapitalized)
-	for _, branch := range topicBranches {
+	remoteTopicBranches := topicBranchNames(branchConfig, remoteBranches)
+	if len(remoteTopicBranches) == 0 {
+		fmt.Printf("No remote %s branches found on '%s'\n", branchType, remoteName)
+		return nil
+	}
+	fmt.Printf("Remote %s branches on '%s':\n", branchType, remoteName)
+	for _, branch := range remoteTopicBranches {
 		fmt.Printf("  %s\n", branch)
 	}
 
 	return nil
 }
+
+// topicBranchNames returns the short names of the branches that belong to a
+// topic branch type. Branches named with a prefix alias show their full name too.
+func topicBranchNames(branchConfig config.BranchConfig, branches []string) []string {
+	var names []string
+	for _, branch := range branches {
+		prefix, ok := config.MatchTopicPrefix(branchConfig, branch)
+		if !ok {
+			continue
+		}
+		// Remove the prefix to get the branch name
+		name := strings.TrimPrefix(branch, prefix)
+		if prefix != branchConfig.Prefix {
+			name = fmt.Sprintf("%s (%s)", name, branch)
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// retryContext returns the context commands run their fetches and pushes
+// with: they retry transient network failures as gitflow.network.retries and
+// gitflow.network.retryDelay configure, announcing each retry on standard error
+func retryContext(cfg *config.Config) context.Context {
+	policy, err := config.ResolveNetworkRetry(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using %d retries\n", err, policy.Retries)
+	}
+	policy.OnRetry = func(err *errors.RemoteError, delay time.Duration) {
+		fmt.Fprintf(os.Stderr, "Warning: %s '%s' failed (attempt %d); retrying in %s\n", err.Operation, err.Remote, err.Attempts, delay)
+	}
+	return git.WithRetryPolicy(context.Background(), policy)
+}
@@ -17,40 +17,33 @@ var overviewCmd = &cobra.Command{
 	Short: "Show an overview of the git-flow configuration and branches",
 	Long: `Show an overview of the git-flow configuration and branches.
 This command displays the current git-flow configuration and lists all active topic branches.`,
+	Annotations: dataOutputAnnotations,
 	Run: func(cmd *cobra.Command, args []string) {
-		OverviewCommand()
+		bestEffort, _ := cmd.Flags().GetBool("best-effort")
+		OverviewCommand(loadContextOrExit(), bestEffort)
 	},
 }
 
 // OverviewCommand is the implementation of the overview command
-func OverviewCommand() {
-	if err := overview(); err != nil {
+func OverviewCommand(cfgCtx *config.Context, bestEffort bool) {
+	if err := overview(cfgCtx, bestEffort); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
 // overview performs the actual overview logic and returns any errors
-func overview() error {
-	// Validate that git-flow is initialized
-	initialized, err := config.IsInitialized()
+func overview(cfgCtx *config.Context, bestEffort bool) error {
+	// Validate that git-flow is initialized, or infer its configuration
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
 	if err != nil {
-		return &errors.GitError{Operation: "check if git-flow is initialized", Err: err}
-	}
-	if !initialized {
-		return &errors.NotInitializedError{}
-	}
-
-	// Get configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
+		return err
 	}
 
 	// Print base branches section with condensed format
@@ -138,6 +131,16 @@ func overview() error {
 		return &errors.GitError{Operation: "get current branch", Err: err}
 	}
 
+	// Branches checked out in other worktrees are marked like 'git branch' does
+	otherWorktrees := make(map[string]string)
+	if worktrees, err := git.ListWorktrees(); err == nil {
+		for _, worktree := range worktrees {
+			if !worktree.Current && worktree.Branch != "" {
+				otherWorktrees[worktree.Branch] = worktree.Path
+			}
+		}
+	}
+
 	// Print active topic branches
 	fmt.Println("Active topic branches:")
 	fmt.Println("======================")
@@ -160,14 +163,18 @@ func overview() error {
 	if len(activeTopicBranches) > 0 {
 		for _, branchName := range activeTopicBranches {
 			prefix := ""
+			suffix := ""
 			if branchName == currentBranch {
 				prefix = "* "
+			} else if path, ok := otherWorktrees[branchName]; ok {
+				prefix = "+ "
+				suffix = fmt.Sprintf(" [worktree: %s]", path)
 			} else {
 				prefix = "  "
 			}
 
 			branchType := branchTypeMap[branchName]
-			fmt.Printf("%s%s (%s)\n", prefix, branchName, branchType)
+			fmt.Printf("%s%s (%s)%s\n", prefix, branchName, branchType, suffix)
 		}
 	} else {
 		fmt.Println("  No active topic branches")
@@ -201,4 +208,6 @@ func getMergeStrategyDescription(strategy string, isUpstream bool) string {
 
 func init() {
 	rootCmd.AddCommand(overviewCmd)
+
+	overviewCmd.Flags().Bool("best-effort", false, "Infer branch names and prefixes from the branches if git-flow is not initialized")
 }
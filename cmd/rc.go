@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/output"
+)
+
+// RCCommand tags the head of a release branch as its next release candidate
+func RCCommand(cfgCtx *config.Context, branchType string, name string, push *bool) {
+	if err := executeRC(cfgCtx, branchType, name, push); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// executeRC tags the head of the branch as <tag>-rc.N, where <tag> is the tag
+// finish will create and N follows the highest existing candidate, and records
+// the candidate so finish can list it in the tag message
+func executeRC(cfgCtx *config.Context, branchType string, name string, push *bool) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Determine branch name - if empty, use current branch
+	fullBranchName := name
+	if name == "" {
+		currentBranch, err := commands.CurrentBranchFor(newDeps(), fmt.Sprintf("git flow %s rc <name>", branchType))
+		if err != nil {
+			return err
+		}
+		if branchConfig.Prefix != "" && !strings.HasPrefix(currentBranch, branchConfig.Prefix) {
+			return fmt.Errorf("current branch '%s' is not a %s branch", currentBranch, branchType)
+		}
+		fullBranchName = currentBranch
+	} else {
+		var err error
+		if fullBranchName, _, err = commands.ResolveTopicName(newDeps(), cfg, branchType, name); err != nil {
+			return err
+		}
+	}
+	shortName := strings.TrimPrefix(fullBranchName, branchConfig.Prefix)
+
+	if err := git.BranchExists(fullBranchName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: fullBranchName}
+	}
+
+	base, _ := git.GetBaseBranch(fullBranchName)
+	releaseTag := config.ResolveStartTagName(cfg, branchType, shortName, base)
+	if releaseTag == "" {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("finishing a %s branch creates no tag, so '%s' has no release candidates", branchType, fullBranchName)}
+	}
+	if git.TagExists(releaseTag) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is already released as '%s'", fullBranchName, releaseTag)}
+	}
+
+	head, err := git.BranchCommit(fullBranchName)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", fullBranchName), Err: err}
+	}
+	latest, number, err := latestReleaseCandidate(releaseTag)
+	if err != nil {
+		return &errors.GitError{Operation: "list release candidates", Err: err}
+	}
+	if latest != "" {
+		if commit, err := git.TagCommit(latest); err == nil && commit == head {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' has not changed since release candidate '%s'", fullBranchName, latest)}
+		}
+	}
+
+	candidate := git.ReleaseCandidate{Tag: fmt.Sprintf("%s%s%d", releaseTag, git.ReleaseCandidateSuffix, number+1), Commit: head}
+	tagOptions := &git.TagOptions{
+		Message: fmt.Sprintf("Release candidate %d of %s", number+1, releaseTag),
+		Target:  fullBranchName,
+	}
+	if err := git.CreateTag(candidate.Tag, tagOptions); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("create tag '%s'", candidate.Tag), Err: err}
+	}
+	if err := git.AddReleaseCandidate(fullBranchName, candidate); err != nil {
+		return &errors.GitError{Operation: "record release candidate", Err: err}
+	}
+	fmt.Printf("Tagged '%s' (%s) as release candidate '%s'\n", fullBranchName, git.ShortCommit(head), candidate.Tag)
+
+	if config.ResolveRCPush(cfg, branchType, push) {
+		fmt.Printf("Pushing tag '%s' to remote '%s'...\n", candidate.Tag, cfg.Remote)
+		if err := git.PushRefsAtomic(retryContext(cfg), cfg.Remote, []string{"refs/tags/" + candidate.Tag}); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("push tag '%s' (push it with 'git push %s %s')", candidate.Tag, cfg.Remote, candidate.Tag), Err: err}
+		}
+		fmt.Printf("Pushed tag '%s' to '%s'\n", candidate.Tag, cfg.Remote)
+	}
+
+	output.Result("%s", candidate.Tag)
+	return nil
+}
+
+// latestReleaseCandidate returns the release candidate tag of releaseTag with
+// the highest number, and that number; "" and 0 when there is none
+func latestReleaseCandidate(releaseTag string) (string, int, error) {
+	tags, err := git.ListTags(releaseTag + git.ReleaseCandidateSuffix + "*")
+	if err != nil {
+		return "", 0, err
+	}
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(releaseTag+git.ReleaseCandidateSuffix) + `(\d+)$`)
+	latest, highest := "", 0
+	for _, tag := range tags {
+		match := pattern.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+		if number, err := strconv.Atoi(match[1]); err == nil && number > highest {
+			latest, highest = tag, number
+		}
+	}
+	return latest, highest, nil
+}
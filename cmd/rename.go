@@ -1,80 +1,28 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/gittower/git-flow-next/internal/commands"
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
-	"github.com/gittower/git-flow-next/internal/git"
 )
 
 // RenameCommand handles renaming a topic branch
-func RenameCommand(branchType string, oldName string, newName string) {
-	if err := executeRename(branchType, oldName, newName); err != nil {
+func RenameCommand(cfgCtx *config.Context, branchType string, oldName string, newName string) {
+	if err := executeRename(cfgCtx, branchType, oldName, newName); err != nil {
 		var exitCode errors.ExitCode
 		if flowErr, ok := err.(errors.Error); ok {
 			exitCode = flowErr.ExitCode()
 		} else {
 			exitCode = errors.ExitCodeGitError
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printError(err)
 		os.Exit(int(exitCode))
 	}
 }
 
-// executeRename performs the actual branch renaming logic and returns any errors
-func executeRename(branchType string, oldName string, newName string) error {
-	// Load configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return &errors.GitError{Operation: "load configuration", Err: err}
-	}
-
-	// Get branch configuration
-	branchConfig, ok := cfg.Branches[branchType]
-	if !ok {
-		return &errors.InvalidBranchTypeError{BranchType: branchType}
-	}
-
-	// Construct full branch names
-	oldFullBranchName := oldName
-	newFullBranchName := newName
-	if branchConfig.Prefix != "" {
-		oldFullBranchName = branchConfig.Prefix + oldName
-		newFullBranchName = branchConfig.Prefix + newName
-	}
-
-	// Check if old branch exists
-	err = git.BranchExists(oldFullBranchName)
-	if err != nil {
-		return &errors.BranchNotFoundError{BranchName: oldFullBranchName}
-	}
-
-	// Check if new branch name already exists
-	err = git.BranchExists(newFullBranchName)
-	if err == nil {
-		return &errors.GitError{Operation: "rename branch", Err: fmt.Errorf("branch '%s' already exists", newFullBranchName)}
-	}
-
-	// Check if we're currently on the branch to be renamed
-	currentBranch, err := git.GetCurrentBranch()
-	if err != nil {
-		return &errors.GitError{Operation: "get current branch", Err: err}
-	}
-
-	// If we're on the branch to be renamed, we need to rename it while on it
-	if currentBranch == oldFullBranchName {
-		err = git.RenameBranch(oldFullBranchName, newFullBranchName)
-	} else {
-		// Otherwise, rename it while staying on the current branch
-		err = git.RenameBranch(oldFullBranchName, newFullBranchName)
-	}
-
-	if err != nil {
-		return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", oldFullBranchName, newFullBranchName), Err: err}
-	}
-
-	fmt.Printf("Renamed branch '%s' to '%s'\n", oldFullBranchName, newFullBranchName)
-	return nil
+// executeRename runs the command against the real repository and returns any errors
+func executeRename(cfgCtx *config.Context, branchType string, oldName string, newName string) error {
+	return commands.Rename(newDeps(), cfgCtx.Config, branchType, oldName, newName)
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/replay"
+)
+
+// ReplayCommand runs a replay script against a new fixture repository. It is
+// reached through the hidden --replay flag of the root command.
+func ReplayCommand(path string) {
+	if err := executeReplay(path); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// executeReplay parses the script, runs it with this executable for the flow
+// steps, and removes the fixture unless a step failed
+func executeReplay(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("cannot read replay script: %v", err)}
+	}
+	defer file.Close()
+	script, err := replay.Parse(file)
+	if err != nil {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("invalid replay script '%s': %v", path, err)}
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return &errors.GitError{Operation: "locate the git-flow executable", Err: err}
+	}
+	runner, err := replay.NewRunner(binary, os.Stdout)
+	if err != nil {
+		return &errors.GitError{Operation: "create the fixture directory", Err: err}
+	}
+	if err := runner.Run(script); err != nil {
+		return &errors.ReplayFailedError{Script: path, Err: err, Fixture: runner.Dir}
+	}
+	os.RemoveAll(runner.Dir)
+	fmt.Printf("Replay of '%s' passed (%d steps)\n", path, len(script.Steps))
+	return nil
+}
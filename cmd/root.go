@@ -1,9 +1,28 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/profile"
 	"github.com/spf13/cobra"
 )
 
+// dataOutputAnnotations marks commands whose standard output is the requested
+// data itself, such as listings, so --quiet leaves their output untouched
+var dataOutputAnnotations = map[string]string{"dataOutput": "true"}
+
+// commandCache answers repeated Git queries for the duration of the command
+var commandCache *git.Cache
+
+// remoteOverride is the remote given with the global --remote flag
+var remoteOverride string
+
 var rootCmd = &cobra.Command{
 	Use:   "git-flow",
 	Short: "git-flow-next is a modern reimplementation of git-flow",
@@ -16,15 +35,120 @@ It provides a set of commands to work with Git branches according to the git-flo
   git flow feature finish my-feature
   git flow release start 1.0.0
   git flow release finish 1.0.0`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commandCache = git.NewCache()
+		remoteOverride, _ = cmd.Flags().GetString("remote")
+
+		if enabled, _ := cmd.Flags().GetBool("profile"); enabled {
+			profile.Enable()
+		}
+
+		if enabled, _ := cmd.Flags().GetBool("porcelain"); enabled {
+			output.EnablePorcelain()
+		}
+
+		// Discard informational output unless the command's output is the data itself
+		if quiet, _ := cmd.Flags().GetBool("quiet"); quiet && cmd.Annotations["dataOutput"] != "true" {
+			if err := output.EnableQuiet(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to enable quiet mode: %v\n", err)
+			}
+		}
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// Drop the cached Git state and stop the helper process
+		commandCache.Close()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if script, _ := cmd.Flags().GetString("replay"); script != "" {
+			ReplayCommand(script)
+			return
+		}
 		// If no subcommand is provided, print help
 		cmd.Help()
 	},
 }
 
+// loadContextOrExit loads the configuration context for the running command.
+// The context is loaded once and passed down explicitly, so the command works
+// with one consistent view of the configuration. Loading errors end the process.
+func loadContextOrExit() *config.Context {
+	cfgCtx, err := loadContext()
+	if err != nil {
+		printError(&errors.GitError{Operation: "load configuration", Err: err})
+		os.Exit(int(errors.ExitCodeGitError))
+	}
+	return cfgCtx
+}
+
+// loadContext loads the configuration context with the --remote override
+func loadContext() (*config.Context, error) {
+	return config.LoadContextWithRemote(remoteOverride)
+}
+
+// newDeps returns the dependencies of the command line for the running
+// command, sharing its Git cache and writing its results in quiet mode
+func newDeps() *commands.Deps {
+	deps := commands.NewDeps(commandCache)
+	deps.Results = output.ResultWriter()
+	return deps
+}
+
+// readOnlyConfig returns the configuration for a command that only shows the
+// repository. Without initialization it fails, or with --best-effort infers
+// the base branch names and topic prefixes from the local and remote branches.
+func readOnlyConfig(cfgCtx *config.Context, bestEffort bool) (*config.Config, error) {
+	if cfgCtx.Initialized {
+		return cfgCtx.Config, nil
+	}
+	if !bestEffort {
+		return nil, &errors.NotInitializedError{}
+	}
+
+	branches, err := git.ListBranches()
+	if err != nil {
+		return nil, &errors.GitError{Operation: "list branches", Err: err}
+	}
+	remote := cfgCtx.Config.Remote
+	if remoteBranches, err := git.RemoteBranches(remote); err == nil {
+		branches = append(branches, remoteBranches...)
+	}
+
+	fmt.Fprintln(os.Stderr, "Note: git-flow is not initialized; branch names and prefixes are inferred from the existing branches")
+	cfg := config.InferConfig(branches)
+	cfg.Remote = remote
+	return cfg, nil
+}
+
+// printError reports err on standard error with its failed git command and
+// hint, or as a JSON object in porcelain mode
+func printError(err error) {
+	if output.IsPorcelain() {
+		errors.RenderJSON(os.Stderr, err)
+		return
+	}
+	errors.Render(os.Stderr, err)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
+	// Topic branch commands are registered last, once every built-in command exists
+	RegisterTopicBranchCommands()
+
+	// Run as 'git flow', a relative --git-dir or --work-tree reaches us in the
+	// environment; pin it before any process is started in another directory
+	if err := git.AbsolutizePathEnv(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to resolve the Git environment: %v\n", err)
+	}
+
+	// Commands git-flow doesn't know are run as extensions from PATH
+	if len(os.Args) > 1 {
+		if path, ok := findExtension(os.Args[1]); ok {
+			os.Exit(runExtension(path, os.Args[2:]))
+		}
+	}
+
+	rootCmd.SetHelpFunc(rootHelp(rootCmd.HelpFunc()))
 	return rootCmd.Execute()
 }
 
@@ -33,4 +157,12 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().String("remote", "", "Remote to use instead of the configured gitflow.origin")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only print errors and requested data")
+	rootCmd.PersistentFlags().Bool("porcelain", false, "Report errors and operation events as JSON on standard error")
+	rootCmd.PersistentFlags().Bool("profile", false, "Report how long each stage of finish and update took")
+
+	// Reproduces scripted scenarios for bug reports and tests, see internal/replay
+	rootCmd.Flags().String("replay", "", "Run a replay script against a new fixture repository")
+	rootCmd.Flags().MarkHidden("replay")
 }
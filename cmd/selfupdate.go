@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/selfupdate"
+	"github.com/gittower/git-flow-next/version"
+	"github.com/spf13/cobra"
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update git-flow-next to the latest release",
+	Long: `Download the latest git-flow-next release from GitHub and replace the running
+binary with it. The download is verified against the checksums published with
+the release and their signature by the release key this build carries; builds
+without a release key, such as development builds, cannot update themselves.
+
+Installations made with a package manager such as Homebrew, Scoop or apt are
+not updated; use the package manager instead.`,
+	Example: "  git flow self-update --check\n  git flow self-update",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		prerelease, _ := cmd.Flags().GetBool("prerelease")
+		force, _ := cmd.Flags().GetBool("force")
+		SelfUpdateCommand(checkOnly, prerelease, force)
+	},
+}
+
+// SelfUpdateCommand is the implementation of the self-update command
+func SelfUpdateCommand(checkOnly, prerelease, force bool) {
+	if err := executeSelfUpdate(checkOnly, prerelease, force); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// executeSelfUpdate looks up the latest release and, unless checkOnly is set,
+// installs it over the running executable
+func executeSelfUpdate(checkOnly, prerelease, force bool) error {
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "locate the git-flow executable", Err: err}
+	}
+
+	manager := selfupdate.PackageManager(version.InstallMethod, executable)
+	if manager != "" && !checkOnly {
+		return &errors.ManagedInstallError{Manager: manager, UpgradeCommand: selfupdate.UpgradeCommand(manager)}
+	}
+
+	release, err := selfupdate.FetchLatestRelease(selfupdate.ReleasesURL(), prerelease)
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "look up the latest release", Err: err}
+	}
+	tag := release.TagName
+
+	if selfupdate.CompareVersions(tag, version.Version) <= 0 && !force {
+		fmt.Printf("git-flow-next %s is up to date (latest release: %s)\n", version.Version, tag)
+		return nil
+	}
+	if checkOnly {
+		fmt.Printf("git-flow-next %s is available (installed: %s)\n", tag, version.Version)
+		if command := selfupdate.UpgradeCommand(manager); command != "" {
+			fmt.Printf("Update it with '%s'\n", command)
+		} else if manager == "" {
+			fmt.Println("Run 'git flow self-update' to install it")
+		}
+		return nil
+	}
+
+	archiveName := selfupdate.ArchiveName(tag, runtime.GOOS, runtime.GOARCH)
+	archiveAsset := release.Asset(archiveName)
+	if archiveAsset == nil {
+		return &errors.SelfUpdateError{Operation: "find a download for this platform", Err: fmt.Errorf("release %s has no %s", tag, archiveName)}
+	}
+	checksumsName := selfupdate.ChecksumsName(tag)
+	checksumsAsset := release.Asset(checksumsName)
+	if checksumsAsset == nil {
+		return &errors.ReleaseVerificationError{Release: tag, Reason: fmt.Sprintf("the release has no %s", checksumsName)}
+	}
+
+	// Without a key, whoever controls the release server controls the checksums
+	if version.ReleasePublicKey == "" {
+		return &errors.ReleaseVerificationError{Release: tag, Reason: "this build carries no release key to verify the download with; install the release manually"}
+	}
+
+	checksums, err := selfupdate.Download(checksumsAsset.URL)
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "download " + checksumsName, Err: err}
+	}
+	signatureAsset := release.Asset(checksumsName + ".sig")
+	if signatureAsset == nil {
+		return &errors.ReleaseVerificationError{Release: tag, Reason: fmt.Sprintf("%s is not signed", checksumsName)}
+	}
+	signature, err := selfupdate.Download(signatureAsset.URL)
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "download " + signatureAsset.Name, Err: err}
+	}
+	if err := selfupdate.VerifySignature(checksums, signature, version.ReleasePublicKey); err != nil {
+		return &errors.ReleaseVerificationError{Release: tag, Reason: err.Error()}
+	}
+	fmt.Printf("Verified signature of %s\n", checksumsName)
+
+	fmt.Printf("Downloading %s...\n", archiveName)
+	archive, err := selfupdate.Download(archiveAsset.URL)
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "download " + archiveName, Err: err}
+	}
+	if err := selfupdate.VerifyChecksum(checksums, archiveName, archive); err != nil {
+		return &errors.ReleaseVerificationError{Release: tag, Reason: err.Error()}
+	}
+	fmt.Printf("Verified checksum of %s\n", archiveName)
+
+	binary, err := selfupdate.ExtractBinary(archive, archiveName, selfupdate.BinaryName(tag, runtime.GOOS, runtime.GOARCH))
+	if err != nil {
+		return &errors.SelfUpdateError{Operation: "extract " + archiveName, Err: err}
+	}
+	if err := selfupdate.ReplaceExecutable(executable, binary); err != nil {
+		return &errors.SelfUpdateError{Operation: "replace " + executable, Err: err}
+	}
+
+	fmt.Printf("Updated git-flow-next from %s to %s\n", version.Version, tag)
+	return nil
+}
+
+func init() {
+	selfUpdateCmd.Flags().Bool("check", false, "Only report whether a newer release is available")
+	selfUpdateCmd.Flags().Bool("prerelease", false, "Include preview releases")
+	selfUpdateCmd.Flags().Bool("force", false, "Install the latest release even if it is not newer")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
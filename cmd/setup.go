@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// versionMergeDriver is the name of the merge driver for version files
+	versionMergeDriver = "gitflow-version"
+
+	// gitattributesComment marks the .gitattributes block written by setup
+	gitattributesComment = "# git-flow: merge driver for version files"
+)
+
+// versionMergeStrategies maps the --strategy values to merge driver commands
+var versionMergeStrategies = map[string]string{
+	"ours":  "true",
+	"union": "git merge-file --union %A %O %B",
+}
+
+// setupCmd represents the setup command
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Set up repository integrations for git-flow",
+	Long: `Set up repository integrations that make git-flow workflows smoother.
+
+Examples:
+  git-flow setup merge-driver version
+  git-flow setup merge-driver version status
+  git-flow setup merge-driver version remove`,
+}
+
+// setupMergeDriverCmd represents the setup merge-driver command
+var setupMergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver",
+	Short: "Register merge drivers for files that conflict on every back-merge",
+}
+
+// setupMergeDriverVersionCmd represents the setup merge-driver version command
+var setupMergeDriverVersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Register a merge driver for the declared version files",
+	Long: `Register a merge driver for the version files declared in gitflow.version.file
+so that back-merges of main into develop stop conflicting on version bumps.
+
+The driver is configured in the local Git config as merge.gitflow-version and
+assigned to the version files in the .gitattributes file at the root of the
+working tree. Commit .gitattributes to share the assignment; every clone needs
+to run this command to configure the driver itself.
+
+With --strategy ours (the default) a merge keeps the version of the branch
+being merged into; with --strategy union the lines of both sides are kept.
+
+Declare version files with:
+  git config --add gitflow.version.file version.txt
+
+Examples:
+  git-flow setup merge-driver version
+  git-flow setup merge-driver version --strategy union
+  git-flow setup merge-driver version status
+  git-flow setup merge-driver version remove`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		strategy, _ := cmd.Flags().GetString("strategy")
+		SetupVersionMergeDriverCommand(strategy)
+	},
+}
+
+// setupMergeDriverVersionStatusCmd represents the setup merge-driver version status command
+var setupMergeDriverVersionStatusCmd = &cobra.Command{
+	Use:         "status",
+	Short:       "Show whether the version file merge driver is set up",
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		SetupVersionMergeDriverStatusCommand()
+	},
+}
+
+// setupMergeDriverVersionRemoveCmd represents the setup merge-driver version remove command
+var setupMergeDriverVersionRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the version file merge driver and its .gitattributes entries",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		SetupVersionMergeDriverRemoveCommand()
+	},
+}
+
+// SetupVersionMergeDriverCommand is the implementation of the setup merge-driver version command
+func SetupVersionMergeDriverCommand(strategy string) {
+	exitOnSetupError(executeSetupVersionMergeDriver(strategy))
+}
+
+// SetupVersionMergeDriverStatusCommand is the implementation of the setup merge-driver version status command
+func SetupVersionMergeDriverStatusCommand() {
+	exitOnSetupError(executeSetupVersionMergeDriverStatus())
+}
+
+// SetupVersionMergeDriverRemoveCommand is the implementation of the setup merge-driver version remove command
+func SetupVersionMergeDriverRemoveCommand() {
+	exitOnSetupError(executeSetupVersionMergeDriverRemove())
+}
+
+// exitOnSetupError prints err and exits with its exit code, if err is set
+func exitOnSetupError(err error) {
+	if err == nil {
+		return
+	}
+	var exitCode errors.ExitCode
+	if flowErr, ok := err.(errors.Error); ok {
+		exitCode = flowErr.ExitCode()
+	} else {
+		exitCode = errors.ExitCodeGitError
+	}
+	printError(err)
+	os.Exit(int(exitCode))
+}
+
+func executeSetupVersionMergeDriver(strategy string) error {
+	driver, ok := versionMergeStrategies[strategy]
+	if !ok {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("invalid strategy '%s'; must be ours or union", strategy)}
+	}
+	files := declaredVersionFiles()
+	if len(files) == 0 {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("no version files declared; declare them with 'git config --add %s <path>'", config.KeyVersionFile)}
+	}
+	attributesPath, err := gitattributesPath()
+	if err != nil {
+		return err
+	}
+
+	if err := git.SetConfig(fmt.Sprintf("merge.%s.name", versionMergeDriver), fmt.Sprintf("git-flow version files (%s)", strategy)); err != nil {
+		return &errors.GitError{Operation: "configure merge driver", Err: err}
+	}
+	if err := git.SetConfig(fmt.Sprintf("merge.%s.driver", versionMergeDriver), driver); err != nil {
+		return &errors.GitError{Operation: "configure merge driver", Err: err}
+	}
+	fmt.Printf("Configured merge driver '%s' (%s)\n", versionMergeDriver, strategy)
+
+	lines, err := readGitattributes(attributesPath)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for _, line := range lines {
+		existing[strings.TrimSpace(line)] = true
+	}
+	var added []string
+	for _, file := range files {
+		entry := gitattributesEntry(file)
+		if !existing[entry] {
+			added = append(added, entry)
+		}
+	}
+	if len(added) == 0 {
+		fmt.Println(".gitattributes already assigns the driver to all version files")
+		return nil
+	}
+	if !existing[gitattributesComment] {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, gitattributesComment)
+	}
+	lines = append(lines, added...)
+	if err := writeGitattributes(attributesPath, lines); err != nil {
+		return err
+	}
+	for _, entry := range added {
+		fmt.Printf("Added '%s' to .gitattributes\n", entry)
+	}
+	fmt.Println("Commit .gitattributes to share the assignment with other clones")
+	return nil
+}
+
+func executeSetupVersionMergeDriverStatus() error {
+	attributesPath, err := gitattributesPath()
+	if err != nil {
+		return err
+	}
+
+	driver, _ := git.GetConfig(fmt.Sprintf("merge.%s.driver", versionMergeDriver))
+	strategy := ""
+	for name, command := range versionMergeStrategies {
+		if command == driver {
+			strategy = name
+		}
+	}
+	switch {
+	case driver == "":
+		fmt.Println("Merge driver:  not configured")
+	case strategy != "":
+		fmt.Printf("Merge driver:  configured (%s)\n", strategy)
+	default:
+		fmt.Printf("Merge driver:  configured (custom: %s)\n", driver)
+	}
+
+	files := declaredVersionFiles()
+	if len(files) == 0 {
+		fmt.Printf("Version files: none declared (%s)\n", config.KeyVersionFile)
+		return nil
+	}
+	lines, err := readGitattributes(attributesPath)
+	if err != nil {
+		return err
+	}
+	assigned := make(map[string]bool)
+	for _, line := range lines {
+		assigned[strings.TrimSpace(line)] = true
+	}
+	fmt.Println("Version files:")
+	for _, file := range files {
+		if assigned[gitattributesEntry(file)] {
+			fmt.Printf("  %s: assigned in .gitattributes\n", file)
+		} else {
+			fmt.Printf("  %s: not assigned in .gitattributes\n", file)
+		}
+	}
+	return nil
+}
+
+func executeSetupVersionMergeDriverRemove() error {
+	attributesPath, err := gitattributesPath()
+	if err != nil {
+		return err
+	}
+
+	if driver, _ := git.GetConfig(fmt.Sprintf("merge.%s.driver", versionMergeDriver)); driver != "" {
+		if err := git.UnsetConfigSection(fmt.Sprintf("merge.%s", versionMergeDriver)); err != nil {
+			return &errors.GitError{Operation: "remove merge driver", Err: err}
+		}
+		fmt.Printf("Removed merge driver '%s'\n", versionMergeDriver)
+	} else {
+		fmt.Printf("Merge driver '%s' is not configured\n", versionMergeDriver)
+	}
+
+	lines, err := readGitattributes(attributesPath)
+	if err != nil {
+		return err
+	}
+	suffix := " merge=" + versionMergeDriver
+	var kept []string
+	removed := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == gitattributesComment || strings.HasSuffix(trimmed, suffix) {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return nil
+	}
+	// Drop the blank line that separated the block
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+	if len(kept) == 0 {
+		if err := os.Remove(attributesPath); err != nil {
+			return &errors.GitError{Operation: "remove .gitattributes", Err: err}
+		}
+		fmt.Println("Removed .gitattributes, which only held the version file entries")
+		return nil
+	}
+	if err := writeGitattributes(attributesPath, kept); err != nil {
+		return err
+	}
+	fmt.Println("Removed the version file entries from .gitattributes")
+	return nil
+}
+
+// declaredVersionFiles returns the version files declared in gitflow.version.file
+func declaredVersionFiles() []string {
+	values, _ := git.GetConfigAllValues(config.KeyVersionFile)
+	var files []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			files = append(files, filepath.ToSlash(value))
+		}
+	}
+	return files
+}
+
+// gitattributesEntry returns the .gitattributes line assigning the driver to file
+func gitattributesEntry(file string) string {
+	return fmt.Sprintf("/%s merge=%s", strings.TrimPrefix(file, "/"), versionMergeDriver)
+}
+
+// gitattributesPath returns the path of the .gitattributes file at the root of the working tree
+func gitattributesPath() (string, error) {
+	root, err := git.GetTopLevelDir()
+	if err != nil {
+		return "", &errors.GitError{Operation: "find working tree root", Err: err}
+	}
+	return filepath.Join(root, ".gitattributes"), nil
+}
+
+// readGitattributes returns the lines of the .gitattributes file, or none if it doesn't exist
+func readGitattributes(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &errors.GitError{Operation: "read .gitattributes", Err: err}
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		return nil, nil
+	}
+	return strings.Split(strings.TrimRight(string(content), "\n"), "\n"), nil
+}
+
+// writeGitattributes writes lines to the .gitattributes file
+func writeGitattributes(path string, lines []string) error {
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return &errors.GitError{Operation: "write .gitattributes", Err: err}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(setupCmd)
+	setupCmd.AddCommand(setupMergeDriverCmd)
+	setupMergeDriverCmd.AddCommand(setupMergeDriverVersionCmd)
+	setupMergeDriverVersionCmd.AddCommand(setupMergeDriverVersionStatusCmd)
+	setupMergeDriverVersionCmd.AddCommand(setupMergeDriverVersionRemoveCmd)
+
+	setupMergeDriverVersionCmd.Flags().String("strategy", "ours", "How the driver merges version files: ours or union")
+}
@@ -6,8 +6,11 @@ import (
 	"os"
 	"strings"
 
+	"github.com/gittower/git-flow-next/internal/commands"
 	"github.com/gittower/git-flow-next/internal/config"
-	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -18,23 +21,53 @@ func init() {
 
 // RegisterShorthandCommands adds shorthand commands to the root
 func RegisterShorthandCommands() {
+	// Start (takes the branch type as an argument, since there is no current topic branch to detect it from)
+	startCmd := &cobra.Command{
+		Use:   "start <type> <name> [base]",
+		Short: "Start a new topic branch of the given type",
+		Long: `Starts a new topic branch of any configured topic type, including custom
+types added with 'git flow config add topic'. This is equivalent to
+'git flow <type> start <name> [base]'.`,
+		Example: "  git flow start spike cache-experiment\n  git flow start bugfix login-crash develop",
+		Args:    cobra.RangeArgs(2, 3),
+		Run: func(cmd *cobra.Command, args []string) {
+			var base string
+			if len(args) > 2 {
+				base = args[2]
+			}
+			describe, _ := cmd.Flags().GetBool("edit")
+			StartCommand(loadContextOrExit(), args[0], args[1], base, "", getBoolPtr(cmd, "fetch", "no-fetch"), getBoolPtr(cmd, "from-remote", "no-from-remote"), getBoolPtr(cmd, "publish", "no-publish"), describe, getBoolPtr(cmd, "track-upstream", "no-track-upstream"))
+		},
+	}
+	startCmd.Flags().Bool("fetch", false, "Fetch from remote before creating branch")
+	startCmd.Flags().Bool("no-fetch", false, "Don't fetch from remote before creating branch")
+	startCmd.Flags().Bool("from-remote", false, "Create the branch from the remote branch of its base, fast-forwarding the local base if possible")
+	startCmd.Flags().Bool("no-from-remote", false, "Create the branch from the local base branch")
+	startCmd.Flags().Bool("publish", false, "Push the new branch to the remote and set up tracking")
+	startCmd.Flags().Bool("no-publish", false, "Don't publish the new branch")
+	startCmd.Flags().Bool("track-upstream", false, "Track the remote branch of the same name when it exists or is published")
+	startCmd.Flags().Bool("no-track-upstream", false, "Don't set up upstream tracking for the new branch")
+	startCmd.Flags().BoolP("edit", "e", false, "Write a description for the new branch in the editor")
+	rootCmd.AddCommand(startCmd)
+
 	// Delete (with optional name for off-branch deletion, per issue test case)
 	deleteCmd := &cobra.Command{
 		Use:   "delete [name]",
 		Short: "Delete the current topic branch (or specified if provided)",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgCtx := loadContextOrExit()
 			var branchType, name string
 			var err error
 			if len(args) > 0 {
 				// Use provided name (detect type from it)
-				branchType, name, err = detectBranchTypeAndNameFromString(args[0])
+				branchType, name, err = detectBranchTypeAndNameFromString(cfgCtx.Config, args[0])
 			} else {
 				// Use current branch
-				branchType, name, err = detectBranchTypeAndName()
+				branchType, name, err = detectBranchTypeAndName(cfgCtx.Config, "git flow delete <branch>")
 			}
 			if err != nil {
-				return err
+				exitWithShorthandError(err)
 			}
 			var force *bool
 			if cmd.Flags().Changed("force") {
@@ -52,7 +85,7 @@ func RegisterShorthandCommands() {
 				f := false
 				remote = &f
 			}
-			DeleteCommand(branchType, name, force, remote)
+			DeleteCommand(cfgCtx, branchType, name, force, remote)
 			return nil
 		},
 	}
@@ -64,25 +97,35 @@ func RegisterShorthandCommands() {
 
 	// Update
 	updateCmd := &cobra.Command{
-		Use:   "update",
-		Short: "Update the current topic branch from parent",
+		Use:   "update [branch]",
+		Short: "Update the current topic branch (or specified if provided) from parent",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useRebase, _ := cmd.Flags().GetBool("rebase")
-			return executeShorthandUpdate(useRebase, args)
+			noVerify, _ := cmd.Flags().GetBool("no-verify")
+			if err := executeShorthandUpdate(loadContextOrExit(), useRebase, getSingleBoolPtr(noVerify), args); err != nil {
+				exitWithShorthandError(err)
+			}
+			return nil
 		},
 	}
 	updateCmd.Flags().Bool("rebase", false, "Force rebase strategy instead of configured strategy")
+	updateCmd.Flags().Bool("no-verify", false, "Bypass the commit and pre-rebase hooks while updating")
 	rootCmd.AddCommand(updateCmd)
 
 	// Rebase (shorthand for update --rebase)
 	rebaseCmd := &cobra.Command{
-		Use:   "rebase",
-		Short: "Rebase the current topic branch from parent",
+		Use:   "rebase [branch]",
+		Short: "Rebase the current topic branch (or specified if provided) from parent",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Always use rebase strategy for this shorthand
-			return executeShorthandUpdate(true, args)
+			noVerify, _ := cmd.Flags().GetBool("no-verify")
+			if err := executeShorthandUpdate(loadContextOrExit(), true, getSingleBoolPtr(noVerify), args); err != nil {
+				exitWithShorthandError(err)
+			}
+			return nil
 		},
 	}
+	rebaseCmd.Flags().Bool("no-verify", false, "Bypass the pre-rebase hook")
 	rootCmd.AddCommand(rebaseCmd)
 
 	// Rename
@@ -91,11 +134,12 @@ func RegisterShorthandCommands() {
 		Short: "Rename the current topic branch",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			branchType, oldName, err := detectBranchTypeAndName()
+			cfgCtx := loadContextOrExit()
+			branchType, oldName, err := detectBranchTypeAndName(cfgCtx.Config, "git flow <type> rename <old-name> <new-name>")
 			if err != nil {
-				return err
+				exitWithShorthandError(err)
 			}
-			RenameCommand(branchType, oldName, args[0])
+			RenameCommand(cfgCtx, branchType, oldName, args[0])
 			return nil
 		},
 	}
@@ -103,35 +147,54 @@ func RegisterShorthandCommands() {
 
 	// Publish
 	publishCmd := &cobra.Command{
-		Use:   "publish",
-		Short: "Publish the current topic branch to remote",
+		Use:   "publish [branch]",
+		Short: "Publish the current topic branch (or specified if provided) to remote",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			branchType, name, err := detectBranchTypeAndName()
+			cfgCtx := loadContextOrExit()
+			branchType, name, err := detectShorthandBranch(cfgCtx.Config, args, "git flow publish <branch>")
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				exitWithShorthandError(err)
 			}
 			pushOptions, _ := cmd.Flags().GetStringArray("push-option")
 			noPushOption, _ := cmd.Flags().GetBool("no-push-option")
-			PublishCommand(branchType, name, pushOptions, noPushOption)
+			forceWithLease, _ := cmd.Flags().GetBool("force-with-lease")
+			trackInstead, _ := cmd.Flags().GetBool("track-instead")
+			openPR, _ := cmd.Flags().GetBool("pr")
+			draft, _ := cmd.Flags().GetBool("draft")
+			PublishCommand(cfgCtx, branchType, name, pushOptions, noPushOption, forceWithLease, trackInstead, openPR, draft, getBoolPtr(cmd, "track-upstream", "no-track-upstream"))
 		},
 	}
 	publishCmd.Flags().StringArrayP("push-option", "o", nil, "Push option to transmit to the server (repeatable)")
 	publishCmd.Flags().Bool("no-push-option", false, "Don't send any push options (overrides config defaults)")
+	publishCmd.Flags().Bool("force-with-lease", false, "Overwrite an existing remote branch unless it changed since the last fetch")
+	publishCmd.Flags().Bool("track-instead", false, "Track an existing remote branch instead of pushing")
+	publishCmd.Flags().Bool("pr", false, "Open a pull request against the parent branch after publishing")
+	publishCmd.Flags().Bool("draft", false, "Open the pull request as a draft (implies --pr)")
+	publishCmd.Flags().Bool("track-upstream", false, "Make the local branch track the published branch")
+	publishCmd.Flags().Bool("no-track-upstream", false, "Push without setting up upstream tracking")
 	rootCmd.AddCommand(publishCmd)
 
 	// Finish
 	finishCmd := &cobra.Command{
-		Use:   "finish",
-		Short: "Finish the current topic branch",
+		Use:   "finish [branch] | --batch <branch>...",
+		Short: "Finish the current topic branch (or specified if provided)",
+		Args:  finishArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			branchType, name, err := detectBranchTypeAndName()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
+			cfgCtx := loadContextOrExit()
 			continueOp, _ := cmd.Flags().GetBool("continue")
 			abortOp, _ := cmd.Flags().GetBool("abort")
+			var branchType, name string
+			var err error
+			if state, _ := mergestate.LoadMergeState(); state != nil && state.Action == "finish" && (continueOp || abortOp) {
+				// The interrupted finish knows its branch; a rebase may have detached HEAD
+				branchType, name = state.BranchType, state.BranchName
+			} else {
+				branchType, name, err = detectShorthandBranch(cfgCtx.Config, args, "git flow finish <branch>")
+			}
+			if err != nil {
+				exitWithShorthandError(err)
+			}
 			force, _ := cmd.Flags().GetBool("force")
 			tagOptions := &config.TagOptions{
 				ShouldTag:   getBoolPtr(cmd, "tag", "notag"),
@@ -141,6 +204,10 @@ func RegisterShorthandCommands() {
 				MessageFile: cmd.Flag("messagefile").Value.String(),
 				TagName:     cmd.Flag("tagname").Value.String(),
 			}
+			tagOptions.ExtraTags, _ = cmd.Flags().GetStringArray("extra-tag")
+			tagOptions.NoExtraTags, _ = cmd.Flags().GetBool("no-extra-tags")
+			tagOptions.Retag, _ = cmd.Flags().GetBool("retag")
+			tagOptions.SkipTag, _ = cmd.Flags().GetBool("skip-tag")
 			retentionOptions := &config.BranchRetentionOptions{
 				Keep:        getBoolPtr(cmd, "keep", "no-keep"),
 				KeepRemote:  getBoolPtr(cmd, "keepremote", "no-keepremote"),
@@ -152,16 +219,41 @@ func RegisterShorthandCommands() {
 				Rebase:         getBoolPtr(cmd, "rebase", "no-rebase"),
 				PreserveMerges: getBoolPtr(cmd, "preserve-merges", "no-preserve-merges"),
 				NoFF:           getBoolPtr(cmd, "no-ff", "ff"),
+				FFOnly:         getBoolPtr(cmd, "ff-only", ""),
 				Squash:         getBoolPtr(cmd, "squash", "no-squash"),
 				SquashMessage:  getStringPtrFromFlag(cmd, "squash-message"),
 			}
-			// Get no-verify flag
+			mergeOptions.ChildStrategy, _ = cmd.Flags().GetStringArray("child-strategy")
+			mergeOptions.NoUpdate, _ = cmd.Flags().GetStringArray("no-update")
+			mergeOptions.Edit, _ = cmd.Flags().GetBool("edit")
+			mergeOptions.IfMerged, _ = cmd.Flags().GetBool("if-merged")
+			mergeOptions.Trailers, _ = cmd.Flags().GetStringArray("trailer")
+			mergeOptions.AutostashUntracked = getBoolPtr(cmd, "autostash-untracked", "no-autostash-untracked")
+			mergeOptions.Summary = getBoolPtr(cmd, "summary", "no-summary")
+			mergeOptions.Backport, _ = cmd.Flags().GetStringArray("backport")
+			mergeOptions.NoBackport, _ = cmd.Flags().GetBool("no-backport")
+			mergeOptions.BackportPR = getBoolPtr(cmd, "backport-pr", "no-backport-pr")
+			if batch, _ := cmd.Flags().GetBool("batch"); batch && !(continueOp || abortOp) {
+				for _, branch := range args[1:] {
+					batchType, batchName, err := detectBranchTypeAndNameFromString(cfgCtx.Config, branch)
+					if err != nil {
+						exitWithShorthandError(err)
+					}
+					if batchType != branchType {
+						exitWithShorthandError(&errors.InvalidInputError{Message: fmt.Sprintf("--batch: '%s' is not a %s branch like '%s'", branch, branchType, args[0])})
+					}
+					mergeOptions.Batch = append(mergeOptions.Batch, batchName)
+				}
+			}
+			// Get no-verify flags
 			noVerify, _ := cmd.Flags().GetBool("no-verify")
 			var noVerifyPtr *bool
 			if noVerify {
 				noVerifyPtr = &noVerify
 			}
-			FinishCommand(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, mergeOptions, nil, noVerifyPtr)
+			noVerifyChildren, _ := cmd.Flags().GetBool("no-verify-children")
+			to, _ := cmd.Flags().GetString("to")
+			FinishCommand(cfgCtx, branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, mergeOptions, nil, getBoolPtr(cmd, "push", "no-push"), noVerifyPtr, getSingleBoolPtr(noVerifyChildren), to)
 		},
 	}
 
@@ -170,26 +262,23 @@ func RegisterShorthandCommands() {
 }
 
 // executeShorthandUpdate handles the shared logic for both update and rebase shorthand commands
-func executeShorthandUpdate(useRebase bool, args []string) error {
-	branchType, name, err := detectBranchTypeAndName()
+func executeShorthandUpdate(cfgCtx *config.Context, useRebase bool, noVerify *bool, args []string) error {
+	// An explicit branch needs neither the current branch nor a topic prefix
+	if len(args) > 0 {
+		return executeUpdate(cfgCtx, "", args[0], useRebase, noVerify)
+	}
+	branchType, name, err := detectBranchTypeAndName(cfgCtx.Config, "git flow update <branch>")
 	if err == nil {
-		return executeUpdate(branchType, name, useRebase)
+		return executeUpdate(cfgCtx, branchType, name, useRebase, noVerify)
 	}
 	// Fallback to original if not topic
-	var branchName string
-	if len(args) > 0 {
-		branchName = args[0]
-	}
-	return executeUpdate("", branchName, useRebase)
+	return executeUpdate(cfgCtx, "", "", useRebase, noVerify)
 }
 
-// detectBranchTypeAndName detects type and name from current branch
-func detectBranchTypeAndName() (string, string, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return "", "", err
-	}
-	currentBranch, err := git.GetCurrentBranch()
+// detectBranchTypeAndName detects type and name from current branch. usage is
+// suggested when HEAD is detached.
+func detectBranchTypeAndName(cfg *config.Config, usage string) (string, string, error) {
+	currentBranch, err := commands.CurrentBranchFor(newDeps(), usage)
 	if err != nil {
 		return "", "", err
 	}
@@ -217,8 +306,8 @@ func detectBranchTypeAndName() (string, string, error) {
 		for _, m := range matches {
 			typesStr = append(typesStr, m.Type)
 		}
-		fmt.Printf("Ambiguous branch '%s' matches multiple types: %s\n", currentBranch, strings.Join(typesStr, ", "))
-		fmt.Print("Use explicit command? [Y/n]: ")
+		output.Prompt("Ambiguous branch '%s' matches multiple types: %s\n", currentBranch, strings.Join(typesStr, ", "))
+		output.Prompt("Use explicit command? [Y/n]: ")
 		reader := bufio.NewReader(os.Stdin)
 		response, _ := reader.ReadString('\n')
 		response = strings.TrimSpace(strings.ToLower(response))
@@ -229,12 +318,27 @@ func detectBranchTypeAndName() (string, string, error) {
 	}
 }
 
-// detectBranchTypeAndNameFromString detects from a given string (for delete [name])
-func detectBranchTypeAndNameFromString(branch string) (string, string, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return "", "", err
+// detectShorthandBranch detects type and name from the branch given in args,
+// or from the current branch when there is none
+func detectShorthandBranch(cfg *config.Config, args []string, usage string) (string, string, error) {
+	if len(args) > 0 {
+		return detectBranchTypeAndNameFromString(cfg, args[0])
 	}
+	return detectBranchTypeAndName(cfg, usage)
+}
+
+// exitWithShorthandError reports an error of the shorthand commands and exits
+// with its exit code, or 1 for errors without one
+func exitWithShorthandError(err error) {
+	printError(err)
+	if flowErr, ok := err.(errors.Error); ok {
+		os.Exit(int(flowErr.ExitCode()))
+	}
+	os.Exit(1)
+}
+
+// detectBranchTypeAndNameFromString detects from a given string (for delete [name])
+func detectBranchTypeAndNameFromString(cfg *config.Config, branch string) (string, string, error) {
 	matches := []struct{ Type, Prefix string }{}
 	for typ, bc := range cfg.Branches {
 		if bc.Type == string(config.BranchTypeTopic) && strings.HasPrefix(branch, bc.Prefix) {
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and repair the state of an interrupted git-flow operation",
+	Long: `Inspect and repair the state of an interrupted git-flow operation.
+
+Operations that can stop midway (finish, update) record their progress in
+.git/gitflow/state/merge.json so they can be continued or aborted later.
+
+Examples:
+  git-flow state show
+  git-flow state repair
+  git-flow state repair --discard`,
+}
+
+// stateShowCmd represents the state show command
+var stateShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the recorded state of an interrupted operation",
+	Long: `Show the recorded state of an interrupted operation in a readable format.
+
+Example:
+  git-flow state show`,
+	Args:        cobra.NoArgs,
+	Annotations: dataOutputAnnotations,
+	Run: func(cmd *cobra.Command, args []string) {
+		StateShowCommand()
+	},
+}
+
+// stateRepairCmd represents the state repair command
+var stateRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Validate the recorded state and discard or reconstruct it",
+	Long: `Validate the recorded state against the repository.
+
+If the state file cannot be read, references branches that no longer exist, or
+describes steps that were already completed by hand, repair offers to discard
+the state or to reconstruct it from the repository so that the operation can
+be resumed with --continue.
+
+Examples:
+  git-flow state repair
+  git-flow state repair --reconstruct
+  git-flow state repair --discard`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		discard, _ := cmd.Flags().GetBool("discard")
+		reconstruct, _ := cmd.Flags().GetBool("reconstruct")
+		StateRepairCommand(discard, reconstruct)
+	},
+}
+
+// StateShowCommand is the implementation of the state show command
+func StateShowCommand() {
+	if err := commands.StateShow(newDeps()); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// StateRepairCommand is the implementation of the state repair command
+func StateRepairCommand(discard bool, reconstruct bool) {
+	if err := commands.StateRepair(newDeps(), commands.StateRepairOptions{Discard: discard, Reconstruct: reconstruct}); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateShowCmd)
+	stateCmd.AddCommand(stateRepairCmd)
+
+	stateRepairCmd.Flags().Bool("discard", false, "Discard the recorded state without asking")
+	stateRepairCmd.Flags().Bool("reconstruct", false, "Reconstruct the state from the repository, or restore its backup, without asking")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Fetch, fast-forward the base branches and update the current topic branch",
+	Long: `Brings the repository up to date in one go: fetches from the remote,
+fast-forwards every local base branch to its remote branch, updates the current
+topic branch from its parent with the configured strategy, and prints a summary.
+
+Base branches with local commits that are not on the remote are left alone.`,
+	Example: "  git flow sync\n  git flow sync --rebase",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		useRebase, _ := cmd.Flags().GetBool("rebase")
+		noVerify, _ := cmd.Flags().GetBool("no-verify")
+		SyncCommand(loadContextOrExit(), useRebase, getSingleBoolPtr(noVerify))
+	},
+}
+
+func init() {
+	syncCmd.Flags().Bool("rebase", false, "Update the current topic branch with rebase instead of the configured strategy")
+	syncCmd.Flags().Bool("no-verify", false, "Bypass the commit and pre-rebase hooks while updating the topic branch")
+	rootCmd.AddCommand(syncCmd)
+}
+
+// SyncCommand is the implementation of the sync command
+func SyncCommand(cfgCtx *config.Context, useRebase bool, noVerify *bool) {
+	err := commands.Sync(retryContext(cfgCtx.Config), newDeps(), cfgCtx, commands.SyncOptions{Rebase: useRebase, NoVerify: noVerify})
+	if err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
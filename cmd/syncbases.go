@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// syncBasesCmd represents the sync-bases command
+var syncBasesCmd = &cobra.Command{
+	Use:   "sync-bases",
+	Short: "Update each base branch from its parent, down the branch hierarchy",
+	Long: `Walks the base branch hierarchy from the top down and updates every base
+branch from its parent with the branch's downstream strategy (main -> develop
+-> staging ...), the same way finish updates child base branches. Branches that
+already contain their parent are left alone.
+
+A conflict stops the run and saves its state. Resolve the conflict and run
+'git flow sync-bases --continue', or undo the update of the current branch with
+'git flow sync-bases --abort'.
+
+Updates skipped with 'finish --no-update' are marked as reconciled in the
+operation journal once the branch contains its parent again.
+
+For scheduled jobs, --check only reports which branches are behind their
+parent, and --push brings the local base branches up to date with the remote
+first and pushes the updated branches afterwards. The exit code tells the
+outcomes apart: 0 when everything is up to date, 7 when branches were updated
+(or would be, with --check) and 8 when an update stopped on conflicts.`,
+	Example: "  git flow sync-bases\n  git flow sync-bases --continue\n  git flow sync-bases --check\n  git flow sync-bases --push",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		continueOp, _ := cmd.Flags().GetBool("continue")
+		abortOp, _ := cmd.Flags().GetBool("abort")
+		noVerify, _ := cmd.Flags().GetBool("no-verify")
+		check, _ := cmd.Flags().GetBool("check")
+		push, _ := cmd.Flags().GetBool("push")
+		SyncBasesCommand(loadContextOrExit(), continueOp, abortOp, check, push, getSingleBoolPtr(noVerify))
+	},
+}
+
+func init() {
+	syncBasesCmd.Flags().BoolP("continue", "c", false, "Continue after resolving the conflicts of the current update")
+	syncBasesCmd.Flags().BoolP("abort", "a", false, "Abort the update of the current branch and stop")
+	syncBasesCmd.Flags().Bool("no-verify", false, "Bypass the commit and pre-rebase hooks while updating")
+	syncBasesCmd.Flags().Bool("check", false, "Only report the base branches that are behind their parent, without updating them")
+	syncBasesCmd.Flags().Bool("push", false, "Fast-forward the base branches from the remote first and push the updated branches")
+	rootCmd.AddCommand(syncBasesCmd)
+}
+
+// SyncBasesCommand is the implementation of the sync-bases command. Conflicts
+// exit with ExitCodeConflict and updates with ExitCodeUpdated, so scheduled
+// jobs can tell them apart from an up-to-date run.
+func SyncBasesCommand(cfgCtx *config.Context, continueOp bool, abortOp bool, check bool, push bool, noVerify *bool) {
+	options := commands.SyncBasesOptions{Continue: continueOp, Abort: abortOp, Check: check, Push: push, NoVerify: noVerify}
+	updated, err := commands.SyncBases(retryContext(cfgCtx.Config), newDeps(), cfgCtx, options)
+	if err != nil {
+		var exitCode errors.ExitCode
+		if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+			exitCode = errors.ExitCodeConflict
+		} else if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+	if updated {
+		os.Exit(int(errors.ExitCodeUpdated))
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd groups the commands that tag base branches
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Create tags on base branches",
+	Long:  "Creates tags on base branches outside of a finish, such as prerelease tags for delivery channels.",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// tagPrereleaseCmd tags a base branch as the next prerelease of a channel
+var tagPrereleaseCmd = &cobra.Command{
+	Use:   "prerelease --channel <channel>",
+	Short: "Tag a base branch as the next prerelease of a channel",
+	Long: `Tags the head of a base branch, develop by default, as the next prerelease
+of a delivery channel such as beta or nightly, e.g. v1.3.0-beta.4.
+
+The upcoming release version is the latest release tag reachable from the
+branch with its minor version incremented (see gitflow.prerelease.bump), or
+the version given with --version. The number continues from the highest
+existing prerelease tag of the channel for that version.
+
+If the branch head already is the latest prerelease of the channel, nothing is
+tagged. Use --dry-run to only print the tag name.`,
+	Example: "  git flow tag prerelease --channel beta\n  git flow tag prerelease -c nightly --push",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		channel, _ := cmd.Flags().GetString("channel")
+		branch, _ := cmd.Flags().GetString("branch")
+		version, _ := cmd.Flags().GetString("version")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		TagPrereleaseCommand(loadContextOrExit(), channel, branch, version, getBoolPtr(cmd, "push", "no-push"), dryRun)
+	},
+}
+
+func init() {
+	tagPrereleaseCmd.Flags().StringP("channel", "c", "", "Channel to tag, one of gitflow.prerelease.channels (required)")
+	tagPrereleaseCmd.Flags().StringP("branch", "b", "", "Base branch to tag (default: the channel's branch, or the parent of release branches)")
+	tagPrereleaseCmd.Flags().String("version", "", "Upcoming release version, instead of deriving it from the latest release tag")
+	tagPrereleaseCmd.Flags().Bool("push", false, "Push the prerelease tag to the remote")
+	tagPrereleaseCmd.Flags().Bool("no-push", false, "Don't push the prerelease tag")
+	tagPrereleaseCmd.Flags().BoolP("dry-run", "n", false, "Only print the name of the next prerelease tag")
+	tagPrereleaseCmd.MarkFlagRequired("channel")
+	tagCmd.AddCommand(tagPrereleaseCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+// TagPrereleaseCommand tags a base branch as the next prerelease of a channel
+func TagPrereleaseCommand(cfgCtx *config.Context, channel, branch, version string, push *bool, dryRun bool) {
+	if err := executeTagPrerelease(cfgCtx, channel, branch, version, push, dryRun); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+func executeTagPrerelease(cfgCtx *config.Context, channel, branch, version string, push *bool, dryRun bool) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	options, err := config.ResolvePrerelease(cfg, channel, branch, push)
+	if err != nil {
+		return err
+	}
+	if options.Branch == "" {
+		return &errors.InvalidInputError{Message: "no release branch type is configured; choose the branch to tag with --branch"}
+	}
+	if branchConfig, ok := cfg.Branches[options.Branch]; !ok || branchConfig.Type != string(config.BranchTypeBase) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a base branch; prerelease tags are created on base branches", options.Branch)}
+	}
+	head, err := git.BranchCommit(options.Branch)
+	if err != nil {
+		return &errors.BranchNotFoundError{BranchName: options.Branch}
+	}
+
+	tagPrefix := cfg.Branches["release"].TagPrefix
+	upcoming, err := upcomingVersion(options.Branch, tagPrefix, version, options.Bump)
+	if err != nil {
+		return err
+	}
+	releaseTag := tagPrefix + upcoming.String()
+	if git.TagExists(releaseTag) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is already released as '%s'; choose the upcoming version with --version", upcoming, releaseTag)}
+	}
+
+	date := time.Now().UTC().Format("20060102")
+	expand := func(number string) string {
+		return util.ExpandPrereleasePlaceholders(options.Format, upcoming.String(), releaseTag, options.Channel, number, date)
+	}
+	latest, number, err := latestPrerelease(expand)
+	if err != nil {
+		return &errors.GitError{Operation: "list prerelease tags", Err: err}
+	}
+	if latest != "" {
+		if commit, err := git.TagCommit(latest); err == nil && commit == head {
+			fmt.Printf("'%s' is already tagged as '%s'; nothing to tag\n", options.Branch, latest)
+			output.Result("%s", latest)
+			return nil
+		}
+	}
+
+	tag := expand(strconv.Itoa(number + 1))
+	if !git.IsValidTagName(tag) {
+		return &errors.InvalidConfigValueError{Key: config.ChannelKey(options.Channel, config.OptChannelFormat), Value: options.Format, Allowed: []string{"a format that yields a valid tag name"}}
+	}
+	if dryRun {
+		fmt.Printf("Would tag '%s' (%s) as '%s'\n", options.Branch, git.ShortCommit(head), tag)
+		output.Result("%s", tag)
+		return nil
+	}
+
+	tagOptions := &git.TagOptions{
+		Message: fmt.Sprintf("%s prerelease %d of %s", options.Channel, number+1, upcoming),
+		Target:  options.Branch,
+	}
+	if err := git.CreateTag(tag, tagOptions); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("create tag '%s'", tag), Err: err}
+	}
+	fmt.Printf("Tagged '%s' (%s) as '%s'\n", options.Branch, git.ShortCommit(head), tag)
+
+	if options.Push {
+		fmt.Printf("Pushing tag '%s' to remote '%s'...\n", tag, cfg.Remote)
+		if err := git.PushRefsAtomic(retryContext(cfg), cfg.Remote, []string{"refs/tags/" + tag}); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("push tag '%s' (push it with 'git push %s %s')", tag, cfg.Remote, tag), Err: err}
+		}
+		fmt.Printf("Pushed tag '%s' to '%s'\n", tag, cfg.Remote)
+	}
+
+	output.Result("%s", tag)
+	return nil
+}
+
+// upcomingVersion returns the version of the next release: the given version,
+// or the highest release tag reachable from branch with part bumped
+func upcomingVersion(branch, tagPrefix, given, part string) (util.Version, error) {
+	if given != "" {
+		version, ok := util.ParseVersion(strings.TrimPrefix(given, tagPrefix))
+		if !ok {
+			return util.Version{}, &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a version like 1.3.0", given)}
+		}
+		return version, nil
+	}
+
+	tags, err := git.MergedTags(branch, tagPrefix+"*")
+	if err != nil {
+		return util.Version{}, &errors.GitError{Operation: "list release tags", Err: err}
+	}
+	var latest util.Version
+	for _, tag := range tags {
+		if version, ok := util.ParseVersion(strings.TrimPrefix(tag, tagPrefix)); ok && latest.Less(version) {
+			latest = version
+		}
+	}
+	return latest.Bump(part), nil
+}
+
+// latestPrerelease returns the existing tag expand yields for the highest
+// number, and that number; "" and 0 when there is none
+func latestPrerelease(expand func(number string) string) (string, int, error) {
+	// A control character can't be part of a tag name, so it marks where
+	// the number goes
+	template := expand("\x01")
+	tags, err := git.ListTags(strings.ReplaceAll(template, "\x01", "*"))
+	if err != nil {
+		return "", 0, err
+	}
+	pattern := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(template), "\x01", `(\d+)`) + "$")
+	latest, highest := "", 0
+	for _, tag := range tags {
+		match := pattern.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+		if number, err := strconv.Atoi(match[1]); err == nil && number > highest {
+			latest, highest = tag, number
+		}
+	}
+	return latest, highest, nil
+}
@@ -5,14 +5,37 @@ import (
 	"os"
 	"strings"
 
+	"github.com/gittower/git-flow-next/internal/commands"
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
-	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
 	"github.com/spf13/cobra"
 )
 
+// topicTypeAnnotation marks the commands generated for topic branch types
+const topicTypeAnnotation = "topicType"
+
+// builtinCommandNames returns the names and aliases of the top-level commands
+// that don't belong to a topic branch type, including the help and completion
+// commands Cobra adds on its own. A topic type with one of these names can't be
+// used as a command.
+func builtinCommandNames() map[string]bool {
+	names := map[string]bool{"help": true, "completion": true}
+	for _, cmd := range rootCmd.Commands() {
+		if _, ok := cmd.Annotations[topicTypeAnnotation]; ok {
+			continue
+		}
+		names[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			names[alias] = true
+		}
+	}
+	return names
+}
+
 // RegisterTopicBranchCommands dynamically creates commands for topic branches
-// based on configuration.
+// based on configuration. It runs after all built-in commands are registered,
+// so topic types named like a built-in command can be detected and skipped.
 func RegisterTopicBranchCommands() {
 	// Load configuration
 	cfg, err := config.LoadConfig()
@@ -38,7 +61,12 @@ func RegisterTopicBranchCommands() {
 	}
 
 	// Register commands for each topic branch type
+	builtins := builtinCommandNames()
 	for _, branchType := range topicBranchTypes {
+		if builtins[branchType] {
+			fmt.Fprintf(os.Stderr, "Warning: Topic branch type '%s' has the same name as a built-in command; use 'git flow start %s <name>' to start its branches\n", branchType, branchType)
+			continue
+		}
 		registerBranchCommand(branchType)
 	}
 }
@@ -46,7 +74,7 @@ func RegisterTopicBranchCommands() {
 // registerDefaultBranchCommands registers commands for standard branch types
 func registerDefaultBranchCommands() {
 	// Standard branch types
-	branchTypes := []string{"feature", "release", "hotfix", "support"}
+	branchTypes := []string{"feature", "bugfix", "release", "hotfix", "support"}
 
 	// Register commands for each branch type
 	for _, branchType := range branchTypes {
@@ -58,9 +86,10 @@ func registerDefaultBranchCommands() {
 func registerBranchCommand(branchType string) {
 	// Create command for this branch type
 	branchCmd := &cobra.Command{
-		Use:   branchType,
-		Short: fmt.Sprintf("Manage %s branches", branchType),
-		Long:  fmt.Sprintf("Manage %s branches according to git-flow model", branchType),
+		Use:         branchType,
+		Short:       fmt.Sprintf("Manage %s branches", branchType),
+		Long:        fmt.Sprintf("Manage %s branches according to git-flow model", branchType),
+		Annotations: map[string]string{topicTypeAnnotation: branchType},
 		Run: func(cmd *cobra.Command, args []string) {
 			// If no subcommand is provided, print help
 			cmd.Help()
@@ -95,24 +124,37 @@ func registerBranchCommand(branchType string) {
 				base = args[1]
 			}
 
+			describe, _ := cmd.Flags().GetBool("edit")
+			line, _ := cmd.Flags().GetString("line")
+
 			// Call the generic start command with the branch type, name, base, and fetch flags
-			StartCommand(branchType, args[0], base, shouldFetch)
+			StartCommand(loadContextOrExit(), branchType, args[0], base, line, shouldFetch, getBoolPtr(cmd, "from-remote", "no-from-remote"), getBoolPtr(cmd, "publish", "no-publish"), describe, getBoolPtr(cmd, "track-upstream", "no-track-upstream"))
 		},
 	}
 
 	// Add fetch-related flags
 	startCmd.Flags().Bool("fetch", false, "Fetch from remote before creating branch")
 	startCmd.Flags().Bool("no-fetch", false, "Don't fetch from remote before creating branch")
+	startCmd.Flags().Bool("from-remote", false, "Create the branch from the remote branch of its base, fast-forwarding the local base if possible")
+	startCmd.Flags().Bool("no-from-remote", false, "Create the branch from the local base branch")
+	startCmd.Flags().Bool("publish", false, "Push the new branch to the remote and set up tracking")
+	startCmd.Flags().Bool("no-publish", false, "Don't publish the new branch")
+	startCmd.Flags().Bool("track-upstream", false, "Track the remote branch of the same name when it exists or is published")
+	startCmd.Flags().Bool("no-track-upstream", false, "Don't set up upstream tracking for the new branch")
+	startCmd.Flags().BoolP("edit", "e", false, "Write a description for the new branch in the editor")
+	if branchType == "release" || branchType == "hotfix" {
+		startCmd.Flags().String("line", "", "Start from the base branch of a version line, such as 1.x (see gitflow.line.<line>.base)")
+	}
 
 	branchCmd.AddCommand(startCmd)
 
 	// Add finish subcommand
 	finishCmd := &cobra.Command{
-		Use:     "finish [name]",
+		Use:     "finish [name] | --batch <name>...",
 		Short:   fmt.Sprintf("Finish a %s branch", branchType),
-		Long:    fmt.Sprintf("Finish a %s branch by merging it into the appropriate base branch. If no name is provided, finishes the current branch.", branchType),
-		Example: fmt.Sprintf("  git flow %s finish\n  git flow %s finish my-feature\n  git flow %s finish other/branch -f", branchType, branchType, branchType),
-		Args:    cobra.MaximumNArgs(1),
+		Long:    fmt.Sprintf("Finish a %s branch by merging it into the appropriate base branch. If no name is provided, finishes the current branch. With --batch, finishes the given branches one after the other and updates the child base branches once, after the last.", branchType),
+		Example: fmt.Sprintf("  git flow %s finish\n  git flow %s finish my-feature\n  git flow %s finish other/branch -f\n  git flow %s finish --batch one two", branchType, branchType, branchType, branchType),
+		Args:    finishArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Get flags
 			continueOp, _ := cmd.Flags().GetBool("continue")
@@ -146,6 +188,7 @@ func registerBranchCommand(branchType string) {
 			noPreserveMerges, _ := cmd.Flags().GetBool("no-preserve-merges")
 			noFF, _ := cmd.Flags().GetBool("no-ff")
 			ff, _ := cmd.Flags().GetBool("ff")
+			ffOnly, _ := cmd.Flags().GetBool("ff-only")
 			squash, _ := cmd.Flags().GetBool("squash")
 			noSquash, _ := cmd.Flags().GetBool("no-squash")
 			squashMessage, _ := cmd.Flags().GetString("squash-message")
@@ -154,27 +197,43 @@ func registerBranchCommand(branchType string) {
 			fetch, _ := cmd.Flags().GetBool("fetch")
 			noFetch, _ := cmd.Flags().GetBool("no-fetch")
 
+			// Get push flags
+			push, _ := cmd.Flags().GetBool("push")
+			noPush, _ := cmd.Flags().GetBool("no-push")
+
+			// Get extra tag flags
+			extraTags, _ := cmd.Flags().GetStringArray("extra-tag")
+			noExtraTags, _ := cmd.Flags().GetBool("no-extra-tags")
+			retag, _ := cmd.Flags().GetBool("retag")
+			skipTag, _ := cmd.Flags().GetBool("skip-tag")
+
 			// Get hook bypass flag
 			noVerify, _ := cmd.Flags().GetBool("no-verify")
+			noVerifyChildren, _ := cmd.Flags().GetBool("no-verify-children")
+
+			// Get explicit target branch
+			to, _ := cmd.Flags().GetString("to")
+
+			cfgCtx := loadContextOrExit()
 
 			// Determine branch name - use provided arg or detect from current branch
 			var name string
 			if len(args) > 0 {
 				name = args[0]
+			} else if (continueOp || abortOp) && mergestate.IsMergeInProgress() {
+				// The interrupted finish knows its branch; a rebase may have detached HEAD
 			} else {
 				// No name provided, try to detect from current branch
-				currentBranch, err := git.GetCurrentBranch()
+				currentBranch, err := commands.CurrentBranchFor(newDeps(), fmt.Sprintf("git flow %s finish <name>", branchType))
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(int(errors.ExitCodeGitError))
+					exitCode := errors.ExitCodeGitError
+					if flowErr, ok := err.(errors.Error); ok {
+						exitCode = flowErr.ExitCode()
+					}
+					printError(err)
+					os.Exit(int(exitCode))
 				}
-				// Load config to get prefix
-				cfg, err := config.LoadConfig()
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(int(errors.ExitCodeGitError))
-				}
-				branchConfig, ok := cfg.Branches[branchType]
+				branchConfig, ok := cfgCtx.Config.Branches[branchType]
 				if !ok {
 					fmt.Fprintf(os.Stderr, "Error: invalid branch type '%s'\n", branchType)
 					os.Exit(int(errors.ExitCodeInvalidInput))
@@ -196,6 +255,10 @@ func registerBranchCommand(branchType string) {
 				Message:     message,
 				MessageFile: messageFile,
 				TagName:     tagName,
+				ExtraTags:   extraTags,
+				NoExtraTags: noExtraTags,
+				Retag:       retag,
+				SkipTag:     skipTag,
 			}
 
 			// Create branch retention options
@@ -209,20 +272,35 @@ func registerBranchCommand(branchType string) {
 			// Get merge message flags
 			mergeMessage, _ := cmd.Flags().GetString("merge-message")
 			updateMessage, _ := cmd.Flags().GetString("update-message")
+			childStrategy, _ := cmd.Flags().GetStringArray("child-strategy")
 
 			// Create merge strategy options
 			mergeOptions := &config.MergeStrategyOptions{
 				Rebase:         getBoolFlag(rebase, noRebase),
 				PreserveMerges: getBoolFlag(preserveMerges, noPreserveMerges),
 				NoFF:           getBoolFlag(noFF, ff),
+				FFOnly:         getBoolFlag(ffOnly, false),
 				Squash:         getBoolFlag(squash, noSquash),
 				SquashMessage:  getStringPtr(squashMessage),
 				MergeMessage:   getStringPtr(mergeMessage),
 				UpdateMessage:  getStringPtr(updateMessage),
+				ChildStrategy:  childStrategy,
+			}
+			mergeOptions.NoUpdate, _ = cmd.Flags().GetStringArray("no-update")
+			mergeOptions.Edit, _ = cmd.Flags().GetBool("edit")
+			mergeOptions.IfMerged, _ = cmd.Flags().GetBool("if-merged")
+			mergeOptions.Trailers, _ = cmd.Flags().GetStringArray("trailer")
+			mergeOptions.AutostashUntracked = getBoolPtr(cmd, "autostash-untracked", "no-autostash-untracked")
+			mergeOptions.Summary = getBoolPtr(cmd, "summary", "no-summary")
+			mergeOptions.Backport, _ = cmd.Flags().GetStringArray("backport")
+			mergeOptions.NoBackport, _ = cmd.Flags().GetBool("no-backport")
+			mergeOptions.BackportPR = getBoolPtr(cmd, "backport-pr", "no-backport-pr")
+			if batch, _ := cmd.Flags().GetBool("batch"); batch {
+				mergeOptions.Batch = args[1:]
 			}
 
 			// Call the generic finish command with the branch type and name
-			FinishCommand(branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, mergeOptions, getBoolFlag(fetch, noFetch), getSingleBoolPtr(noVerify))
+			FinishCommand(cfgCtx, branchType, name, continueOp, abortOp, force, tagOptions, retentionOptions, mergeOptions, getBoolFlag(fetch, noFetch), getBoolFlag(push, noPush), getSingleBoolPtr(noVerify), getSingleBoolPtr(noVerifyChildren), to)
 		},
 	}
 
@@ -231,16 +309,21 @@ func registerBranchCommand(branchType string) {
 
 	// Add list subcommand
 	listCmd := &cobra.Command{
-		Use:     "list",
-		Short:   fmt.Sprintf("List all %s branches", branchType),
-		Long:    fmt.Sprintf("List all %s branches in the repository", branchType),
-		Example: fmt.Sprintf("  git flow %s list", branchType),
-		Args:    cobra.NoArgs,
+		Use:         "list",
+		Short:       fmt.Sprintf("List all %s branches", branchType),
+		Long:        fmt.Sprintf("List all %s branches in the repository", branchType),
+		Example:     fmt.Sprintf("  git flow %s list\n  git flow %s list --remote", branchType, branchType),
+		Args:        cobra.NoArgs,
+		Annotations: dataOutputAnnotations,
 		Run: func(cmd *cobra.Command, args []string) {
+			remote, _ := cmd.Flags().GetBool("remote")
+			bestEffort, _ := cmd.Flags().GetBool("best-effort")
 			// Call the generic list command with the branch type
-			ListCommand(branchType)
+			ListCommand(loadContextOrExit(), branchType, remote, bestEffort)
 		},
 	}
+	listCmd.Flags().BoolP("remote", "r", false, "Also list the branches on the remote as of the last fetch")
+	listCmd.Flags().Bool("best-effort", false, "Infer branch prefixes from the branch names if git-flow is not initialized")
 	branchCmd.AddCommand(listCmd)
 
 	// Add update subcommand
@@ -255,19 +338,21 @@ func registerBranchCommand(branchType string) {
 			if len(args) > 0 {
 				name = args[0]
 			}
-			if err := executeUpdate(branchType, name, false); err != nil {
+			noVerify, _ := cmd.Flags().GetBool("no-verify")
+			if err := executeUpdate(loadContextOrExit(), branchType, name, false, getSingleBoolPtr(noVerify)); err != nil {
 				var exitCode errors.ExitCode
 				if flowErr, ok := err.(errors.Error); ok {
 					exitCode = flowErr.ExitCode()
 				} else {
 					exitCode = errors.ExitCodeGitError
 				}
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				printError(err)
 				os.Exit(int(exitCode))
 			}
 			return nil
 		},
 	}
+	updateCmd.Flags().Bool("no-verify", false, "Bypass the commit and pre-rebase hooks while updating")
 	branchCmd.AddCommand(updateCmd)
 
 	// Add delete subcommand
@@ -301,7 +386,7 @@ func registerBranchCommand(branchType string) {
 				remotePtr = &falseBool
 			}
 
-			DeleteCommand(branchType, args[0], forcePtr, remotePtr)
+			DeleteCommand(loadContextOrExit(), branchType, args[0], forcePtr, remotePtr)
 			return nil
 		},
 	}
@@ -322,7 +407,7 @@ func registerBranchCommand(branchType string) {
 		Example: fmt.Sprintf("  git flow %s rename old-feature new-feature", branchType),
 		Args:    cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			RenameCommand(branchType, args[0], args[1])
+			RenameCommand(loadContextOrExit(), branchType, args[0], args[1])
 			return nil
 		},
 	}
@@ -342,7 +427,7 @@ func registerBranchCommand(branchType string) {
 				nameOrPrefix = args[0]
 			}
 			showCommands, _ := cmd.Flags().GetBool("showcommands")
-			CheckoutCommand(branchType, nameOrPrefix, showCommands)
+			CheckoutCommand(loadContextOrExit(), branchType, nameOrPrefix, showCommands)
 			return nil
 		},
 	}
@@ -372,11 +457,21 @@ If no name is provided, the current branch is published.`, branchType, branchTyp
 			}
 			pushOptions, _ := cmd.Flags().GetStringArray("push-option")
 			noPushOption, _ := cmd.Flags().GetBool("no-push-option")
-			PublishCommand(branchType, name, pushOptions, noPushOption)
+			forceWithLease, _ := cmd.Flags().GetBool("force-with-lease")
+			trackInstead, _ := cmd.Flags().GetBool("track-instead")
+			openPR, _ := cmd.Flags().GetBool("pr")
+			draft, _ := cmd.Flags().GetBool("draft")
+			PublishCommand(loadContextOrExit(), branchType, name, pushOptions, noPushOption, forceWithLease, trackInstead, openPR, draft, getBoolPtr(cmd, "track-upstream", "no-track-upstream"))
 		},
 	}
 	publishCmd.Flags().StringArrayP("push-option", "o", nil, "Push option to transmit to the server (repeatable)")
 	publishCmd.Flags().Bool("no-push-option", false, "Don't send any push options (overrides config defaults)")
+	publishCmd.Flags().Bool("force-with-lease", false, "Overwrite an existing remote branch unless it changed since the last fetch")
+	publishCmd.Flags().Bool("track-instead", false, "Track an existing remote branch instead of pushing")
+	publishCmd.Flags().Bool("pr", false, "Open a pull request against the parent branch after publishing")
+	publishCmd.Flags().Bool("draft", false, "Open the pull request as a draft (implies --pr)")
+	publishCmd.Flags().Bool("track-upstream", false, "Make the local branch track the published branch")
+	publishCmd.Flags().Bool("no-track-upstream", false, "Push without setting up upstream tracking")
 	branchCmd.AddCommand(publishCmd)
 
 	// Add track subcommand
@@ -390,19 +485,78 @@ started by someone else.`, branchType, branchType),
 		Example: fmt.Sprintf("  git flow %s track my-feature", branchType),
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			TrackCommand(branchType, args[0])
+			TrackCommand(loadContextOrExit(), branchType, args[0], getBoolPtr(cmd, "track-upstream", "no-track-upstream"))
 		},
 	}
+	trackCmd.Flags().Bool("track-upstream", false, "Make the local branch track the remote branch")
+	trackCmd.Flags().Bool("no-track-upstream", false, "Create the local branch without upstream tracking")
 
 	branchCmd.AddCommand(trackCmd)
 
+	// Add compare subcommand
+	compareCmd := &cobra.Command{
+		Use:     "compare [name]",
+		Aliases: []string{"browse"},
+		Short:   fmt.Sprintf("Open the compare page of a %s branch", branchType),
+		Long: fmt.Sprintf(`Opens the web page comparing a %s branch with its parent on GitHub,
+GitLab or Bitbucket. The hosting service and repository are derived from
+the URL of the configured remote.
+
+Use --print to write the URL to standard output instead of opening it.
+If no name is provided, the current branch is compared.`, branchType),
+		Example: fmt.Sprintf("  git flow %s compare my-feature\n  git flow %s browse --print", branchType, branchType),
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			printOnly, _ := cmd.Flags().GetBool("print")
+			CompareCommand(loadContextOrExit(), branchType, name, printOnly)
+		},
+	}
+	compareCmd.Flags().Bool("print", false, "Print the compare URL instead of opening it")
+	branchCmd.AddCommand(compareCmd)
+
+	// Add rc subcommand to the branch types that are released with a tag
+	if branchType == "release" || branchType == "hotfix" {
+		rcCmd := &cobra.Command{
+			Use:   "rc [name]",
+			Short: fmt.Sprintf("Tag the head of a %s branch as a release candidate", branchType),
+			Long: fmt.Sprintf(`Tags the head of a %s branch as its next release candidate, named
+after the tag finish will create: v1.2.0-rc.1, then v1.2.0-rc.2 and so on.
+The candidates are recorded, and finish lists them in the message of the
+release tag.
+
+If no name is provided, the current branch is tagged.`, branchType),
+			Example: fmt.Sprintf("  git flow %s rc 1.2.0\n  git flow %s rc --push", branchType, branchType),
+			Args:    cobra.MaximumNArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				name := ""
+				if len(args) > 0 {
+					name = args[0]
+				}
+				RCCommand(loadContextOrExit(), branchType, name, getBoolPtr(cmd, "push", "no-push"))
+			},
+		}
+		rcCmd.Flags().Bool("push", false, "Push the release candidate tag to the remote")
+		rcCmd.Flags().Bool("no-push", false, "Don't push the release candidate tag")
+		branchCmd.AddCommand(rcCmd)
+	}
+
 	// Add the branch command to the root command
 	rootCmd.AddCommand(branchCmd)
 }
 
-func init() {
-	// Register topic branch commands
-	RegisterTopicBranchCommands()
+// finishArgs accepts one optional branch name, or two or more with --batch
+func finishArgs(cmd *cobra.Command, args []string) error {
+	if batch, _ := cmd.Flags().GetBool("batch"); batch {
+		if len(args) < 2 {
+			return fmt.Errorf("--batch needs at least two branches, got %d", len(args))
+		}
+		return nil
+	}
+	return cobra.MaximumNArgs(1)(cmd, args)
 }
 
 // addFinishFlags adds common finish flags to the given Cobra command
@@ -421,6 +575,10 @@ func addFinishFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("message", "m", "", "Use the given message for the tag")
 	cmd.Flags().String("messagefile", "", "Use contents of the given file as tag message")
 	cmd.Flags().StringP("tagname", "T", "", "Use the given tag name instead of the default")
+	cmd.Flags().StringArray("extra-tag", nil, "Also point the given tag at a branch, as <name>[:<branch>] (can be used multiple times)")
+	cmd.Flags().Bool("no-extra-tags", false, "Don't create the configured extra tags")
+	cmd.Flags().Bool("retag", false, "Move the tag to the finished commit if it already exists")
+	cmd.Flags().Bool("skip-tag", false, "Keep the tag if it already exists and don't create one")
 
 	// Branch Retention Flags
 	cmd.Flags().BoolP("keep", "k", false, "Keep the branch after finishing")
@@ -439,18 +597,41 @@ func addFinishFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("no-preserve-merges", false, "Flatten merges during rebase")
 	cmd.Flags().Bool("no-ff", false, "Create merge commit even for fast-forward")
 	cmd.Flags().Bool("ff", false, "Allow fast-forward merge when possible")
+	cmd.Flags().Bool("ff-only", false, "Refuse to finish unless the merge is a fast-forward")
 	cmd.Flags().BoolP("squash", "S", false, "Squash all commits into single commit")
 	cmd.Flags().Bool("no-squash", false, "Keep individual commits (don't squash)")
 	cmd.Flags().String("squash-message", "", "Custom commit message for squash merge")
 	cmd.Flags().StringP("merge-message", "M", "", "Custom commit message for the upstream merge operation")
 	cmd.Flags().String("update-message", "", "Custom commit message for child branch update operations")
+	cmd.Flags().Bool("if-merged", false, "Skip the merge without asking when the branch is already merged into its target")
+	cmd.Flags().BoolP("edit", "e", false, "Edit the merge, squash and tag messages in the editor before finishing")
+	cmd.Flags().StringArray("child-strategy", nil, "Update a child base branch with another strategy, as <branch>=<merge|rebase|squash> (can be used multiple times)")
+	cmd.Flags().StringArray("no-update", nil, "Don't update the given auto-update child base branch this time (can be used multiple times)")
+	cmd.Flags().StringArray("trailer", nil, "Add a trailer such as 'Reviewed-by: Name <email>' to the merge or squash commit (can be used multiple times)")
+	cmd.Flags().Bool("batch", false, "Finish all given branches one after the other, updating the child base branches only after the last")
+	cmd.Flags().Bool("summary", false, "Show the commits, files and lines the merge brings into the target branch before finishing")
+	cmd.Flags().Bool("no-summary", false, "Don't show the summary before finishing")
+	cmd.Flags().StringArray("backport", nil, "Backport the branch to a maintenance branch once finished, replacing the configured ones (can be used multiple times)")
+	cmd.Flags().Bool("no-backport", false, "Don't backport the branch to any maintenance branch")
+	cmd.Flags().Bool("backport-pr", false, "Push each backport branch and open a pull request against its maintenance branch")
+	cmd.Flags().Bool("no-backport-pr", false, "Only create the backport branches locally")
 
 	// Fetch Flags
 	cmd.Flags().Bool("fetch", false, "Fetch from remote before finishing")
 	cmd.Flags().Bool("no-fetch", false, "Don't fetch from remote before finishing")
 
+	// Push Flags
+	cmd.Flags().Bool("push", false, "Push the updated branches and tags after finishing")
+	cmd.Flags().Bool("no-push", false, "Don't push after finishing")
+
 	// Hook Control Flags
 	cmd.Flags().Bool("no-verify", false, "Bypass pre-commit and commit-msg hooks during merge and commit operations")
+	cmd.Flags().Bool("no-verify-children", false, "Also bypass the hooks when updating child base branches")
+	cmd.Flags().Bool("autostash-untracked", false, "Stash untracked files that checking out a child base branch would overwrite, and restore them afterwards")
+	cmd.Flags().Bool("no-autostash-untracked", false, "Don't stash untracked files for the child updates")
+
+	// Target Flags
+	cmd.Flags().String("to", "", "Finish into the given branch instead of the configured parent or stored base")
 }
 
 // getBoolFlag converts two opposite boolean flags into a single *bool value
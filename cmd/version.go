@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
 
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/git"
 	"github.com/spf13/cobra"
 )
 
@@ -13,21 +17,170 @@ var (
 	GitCommit = "unknown"
 )
 
+// gitFeature is an optional Git capability git-flow-next can use
+type gitFeature struct {
+	name         string
+	major, minor int // Oldest Git version providing the feature
+	description  string
+}
+
+// gitFeatures are reported by the version command
+var gitFeatures = []gitFeature{
+	{"merge-tree", 2, 38, "conflict checks without touching the working tree (git merge-tree --write-tree)"},
+	{"worktrees", 2, 5, "running git-flow in linked worktrees (git worktree)"},
+	{"ssh-signing", 2, 34, "SSH signatures for tags and base branch verification (gpg.format ssh)"},
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Long:  `Display version information for git-flow-next.`,
+	Long: `Display version information for git-flow-next, followed by a report of the
+environment for bug reports: the Git version, which optional Git features are
+available, the configuration schema of the current repository, and whether
+git-flow-avh configuration was detected.
+
+With --features, only the Git features and the experimental features are
+printed, with whether the repository enabled them. Experimental features are
+new behaviors a repository opts into with gitflow.experimental.<name>=true.`,
+	Annotations: dataOutputAnnotations,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("git-flow-next version %s\n", Version)
-		if BuildDate != "unknown" {
-			fmt.Printf("Build date: %s\n", BuildDate)
-		}
-		if GitCommit != "unknown" {
-			fmt.Printf("Git commit: %s\n", GitCommit)
+		short, _ := cmd.Flags().GetBool("short")
+		features, _ := cmd.Flags().GetBool("features")
+		if features {
+			VersionFeaturesCommand()
+			return
 		}
+		VersionCommand(short)
 	},
 }
 
+// VersionCommand is the implementation of the version command
+func VersionCommand(short bool) {
+	fmt.Printf("git-flow-next version %s\n", Version)
+	if short {
+		return
+	}
+	if BuildDate != "unknown" {
+		fmt.Printf("Build date: %s\n", BuildDate)
+	}
+	if GitCommit != "unknown" {
+		fmt.Printf("Git commit: %s\n", GitCommit)
+	}
+
+	fmt.Println()
+	fmt.Printf("Platform:        %s/%s (%s)\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	gitVersion, err := git.GetGitVersion()
+	if err != nil {
+		fmt.Printf("Git version:     unknown (%v)\n", err)
+	} else {
+		fmt.Printf("Git version:     %s\n", gitVersion)
+	}
+
+	fmt.Println()
+	fmt.Println("Features:")
+	printGitFeatures(gitVersion, err)
+
+	fmt.Println()
+	fmt.Println("Repository:")
+	printRepositoryReport()
+}
+
+// VersionFeaturesCommand is the implementation of 'git flow version --features'
+func VersionFeaturesCommand() {
+	gitVersion, err := git.GetGitVersion()
+	fmt.Println("Features:")
+	printGitFeatures(gitVersion, err)
+
+	fmt.Println()
+	fmt.Println("Experimental features:")
+	cfg := experimentalConfig()
+	for _, feature := range config.ExperimentalFeatures {
+		status := "disabled"
+		if cfg.ExperimentalEnabled(feature.Name) {
+			status = "enabled"
+		}
+		fmt.Printf("  %-16s %-8s - %s\n", feature.Name, status, feature.Description)
+	}
+	for _, key := range cfg.UnknownExperimentalKeys() {
+		fmt.Printf("  Warning: %s names no experimental feature of this version and is ignored\n", key)
+	}
+	fmt.Printf("\nEnable one in this repository with 'git config %s true'\n", config.ExperimentalKey("<name>"))
+}
+
+// experimentalConfig returns the configuration of the repository in the
+// current directory, which enables no experimental feature when it cannot be
+// loaded
+func experimentalConfig() *config.Config {
+	cfgCtx, err := loadContext()
+	if err != nil {
+		return &config.Config{}
+	}
+	return cfgCtx.Config
+}
+
+// printGitFeatures reports which optional Git features the installed Git
+// provides; err is the error determining its version
+func printGitFeatures(gitVersion git.GitVersion, err error) {
+	for _, feature := range gitFeatures {
+		status := "available"
+		if err != nil {
+			status = "unknown"
+		} else if !gitVersion.AtLeast(feature.major, feature.minor) {
+			status = fmt.Sprintf("unavailable (requires Git %d.%d)", feature.major, feature.minor)
+		}
+		fmt.Printf("  %-14s %s - %s\n", feature.name, status, feature.description)
+	}
+}
+
+// printRepositoryReport prints the git-flow state of the repository in the
+// current directory
+func printRepositoryReport() {
+	if _, err := git.GetGitDir(); err != nil {
+		fmt.Println("  Not in a Git repository")
+		return
+	}
+
+	status, err := config.IsGitFlowNextInitializedWithScope(git.ConfigScopeDefault, "")
+	schema, schemaErr := git.GetConfig(config.KeyVersion)
+	switch {
+	case err != nil || !status.Initialized || schemaErr != nil:
+		fmt.Println("  Config schema:  not initialized")
+	case schema == config.SchemaVersion:
+		fmt.Printf("  Config schema:  %s (%s config)\n", schema, status.SourceScope)
+	default:
+		fmt.Printf("  Config schema:  %s (%s config; this version uses %s)\n", schema, status.SourceScope, config.SchemaVersion)
+	}
+
+	if keys := config.FindGitFlowAVHKeys(); len(keys) > 0 {
+		fmt.Printf("  AVH config:     detected (%s)\n", strings.Join(keys, ", "))
+	} else {
+		fmt.Println("  AVH config:     not detected")
+	}
+
+	var enabled []string
+	cfg := experimentalConfig()
+	for _, feature := range config.ExperimentalFeatures {
+		if cfg.ExperimentalEnabled(feature.Name) {
+			enabled = append(enabled, feature.Name)
+		}
+	}
+	if len(enabled) > 0 {
+		fmt.Printf("  Experimental:   %s\n", strings.Join(enabled, ", "))
+	} else {
+		fmt.Println("  Experimental:   none enabled")
+	}
+
+	if linked, err := git.IsLinkedWorktree(); err == nil {
+		if linked {
+			fmt.Println("  Working tree:   linked worktree")
+		} else {
+			fmt.Println("  Working tree:   main working tree")
+		}
+	}
+}
+
 func init() {
+	versionCmd.Flags().Bool("short", false, "Only print the git-flow-next version")
+	versionCmd.Flags().Bool("features", false, "Only print the Git features and the experimental features of the repository")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/dashboard"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/interrupt"
+	"github.com/spf13/cobra"
+)
+
+// webCmd represents the web command
+var webCmd = &cobra.Command{
+	Use:   "web",
+	Short: "Serve a local dashboard of the branches",
+	Long: `Serve a small web page on this computer that shows the base branch
+hierarchy, the open topic branches with the commits they are ahead of and
+behind their base, and the operations waiting to be continued, the same data
+as 'git flow overview' and 'git flow state show'. The page reloads itself
+every few seconds. The server listens on 127.0.0.1 only and stops with Ctrl-C.
+
+Examples:
+  git-flow web
+  git-flow web --port 8080 --open`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		port, _ := cmd.Flags().GetInt("port")
+		open, _ := cmd.Flags().GetBool("open")
+		bestEffort, _ := cmd.Flags().GetBool("best-effort")
+		WebCommand(loadContextOrExit(), port, open, bestEffort)
+	},
+}
+
+// WebCommand is the implementation of the web command
+func WebCommand(cfgCtx *config.Context, port int, open bool, bestEffort bool) {
+	if err := executeWeb(cfgCtx, port, open, bestEffort); err != nil {
+		var exitCode errors.ExitCode
+		if flowErr, ok := err.(errors.Error); ok {
+			exitCode = flowErr.ExitCode()
+		} else {
+			exitCode = errors.ExitCodeGitError
+		}
+		printError(err)
+		os.Exit(int(exitCode))
+	}
+}
+
+// executeWeb serves the dashboard until the command is interrupted. The
+// configuration is read once; each page view collects the branches anew, from
+// Git directly rather than through the command cache.
+func executeWeb(cfgCtx *config.Context, port int, open bool, bestEffort bool) error {
+	if port < 0 || port > 65535 {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("invalid port %d", port)}
+	}
+	cfg, err := readOnlyConfig(cfgCtx, bestEffort)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("listen on port %d", port), Err: err}
+	}
+	server := &http.Server{
+		Handler: dashboard.Handler(func() (*dashboard.Snapshot, error) {
+			return dashboard.Collect(cfg)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	url := fmt.Sprintf("http://%s/", listener.Addr())
+	fmt.Printf("Serving the dashboard at %s (press Ctrl-C to stop)\n", url)
+	if open {
+		if err := forge.OpenBrowser(url); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not open the browser: %v\n", err)
+		}
+	}
+
+	ctx, stop := interrupt.NotifyContext(context.Background())
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return &errors.GitError{Operation: "serve the dashboard", Err: err}
+	}
+	fmt.Println("Stopped the dashboard")
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(webCmd)
+
+	webCmd.Flags().Int("port", 0, "Port to listen on (default: any free port)")
+	webCmd.Flags().Bool("open", false, "Open the dashboard in the browser")
+	webCmd.Flags().Bool("best-effort", false, "Infer branch names and prefixes from the branches if git-flow is not initialized")
+}
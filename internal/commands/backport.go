@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// checkBackports verifies the backport targets of a finish before anything is
+// changed: each must be an existing branch other than the branch and its
+// target, and pull requests must be possible when they are requested. It
+// returns the commits to backport, oldest first.
+func checkBackports(deps *Deps, cfg *config.Config, targets []string, openPR bool, branch string, target string) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	for _, backport := range targets {
+		if backport == branch || backport == target {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("cannot backport '%s' to '%s', the branch finish merges it into", branch, backport)}
+		}
+		if err := deps.Git.BranchExists(backport); err != nil {
+			return nil, &errors.BranchNotFoundError{BranchName: backport}
+		}
+	}
+	if openPR {
+		repo, err := ForgeRepository(deps, cfg, cfg.Remote, "open backport pull requests")
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.CheckPullRequests(); err != nil {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("cannot open backport pull requests: %v (use --no-backport-pr to only create the backport branches)", err)}
+		}
+	}
+
+	commits, err := deps.Git.BackportCommits(target, branch)
+	if err != nil {
+		return nil, &errors.GitError{Operation: "list the commits to backport", Err: err}
+	}
+	return commits, nil
+}
+
+// backportBranchName returns the branch holding the backport of the finished
+// branch name to target, e.g. backport/support/1.x/1.4.3
+func backportBranchName(target, name string) string {
+	return "backport/" + target + "/" + name
+}
+
+// backportFinished cherry-picks the commits of the finished branch onto each
+// backport target of the finish, on a branch of its own, opens a pull request
+// for each when requested, and prints a summary. The finish is complete at this
+// point, so a backport that fails is reported and skipped.
+func backportFinished(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState) {
+	if len(state.Backports) == 0 {
+		return
+	}
+	if len(state.BackportCommits) == 0 {
+		fmt.Fprintf(deps.Out, "Nothing to backport: '%s' brought no commits of its own\n", state.FullBranchName)
+		return
+	}
+
+	var repo *forge.Repository
+	if state.BackportPR {
+		repo, _ = ForgeRepository(deps, cfg, cfg.Remote, "open backport pull requests")
+	}
+
+	var summary strings.Builder
+	for _, target := range state.Backports {
+		result, err := backportTo(ctx, deps, cfg, repo, state, target)
+		if err != nil {
+			fmt.Fprintf(&summary, "  ✗ %s: %v\n", target, err)
+			continue
+		}
+		fmt.Fprintf(&summary, "  ✓ %s: %s\n", target, result)
+	}
+	fmt.Fprintf(deps.Out, "Backports:\n%s", summary.String())
+}
+
+// backportTo backports the finished branch to target and returns what was
+// done, such as the backport branch and the URL of its pull request
+func backportTo(ctx context.Context, deps *Deps, cfg *config.Config, repo *forge.Repository, state *mergestate.MergeState, target string) (string, error) {
+	branch := backportBranchName(target, state.BranchName)
+	if deps.Git.BranchExists(branch) == nil {
+		return "", fmt.Errorf("'%s' already exists", branch)
+	}
+
+	commits, err := deps.Git.UnappliedCommits(target, state.BackportCommits)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "already contains the changes", nil
+	}
+
+	fmt.Fprintf(deps.Out, "Backporting %d commit(s) to '%s'...\n", len(commits), target)
+	if err := deps.Git.CherryPickOnto(ctx, branch, target, commits); err != nil {
+		conflict, ok := err.(*errors.CherryPickConflictError)
+		if !ok {
+			return "", err
+		}
+		shortCommits := make([]string, len(commits))
+		for i, commit := range commits {
+			shortCommits[i] = git.ShortCommit(commit)
+		}
+		return "", fmt.Errorf("%s conflicts in %s; backport it manually with 'git checkout -b %s %s && git cherry-pick -x %s'",
+			git.ShortCommit(conflict.Commit), strings.Join(conflict.Files, ", "), branch, target, strings.Join(shortCommits, " "))
+	}
+	result := fmt.Sprintf("%s (%d commit(s))", branch, len(commits))
+	if repo == nil {
+		return result, nil
+	}
+
+	if err := deps.Remote.PushRefs(ctx, cfg.Remote, []string{"refs/heads/" + branch}, nil); err != nil {
+		return "", fmt.Errorf("created %s, but could not push it: %v", result, err)
+	}
+	subject := state.FullBranchName
+	if state.TagName != "" {
+		subject = state.TagName
+	}
+	subjects, _ := deps.Git.CommitSubjects(target, branch)
+	pr := forge.PullRequest{
+		Base:  target,
+		Head:  branch,
+		Title: fmt.Sprintf("Backport %s to %s", subject, target),
+		Body:  fmt.Sprintf("Backport of '%s' to '%s'.\n\nCommits:\n- %s", state.FullBranchName, target, strings.Join(subjects, "\n- ")),
+	}
+	url, err := repo.CreatePullRequest(pr)
+	if err != nil {
+		return "", fmt.Errorf("created and pushed %s, but could not open a pull request: %v", result, err)
+	}
+	return result + ", " + url, nil
+}
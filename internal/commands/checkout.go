@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// Checkout checks out the topic branch of branchType named, or uniquely
+// prefixed by, nameOrPrefix. Without nameOrPrefix it lists the branches of
+// the type.
+func Checkout(deps *Deps, cfg *config.Config, branchType string, nameOrPrefix string, showCommands bool) error {
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// If no name/prefix provided, list available branches and return
+	if nameOrPrefix == "" {
+		branches, err := deps.Git.ListBranches()
+		if err != nil {
+			return &errors.GitError{Operation: "list branches", Err: err}
+		}
+
+		prefix := branchConfig.Prefix
+		found := false
+		fmt.Fprintf(deps.Out, "Available %s branches:\n", branchType)
+		for _, branch := range branches {
+			if strings.HasPrefix(branch, prefix) {
+				found = true
+				fmt.Fprintf(deps.Out, "  %s\n", strings.TrimPrefix(branch, prefix))
+				deps.result("%s", strings.TrimPrefix(branch, prefix))
+			}
+		}
+		if !found {
+			fmt.Fprintf(deps.Out, "No %s branches exist.\n", branchType)
+		}
+		return nil
+	}
+
+	// Accept the short or the full branch name
+	exists := func(branch string) bool { return deps.Git.BranchExists(branch) == nil }
+	fullBranchName, nameOrPrefix, err := config.ResolveTopicName(cfg, branchType, nameOrPrefix, exists)
+	if err != nil {
+		return err
+	}
+
+	// Check if branch exists
+	if err := deps.Git.BranchExists(fullBranchName); err != nil {
+		// If exact match not found, try prefix match
+		branches, err := deps.Git.ListBranches()
+		if err != nil {
+			return &errors.GitError{Operation: "list branches", Err: err}
+		}
+
+		matches := []string{}
+		prefix := branchConfig.Prefix + nameOrPrefix
+		for _, branch := range branches {
+			if strings.HasPrefix(branch, prefix) {
+				matches = append(matches, branch)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return &errors.BranchNotFoundError{BranchName: fullBranchName}
+		case 1:
+			fullBranchName = matches[0]
+		default:
+			return &errors.GitError{Operation: "checkout branch", Err: fmt.Errorf("ambiguous branch name '%s' matches multiple branches:\n  %s", nameOrPrefix, strings.Join(matches, "\n  "))}
+		}
+	}
+
+	// Show git command if requested
+	if showCommands {
+		fmt.Fprintf(deps.Out, "$ git checkout %s\n", fullBranchName)
+	}
+
+	// Checkout the branch
+	if err := deps.Git.Checkout(fullBranchName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("checkout branch '%s'", fullBranchName), Err: err}
+	}
+
+	fmt.Fprintf(deps.Out, "Switched to branch '%s'\n", fullBranchName)
+	deps.result("%s", fullBranchName)
+	return nil
+}
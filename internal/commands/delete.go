@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/hooks"
+)
+
+// Delete deletes the topic branch name of branchType, wrapped in the delete
+// hooks. force and remote override the configured behavior when set.
+// Cancelling ctx stops the deletion of the remote branch.
+func Delete(ctx context.Context, deps *Deps, cfg *config.Config, branchType string, name string, force *bool, remote *bool) error {
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Accept the short or the full branch name
+	exists := func(branch string) bool { return deps.Git.BranchExists(branch) == nil }
+	fullBranchName, name, err := config.ResolveTopicName(cfg, branchType, name, exists)
+	if err != nil {
+		return err
+	}
+
+	// Check if branch exists
+	if err := deps.Git.BranchExists(fullBranchName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: fullBranchName}
+	}
+
+	// Get git directory for hooks
+	gitDir, err := deps.Git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	remoteName := cfg.Remote
+
+	// Build hook context
+	hookCtx := hooks.HookContext{
+		BranchType: branchType,
+		BranchName: name,
+		FullBranch: fullBranchName,
+		BaseBranch: branchConfig.Parent,
+		Origin:     remoteName,
+	}
+	if branchType == "release" || branchType == "hotfix" {
+		hookCtx.Version = name
+	}
+
+	// Run delete operation wrapped with hooks
+	return hooks.WithHooks(gitDir, branchType, hooks.HookActionDelete, hookCtx, func() error {
+		return performDelete(ctx, deps, branchType, fullBranchName, branchConfig, force, remote, remoteName)
+	})
+}
+
+// performDelete performs the actual delete operation (called within hooks wrapper)
+func performDelete(ctx context.Context, deps *Deps, branchType, fullBranchName string, branchConfig config.BranchConfig, force *bool, remote *bool, remoteName string) error {
+	// Check if we're currently on the branch to be deleted
+	currentBranch, err := deps.Git.GetCurrentBranch()
+	if err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+	if currentBranch == fullBranchName {
+		// If we're on the branch to be deleted, try to switch to its parent
+		parentBranch := branchConfig.Parent
+		if parentBranch == "" {
+			return &errors.GitError{Operation: "delete branch", Err: fmt.Errorf("cannot delete the current branch without a parent branch configured")}
+		}
+		if err := deps.Git.Checkout(parentBranch); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("checkout parent branch '%s'", parentBranch), Err: err}
+		}
+	}
+
+	// Command line flags take precedence over the config
+	forceDelete := configuredBool(deps, force, config.CommandKey(branchType, config.CommandDelete, config.OptForce))
+	deleteRemote := configuredBool(deps, remote, config.BranchKey(branchType, config.PropDeleteRemote))
+
+	// Delete the branch with appropriate flag
+	if err := deps.Git.DeleteBranch(fullBranchName, forceDelete); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("delete branch '%s'", fullBranchName), Err: err}
+	}
+	deps.Events.BranchDeleted(events.BranchDeleted{Branch: fullBranchName})
+
+	// Delete remote branch if requested
+	if deleteRemote {
+		if err := deps.Remote.DeleteRemoteBranch(ctx, remoteName, fullBranchName); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s'", fullBranchName), Err: err}
+		}
+		deps.Events.BranchDeleted(events.BranchDeleted{Branch: fullBranchName, Remote: remoteName})
+		fmt.Fprintf(deps.Out, "Deleted branch %s and its remote tracking branch\n", fullBranchName)
+	} else {
+		fmt.Fprintf(deps.Out, "Deleted branch %s\n", fullBranchName)
+	}
+	deps.result("%s", fullBranchName)
+
+	// Clean up base branch configuration
+	configKey := config.BaseKey(fullBranchName)
+	if err := deps.Config.Unset(configKey); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to clean up base config: %v\n", err)
+	}
+
+	return nil
+}
+
+// configuredBool returns flag if it is set, otherwise the boolean value of key
+func configuredBool(deps *Deps, flag *bool, key string) bool {
+	if flag != nil {
+		return *flag
+	}
+	value, err := deps.Config.Get(key)
+	if err != nil {
+		return false
+	}
+	result, _ := config.ParseBool(value)
+	return result
+}
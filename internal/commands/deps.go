@@ -0,0 +1,520 @@
+// Package commands implements the logic of git-flow's commands independent of
+// the command line.
+//
+// Each command takes its collaborators through Deps, so it can run against
+// fakes in unit tests without a compiled binary or a real repository. The cmd
+// package only parses flags, loads the configuration, calls the command with
+// NewDeps and maps the returned error to an exit code.
+//
+// Checkout, delete, rename, start, finish, update, publish, sync, sync-bases,
+// track and state live here and reach the repository only through Deps. The
+// other commands, among them init, config, tag, rc, list and overview, are
+// still implemented in cmd/ against internal/git and move here one by one.
+//
+// A command keeps its state in its arguments: the context it is called with
+// stops its long-running git commands and carries the retry policy of remote
+// operations (see git.WithRetryPolicy), Deps holds its command cache and the
+// writers of its messages, and the configuration context its --remote
+// override. Commands can therefore run concurrently with separate Deps, e.g.
+// in a program that embeds them. Commands that defer SIGINT and SIGTERM
+// (Finish and Update) each watch for them on their own, and all act on the
+// repository of the process's working directory.
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/output"
+)
+
+// Git is the access to the local repository used by the commands: its
+// branches, commits, working tree, tags and what it knows of its remotes. What
+// goes over the network goes through Remote, single config keys through
+// ConfigStore.
+type Git interface {
+	// Repository
+	GetGitDir() (string, error)
+	GetTopLevelDir() (string, error)
+	GetGitVersion() (git.GitVersion, error)
+	ColorEnabled(slot string, stdoutIsTTY bool) bool
+	EditMessage(name, template string) (string, error)
+
+	// Branches and worktrees
+	GetCurrentBranch() (string, error)
+	IsDetachedHead() bool
+	BranchExists(branch string) error
+	BranchOrCommitExists(ref string) error
+	ListBranches() ([]string, error)
+	Checkout(branch string) error
+	CreateBranch(name string, startPoint string) error
+	CreateBranchNoTrack(name string, startPoint string) error
+	CreateTrackingBranch(localBranch, remote, remoteBranch string) error
+	FastForwardBranch(branch, target string) error
+	RenameBranch(oldBranch, newBranch string) error
+	DeleteBranch(branch string, force bool) error
+	GetBaseBranch(branchName string) (string, error)
+	SetBaseBranch(branchName, baseBranch string) error
+	GetBranchDescription(branchName string) string
+	SetBranchDescription(branchName, description string) error
+	ListWorktrees() ([]git.Worktree, error)
+	WorktreeOfBranch(branch string) string
+
+	// Working tree: uncommitted changes, conflicts of a stopped merge and
+	// untracked files
+	HasUncommittedChanges() (bool, error)
+	GetOperationInProgress() (string, error)
+	HasConflicts() bool
+	UnmergedFiles() ([]string, error)
+	ResolveConflictFavoring(path, side string) error
+	UntrackedFilesIn(branch string) ([]string, error)
+	StashUntracked(message string) (string, error)
+	RestoreStash(commit string) error
+
+	// Commits and their history (rev-parse, rev-list, log)
+	BranchCommit(branch string) (string, error)
+	IsAncestor(ancestor, descendant string) bool
+	IsFirstParentAncestor(commit, branch string) bool
+	AheadBehind(branch, other string) (int, int, error)
+	CompareBranches(branch, other string) (git.BranchSyncStatus, int, error)
+	ChangesApplied(branch, target string) bool
+	OnlyRebasedCommits(branch, upstream string) bool
+	BackMerges(branch, parent string) ([]string, error)
+	CommitSubjects(base, branch string) ([]string, error)
+	CommitTrailers(revRange string, key string) ([]string, error)
+	DiffShortStat(base, branch string) (git.DiffStat, error)
+	GetCommitSignature(rev string) (*git.CommitSignature, error)
+	BackportCommits(base, branch string) ([]string, error)
+	UnappliedCommits(target string, commits []string) ([]string, error)
+
+	// Merges, rebases and commits. Those that stop on conflicts leave them in
+	// the working tree and return an error mentioning "conflict". Cancelling
+	// ctx stops the git command, which may run hooks or wait for the editor;
+	// aborting and undoing are not cancelled, so they always leave a
+	// consistent state.
+	Merge(ctx context.Context, branch string, noVerify bool) error
+	MergeWithOptions(ctx context.Context, branchName string, noFF bool, noVerify bool) error
+	MergeWithMessage(ctx context.Context, branchName string, message string, noFF bool, noVerify bool) error
+	MergeFastForwardOnly(ctx context.Context, branchName string) error
+	SquashMerge(ctx context.Context, branch string, noVerify bool) error
+	MergeSquashWithMessage(ctx context.Context, branchName string, message string, noVerify bool) error
+	MergeInMemory(ctx context.Context, branch, source, message string) (merged bool, err error)
+	MergeAbort() error
+	Rebase(ctx context.Context, branch string, noVerify bool) error
+	RebaseWithOptions(ctx context.Context, targetBranch string, preserveMerges bool, noVerify bool) error
+	RebaseContinue(ctx context.Context) error
+	RebaseAbort() error
+	UndoStoppedOperation() error
+	Commit(ctx context.Context, message string, noVerify bool) error
+	CherryPickOnto(ctx context.Context, branch, startPoint string, commits []string) error
+
+	// Tags
+	TagExists(name string) bool
+	TagCommit(name string) (string, error)
+	IsValidTagName(name string) bool
+	CreateTag(tagName string, options *git.TagOptions) error
+	MoveTags(targets map[string]string) error
+	LatestTagExcluding(rev string, exclude string) (string, error)
+	ReleaseCandidates(branchName string) ([]git.ReleaseCandidate, error)
+	ClearReleaseCandidates(branchName string) error
+
+	// Remotes as the local repository knows them: their configuration and
+	// remote-tracking branches, without contacting them
+	RemoteExists(remote string) bool
+	RemoteBranchExists(remote, branch string) bool
+	RemoteBranches(remote string) ([]string, error)
+	GetRemoteURL(remote string) (string, error)
+	GetTrackingBranch(branch string) (string, error)
+	SetUpstream(branch, remote, remoteBranch string) error
+	PushDestination(branch, remote string) (string, error)
+	PushExclusion(remote, ref string) string
+}
+
+// Remote is the access to the remote repositories: everything that talks to
+// the network, and so may fail for reasons the local repository cannot show,
+// such as an unreachable host or a rejected push. Cancelling ctx stops the
+// operation; the retry policy of ctx (see git.WithRetryPolicy) controls how
+// transient failures are retried.
+type Remote interface {
+	// CheckRemoteReachable fails when remote cannot be contacted
+	CheckRemoteReachable(ctx context.Context, remote string) error
+	Fetch(ctx context.Context, remote string) error
+	FetchBranch(ctx context.Context, remote, branch string) error
+	// PushBranch pushes branch to remoteBranch. track sets the pushed branch as
+	// upstream and withLease overwrites it if it is where it was last fetched.
+	PushBranch(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string, track, withLease bool) error
+	// PushRefs pushes refspecs atomically, forcing the leased refspecs with a
+	// lease on their last fetched position
+	PushRefs(ctx context.Context, remote string, refspecs, leased []string) error
+	DeleteRemoteBranch(ctx context.Context, remote, branch string) error
+}
+
+// ConfigStore reads and writes single Git config keys. The parsed git-flow
+// configuration is passed to the commands separately.
+type ConfigStore interface {
+	Get(key string) (string, error)
+	// GetAll returns all values of a multi-valued key
+	GetAll(key string) ([]string, error)
+	Set(key, value string) error
+	Unset(key string) error
+}
+
+// StateStore keeps the state of an operation stopped by conflicts or an
+// interruption, so it can be continued or aborted later
+type StateStore interface {
+	// Load returns the saved state, or nil if there is none
+	Load() (*mergestate.MergeState, error)
+	Save(state *mergestate.MergeState) error
+	Clear() error
+	// InProgress reports whether a state is saved, even one that cannot be read
+	InProgress() bool
+	// LoadBackup returns the state saved before the current one, or nil
+	LoadBackup() (*mergestate.MergeState, error)
+	// ReadRaw returns the unparsed saved state, or nil if there is none
+	ReadRaw() ([]byte, error)
+	// Path returns where the state is saved
+	Path() (string, error)
+}
+
+// Prompter asks the user questions
+type Prompter interface {
+	// Confirm asks a yes/no question; an empty answer returns defaultYes
+	Confirm(prompt string, defaultYes bool) bool
+	// Ask asks for a value and returns the answer without surrounding space
+	Ask(prompt string) string
+}
+
+// Clock returns the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Deps holds the dependencies of a command
+type Deps struct {
+	Git      Git
+	Remote   Remote
+	Config   ConfigStore
+	State    StateStore
+	Prompter Prompter
+	Clock    Clock
+	// Events receives the lifecycle events of the operation; nil drops them
+	Events *events.Bus
+	// Out receives the informational messages
+	Out io.Writer
+	// Err receives the warnings
+	Err io.Writer
+	// Results receives the results of the command, one value per line, which
+	// --quiet prints instead of the informational messages; nil drops them
+	Results io.Writer
+}
+
+// NewDeps returns the dependencies backed by the git executable, the
+// terminal and the system clock. cache answers the repeated queries of the
+// command; with nil, Git answers each one. Call it after the --quiet mode is
+// set up, as Out is the standard output at that time.
+func NewDeps(cache *git.Cache) *Deps {
+	return &Deps{
+		Git:      gitRunner{cache: cache},
+		Remote:   gitRemote{},
+		Config:   gitConfigStore{cache: cache},
+		State:    mergestate.Store{},
+		Prompter: terminalPrompter{},
+		Clock:    systemClock{},
+		Events:   events.Default(),
+		Out:      os.Stdout,
+		Err:      os.Stderr,
+	}
+}
+
+// result writes one line of the results of the command to Results
+func (d *Deps) result(format string, args ...interface{}) {
+	if d.Results != nil {
+		fmt.Fprintf(d.Results, format+"\n", args...)
+	}
+}
+
+// isTerminal reports whether w is a terminal, so messages written to it may
+// be colored
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// gitRunner implements Git by running the git executable, answering repeated
+// queries from cache
+type gitRunner struct {
+	cache *git.Cache
+}
+
+func (r gitRunner) GetGitDir() (string, error)           { return r.cache.GetGitDir() }
+func (gitRunner) GetTopLevelDir() (string, error)        { return git.GetTopLevelDir() }
+func (gitRunner) GetGitVersion() (git.GitVersion, error) { return git.GetGitVersion() }
+func (gitRunner) ColorEnabled(slot string, stdoutIsTTY bool) bool {
+	return git.ColorEnabled(slot, stdoutIsTTY)
+}
+func (gitRunner) EditMessage(name, template string) (string, error) {
+	return git.EditMessage(name, template)
+}
+func (gitRunner) GetCurrentBranch() (string, error)  { return git.GetCurrentBranch() }
+func (gitRunner) IsDetachedHead() bool               { return git.IsDetachedHead() }
+func (r gitRunner) BranchExists(branch string) error { return r.cache.BranchExists(branch) }
+func (r gitRunner) BranchOrCommitExists(ref string) error {
+	return r.cache.BranchOrCommitExists(ref)
+}
+func (gitRunner) ListBranches() ([]string, error) { return git.ListBranches() }
+func (gitRunner) Checkout(branch string) error    { return git.Checkout(branch) }
+func (gitRunner) CreateBranch(name string, startPoint string) error {
+	return git.CreateBranch(name, startPoint)
+}
+func (gitRunner) CreateBranchNoTrack(name string, startPoint string) error {
+	return git.CreateBranchNoTrack(name, startPoint)
+}
+func (gitRunner) CreateTrackingBranch(localBranch, remote, remoteBranch string) error {
+	return git.CreateTrackingBranch(localBranch, remote, remoteBranch)
+}
+func (gitRunner) FastForwardBranch(branch, target string) error {
+	return git.FastForwardBranch(branch, target)
+}
+func (gitRunner) RenameBranch(oldBranch, newBranch string) error {
+	return git.RenameBranch(oldBranch, newBranch)
+}
+func (gitRunner) DeleteBranch(branch string, force bool) error {
+	return git.DeleteBranch(branch, force)
+}
+func (gitRunner) GetBaseBranch(branchName string) (string, error) {
+	return git.GetBaseBranch(branchName)
+}
+func (gitRunner) SetBaseBranch(branchName, baseBranch string) error {
+	return git.SetBaseBranch(branchName, baseBranch)
+}
+func (gitRunner) GetBranchDescription(branchName string) string {
+	return git.GetBranchDescription(branchName)
+}
+func (gitRunner) SetBranchDescription(branchName, description string) error {
+	return git.SetBranchDescription(branchName, description)
+}
+func (gitRunner) ListWorktrees() ([]git.Worktree, error)  { return git.ListWorktrees() }
+func (gitRunner) WorktreeOfBranch(branch string) string   { return git.WorktreeOfBranch(branch) }
+func (gitRunner) HasUncommittedChanges() (bool, error)    { return git.HasUncommittedChanges() }
+func (gitRunner) GetOperationInProgress() (string, error) { return git.GetOperationInProgress() }
+func (gitRunner) HasConflicts() bool                      { return git.HasConflicts() }
+func (gitRunner) UnmergedFiles() ([]string, error)        { return git.UnmergedFiles() }
+func (gitRunner) ResolveConflictFavoring(path, side string) error {
+	return git.ResolveConflictFavoring(path, side)
+}
+func (gitRunner) UntrackedFilesIn(branch string) ([]string, error) {
+	return git.UntrackedFilesIn(branch)
+}
+func (gitRunner) StashUntracked(message string) (string, error) { return git.StashUntracked(message) }
+func (gitRunner) RestoreStash(commit string) error              { return git.RestoreStash(commit) }
+func (gitRunner) BranchCommit(branch string) (string, error)    { return git.BranchCommit(branch) }
+func (gitRunner) IsAncestor(ancestor, descendant string) bool {
+	return git.IsAncestor(ancestor, descendant)
+}
+func (gitRunner) IsFirstParentAncestor(commit, branch string) bool {
+	return git.IsFirstParentAncestor(commit, branch)
+}
+func (gitRunner) AheadBehind(branch, other string) (int, int, error) {
+	return git.AheadBehind(branch, other)
+}
+func (gitRunner) CompareBranches(branch, other string) (git.BranchSyncStatus, int, error) {
+	return git.CompareBranches(branch, other)
+}
+func (gitRunner) ChangesApplied(branch, target string) bool {
+	return git.ChangesApplied(branch, target)
+}
+func (gitRunner) OnlyRebasedCommits(branch, upstream string) bool {
+	return git.OnlyRebasedCommits(branch, upstream)
+}
+func (gitRunner) BackMerges(branch, parent string) ([]string, error) {
+	return git.BackMerges(branch, parent)
+}
+func (gitRunner) CommitSubjects(base, branch string) ([]string, error) {
+	return git.CommitSubjects(base, branch)
+}
+func (gitRunner) CommitTrailers(revRange string, key string) ([]string, error) {
+	return git.CommitTrailers(revRange, key)
+}
+func (gitRunner) DiffShortStat(base, branch string) (git.DiffStat, error) {
+	return git.DiffShortStat(base, branch)
+}
+func (gitRunner) GetCommitSignature(rev string) (*git.CommitSignature, error) {
+	return git.GetCommitSignature(rev)
+}
+func (gitRunner) BackportCommits(base, branch string) ([]string, error) {
+	return git.BackportCommits(base, branch)
+}
+func (gitRunner) UnappliedCommits(target string, commits []string) ([]string, error) {
+	return git.UnappliedCommits(target, commits)
+}
+func (gitRunner) Merge(ctx context.Context, branch string, noVerify bool) error {
+	return git.Merge(ctx, branch, noVerify)
+}
+func (gitRunner) MergeWithOptions(ctx context.Context, branchName string, noFF bool, noVerify bool) error {
+	return git.MergeWithOptions(ctx, branchName, noFF, noVerify)
+}
+func (gitRunner) MergeWithMessage(ctx context.Context, branchName string, message string, noFF bool, noVerify bool) error {
+	return git.MergeWithMessage(ctx, branchName, message, noFF, noVerify)
+}
+func (gitRunner) MergeFastForwardOnly(ctx context.Context, branchName string) error {
+	return git.MergeFastForwardOnly(ctx, branchName)
+}
+func (gitRunner) SquashMerge(ctx context.Context, branch string, noVerify bool) error {
+	return git.SquashMerge(ctx, branch, noVerify)
+}
+func (gitRunner) MergeSquashWithMessage(ctx context.Context, branchName string, message string, noVerify bool) error {
+	return git.MergeSquashWithMessage(ctx, branchName, message, noVerify)
+}
+func (gitRunner) MergeInMemory(ctx context.Context, branch, source, message string) (merged bool, err error) {
+	return git.MergeInMemory(ctx, branch, source, message)
+}
+func (gitRunner) MergeAbort() error { return git.MergeAbort() }
+func (gitRunner) Rebase(ctx context.Context, branch string, noVerify bool) error {
+	return git.Rebase(ctx, branch, noVerify)
+}
+func (gitRunner) RebaseWithOptions(ctx context.Context, targetBranch string, preserveMerges bool, noVerify bool) error {
+	return git.RebaseWithOptions(ctx, targetBranch, preserveMerges, noVerify)
+}
+func (gitRunner) RebaseContinue(ctx context.Context) error { return git.RebaseContinue(ctx) }
+func (gitRunner) RebaseAbort() error                       { return git.RebaseAbort() }
+func (gitRunner) UndoStoppedOperation() error              { return git.UndoStoppedOperation() }
+func (gitRunner) Commit(ctx context.Context, message string, noVerify bool) error {
+	return git.Commit(ctx, message, noVerify)
+}
+func (gitRunner) CherryPickOnto(ctx context.Context, branch, startPoint string, commits []string) error {
+	return git.CherryPickOnto(ctx, branch, startPoint, commits)
+}
+func (r gitRunner) TagExists(name string) bool          { return r.cache.TagExists(name) }
+func (gitRunner) TagCommit(name string) (string, error) { return git.TagCommit(name) }
+func (gitRunner) IsValidTagName(name string) bool       { return git.IsValidTagName(name) }
+func (gitRunner) CreateTag(tagName string, options *git.TagOptions) error {
+	return git.CreateTag(tagName, options)
+}
+func (gitRunner) MoveTags(targets map[string]string) error { return git.MoveTags(targets) }
+func (gitRunner) LatestTagExcluding(rev string, exclude string) (string, error) {
+	return git.LatestTagExcluding(rev, exclude)
+}
+func (gitRunner) ReleaseCandidates(branchName string) ([]git.ReleaseCandidate, error) {
+	return git.ReleaseCandidates(branchName)
+}
+func (gitRunner) ClearReleaseCandidates(branchName string) error {
+	return git.ClearReleaseCandidates(branchName)
+}
+func (gitRunner) RemoteExists(remote string) bool { return git.RemoteExists(remote) }
+func (r gitRunner) RemoteBranchExists(remote, branch string) bool {
+	return r.cache.RemoteBranchExists(remote, branch)
+}
+func (r gitRunner) RemoteBranches(remote string) ([]string, error) {
+	return r.cache.RemoteBranches(remote)
+}
+func (gitRunner) GetRemoteURL(remote string) (string, error) { return git.GetRemoteURL(remote) }
+func (gitRunner) GetTrackingBranch(branch string) (string, error) {
+	return git.GetTrackingBranch(branch)
+}
+func (gitRunner) SetUpstream(branch, remote, remoteBranch string) error {
+	return git.SetUpstream(branch, remote, remoteBranch)
+}
+func (gitRunner) PushDestination(branch, remote string) (string, error) {
+	return git.PushDestination(branch, remote)
+}
+func (gitRunner) PushExclusion(remote, ref string) string { return git.PushExclusion(remote, ref) }
+
+// gitRemote implements Remote by running the git executable
+type gitRemote struct{}
+
+func (gitRemote) CheckRemoteReachable(ctx context.Context, remote string) error {
+	return git.CheckRemoteReachable(ctx, remote)
+}
+func (gitRemote) Fetch(ctx context.Context, remote string) error { return git.Fetch(ctx, remote) }
+func (gitRemote) FetchBranch(ctx context.Context, remote, branch string) error {
+	return git.FetchBranch(ctx, remote, branch)
+}
+func (gitRemote) PushBranch(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string, track, withLease bool) error {
+	switch {
+	case track && withLease:
+		return git.PushBranchWithLease(ctx, remote, branch, remoteBranch, pushOptions)
+	case withLease:
+		return git.PushBranchWithLeaseNoTrack(ctx, remote, branch, remoteBranch, pushOptions)
+	case track:
+		return git.PushBranch(ctx, remote, branch, remoteBranch, pushOptions)
+	default:
+		return git.PushBranchNoTrack(ctx, remote, branch, remoteBranch, pushOptions)
+	}
+}
+func (gitRemote) PushRefs(ctx context.Context, remote string, refspecs, leased []string) error {
+	if len(leased) == 0 {
+		return git.PushRefsAtomic(ctx, remote, refspecs)
+	}
+	return git.PushRefsAtomicWithLease(ctx, remote, refspecs, leased)
+}
+func (gitRemote) DeleteRemoteBranch(ctx context.Context, remote, branch string) error {
+	return git.DeleteRemoteBranch(ctx, remote, branch)
+}
+
+// gitConfigStore implements ConfigStore with the repository's Git config,
+// reading the gitflow.* keys from the snapshot of cache
+type gitConfigStore struct {
+	cache *git.Cache
+}
+
+func (s gitConfigStore) Get(key string) (string, error) { return s.cache.GetConfig(key) }
+func (gitConfigStore) GetAll(key string) ([]string, error) {
+	return git.GetConfigAllValues(key)
+}
+func (gitConfigStore) Set(key, value string) error { return git.SetConfig(key, value) }
+func (gitConfigStore) Unset(key string) error      { return git.UnsetConfig(key) }
+
+// terminalPrompter implements Prompter by reading answers from standard input
+type terminalPrompter struct{}
+
+func (p terminalPrompter) Confirm(prompt string, defaultYes bool) bool {
+	switch strings.ToLower(p.Ask(prompt)) {
+	case "":
+		return defaultYes
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func (terminalPrompter) Ask(prompt string) string {
+	output.Prompt("%s", prompt)
+	return strings.TrimSpace(readLine(os.Stdin))
+}
+
+// readLine reads one line from r a byte at a time, so input piped to several
+// questions is not consumed by a buffer beyond the current answer
+func readLine(r io.Reader) string {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			break
+		}
+	}
+	return string(line)
+}
+
+// systemClock implements Clock with the system time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
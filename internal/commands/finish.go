@@ -0,0 +1,2927 @@
+// Finish completes topic branches.
+//
+// FINISH COMMAND STATE MACHINE
+// =============================
+//
+// The finish command follows a strict state machine to ensure safe and consistent
+// branch completion, even in the presence of merge conflicts. The state is persisted
+// to disk to allow continuation after conflict resolution.
+//
+// State Flow:
+// 1. MERGE STATE
+//    - Creates merge state file with current step "merge"
+//    - Executes merge into parent branch using topic branch's merge strategy
+//    - On conflict: Saves state and exits for user to resolve
+//    - On success: Advances to CREATE_TAG state
+//
+// 2. CREATE_TAG STATE
+//    - Creates tag if configured (should not fail)
+//    - Advances to UPDATE_CHILDREN state
+//
+// 3. UPDATE_CHILDREN STATE
+//    - Identifies child branches with AutoUpdate=true
+//    - For each child branch:
+//      * Checks out child branch
+//      * Merges parent branch using child's downstream strategy
+//      * On conflict: Saves state (including which child) and exits
+//      * On success: Marks child as updated, continues with next
+//    - When all children updated: Advances to EXTRA_TAGS state
+//
+// 4. EXTRA_TAGS STATE
+//    - Points the configured extra tags at their target branches in a single
+//      transaction (no-op without extra tags)
+//    - Advances to PUSH state
+//
+// 5. PUSH STATE
+//    - With --push, pushes the parent, updated children and all tags atomically,
+//      to the branches push.default selects and leaving out refs excluded by
+//      negative refspecs of the remote
+//    - Otherwise, with pushtag configured, pushes only the created tag
+//    - On failure: Keeps the state so --continue retries the push
+//    - Advances to DELETE_BRANCH state
+//
+// 6. DELETE_BRANCH STATE
+//    - Deletes topic branch (local/remote based on settings)
+//    - Clears merge state file
+//    - Operation complete; what was pushed is then pushed to the mirror remotes
+//      of gitflow.mirror.remotes, and the branch is backported to the
+//      maintenance branches of gitflow.<type>.finish.backport, outside the
+//      state machine
+//
+// Conflict Resolution:
+// - User resolves conflicts manually
+// - Runs 'git flow <type> finish --continue <name>' to resume
+// - State machine continues from saved position
+// - Can abort with 'git flow <type> finish --abort <name>'
+//
+// Interruption:
+// - SIGINT/SIGTERM received after the merge state is saved do not kill the process
+// - The running step completes, then state is saved with Interrupted=true and the
+//   command exits with status 130
+// - 'git flow <type> finish --continue <name>' resumes with the next step
+// - Cancelling the context passed to Finish stops the running git command
+//   instead; the step cut short is undone (merge or rebase aborted), state is
+//   saved with Interrupted=true and --continue runs that step again. A fetch
+//   still running is stopped and the finish ends before anything was changed
+//
+// Critical Requirements:
+// - State must ALWAYS be saved before exiting on conflicts
+// - State must accurately reflect current branch and step
+// - Child branch updates must track which child is being processed
+// - All operations must be idempotent for safe continuation
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/interrupt"
+	"github.com/gittower/git-flow-next/internal/journal"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/profile"
+	"github.com/gittower/git-flow-next/internal/update"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// Strategy constants
+const (
+	strategyRebase = "rebase"
+	strategySquash = "squash"
+	strategyMerge  = "merge"
+)
+
+// =============================================================================
+// PUBLIC ENTRY POINTS
+// =============================================================================
+
+// FinishOptions are the options of a finish. Nil pointers fall back to the
+// configuration.
+type FinishOptions struct {
+	// Continue resumes a finish stopped by a conflict or an interruption
+	Continue bool
+	// Abort abandons the finish in progress and restores the branches
+	Abort bool
+	// Force skips the confirmation for a branch without the type's prefix,
+	// the check that the branch is up to date with its remote branch and the
+	// refusal to rebase published child branches
+	Force            bool
+	Tag              *config.TagOptions
+	Retention        *config.BranchRetentionOptions
+	Merge            *config.MergeStrategyOptions
+	Fetch            *bool
+	Push             *bool
+	NoVerify         *bool
+	NoVerifyChildren *bool
+	// To finishes into this branch instead of the resolved base
+	To string
+}
+
+// resolve resolves the options against the configuration of branchType
+func (o FinishOptions) resolve(cfg *config.Config, branchType string, shortName string) *config.ResolvedFinishOptions {
+	return config.ResolveFinishOptions(cfg, branchType, shortName, o.Tag, o.Retention, o.Merge, o.Fetch, o.Push, o.NoVerify)
+}
+
+// Finish finishes the topic branch name of branchType: it merges the branch
+// into its base, tags it, updates the child base branches, pushes and deletes
+// it as the configuration and options select.
+//
+// Cancelling ctx stops the running git command and ends the finish with the
+// merge state saved: a step cut short is undone and runs again on --continue.
+// A signal, unlike a cancellation, lets the running step complete first.
+func Finish(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options FinishOptions) error {
+	// Nothing may run once ctx is cancelled, as every long-running git command would fail
+	if ctx.Err() != nil {
+		return &errors.InterruptedError{Signal: "cancellation"}
+	}
+	return executeFinish(ctx, deps, cfgCtx, branchType, name, options)
+}
+
+// =============================================================================
+// MAIN EXECUTION FLOW
+// =============================================================================
+
+// executeFinish performs the actual branch finishing logic and returns any errors.
+// Cancelling ctx stops a running fetch, merge or push and ends the finish with
+// the merge state saved.
+func executeFinish(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options FinishOptions) error {
+	defer profile.Report()
+
+	cfg := cfgCtx.Config
+
+	// --ff-only refuses the merge commit --no-ff asks for
+	if options.Merge != nil && options.Merge.FFOnly != nil && *options.Merge.FFOnly && options.Merge.NoFF != nil && *options.Merge.NoFF {
+		return &errors.InvalidInputError{Message: "--ff-only cannot be combined with --no-ff"}
+	}
+
+	if options.Tag != nil && options.Tag.Retag && options.Tag.SkipTag {
+		return &errors.InvalidInputError{Message: "--retag cannot be combined with --skip-tag"}
+	}
+
+	if _, err := parseChildStrategies(options.Merge); err != nil {
+		return err
+	}
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Check if there's a merge in progress
+	if deps.State.InProgress() {
+		state, err := deps.State.Load()
+		if _, unreadable := err.(*errors.UnreadableStateError); unreadable {
+			return err
+		}
+		if err != nil {
+			return &errors.GitError{Operation: "load merge state", Err: err}
+		}
+
+		// Configuration the pre-hook amended still applies
+		if err := config.AmendConfig(cfg, state.ConfigAmendments); err != nil {
+			return err
+		}
+
+		// Get the branch config for the state's branch type
+		stateBranchConfig, ok := cfg.Branches[state.BranchType]
+		if !ok {
+			return &errors.InvalidBranchTypeError{BranchType: state.BranchType}
+		}
+
+		if options.Abort {
+			return handleAbort(deps, state)
+		}
+
+		if options.Continue {
+			watcher := interrupt.Watch()
+			defer watcher.Stop()
+
+			// Resolve options for continue operation
+			resolvedOptions := options.resolve(cfg, state.BranchType, state.BranchName)
+			// The tag message written with --edit is used unless a new one is given
+			if state.EditedTagMessage != "" && (options.Tag == nil || options.Tag.Message == "" && options.Tag.MessageFile == "") {
+				resolvedOptions.TagMessage = state.EditedTagMessage
+			}
+			// --retag or --skip-tag given with --continue replaces the one given to finish
+			if resolvedOptions.ExistingTag == "" {
+				resolvedOptions.ExistingTag = state.ExistingTag
+			}
+			// A child update rejected by a hook can be continued without it
+			if options.NoVerifyChildren != nil {
+				state.NoVerifyChildren = *options.NoVerifyChildren
+			}
+			if options.Merge != nil && options.Merge.AutostashUntracked != nil {
+				state.AutostashUntracked = *options.Merge.AutostashUntracked
+			}
+			if err := handleContinue(ctx, watcher, deps, cfg, state, stateBranchConfig, resolvedOptions, options.Merge); err != nil {
+				return err
+			}
+			// The options given with --continue apply to the rest of the batch
+			return finishBatch(ctx, deps, cfgCtx, state.BranchType, state.Batch, options, state.ParentBranch)
+		}
+
+		return &errors.MergeInProgressError{BranchName: state.FullBranchName}
+	}
+
+	// Don't allow continue or abort if no merge is in progress
+	if options.Continue || options.Abort {
+		return &errors.NoMergeInProgressError{}
+	}
+
+	if options.Merge != nil && len(options.Merge.Batch) > 0 {
+		if err := validateBatch(deps, cfg, branchType, name, options.Merge.Batch, options.Tag); err != nil {
+			return err
+		}
+	}
+
+	// Resolve the short or full branch name; a missing branch is reported
+	// together with any other pre-flight problems, a name of another type at once
+	resolvedName, branchErr := resolveBranchName(deps, cfg, branchType, name)
+	if mismatch, ok := branchErr.(*errors.TopicTypeMismatchError); ok {
+		return mismatch
+	}
+	if branchErr == nil {
+		name = resolvedName
+	}
+
+	// Decide whether to finish into the configured parent, the stored base or an explicit --to target
+	targetBranch, baseSource, err := resolveFinishBase(deps, cfg, branchType, name, branchConfig, options.To)
+	if err != nil {
+		return err
+	}
+
+	// Validate everything before anything is fetched, merged or hooked
+	preflightOptions := options.resolve(cfg, branchType, finishShortName(name, branchConfig))
+	stopPreflight := profile.Start("pre-flight checks")
+	err = preflightFinish(ctx, deps, cfg, branchType, name, branchErr, targetBranch, baseSource, preflightOptions)
+	stopPreflight()
+	if err != nil {
+		return err
+	}
+	branchConfig.Parent = targetBranch
+	fmt.Fprintf(deps.Out, "Using base branch '%s' (%s)\n", targetBranch, baseSource)
+
+	// If the branch exists but doesn't have the expected prefix
+	if !strings.HasPrefix(name, branchConfig.Prefix) {
+		if !options.Force {
+			// Get the short name for tag creation
+			shortName := name
+			if strings.Contains(name, "/") {
+				parts := strings.Split(name, "/")
+				shortName = parts[len(parts)-1]
+			}
+
+			// Prompt user for confirmation
+			var prompt strings.Builder
+			fmt.Fprintf(&prompt, "Warning: Branch '%s' is not a standard %s branch (missing prefix '%s').\n", name, branchType, branchConfig.Prefix)
+			fmt.Fprintf(&prompt, "Finishing this branch will:\n")
+			fmt.Fprintf(&prompt, "1. Merge it into '%s' using the %s strategy\n", branchConfig.Parent, branchConfig.UpstreamStrategy)
+
+			// Resolve options early for confirmation dialog
+			resolvedOptions := options.resolve(cfg, branchType, shortName)
+
+			if resolvedOptions.ShouldTag {
+				fmt.Fprintf(&prompt, "2. Create a tag '%s'\n", resolvedOptions.TagName)
+			}
+
+			fmt.Fprintf(&prompt, "3. Delete the branch after successful merge\n\n")
+			fmt.Fprintf(&prompt, "Do you want to continue? [y/N]: ")
+
+			if !deps.Prompter.Confirm(prompt.String(), false) {
+				return fmt.Errorf("operation cancelled by user")
+			}
+		}
+	}
+
+	// Get the short name for option resolution
+	shortName := finishShortName(name, branchConfig)
+
+	// Resolve all options once before starting operations
+	resolvedOptions := options.resolve(cfg, branchType, shortName)
+
+	// Perform fetch if enabled (only on initial finish, not continue)
+	if resolvedOptions.ShouldFetch {
+		stopFetch := profile.Start("fetch")
+		fmt.Fprintf(deps.Out, "Fetching from remote '%s'...\n", cfg.Remote)
+		// Fetch base branch
+		offline := false
+		if err := deps.Remote.FetchBranch(ctx, cfg.Remote, branchConfig.Parent); err != nil {
+			if errors.RemoteFailure(err) != nil {
+				offline = WarnFetchFailed(deps, cfg.Remote, err)
+			} else {
+				// Non-fatal: remote branch might not exist
+				fmt.Fprintf(deps.Out, "Note: Could not fetch base branch '%s': %v\n", branchConfig.Parent, err)
+			}
+		}
+		// Fetch topic branch, unless the remote turned out to be unreachable
+		if !offline {
+			if err := deps.Remote.FetchBranch(ctx, cfg.Remote, name); err != nil {
+				// Non-fatal: remote branch might not exist
+				fmt.Fprintf(deps.Out, "Note: Could not fetch topic branch '%s': %v\n", name, err)
+			}
+		}
+		stopFetch()
+		// Nothing has been changed yet, so a cancelled fetch ends the finish here
+		if ctx.Err() != nil {
+			return &errors.InterruptedError{Signal: "cancellation"}
+		}
+		fmt.Fprintf(deps.Out, "Fetch completed\n")
+	}
+
+	// Check if local branch is in sync with remote (unless --force)
+	if !options.Force {
+		if err := checkTopicUpToDate(deps, cfg, branchType, name); err != nil {
+			return err
+		}
+	}
+
+	// Regular finish command flow
+	if err := finishBranch(ctx, deps, cfgCtx, branchType, name, branchConfig, options); err != nil {
+		return err
+	}
+	if options.Merge == nil {
+		return nil
+	}
+	return finishBatch(ctx, deps, cfgCtx, branchType, options.Merge.Batch, options, branchConfig.Parent)
+}
+
+// validateBatch checks the branches of a --batch finish before the first one is
+// finished, so the batch doesn't stop halfway on a typo
+func validateBatch(deps *Deps, cfg *config.Config, branchType string, name string, batch []string, tagOptions *config.TagOptions) error {
+	if tagOptions != nil && tagOptions.TagName != "" {
+		return &errors.InvalidInputError{Message: "--tagname cannot be used with --batch, every branch is tagged with its own name"}
+	}
+	seen := map[string]bool{}
+	for _, branch := range append([]string{name}, batch...) {
+		resolved, err := resolveBranchName(deps, cfg, branchType, branch)
+		if err != nil {
+			return err
+		}
+		if seen[resolved] {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("--batch: '%s' is given more than once", branch)}
+		}
+		seen[resolved] = true
+	}
+	return nil
+}
+
+// finishBatch finishes the remaining branches of a --batch finish one after
+// the other into target. Only the last one updates the child base branches.
+func finishBatch(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, remaining []string, options FinishOptions, target string) error {
+	if len(remaining) == 0 {
+		return nil
+	}
+	batchMerge := config.MergeStrategyOptions{}
+	if options.Merge != nil {
+		batchMerge = *options.Merge
+	}
+	batchMerge.Batch = remaining[1:]
+	batchOptions := options
+	batchOptions.Continue, batchOptions.Abort = false, false
+	batchOptions.Merge = &batchMerge
+	batchOptions.To = target
+
+	fmt.Fprintf(deps.Out, "\nFinishing '%s' (%d of the batch left after it)...\n", remaining[0], len(batchMerge.Batch))
+	return executeFinish(ctx, deps, cfgCtx, branchType, remaining[0], batchOptions)
+}
+
+func finishBranch(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, branchConfig config.BranchConfig, options FinishOptions) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	// Validate inputs
+	if name == "" {
+		return &errors.InvalidBranchNameError{BranchName: name}
+	}
+
+	// Get the short name by removing the prefix if it exists
+	shortName := finishShortName(name, branchConfig)
+
+	// Check if branch exists
+	if err := deps.Git.BranchExists(name); err != nil {
+		return &errors.BranchNotFoundError{BranchName: name}
+	}
+
+	// Get target branch (always the parent branch for finishing)
+	targetBranch := branchConfig.Parent
+
+	// Check if target branch exists
+	if err := deps.Git.BranchExists(targetBranch); err != nil {
+		return &errors.BranchNotFoundError{BranchName: targetBranch}
+	}
+
+	overrides, err := parseChildStrategies(options.Merge)
+	if err != nil {
+		return err
+	}
+
+	// Find child base branches that need to be updated, in update order, and collect their strategies
+	skip := make(map[string]bool)
+	if options.Merge != nil {
+		for _, branchName := range options.Merge.NoUpdate {
+			skip[branchName] = true
+		}
+	}
+	// With --batch, the children are only updated after the last branch of the batch
+	deferChildren := options.Merge != nil && len(options.Merge.Batch) > 0
+	childBranches := []string{}
+	skippedBranches := []string{}
+	for branchName, branch := range cfg.Branches {
+		if branch.Type == string(config.BranchTypeBase) && branch.Parent == targetBranch && branch.AutoUpdate {
+			if skip[branchName] {
+				skippedBranches = append(skippedBranches, branchName)
+				delete(skip, branchName)
+				continue
+			}
+			childBranches = append(childBranches, branchName)
+		}
+	}
+	for branchName := range skip {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("--no-update: '%s' is not a child base branch of '%s' with auto-update enabled", branchName, targetBranch)}
+	}
+	config.SortByUpdateOrder(cfg, childBranches)
+	config.SortByUpdateOrder(cfg, skippedBranches)
+	if !deferChildren {
+		for _, branchName := range skippedBranches {
+			fmt.Fprintf(deps.Out, "Skipping child base branch '%s' (--no-update)\n", branchName)
+		}
+	}
+	childStrategies := make(map[string]string)
+	for _, branchName := range childBranches {
+		// Store the downstream strategy for this child branch
+		strategy, source := cfg.Branches[branchName].DownstreamStrategy, "downstream strategy"
+		if override, ok := overrides[branchName]; ok {
+			strategy, source = override, "--child-strategy"
+			delete(overrides, branchName)
+		}
+		childStrategies[branchName] = strategy
+		if !deferChildren {
+			fmt.Fprintf(deps.Out, "Found child base branch '%s' with auto-update enabled (%s: %s)\n", branchName, source, EffectiveChildStrategy(strategy))
+		}
+	}
+	for branchName := range overrides {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("--child-strategy: '%s' is not a child base branch of '%s' with auto-update enabled", branchName, targetBranch)}
+	}
+	if deferChildren && len(childBranches)+len(skippedBranches) > 0 {
+		fmt.Fprintf(deps.Out, "Child base branches are updated after the last branch of the batch (%s)\n", options.Merge.Batch[len(options.Merge.Batch)-1])
+		childBranches, skippedBranches = []string{}, nil
+		childStrategies = make(map[string]string)
+	}
+
+	// Rebasing a published child rewrites history others may have built on
+	forcePushBranches := []string{}
+	for _, branchName := range childBranches {
+		if EffectiveChildStrategy(childStrategies[branchName]) != strategyRebase || cfg.Remote == "" || !deps.Git.RemoteBranchExists(cfg.Remote, branchName) {
+			continue
+		}
+		if !options.Force {
+			return &errors.PublishedRebaseError{BranchType: branchType, BranchName: name, ChildBranch: branchName, ParentBranch: targetBranch, Remote: cfg.Remote}
+		}
+		fmt.Fprintf(deps.Out, "Warning: '%s' is published on '%s'; rebasing it rewrites its history (--force)\n", branchName, cfg.Remote)
+		forcePushBranches = append(forcePushBranches, branchName)
+	}
+
+	// Untracked files a child checkout would overwrite fail the finish before
+	// the merge, unless they are stashed around the child updates
+	autostashUntracked := config.ResolveFinishAutostashUntracked(cfg, branchType, options.Merge)
+	if !autostashUntracked {
+		for _, branchName := range childBranches {
+			files, err := deps.Git.UntrackedFilesIn(branchName)
+			if err != nil {
+				return &errors.GitError{Operation: "check untracked files", Err: err}
+			}
+			if len(files) > 0 {
+				return &errors.UntrackedFilesError{ChildBranch: branchName, Files: files, BranchType: branchType, BranchName: name}
+			}
+		}
+	}
+
+	// Resolve all options once at the beginning
+	resolvedOptions := options.resolve(cfg, branchType, shortName)
+
+	// Expand and validate extra tags before anything is changed
+	extraTags, err := expandExtraTags(deps, resolveExtraTags(deps, branchType, options.Tag), shortName, targetBranch, resolvedOptions)
+	if err != nil {
+		return err
+	}
+
+	// A branch merged elsewhere, e.g. through a pull request, only needs the tag and the cleanup
+	alreadyMerged := detectAlreadyMerged(deps, name, targetBranch, options.Merge != nil && options.Merge.IfMerged)
+
+	// Backport targets must exist, and pull requests be possible, before anything is changed
+	backports, backportPR := config.ResolveFinishBackports(cfg, branchType, options.Merge)
+	backportCommits, err := checkBackports(deps, cfg, backports, backportPR, name, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	// Trailers need a commit of their own, so the merge doesn't fast-forward
+	if !alreadyMerged {
+		trailers, err := resolveTrailers(deps, branchType, options.Merge, name, targetBranch, shortName, resolvedOptions)
+		if err != nil {
+			return err
+		}
+		if len(trailers) > 0 {
+			if resolvedOptions.FastForwardOnly {
+				return &errors.InvalidInputError{Message: "trailers are recorded in a merge commit, which --ff-only refuses to create"}
+			}
+			resolvedOptions.NoFastForward = true
+			if resolvedOptions.MergeMessage == "" {
+				resolvedOptions.MergeMessage = "Merge branch '%b' into %p"
+			}
+			// The merge message is expanded later, so a '%' in a trailer is kept literally
+			resolvedOptions.MergeMessage += "\n\n" + strings.ReplaceAll(strings.Join(trailers, "\n"), "%", "%%")
+			resolvedOptions.SquashMessage += "\n\n" + strings.Join(trailers, "\n")
+		}
+	}
+
+	// A fast-forward-only merge must be possible before anything is changed
+	if !alreadyMerged && resolvedOptions.FastForwardOnly && resolvedOptions.MergeStrategy == strategyMerge && !deps.Git.IsAncestor(targetBranch, name) {
+		return &errors.FastForwardNotPossibleError{BranchType: branchType, BranchName: name, TargetBranch: targetBranch}
+	}
+
+	// Merging back-merges would add them to the target's history as well
+	if !alreadyMerged {
+		if err := checkBackMerges(deps, cfg, branchType, name, targetBranch, resolvedOptions); err != nil {
+			return err
+		}
+	}
+
+	// Show what the merge brings in, to catch an accidentally huge merge
+	if !alreadyMerged && config.ResolveFinishSummary(cfg, branchType, options.Merge) {
+		printFinishSummary(deps, name, targetBranch)
+	}
+
+	// Let the user write the messages before anything is changed
+	if options.Merge != nil && options.Merge.Edit {
+		if err := editFinishMessages(deps, resolvedOptions, name, targetBranch, !alreadyMerged); err != nil {
+			return err
+		}
+	}
+
+	// Check the signature of the base branch before anything is merged into it.
+	// A failed check is reported after the pre-hook has seen it.
+	signature, signatureErr := verifyBaseSignature(deps, cfg, branchType, targetBranch)
+	if signatureErr != nil && signature == nil {
+		return signatureErr
+	}
+
+	// Run pre-hook before starting finish operation
+	gitDir, err := deps.Git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	hookCtx := hooks.HookContext{
+		BranchType: branchType,
+		BranchName: shortName,
+		FullBranch: name,
+		BaseBranch: targetBranch,
+		Origin:     cfg.Remote,
+	}
+	// Set version for branches configured with tagging
+	if branchConfig.Tag {
+		hookCtx.Version = shortName
+	}
+
+	if signature != nil {
+		hookCtx.BaseSignatureStatus = signature.Status
+		hookCtx.BaseSignatureKey = signature.Key
+		if sigErr, ok := signatureErr.(*errors.BaseSignatureError); ok {
+			hookCtx.BaseSignatureError = sigErr.Reason
+		}
+	}
+
+	// The pre-hook may amend the configuration of this finish
+	var amendments map[string]string
+	hookCtx.AmendConfig = func(values map[string]string) error {
+		amendments = values
+		return config.AmendConfig(cfg, values)
+	}
+
+	if err := hooks.RunPreHook(gitDir, branchType, hooks.HookActionFinish, hookCtx); err != nil {
+		if signatureErr != nil {
+			return signatureErr
+		}
+		return err
+	}
+	if signatureErr != nil {
+		return signatureErr
+	}
+
+	if len(amendments) > 0 {
+		branchConfig = cfg.Branches[branchType]
+		resolvedOptions = amendFinishOptions(cfg, branchType, shortName, resolvedOptions, options)
+		if extraTags, err = expandExtraTags(deps, resolveExtraTags(deps, branchType, options.Tag), shortName, targetBranch, resolvedOptions); err != nil {
+			return err
+		}
+	}
+
+	// From here on a signal stops the finish at the next step boundary
+	watcher := interrupt.Watch()
+	defer watcher.Stop()
+	if watcher.Stopped(ctx) {
+		return &errors.InterruptedError{Signal: watcher.Reason()}
+	}
+
+	// Save merge state before starting
+	state := &mergestate.MergeState{
+		Action:             "finish",
+		BranchType:         branchType,
+		BranchName:         shortName,
+		CurrentStep:        mergestate.StepMerge,
+		ParentBranch:       targetBranch,
+		MergeStrategy:      branchConfig.UpstreamStrategy,
+		FullBranchName:     name,
+		ChildBranches:      childBranches,
+		UpdatedBranches:    []string{},
+		ChildStrategies:    childStrategies,
+		SkippedBranches:    skippedBranches,
+		ForcePushBranches:  forcePushBranches,
+		SquashMessage:      resolvedOptions.SquashMessage,
+		MergeMessage:       resolvedOptions.MergeMessage,
+		UpdateMessage:      resolvedOptions.UpdateMessage,
+		NoVerify:           resolvedOptions.NoVerify,
+		NoVerifyChildren:   config.ResolveFinishNoVerifyChildren(cfg, branchType, options.NoVerifyChildren),
+		AutostashUntracked: autostashUntracked,
+		ExtraTags:          extraTags,
+		EditedTagMessage:   editedTagMessage(options.Merge, resolvedOptions),
+		ExistingTag:        resolvedOptions.ExistingTag,
+		AlreadyMerged:      alreadyMerged,
+		MergeTagToChildren: resolvedOptions.ShouldTag && config.ResolveFinishMergeTagToChildren(cfg, branchType),
+		Push:               resolvedOptions.ShouldPush,
+		PushTag:            resolvedOptions.ShouldPushTag,
+		Backports:          backports,
+		BackportCommits:    backportCommits,
+		BackportPR:         backportPR,
+	}
+	if options.Merge != nil {
+		state.Batch = options.Merge.Batch
+	}
+	state.ConfigAmendments = amendments
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	return executeSteps(ctx, watcher, deps, cfg, state, branchConfig, resolvedOptions)
+}
+
+// =============================================================================
+// PRE-FLIGHT VALIDATION
+// =============================================================================
+
+// preflightCheck is a single entry of the pre-flight checklist
+type preflightCheck struct {
+	label   string
+	err     error  // nil when the check passed
+	skipped string // reason the check was skipped, if any
+	warning string // non-fatal problem worth reporting
+}
+
+// preflightFinish validates that the finish can proceed before any repository
+// state is changed. All checks run, the results are printed as a checklist, and
+// every problem is reported at once instead of failing midway through the merge.
+func preflightFinish(ctx context.Context, deps *Deps, cfg *config.Config, branchType string, name string, branchErr error, targetBranch string, baseSource string, options *config.ResolvedFinishOptions) error {
+	checks := []preflightCheck{
+		{label: fmt.Sprintf("Branch '%s' exists", name), err: branchErr},
+	}
+
+	// Nothing else may be half-done in the working copy
+	operationCheck := preflightCheck{label: "No Git operation in progress"}
+	if operation, err := deps.Git.GetOperationInProgress(); err != nil {
+		operationCheck.err = &errors.GitError{Operation: "check for operations in progress", Err: err}
+	} else if operation != "" {
+		operationCheck.err = fmt.Errorf("a %s is in progress; complete or abort it first", operation)
+	}
+	checks = append(checks, operationCheck)
+
+	cleanCheck := preflightCheck{label: "Working tree is clean"}
+	if dirty, err := deps.Git.HasUncommittedChanges(); err != nil {
+		cleanCheck.err = &errors.GitError{Operation: "check working tree", Err: err}
+	} else if dirty {
+		cleanCheck.err = fmt.Errorf("working tree has uncommitted changes; commit or stash them first")
+	}
+	checks = append(checks, cleanCheck)
+	checks = append(checks, worktreeChecks(deps, cfg, name, targetBranch, options)...)
+
+	baseCheck := preflightCheck{label: fmt.Sprintf("Base branch '%s' exists (%s)", targetBranch, baseSource)}
+	if err := deps.Git.BranchExists(targetBranch); err != nil {
+		baseCheck.err = &errors.BaseBranchMissingError{
+			BranchType: branchType,
+			BranchName: name,
+			BaseBranch: targetBranch,
+			Source:     baseSource,
+			Candidates: findBaseCandidates(deps, cfg, name),
+		}
+	}
+	checks = append(checks, baseCheck)
+
+	// An existing tag fails the finish now rather than after the merge
+	tagCheck := preflightCheck{label: fmt.Sprintf("Tag '%s' is not taken", options.TagName)}
+	if !options.ShouldTag {
+		tagCheck.skipped = "no tag"
+	} else if deps.Git.TagExists(options.TagName) {
+		switch options.ExistingTag {
+		case config.ExistingTagRetag:
+			tagCheck.warning = "it exists and is moved with --retag"
+		case config.ExistingTagSkip:
+			tagCheck.warning = "it exists and is kept with --skip-tag"
+		default:
+			// A finish re-run after the merge finds the tag on the base branch
+			if tagCommit, err := deps.Git.TagCommit(options.TagName); err != nil || !tagOnBase(deps, tagCommit, name, targetBranch) {
+				tagCheck.err = &errors.TagExistsError{TagName: options.TagName, BranchType: branchType, BranchName: name}
+			}
+		}
+	}
+	checks = append(checks, tagCheck)
+
+	remoteCheck := preflightCheck{label: fmt.Sprintf("Remote '%s' is reachable", cfg.Remote)}
+	if !options.ShouldFetch {
+		remoteCheck.skipped = "fetch disabled"
+	} else if !deps.Git.RemoteExists(cfg.Remote) {
+		remoteCheck.skipped = "remote not configured"
+	} else if err := deps.Remote.CheckRemoteReachable(ctx, cfg.Remote); err != nil {
+		// Fetch failures are non-fatal, so an unreachable remote only warrants a warning
+		remoteCheck.warning = err.Error()
+	}
+	checks = append(checks, remoteCheck)
+
+	hooksCheck := preflightCheck{label: "Hooks are executable"}
+	if gitDir, err := deps.Git.GetGitDir(); err == nil {
+		if scripts := hooks.FindNonExecutableScripts(gitDir, branchType, hooks.HookActionFinish); len(scripts) > 0 {
+			hooksCheck.err = fmt.Errorf("hook scripts are not executable and would be skipped: %s (run chmod +x)", strings.Join(scripts, ", "))
+		}
+	}
+	checks = append(checks, hooksCheck)
+
+	// Print the checklist and collect failures
+	failures := []error{}
+	fmt.Fprintln(deps.Out, "Pre-flight checks:")
+	for _, check := range checks {
+		switch {
+		case check.skipped != "":
+			fmt.Fprintf(deps.Out, "  - %s (skipped: %s)\n", check.label, check.skipped)
+		case check.warning != "":
+			fmt.Fprintf(deps.Out, "  ! %s (warning: %s)\n", check.label, check.warning)
+		case check.err != nil:
+			fmt.Fprintf(deps.Out, "  ✗ %s\n", check.label)
+			failures = append(failures, check.err)
+		default:
+			fmt.Fprintf(deps.Out, "  ✓ %s\n", check.label)
+		}
+	}
+
+	// A stale stored base is harmless when it isn't the target, but worth pointing out
+	if stored, err := deps.Git.GetBaseBranch(name); err == nil && stored != "" && stored != targetBranch {
+		if deps.Git.BranchExists(stored) != nil {
+			fmt.Fprintf(deps.Out, "Warning: Stored base '%s' for '%s' no longer exists\n", stored, name)
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	// Keep the specific error (and its exit code) when there is only one problem
+	if len(failures) == 1 {
+		if _, ok := failures[0].(errors.Error); ok {
+			return failures[0]
+		}
+	}
+
+	problems := make([]string, len(failures))
+	for i, failure := range failures {
+		problems[i] = failure.Error()
+	}
+	return &errors.PreflightFailedError{Problems: problems}
+}
+
+// worktreeChecks makes sure no other worktree of the repository is in the way:
+// git refuses to check out the target and child branches or to delete or
+// rebase the branch while another worktree has them checked out, and an
+// operation in progress there would race with this one. Without linked
+// worktrees there is nothing to check and no entries are added.
+func worktreeChecks(deps *Deps, cfg *config.Config, name string, targetBranch string, options *config.ResolvedFinishOptions) []preflightCheck {
+	worktrees, err := deps.Git.ListWorktrees()
+	if err != nil || len(worktrees) < 2 {
+		return nil
+	}
+	checkedOut := make(map[string]string)
+	for _, worktree := range worktrees {
+		if !worktree.Current && worktree.Branch != "" {
+			checkedOut[worktree.Branch] = worktree.Path
+		}
+	}
+
+	branchCheck := preflightCheck{label: fmt.Sprintf("Branch '%s' is not checked out in another worktree", name)}
+	if path, ok := checkedOut[name]; ok {
+		if (options.Keep || options.KeepLocal) && !options.UseRebase {
+			branchCheck.warning = fmt.Sprintf("it is checked out in '%s' and kept", path)
+		} else {
+			branchCheck.err = &errors.WorktreeConflictError{BranchName: name, Worktree: path}
+		}
+	}
+
+	// The target and the base branches updated from it are checked out in turn
+	touched := []string{targetBranch}
+	for _, baseName := range config.SortedBaseBranches(cfg) {
+		if branch := cfg.Branches[baseName]; branch.Parent == targetBranch && branch.AutoUpdate {
+			touched = append(touched, baseName)
+		}
+	}
+	targetCheck := preflightCheck{label: fmt.Sprintf("'%s' is not checked out in another worktree", strings.Join(touched, "', '"))}
+	for _, branch := range touched {
+		if path, ok := checkedOut[branch]; ok {
+			targetCheck.err = &errors.WorktreeConflictError{BranchName: branch, Worktree: path}
+			break
+		}
+	}
+
+	operationCheck := preflightCheck{label: "No git-flow operation in another worktree changes these branches"}
+	for _, other := range mergestate.StatesOfWorktrees(worktrees) {
+		for _, branch := range append([]string{name}, touched...) {
+			if other.State.Touches(branch) {
+				operationCheck.err = &errors.WorktreeConflictError{BranchName: branch, Worktree: other.Worktree, Operation: other.State.Action}
+				break
+			}
+		}
+		if operationCheck.err != nil {
+			break
+		}
+	}
+
+	return []preflightCheck{branchCheck, targetCheck, operationCheck}
+}
+
+// findBaseCandidates lists existing branches that could serve as a finish target:
+// configured base branches first, then any other non-topic local branches.
+func findBaseCandidates(deps *Deps, cfg *config.Config, name string) []string {
+	branches, err := deps.Git.ListBranches()
+	if err != nil {
+		return nil
+	}
+
+	existing := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		existing[branch] = true
+	}
+
+	candidates := []string{}
+	seen := make(map[string]bool)
+	baseNames := []string{}
+	for branchName, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(config.BranchTypeBase) {
+			baseNames = append(baseNames, branchName)
+		}
+	}
+	sort.Strings(baseNames)
+	for _, branchName := range baseNames {
+		if existing[branchName] {
+			candidates = append(candidates, branchName)
+			seen[branchName] = true
+		}
+	}
+
+	for _, branch := range branches {
+		if seen[branch] || branch == name || isTopicBranch(cfg, branch) {
+			continue
+		}
+		candidates = append(candidates, branch)
+	}
+
+	return candidates
+}
+
+// isTopicBranch reports whether a branch name carries a configured topic prefix
+func isTopicBranch(cfg *config.Config, branch string) bool {
+	for _, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(config.BranchTypeTopic) && branchConfig.Prefix != "" && strings.HasPrefix(branch, branchConfig.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// finishShortName returns the name of the branch without its prefix; for
+// non-standard branches, the part after the last slash
+func finishShortName(name string, branchConfig config.BranchConfig) string {
+	if strings.HasPrefix(name, branchConfig.Prefix) {
+		return strings.TrimPrefix(name, branchConfig.Prefix)
+	}
+	if strings.Contains(name, "/") {
+		parts := strings.Split(name, "/")
+		return parts[len(parts)-1]
+	}
+	return name
+}
+
+// tagOnBase reports whether the branch is merged into the base branch and the
+// tag commit is its tip, as when the finish that created the tag is run again
+func tagOnBase(deps *Deps, tagCommit string, branch string, baseBranch string) bool {
+	baseCommit, err := deps.Git.BranchCommit(baseBranch)
+	return err == nil && baseCommit == tagCommit && deps.Git.IsAncestor(branch, baseBranch)
+}
+
+// =============================================================================
+// STATE MACHINE AND CONTROL FLOW
+// =============================================================================
+
+// executeSteps runs the state machine for the finish operation. Cancelling ctx
+// stops the git command of the running step; a signal caught by watcher stops
+// the finish once the step completed.
+func executeSteps(ctx context.Context, watcher *interrupt.Watcher, deps *Deps, cfg *config.Config, state *mergestate.MergeState, branchConfig config.BranchConfig, resolvedOptions *config.ResolvedFinishOptions) error {
+	for {
+		var err error
+		final := false
+		label := stepLabel(state)
+		stopStep := profile.Start(label)
+		deps.Events.StepStart(events.StepStart{Operation: state.Action, Branch: state.FullBranchName, Step: state.CurrentStep, Label: label})
+		switch state.CurrentStep {
+		case mergestate.StepMerge:
+			err = handleMergeStep(ctx, deps, cfg, state, branchConfig, resolvedOptions)
+		case mergestate.StepCreateTag:
+			err = handleCreateTagStep(deps, cfg, state, resolvedOptions)
+		case mergestate.StepUpdateChildren:
+			err = handleUpdateChildrenStep(ctx, deps, cfg, state, branchConfig, resolvedOptions)
+		case mergestate.StepExtraTags:
+			err = handleExtraTagsStep(deps, state)
+		case mergestate.StepPush:
+			err = handlePushStep(ctx, deps, cfg, state)
+		case mergestate.StepDeleteBranch:
+			err = handleDeleteBranchStep(ctx, deps, cfg, state, resolvedOptions) // Final step
+			final = true
+		default:
+			return &errors.GitError{Operation: fmt.Sprintf("unknown step '%s'", state.CurrentStep), Err: nil}
+		}
+		stopStep()
+
+		if err != nil {
+			// The step may have failed only because its git command was stopped
+			if ctx.Err() != nil {
+				return stopCancelled(deps, state, watcher.Reason())
+			}
+			return err
+		}
+		if final {
+			return nil
+		}
+
+		// Stop between steps if a signal or cancellation arrived while the step was running
+		if watcher.Stopped(ctx) {
+			return stopInterrupted(deps, state, watcher.Reason())
+		}
+	}
+}
+
+// stepLabel describes the current step for the --profile report
+func stepLabel(state *mergestate.MergeState) string {
+	switch state.CurrentStep {
+	case mergestate.StepMerge:
+		return fmt.Sprintf("merge %s into %s", state.FullBranchName, state.ParentBranch)
+	case mergestate.StepCreateTag:
+		return "create tag"
+	case mergestate.StepUpdateChildren:
+		if next := findNextBranchToUpdate(state); next != "" {
+			return fmt.Sprintf("update %s from %s", next, state.ParentBranch)
+		}
+		return "update child branches"
+	case mergestate.StepExtraTags:
+		return "move extra tags"
+	case mergestate.StepPush:
+		return "push"
+	case mergestate.StepDeleteBranch:
+		return "delete branch"
+	default:
+		return state.CurrentStep
+	}
+}
+
+// stopInterrupted records that the finish was stopped by a signal or cancellation
+// between steps, so that --continue resumes with the next step instead of
+// expecting a conflict
+func stopInterrupted(deps *Deps, state *mergestate.MergeState, reason string) error {
+	state.Interrupted = true
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return &errors.InterruptedError{
+		Signal:        reason,
+		Step:          state.CurrentStep,
+		ResumeCommand: fmt.Sprintf("git flow %s finish --continue %s", state.BranchType, state.BranchName),
+	}
+}
+
+// stopCancelled stops a finish whose step failed after its context was
+// cancelled. What the stopped command left half done is undone, so that
+// --continue runs the step again from its start.
+func stopCancelled(deps *Deps, state *mergestate.MergeState, reason string) error {
+	if err := deps.Git.UndoStoppedOperation(); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("undo step '%s' stopped by the cancellation", state.CurrentStep), Err: err}
+	}
+	return stopInterrupted(deps, state, reason)
+}
+
+func handleContinue(ctx context.Context, watcher *interrupt.Watcher, deps *Deps, cfg *config.Config, state *mergestate.MergeState, branchConfig config.BranchConfig, resolvedOptions *config.ResolvedFinishOptions, mergeOptions *config.MergeStrategyOptions) error {
+	// A finish stopped by a signal or cancellation ended cleanly between steps or
+	// undid the step cut short, so there is nothing to complete
+	if state.Interrupted {
+		state.Interrupted = false
+		if err := deps.State.Save(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return executeSteps(ctx, watcher, deps, cfg, state, branchConfig, resolvedOptions)
+	}
+
+	// Another branch may have been checked out while the finish was stopped
+	if state.CurrentStep == mergestate.StepMerge || state.CurrentStep == mergestate.StepUpdateChildren {
+		if err := restoreExpectedHead(deps, state); err != nil {
+			return err
+		}
+	}
+
+	// Handle continuation based on current step
+	switch state.CurrentStep {
+	case mergestate.StepMerge:
+		// For merge step continuation, check if conflicts are resolved
+		if deps.Git.HasConflicts() {
+			return &errors.UnresolvedConflictsError{}
+		}
+
+		// A merge or squash committed with git directly only needs the state to catch up
+		committed, err := committedOutsideFlow(deps, state, state.ParentBranch)
+		if err != nil {
+			return err
+		}
+		strategy := state.MergeStrategy
+		if committed {
+			fmt.Fprintf(deps.Out, "The merge into '%s' was already committed, continuing\n", state.ParentBranch)
+			strategy = ""
+		}
+
+		// Complete the merge/rebase operation based on strategy
+		switch strategy {
+		case "":
+			// Already committed
+		case strategyRebase:
+			// Continue the rebase operation
+			err = deps.Git.RebaseContinue(ctx)
+			if err != nil {
+				// Check if rebase is complete or if there are more commits to rebase
+				if strings.Contains(err.Error(), "No rebase in progress") {
+					// Rebase is already complete, proceed
+				} else if strings.Contains(err.Error(), "conflict") {
+					// More conflicts in subsequent commits
+					return &errors.UnresolvedConflictsError{}
+				} else {
+					return &errors.GitError{Operation: "continue rebase", Err: err}
+				}
+			}
+
+			// After successful rebase, checkout target and merge
+			err = deps.Git.Checkout(state.ParentBranch)
+			if err != nil {
+				return &errors.GitError{Operation: "checkout target branch after rebase", Err: err}
+			}
+			// Use custom merge message if provided (from CLI or saved state), otherwise use default
+			mergeMsg := state.MergeMessage
+			if mergeOptions != nil && mergeOptions.MergeMessage != nil && *mergeOptions.MergeMessage != "" {
+				mergeMsg = *mergeOptions.MergeMessage
+			}
+			if resolvedOptions.FastForwardOnly {
+				err = deps.Git.MergeFastForwardOnly(ctx, state.FullBranchName)
+			} else if mergeMsg != "" {
+				expandedMsg := util.ExpandMessagePlaceholders(mergeMsg, state.FullBranchName, state.ParentBranch)
+				err = deps.Git.MergeWithMessage(ctx, state.FullBranchName, expandedMsg, resolvedOptions.NoFastForward, state.NoVerify)
+			} else {
+				err = deps.Git.MergeWithOptions(ctx, state.FullBranchName, resolvedOptions.NoFastForward, state.NoVerify)
+			}
+			if err != nil {
+				return &errors.GitError{Operation: "merge rebased branch", Err: err}
+			}
+
+		case strategySquash:
+			// For squash merge, commit the staged changes
+			// Use CLI-provided message if given, otherwise use saved state message
+			squashMsg := state.SquashMessage
+			if mergeOptions != nil && mergeOptions.SquashMessage != nil && *mergeOptions.SquashMessage != "" {
+				squashMsg = *mergeOptions.SquashMessage
+			}
+			err = deps.Git.Commit(ctx, squashMsg, state.NoVerify)
+			if err != nil {
+				return &errors.GitError{Operation: "commit squashed changes", Err: err}
+			}
+
+		case strategyMerge:
+			// Complete the merge by committing
+			// Use custom merge message if provided (from CLI or saved state), otherwise use default
+			mergeMsg := state.MergeMessage
+			if mergeOptions != nil && mergeOptions.MergeMessage != nil && *mergeOptions.MergeMessage != "" {
+				mergeMsg = *mergeOptions.MergeMessage
+			}
+			if mergeMsg == "" {
+				mergeMsg = fmt.Sprintf("Merge branch '%s' into %s", state.FullBranchName, state.ParentBranch)
+			} else {
+				mergeMsg = util.ExpandMessagePlaceholders(mergeMsg, state.FullBranchName, state.ParentBranch)
+			}
+			err = deps.Git.Commit(ctx, mergeMsg, state.NoVerify)
+			if err != nil {
+				return &errors.GitError{Operation: "commit merge", Err: err}
+			}
+
+		default:
+			return &errors.GitError{Operation: fmt.Sprintf("unknown merge strategy: %s", state.MergeStrategy), Err: nil}
+		}
+
+		// Move to next step since merge conflicts are resolved and committed
+		state.CurrentStep = mergestate.StepCreateTag
+		if err := deps.State.Save(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+
+	case mergestate.StepUpdateChildren:
+		// For child branch update continuation, check if conflicts are resolved
+		if deps.Git.HasConflicts() {
+			return &errors.UnresolvedConflictsError{}
+		}
+
+		// Determine which child branch we're updating
+		currentChild := state.CurrentChildBranch
+		if currentChild == "" {
+			// Try to determine from current branch
+			currentBranch, err := deps.Git.GetCurrentBranch()
+			if err != nil {
+				return &errors.GitError{Operation: "get current branch", Err: err}
+			}
+			currentChild = currentBranch
+		}
+
+		// Use custom update message if provided (from CLI or saved state), otherwise use default
+		updateMsg := state.UpdateMessage
+		if mergeOptions != nil && mergeOptions.UpdateMessage != nil && *mergeOptions.UpdateMessage != "" {
+			updateMsg = *mergeOptions.UpdateMessage
+		}
+		if err := CompleteChildUpdate(ctx, deps, cfg, state, currentChild, updateMsg); err != nil {
+			return err
+		}
+	}
+
+	return executeSteps(ctx, watcher, deps, cfg, state, branchConfig, resolvedOptions)
+}
+
+// restoreExpectedHead switches back to the branch the finish stopped on when
+// another branch was checked out since. Without changes in the working tree
+// and no Git operation in progress, nothing is lost by switching; otherwise
+// the user is told how to get back.
+func restoreExpectedHead(deps *Deps, state *mergestate.MergeState) error {
+	if state.ExpectedHead == "" {
+		return nil
+	}
+	current, _ := deps.Git.GetCurrentBranch()
+	if deps.Git.IsDetachedHead() {
+		current = ""
+	}
+	if current == state.ExpectedHead {
+		return nil
+	}
+
+	unexpected := &errors.UnexpectedBranchError{Expected: state.ExpectedHead, Current: current, BranchType: state.BranchType, BranchName: state.FullBranchName}
+	if operation, err := deps.Git.GetOperationInProgress(); err != nil || operation != "" {
+		return unexpected
+	}
+	if dirty, err := deps.Git.HasUncommittedChanges(); err != nil || dirty {
+		return unexpected
+	}
+	if err := deps.Git.Checkout(state.ExpectedHead); err != nil {
+		return unexpected
+	}
+	fmt.Fprintf(deps.Out, "Switched back to '%s', where the finish stopped on a conflict\n", state.ExpectedHead)
+	return nil
+}
+
+// committedOutsideFlow reports whether the merge or squash into branch that
+// the finish stopped on was completed with 'git commit' or 'git merge
+// --continue' instead of --continue: no merge is in progress, the working
+// tree is clean and branch moved on from where it was when the step started.
+// A merge that was aborted the same way is an error, since there is nothing
+// to continue.
+func committedOutsideFlow(deps *Deps, state *mergestate.MergeState, branch string) (bool, error) {
+	if state.StepStartCommit == "" || state.MergeStrategy == strategyRebase && state.CurrentStep == mergestate.StepMerge {
+		return false, nil
+	}
+	if operation, err := deps.Git.GetOperationInProgress(); err != nil || operation != "" {
+		return false, nil
+	}
+	if dirty, err := deps.Git.HasUncommittedChanges(); err != nil || dirty {
+		return false, nil
+	}
+	commit, err := deps.Git.BranchCommit(branch)
+	if err != nil {
+		return false, &errors.GitError{Operation: fmt.Sprintf("resolve branch '%s'", branch), Err: err}
+	}
+	if commit == state.StepStartCommit {
+		abort := fmt.Sprintf("git flow %s finish --abort", state.BranchType)
+		if state.Action == "sync-bases" {
+			abort = "git flow sync-bases --abort"
+		}
+		return false, &errors.GitError{
+			Operation: fmt.Sprintf("continue the update of '%s'", branch),
+			Err:       fmt.Errorf("the merge is no longer in progress and nothing was committed; run '%s' and start again", abort),
+		}
+	}
+	return deps.Git.IsAncestor(state.StepStartCommit, branch), nil
+}
+
+// CompleteChildUpdate completes the update of a child branch from
+// state.ParentBranch that stopped on a conflict, once the conflicts are
+// resolved, and marks the child as updated. updateMsg is the custom update
+// message, if any.
+func CompleteChildUpdate(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState, currentChild string, updateMsg string) error {
+	// Get the strategy for this child branch
+	strategy := ""
+	if state.ChildStrategies != nil {
+		strategy = state.ChildStrategies[currentChild]
+	}
+	if strategy == "" {
+		// Fallback: try to get from config
+		if childConfig, ok := cfg.Branches[currentChild]; ok {
+			strategy = childConfig.DownstreamStrategy
+		} else {
+			// Default to merge if we can't determine
+			strategy = "merge"
+		}
+	}
+
+	// An update committed with git directly only needs the state to catch up
+	committed, err := committedOutsideFlow(deps, state, currentChild)
+	if err != nil {
+		return err
+	}
+	if committed {
+		fmt.Fprintf(deps.Out, "The update of '%s' was already committed, continuing\n", currentChild)
+		strategy = ""
+	}
+
+	// Complete the operation based on strategy
+	switch strategy {
+	case "":
+		// Already committed
+	case "rebase":
+		// Continue the rebase operation
+		err = deps.Git.RebaseContinue(ctx)
+		if err != nil {
+			if strings.Contains(err.Error(), "No rebase in progress") {
+				// Rebase might be complete, try to proceed
+				err = nil
+			} else if strings.Contains(err.Error(), "conflict") {
+				// More conflicts in subsequent commits
+				return &errors.UnresolvedConflictsError{}
+			} else {
+				return &errors.GitError{Operation: "continue rebase for child update", Err: err}
+			}
+		}
+
+	case "squash":
+		// Commit the squashed changes
+		if updateMsg == "" {
+			updateMsg = fmt.Sprintf("Update %s: squashed changes from %s",
+				currentChild, state.ParentBranch)
+		} else {
+			// For child updates, the "branch" is the child and "parent" is the source
+			updateMsg = util.ExpandMessagePlaceholders(updateMsg, currentChild, state.ParentBranch)
+		}
+		err = deps.Git.Commit(ctx, updateMsg, state.NoVerifyChildren)
+		if err != nil {
+			return &errors.GitError{Operation: "commit squashed child update", Err: err}
+		}
+
+	default: // "merge" or unknown
+		// Complete the merge
+		if updateMsg == "" && state.MergeTagToChildren && state.TagName != "" {
+			updateMsg = fmt.Sprintf("Merge tag '%s' into %s", state.TagName, currentChild)
+		} else if updateMsg == "" {
+			updateMsg = fmt.Sprintf("Merge branch '%s' into %s",
+				state.ParentBranch, currentChild)
+		} else {
+			// For child updates, the "branch" is the child and "parent" is the source
+			updateMsg = util.ExpandMessagePlaceholders(updateMsg, currentChild, state.ParentBranch)
+		}
+		err = deps.Git.Commit(ctx, updateMsg, state.NoVerifyChildren)
+		if err != nil {
+			return &errors.GitError{Operation: "commit child branch update", Err: err}
+		}
+	}
+
+	// Mark this child as updated if not already done
+	if !state.IsChildUpdated(currentChild) {
+		state.UpdatedBranches = append(state.UpdatedBranches, currentChild)
+	}
+	recordChildCommit(deps, state, currentChild)
+	state.CurrentChildBranch = "" // Clear current child
+
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+func handleAbort(deps *Deps, state *mergestate.MergeState) error {
+	// A merge on another branch checked out since is not ours to abort
+	current, _ := deps.Git.GetCurrentBranch()
+	onOtherBranch := state.ExpectedHead != "" && current != state.ExpectedHead
+
+	// Abort the merge based on strategy
+	var err error
+	switch {
+	case onOtherBranch:
+		fmt.Fprintf(deps.Out, "'%s' is no longer checked out; leaving the working tree of '%s' alone\n", state.ExpectedHead, current)
+	case state.MergeStrategy == strategyMerge:
+		err = deps.Git.MergeAbort()
+	case state.MergeStrategy == strategyRebase:
+		err = deps.Git.RebaseAbort()
+	default:
+		err = deps.Git.MergeAbort() // Default to merge abort
+	}
+
+	if err != nil {
+		return &errors.GitError{Operation: "abort merge", Err: err}
+	}
+
+	// Checkout the original branch
+	if err := deps.Git.Checkout(state.FullBranchName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", state.FullBranchName), Err: err}
+	}
+	restoreUntracked(deps, state, state.FullBranchName)
+
+	// Clear the merge state
+	if err := deps.State.Clear(); err != nil {
+		return &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+
+	// Branches finished earlier in the batch left the child updates to the last one
+	if len(state.Batch) > 0 {
+		fmt.Fprintf(deps.Out, "Not finished from the batch: %s\n", strings.Join(state.Batch, ", "))
+		fmt.Fprintf(deps.Out, "Child base branches of '%s' were not updated; finish the rest or run 'git flow sync-bases'\n", state.ParentBranch)
+	}
+
+	return nil
+}
+
+// =============================================================================
+// STEP HANDLERS (Called by state machine)
+// =============================================================================
+
+// handleMergeStep handles the merge step of the finish operation
+func handleMergeStep(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState, branchConfig config.BranchConfig, resolvedOptions *config.ResolvedFinishOptions) error {
+	// Checkout target branch
+	err := deps.Git.Checkout(state.ParentBranch)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("checkout target branch '%s'", state.ParentBranch), Err: err}
+	}
+	fmt.Fprintf(deps.Out, "Switched to branch '%s'\n", state.ParentBranch)
+
+	if state.AlreadyMerged {
+		fmt.Fprintf(deps.Out, "Branch '%s' is already merged into '%s', skipping the merge\n", state.FullBranchName, state.ParentBranch)
+		state.CurrentStep = mergestate.StepCreateTag
+		if err := deps.State.Save(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return nil
+	}
+
+	// Update state with the resolved strategy (might be different from branch default)
+	state.MergeStrategy = resolvedOptions.MergeStrategy
+	state.StepStartCommit, _ = deps.Git.BranchCommit(state.ParentBranch)
+	state.ExpectedHead = ""
+
+	// Perform merge based on resolved strategy
+	fmt.Fprintf(deps.Out, "Merging using strategy: %v\n", resolvedOptions.MergeStrategy)
+	var mergeErr error
+	switch resolvedOptions.MergeStrategy {
+	case strategyRebase:
+		fmt.Fprintf(deps.Out, "Rebase strategy selected\n")
+		// For rebase, we need to:
+		// 1. Stay on feature branch
+		err = deps.Git.Checkout(state.FullBranchName)
+		if err != nil {
+			return &errors.GitError{Operation: "checkout feature branch for rebase", Err: err}
+		}
+		// 2. Rebase onto target branch with options
+		mergeErr = deps.Git.RebaseWithOptions(ctx, state.ParentBranch, resolvedOptions.PreserveMerges, resolvedOptions.NoVerify)
+		if mergeErr == nil {
+			// 3. If rebase succeeds, checkout target and merge
+			err = deps.Git.Checkout(state.ParentBranch)
+			if err != nil {
+				return &errors.GitError{Operation: "checkout target branch after rebase", Err: err}
+			}
+			// Use custom merge message if provided, otherwise use default
+			if resolvedOptions.FastForwardOnly {
+				mergeErr = deps.Git.MergeFastForwardOnly(ctx, state.FullBranchName)
+			} else if resolvedOptions.MergeMessage != "" {
+				expandedMsg := util.ExpandMessagePlaceholders(resolvedOptions.MergeMessage, state.FullBranchName, state.ParentBranch)
+				mergeErr = deps.Git.MergeWithMessage(ctx, state.FullBranchName, expandedMsg, resolvedOptions.NoFastForward, resolvedOptions.NoVerify)
+			} else {
+				mergeErr = deps.Git.MergeWithOptions(ctx, state.FullBranchName, resolvedOptions.NoFastForward, resolvedOptions.NoVerify)
+			}
+		}
+	case strategySquash:
+		mergeErr = deps.Git.MergeSquashWithMessage(ctx, state.FullBranchName, resolvedOptions.SquashMessage, resolvedOptions.NoVerify)
+	case strategyMerge:
+		if resolvedOptions.FastForwardOnly {
+			mergeErr = deps.Git.MergeFastForwardOnly(ctx, state.FullBranchName)
+		} else if resolvedOptions.MergeMessage != "" {
+			expandedMsg := util.ExpandMessagePlaceholders(resolvedOptions.MergeMessage, state.FullBranchName, state.ParentBranch)
+			mergeErr = deps.Git.MergeWithMessage(ctx, state.FullBranchName, expandedMsg, resolvedOptions.NoFastForward, resolvedOptions.NoVerify)
+		} else {
+			mergeErr = deps.Git.MergeWithOptions(ctx, state.FullBranchName, resolvedOptions.NoFastForward, resolvedOptions.NoVerify)
+		}
+	default:
+		return &errors.GitError{Operation: fmt.Sprintf("unknown merge strategy: %s", resolvedOptions.MergeStrategy), Err: nil}
+	}
+
+	if mergeErr != nil {
+		if strings.Contains(mergeErr.Error(), "conflict") {
+			// Save state before returning conflict error
+			state.CurrentStep = mergestate.StepMerge
+			if resolvedOptions.MergeStrategy != strategyRebase {
+				state.ExpectedHead = state.ParentBranch
+			}
+			if err := deps.State.Save(state); err != nil {
+				return &errors.GitError{Operation: "save merge state", Err: err}
+			}
+
+			// Generate and print detailed conflict message
+			msg := generateConflictMessage(state, cfg, resolvedOptions)
+			fmt.Fprintln(deps.Out, msg)
+			files, _ := deps.Git.UnmergedFiles()
+			deps.Events.Conflict(events.Conflict{Branch: state.ParentBranch, Source: state.FullBranchName, Files: files})
+			return &errors.UnresolvedConflictsError{}
+		}
+		return &errors.GitError{Operation: "merge branch", Err: mergeErr}
+	}
+
+	// Move to next step (tag creation)
+	state.CurrentStep = mergestate.StepCreateTag
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	return nil
+}
+
+// handleCreateTagStep handles the tag creation step
+func handleCreateTagStep(deps *Deps, cfg *config.Config, state *mergestate.MergeState, resolvedOptions *config.ResolvedFinishOptions) error {
+	// The merge is complete; remember its commit for the post-finish hook
+	if commit, err := deps.Git.BranchCommit(state.ParentBranch); err == nil {
+		state.MergeCommit = commit
+	}
+
+	if resolvedOptions.ShouldTag {
+		if err := collectReleaseNotes(deps, cfg, state, resolvedOptions); err != nil {
+			return err
+		}
+		if err := appendReleaseCandidates(deps, state, resolvedOptions); err != nil {
+			return err
+		}
+
+		// Apply tag message filter for any branch type configured with tagging
+		// The filter script (filter-flow-{branchType}-finish-tag-message) decides what to do
+		gitDir, err := deps.Git.GetGitDir()
+		if err != nil {
+			return &errors.GitError{Operation: "get git directory", Err: err}
+		}
+
+		ctx := hooks.FilterContext{
+			BranchType: state.BranchType,
+			BranchName: state.BranchName,
+			Version:    resolvedOptions.TagName,
+			TagMessage: resolvedOptions.TagMessage,
+			BaseBranch: state.ParentBranch,
+			FullBranch: state.FullBranchName,
+			Origin:     cfg.Remote,
+		}
+
+		filteredMessage, err := hooks.RunTagMessageFilter(gitDir, state.BranchType, ctx)
+		if err != nil {
+			return &errors.GitError{Operation: "run tag message filter", Err: err}
+		}
+		if filteredMessage != resolvedOptions.TagMessage {
+			fmt.Fprintf(deps.Out, "Tag message filter modified the message\n")
+			resolvedOptions.TagMessage = filteredMessage
+		}
+
+		if err := createTagForBranchResolved(deps, state, resolvedOptions); err != nil {
+			return err
+		}
+	}
+
+	// Move to next step
+	state.CurrentStep = mergestate.StepUpdateChildren
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// stashUntrackedFor stashes the untracked files that checking out child would
+// overwrite, once for all child updates. Without autostash, the finish stops
+// cleanly before the update so that --continue can try again.
+func stashUntrackedFor(deps *Deps, state *mergestate.MergeState, child string) error {
+	if state.AutostashCommit != "" {
+		return nil
+	}
+	files, err := deps.Git.UntrackedFilesIn(child)
+	if err != nil {
+		return &errors.GitError{Operation: "check untracked files", Err: err}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	if !state.AutostashUntracked {
+		// Nothing of the update has started, so --continue starts it afresh
+		state.Interrupted = true
+		if err := deps.State.Save(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return &errors.UntrackedFilesError{ChildBranch: child, Files: files, BranchType: state.BranchType, BranchName: state.FullBranchName, Continue: true}
+	}
+
+	commit, err := deps.Git.StashUntracked(fmt.Sprintf("git-flow: untracked files stashed while finishing %s", state.FullBranchName))
+	if err != nil {
+		return &errors.GitError{Operation: "stash untracked files", Err: err}
+	}
+	state.AutostashCommit = commit
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	fmt.Fprintf(deps.Out, "Stashed untracked files that checking out '%s' would overwrite: %s\n", child, strings.Join(files, ", "))
+	return nil
+}
+
+// restoreUntracked restores the untracked files stashed for the child updates
+// on branch. A failure only warns, since the files are still in the stash.
+func restoreUntracked(deps *Deps, state *mergestate.MergeState, branch string) {
+	if state.AutostashCommit == "" {
+		return
+	}
+	commit := state.AutostashCommit
+	state.AutostashCommit = ""
+	if err := deps.Git.Checkout(branch); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Could not restore the stashed untracked files: %v\nThey are kept in stash %s; restore them with 'git stash apply %s'\n", err, git.ShortCommit(commit), git.ShortCommit(commit))
+		return
+	}
+	if err := deps.Git.RestoreStash(commit); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Could not restore the stashed untracked files: %v\nThey are kept in stash %s; restore them with 'git stash apply %s'\n", err, git.ShortCommit(commit), git.ShortCommit(commit))
+		return
+	}
+	fmt.Fprintf(deps.Out, "Restored the stashed untracked files on '%s'\n", branch)
+}
+
+// handleUpdateChildrenStep handles updating child base branches
+func handleUpdateChildrenStep(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState, branchConfig config.BranchConfig, resolvedOptions *config.ResolvedFinishOptions) error {
+	// Find next child branch to update
+	nextBranch := findNextBranchToUpdate(state)
+
+	// If no more branches to update, move to the extra tags
+	if nextBranch == "" {
+		restoreUntracked(deps, state, state.ParentBranch)
+		state.CurrentStep = mergestate.StepExtraTags
+		if err := deps.State.Save(state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		return nil
+	}
+
+	if err := stashUntrackedFor(deps, state, nextBranch); err != nil {
+		return err
+	}
+
+	// Update the next child branch
+	if err := updateChildBranch(ctx, deps, cfg, nextBranch, state); err != nil {
+		return err
+	}
+
+	// Mark this branch as updated and clear current child
+	state.UpdatedBranches = append(state.UpdatedBranches, nextBranch)
+	recordChildCommit(deps, state, nextBranch)
+	state.CurrentChildBranch = "" // Clear after successful update
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	// Continue with next branch
+	return nil
+}
+
+// handleExtraTagsStep points the extra tags at their target branches
+func handleExtraTagsStep(deps *Deps, state *mergestate.MergeState) error {
+	if len(state.ExtraTags) > 0 {
+		targets := make(map[string]string, len(state.ExtraTags))
+		for _, tag := range state.ExtraTags {
+			targets[tag.Name] = tag.Target
+		}
+		if err := deps.Git.MoveTags(targets); err != nil {
+			return &errors.GitError{Operation: "move extra tags", Err: err}
+		}
+		for _, tag := range state.ExtraTags {
+			fmt.Fprintf(deps.Out, "Moved tag '%s' to '%s'\n", tag.Name, tag.Target)
+		}
+	}
+
+	// Move to next step
+	state.CurrentStep = mergestate.StepPush
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// handlePushStep pushes the parent branch, the updated child branches and the
+// created tags in one atomic push. Extra tags are force-pushed since they move.
+// With only PushTag set, just the created tag is pushed.
+func handlePushStep(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState) error {
+	if !state.Push && state.PushTag && state.TagName != "" {
+		fmt.Fprintf(deps.Out, "Pushing tag '%s' to remote '%s'...\n", state.TagName, cfg.Remote)
+		if err := deps.Remote.PushRefs(ctx, cfg.Remote, []string{tagRefspec(state)}, nil); err != nil {
+			return &errors.GitError{Operation: "push tag", Err: fmt.Errorf("%w; fix the problem and run 'git flow %s finish --continue %s' to retry", err, state.BranchType, state.BranchName)}
+		}
+		fmt.Fprintf(deps.Out, "Pushed tag '%s' to '%s'\n", state.TagName, cfg.Remote)
+	}
+
+	if state.Push {
+		var tagRefspecs []string
+		if state.TagName != "" {
+			tagRefspecs = append(tagRefspecs, tagRefspec(state))
+		}
+		for _, tag := range state.ExtraTags {
+			tagRefspecs = append(tagRefspecs, "+refs/tags/"+tag.Name)
+		}
+		branches := append([]string{state.ParentBranch}, state.UpdatedBranches...)
+		refspecs, leased, err := pushRefspecs(deps, cfg.Remote, branches, tagRefspecs, state.ForcePushBranches)
+		if err != nil {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("%v; fix the problem and run 'git flow %s finish --continue %s' to retry", err, state.BranchType, state.BranchName)}
+		}
+
+		if len(refspecs) > 0 {
+			fmt.Fprintf(deps.Out, "Pushing to remote '%s'...\n", cfg.Remote)
+			// Rebased child branches replace their remote branch, unless it moved since the fetch
+			if err := deps.Remote.PushRefs(ctx, cfg.Remote, refspecs, leased); err != nil {
+				return &errors.GitError{Operation: "push finished branches and tags", Err: fmt.Errorf("%w; fix the problem and run 'git flow %s finish --continue %s' to retry", err, state.BranchType, state.BranchName)}
+			}
+			fmt.Fprintf(deps.Out, "Pushed to '%s'\n", cfg.Remote)
+		}
+	}
+
+	// Move to final step
+	state.CurrentStep = mergestate.StepDeleteBranch
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// pushRefspecs returns the refspecs finish pushes to remote: each branch is
+// pushed to the branch push.default selects, and the tags as given. Refs a
+// negative refspec of the remote excludes are left out with a note. leased
+// branches, which may be rewritten, are returned as the remote branches they
+// are pushed to.
+func pushRefspecs(deps *Deps, remote string, branches []string, tagRefspecs []string, leased []string) ([]string, []string, error) {
+	var refspecs, remoteLeased []string
+	for _, branch := range branches {
+		if refspec := deps.Git.PushExclusion(remote, "refs/heads/"+branch); refspec != "" {
+			fmt.Fprintf(deps.Out, "Not pushing '%s': excluded by '%s' in remote.%s.push\n", branch, refspec, remote)
+			continue
+		}
+		remoteBranch, err := deps.Git.PushDestination(branch, remote)
+		if err != nil {
+			return nil, nil, err
+		}
+		if remoteBranch == branch {
+			refspecs = append(refspecs, branch)
+		} else {
+			refspecs = append(refspecs, branch+":refs/heads/"+remoteBranch)
+		}
+		if slices.Contains(leased, branch) {
+			remoteLeased = append(remoteLeased, remoteBranch)
+		}
+	}
+	for _, refspec := range tagRefspecs {
+		ref := strings.TrimPrefix(refspec, "+")
+		if exclusion := deps.Git.PushExclusion(remote, ref); exclusion != "" {
+			fmt.Fprintf(deps.Out, "Not pushing '%s': excluded by '%s' in remote.%s.push\n", strings.TrimPrefix(ref, "refs/tags/"), exclusion, remote)
+			continue
+		}
+		refspecs = append(refspecs, refspec)
+	}
+	return refspecs, remoteLeased, nil
+}
+
+// pushToMirrors pushes what finish pushed to the remote to the mirror remotes
+// of gitflow.mirror.remotes as well, each in its own atomic push, and prints
+// a summary. A failed mirror is reported and skipped; the returned error names
+// the first failed mirror that is required.
+func pushToMirrors(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState) error {
+	mirrors := config.ResolveMirrors(cfg)
+	if len(mirrors) == 0 || !state.Push && !(state.PushTag && state.TagName != "") {
+		return nil
+	}
+
+	var branches, tagRefspecs []string
+	if state.TagName != "" {
+		tagRefspecs = append(tagRefspecs, tagRefspec(state))
+	}
+	if state.Push {
+		branches = append([]string{state.ParentBranch}, state.UpdatedBranches...)
+		for _, tag := range state.ExtraTags {
+			tagRefspecs = append(tagRefspecs, "+refs/tags/"+tag.Name)
+		}
+	}
+
+	var requiredErr error
+	var summary strings.Builder
+	for _, mirror := range mirrors {
+		fmt.Fprintf(deps.Out, "Pushing to mirror '%s'...\n", mirror.Remote)
+		refspecs, err := pushToMirror(ctx, deps, mirror.Remote, branches, tagRefspecs, state.ForcePushBranches)
+		reason, _, _ := strings.Cut(fmt.Sprint(err), "\n")
+		switch {
+		case err == nil && len(refspecs) == 0:
+			fmt.Fprintf(&summary, "  ✓ %s: nothing to push\n", mirror.Remote)
+		case err == nil:
+			fmt.Fprintf(&summary, "  ✓ %s: %s\n", mirror.Remote, strings.Join(refspecNames(refspecs), ", "))
+		case mirror.Required:
+			fmt.Fprintf(&summary, "  ✗ %s: %s\n", mirror.Remote, reason)
+			if requiredErr == nil {
+				operation := fmt.Sprintf("push to the required mirror '%s' ('%s' is finished)", mirror.Remote, state.FullBranchName)
+				if len(refspecs) > 0 {
+					operation = fmt.Sprintf("push to the required mirror '%s' ('%s' is finished; retry with 'git push %s %s')", mirror.Remote, state.FullBranchName, mirror.Remote, strings.Join(refspecs, " "))
+				}
+				requiredErr = &errors.GitError{Operation: operation, Err: err}
+			}
+		default:
+			fmt.Fprintf(&summary, "  ! %s: %s (not required, skipped)\n", mirror.Remote, reason)
+		}
+	}
+	fmt.Fprintf(deps.Out, "Mirrors:\n%s", summary.String())
+	return requiredErr
+}
+
+// pushToMirror pushes branches and tags to the mirror remote, leaving out refs
+// its negative push refspecs exclude. Branches in forced were rebased and
+// replace their copy on the mirror. It returns the refspecs it pushed, or
+// tried to push.
+func pushToMirror(ctx context.Context, deps *Deps, remote string, branches []string, tagRefspecs []string, forced []string) ([]string, error) {
+	if !deps.Git.RemoteExists(remote) {
+		return nil, fmt.Errorf("remote '%s' is not configured", remote)
+	}
+	refspecs, _, err := pushRefspecs(deps, remote, branches, tagRefspecs, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, refspec := range refspecs {
+		branch, _, _ := strings.Cut(refspec, ":")
+		if slices.Contains(forced, branch) {
+			refspecs[i] = "+" + refspec
+		}
+	}
+	if len(refspecs) == 0 {
+		return nil, nil
+	}
+	return refspecs, deps.Remote.PushRefs(ctx, remote, refspecs, nil)
+}
+
+// refspecNames returns the branch and tag names refspecs push
+func refspecNames(refspecs []string) []string {
+	names := make([]string, 0, len(refspecs))
+	for _, refspec := range refspecs {
+		name, _, _ := strings.Cut(strings.TrimPrefix(refspec, "+"), ":")
+		names = append(names, strings.TrimPrefix(name, "refs/tags/"))
+	}
+	return names
+}
+
+// tagRefspec returns the refspec pushing the created tag, forced when it was
+// moved with --retag
+func tagRefspec(state *mergestate.MergeState) string {
+	if state.Retagged {
+		return "+refs/tags/" + state.TagName
+	}
+	return "refs/tags/" + state.TagName
+}
+
+// handleDeleteBranchStep handles branch deletion
+func handleDeleteBranchStep(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState, resolvedOptions *config.ResolvedFinishOptions) error {
+	// Ensure we're on the parent branch before deletion
+	if err := deps.Git.Checkout(state.ParentBranch); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("checkout parent branch '%s'", state.ParentBranch), Err: err}
+	}
+
+	// Apply keep logic: if keep is set, it overrides individual settings
+	keepRemote := resolvedOptions.KeepRemote
+	keepLocal := resolvedOptions.KeepLocal
+	if resolvedOptions.Keep {
+		keepRemote = true
+		keepLocal = true
+	}
+
+	// Delete branches based on settings
+	// Use force delete since we've already merged the branch
+	forceDelete := true
+	if err := deleteBranchesIfNeeded(ctx, deps, state, cfg.Remote, keepRemote, keepLocal, forceDelete); err != nil {
+		return err
+	}
+
+	// Clean up base branch configuration if branch was deleted
+	if !keepLocal {
+		configKey := config.BaseKey(state.FullBranchName)
+		if err := deps.Config.Unset(configKey); err != nil {
+			fmt.Fprintf(deps.Err, "Warning: Failed to clean up base config: %v\n", err)
+		}
+		if err := deps.Git.ClearReleaseCandidates(state.FullBranchName); err != nil {
+			fmt.Fprintf(deps.Err, "Warning: Failed to clean up the recorded release candidates: %v\n", err)
+		}
+	}
+
+	// Clear the merge state
+	if err := deps.State.Clear(); err != nil {
+		return &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+
+	fmt.Fprintf(deps.Out, "Successfully finished branch '%s' and updated %d child base branches\n", state.FullBranchName, len(state.UpdatedBranches))
+	recordSkippedUpdates(deps, state)
+	endStabilization(deps, cfg, state.FullBranchName)
+	if state.TagName != "" {
+		deps.result("%s", state.TagName)
+	}
+	reportFinishToActions(state)
+	mirrorErr := pushToMirrors(ctx, deps, cfg, state)
+	backportFinished(ctx, deps, cfg, state)
+
+	// Run post-hook after successful completion
+	gitDir, err := deps.Git.GetGitDir()
+	if err == nil {
+		hookCtx := hooks.HookContext{
+			BranchType: state.BranchType,
+			BranchName: state.BranchName,
+			FullBranch: state.FullBranchName,
+			BaseBranch: state.ParentBranch,
+			Origin:     cfg.Remote,
+			ExitCode:   0, // Success
+
+			ReleaseNotesFile: state.ReleaseNotesFile,
+			Finish:           finishResult(state),
+		}
+		// Set version for branches configured with tagging
+		if resolvedOptions.ShouldTag {
+			hookCtx.Version = state.BranchName
+		}
+
+		result := hooks.RunPostHook(gitDir, state.BranchType, hooks.HookActionFinish, hookCtx)
+		if result.Executed && result.Output != "" {
+			fmt.Fprint(deps.Out, result.Output)
+		}
+		hooks.Notify(gitDir, hooks.BranchEvent(hooks.HookActionFinish, hookCtx))
+	}
+
+	return mirrorErr
+}
+
+// =============================================================================
+// HELPER FUNCTIONS (Called by step handlers and main flow)
+// =============================================================================
+
+// resolveBranchName finds the branch to finish for the short or full name.
+// A branch without the prefix of the type is only used when there is no
+// branch with it; finishing it asks for confirmation.
+func resolveBranchName(deps *Deps, cfg *config.Config, branchType string, name string) (string, error) {
+	fullName, _, err := ResolveTopicName(deps, cfg, branchType, name)
+	if err != nil {
+		return "", err
+	}
+	if localBranchExists(deps, fullName) {
+		return fullName, nil
+	}
+	if localBranchExists(deps, name) {
+		return name, nil
+	}
+	return "", &errors.BranchNotFoundError{BranchName: name}
+}
+
+// verifyBaseSignature checks the signature of the tip of targetBranch when
+// gitflow.finish.verifyBaseSignature is enabled. It returns the checked signature,
+// or nil when the check is disabled or the signature could not be read. A
+// signature that fails the check is returned with a BaseSignatureError.
+func verifyBaseSignature(deps *Deps, cfg *config.Config, branchType string, targetBranch string) (*git.CommitSignature, error) {
+	options := config.ResolveBaseSignature(cfg, branchType)
+	if !options.Verify {
+		return nil, nil
+	}
+
+	commit, err := deps.Git.BranchCommit(targetBranch)
+	if err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("resolve base branch '%s'", targetBranch), Err: err}
+	}
+	signature, err := deps.Git.GetCommitSignature(commit)
+	if err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("verify signature of '%s'", targetBranch), Err: err}
+	}
+
+	if reason := signatureProblem(signature, options.AllowedKeys); reason != "" {
+		return signature, &errors.BaseSignatureError{BaseBranch: targetBranch, Commit: commit, Reason: reason}
+	}
+	fmt.Fprintf(deps.Out, "Verified signature of '%s' (key %s)\n", targetBranch, signature.Key)
+	return signature, nil
+}
+
+// signatureProblem describes why signature fails the base branch check, or
+// returns "" if it passes. A good signature of an untrusted key only passes
+// when the key is one of allowedKeys.
+func signatureProblem(signature *git.CommitSignature, allowedKeys []string) string {
+	switch signature.Status {
+	case "G", "U":
+	case "N":
+		return "is missing"
+	case "B":
+		return "is bad"
+	case "X":
+		return "has expired"
+	case "Y":
+		return fmt.Sprintf("was made by the expired key %s", signature.Key)
+	case "R":
+		return fmt.Sprintf("was made by the revoked key %s", signature.Key)
+	case "E":
+		return "can't be checked; the signing key may be missing or gpg.ssh.allowedSignersFile not set"
+	default:
+		return fmt.Sprintf("has the unknown status '%s'", signature.Status)
+	}
+
+	if len(allowedKeys) == 0 {
+		if signature.Status == "U" {
+			return fmt.Sprintf("was made by the untrusted key %s", signature.Key)
+		}
+		return ""
+	}
+	for _, allowed := range allowedKeys {
+		for _, key := range []string{signature.Key, signature.Fingerprint, signature.PrimaryFingerprint} {
+			if key != "" && strings.EqualFold(key, allowed) {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("was made by the key %s, which is not in allowedSigningKeys", signature.Key)
+}
+
+// checkTopicUpToDate refuses to finish a topic branch that is behind or diverged
+// from its remote counterpart, which would merge a stale branch. Without a
+// tracking branch, the branch of the same name on the remote is compared.
+// With gitflow.finish.requireUpToDateTopic disabled, only a warning is printed.
+func checkTopicUpToDate(deps *Deps, cfg *config.Config, branchType string, name string) error {
+	untracked := false
+	remoteBranch, err := deps.Git.GetTrackingBranch(name)
+	if err != nil {
+		// No tracking branch and nothing on the remote - nothing to compare against
+		if cfg.Remote == "" || !deps.Git.RemoteBranchExists(cfg.Remote, name) {
+			return nil
+		}
+		remoteBranch = cfg.Remote + "/" + name
+		untracked = true
+	}
+
+	status, commitCount, err := deps.Git.CompareBranches(name, remoteBranch)
+	if err != nil {
+		return nil
+	}
+
+	switch status {
+	case git.SyncStatusBehind, git.SyncStatusDiverged:
+		if !config.ResolveRequireUpToDateTopic(cfg, branchType) {
+			fmt.Fprintf(deps.Out, "Warning: Local branch '%s' is %s compared to '%s' (%d commit(s)); finishing anyway since requireUpToDateTopic is disabled\n", name, status, remoteBranch, commitCount)
+			return nil
+		}
+		return &errors.BranchBehindRemoteError{
+			BranchName:   name,
+			RemoteBranch: remoteBranch,
+			CommitCount:  commitCount,
+			BranchType:   branchType,
+			Untracked:    untracked,
+		}
+	case git.SyncStatusAhead:
+		// Local is ahead - proceed with a note
+		fmt.Fprintf(deps.Out, "Note: Local branch is %d commit(s) ahead of remote\n", commitCount)
+	}
+	// SyncStatusEqual - proceed normally
+	return nil
+}
+
+// resolveFinishBase picks the branch to finish into. An explicit --to target
+// wins; otherwise the gitflow.finish.baseResolution policy decides. It returns
+// the chosen branch and a short description of where it came from.
+func resolveFinishBase(deps *Deps, cfg *config.Config, branchType string, name string, branchConfig config.BranchConfig, to string) (string, string, error) {
+	if to != "" {
+		return to, "--to", nil
+	}
+
+	policy, key := config.ResolveBaseResolution(cfg, branchType)
+	switch policy {
+	case config.BaseResolutionConfigured, config.BaseResolutionStored, config.BaseResolutionPrompt:
+	default:
+		return "", "", &errors.InvalidConfigValueError{
+			Key:     key,
+			Value:   policy,
+			Allowed: []string{config.BaseResolutionConfigured, config.BaseResolutionStored, config.BaseResolutionPrompt},
+		}
+	}
+
+	// Without a differing stored base there is nothing to choose between
+	configured := branchConfig.Parent
+	stored, err := deps.Git.GetBaseBranch(name)
+
+	// Branches started while a release is stabilized go back into it
+	if err == nil && stored != "" && stored == ActiveStabilization(deps, cfg) {
+		return stored, "stabilization", nil
+	}
+
+	// Releases and hotfixes of a version line go back into the line's base
+	if err == nil && stored != configured {
+		if line, ok := config.VersionLineOfBase(cfg, stored); ok {
+			return stored, fmt.Sprintf("version line %s", line.Name), nil
+		}
+	}
+
+	// A topic branch base, such as the release branch a bugfix was started from,
+	// is where the branch belongs while it exists
+	if err == nil && IsTopicBase(deps, cfg, branchType, stored) && stored != configured {
+		return stored, "stored topic base", nil
+	}
+	if err != nil || stored == "" || stored == configured || policy == config.BaseResolutionConfigured {
+		return configured, "configured parent", nil
+	}
+
+	if policy == config.BaseResolutionPrompt {
+		response := deps.Prompter.Ask(fmt.Sprintf("Branch '%s' was started from '%s', but %s branches are configured to finish into '%s'.\n"+
+			"Finish into [c]onfigured parent '%s' or [s]tored base '%s'? [C/s]: ", name, stored, branchType, configured, configured, stored))
+		if strings.ToLower(response) != "s" {
+			return configured, "configured parent", nil
+		}
+	}
+
+	return stored, "stored base", nil
+}
+
+// IsTopicBase reports whether stored is an existing topic branch that branches
+// of branchType may be based on
+func IsTopicBase(deps *Deps, cfg *config.Config, branchType string, stored string) bool {
+	return stored != "" && config.ResolveAllowTopicBase(cfg, branchType) && config.TopicBranchType(cfg, stored) != "" && deps.Git.BranchExists(stored) == nil
+}
+
+// detectAlreadyMerged reports whether the finish can skip the merge because
+// the branch is already merged into target, as its tip or as equivalent
+// changes. With ifMerged that is decided without asking. Otherwise the user is
+// asked for branches whose commits were brought in by a merge commit or a
+// rebase; a branch on target's first-parent history, such as one without
+// commits of its own, finishes as usual.
+func detectAlreadyMerged(deps *Deps, branch string, target string, ifMerged bool) bool {
+	contained := deps.Git.IsAncestor(branch, target)
+	if !contained && !deps.Git.ChangesApplied(branch, target) {
+		return false
+	}
+	if ifMerged {
+		fmt.Fprintf(deps.Out, "Branch '%s' is already merged into '%s'\n", branch, target)
+		return true
+	}
+	if contained && deps.Git.IsFirstParentAncestor(branch, target) {
+		return false
+	}
+
+	return deps.Prompter.Confirm(fmt.Sprintf("Branch '%s' is already merged into '%s'.\nSkip the merge and only tag and clean up? [y/N]: ", branch, target), false)
+}
+
+// editFinishMessages opens the squash or merge commit message and the tag
+// message in the editor, like git commit --edit, and stores the results in
+// options. An empty message aborts the finish. The commit message is only
+// edited when merge is set.
+func editFinishMessages(deps *Deps, options *config.ResolvedFinishOptions, branch string, parent string, merge bool) error {
+	strategy := options.MergeStrategy
+	if !merge {
+		strategy = ""
+	}
+	switch strategy {
+	case strategySquash:
+		message, err := editMessage(deps, "SQUASH_EDITMSG", "squash commit", options.SquashMessage,
+			fmt.Sprintf("Please enter the commit message for squashing '%s' into '%s'.", branch, parent))
+		if err != nil {
+			return err
+		}
+		options.SquashMessage = message
+	case strategyMerge, strategyRebase:
+		// A fast-forward-only finish never creates a merge commit
+		if options.FastForwardOnly {
+			break
+		}
+		template := fmt.Sprintf("Merge branch '%s' into %s", branch, parent)
+		if options.MergeMessage != "" {
+			template = util.ExpandMessagePlaceholders(options.MergeMessage, branch, parent)
+		}
+		message, err := editMessage(deps, "MERGE_EDITMSG", "merge commit", template,
+			fmt.Sprintf("Please enter the merge commit message for merging '%s' into '%s'.", branch, parent),
+			"The message is only used if the merge is not a fast-forward.")
+		if err != nil {
+			return err
+		}
+		options.MergeMessage = message
+	}
+
+	if options.ShouldTag {
+		template := options.TagMessage
+		if options.MessageFile != "" {
+			content, err := os.ReadFile(options.MessageFile)
+			if err != nil {
+				return &errors.InvalidInputError{Message: fmt.Sprintf("cannot read tag message file '%s': %v", options.MessageFile, err)}
+			}
+			template = string(content)
+		}
+		message, err := editMessage(deps, "TAG_EDITMSG", "tag", template,
+			fmt.Sprintf("Please enter the message for tag '%s'.", options.TagName))
+		if err != nil {
+			return err
+		}
+		options.TagMessage = message
+		options.MessageFile = ""
+	}
+	return nil
+}
+
+// editMessage opens template followed by the commented instructions in the
+// editor and returns the edited message. kind names the message in errors.
+func editMessage(deps *Deps, file string, kind string, template string, instructions ...string) (string, error) {
+	var content strings.Builder
+	content.WriteString(strings.TrimRight(template, "\n"))
+	content.WriteString("\n\n")
+	for _, line := range instructions {
+		content.WriteString("# " + line + "\n")
+	}
+	content.WriteString("# Lines starting with '#' will be ignored, and an empty message aborts the finish.\n")
+
+	message, err := deps.Git.EditMessage(file, content.String())
+	if err != nil {
+		return "", &errors.GitError{Operation: fmt.Sprintf("edit the %s message", kind), Err: err}
+	}
+	if message == "" {
+		return "", &errors.EmptyMessageError{Kind: kind}
+	}
+	return message, nil
+}
+
+// editedTagMessage returns the tag message to keep for --continue when it was
+// written in the editor
+func editedTagMessage(mergeOptions *config.MergeStrategyOptions, options *config.ResolvedFinishOptions) string {
+	if mergeOptions == nil || !mergeOptions.Edit || !options.ShouldTag {
+		return ""
+	}
+	return options.TagMessage
+}
+
+// checkBackMerges warns about or refuses merging a branch that merged its
+// target in more often than gitflow.finish.maxBackMerges allows. Rebasing
+// without preserving merges and squashing drop the merges and are not checked.
+func checkBackMerges(deps *Deps, cfg *config.Config, branchType, branch, target string, options *config.ResolvedFinishOptions) error {
+	if options.MergeStrategy == strategySquash || options.MergeStrategy == strategyRebase && !options.PreserveMerges {
+		return nil
+	}
+	policy, err := config.ResolveFinishBackMerges(cfg, branchType)
+	if err != nil {
+		return err
+	}
+	if policy.Action == config.BackMergesAllow {
+		return nil
+	}
+	merges, err := deps.Git.BackMerges(branch, target)
+	if err != nil {
+		return &errors.GitError{Operation: "look for back-merges", Err: err}
+	}
+	if len(merges) <= policy.Max {
+		return nil
+	}
+	backMergesErr := &errors.BackMergesError{BranchType: branchType, BranchName: branch, TargetBranch: target, Count: len(merges), Max: policy.Max}
+	if policy.Action == config.BackMergesRefuse {
+		return backMergesErr
+	}
+	fmt.Fprintf(deps.Err, "Warning: '%s' merged '%s' in %d times; merging it adds these merges to '%s'.\n%s\n",
+		branch, target, len(merges), target, backMergesErr.Hint())
+	return nil
+}
+
+// printFinishSummary prints the commits, files and lines branch brings into
+// target, colored like git diff --stat when color.diff allows it
+func printFinishSummary(deps *Deps, branch, target string) {
+	ahead, _, err := deps.Git.AheadBehind(branch, target)
+	if err != nil {
+		fmt.Fprintf(deps.Err, "Warning: could not summarize '%s': %v\n", branch, err)
+		return
+	}
+	stat, err := deps.Git.DiffShortStat(target, branch)
+	if err != nil {
+		fmt.Fprintf(deps.Err, "Warning: could not summarize '%s': %v\n", branch, err)
+		return
+	}
+	insertions := fmt.Sprintf("+%d", stat.Insertions)
+	deletions := fmt.Sprintf("-%d", stat.Deletions)
+	if deps.Git.ColorEnabled("color.diff", isTerminal(deps.Out)) {
+		insertions = "\033[32m" + insertions + "\033[m"
+		deletions = "\033[31m" + deletions + "\033[m"
+	}
+	fmt.Fprintf(deps.Out, "Merging '%s' into '%s': %d commit(s), %d file(s) changed, %s/%s lines\n",
+		branch, target, ahead, stat.Files, insertions, deletions)
+}
+
+// amendFinishOptions resolves the options again after the pre-hook amended the
+// configuration. The messages were expanded, amended with trailers or edited
+// before the hook ran and are kept, as is the fast-forward check already done.
+func amendFinishOptions(cfg *config.Config, branchType string, shortName string, resolved *config.ResolvedFinishOptions, options FinishOptions) *config.ResolvedFinishOptions {
+	amended := options.resolve(cfg, branchType, shortName)
+	amended.TagMessage = resolved.TagMessage
+	amended.MergeMessage = resolved.MergeMessage
+	amended.SquashMessage = resolved.SquashMessage
+	amended.UpdateMessage = resolved.UpdateMessage
+	amended.NoFastForward = amended.NoFastForward || resolved.NoFastForward
+	amended.FastForwardOnly = resolved.FastForwardOnly
+	return amended
+}
+
+// createTagForBranchResolved creates a tag using resolved options
+func createTagForBranchResolved(deps *Deps, state *mergestate.MergeState, options *config.ResolvedFinishOptions) error {
+	// Determine if we should use message file
+	useMessageFile := options.MessageFile != ""
+
+	// Create the tag using the git module
+	gitTagOptions := &git.TagOptions{
+		Message:     options.TagMessage,
+		MessageFile: options.MessageFile,
+		Sign:        options.ShouldSign,
+		SigningKey:  options.SigningKey,
+	}
+
+	// Use MessageFile if specified, otherwise use Message
+	if useMessageFile {
+		gitTagOptions.Message = "" // Clear message since we're using file
+	} else {
+		gitTagOptions.MessageFile = "" // Clear file since we're using message
+	}
+
+	// The tag may exist from an earlier run of this finish, or from before it
+	if deps.Git.TagExists(options.TagName) {
+		tagCommit, err := deps.Git.TagCommit(options.TagName)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("resolve tag '%s'", options.TagName), Err: err}
+		}
+		switch {
+		case tagCommit == state.MergeCommit:
+			fmt.Fprintf(deps.Out, "Tag '%s' already points at '%s'\n", options.TagName, state.ParentBranch)
+			state.TagName = options.TagName
+			return nil
+		case options.ExistingTag == config.ExistingTagSkip:
+			fmt.Fprintf(deps.Out, "Keeping existing tag '%s' at %s; no tag is created\n", options.TagName, git.ShortCommit(tagCommit))
+			return nil
+		case options.ExistingTag == config.ExistingTagRetag:
+			gitTagOptions.Force = true
+			if err := deps.Git.CreateTag(options.TagName, gitTagOptions); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("move tag '%s'", options.TagName), Err: err}
+			}
+			fmt.Fprintf(deps.Out, "Moved tag '%s' from %s to '%s'\n", options.TagName, git.ShortCommit(tagCommit), state.ParentBranch)
+			deps.Events.TagCreated(events.TagCreated{Tag: options.TagName, Branch: state.ParentBranch, Moved: true})
+			state.TagName = options.TagName
+			state.Retagged = true
+			return nil
+		default:
+			return &errors.TagExistsError{TagName: options.TagName, BranchType: state.BranchType, BranchName: state.BranchName, Continue: true}
+		}
+	}
+
+	if err := deps.Git.CreateTag(options.TagName, gitTagOptions); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("create tag '%s'", options.TagName), Err: err}
+	}
+	fmt.Fprintf(deps.Out, "Created tag '%s'\n", options.TagName)
+	deps.Events.TagCreated(events.TagCreated{Tag: options.TagName, Branch: state.ParentBranch})
+	state.TagName = options.TagName
+	return nil
+}
+
+// parseChildStrategies parses the --child-strategy overrides of the form
+// <branch>=<strategy> into a map from child branch to strategy
+func parseChildStrategies(mergeOptions *config.MergeStrategyOptions) (map[string]string, error) {
+	overrides := make(map[string]string)
+	if mergeOptions == nil {
+		return overrides, nil
+	}
+	for _, spec := range mergeOptions.ChildStrategy {
+		branch, strategy, found := strings.Cut(spec, "=")
+		if !found || branch == "" {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("--child-strategy '%s' must have the form <branch>=<strategy>", spec)}
+		}
+		strategy = strings.ToLower(strategy)
+		switch strategy {
+		case strategyMerge, strategyRebase, strategySquash:
+		default:
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("--child-strategy '%s': strategy must be merge, rebase or squash", spec)}
+		}
+		overrides[branch] = strategy
+	}
+	return overrides, nil
+}
+
+// recordSkippedUpdates records the child updates skipped with --no-update in
+// the journal, so they can be made up for later
+func recordSkippedUpdates(deps *Deps, state *mergestate.MergeState) {
+	if len(state.SkippedBranches) == 0 {
+		return
+	}
+	entry := journal.Entry{Operation: journal.OperationFinish, Branch: state.FullBranchName}
+	for _, branchName := range state.SkippedBranches {
+		entry.SkippedUpdates = append(entry.SkippedUpdates, journal.Update{Branch: branchName, Parent: state.ParentBranch})
+	}
+	if err := journal.Append(entry); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to record the skipped updates: %v\n", err)
+		return
+	}
+	fmt.Fprintf(deps.Out, "Not updated from '%s': %s\n", state.ParentBranch, strings.Join(state.SkippedBranches, ", "))
+}
+
+// EffectiveChildStrategy returns the strategy a child branch update applies;
+// anything but rebase and squash, including an unset strategy, merges
+func EffectiveChildStrategy(strategy string) string {
+	switch strings.ToLower(strategy) {
+	case strategyRebase, strategySquash:
+		return strings.ToLower(strategy)
+	}
+	return strategyMerge
+}
+
+// resolveExtraTags returns the extra tag specs for a finish: the multi-valued
+// gitflow.<type>.finish.extra-tag config followed by any --extra-tag values.
+// --no-extra-tags suppresses all of them.
+func resolveExtraTags(deps *Deps, branchType string, tagOptions *config.TagOptions) []string {
+	if tagOptions != nil && tagOptions.NoExtraTags {
+		return nil
+	}
+
+	var specs []string
+
+	// Layer 2: Load from git config (multi-value key)
+	configKey := config.CommandKey(branchType, config.CommandFinish, config.OptExtraTag)
+	if configSpecs, err := deps.Config.GetAll(configKey); err == nil {
+		specs = append(specs, configSpecs...)
+	}
+
+	// Layer 3: CLI flags add to config defaults
+	if tagOptions != nil {
+		specs = append(specs, tagOptions.ExtraTags...)
+	}
+
+	return specs
+}
+
+// resolveTrailers returns the trailers of the merge or squash commit, as
+// "<key>: <value>" lines. Templates configured in gitflow.<type>.finish.trailer
+// come first; one without a value is required and takes the value of the
+// --trailer with the same key, or asks for it. --trailer values replace the
+// template of their key and the others are added at the end.
+func resolveTrailers(deps *Deps, branchType string, mergeOptions *config.MergeStrategyOptions, branch, parent, version string, options *config.ResolvedFinishOptions) ([]string, error) {
+	var templates []string
+	if values, err := deps.Config.GetAll(config.CommandKey(branchType, config.CommandFinish, config.OptTrailer)); err == nil {
+		templates = values
+	}
+
+	given := make(map[string][]string)
+	keys := []string{}
+	if mergeOptions != nil {
+		for _, spec := range mergeOptions.Trailers {
+			key, value, err := parseTrailer(spec)
+			if err != nil {
+				return nil, err
+			}
+			if value == "" {
+				return nil, &errors.InvalidInputError{Message: fmt.Sprintf("--trailer '%s' has no value", spec)}
+			}
+			lower := strings.ToLower(key)
+			if _, ok := given[lower]; !ok {
+				keys = append(keys, lower)
+			}
+			given[lower] = append(given[lower], key+": "+value)
+		}
+	}
+
+	tag := ""
+	if options.ShouldTag {
+		tag = options.TagName
+	}
+
+	trailers := []string{}
+	used := make(map[string]bool)
+	for _, template := range templates {
+		key, value, err := parseTrailer(template)
+		if err != nil {
+			return nil, err
+		}
+		lower := strings.ToLower(key)
+		if lines, ok := given[lower]; ok {
+			if !used[lower] {
+				trailers = append(trailers, lines...)
+				used[lower] = true
+			}
+			continue
+		}
+		if value != "" {
+			trailers = append(trailers, key+": "+util.ExpandTrailerPlaceholders(value, branch, parent, version, tag))
+			continue
+		}
+
+		answer := deps.Prompter.Ask(key + ": ")
+		if answer == "" {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("the trailer '%s' is required for %s branches; pass it with --trailer '%s: <value>'", key, branchType, key)}
+		}
+		trailers = append(trailers, key+": "+answer)
+	}
+	for _, lower := range keys {
+		if !used[lower] {
+			trailers = append(trailers, given[lower]...)
+		}
+	}
+	return trailers, nil
+}
+
+// parseTrailer splits a trailer of the form "<key>: <value>"
+func parseTrailer(spec string) (string, string, error) {
+	key, value, found := strings.Cut(spec, ":")
+	key = strings.TrimSpace(key)
+	if !found || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", &errors.InvalidInputError{Message: fmt.Sprintf("trailer '%s' must have the form '<key>: <value>'", spec)}
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// expandExtraTags turns extra tag specs of the form <template>[:<branch>] into
+// tag names and target branches. The target defaults to the branch finished into.
+func expandExtraTags(deps *Deps, specs []string, version string, parent string, options *config.ResolvedFinishOptions) ([]mergestate.ExtraTag, error) {
+	tags := []mergestate.ExtraTag{}
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		template, target := spec, parent
+		if i := strings.LastIndex(spec, ":"); i >= 0 {
+			template, target = spec[:i], spec[i+1:]
+		}
+		if strings.Contains(template, "%t") && !options.ShouldTag {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("extra tag '%s' refers to the finish tag (%%t), but no tag is created", spec)}
+		}
+
+		name := util.ExpandTagPlaceholders(template, version, options.TagName, parent)
+		if name == "" || !deps.Git.IsValidTagName(name) {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("extra tag '%s' is not a valid tag name", spec)}
+		}
+		if options.ShouldTag && name == options.TagName {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("extra tag '%s' has the same name as the finish tag", spec)}
+		}
+		if seen[name] {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("extra tag '%s' is given more than once", name)}
+		}
+		if target == "" || deps.Git.BranchExists(target) != nil {
+			return nil, &errors.InvalidInputError{Message: fmt.Sprintf("extra tag '%s' targets branch '%s', which does not exist", spec, target)}
+		}
+		seen[name] = true
+		tags = append(tags, mergestate.ExtraTag{Name: name, Target: target})
+	}
+	return tags, nil
+}
+
+// collectReleaseNotes gathers the release note trailers of the commits since the
+// latest tag, writes them to the notes file and, if configured, appends them to
+// the tag message. Nothing happens unless gitflow.releasenotes.enabled is set.
+func collectReleaseNotes(deps *Deps, cfg *config.Config, state *mergestate.MergeState, options *config.ResolvedFinishOptions) error {
+	notesOptions := config.ResolveReleaseNotes(cfg)
+	if !notesOptions.Enabled {
+		return nil
+	}
+
+	revRange := state.FullBranchName
+	// Release candidates of this release are not releases of their own
+	lastTag, err := deps.Git.LatestTagExcluding(state.FullBranchName, "*"+git.ReleaseCandidateSuffix+"*")
+	if err != nil {
+		return &errors.GitError{Operation: "find latest tag", Err: err}
+	}
+	if lastTag != "" {
+		revRange = lastTag + ".." + state.FullBranchName
+	}
+
+	notes, err := deps.Git.CommitTrailers(revRange, notesOptions.Trailer)
+	if err != nil {
+		return &errors.GitError{Operation: "collect release notes", Err: err}
+	}
+	if len(notes) == 0 {
+		fmt.Fprintf(deps.Out, "No '%s' trailers found, skipping release notes\n", notesOptions.Trailer)
+		return nil
+	}
+
+	var content strings.Builder
+	for _, note := range notes {
+		content.WriteString("- " + note + "\n")
+	}
+
+	path, err := releaseNotesPath(deps, notesOptions.File)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return &errors.GitError{Operation: "create release notes directory", Err: err}
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		return &errors.GitError{Operation: "write release notes", Err: err}
+	}
+	fmt.Fprintf(deps.Out, "Wrote %d release notes to '%s'\n", len(notes), path)
+	state.ReleaseNotesFile = path
+
+	if notesOptions.Tag {
+		// Fold a message file into the message so the notes can be appended
+		if options.MessageFile != "" {
+			message, err := os.ReadFile(options.MessageFile)
+			if err != nil {
+				return &errors.GitError{Operation: "read tag message file", Err: err}
+			}
+			options.TagMessage = string(message)
+			options.MessageFile = ""
+		}
+		options.TagMessage = strings.TrimRight(options.TagMessage, "\n") + "\n\n" + content.String()
+	}
+
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return nil
+}
+
+// appendReleaseCandidates appends the release candidates recorded by rc for
+// the branch to the tag message
+func appendReleaseCandidates(deps *Deps, state *mergestate.MergeState, options *config.ResolvedFinishOptions) error {
+	summary := releaseCandidateSummary(deps, state.FullBranchName)
+	if summary == "" {
+		return nil
+	}
+	// Fold a message file into the message so the candidates can be appended
+	if options.MessageFile != "" {
+		message, err := os.ReadFile(options.MessageFile)
+		if err != nil {
+			return &errors.GitError{Operation: "read tag message file", Err: err}
+		}
+		options.TagMessage = string(message)
+		options.MessageFile = ""
+	}
+	options.TagMessage = strings.TrimRight(options.TagMessage, "\n") + "\n\n" + summary
+	return nil
+}
+
+// releaseCandidateSummary returns the lines listing the release candidates
+// recorded for branch whose tags still exist, oldest first, or "" when there
+// are none
+func releaseCandidateSummary(deps *Deps, branch string) string {
+	candidates, err := deps.Git.ReleaseCandidates(branch)
+	if err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Could not read the release candidates of '%s': %v\n", branch, err)
+		return ""
+	}
+	var summary strings.Builder
+	for _, candidate := range candidates {
+		if !deps.Git.TagExists(candidate.Tag) {
+			continue
+		}
+		fmt.Fprintf(&summary, "- %s (%s)\n", candidate.Tag, git.ShortCommit(candidate.Commit))
+	}
+	if summary.Len() == 0 {
+		return ""
+	}
+	return "Release candidates:\n" + summary.String()
+}
+
+// releaseNotesPath returns the absolute path of the release notes file. Relative
+// configured paths are relative to the working tree root; without a configured
+// path the notes are kept in the git directory.
+func releaseNotesPath(deps *Deps, configured string) (string, error) {
+	if configured == "" {
+		gitDir, err := deps.Git.GetGitDir()
+		if err != nil {
+			return "", &errors.GitError{Operation: "get git directory", Err: err}
+		}
+		return filepath.Abs(filepath.Join(gitDir, "gitflow", "RELEASE_NOTES"))
+	}
+	if filepath.IsAbs(configured) {
+		return configured, nil
+	}
+	root, err := deps.Git.GetTopLevelDir()
+	if err != nil {
+		return "", &errors.GitError{Operation: "get working tree root", Err: err}
+	}
+	return filepath.Join(root, configured), nil
+}
+
+// findNextBranchToUpdate finds the next child branch that needs updating
+func findNextBranchToUpdate(state *mergestate.MergeState) string {
+	for _, branch := range state.ChildBranches {
+		alreadyUpdated := false
+		for _, updated := range state.UpdatedBranches {
+			if branch == updated {
+				alreadyUpdated = true
+				break
+			}
+		}
+		if !alreadyUpdated {
+			return branch
+		}
+	}
+	return ""
+}
+
+// updateChildBranch updates a single child branch
+func updateChildBranch(ctx context.Context, deps *Deps, cfg *config.Config, branchName string, state *mergestate.MergeState) error {
+	// Track which child branch we're updating
+	state.CurrentChildBranch = branchName
+	state.StepStartCommit, _ = deps.Git.BranchCommit(branchName)
+	state.ExpectedHead = ""
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+
+	// Get strategy from saved state if available, fallback to config
+	strategy := ""
+	if state.ChildStrategies != nil {
+		strategy = state.ChildStrategies[branchName]
+	}
+
+	if strategy == "" {
+		// Fallback to current config if not in state
+		childBranchConfig, ok := cfg.Branches[branchName]
+		if !ok {
+			return &errors.GitError{Operation: fmt.Sprintf("get config for branch '%s'", branchName), Err: fmt.Errorf("branch config not found")}
+		}
+		strategy = childBranchConfig.DownstreamStrategy
+	}
+
+	// With mergeTagToChildren the child records the tag object instead of the parent branch
+	source := state.ParentBranch
+	if state.MergeTagToChildren && state.TagName != "" {
+		source = state.TagName
+		fmt.Fprintf(deps.Out, "Updating child base branch '%s' from tag '%s' (strategy: %s)...\n", branchName, state.TagName, EffectiveChildStrategy(strategy))
+	} else {
+		fmt.Fprintf(deps.Out, "Updating child base branch '%s' from '%s' (strategy: %s)...\n", branchName, state.ParentBranch, EffectiveChildStrategy(strategy))
+	}
+
+	// Expand placeholders in update message if provided
+	// For child updates, the "branch" is the child and "parent" is the source
+	updateMsg := state.UpdateMessage
+	if updateMsg != "" {
+		updateMsg = util.ExpandMessagePlaceholders(updateMsg, branchName, state.ParentBranch)
+	}
+
+	// Use the shared update logic with the determined strategy and custom message if provided
+	// Conflicts covered by the child's conflictResolution setting are resolved automatically
+	var resolution *update.ConflictResolution
+	if cfg != nil {
+		resolution = update.ConflictResolutionFor(cfg.Branches[branchName])
+	}
+
+	err := newUpdater(deps, cfg).FromParentWithResolution(ctx, branchName, source, strategy, updateMsg, state.NoVerifyChildren, resolution, state)
+	if err != nil {
+		if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+			if strategy != strategyRebase {
+				state.ExpectedHead = branchName
+				if err := deps.State.Save(state); err != nil {
+					return &errors.GitError{Operation: "save merge state", Err: err}
+				}
+			}
+			// Get resolved options for the message (might be nil, but generateConflictMessage handles that)
+			var resolvedOptions *config.ResolvedFinishOptions
+			if cfg != nil {
+				// Try to resolve options for better tag information in message
+				resolvedOptions = config.ResolveFinishOptions(cfg, state.BranchType, state.BranchName, nil, nil, nil, nil, nil, nil)
+			}
+
+			// Generate and print detailed conflict message
+			msg := generateConflictMessage(state, cfg, resolvedOptions)
+			fmt.Fprintln(deps.Out, msg)
+			return err
+		}
+		return err
+	}
+
+	return nil
+}
+
+// deleteBranchesIfNeeded deletes branches based on retention settings
+func deleteBranchesIfNeeded(ctx context.Context, deps *Deps, state *mergestate.MergeState, remote string, keepRemote, keepLocal, forceDelete bool) error {
+	// Delete remote branch if not keeping it and if remote branch exists
+	if !keepRemote {
+		// Only attempt to delete if the remote branch actually exists
+		if deps.Git.RemoteBranchExists(remote, state.FullBranchName) {
+			remoteBranch := fmt.Sprintf("%s/%s", remote, state.FullBranchName)
+			if remoteMergeIsPublished(deps, state, remote) {
+				if err := deps.Remote.DeleteRemoteBranch(ctx, remote, state.FullBranchName); err != nil {
+					return &errors.GitError{Operation: fmt.Sprintf("delete remote branch '%s'", remoteBranch), Err: err}
+				}
+				fmt.Fprintf(deps.Out, "Deleted remote branch '%s'\n", remoteBranch)
+				deps.Events.BranchDeleted(events.BranchDeleted{Branch: state.FullBranchName, Remote: remote})
+			} else {
+				fmt.Fprintf(deps.Out, "Warning: Kept remote branch '%s' because the merge into '%s' has not been pushed yet.\n", remoteBranch, state.ParentBranch)
+				fmt.Fprintf(deps.Out, "Push '%s', then delete it with: git push %s --delete %s\n", state.ParentBranch, remote, state.FullBranchName)
+			}
+		}
+	}
+
+	// Delete local branch if not keeping it
+	if !keepLocal {
+		if err := deps.Git.DeleteBranch(state.FullBranchName, forceDelete); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("delete branch '%s'", state.FullBranchName), Err: err}
+		}
+		deps.Events.BranchDeleted(events.BranchDeleted{Branch: state.FullBranchName})
+	}
+
+	return nil
+}
+
+// remoteMergeIsPublished reports whether deleting the remote topic branch is safe:
+// either the merge was pushed by the push step, or every commit of the remote
+// branch is already contained in the parent branch on the remote.
+func remoteMergeIsPublished(deps *Deps, state *mergestate.MergeState, remote string) bool {
+	if state.Push {
+		return true
+	}
+	if !deps.Git.RemoteBranchExists(remote, state.ParentBranch) {
+		return false
+	}
+	remoteBranch := fmt.Sprintf("refs/remotes/%s/%s", remote, state.FullBranchName)
+	remoteParent := fmt.Sprintf("refs/remotes/%s/%s", remote, state.ParentBranch)
+	return deps.Git.IsAncestor(remoteBranch, remoteParent)
+}
+
+// recordChildCommit remembers the commit an updated child branch points to for the post-finish hook
+func recordChildCommit(deps *Deps, state *mergestate.MergeState, childName string) {
+	commit, err := deps.Git.BranchCommit(childName)
+	if err != nil {
+		return
+	}
+	if state.ChildCommits == nil {
+		state.ChildCommits = make(map[string]string)
+	}
+	state.ChildCommits[childName] = commit
+}
+
+// finishResult describes a completed finish for the post-finish hook
+func finishResult(state *mergestate.MergeState) *hooks.FinishResult {
+	result := &hooks.FinishResult{
+		BranchType:       state.BranchType,
+		BranchName:       state.BranchName,
+		FullBranch:       state.FullBranchName,
+		BaseBranch:       state.ParentBranch,
+		MergeStrategy:    state.MergeStrategy,
+		MergeCommit:      state.MergeCommit,
+		TagName:          state.TagName,
+		ExtraTags:        []string{},
+		UpdatedBranches:  []hooks.UpdatedBranch{},
+		ReleaseNotesFile: state.ReleaseNotesFile,
+	}
+	for _, tag := range state.ExtraTags {
+		result.ExtraTags = append(result.ExtraTags, tag.Name)
+	}
+	for _, child := range state.UpdatedBranches {
+		result.UpdatedBranches = append(result.UpdatedBranches, hooks.UpdatedBranch{
+			Name:     child,
+			Strategy: state.ChildStrategies[child],
+			Commit:   state.ChildCommits[child],
+		})
+	}
+	return result
+}
+
+// reportFinishToActions writes the branch, its target, the created tag and
+// the updated child branches as GitHub Actions step outputs and job summary
+func reportFinishToActions(state *mergestate.MergeState) {
+	result := finishResult(state)
+	updated := make([]string, 0, len(result.UpdatedBranches))
+	for _, child := range result.UpdatedBranches {
+		updated = append(updated, child.Name)
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "### Finished `%s`\n\n", result.FullBranch)
+	strategy := result.MergeStrategy
+	if strategy == "" {
+		strategy = string(config.MergeStrategyMerge)
+	}
+	fmt.Fprintf(&summary, "- Merged into `%s` (%s)\n", result.BaseBranch, strategy)
+	if result.TagName != "" {
+		fmt.Fprintf(&summary, "- Tagged `%s`\n", result.TagName)
+	}
+	for _, child := range result.UpdatedBranches {
+		fmt.Fprintf(&summary, "- Updated `%s` (%s)\n", child.Name, EffectiveChildStrategy(child.Strategy))
+	}
+
+	output.ReportToActions([]output.ActionOutput{
+		{Name: "branch", Value: result.FullBranch},
+		{Name: "base", Value: result.BaseBranch},
+		{Name: "merge-commit", Value: result.MergeCommit},
+		{Name: "tag", Value: result.TagName},
+		{Name: "updated-branches", Value: strings.Join(updated, " ")},
+	}, summary.String())
+}
+
+// generateConflictMessage generates a human-readable conflict message with progress information
+func generateConflictMessage(state *mergestate.MergeState, cfg *config.Config, resolvedOptions *config.ResolvedFinishOptions) string {
+	var msg strings.Builder
+
+	// Header
+	msg.WriteString(fmt.Sprintf("Merge conflict detected while finishing %s/%s\n\n", state.BranchType, state.BranchName))
+
+	// What happened section
+	msg.WriteString("What happened:\n")
+	if state.CurrentStep == mergestate.StepMerge {
+		msg.WriteString(fmt.Sprintf("  Trying to merge '%s' into '%s' using %s strategy\n", state.FullBranchName, state.ParentBranch, state.MergeStrategy))
+	} else if state.CurrentStep == mergestate.StepUpdateChildren && state.CurrentChildBranch != "" {
+		msg.WriteString(fmt.Sprintf("  Successfully merged '%s' into '%s'\n", state.FullBranchName, state.ParentBranch))
+		strategy := "merge"
+		if state.ChildStrategies != nil && state.ChildStrategies[state.CurrentChildBranch] != "" {
+			strategy = state.ChildStrategies[state.CurrentChildBranch]
+		}
+		msg.WriteString(fmt.Sprintf("  Now updating '%s' from '%s' using %s strategy\n", state.CurrentChildBranch, state.ParentBranch, strategy))
+	}
+
+	// Where we are section - show all steps as natural progression
+	msg.WriteString("\nWhere we are:\n")
+	msg.WriteString("  ✓ Started finish operation\n")
+
+	// Merge step
+	if state.CurrentStep == mergestate.StepMerge {
+		msg.WriteString(fmt.Sprintf("  ✗ Merge into %s (conflict here)\n", state.ParentBranch))
+	} else {
+		msg.WriteString(fmt.Sprintf("  ✓ Merged into %s\n", state.ParentBranch))
+	}
+
+	// Tag step (only show if tags will be created)
+	if state.CurrentStep == mergestate.StepCreateTag || state.CurrentStep == mergestate.StepUpdateChildren || state.CurrentStep == mergestate.StepDeleteBranch {
+		if resolvedOptions != nil && resolvedOptions.ShouldTag {
+			msg.WriteString(fmt.Sprintf("  ✓ Created tag '%s'\n", resolvedOptions.TagName))
+		}
+	} else if resolvedOptions != nil && resolvedOptions.ShouldTag {
+		msg.WriteString(fmt.Sprintf("  ⧖ Create tag '%s'\n", resolvedOptions.TagName))
+	}
+
+	// Child branch updates - show each as individual step
+	if len(state.ChildBranches) > 0 {
+		for _, child := range state.ChildBranches {
+			isUpdated := state.IsChildUpdated(child)
+			isCurrentConflict := state.CurrentStep == mergestate.StepUpdateChildren && state.CurrentChildBranch == child
+
+			if isUpdated {
+				msg.WriteString(fmt.Sprintf("  ✓ Update %s from %s\n", child, state.ParentBranch))
+			} else if isCurrentConflict {
+				msg.WriteString(fmt.Sprintf("  ✗ Update %s from %s (conflict here)\n", child, state.ParentBranch))
+			} else {
+				msg.WriteString(fmt.Sprintf("  ⧖ Update %s from %s\n", child, state.ParentBranch))
+			}
+		}
+	}
+
+	// Extra tag and push steps
+	for _, tag := range state.ExtraTags {
+		msg.WriteString(fmt.Sprintf("  ⧖ Move tag '%s' to %s\n", tag.Name, tag.Target))
+	}
+	if state.Push && cfg != nil {
+		msg.WriteString(fmt.Sprintf("  ⧖ Push to %s\n", cfg.Remote))
+	} else if state.PushTag && cfg != nil {
+		msg.WriteString(fmt.Sprintf("  ⧖ Push tag to %s\n", cfg.Remote))
+	}
+
+	// Delete branch step
+	if state.CurrentStep == mergestate.StepDeleteBranch {
+		msg.WriteString(fmt.Sprintf("  ✓ Delete %s branch\n", state.BranchType))
+	} else {
+		msg.WriteString(fmt.Sprintf("  ⧖ Delete %s branch\n", state.BranchType))
+	}
+
+	// Resolution instructions
+	msg.WriteString("\nTo continue:\n")
+	msg.WriteString("  1. Resolve the conflicts in your files\n")
+	msg.WriteString("  2. Stage resolved files: git add <files>\n")
+	msg.WriteString(fmt.Sprintf("  3. Continue: git flow %s finish --continue %s\n", state.BranchType, state.BranchName))
+	msg.WriteString(fmt.Sprintf("\nTo abort: git flow %s finish --abort %s", state.BranchType, state.BranchName))
+
+	return msg.String()
+}
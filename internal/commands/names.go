@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// CurrentBranchFor returns the current branch for a command that acts on it by
+// default. With a detached HEAD it fails and suggests usage, the form of the
+// command that names the branch explicitly.
+func CurrentBranchFor(deps *Deps, usage string) (string, error) {
+	if deps.Git.IsDetachedHead() {
+		return "", &errors.DetachedHeadError{Usage: usage}
+	}
+	currentBranch, err := deps.Git.GetCurrentBranch()
+	if err != nil {
+		return "", &errors.GitError{Operation: "get current branch", Err: err}
+	}
+	return currentBranch, nil
+}
+
+// ResolveTopicName resolves a name argument of a topic branch command, the
+// short name or the full name, to the full and the short branch name. All
+// commands that take the name of an existing topic branch use it, so they
+// accept the same forms and reject names of other types the same way.
+func ResolveTopicName(deps *Deps, cfg *config.Config, branchType string, name string) (string, string, error) {
+	return config.ResolveTopicName(cfg, branchType, name, func(branch string) bool {
+		return localBranchExists(deps, branch)
+	})
+}
+
+// localBranchExists reports whether branch exists locally
+func localBranchExists(deps *Deps, branch string) bool {
+	return deps.Git.BranchExists(branch) == nil
+}
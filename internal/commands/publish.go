@@ -0,0 +1,319 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/output"
+)
+
+// PublishOptions are the options of a publish
+type PublishOptions struct {
+	// PushOptions are transmitted to the server with the push
+	PushOptions []string
+	// NoPushOption suppresses all push options, given and configured
+	NoPushOption bool
+	// ForceWithLease overwrites the branch when it already exists on the remote
+	ForceWithLease bool
+	// TrackInstead sets up tracking of an existing remote branch without pushing
+	TrackInstead bool
+	// OpenPR opens a pull request against the parent once the branch is
+	// published, as a draft if Draft is set
+	OpenPR bool
+	Draft  bool
+	// TrackUpstream makes the local branch track the published branch; nil
+	// falls back to the configuration
+	TrackUpstream *bool
+}
+
+// Publish pushes the topic branch name of branchType to the remote, wrapped in
+// the publish hooks. An empty name publishes the current branch. Cancelling
+// ctx stops the fetch or push.
+func Publish(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options PublishOptions) error {
+	return publish(ctx, deps, cfgCtx, branchType, name, options.PushOptions, options.NoPushOption, options.ForceWithLease, options.TrackInstead, options.OpenPR, options.Draft, options.TrackUpstream)
+}
+
+// publish performs the actual publish logic and returns any errors
+func publish(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, cliPushOptions []string, noPushOption bool, forceWithLease bool, trackInstead bool, openPR bool, draft bool, trackUpstream *bool) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	if forceWithLease && trackInstead {
+		return &errors.InvalidInputError{Message: "--force-with-lease and --track-instead cannot be used together"}
+	}
+
+	// Get configuration
+	cfg := cfgCtx.Config
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	setUpstream := config.ResolveTrackUpstream(cfg, branchType, trackUpstream)
+	if trackInstead && !setUpstream {
+		return &errors.InvalidInputError{Message: "--track-instead and --no-track-upstream cannot be used together"}
+	}
+
+	// Determine branch name - if empty, use current branch
+	var fullBranchName string
+	var shortName string
+	if name == "" {
+		currentBranch, err := CurrentBranchFor(deps, fmt.Sprintf("git flow %s publish <name>", branchType))
+		if err != nil {
+			return err
+		}
+		fullBranchName = currentBranch
+
+		// Verify current branch is of the correct type
+		if branchConfig.Prefix != "" && !strings.HasPrefix(currentBranch, branchConfig.Prefix) {
+			return fmt.Errorf("current branch '%s' is not a %s branch", currentBranch, branchType)
+		}
+
+		// Extract short name
+		if branchConfig.Prefix != "" {
+			shortName = strings.TrimPrefix(currentBranch, branchConfig.Prefix)
+		} else {
+			shortName = currentBranch
+		}
+	} else {
+		var err error
+		fullBranchName, shortName, err = ResolveTopicName(deps, cfg, branchType, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check if branch exists locally
+	if err := deps.Git.BranchExists(fullBranchName); err != nil {
+		return &errors.LocalBranchNotFoundError{BranchName: fullBranchName}
+	}
+
+	remote := cfg.Remote
+
+	// Check that a pull request can be opened before anything is pushed
+	var repo *forge.Repository
+	var parent string
+	if openPR || draft {
+		var err error
+		if repo, err = ForgeRepository(deps, cfg, remote, "open a pull request"); err != nil {
+			return err
+		}
+		if err := repo.CheckPullRequests(); err != nil {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("cannot open a pull request: %v (use 'git flow %s compare' to open the compare page instead)", err, branchType)}
+		}
+		parent = branchConfig.Parent
+		if stored, err := deps.Git.GetBaseBranch(fullBranchName); err == nil && stored != "" {
+			parent = stored
+		}
+	}
+
+	// Get git directory for hooks
+	gitDir, err := deps.Git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	// Build hook context
+	hookCtx := hooks.HookContext{
+		BranchType: branchType,
+		BranchName: shortName,
+		FullBranch: fullBranchName,
+		BaseBranch: branchConfig.Parent,
+		Origin:     remote,
+	}
+	if branchType == "release" || branchType == "hotfix" {
+		hookCtx.Version = shortName
+	}
+
+	// The branch is pushed to the branch push.default selects, unless a
+	// negative refspec of the remote excludes it
+	remoteBranch, err := deps.Git.PushDestination(fullBranchName, remote)
+	if err != nil {
+		return &errors.InvalidInputError{Message: err.Error()}
+	}
+	if !trackInstead {
+		if refspec := deps.Git.PushExclusion(remote, "refs/heads/"+fullBranchName); refspec != "" {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is excluded from pushes to '%s' by '%s' in remote.%s.push", fullBranchName, remote, refspec, remote)}
+		}
+	}
+
+	// Resolve push options using three-layer precedence:
+	// Layer 1: No default push options (branch config has no push option field)
+	// Layer 2: Git config (gitflow.<branchType>.publish.push-option)
+	// Layer 3: CLI flags add to config defaults
+	// --no-push-option suppresses all options
+	pushOptions := resolvePushOptions(deps, cfg, branchType, cliPushOptions, noPushOption)
+
+	// Run publish operation wrapped with hooks
+	err = hooks.WithHooks(gitDir, branchType, hooks.HookActionPublish, hookCtx, func() error {
+		return executePublish(ctx, deps, fullBranchName, shortName, branchType, remote, remoteBranch, pushOptions, forceWithLease, trackInstead, setUpstream)
+	})
+	if err != nil || repo == nil {
+		return err
+	}
+
+	return openPullRequest(deps, repo, parent, fullBranchName, shortName, draft)
+}
+
+// openPullRequest opens a pull request of branch against parent. The title and
+// body come from the branch description; without one, the title is the subject
+// of a single commit or the branch name, and the body lists the commits.
+func openPullRequest(deps *Deps, repo *forge.Repository, parent string, fullBranchName string, shortName string, draft bool) error {
+	subjects, _ := deps.Git.CommitSubjects(parent, fullBranchName)
+
+	pr := forge.PullRequest{Base: parent, Head: fullBranchName, Draft: draft}
+	description := strings.TrimSpace(deps.Git.GetBranchDescription(fullBranchName))
+	if description != "" {
+		title, body, _ := strings.Cut(description, "\n")
+		pr.Title = strings.TrimSpace(title)
+		pr.Body = strings.TrimSpace(body)
+	} else if len(subjects) == 1 {
+		pr.Title = subjects[0]
+	} else {
+		pr.Title = strings.ReplaceAll(shortName, "-", " ")
+		pr.Title = strings.ToUpper(pr.Title[:1]) + pr.Title[1:]
+	}
+	if len(subjects) > 0 {
+		var commits strings.Builder
+		for _, subject := range subjects {
+			commits.WriteString("- " + subject + "\n")
+		}
+		if pr.Body != "" {
+			pr.Body += "\n\n"
+		}
+		pr.Body += "Commits:\n" + strings.TrimSuffix(commits.String(), "\n")
+	}
+
+	kind := "pull request"
+	if draft {
+		kind = "draft pull request"
+	}
+	fmt.Fprintf(deps.Out, "Opening %s of '%s' against '%s'...\n", kind, fullBranchName, parent)
+	url, err := repo.CreatePullRequest(pr)
+	if err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("open a pull request for '%s'", fullBranchName), Err: err}
+	}
+
+	fmt.Fprintf(deps.Out, "Opened %s\n", url)
+	deps.result("%s", url)
+	output.ReportToActions([]output.ActionOutput{{Name: "pr-url", Value: url}}, fmt.Sprintf("Opened %s of `%s` against `%s`: %s", kind, fullBranchName, parent, url))
+	return nil
+}
+
+// resolvePushOptions resolves push options using three-layer precedence:
+// - Layer 1: No default push options (branch config has no push option field)
+// - Layer 2: Git config (gitflow.<branchType>.publish.push-option)
+// - Layer 3: CLI flags add to config defaults
+// If noPushOption is true, all push options are suppressed.
+func resolvePushOptions(deps *Deps, cfg *config.Config, branchType string, cliPushOptions []string, noPushOption bool) []string {
+	// --no-push-option suppresses all options
+	if noPushOption {
+		return nil
+	}
+
+	var resolvedOptions []string
+
+	// Layer 2: Load from git config (multi-value key)
+	configKey := config.CommandKey(branchType, config.CommandPublish, config.OptPushOption)
+	configOptions, err := deps.Config.GetAll(configKey)
+	if err == nil {
+		resolvedOptions = append(resolvedOptions, configOptions...)
+	}
+
+	// Layer 3: CLI flags add to config defaults
+	resolvedOptions = append(resolvedOptions, cliPushOptions...)
+
+	return resolvedOptions
+}
+
+// executePublish performs the actual publish operation (called within hooks wrapper).
+// The branch is published to remoteBranch, usually of the same name.
+func executePublish(ctx context.Context, deps *Deps, fullBranchName, shortName, branchType, remote, remoteBranch string, pushOptions []string, forceWithLease bool, trackInstead bool, trackUpstream bool) error {
+	// Fetch to get latest remote refs
+	fmt.Fprintf(deps.Out, "Fetching from '%s'...\n", remote)
+	if err := deps.Remote.Fetch(ctx, remote); err != nil {
+		// Don't fail if fetch fails; the push reports what went wrong
+		WarnFetchFailed(deps, remote, err)
+	}
+
+	// Check if remote branch already exists
+	remoteExists := deps.Git.RemoteBranchExists(remote, remoteBranch)
+	if trackInstead {
+		return trackExistingRemoteBranch(deps, fullBranchName, remote, remoteBranch, remoteExists)
+	}
+	// A remote branch the local branch contains is fast-forwarded; only one with
+	// commits the local branch lacks is refused
+	if remoteExists && !forceWithLease {
+		ahead, behind, err := deps.Git.AheadBehind(fullBranchName, remote+"/"+remoteBranch)
+		if err != nil {
+			return &errors.RemoteBranchExistsError{Remote: remote, BranchName: remoteBranch}
+		}
+		if behind > 0 {
+			return &errors.RemoteBranchExistsError{
+				Remote:     remote,
+				BranchName: remoteBranch,
+				Compared:   true,
+				Ahead:      ahead,
+				Behind:     behind,
+			}
+		}
+	}
+
+	// Push the branch to remote, with tracking unless it is turned off. An
+	// existing remote branch is only overwritten with --force-with-lease, and
+	// only if nobody pushed to it since the last fetch
+	fmt.Fprintf(deps.Out, "Publishing '%s' to '%s'...\n", fullBranchName, remote)
+	err := deps.Remote.PushBranch(ctx, remote, fullBranchName, remoteBranch, pushOptions, trackUpstream, remoteExists && forceWithLease)
+	if err != nil {
+		// Overwriting is only suggested when the remote commits were rebased locally
+		if rejected := errors.PushRejection(err); rejected != nil && !forceWithLease {
+			rejected.ForceWithLease = deps.Git.OnlyRebasedCommits(fullBranchName, remote+"/"+remoteBranch)
+		}
+		return &errors.GitError{
+			Operation: fmt.Sprintf("push branch '%s' to '%s'", fullBranchName, remote),
+			Err:       err,
+		}
+	}
+
+	fmt.Fprintf(deps.Out, "Successfully published '%s' to '%s/%s'\n", fullBranchName, remote, remoteBranch)
+	fmt.Fprintf(deps.Out, "Other team members can now track this branch with:\n")
+	fmt.Fprintf(deps.Out, "    git flow %s track %s\n", branchType, shortName)
+	deps.result("%s/%s", remote, remoteBranch)
+	output.ReportToActions([]output.ActionOutput{
+		{Name: "branch", Value: fullBranchName},
+		{Name: "remote", Value: remote},
+	}, fmt.Sprintf("Published `%s` to `%s`", fullBranchName, remote))
+	return nil
+}
+
+// trackExistingRemoteBranch handles publish --track-instead: the local branch is
+// set up to track the existing remote branch and nothing is pushed
+func trackExistingRemoteBranch(deps *Deps, fullBranchName, remote, remoteBranch string, remoteExists bool) error {
+	if !remoteExists {
+		return &errors.RemoteBranchNotFoundError{
+			Remote:      remote,
+			BranchName:  remoteBranch,
+			Suggestions: SimilarRemoteBranches(deps, remote, remoteBranch),
+		}
+	}
+
+	if err := deps.Git.SetUpstream(fullBranchName, remote, remoteBranch); err != nil {
+		return &errors.GitError{
+			Operation: fmt.Sprintf("track '%s/%s'", remote, remoteBranch),
+			Err:       err,
+		}
+	}
+
+	fmt.Fprintf(deps.Out, "Branch '%s' now tracks the existing remote branch '%s/%s'\n", fullBranchName, remote, remoteBranch)
+	deps.result("%s/%s", remote, remoteBranch)
+	return nil
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// WarnFetchFailed reports a fetch the operation can do without to deps.Err.
+// When the remote could not be reached, the operation continues with the local
+// state of the remote branches, as with --no-fetch, and offline is true. Other
+// failures to talk to the remote are printed with their hint.
+func WarnFetchFailed(deps *Deps, remote string, err error) (offline bool) {
+	remoteErr := errors.RemoteFailure(err)
+	if remoteErr == nil {
+		fmt.Fprintf(deps.Err, "Warning: Could not fetch from '%s': %v\n", remote, err)
+		return false
+	}
+
+	fmt.Fprintf(deps.Err, "Warning: %v\n", remoteErr)
+	if remoteErr.Kind == errors.RemoteUnreachable {
+		fmt.Fprintf(deps.Err, "Continuing without fetching, as with --no-fetch; remote branches may be out of date\n")
+		return true
+	}
+	fmt.Fprintf(deps.Err, "Hint: %s\n", remoteErr.Hint())
+	return false
+}
+
+// FastForwardBaseBranch fast-forwards branch to its remote branch when it is
+// behind and returns the summary status
+func FastForwardBaseBranch(ctx context.Context, deps *Deps, remote string, branch string, currentBranch string) string {
+	if deps.Git.BranchExists(branch) != nil {
+		return "no local branch"
+	}
+	if !deps.Git.RemoteBranchExists(remote, branch) {
+		return "no remote branch"
+	}
+
+	remoteBranch := remote + "/" + branch
+	ahead, behind, err := deps.Git.AheadBehind(branch, remoteBranch)
+	if err != nil {
+		return fmt.Sprintf("could not compare with '%s'", remoteBranch)
+	}
+	switch {
+	case behind == 0 && ahead == 0:
+		return "up to date"
+	case behind == 0:
+		return fmt.Sprintf("%d commit(s) ahead of '%s', not pushed", ahead, remoteBranch)
+	case ahead > 0:
+		return fmt.Sprintf("diverged from '%s' (%d local, %d remote commit(s)), not fast-forwarded", remoteBranch, ahead, behind)
+	}
+
+	if branch == currentBranch {
+		err = deps.Git.MergeFastForwardOnly(ctx, remoteBranch)
+	} else {
+		err = deps.Git.FastForwardBranch(branch, "refs/remotes/"+remoteBranch)
+	}
+	if err != nil {
+		fmt.Fprintf(deps.Err, "Warning: %v\n", err)
+		return "fast-forward failed"
+	}
+	return fmt.Sprintf("fast-forwarded %d commit(s) from '%s'", behind, remoteBranch)
+}
+
+// maxRemoteSuggestions limits how many similar remote branches are suggested
+const maxRemoteSuggestions = 5
+
+// SimilarRemoteBranches returns remote branches with names close to branch,
+// e.g. to point out a typo when a branch to track does not exist
+func SimilarRemoteBranches(deps *Deps, remote, branch string) []string {
+	branches, err := deps.Git.RemoteBranches(remote)
+	if err != nil {
+		return nil
+	}
+	return util.ClosestMatches(branch, branches, maxRemoteSuggestions)
+}
+
+// ForgeRepository returns the hosted repository behind remote; purpose completes
+// the error message "cannot <purpose> for remote ..."
+func ForgeRepository(deps *Deps, cfg *config.Config, remote string, purpose string) (*forge.Repository, error) {
+	remoteURL, err := deps.Git.GetRemoteURL(remote)
+	if err != nil {
+		return nil, &errors.GitError{Operation: fmt.Sprintf("get URL of remote '%s'", remote), Err: err}
+	}
+
+	kind := config.ResolveForge(cfg)
+	if kind != "" && !forge.Supported(forge.Kind(kind)) {
+		allowed := make([]string, len(forge.Kinds))
+		for i, k := range forge.Kinds {
+			allowed[i] = string(k)
+		}
+		return nil, &errors.InvalidConfigValueError{Key: config.KeyForge, Value: kind, Allowed: allowed}
+	}
+
+	repo, err := forge.ParseRemoteURL(remoteURL, forge.Kind(kind))
+	if err != nil {
+		return nil, &errors.InvalidInputError{Message: fmt.Sprintf("cannot %s for remote '%s': %v (set gitflow.forge for self-hosted instances)", purpose, remote, err)}
+	}
+	return repo, nil
+}
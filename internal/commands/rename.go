@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// Rename renames the topic branch oldName of branchType to newName
+func Rename(deps *Deps, cfg *config.Config, branchType string, oldName string, newName string) error {
+	// Get branch configuration
+	if _, ok := cfg.Branches[branchType]; !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Accept the short or the full branch names
+	exists := func(branch string) bool { return deps.Git.BranchExists(branch) == nil }
+	oldFullBranchName, _, err := config.ResolveTopicName(cfg, branchType, oldName, exists)
+	if err != nil {
+		return err
+	}
+	newFullBranchName, newName, err := config.ResolveTopicName(cfg, branchType, newName, nil)
+	if err != nil {
+		return err
+	}
+
+	// Check if old branch exists
+	if err := deps.Git.BranchExists(oldFullBranchName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: oldFullBranchName}
+	}
+
+	// Check if new branch name already exists
+	if err := deps.Git.BranchExists(newFullBranchName); err == nil {
+		return &errors.GitError{Operation: "rename branch", Err: fmt.Errorf("branch '%s' already exists", newFullBranchName)}
+	}
+
+	// Refuse a short name that a topic branch of another type already uses
+	if unique, _ := cfg.GetBool(config.KeyUniqueTopicNames); unique {
+		if existing := config.TopicNameConflict(cfg, branchType, newName, exists); existing != "" {
+			return &errors.DuplicateTopicNameError{Name: newName, ExistingBranch: existing}
+		}
+	}
+
+	// Make sure HEAD can be read; git renames the current branch in place
+	if _, err := deps.Git.GetCurrentBranch(); err != nil {
+		return &errors.GitError{Operation: "get current branch", Err: err}
+	}
+
+	if err := deps.Git.RenameBranch(oldFullBranchName, newFullBranchName); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("rename branch '%s' to '%s'", oldFullBranchName, newFullBranchName), Err: err}
+	}
+
+	fmt.Fprintf(deps.Out, "Renamed branch '%s' to '%s'\n", oldFullBranchName, newFullBranchName)
+	deps.result("%s", newFullBranchName)
+	return nil
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gittower/git-flow-next/internal/config"
+)
+
+// ActiveStabilization returns the branch being stabilized, or "" when the mode
+// is off or the branch no longer exists
+func ActiveStabilization(deps *Deps, cfg *config.Config) string {
+	branch, _ := cfg.GetString(config.KeyStabilization)
+	if branch == "" || deps.Git.BranchExists(branch) != nil {
+		return ""
+	}
+	return branch
+}
+
+// StabilizationSource returns the branch the stabilized branch was started
+// from: its stored base, or the start point of its type
+func StabilizationSource(deps *Deps, cfg *config.Config, branch string) string {
+	if stored, err := deps.Git.GetBaseBranch(branch); err == nil && stored != "" {
+		return stored
+	}
+	branchConfig := cfg.Branches[config.TopicBranchType(cfg, branch)]
+	if branchConfig.StartPoint != "" {
+		return branchConfig.StartPoint
+	}
+	return branchConfig.Parent
+}
+
+// StabilizedTypes returns the topic branch types routed to the stabilized
+// branch: those with the branch's source as parent, other than its own type
+func StabilizedTypes(deps *Deps, cfg *config.Config, branch string) []string {
+	source := StabilizationSource(deps, cfg, branch)
+	ownType := config.TopicBranchType(cfg, branch)
+	types := []string{}
+	for branchType, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(config.BranchTypeTopic) && branchType != ownType && branchConfig.Parent == source {
+			types = append(types, branchType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// endStabilization lifts the stabilization mode once its branch is finished
+func endStabilization(deps *Deps, cfg *config.Config, finishedBranch string) {
+	if branch, _ := cfg.GetString(config.KeyStabilization); branch != finishedBranch {
+		return
+	}
+	if err := deps.Config.Unset(config.KeyStabilization); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to end the stabilization of '%s': %v\n", finishedBranch, err)
+		return
+	}
+	fmt.Fprintf(deps.Out, "Stopped stabilizing '%s'; new branches start from their configured base again\n", finishedBranch)
+}
@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// StartOptions are the options of a start. Nil pointers fall back to the
+// configuration.
+type StartOptions struct {
+	// Base starts the branch from this branch instead of the configured
+	// starting point
+	Base string
+	// Line starts the branch from the base branch of this version line
+	Line          string
+	Fetch         *bool
+	FromRemote    *bool
+	Publish       *bool
+	TrackUpstream *bool
+	// Describe writes the branch description in the editor
+	Describe bool
+}
+
+// Start creates the topic branch name of branchType, wrapped in the start
+// hooks, and publishes it when the configuration or options select it.
+// Cancelling ctx stops the fetch or push.
+func Start(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options StartOptions) error {
+	return start(ctx, deps, cfgCtx, branchType, name, options.Base, options.Line, options.Fetch, options.FromRemote, options.Publish, options.Describe, options.TrackUpstream)
+}
+
+// start performs the actual branch creation logic with optional fetch and returns any errors
+func start(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, base string, line string, shouldFetch *bool, fromRemote *bool, shouldPublish *bool, describe bool, trackUpstream *bool) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Validate inputs
+	if name == "" {
+		return &errors.EmptyBranchNameError{}
+	}
+
+	// Get git directory for hooks and filters
+	gitDir, err := deps.Git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	// Get configuration
+	cfg := cfgCtx.Config
+
+	// A name given with the prefix is reduced to the short name
+	if _, name, err = config.ResolveTopicName(cfg, branchType, name, nil); err != nil {
+		return err
+	}
+
+	// The version filter and the pre-hook may amend the configuration of this start
+	amendConfig := func(values map[string]string) error {
+		return config.AmendConfig(cfg, values)
+	}
+
+	// Apply version filter for any branch type
+	// The filter script (filter-flow-{branchType}-start-version) decides what to do;
+	// without one, the built-in slugify filter normalizes the name if enabled
+	if !hooks.HasVersionFilter(gitDir, branchType) && config.ResolveStartSlugify(cfg, branchType) {
+		if slug := util.Slugify(name); slug != name {
+			if slug == "" {
+				return &errors.EmptyBranchNameError{}
+			}
+			fmt.Fprintf(deps.Out, "Normalized branch name '%s' to '%s'\n", name, slug)
+			name = slug
+		}
+	} else {
+		filteredName, err := hooks.RunVersionFilterWithConfig(gitDir, branchType, name, amendConfig)
+		if err != nil {
+			return &errors.GitError{Operation: "run version filter", Err: err}
+		}
+		if filteredName != name {
+			fmt.Fprintf(deps.Out, "Version filter changed '%s' to '%s'\n", name, filteredName)
+			name = filteredName
+		}
+	}
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok || branchConfig.Type != string(config.BranchTypeTopic) {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Get full branch name
+	fullBranchName := branchConfig.Prefix + name
+
+	// A version line selects the base branch of the line's releases and hotfixes
+	if line != "" {
+		if base != "" {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("--line starts from the base branch of the line; don't pass '%s' as base as well", base)}
+		}
+		versionLine, err := config.ResolveVersionLine(cfg, line)
+		if err != nil {
+			return err
+		}
+		if !versionLine.Contains(name) {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a version of line %s", name, versionLine.Name)}
+		}
+		fmt.Fprintf(deps.Out, "Starting '%s' on version line %s from '%s'\n", fullBranchName, versionLine.Name, versionLine.Base)
+		base = versionLine.Base
+	}
+
+	// Get start point
+	startPoint := branchConfig.Parent
+	if branchConfig.StartPoint != "" {
+		// If start point is specified in config, use it instead of parent
+		startPoint = branchConfig.StartPoint
+	}
+	if base != "" {
+		// If base argument is provided, it overrides the configured starting point
+		startPoint = base
+	} else if stabilized := ActiveStabilization(deps, cfg); stabilized != "" && slices.Contains(StabilizedTypes(deps, cfg, stabilized), branchType) {
+		fmt.Fprintf(deps.Out, "Stabilizing '%s', starting from it instead of '%s'\n", stabilized, startPoint)
+		startPoint = stabilized
+	}
+
+	// Another topic branch, such as a release branch, is only a base when the type
+	// allows it; the base of a version line, often a support branch, always is
+	if base != "" && line == "" {
+		if baseType := config.TopicBranchType(cfg, base); baseType != "" {
+			if !config.ResolveAllowTopicBase(cfg, branchType) {
+				return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is a %s branch; set %s to true to start %s branches from topic branches", base, baseType, config.TypeKey(branchType, config.OptAllowTopicBase), branchType)}
+			}
+			if err := deps.Git.BranchExists(base); err != nil {
+				return &errors.BranchNotFoundError{BranchName: base}
+			}
+		}
+	}
+
+	// Build hook context
+	hookCtx := hooks.HookContext{
+		BranchType:  branchType,
+		BranchName:  name,
+		FullBranch:  fullBranchName,
+		BaseBranch:  startPoint,
+		Origin:      cfg.Remote,
+		AmendConfig: amendConfig,
+	}
+	// Set version for branches configured with tagging
+	if branchConfig.Tag {
+		hookCtx.Version = name
+	}
+
+	// Run start operation wrapped with hooks
+	err = hooks.WithHooks(gitDir, branchType, hooks.HookActionStart, hookCtx, func() error {
+		return executeStart(ctx, deps, branchType, name, base, shouldFetch, fromRemote, describe, trackUpstream, cfg, branchConfig, fullBranchName, startPoint)
+	})
+	if err != nil || !config.ResolveStartPublish(cfg, branchType, shouldPublish) {
+		return err
+	}
+
+	// Publish the new branch, running the publish hooks
+	if err := publish(ctx, deps, cfgCtx, branchType, fullBranchName, nil, false, false, false, false, false, trackUpstream); err != nil {
+		fmt.Fprintf(deps.Err, "Branch '%s' was created but not published; retry with 'git flow %s publish'\n", fullBranchName, branchType)
+		return err
+	}
+	return nil
+}
+
+// executeStart performs the actual start operation (called within hooks wrapper)
+func executeStart(ctx context.Context, deps *Deps, branchType string, name string, base string, shouldFetch *bool, fromRemote *bool, describe bool, trackUpstream *bool, cfg *config.Config, branchConfig config.BranchConfig, fullBranchName string, startPoint string) error {
+	useRemote := config.ResolveStartFromRemote(cfg, branchType, fromRemote)
+
+	// Determine if we should fetch; starting from the remote branch fetches unless --no-fetch is given
+	fetchFromConfig := useRemote
+	if shouldFetch == nil && !useRemote {
+		// If not explicitly specified, check config
+		fetchFromConfig, _ = cfg.GetBool(config.CommandKey(branchType, config.CommandStart, config.OptFetch))
+	}
+
+	// Perform fetch if requested
+	remoteName := cfg.Remote
+	if shouldFetch != nil && *shouldFetch || shouldFetch == nil && fetchFromConfig {
+		// Fetch from remote
+		fmt.Fprintf(deps.Out, "Fetching from %s...\n", remoteName)
+		if err := deps.Remote.Fetch(ctx, remoteName); err != nil {
+			WarnFetchFailed(deps, remoteName, err)
+		}
+	}
+
+	// Check if branch already exists
+	if err := deps.Git.BranchExists(fullBranchName); err == nil {
+		return &errors.BranchExistsError{BranchName: fullBranchName}
+	}
+
+	// Fail now rather than at finish when the tag finish would create is taken
+	if err := checkStartTag(deps, cfg, branchType, name, fullBranchName, startPoint); err != nil {
+		return err
+	}
+
+	// Refuse a short name that a topic branch of another type already uses, locally or on the remote
+	if unique, _ := cfg.GetBool(config.KeyUniqueTopicNames); unique {
+		exists := func(branch string) bool {
+			return deps.Git.BranchExists(branch) == nil || deps.Git.RemoteBranchExists(remoteName, branch)
+		}
+		if existing := config.TopicNameConflict(cfg, branchType, name, exists); existing != "" {
+			return &errors.DuplicateTopicNameError{Name: name, ExistingBranch: existing}
+		}
+	}
+
+	// Check if start point exists (can be branch, tag, or commit)
+	if err := deps.Git.BranchOrCommitExists(startPoint); err != nil {
+		return &errors.BranchNotFoundError{BranchName: startPoint}
+	}
+
+	// Branch off the remote counterpart of a local start point, which may be
+	// ahead of the local branch
+	createFrom := startPoint
+	if useRemote && deps.Git.BranchExists(startPoint) == nil && deps.Git.RemoteBranchExists(remoteName, startPoint) {
+		createFrom = remoteName + "/" + startPoint
+		currentBranch, _ := deps.Git.GetCurrentBranch()
+		if status := FastForwardBaseBranch(ctx, deps, remoteName, startPoint, currentBranch); status != "up to date" {
+			fmt.Fprintf(deps.Out, "Local branch '%s': %s\n", startPoint, status)
+		}
+	}
+
+	// Write the description before the branch is created, so a failing editor leaves nothing behind
+	var description string
+	if describe {
+		template := fmt.Sprintf("\n# Please enter the description for branch '%s'.\n# Lines starting with '#' will be ignored, and an empty description is not stored.\n", fullBranchName)
+		var err error
+		description, err = deps.Git.EditMessage("BRANCH_DESCRIPTION", template)
+		if err != nil {
+			return &errors.GitError{Operation: "edit the branch description", Err: err}
+		}
+	}
+
+	// Create branch. It never tracks its start point, which would make git pull
+	// and git push work against the base branch.
+	if err := deps.Git.CreateBranchNoTrack(fullBranchName, createFrom); err != nil {
+		return &errors.GitError{Operation: "create branch", Err: err}
+	}
+
+	// A remote branch of the same name, e.g. one a deleted local branch was
+	// published to, becomes the upstream
+	if config.ResolveTrackUpstream(cfg, branchType, trackUpstream) && deps.Git.RemoteBranchExists(remoteName, fullBranchName) {
+		if err := deps.Git.SetUpstream(fullBranchName, remoteName, fullBranchName); err != nil {
+			fmt.Fprintf(deps.Err, "Warning: Failed to track '%s/%s': %v\n", remoteName, fullBranchName, err)
+		} else {
+			fmt.Fprintf(deps.Out, "Branch '%s' tracks the existing remote branch '%s/%s'\n", fullBranchName, remoteName, fullBranchName)
+		}
+	}
+
+	// Store the start point in Git config
+	if err := deps.Git.SetBaseBranch(fullBranchName, startPoint); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to store base branch: %v\n", err)
+	}
+
+	if description != "" {
+		if err := deps.Git.SetBranchDescription(fullBranchName, description); err != nil {
+			fmt.Fprintf(deps.Err, "Warning: Failed to store branch description: %v\n", err)
+		}
+	}
+
+	fmt.Fprintf(deps.Out, "Created branch '%s' from '%s'\n", fullBranchName, createFrom)
+	deps.result("%s", fullBranchName)
+	output.ReportToActions([]output.ActionOutput{
+		{Name: "branch", Value: fullBranchName},
+		{Name: "base", Value: startPoint},
+	}, fmt.Sprintf("Started `%s` from `%s`", fullBranchName, createFrom))
+	return nil
+}
+
+// checkStartTag verifies the tag finish will create for the new branch is a
+// valid, unused tag name that no branch shares, and that no tag shares the
+// name of the new branch
+func checkStartTag(deps *Deps, cfg *config.Config, branchType, name, fullBranchName, startPoint string) error {
+	if deps.Git.TagExists(fullBranchName) {
+		return &errors.TagConflictError{BranchName: fullBranchName, TagName: fullBranchName, Reason: fmt.Sprintf("tag '%s' has the same name as the branch", fullBranchName)}
+	}
+	tagName := config.ResolveStartTagName(cfg, branchType, name, startPoint)
+	if tagName == "" {
+		return nil
+	}
+	if !deps.Git.IsValidTagName(tagName) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a valid tag name, and finish would tag '%s' with it", tagName, fullBranchName)}
+	}
+	if deps.Git.TagExists(tagName) {
+		return &errors.TagConflictError{BranchName: fullBranchName, TagName: tagName, Reason: fmt.Sprintf("tag '%s' already exists", tagName)}
+	}
+	if deps.Git.BranchExists(tagName) == nil {
+		return &errors.TagConflictError{BranchName: fullBranchName, TagName: tagName, Reason: fmt.Sprintf("branch '%s' has the same name as the tag", tagName)}
+	}
+	return nil
+}
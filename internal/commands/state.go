@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// StateRepairOptions are the options of a state repair. Without either, the
+// user is asked what to do with a state that has problems.
+type StateRepairOptions struct {
+	// Discard removes the state without asking
+	Discard bool
+	// Reconstruct reconstructs the state from the repository, or restores its
+	// backup, without asking
+	Reconstruct bool
+}
+
+// StateShow prints the recorded state of an interrupted operation
+func StateShow(deps *Deps) error {
+	return executeStateShow(deps)
+}
+
+// StateRepair validates the recorded state against the repository and
+// discards or reconstructs it
+func StateRepair(deps *Deps, options StateRepairOptions) error {
+	return executeStateRepair(deps, options.Discard, options.Reconstruct)
+}
+
+// executeStateShow prints the recorded merge state
+func executeStateShow(deps *Deps) error {
+	statePath, err := deps.State.Path()
+	if err != nil {
+		return &errors.GitError{Operation: "determine state path", Err: err}
+	}
+
+	raw, err := deps.State.ReadRaw()
+	if err != nil {
+		return &errors.GitError{Operation: "read merge state", Err: err}
+	}
+	if raw == nil {
+		fmt.Fprintln(deps.Out, "No git-flow operation in progress")
+		printOtherWorktreeStates(deps)
+		return nil
+	}
+
+	var state mergestate.MergeState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		fmt.Fprintf(deps.Out, "State file:      %s\n", statePath)
+		fmt.Fprintf(deps.Out, "Status:          unreadable (%v)\n", err)
+		fmt.Fprintf(deps.Out, "\nRaw contents:\n%s\n", string(raw))
+		if backup, _ := deps.State.LoadBackup(); backup != nil {
+			fmt.Fprintf(deps.Out, "\nBackup:          %s of '%s' at step '%s'\n", backup.Action, backup.FullBranchName, backup.CurrentStep)
+			fmt.Fprintf(deps.Out, "\nRun 'git flow state repair' to restore or discard it.\n")
+		} else {
+			fmt.Fprintf(deps.Out, "\nRun 'git flow state repair' to discard it.\n")
+		}
+		return nil
+	}
+
+	fmt.Fprintf(deps.Out, "State file:      %s\n", statePath)
+	fmt.Fprintf(deps.Out, "Operation:       %s\n", state.Action)
+	if state.BranchType != "" {
+		fmt.Fprintf(deps.Out, "Branch:          %s (%s)\n", state.FullBranchName, state.BranchType)
+	} else {
+		fmt.Fprintf(deps.Out, "Branch:          %s\n", state.FullBranchName)
+	}
+	fmt.Fprintf(deps.Out, "Target:          %s\n", state.ParentBranch)
+	fmt.Fprintf(deps.Out, "Strategy:        %s\n", state.MergeStrategy)
+	fmt.Fprintf(deps.Out, "Current step:    %s\n", state.CurrentStep)
+	if len(state.ChildBranches) > 0 {
+		fmt.Fprintf(deps.Out, "Child branches:  %s\n", strings.Join(state.ChildBranches, ", "))
+		updated := "(none)"
+		if len(state.UpdatedBranches) > 0 {
+			updated = strings.Join(state.UpdatedBranches, ", ")
+		}
+		fmt.Fprintf(deps.Out, "Updated:         %s\n", updated)
+	}
+	if state.CurrentChildBranch != "" {
+		fmt.Fprintf(deps.Out, "Updating child:  %s\n", state.CurrentChildBranch)
+	}
+	if state.NoVerify {
+		fmt.Fprintf(deps.Out, "No verify:       true\n")
+	}
+	if state.NoVerifyChildren {
+		fmt.Fprintf(deps.Out, "No verify child: true\n")
+	}
+	if state.Interrupted {
+		fmt.Fprintf(deps.Out, "Interrupted:     stopped by a signal between steps\n")
+	}
+
+	problems, _ := validateMergeState(deps, &state)
+	if len(problems) > 0 {
+		fmt.Fprintf(deps.Out, "\nProblems:\n")
+		for _, problem := range problems {
+			fmt.Fprintf(deps.Out, "  ✗ %s\n", problem)
+		}
+		fmt.Fprintf(deps.Out, "\nRun 'git flow state repair' to fix them.\n")
+	}
+	printOtherWorktreeStates(deps)
+
+	return nil
+}
+
+// printOtherWorktreeStates lists the operations in progress in the other
+// worktrees of the repository. Each worktree has its own state, so these are
+// continued or aborted from their worktree, not from here.
+func printOtherWorktreeStates(deps *Deps) {
+	worktrees, err := deps.Git.ListWorktrees()
+	if err != nil {
+		return
+	}
+	states := mergestate.StatesOfWorktrees(worktrees)
+	if len(states) == 0 {
+		return
+	}
+	fmt.Fprintf(deps.Out, "\nIn other worktrees:\n")
+	for _, other := range states {
+		fmt.Fprintf(deps.Out, "  %s of '%s' into '%s' at step '%s' in %s\n", other.State.Action, other.State.FullBranchName, other.State.ParentBranch, other.State.CurrentStep, other.Worktree)
+	}
+}
+
+// executeStateRepair validates the merge state and discards or reconstructs it
+func executeStateRepair(deps *Deps, discard bool, reconstruct bool) error {
+	if discard && reconstruct {
+		return &errors.InvalidInputError{Message: "--discard and --reconstruct cannot be used together"}
+	}
+
+	raw, err := deps.State.ReadRaw()
+	if err != nil {
+		return &errors.GitError{Operation: "read merge state", Err: err}
+	}
+	if raw == nil {
+		fmt.Fprintln(deps.Out, "No git-flow operation in progress, nothing to repair")
+		return nil
+	}
+
+	var state mergestate.MergeState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		fmt.Fprintf(deps.Out, "State file is unreadable: %v\n", err)
+		return repairUnreadableState(deps, discard, reconstruct)
+	}
+
+	problems, canReconstruct := validateMergeState(deps, &state)
+	if len(problems) == 0 {
+		fmt.Fprintf(deps.Out, "State for %s of '%s' is consistent with the repository\n", state.Action, state.FullBranchName)
+		if discard {
+			return discardMergeState(deps)
+		}
+		return nil
+	}
+
+	fmt.Fprintln(deps.Out, "Problems found:")
+	for _, problem := range problems {
+		fmt.Fprintf(deps.Out, "  ✗ %s\n", problem)
+	}
+
+	if reconstruct && !canReconstruct {
+		return &errors.InvalidInputError{Message: "state cannot be reconstructed because required branches are missing, use --discard"}
+	}
+
+	// Interactive choice when no flag decided it
+	if !discard && !reconstruct {
+		prompt := "[d]iscard or [k]eep as is? [d/K]: "
+		if canReconstruct {
+			prompt = "[r]econstruct from repository, [d]iscard, or [k]eep as is? [r/d/K]: "
+		}
+		switch strings.ToLower(deps.Prompter.Ask(prompt)) {
+		case "d":
+			discard = true
+		case "r":
+			reconstruct = canReconstruct
+		}
+	}
+
+	if discard {
+		return discardMergeState(deps)
+	}
+	if reconstruct {
+		if completed := reconstructMergeState(deps, &state); completed {
+			fmt.Fprintf(deps.Out, "The %s of '%s' is already complete\n", state.Action, state.FullBranchName)
+			return discardMergeState(deps)
+		}
+		if err := deps.State.Save(&state); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		fmt.Fprintf(deps.Out, "Reconstructed state: %s of '%s' resumes at step '%s'\n", state.Action, state.FullBranchName, state.CurrentStep)
+		if state.Action == "finish" && state.BranchType != "" {
+			fmt.Fprintf(deps.Out, "Run 'git flow %s finish --continue %s' to resume\n", state.BranchType, state.BranchName)
+		} else if state.Action == ActionSyncBases {
+			fmt.Fprintln(deps.Out, "Run 'git flow sync-bases --continue' to resume")
+		}
+		return nil
+	}
+
+	fmt.Fprintln(deps.Out, "State left unchanged")
+	return nil
+}
+
+// repairUnreadableState handles a state file that no longer parses. The backup of
+// the previous state, if intact, can be restored in its place.
+func repairUnreadableState(deps *Deps, discard bool, reconstruct bool) error {
+	backup, _ := deps.State.LoadBackup()
+	if backup == nil {
+		if reconstruct {
+			return &errors.InvalidInputError{Message: "an unreadable state file without backup cannot be reconstructed, use --discard"}
+		}
+		if !discard && !deps.Prompter.Confirm("Discard it? [y/N]: ", false) {
+			return fmt.Errorf("operation cancelled by user")
+		}
+		return discardMergeState(deps)
+	}
+
+	fmt.Fprintf(deps.Out, "A backup of the previous state is available: %s of '%s' at step '%s'\n", backup.Action, backup.FullBranchName, backup.CurrentStep)
+	if !discard && !reconstruct {
+		switch strings.ToLower(deps.Prompter.Ask("[r]estore backup, [d]iscard, or [k]eep as is? [r/d/K]: ")) {
+		case "d":
+			discard = true
+		case "r":
+			reconstruct = true
+		}
+	}
+
+	if discard {
+		return discardMergeState(deps)
+	}
+	if reconstruct {
+		if err := deps.State.Save(backup); err != nil {
+			return &errors.GitError{Operation: "save merge state", Err: err}
+		}
+		fmt.Fprintln(deps.Out, "Restored merge state from backup")
+		return nil
+	}
+
+	fmt.Fprintln(deps.Out, "State left unchanged")
+	return nil
+}
+
+// validateMergeState checks the recorded state against the repository. It returns
+// the problems found and whether the state can be reconstructed from the repository.
+func validateMergeState(deps *Deps, state *mergestate.MergeState) ([]string, bool) {
+	problems := []string{}
+	canReconstruct := true
+
+	switch state.Action {
+	case "finish", "update", ActionSyncBases:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown operation '%s'", state.Action))
+		canReconstruct = false
+	}
+
+	switch state.CurrentStep {
+	case mergestate.StepMerge, mergestate.StepCreateTag, mergestate.StepUpdateChildren, mergestate.StepExtraTags, mergestate.StepPush, mergestate.StepDeleteBranch:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown step '%s'", state.CurrentStep))
+		canReconstruct = false
+	}
+
+	// The topic branch legitimately disappears during the delete step
+	if state.FullBranchName != "" && state.CurrentStep != mergestate.StepDeleteBranch && deps.Git.BranchExists(state.FullBranchName) != nil {
+		problems = append(problems, fmt.Sprintf("branch '%s' no longer exists", state.FullBranchName))
+		canReconstruct = false
+	}
+	if state.ParentBranch != "" && deps.Git.BranchExists(state.ParentBranch) != nil {
+		problems = append(problems, fmt.Sprintf("target branch '%s' no longer exists", state.ParentBranch))
+		canReconstruct = false
+	}
+	for _, child := range state.ChildBranches {
+		if !state.IsChildUpdated(child) && deps.Git.BranchExists(child) != nil {
+			problems = append(problems, fmt.Sprintf("child branch '%s' no longer exists", child))
+		}
+	}
+	if !canReconstruct {
+		return problems, false
+	}
+
+	// Detect steps that were completed by hand while no Git operation is pending
+	if operation, err := deps.Git.GetOperationInProgress(); err == nil && operation == "" {
+		if isMergeStepDone(deps, state) {
+			if state.Action == "update" {
+				problems = append(problems, fmt.Sprintf("'%s' is already updated from '%s'", state.FullBranchName, state.ParentBranch))
+			} else {
+				problems = append(problems, fmt.Sprintf("'%s' is already merged into '%s'", state.FullBranchName, state.ParentBranch))
+			}
+		}
+		if state.CurrentChildBranch != "" && deps.Git.BranchExists(state.CurrentChildBranch) == nil && deps.Git.IsAncestor(state.ParentBranch, state.CurrentChildBranch) {
+			problems = append(problems, fmt.Sprintf("child branch '%s' is already updated from '%s'", state.CurrentChildBranch, state.ParentBranch))
+		}
+	}
+
+	return problems, true
+}
+
+// isMergeStepDone reports whether the merge recorded in the state is already
+// reflected in the repository. Squash merges leave no ancestry and can't be detected.
+func isMergeStepDone(deps *Deps, state *mergestate.MergeState) bool {
+	if state.CurrentStep != mergestate.StepMerge || state.MergeStrategy == string(config.MergeStrategySquash) {
+		return false
+	}
+	if state.Action == "update" {
+		// Update merges the parent into the branch
+		return deps.Git.IsAncestor(state.ParentBranch, state.FullBranchName)
+	}
+	return deps.Git.IsAncestor(state.FullBranchName, state.ParentBranch)
+}
+
+// reconstructMergeState advances the state past steps the repository shows as done
+// and drops child branches that no longer exist. It returns true when nothing is
+// left to do, in which case the state should be discarded.
+func reconstructMergeState(deps *Deps, state *mergestate.MergeState) bool {
+	if isMergeStepDone(deps, state) {
+		if state.Action == "update" {
+			return true
+		}
+		state.CurrentStep = mergestate.StepCreateTag
+	}
+
+	children := []string{}
+	for _, child := range state.ChildBranches {
+		if state.IsChildUpdated(child) {
+			children = append(children, child)
+			continue
+		}
+		if deps.Git.BranchExists(child) != nil {
+			continue
+		}
+		children = append(children, child)
+		if deps.Git.IsAncestor(childParent(state, child), child) && state.CurrentStep == mergestate.StepUpdateChildren {
+			state.UpdatedBranches = append(state.UpdatedBranches, child)
+		}
+	}
+	state.ChildBranches = children
+
+	if state.CurrentChildBranch != "" && (state.IsChildUpdated(state.CurrentChildBranch) || deps.Git.BranchExists(state.CurrentChildBranch) != nil) {
+		state.CurrentChildBranch = ""
+	}
+	return false
+}
+
+// childParent returns the branch a child branch is updated from
+func childParent(state *mergestate.MergeState, child string) string {
+	if parent := state.ChildParents[child]; parent != "" {
+		return parent
+	}
+	return state.ParentBranch
+}
+
+// discardMergeState removes the merge state file
+func discardMergeState(deps *Deps) error {
+	if err := deps.State.Clear(); err != nil {
+		return &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+	fmt.Fprintln(deps.Out, "Discarded merge state")
+	return nil
+}
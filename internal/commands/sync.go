@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// SyncOptions are the options of a sync
+type SyncOptions struct {
+	// Rebase updates the current topic branch with rebase instead of the
+	// configured strategy
+	Rebase   bool
+	NoVerify *bool
+}
+
+// syncResult is one line of the sync summary
+type syncResult struct {
+	branch string
+	status string
+}
+
+// Sync fetches, fast-forwards the base branches and updates the current topic
+// branch. The summary is printed even when the topic update fails. Cancelling
+// ctx stops the update as it does for Update.
+func Sync(ctx context.Context, deps *Deps, cfgCtx *config.Context, options SyncOptions) error {
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+	state, err := deps.State.Load()
+	if _, unreadable := err.(*errors.UnreadableStateError); unreadable {
+		return err
+	}
+	if state != nil {
+		return &errors.MergeInProgressError{BranchName: state.FullBranchName}
+	}
+	cfg := cfgCtx.Config
+
+	currentBranch := ""
+	if !deps.Git.IsDetachedHead() {
+		currentBranch, _ = deps.Git.GetCurrentBranch()
+	}
+
+	var results []syncResult
+
+	// Fetch; without a reachable remote the local branches are still synced
+	fetched := false
+	if cfg.Remote != "" && deps.Git.RemoteExists(cfg.Remote) {
+		fmt.Fprintf(deps.Out, "Fetching from '%s'...\n", cfg.Remote)
+		if err := deps.Remote.Fetch(ctx, cfg.Remote); err != nil {
+			WarnFetchFailed(deps, cfg.Remote, err)
+			results = append(results, syncResult{cfg.Remote, "fetch failed, base branches not fast-forwarded"})
+		} else {
+			fetched = true
+		}
+	} else {
+		fmt.Fprintln(deps.Out, "No remote configured, base branches are not fast-forwarded")
+	}
+
+	if fetched {
+		for _, branch := range syncBaseBranches(cfg) {
+			results = append(results, syncResult{branch, FastForwardBaseBranch(ctx, deps, cfg.Remote, branch, currentBranch)})
+		}
+	}
+
+	// Update the current topic branch from its parent
+	var updateErr error
+	branchType, _ := DetectBranchTypeFromName(cfg, currentBranch)
+	switch {
+	case currentBranch == "":
+		results = append(results, syncResult{"HEAD", "detached, no topic branch updated"})
+	case branchType == "":
+		if _, isBase := cfg.Branches[currentBranch]; !isBase {
+			results = append(results, syncResult{currentBranch, "not a topic branch, not updated"})
+		}
+	default:
+		fmt.Fprintf(deps.Out, "Updating '%s'...\n", currentBranch)
+		updateErr = Update(ctx, deps, cfgCtx, "", currentBranch, UpdateOptions{Rebase: options.Rebase, NoVerify: options.NoVerify})
+		if updateErr != nil {
+			results = append(results, syncResult{currentBranch, "update failed, see below"})
+		} else {
+			results = append(results, syncResult{currentBranch, "updated from its parent"})
+		}
+	}
+
+	printSyncSummary(deps.Out, results)
+	return updateErr
+}
+
+// syncBaseBranches returns the configured base branches, trunk branches
+// first, each level sorted by name
+func syncBaseBranches(cfg *config.Config) []string {
+	depth := func(name string) int {
+		d := 0
+		seen := map[string]bool{name: true}
+		for parent := cfg.Branches[name].Parent; parent != "" && !seen[parent]; parent = cfg.Branches[parent].Parent {
+			seen[parent] = true
+			d++
+		}
+		return d
+	}
+
+	var bases []string
+	for name, branch := range cfg.Branches {
+		if branch.Type == string(config.BranchTypeBase) {
+			bases = append(bases, name)
+		}
+	}
+	sort.Slice(bases, func(i, j int) bool {
+		if di, dj := depth(bases[i]), depth(bases[j]); di != dj {
+			return di < dj
+		}
+		return bases[i] < bases[j]
+	})
+	return bases
+}
+
+// printSyncSummary prints the status of each synced branch
+func printSyncSummary(out io.Writer, results []syncResult) {
+	width := 0
+	for _, result := range results {
+		if len(result.branch) > width {
+			width = len(result.branch)
+		}
+	}
+	fmt.Fprintln(out, "\nSummary:")
+	for _, result := range results {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, result.branch, result.status)
+	}
+}
@@ -0,0 +1,463 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/journal"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/output"
+	"github.com/gittower/git-flow-next/internal/update"
+)
+
+// ActionSyncBases is the merge state action of an interrupted sync-bases run
+const ActionSyncBases = "sync-bases"
+
+// SyncBasesOptions are the options of a sync-bases run
+type SyncBasesOptions struct {
+	// Continue completes the update stopped on conflicts and goes on with
+	// the remaining branches
+	Continue bool
+	// Abort abandons the update of the current branch and stops
+	Abort bool
+	// Check only reports the base branches that are behind their parent
+	Check bool
+	// Push fast-forwards the base branches from the remote first and pushes
+	// the updated branches
+	Push     bool
+	NoVerify *bool
+}
+
+// SyncBases starts, continues or aborts updating each base branch from its
+// parent, down the branch hierarchy, or only checks them. It reports whether
+// branches were updated, or would be. Cancelling ctx stops the running git
+// command; the update it cut short is undone and runs again on --continue.
+func SyncBases(ctx context.Context, deps *Deps, cfgCtx *config.Context, options SyncBasesOptions) (bool, error) {
+	return executeSyncBases(ctx, deps, cfgCtx, options.Continue, options.Abort, options.Check, options.Push, options.NoVerify)
+}
+
+// executeSyncBases starts, continues or aborts updating the base branches, or
+// only checks them. It reports whether branches were updated, or would be.
+func executeSyncBases(ctx context.Context, deps *Deps, cfgCtx *config.Context, continueOp bool, abortOp bool, check bool, push bool, noVerify *bool) (bool, error) {
+	if !cfgCtx.Initialized {
+		return false, &errors.NotInitializedError{}
+	}
+	cfg := cfgCtx.Config
+
+	if check && (push || continueOp || abortOp) {
+		return false, &errors.InvalidInputError{Message: "--check cannot be combined with --push, --continue or --abort"}
+	}
+	if push && (continueOp || abortOp) {
+		return false, &errors.InvalidInputError{Message: "--push applies to a new run; --continue pushes when the interrupted run used it"}
+	}
+	if check {
+		return checkSyncBases(deps, cfg), nil
+	}
+
+	if deps.State.InProgress() {
+		state, err := deps.State.Load()
+		if _, unreadable := err.(*errors.UnreadableStateError); unreadable {
+			return false, err
+		}
+		if err != nil {
+			return false, &errors.GitError{Operation: "load merge state", Err: err}
+		}
+		if state.Action != ActionSyncBases {
+			return false, &errors.MergeInProgressError{BranchName: state.FullBranchName}
+		}
+		switch {
+		case abortOp:
+			return false, abortSyncBases(deps, state)
+		case continueOp:
+			if state.CurrentChildBranch != "" {
+				if deps.Git.HasConflicts() {
+					return false, &errors.UnresolvedConflictsError{}
+				}
+				// A base branch update rejected by a hook can be continued without it
+				if noVerify != nil {
+					state.NoVerifyChildren = *noVerify
+				}
+				if err := CompleteChildUpdate(ctx, deps, cfg, state, state.CurrentChildBranch, ""); err != nil {
+					return false, err
+				}
+			}
+			return runSyncBases(ctx, deps, cfg, state)
+		}
+		return false, &errors.MergeInProgressError{BranchName: state.CurrentChildBranch}
+	}
+
+	if continueOp || abortOp {
+		return false, &errors.NoMergeInProgressError{}
+	}
+
+	if operation, err := deps.Git.GetOperationInProgress(); err != nil {
+		return false, &errors.GitError{Operation: "check for operations in progress", Err: err}
+	} else if operation != "" {
+		return false, &errors.GitError{Operation: "update base branches", Err: fmt.Errorf("a %s is in progress; complete or abort it first", operation)}
+	}
+	if dirty, err := deps.Git.HasUncommittedChanges(); err != nil {
+		return false, &errors.GitError{Operation: "check working tree", Err: err}
+	} else if dirty {
+		return false, &errors.GitError{Operation: "update base branches", Err: fmt.Errorf("working tree has uncommitted changes; commit or stash them first")}
+	}
+
+	// Return to the current branch when done
+	startBranch := ""
+	if !deps.Git.IsDetachedHead() {
+		startBranch, _ = deps.Git.GetCurrentBranch()
+	}
+
+	if push {
+		if err := pullBaseBranches(ctx, deps, cfg, startBranch); err != nil {
+			return false, err
+		}
+	}
+
+	state := &mergestate.MergeState{
+		Action:           ActionSyncBases,
+		CurrentStep:      mergestate.StepUpdateChildren,
+		FullBranchName:   startBranch,
+		ChildBranches:    syncBasesPlan(cfg),
+		UpdatedBranches:  []string{},
+		ChildStrategies:  make(map[string]string),
+		ChildParents:     make(map[string]string),
+		NoVerifyChildren: config.ResolveUpdateNoVerify(cfg, "", noVerify),
+		Push:             push,
+	}
+	for _, branchName := range state.ChildBranches {
+		state.ChildParents[branchName] = cfg.Branches[branchName].Parent
+		state.ChildStrategies[branchName] = cfg.Branches[branchName].DownstreamStrategy
+	}
+	if len(state.ChildBranches) == 0 {
+		fmt.Fprintln(deps.Out, "No base branches with a parent branch are configured")
+		return false, nil
+	}
+	return runSyncBases(ctx, deps, cfg, state)
+}
+
+// syncBasesPlan returns the base branches that have a parent in the order they
+// are updated: each branch after its parent, siblings in update order
+func syncBasesPlan(cfg *config.Config) []string {
+	roots := []string{}
+	children := make(map[string][]string)
+	for name, branch := range cfg.Branches {
+		if branch.Type != string(config.BranchTypeBase) {
+			continue
+		}
+		if branch.Parent == "" {
+			roots = append(roots, name)
+		} else {
+			children[branch.Parent] = append(children[branch.Parent], name)
+		}
+	}
+	sort.Strings(roots)
+
+	plan := []string{}
+	visited := make(map[string]bool)
+	var walk func(parent string)
+	walk = func(parent string) {
+		config.SortByUpdateOrder(cfg, children[parent])
+		for _, child := range children[parent] {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			plan = append(plan, child)
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return plan
+}
+
+// runSyncBases updates the remaining branches of the plan, saving the state
+// before each update so a conflict can be continued. It reports whether any
+// branch was updated.
+func runSyncBases(ctx context.Context, deps *Deps, cfg *config.Config, state *mergestate.MergeState) (bool, error) {
+	for {
+		branchName := nextSyncBase(state)
+		if branchName == "" {
+			break
+		}
+		parent := state.ChildParents[branchName]
+
+		if deps.Git.BranchExists(branchName) != nil || deps.Git.BranchExists(parent) != nil || deps.Git.IsAncestor(parent, branchName) {
+			state.SkippedBranches = append(state.SkippedBranches, branchName)
+			if err := deps.State.Save(state); err != nil {
+				return false, &errors.GitError{Operation: "save merge state", Err: err}
+			}
+			continue
+		}
+
+		strategy := state.ChildStrategies[branchName]
+		state.ParentBranch = parent
+		state.CurrentChildBranch = branchName
+		state.StepStartCommit, _ = deps.Git.BranchCommit(branchName)
+		if err := deps.State.Save(state); err != nil {
+			return false, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+
+		fmt.Fprintf(deps.Out, "Updating base branch '%s' from '%s' (strategy: %s)...\n", branchName, parent, EffectiveChildStrategy(strategy))
+		deps.Events.StepStart(events.StepStart{Operation: ActionSyncBases, Branch: branchName, Step: mergestate.StepUpdateChildren, Label: fmt.Sprintf("update %s from %s", branchName, parent)})
+		resolution := update.ConflictResolutionFor(cfg.Branches[branchName])
+		if err := newUpdater(deps, cfg).FromParentWithResolution(ctx, branchName, parent, strategy, "", state.NoVerifyChildren, resolution, state); err != nil {
+			// The update may have failed only because its git command was stopped
+			if ctx.Err() != nil {
+				return false, stopSyncBasesCancelled(deps, state)
+			}
+			if _, ok := err.(*errors.UnresolvedConflictsError); ok {
+				fmt.Fprintf(deps.Out, "\nUpdating '%s' from '%s' stopped on conflicts.\n", branchName, parent)
+				fmt.Fprintln(deps.Out, "Resolve them and stage the files with 'git add', then run 'git flow sync-bases --continue'")
+				fmt.Fprintln(deps.Out, "or run 'git flow sync-bases --abort' to stop here.")
+			}
+			return false, err
+		}
+
+		state.UpdatedBranches = append(state.UpdatedBranches, branchName)
+		state.CurrentChildBranch = ""
+		if err := deps.State.Save(state); err != nil {
+			return false, &errors.GitError{Operation: "save merge state", Err: err}
+		}
+	}
+
+	if state.FullBranchName != "" {
+		if current, _ := deps.Git.GetCurrentBranch(); current != state.FullBranchName {
+			if err := deps.Git.Checkout(state.FullBranchName); err != nil {
+				return false, &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", state.FullBranchName), Err: err}
+			}
+		}
+	}
+
+	// A failed push keeps the state, so --continue retries it
+	if state.Push && len(state.UpdatedBranches) > 0 {
+		fmt.Fprintf(deps.Out, "Pushing to remote '%s'...\n", cfg.Remote)
+		if err := deps.Remote.PushRefs(ctx, cfg.Remote, state.UpdatedBranches, nil); err != nil {
+			return false, &errors.GitError{Operation: "push updated base branches", Err: fmt.Errorf("%w; fix the problem and run 'git flow sync-bases --continue' to retry", err)}
+		}
+		fmt.Fprintf(deps.Out, "Pushed to '%s'\n", cfg.Remote)
+	}
+
+	if err := deps.State.Clear(); err != nil {
+		return false, &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+
+	printSyncSummary(deps.Out, syncBasesResults(deps, state))
+	reconcileSkippedUpdates(deps)
+	for _, branchName := range state.UpdatedBranches {
+		deps.result("%s", branchName)
+	}
+	reportSyncBasesToActions(deps, state)
+	return len(state.UpdatedBranches) > 0, nil
+}
+
+// stopSyncBasesCancelled undoes the update of a base branch stopped by the
+// cancellation of its context and keeps the state, so that --continue runs
+// the update again
+func stopSyncBasesCancelled(deps *Deps, state *mergestate.MergeState) error {
+	if err := deps.Git.UndoStoppedOperation(); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("undo the update of '%s' stopped by the cancellation", state.CurrentChildBranch), Err: err}
+	}
+	state.CurrentChildBranch = ""
+	if err := deps.State.Save(state); err != nil {
+		return &errors.GitError{Operation: "save merge state", Err: err}
+	}
+	return &errors.InterruptedError{
+		Signal:        "cancellation",
+		Step:          state.CurrentStep,
+		ResumeCommand: "git flow sync-bases --continue",
+	}
+}
+
+// reportSyncBasesToActions writes the updated base branches as GitHub Actions
+// step output and job summary
+func reportSyncBasesToActions(deps *Deps, state *mergestate.MergeState) {
+	var summary strings.Builder
+	summary.WriteString("### Synced base branches\n\n")
+	for _, result := range syncBasesResults(deps, state) {
+		fmt.Fprintf(&summary, "- `%s` %s\n", result.branch, result.status)
+	}
+	output.ReportToActions([]output.ActionOutput{
+		{Name: "updated-branches", Value: strings.Join(state.UpdatedBranches, " ")},
+	}, summary.String())
+}
+
+// pullBaseBranches fetches and brings the local base branches up to date with
+// their remote branches before --push updates them, creating the ones that
+// only exist on the remote, as in a fresh CI clone
+func pullBaseBranches(ctx context.Context, deps *Deps, cfg *config.Config, currentBranch string) error {
+	if cfg.Remote == "" || !deps.Git.RemoteExists(cfg.Remote) {
+		return &errors.InvalidInputError{Message: fmt.Sprintf("--push needs the remote '%s'", cfg.Remote)}
+	}
+	fmt.Fprintf(deps.Out, "Fetching from '%s'...\n", cfg.Remote)
+	if err := deps.Remote.Fetch(ctx, cfg.Remote); err != nil {
+		return &errors.GitError{Operation: fmt.Sprintf("fetch from '%s'", cfg.Remote), Err: err}
+	}
+
+	for _, branchName := range syncBaseBranches(cfg) {
+		if deps.Git.BranchExists(branchName) != nil {
+			if !deps.Git.RemoteBranchExists(cfg.Remote, branchName) {
+				continue
+			}
+			if err := deps.Git.CreateBranch(branchName, cfg.Remote+"/"+branchName); err != nil {
+				return &errors.GitError{Operation: fmt.Sprintf("create branch '%s'", branchName), Err: err}
+			}
+			fmt.Fprintf(deps.Out, "Created local branch '%s' from '%s/%s'\n", branchName, cfg.Remote, branchName)
+			continue
+		}
+		if status := FastForwardBaseBranch(ctx, deps, cfg.Remote, branchName, currentBranch); status != "up to date" && status != "no remote branch" {
+			fmt.Fprintf(deps.Out, "Local branch '%s': %s\n", branchName, status)
+		}
+	}
+	return nil
+}
+
+// checkSyncBases reports which base branches are behind their parent without
+// updating them. Branches that only exist on the remote are compared through
+// their remote-tracking branch. It reports whether any branch is behind.
+func checkSyncBases(deps *Deps, cfg *config.Config) bool {
+	ref := func(branchName string) string {
+		if deps.Git.BranchExists(branchName) == nil {
+			return branchName
+		}
+		if cfg.Remote != "" && deps.Git.RemoteBranchExists(cfg.Remote, branchName) {
+			return cfg.Remote + "/" + branchName
+		}
+		return ""
+	}
+
+	behindAny := false
+	results := []syncResult{}
+	for _, branchName := range syncBasesPlan(cfg) {
+		parent := cfg.Branches[branchName].Parent
+		branchRef, parentRef := ref(branchName), ref(parent)
+		var status string
+		switch {
+		case branchRef == "":
+			status = "no local branch"
+		case parentRef == "":
+			status = fmt.Sprintf("parent '%s' has no local branch", parent)
+		default:
+			_, behind, err := deps.Git.AheadBehind(branchRef, parentRef)
+			switch {
+			case err != nil:
+				status = fmt.Sprintf("could not compare with '%s'", parentRef)
+			case behind == 0:
+				status = fmt.Sprintf("up to date with '%s'", parentRef)
+			default:
+				behindAny = true
+				status = fmt.Sprintf("%d commit(s) behind '%s', would be updated (strategy: %s)", behind, parentRef, EffectiveChildStrategy(cfg.Branches[branchName].DownstreamStrategy))
+				deps.result("%s", branchName)
+			}
+		}
+		results = append(results, syncResult{branchName, status})
+	}
+
+	printSyncSummary(deps.Out, results)
+	return behindAny
+}
+
+// nextSyncBase returns the next branch of the plan that was neither updated
+// nor skipped
+func nextSyncBase(state *mergestate.MergeState) string {
+	done := make(map[string]bool)
+	for _, branchName := range state.UpdatedBranches {
+		done[branchName] = true
+	}
+	for _, branchName := range state.SkippedBranches {
+		done[branchName] = true
+	}
+	for _, branchName := range state.ChildBranches {
+		if !done[branchName] {
+			return branchName
+		}
+	}
+	return ""
+}
+
+// syncBasesResults describes what happened to each branch of the plan
+func syncBasesResults(deps *Deps, state *mergestate.MergeState) []syncResult {
+	results := make([]syncResult, 0, len(state.ChildBranches))
+	for _, branchName := range state.ChildBranches {
+		parent := state.ChildParents[branchName]
+		var status string
+		switch {
+		case state.IsChildUpdated(branchName):
+			status = fmt.Sprintf("updated from '%s' (strategy: %s)", parent, EffectiveChildStrategy(state.ChildStrategies[branchName]))
+		case deps.Git.BranchExists(branchName) != nil:
+			status = "no local branch"
+		case deps.Git.BranchExists(parent) != nil:
+			status = fmt.Sprintf("parent '%s' has no local branch", parent)
+		default:
+			status = fmt.Sprintf("up to date with '%s'", parent)
+		}
+		results = append(results, syncResult{branchName, status})
+	}
+	return results
+}
+
+// reconcileSkippedUpdates marks the updates skipped by finish --no-update as
+// reconciled in the journal once the branch contains its parent
+func reconcileSkippedUpdates(deps *Deps) {
+	pending, err := journal.PendingUpdates()
+	if err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to read the journal: %v\n", err)
+		return
+	}
+
+	entry := journal.Entry{Operation: journal.OperationSyncBases}
+	names := []string{}
+	for _, skipped := range pending {
+		if deps.Git.BranchExists(skipped.Branch) == nil && deps.Git.BranchExists(skipped.Parent) == nil && deps.Git.IsAncestor(skipped.Parent, skipped.Branch) {
+			entry.ReconciledUpdates = append(entry.ReconciledUpdates, skipped)
+			names = append(names, fmt.Sprintf("'%s' from '%s'", skipped.Branch, skipped.Parent))
+		}
+	}
+	if len(entry.ReconciledUpdates) == 0 {
+		return
+	}
+	if err := journal.Append(entry); err != nil {
+		fmt.Fprintf(deps.Err, "Warning: Failed to record the reconciled updates: %v\n", err)
+		return
+	}
+	fmt.Fprintf(deps.Out, "Reconciled updates skipped by finish: %s\n", strings.Join(names, ", "))
+}
+
+// abortSyncBases aborts the update of the current branch and returns to the
+// branch sync-bases started on. Branches updated before are kept.
+func abortSyncBases(deps *Deps, state *mergestate.MergeState) error {
+	if operation, _ := deps.Git.GetOperationInProgress(); operation != "" {
+		var err error
+		if operation == "rebase" {
+			err = deps.Git.RebaseAbort()
+		} else {
+			err = deps.Git.MergeAbort()
+		}
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("abort %s", operation), Err: err}
+		}
+	}
+
+	if state.FullBranchName != "" {
+		if err := deps.Git.Checkout(state.FullBranchName); err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("checkout original branch '%s'", state.FullBranchName), Err: err}
+		}
+	}
+	if err := deps.State.Clear(); err != nil {
+		return &errors.GitError{Operation: "clear merge state", Err: err}
+	}
+
+	if state.CurrentChildBranch != "" {
+		fmt.Fprintf(deps.Out, "Aborted the update of '%s' from '%s'\n", state.CurrentChildBranch, state.ParentBranch)
+	}
+	if len(state.UpdatedBranches) > 0 {
+		fmt.Fprintf(deps.Out, "Base branches already updated are kept: %s\n", strings.Join(state.UpdatedBranches, ", "))
+	}
+	return nil
+}
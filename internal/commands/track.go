@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/hooks"
+)
+
+// TrackOptions are the options of a track
+type TrackOptions struct {
+	// TrackUpstream makes the local branch track the remote branch it is
+	// created from; nil falls back to the configuration
+	TrackUpstream *bool
+}
+
+// Track creates a local branch of the topic branch name of branchType from its
+// remote branch, wrapped in the track hooks. Cancelling ctx stops the fetch.
+func Track(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options TrackOptions) error {
+	return track(ctx, deps, cfgCtx, branchType, name, options.TrackUpstream)
+}
+
+// track performs the actual tracking branch creation logic
+func track(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, trackUpstream *bool) error {
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Validate inputs
+	if name == "" {
+		return &errors.EmptyBranchNameError{}
+	}
+
+	// Get configuration
+	cfg := cfgCtx.Config
+
+	// Get branch configuration
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+
+	// Construct full branch name. A name given with the prefix or one of its
+	// aliases is reduced to the short name first.
+	fullBranchName, shortName, err := config.ResolveTopicName(cfg, branchType, name, nil)
+	if err != nil {
+		return err
+	}
+
+	// The remote branch may use the configured prefix or one of its aliases
+	var remoteCandidates []string
+	for _, prefix := range config.TopicPrefixes(branchConfig) {
+		remoteCandidates = append(remoteCandidates, prefix+shortName)
+	}
+
+	// Check if branch already exists locally
+	if err := deps.Git.BranchExists(fullBranchName); err == nil {
+		return &errors.BranchExistsError{BranchName: fullBranchName}
+	}
+
+	remote := cfg.Remote
+
+	// Get git directory for hooks
+	gitDir, err := deps.Git.GetGitDir()
+	if err != nil {
+		return &errors.GitError{Operation: "get git directory", Err: err}
+	}
+
+	// Build hook context
+	hookCtx := hooks.HookContext{
+		BranchType: branchType,
+		BranchName: shortName,
+		FullBranch: fullBranchName,
+		BaseBranch: branchConfig.Parent,
+		Origin:     remote,
+	}
+	if branchType == "release" || branchType == "hotfix" {
+		hookCtx.Version = shortName
+	}
+
+	// Run track operation wrapped with hooks
+	return hooks.WithHooks(gitDir, branchType, hooks.HookActionTrack, hookCtx, func() error {
+		return executeTrack(ctx, deps, fullBranchName, remoteCandidates, remote, config.ResolveTrackUpstream(cfg, branchType, trackUpstream))
+	})
+}
+
+// executeTrack performs the actual track operation (called within hooks wrapper).
+// The local branch is created from the first of remoteCandidates that exists on
+// the remote, and tracks it if setUpstream is set.
+func executeTrack(ctx context.Context, deps *Deps, fullBranchName string, remoteCandidates []string, remote string, setUpstream bool) error {
+	// Fetch from remote to ensure we have latest refs
+	fmt.Fprintf(deps.Out, "Fetching from '%s'...\n", remote)
+	if err := deps.Remote.Fetch(ctx, remote); err != nil {
+		return &errors.GitError{
+			Operation: fmt.Sprintf("fetch from remote '%s'", remote),
+			Err:       err,
+		}
+	}
+
+	// Check if branch exists on remote
+	remoteBranch := ""
+	for _, candidate := range remoteCandidates {
+		if deps.Git.RemoteBranchExists(remote, candidate) {
+			remoteBranch = candidate
+			break
+		}
+	}
+	if remoteBranch == "" {
+		return &errors.RemoteBranchNotFoundError{
+			Remote:      remote,
+			BranchName:  fullBranchName,
+			Suggestions: SimilarRemoteBranches(deps, remote, fullBranchName),
+		}
+	}
+
+	if !setUpstream {
+		if err := deps.Git.CreateBranchNoTrack(fullBranchName, remote+"/"+remoteBranch); err != nil {
+			return &errors.GitError{
+				Operation: fmt.Sprintf("create branch '%s'", fullBranchName),
+				Err:       err,
+			}
+		}
+		fmt.Fprintf(deps.Out, "Created branch '%s' from '%s/%s' without tracking it\n", fullBranchName, remote, remoteBranch)
+		deps.result("%s", fullBranchName)
+		return nil
+	}
+
+	// Create local tracking branch
+	fmt.Fprintf(deps.Out, "Setting up tracking branch for '%s'...\n", fullBranchName)
+	if err := deps.Git.CreateTrackingBranch(fullBranchName, remote, remoteBranch); err != nil {
+		return &errors.GitError{
+			Operation: fmt.Sprintf("create tracking branch '%s'", fullBranchName),
+			Err:       err,
+		}
+	}
+
+	fmt.Fprintf(deps.Out, "Successfully created tracking branch '%s' from '%s/%s'\n",
+		fullBranchName, remote, remoteBranch)
+	deps.result("%s", fullBranchName)
+	return nil
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/internal/interrupt"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/internal/profile"
+	"github.com/gittower/git-flow-next/internal/update"
+)
+
+// UpdateOptions are the options of an update
+type UpdateOptions struct {
+	// Rebase rebases the branch onto its parent instead of the configured
+	// downstream strategy
+	Rebase   bool
+	NoVerify *bool
+}
+
+// Update updates the topic branch name of branchType with the changes of its
+// parent branch. An empty branchType detects the type from the name, and an
+// empty name updates the current branch. Cancelling ctx stops the running git
+// command and undoes the merge or rebase it cut short.
+func Update(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, options UpdateOptions) error {
+	// Nothing may run once ctx is cancelled, as every long-running git command would fail
+	if ctx.Err() != nil {
+		return &errors.InterruptedError{Signal: "cancellation"}
+	}
+	return executeUpdate(ctx, deps, cfgCtx, branchType, name, options.Rebase, options.NoVerify)
+}
+
+// executeUpdate updates a branch with changes from its parent branch. Cancelling
+// ctx before the merge starts stops the update without touching the branch.
+func executeUpdate(ctx context.Context, deps *Deps, cfgCtx *config.Context, branchType string, name string, useRebase bool, noVerify *bool) error {
+	defer profile.Report()
+
+	// Validate that git-flow is initialized
+	if !cfgCtx.Initialized {
+		return &errors.NotInitializedError{}
+	}
+
+	// Get configuration
+	cfg := cfgCtx.Config
+
+	var branchName string
+	var shortName string
+	var detectedBranchType string
+
+	if branchType != "" {
+		detectedBranchType = branchType
+		// If branch type is specified, construct full branch name
+		if name == "" {
+			// If no name provided, try to get current branch and verify it's of the correct type
+			currentBranch, err := CurrentBranchFor(deps, fmt.Sprintf("git flow %s update <name>", branchType))
+			if err != nil {
+				return err
+			}
+			branchConfig, ok := cfg.Branches[branchType]
+			if !ok {
+				return &errors.InvalidBranchTypeError{BranchType: branchType}
+			}
+			if !strings.HasPrefix(currentBranch, branchConfig.Prefix) {
+				return &errors.GitError{Operation: "validate current branch", Err: fmt.Errorf("current branch is not a %s branch", branchType)}
+			}
+			branchName = currentBranch
+			shortName = strings.TrimPrefix(currentBranch, branchConfig.Prefix)
+		} else {
+			var err error
+			if branchName, shortName, err = ResolveTopicName(deps, cfg, branchType, name); err != nil {
+				return err
+			}
+		}
+	} else {
+		// No branch type specified, use provided branch name or current branch
+		if name == "" {
+			currentBranch, err := CurrentBranchFor(deps, "git flow update <branch>")
+			if err != nil {
+				return err
+			}
+			branchName = currentBranch
+		} else {
+			branchName = name
+		}
+		// Try to detect branch type from branch name
+		detectedBranchType, shortName = DetectBranchTypeFromName(cfg, branchName)
+	}
+
+	// Check if branch exists
+	if err := deps.Git.BranchExists(branchName); err != nil {
+		return &errors.BranchNotFoundError{BranchName: branchName}
+	}
+
+	// Get parent branch
+	parentBranch, err := update.GetParentBranch(cfg, branchName)
+	if err != nil {
+		return err
+	}
+
+	// Check if parent branch exists
+	if err := deps.Git.BranchExists(parentBranch); err != nil {
+		return &errors.BranchNotFoundError{BranchName: parentBranch}
+	}
+
+	// Get branch configuration for merge strategy
+	var strategy string
+	for branchKey, bc := range cfg.Branches {
+		if bc.Type == string(config.BranchTypeBase) && branchKey == branchName {
+			strategy = bc.DownstreamStrategy
+			break
+		}
+		if bc.Type == string(config.BranchTypeTopic) && bc.Prefix != "" && strings.HasPrefix(branchName, bc.Prefix) {
+			strategy = bc.DownstreamStrategy
+			break
+		}
+	}
+
+	if strategy == "" {
+		strategy = "merge" // Default to merge if no strategy configured
+	}
+
+	// Override strategy if --rebase flag is set
+	if useRebase {
+		strategy = "rebase"
+	}
+
+	skipVerify := config.ResolveUpdateNoVerify(cfg, detectedBranchType, noVerify)
+
+	// Create merge state
+	state := &mergestate.MergeState{
+		Action:         "update",
+		BranchName:     branchName,
+		ParentBranch:   parentBranch,
+		MergeStrategy:  strategy,
+		CurrentStep:    "merge",
+		FullBranchName: branchName,
+		NoVerify:       skipVerify,
+	}
+
+	// A signal received before the merge starts stops the update without touching
+	// the branch; once started, the merge runs to completion or conflict unless
+	// ctx is cancelled
+	watcher := interrupt.Watch()
+	defer watcher.Stop()
+
+	runUpdate := func() error {
+		if watcher.Stopped(ctx) {
+			return &errors.InterruptedError{Signal: watcher.Reason()}
+		}
+		label := fmt.Sprintf("update %s from %s (%s)", branchName, parentBranch, strategy)
+		defer profile.Start(label)()
+		deps.Events.StepStart(events.StepStart{Operation: state.Action, Branch: branchName, Step: state.CurrentStep, Label: label})
+		err := newUpdater(deps, cfg).FromParent(ctx, branchName, parentBranch, strategy, skipVerify, state)
+		// The update may have failed only because its git command was stopped
+		if err != nil && ctx.Err() != nil {
+			return undoCancelledUpdate(deps, watcher.Reason())
+		}
+		return err
+	}
+
+	// If we detected a branch type, run with hooks
+	if detectedBranchType != "" {
+		// Get git directory for hooks
+		gitDir, err := deps.Git.GetGitDir()
+		if err != nil {
+			return &errors.GitError{Operation: "get git directory", Err: err}
+		}
+
+		remoteName := cfg.Remote
+
+		// Build hook context
+		hookCtx := hooks.HookContext{
+			BranchType: detectedBranchType,
+			BranchName: shortName,
+			FullBranch: branchName,
+			BaseBranch: parentBranch,
+			Origin:     remoteName,
+		}
+		if detectedBranchType == "release" || detectedBranchType == "hotfix" {
+			hookCtx.Version = shortName
+		}
+
+		// Run update operation wrapped with hooks
+		if err := hooks.WithHooks(gitDir, detectedBranchType, hooks.HookActionUpdate, hookCtx, runUpdate); err != nil {
+			return err
+		}
+		deps.result("%s", branchName)
+		return nil
+	}
+
+	// No branch type detected, run without hooks
+	if err := runUpdate(); err != nil {
+		return err
+	}
+	deps.result("%s", branchName)
+	return nil
+}
+
+// undoCancelledUpdate undoes the merge or rebase of an update whose git command
+// was stopped by the cancellation of its context, and drops the state saved
+// for it, so the branch is left as it was before the update
+func undoCancelledUpdate(deps *Deps, reason string) error {
+	if err := deps.Git.UndoStoppedOperation(); err != nil {
+		return &errors.GitError{Operation: "undo the update stopped by the cancellation", Err: err}
+	}
+	if deps.State.InProgress() {
+		if err := deps.State.Clear(); err != nil {
+			return &errors.GitError{Operation: "clear merge state", Err: err}
+		}
+	}
+	return &errors.InterruptedError{Signal: reason}
+}
+
+// newUpdater returns the updater of branches from their parents working with deps
+func newUpdater(deps *Deps, cfg *config.Config) update.Updater {
+	return update.Updater{Git: deps.Git, Config: cfg, State: deps.State, Events: deps.Events, Out: deps.Out}
+}
+
+// DetectBranchTypeFromName detects the branch type and short name from a full branch name
+func DetectBranchTypeFromName(cfg *config.Config, branchName string) (string, string) {
+	for branchType, bc := range cfg.Branches {
+		if bc.Type == string(config.BranchTypeTopic) && bc.Prefix != "" && strings.HasPrefix(branchName, bc.Prefix) {
+			return branchType, strings.TrimPrefix(branchName, bc.Prefix)
+		}
+	}
+	return "", branchName
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// AmendConfig applies values to the configuration in memory, as if they were
+// set in Git configuration, without writing them. Keys must be known gitflow.*
+// keys holding a single value; branch properties can only be amended for
+// configured branches. Nothing is applied if any value is invalid.
+func AmendConfig(cfg *Config, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		spec, ok := LookupKey(key)
+		if !ok {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is not a git-flow configuration key", key)}
+		}
+		if spec.Kind == KindList {
+			return &errors.InvalidInputError{Message: fmt.Sprintf("'%s' is multi-valued and cannot be amended", key)}
+		}
+		if err := spec.Validate(key, values[key]); err != nil {
+			return err
+		}
+		if branch, _, ok := branchProperty(key); ok && spec.Pattern != BaseKey("<branch>") {
+			if _, exists := cfg.Branches[branch]; !exists {
+				return &errors.InvalidInputError{Message: fmt.Sprintf("cannot amend '%s': branch '%s' is not configured", key, branch)}
+			}
+		}
+	}
+
+	for _, key := range keys {
+		value := values[key]
+		cfg.CommandConfig[strings.ToLower(key)] = value
+
+		switch strings.ToLower(key) {
+		case KeyOrigin:
+			cfg.Remote = value
+		}
+
+		branch, property, ok := branchProperty(key)
+		if !ok {
+			continue
+		}
+		branchConfig, exists := cfg.Branches[branch]
+		if !exists {
+			continue
+		}
+		setBranchProperty(&branchConfig, property, value)
+		cfg.Branches[branch] = branchConfig
+	}
+	return nil
+}
+
+// branchProperty splits a gitflow.branch.<name>.<property> key into the
+// lowercased branch name and property
+func branchProperty(key string) (branch string, property string, ok bool) {
+	lower := strings.ToLower(key)
+	if !strings.HasPrefix(lower, BranchSection("")) {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(lower, BranchSection(""))
+	dot := strings.LastIndex(rest, ".")
+	if dot <= 0 {
+		return "", "", false
+	}
+	return rest[:dot], rest[dot+1:], true
+}
+
+// setBranchProperty sets the field of branchConfig stored in property, which
+// is lowercased as Git does for variable names
+func setBranchProperty(branchConfig *BranchConfig, property string, value string) {
+	switch property {
+	case strings.ToLower(PropType):
+		branchConfig.Type = value
+	case strings.ToLower(PropParent):
+		branchConfig.Parent = value
+	case strings.ToLower(PropStartPoint):
+		branchConfig.StartPoint = value
+	case strings.ToLower(PropUpstreamStrategy):
+		branchConfig.UpstreamStrategy = value
+	case strings.ToLower(PropDownstreamStrategy):
+		branchConfig.DownstreamStrategy = value
+	case strings.ToLower(PropPrefix):
+		branchConfig.Prefix = value
+	case strings.ToLower(PropTagPrefix):
+		branchConfig.TagPrefix = value
+	case strings.ToLower(PropPrefixAliases):
+		branchConfig.PrefixAliases = value
+	case strings.ToLower(PropConflictResolution):
+		branchConfig.ConflictResolution = value
+	case strings.ToLower(PropConflictResolutionPaths):
+		branchConfig.ConflictResolutionPaths = value
+	case strings.ToLower(PropTemplate):
+		branchConfig.Template = value
+	case strings.ToLower(PropAutoUpdate):
+		branchConfig.AutoUpdate, _ = ParseBool(value)
+	case strings.ToLower(PropTag):
+		branchConfig.Tag, _ = ParseBool(value)
+	}
+}
@@ -4,10 +4,11 @@ package config
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
 )
 
@@ -15,12 +16,17 @@ import (
 // Types and constants
 //
 
+// SchemaVersion is the version of the configuration layout written to and
+// understood from gitflow.version
+const SchemaVersion = "1.0"
+
 // Config represents the git-flow configuration
 type Config struct {
 	Version       string
 	Branches      map[string]BranchConfig
-	Remote        string            // Name of the remote to use for all operations
+	Remote        string            // Remote to use for all operations: --remote, gitflow.origin or "origin"; never empty
 	CommandConfig map[string]string // All gitflow.* command-specific config (Layer 2)
+	Experimental  map[string]string // gitflow.experimental.* keys, read even when git-flow is not initialized
 }
 
 // BranchConfig represents the configuration for a branch type
@@ -34,6 +40,18 @@ type BranchConfig struct {
 	AutoUpdate         bool
 	Tag                bool   // whether to create a tag when finishing
 	TagPrefix          string // prefix to use for tag names
+	PrefixAliases      string // comma-separated alternative prefixes, e.g. "bug/,fix/"
+
+	// ConflictResolution is the side ("ours" keeps this branch's changes,
+	// "theirs" takes the parent's) preferred in conflicts when the branch is
+	// updated as a child during finish. ConflictResolutionPaths optionally
+	// limits it to comma-separated path patterns, e.g. "version.txt,*.lock".
+	ConflictResolution      string
+	ConflictResolutionPaths string
+
+	// Template names the gitflow.template.<name>.* options a topic branch
+	// type inherits where its own gitflow.<type>.* options are not set
+	Template string
 }
 
 // MergeStrategy represents the strategy for merging branches
@@ -81,7 +99,7 @@ type ConfigOverrides struct {
 // DefaultConfig returns a default git-flow configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Version:       "1.0",
+		Version:       SchemaVersion,
 		Remote:        "origin",                // Default remote name
 		CommandConfig: make(map[string]string), // Initialize command config map
 		Branches: map[string]BranchConfig{
@@ -153,27 +171,152 @@ func DefaultConfig() *Config {
 	}
 }
 
+// TopicPrefixes returns the prefix of a topic branch type followed by its prefix
+// aliases, the alternative prefixes of branches named after another convention
+func TopicPrefixes(branchConfig BranchConfig) []string {
+	prefixes := []string{branchConfig.Prefix}
+	for _, alias := range strings.Split(branchConfig.PrefixAliases, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" && alias != branchConfig.Prefix {
+			prefixes = append(prefixes, alias)
+		}
+	}
+	return prefixes
+}
+
+// MatchTopicPrefix returns the prefix of branchConfig that branch starts with.
+// The configured prefix takes precedence over the aliases.
+func MatchTopicPrefix(branchConfig BranchConfig, branch string) (string, bool) {
+	for _, prefix := range TopicPrefixes(branchConfig) {
+		if strings.HasPrefix(branch, prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// SortedBaseBranches returns the names of the configured base branches, sorted
+func SortedBaseBranches(cfg *Config) []string {
+	names := []string{}
+	for name, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(BranchTypeBase) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TopicBranchType returns the topic branch type whose prefix or prefix alias
+// branch starts with, or "" if there is none. Types without a prefix are
+// skipped, since any branch would match them.
+func TopicBranchType(cfg *Config, branch string) string {
+	types := make([]string, 0, len(cfg.Branches))
+	for branchType, branchConfig := range cfg.Branches {
+		if branchConfig.Type == string(BranchTypeTopic) && branchConfig.Prefix != "" {
+			types = append(types, branchType)
+		}
+	}
+	sort.Strings(types)
+
+	for _, branchType := range types {
+		if _, ok := MatchTopicPrefix(cfg.Branches[branchType], branch); ok {
+			return branchType
+		}
+	}
+	return ""
+}
+
+// TopicNameConflict returns the first existing branch of another topic type
+// with the same short name as name, or "" if there is none. Types without a
+// prefix are skipped, since any branch would match them.
+func TopicNameConflict(cfg *Config, branchType string, name string, exists func(branch string) bool) string {
+	types := make([]string, 0, len(cfg.Branches))
+	for other, branch := range cfg.Branches {
+		if other != branchType && branch.Type == string(BranchTypeTopic) && branch.Prefix != "" {
+			types = append(types, other)
+		}
+	}
+	sort.Strings(types)
+
+	for _, other := range types {
+		for _, prefix := range TopicPrefixes(cfg.Branches[other]) {
+			if exists(prefix + name) {
+				return prefix + name
+			}
+		}
+	}
+	return ""
+}
+
+// ResolveTopicName resolves a name given on the command line for a topic
+// branch of branchType to the full branch name and the short name. The name
+// may be given as the short name, or with the configured prefix or one of its
+// aliases; the first of these candidates that exists is returned, or the one
+// with the configured prefix if none does. A name that starts with the prefix
+// of another topic type is rejected with a TopicTypeMismatchError, unless it
+// exists as a branch of this type. exists may be nil for branches that are
+// about to be created.
+func ResolveTopicName(cfg *Config, branchType string, name string, exists func(branch string) bool) (string, string, error) {
+	branchConfig, ok := cfg.Branches[branchType]
+	if !ok {
+		return "", "", &errors.InvalidBranchTypeError{BranchType: branchType}
+	}
+	if exists == nil {
+		exists = func(string) bool { return false }
+	}
+
+	if prefix, ok := MatchTopicPrefix(branchConfig, name); ok && prefix != "" {
+		shortName := strings.TrimPrefix(name, prefix)
+		if prefix != branchConfig.Prefix && exists(name) {
+			return name, shortName, nil
+		}
+		return branchConfig.Prefix + shortName, shortName, nil
+	}
+
+	fullName := branchConfig.Prefix + name
+	if otherType := TopicBranchType(cfg, name); otherType != "" && otherType != branchType && !exists(fullName) {
+		otherPrefix, _ := MatchTopicPrefix(cfg.Branches[otherType], name)
+		return "", "", &errors.TopicTypeMismatchError{Name: name, BranchType: branchType, OtherType: otherType, ShortName: strings.TrimPrefix(name, otherPrefix)}
+	}
+	return fullName, name, nil
+}
+
 // LoadConfig loads the git-flow configuration from Git config
 func LoadConfig() (*Config, error) {
-	// Get current directory for git operations
-	currentDir, err := os.Getwd()
+	cfgCtx, err := LoadContext()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %w", err)
+		return nil, err
 	}
+	return cfgCtx.Config, nil
+}
 
+// loadConfig reads the stored configuration without applying runtime overrides
+func loadConfig() (*Config, error) {
 	// Check if git-flow is initialized
 	initialized, err := IsInitialized()
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if git-flow is initialized: %w", err)
 	}
 
+	return loadConfigFor(initialized)
+}
+
+// loadConfigFor reads the stored configuration for a repository whose
+// initialization state has already been checked
+func loadConfigFor(initialized bool) (*Config, error) {
+	// Get current directory for git operations
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
 	if !initialized {
 		// If not initialized, return default config
 		return DefaultConfig(), nil
 	}
 
 	// Get git-flow version
-	version, err := git.GetConfigInDir(currentDir, "gitflow.version")
+	version, err := git.GetConfigInDir(currentDir, KeyVersion)
 	if err != nil {
 		// If no version is set but AVH config exists, import AVH config
 		if CheckGitFlowAVHConfig() {
@@ -192,84 +335,65 @@ func LoadConfig() (*Config, error) {
 	}
 
 	// Load all gitflow.* command-specific config at once
-	allGitflowConfig, err := loadAllGitflowConfig(currentDir)
+	allGitflowConfig, err := loadAllGitflowConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load gitflow config: %w", err)
 	}
 	config.CommandConfig = allGitflowConfig
 
 	// Get custom remote name if set
-	remote, ok := allGitflowConfig["gitflow.origin"]
-	if ok && remote != "" {
+	if remote := allGitflowConfig[KeyOrigin]; remote != "" {
 		config.Remote = remote
 	}
 
-	// Get all gitflow.branch.* config entries
-	// We need to adapt GetAllConfig to work with directory
-	cmd := exec.Command("git", "config", "--get-regexp", "gitflow\\.branch\\.")
-	cmd.Dir = currentDir
-	output, err := cmd.Output()
-
-	// Process branch configurations from command output
+	// Collect the gitflow.branch.* entries from the keys loaded above
 	branchMap := make(map[string]map[string]string)
+	for key, value := range allGitflowConfig {
+		if !strings.HasPrefix(key, BranchSection("")) {
+			continue
+		}
 
-	if err == nil {
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if line == "" {
-				continue
-			}
-
-			parts := strings.SplitN(line, " ", 2)
-			if len(parts) != 2 {
-				continue
-			}
-
-			key := parts[0]
-			value := parts[1]
-
-			// Parse key: gitflow.branch.<branchname>.<property>
-			keyParts := strings.Split(key, ".")
-			if len(keyParts) < 4 {
-				continue
-			}
-
-			branchName := strings.ToLower(keyParts[2])
-			property := strings.ToLower(keyParts[3])
+		// Parse key: gitflow.branch.<branchname>.<property>
+		keyParts := strings.Split(key, ".")
+		if len(keyParts) < 4 {
+			continue
+		}
 
-			// Initialize branch map if needed
-			if _, ok := branchMap[branchName]; !ok {
-				branchMap[branchName] = make(map[string]string)
-			}
+		branchName := strings.ToLower(keyParts[2])
+		property := strings.ToLower(keyParts[3])
 
-			// Add property to branch map
-			branchMap[branchName][property] = value
+		// Initialize branch map if needed
+		if _, ok := branchMap[branchName]; !ok {
+			branchMap[branchName] = make(map[string]string)
 		}
+
+		// Add property to branch map
+		branchMap[branchName][property] = value
 	}
 
 	// Convert branch map to BranchConfig objects
 	for branchName, properties := range branchMap {
+		// Properties were lowercased above, as Git does for variable names
+		property := func(name string) string {
+			return properties[strings.ToLower(name)]
+		}
 		branchConfig := BranchConfig{
-			Type:               properties["type"],
-			Parent:             properties["parent"],
-			StartPoint:         properties["startpoint"],
-			UpstreamStrategy:   properties["upstreamstrategy"],
-			DownstreamStrategy: properties["downstreamstrategy"],
-			Prefix:             properties["prefix"],
+			Type:                    property(PropType),
+			Parent:                  property(PropParent),
+			StartPoint:              property(PropStartPoint),
+			UpstreamStrategy:        property(PropUpstreamStrategy),
+			DownstreamStrategy:      property(PropDownstreamStrategy),
+			Prefix:                  property(PropPrefix),
+			TagPrefix:               property(PropTagPrefix),
+			PrefixAliases:           property(PropPrefixAliases),
+			ConflictResolution:      property(PropConflictResolution),
+			ConflictResolutionPaths: property(PropConflictResolutionPaths),
+			Template:                property(PropTemplate),
 		}
 
 		// Handle boolean properties
-		if autoUpdate, ok := properties["autoupdate"]; ok {
-			branchConfig.AutoUpdate = autoUpdate == "true"
-		}
-		if tag, ok := properties["tag"]; ok {
-			branchConfig.Tag = tag == "true"
-		}
-
-		// Handle tag prefix
-		if tagPrefix, ok := properties["tagprefix"]; ok {
-			branchConfig.TagPrefix = tagPrefix
-		}
+		branchConfig.AutoUpdate, _ = ParseBool(property(PropAutoUpdate))
+		branchConfig.Tag, _ = ParseBool(property(PropTag))
 
 		// Add branch config to config
 		config.Branches[branchName] = branchConfig
@@ -293,7 +417,7 @@ func IsInitialized() (bool, error) {
 	}
 
 	// Check for our own gitflow.version config
-	version, err := git.GetConfigInDir(currentDir, "gitflow.version")
+	version, err := git.GetConfigInDir(currentDir, KeyVersion)
 	if err == nil && version != "" {
 		return true, nil
 	}
@@ -316,7 +440,7 @@ func IsGitFlowNextInitialized() (bool, error) {
 	}
 
 	// Check for our own gitflow.version config
-	version, err := git.GetConfigInDir(currentDir, "gitflow.version")
+	version, err := git.GetConfigInDir(currentDir, KeyVersion)
 	if err == nil && version != "" {
 		return true, nil
 	}
@@ -338,7 +462,7 @@ func IsGitFlowNextInitializedWithScope(scope git.ConfigScope, filePath string) (
 		// Check scopes in order: local > global > system
 		// Return the first scope where gitflow.version is found
 		for _, checkScope := range []git.ConfigScope{git.ConfigScopeLocal, git.ConfigScopeGlobal, git.ConfigScopeSystem} {
-			version, err := git.GetConfigWithScope("gitflow.version", checkScope, "")
+			version, err := git.GetConfigWithScope(KeyVersion, checkScope, "")
 			if err == nil && version != "" {
 				return InitializedStatus{Initialized: true, SourceScope: checkScope}, nil
 			}
@@ -347,7 +471,7 @@ func IsGitFlowNextInitializedWithScope(scope git.ConfigScope, filePath string) (
 	}
 
 	// Check only the specified scope
-	version, err := git.GetConfigWithScope("gitflow.version", scope, filePath)
+	version, err := git.GetConfigWithScope(KeyVersion, scope, filePath)
 	if err == nil && version != "" {
 		return InitializedStatus{Initialized: true, SourceScope: scope}, nil
 	}
@@ -377,6 +501,21 @@ func CheckGitFlowAVHConfig() bool {
 	return false
 }
 
+// FindGitFlowAVHKeys returns the git-flow-avh configuration keys that are set,
+// sorted by name
+func FindGitFlowAVHKeys() []string {
+	values, err := git.GetAllConfig(`^gitflow\.(branch\.(master|develop)|prefix\.[^.]+)$`)
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // ImportGitFlowAVHConfig imports git-flow-avh configuration
 func ImportGitFlowAVHConfig() (*Config, error) {
 	config := DefaultConfig()
@@ -388,7 +527,7 @@ func ImportGitFlowAVHConfig() (*Config, error) {
 	}
 
 	// Check for custom remote in git-flow-avh config
-	remote, err := git.GetConfigInDir(currentDir, "gitflow.origin")
+	remote, err := git.GetConfigInDir(currentDir, KeyOrigin)
 	if err == nil && remote != "" {
 		config.Remote = remote
 	}
@@ -618,6 +757,78 @@ func SaveConfig(config *Config) error {
 	return SaveConfigWithScope(config, git.ConfigScopeDefault, "")
 }
 
+// Clone returns a copy of config that can be changed without affecting the original
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Branches = make(map[string]BranchConfig, len(c.Branches))
+	for name, branch := range c.Branches {
+		clone.Branches[name] = branch
+	}
+	clone.CommandConfig = make(map[string]string, len(c.CommandConfig))
+	for key, value := range c.CommandConfig {
+		clone.CommandConfig[key] = value
+	}
+	return &clone
+}
+
+// PreviewSave returns the gitflow.* configuration before and after removing the
+// gitflow.branch.<name> sections of removedBranches and saving config. Lines
+// are sorted "key=value" pairs with keys in the form 'git config --list' prints
+// them. Nothing is written.
+func PreviewSave(config *Config, removedBranches []string) ([]string, []string, error) {
+	current, planned, err := PlanSave(config, removedBranches)
+	if err != nil {
+		return nil, nil, err
+	}
+	return configLines(current), configLines(planned), nil
+}
+
+// PlanSave returns the gitflow.* keys and values before and after removing the
+// gitflow.branch.<name> sections of removedBranches and saving config, as
+// PreviewSave lists them. Nothing is written.
+func PlanSave(config *Config, removedBranches []string) (map[string]string, map[string]string, error) {
+	current, err := loadAllGitflowConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	planned := make(map[string]string, len(current))
+	for key, value := range current {
+		planned[key] = value
+	}
+	for _, name := range removedBranches {
+		prefix := BranchSection(name) + "."
+		for key := range planned {
+			if strings.HasPrefix(key, prefix) {
+				delete(planned, key)
+			}
+		}
+	}
+	planned[KeyVersion] = config.Version
+	for _, entry := range BranchEntries(config) {
+		planned[listedKey(entry.Key)] = entry.Value
+	}
+
+	return current, planned, nil
+}
+
+// listedKey lowercases the variable name of a gitflow.branch.* key the way
+// 'git config --list' prints it; branch names keep their case
+func listedKey(key string) string {
+	last := strings.LastIndex(key, ".")
+	return key[:last] + strings.ToLower(key[last:])
+}
+
+// configLines formats config values as sorted "key=value" lines
+func configLines(values map[string]string) []string {
+	lines := make([]string, 0, len(values))
+	for key, value := range values {
+		lines = append(lines, key+"="+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
 // MarkRepoInitialized marks the repository as initialized with git-flow (local scope).
 // This is a convenience wrapper around MarkRepoInitializedWithScope for callers
 // that don't need explicit scope control.
@@ -625,82 +836,80 @@ func MarkRepoInitialized() error {
 	return MarkRepoInitializedWithScope(git.ConfigScopeDefault, "")
 }
 
-// SaveConfigWithScope saves the git-flow configuration to Git config at a specific scope
-func SaveConfigWithScope(config *Config, scope git.ConfigScope, filePath string) error {
-	// Set git-flow version
-	err := git.SetConfigWithScope("gitflow.version", config.Version, scope, filePath)
-	if err != nil {
-		return fmt.Errorf("failed to set gitflow.version: %w", err)
+// Entry is a single Git config key and value
+type Entry struct {
+	Key   string
+	Value string
+}
+
+// BranchEntries returns the gitflow.branch.* keys SaveConfig writes for config,
+// ordered by branch name
+func BranchEntries(config *Config) []Entry {
+	names := make([]string, 0, len(config.Branches))
+	for name := range config.Branches {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	// Save branch configurations
-	for branchName, branchConfig := range config.Branches {
-		// Set branch type
-		err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.type", branchName), branchConfig.Type, scope, filePath)
-		if err != nil {
-			return fmt.Errorf("failed to set branch type for %s: %w", branchName, err)
+	var entries []Entry
+	for _, branchName := range names {
+		branchConfig := config.Branches[branchName]
+		key := func(property string) string {
+			return BranchKey(branchName, property)
 		}
 
-		// Set parent branch if it exists
+		entries = append(entries, Entry{key(PropType), branchConfig.Type})
 		if branchConfig.Parent != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.parent", branchName), branchConfig.Parent, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set parent branch for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropParent), branchConfig.Parent})
 		}
-
-		// Set start point if it exists
 		if branchConfig.StartPoint != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.startPoint", branchName), branchConfig.StartPoint, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set start point for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropStartPoint), branchConfig.StartPoint})
 		}
-
-		// Set upstream strategy if it exists
 		if branchConfig.UpstreamStrategy != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.upstreamStrategy", branchName), branchConfig.UpstreamStrategy, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set upstream strategy for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropUpstreamStrategy), branchConfig.UpstreamStrategy})
 		}
-
-		// Set downstream strategy if it exists
 		if branchConfig.DownstreamStrategy != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.downstreamStrategy", branchName), branchConfig.DownstreamStrategy, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set downstream strategy for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropDownstreamStrategy), branchConfig.DownstreamStrategy})
 		}
-
-		// Set prefix if it exists
 		if branchConfig.Prefix != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.prefix", branchName), branchConfig.Prefix, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set prefix for %s: %w", branchName, err)
-			}
-		}
-
-		// Set auto update
-		err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.autoUpdate", branchName), strconv.FormatBool(branchConfig.AutoUpdate), scope, filePath)
-		if err != nil {
-			return fmt.Errorf("failed to set auto update for %s: %w", branchName, err)
+			entries = append(entries, Entry{key(PropPrefix), branchConfig.Prefix})
 		}
-
-		// Set tag configuration only if true (false is default)
+		entries = append(entries, Entry{key(PropAutoUpdate), strconv.FormatBool(branchConfig.AutoUpdate)})
+		// Tag is only written when true (false is default)
 		if branchConfig.Tag {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.tag", branchName), "true", scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set tag configuration for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropTag), "true"})
 		}
-
-		// Set tag prefix if it exists
 		if branchConfig.TagPrefix != "" {
-			err = git.SetConfigWithScope(fmt.Sprintf("gitflow.branch.%s.tagprefix", branchName), branchConfig.TagPrefix, scope, filePath)
-			if err != nil {
-				return fmt.Errorf("failed to set tag prefix for %s: %w", branchName, err)
-			}
+			entries = append(entries, Entry{key(PropTagPrefix), branchConfig.TagPrefix})
+		}
+		if branchConfig.PrefixAliases != "" {
+			entries = append(entries, Entry{key(PropPrefixAliases), branchConfig.PrefixAliases})
+		}
+		if branchConfig.ConflictResolution != "" {
+			entries = append(entries, Entry{key(PropConflictResolution), branchConfig.ConflictResolution})
+		}
+		if branchConfig.ConflictResolutionPaths != "" {
+			entries = append(entries, Entry{key(PropConflictResolutionPaths), branchConfig.ConflictResolutionPaths})
+		}
+		if branchConfig.Template != "" {
+			entries = append(entries, Entry{key(PropTemplate), branchConfig.Template})
+		}
+	}
+	return entries
+}
+
+// SaveConfigWithScope saves the git-flow configuration to Git config at a specific scope
+func SaveConfigWithScope(config *Config, scope git.ConfigScope, filePath string) error {
+	// Set git-flow version
+	err := git.SetConfigWithScope(KeyVersion, config.Version, scope, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to set gitflow.version: %w", err)
+	}
+
+	// Save branch configurations
+	for _, entry := range BranchEntries(config) {
+		if err := git.SetConfigWithScope(entry.Key, entry.Value, scope, filePath); err != nil {
+			return fmt.Errorf("failed to set %s: %w", entry.Key, err)
 		}
 	}
 
@@ -709,7 +918,7 @@ func SaveConfigWithScope(config *Config, scope git.ConfigScope, filePath string)
 
 // MarkRepoInitializedWithScope marks the repository as initialized with git-flow at a specific scope
 func MarkRepoInitializedWithScope(scope git.ConfigScope, filePath string) error {
-	err := git.SetConfigWithScope("gitflow.initialized", "true", scope, filePath)
+	err := git.SetConfigWithScope(KeyInitialized, "true", scope, filePath)
 	if err != nil {
 		return fmt.Errorf("failed to mark repository as initialized: %w", err)
 	}
@@ -761,7 +970,7 @@ func PresetConfig(preset PresetType) *Config {
 // githubFlowConfig returns a GitHub Flow configuration
 func githubFlowConfig() *Config {
 	return &Config{
-		Version:       "1.0",
+		Version:       SchemaVersion,
 		Remote:        "origin",
 		CommandConfig: make(map[string]string),
 		Branches: map[string]BranchConfig{
@@ -787,7 +996,7 @@ func githubFlowConfig() *Config {
 // gitlabFlowConfig returns a GitLab Flow configuration
 func gitlabFlowConfig() *Config {
 	return &Config{
-		Version:       "1.0",
+		Version:       SchemaVersion,
 		Remote:        "origin",
 		CommandConfig: make(map[string]string),
 		Branches: map[string]BranchConfig{
@@ -833,35 +1042,10 @@ func gitlabFlowConfig() *Config {
 }
 
 // loadAllGitflowConfig loads all gitflow.* configuration keys at once
-func loadAllGitflowConfig(currentDir string) (map[string]string, error) {
-	cmd := exec.Command("git", "config", "--get-regexp", "gitflow\\.")
-	cmd.Dir = currentDir
-	output, err := cmd.Output()
-
-	result := make(map[string]string)
-
+func loadAllGitflowConfig() (map[string]string, error) {
+	result, err := git.GetAllConfig("gitflow\\.")
 	if err != nil {
-		// If no config values match, don't treat it as an error
-		if strings.Contains(err.Error(), "exit status 1") {
-			return result, nil
-		}
 		return nil, fmt.Errorf("failed to get gitflow config: %w", err)
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) == 2 {
-			result[parts[0]] = parts[1]
-		} else {
-			// Handle case where config value is empty
-			result[parts[0]] = ""
-		}
-	}
-
 	return result, nil
 }
@@ -0,0 +1,65 @@
+package config
+
+import "fmt"
+
+// Context is the git-flow configuration as seen by a single command invocation.
+//
+// Commands load it once and pass it to the code that needs it instead of
+// calling IsInitialized and LoadConfig at every step, so all decisions within a
+// command are based on the same configuration. Code that writes configuration
+// calls Reload afterwards; the context never refreshes itself.
+type Context struct {
+	// Initialized reports whether git-flow (next or AVH) is initialized
+	Initialized bool
+
+	// Config is the loaded configuration, including the --remote override.
+	// It holds the default configuration when git-flow is not initialized.
+	Config *Config
+
+	// RemoteOverride, if set, is the remote given with --remote, which Reload
+	// reports as Config.Remote instead of the configured gitflow.origin
+	RemoteOverride string
+}
+
+// LoadContext checks the initialization state and loads the configuration.
+func LoadContext() (*Context, error) {
+	return LoadContextWithRemote("")
+}
+
+// LoadContextWithRemote loads the context like LoadContext, using remote, if
+// not empty, instead of the configured gitflow.origin
+func LoadContextWithRemote(remote string) (*Context, error) {
+	cfgCtx := &Context{RemoteOverride: remote}
+	if err := cfgCtx.Reload(); err != nil {
+		return nil, err
+	}
+	return cfgCtx, nil
+}
+
+// Reload reads the configuration from Git again, e.g. after it was saved.
+// On error the previously loaded configuration is kept.
+func (c *Context) Reload() error {
+	initialized, err := IsInitialized()
+	if err != nil {
+		return fmt.Errorf("failed to check if git-flow is initialized: %w", err)
+	}
+
+	cfg, err := loadConfigFor(initialized)
+	if err != nil {
+		return err
+	}
+	if c.RemoteOverride != "" {
+		cfg.Remote = c.RemoteOverride
+	}
+	values := cfg.CommandConfig
+	if !initialized {
+		if values, err = loadAllGitflowConfig(); err != nil {
+			return err
+		}
+	}
+	cfg.Experimental = experimentalKeys(values)
+
+	c.Initialized = initialized
+	c.Config = cfg
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Experimental features
+const (
+	// ExperimentalInMemoryMerge merges into base branches that are not checked
+	// out without switching to them
+	ExperimentalInMemoryMerge = "in-memory-merge"
+)
+
+// experimentalPrefix starts the keys enabling experimental features
+const experimentalPrefix = "gitflow.experimental."
+
+// ExperimentalFeature is a new behavior a repository opts into with
+// gitflow.experimental.<name>=true before it becomes the default
+type ExperimentalFeature struct {
+	Name        string
+	Description string
+}
+
+// ExperimentalFeatures are the behaviors that can be enabled per repository
+var ExperimentalFeatures = []ExperimentalFeature{
+	{ExperimentalInMemoryMerge, "update child base branches with the merge strategy without checking them out (requires Git 2.38; no commit hooks run)"},
+}
+
+// ExperimentalKey returns the key enabling an experimental feature,
+// gitflow.experimental.<name>
+func ExperimentalKey(name string) string {
+	return experimentalPrefix + name
+}
+
+// ExperimentalEnabled reports whether the repository enabled the experimental
+// feature name. Values that are not Git booleans read as false.
+func (c *Config) ExperimentalEnabled(name string) bool {
+	enabled, _ := ParseBool(c.Experimental[ExperimentalKey(name)])
+	return enabled
+}
+
+// UnknownExperimentalKeys returns the gitflow.experimental.* keys that are
+// set but name no experimental feature of this version, such as features
+// that were removed or became the default
+func (c *Config) UnknownExperimentalKeys() []string {
+	var unknown []string
+	for key := range c.Experimental {
+		name := strings.TrimPrefix(key, experimentalPrefix)
+		known := false
+		for _, feature := range ExperimentalFeatures {
+			known = known || strings.EqualFold(feature.Name, name)
+		}
+		if !known {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// experimentalKeys picks the gitflow.experimental.* keys from the gitflow.*
+// configuration
+func experimentalKeys(values map[string]string) map[string]string {
+	experimental := map[string]string{}
+	for key, value := range values {
+		if strings.HasPrefix(key, experimentalPrefix) {
+			experimental[key] = value
+		}
+	}
+	return experimental
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{FormatYAML, FormatJSON, FormatTOML}
+
+// Document is the file representation of a git-flow configuration used by
+// 'git flow config export' and 'git flow config import'
+type Document struct {
+	Version  string                    `json:"version" yaml:"version"`
+	Remote   string                    `json:"remote,omitempty" yaml:"remote,omitempty"`
+	Branches map[string]BranchDocument `json:"branches" yaml:"branches"`
+	// Settings holds the remaining gitflow.* keys without the "gitflow." prefix,
+	// e.g. "feature.finish.rebase"
+	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty"`
+}
+
+// BranchDocument is the file representation of a BranchConfig
+type BranchDocument struct {
+	Type                    string `json:"type" yaml:"type"`
+	Parent                  string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	StartPoint              string `json:"startPoint,omitempty" yaml:"startPoint,omitempty"`
+	UpstreamStrategy        string `json:"upstreamStrategy,omitempty" yaml:"upstreamStrategy,omitempty"`
+	DownstreamStrategy      string `json:"downstreamStrategy,omitempty" yaml:"downstreamStrategy,omitempty"`
+	Prefix                  string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	TagPrefix               string `json:"tagPrefix,omitempty" yaml:"tagPrefix,omitempty"`
+	PrefixAliases           string `json:"prefixAliases,omitempty" yaml:"prefixAliases,omitempty"`
+	ConflictResolution      string `json:"conflictResolution,omitempty" yaml:"conflictResolution,omitempty"`
+	ConflictResolutionPaths string `json:"conflictResolutionPaths,omitempty" yaml:"conflictResolutionPaths,omitempty"`
+	Template                string `json:"template,omitempty" yaml:"template,omitempty"`
+	AutoUpdate              bool   `json:"autoUpdate,omitempty" yaml:"autoUpdate,omitempty"`
+	Tag                     bool   `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// isModelKey reports whether a gitflow.* key is represented outside Settings
+// or is repository state that does not belong in an exported model
+func isModelKey(key string) bool {
+	switch key {
+	case KeyVersion, KeyInitialized, KeyOrigin:
+		return true
+	}
+	// Branch type definitions and per-branch state such as the stored base
+	return strings.HasPrefix(key, BranchSection(""))
+}
+
+// NewDocument converts a configuration into its file representation
+func NewDocument(cfg *Config) *Document {
+	doc := &Document{
+		Version:  cfg.Version,
+		Branches: make(map[string]BranchDocument, len(cfg.Branches)),
+	}
+
+	// Export the stored remote, not one overridden by --remote
+	if remote, _ := cfg.GetString(KeyOrigin); remote != "" {
+		doc.Remote = remote
+	}
+
+	for name, branch := range cfg.Branches {
+		// Entries without a type only hold per-branch state, e.g. a stored base
+		if branch.Type == "" {
+			continue
+		}
+		doc.Branches[name] = BranchDocument{
+			Type:                    branch.Type,
+			Parent:                  branch.Parent,
+			StartPoint:              branch.StartPoint,
+			UpstreamStrategy:        branch.UpstreamStrategy,
+			DownstreamStrategy:      branch.DownstreamStrategy,
+			Prefix:                  branch.Prefix,
+			TagPrefix:               branch.TagPrefix,
+			PrefixAliases:           branch.PrefixAliases,
+			ConflictResolution:      branch.ConflictResolution,
+			ConflictResolutionPaths: branch.ConflictResolutionPaths,
+			Template:                branch.Template,
+			AutoUpdate:              branch.AutoUpdate,
+			Tag:                     branch.Tag,
+		}
+	}
+
+	for key, value := range cfg.CommandConfig {
+		if isModelKey(key) {
+			continue
+		}
+		if doc.Settings == nil {
+			doc.Settings = make(map[string]string)
+		}
+		doc.Settings[strings.TrimPrefix(key, "gitflow.")] = value
+	}
+
+	return doc
+}
+
+// Config converts the document into a configuration. Settings are returned
+// as full gitflow.* keys in CommandConfig.
+func (d *Document) Config() *Config {
+	cfg := &Config{
+		Version:       d.Version,
+		Remote:        d.Remote,
+		Branches:      make(map[string]BranchConfig, len(d.Branches)),
+		CommandConfig: make(map[string]string, len(d.Settings)),
+	}
+	if cfg.Version == "" {
+		cfg.Version = DefaultConfig().Version
+	}
+
+	for name, branch := range d.Branches {
+		cfg.Branches[name] = BranchConfig{
+			Type:                    branch.Type,
+			Parent:                  branch.Parent,
+			StartPoint:              branch.StartPoint,
+			UpstreamStrategy:        branch.UpstreamStrategy,
+			DownstreamStrategy:      branch.DownstreamStrategy,
+			Prefix:                  branch.Prefix,
+			TagPrefix:               branch.TagPrefix,
+			PrefixAliases:           branch.PrefixAliases,
+			ConflictResolution:      branch.ConflictResolution,
+			ConflictResolutionPaths: branch.ConflictResolutionPaths,
+			Template:                branch.Template,
+			AutoUpdate:              branch.AutoUpdate,
+			Tag:                     branch.Tag,
+		}
+	}
+
+	for key, value := range d.Settings {
+		cfg.CommandConfig["gitflow."+key] = value
+	}
+
+	return cfg
+}
+
+// FormatFromPath guesses the format of a file from its extension
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of '%s' from its extension", path)
+}
+
+// EncodeDocument serializes a document in the given format
+func EncodeDocument(doc *Document, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatTOML:
+		return encodeTOML(doc), nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+// DecodeDocument parses a document in the given format. Unknown fields are
+// rejected so that typos in hand-written files do not go unnoticed.
+func DecodeDocument(data []byte, format string) (*Document, error) {
+	var doc Document
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+	case FormatJSON:
+		if err := decodeJSON(data, &doc); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		tables, err := decodeTOML(data)
+		if err != nil {
+			return nil, err
+		}
+		// The TOML subset maps onto the same structure as the JSON form
+		converted, err := json.Marshal(tables)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(converted, &doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
+	return &doc, nil
+}
+
+// decodeJSON decodes data into v, rejecting unknown fields
+func decodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph output formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// GraphFormats lists the supported graph formats
+var GraphFormats = []string{GraphFormatDOT, GraphFormatMermaid}
+
+// graphEdgeKind distinguishes the relations drawn between branches
+type graphEdgeKind int
+
+const (
+	edgeBase       graphEdgeKind = iota // parent to a child base branch
+	edgeTopic                           // parent to a topic type finishing into it
+	edgeStartPoint                      // start point other than the parent to a topic type
+)
+
+// graphEdge is a relation between two configured branches
+type graphEdge struct {
+	from  string
+	to    string
+	kind  graphEdgeKind
+	label string
+}
+
+// graphNodes returns the configured branch names, base branches first, each
+// group sorted by name
+func graphNodes(cfg *Config) []string {
+	var bases, topics []string
+	for name, branch := range cfg.Branches {
+		switch branch.Type {
+		case string(BranchTypeBase):
+			bases = append(bases, name)
+		case string(BranchTypeTopic):
+			topics = append(topics, name)
+		}
+	}
+	sort.Strings(bases)
+	sort.Strings(topics)
+	return append(bases, topics...)
+}
+
+// graphEdges returns the relations between the given branches. Edges point
+// from the parent to the child branch, following the direction work flows
+// when branches are created.
+func graphEdges(cfg *Config, nodes []string) []graphEdge {
+	var edges []graphEdge
+	for _, name := range nodes {
+		branch := cfg.Branches[name]
+		if branch.Parent == "" {
+			continue
+		}
+		if _, exists := cfg.Branches[branch.Parent]; !exists {
+			continue
+		}
+		label := fmt.Sprintf("up: %s, down: %s", branch.UpstreamStrategy, branch.DownstreamStrategy)
+		if branch.Type == string(BranchTypeBase) {
+			edges = append(edges, graphEdge{from: branch.Parent, to: name, kind: edgeBase, label: label})
+			continue
+		}
+		edges = append(edges, graphEdge{from: branch.Parent, to: name, kind: edgeTopic, label: label})
+		if branch.StartPoint != "" && branch.StartPoint != branch.Parent {
+			if _, exists := cfg.Branches[branch.StartPoint]; exists {
+				edges = append(edges, graphEdge{from: branch.StartPoint, to: name, kind: edgeStartPoint, label: "start"})
+			}
+		}
+	}
+	return edges
+}
+
+// graphLabel returns the text shown for a branch: the name of a base branch,
+// or the branch pattern of a topic type, followed by notable settings
+func graphLabel(name string, branch BranchConfig) []string {
+	lines := []string{name}
+	if branch.Type == string(BranchTypeTopic) {
+		lines[0] = branch.Prefix + "*"
+		if branch.Prefix == "" {
+			lines[0] = name
+		}
+		if branch.Tag {
+			lines = append(lines, "(tags)")
+		}
+		return lines
+	}
+	if branch.AutoUpdate {
+		lines = append(lines, "(auto-update)")
+	}
+	return lines
+}
+
+// RenderGraph renders the configured base branch hierarchy and topic branch
+// types as a Graphviz DOT or Mermaid flowchart
+func RenderGraph(cfg *Config, format string) (string, error) {
+	switch format {
+	case GraphFormatDOT:
+		return renderDOT(cfg), nil
+	case GraphFormatMermaid:
+		return renderMermaid(cfg), nil
+	}
+	return "", fmt.Errorf("unsupported graph format '%s'", format)
+}
+
+// renderDOT renders the branch model as a Graphviz digraph
+func renderDOT(cfg *Config) string {
+	nodes := graphNodes(cfg)
+
+	var b strings.Builder
+	b.WriteString("digraph gitflow {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, name := range nodes {
+		branch := cfg.Branches[name]
+		label := dotQuote(strings.Join(graphLabel(name, branch), "\n"))
+		if branch.Type == string(BranchTypeBase) {
+			style := "solid"
+			if branch.Parent == "" {
+				style = "bold"
+			}
+			fmt.Fprintf(&b, "  %s [shape=box, style=%s, label=%s];\n", dotQuote(name), style, label)
+		} else {
+			fmt.Fprintf(&b, "  %s [shape=ellipse, label=%s];\n", dotQuote(name), label)
+		}
+	}
+	for _, edge := range graphEdges(cfg, nodes) {
+		attributes := []string{"label=" + dotQuote(edge.label)}
+		switch edge.kind {
+		case edgeTopic:
+			attributes = append(attributes, "style=dashed")
+		case edgeStartPoint:
+			attributes = append(attributes, "style=dotted")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.from), dotQuote(edge.to), strings.Join(attributes, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a quoted DOT identifier; line breaks become \n
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// renderMermaid renders the branch model as a Mermaid flowchart. Branch
+// names may contain characters Mermaid does not allow in node IDs, so nodes
+// are numbered and the names are used as labels.
+func renderMermaid(cfg *Config) string {
+	nodes := graphNodes(cfg)
+	ids := make(map[string]string, len(nodes))
+	for i, name := range nodes {
+		ids[name] = fmt.Sprintf("n%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, name := range nodes {
+		branch := cfg.Branches[name]
+		label := mermaidQuote(strings.Join(graphLabel(name, branch), "<br/>"))
+		if branch.Type == string(BranchTypeBase) {
+			fmt.Fprintf(&b, "  %s[%s]\n", ids[name], label)
+		} else {
+			fmt.Fprintf(&b, "  %s([%s])\n", ids[name], label)
+		}
+	}
+	for _, edge := range graphEdges(cfg, nodes) {
+		arrow := "==>"
+		switch edge.kind {
+		case edgeTopic:
+			arrow = "-->"
+		case edgeStartPoint:
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", ids[edge.from], arrow, mermaidQuote(edge.label), ids[edge.to])
+	}
+	return b.String()
+}
+
+// mermaidQuote returns s as a quoted Mermaid label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// inferredBaseNames lists, per base branch of the default configuration, the
+// names repositories commonly use for it, most common first
+var inferredBaseNames = []struct {
+	name       string
+	candidates []string
+}{
+	{"main", []string{"main", "master", "trunk"}},
+	{"develop", []string{"develop", "dev", "development"}},
+}
+
+// inferredTopicPrefixes lists, per topic type of the default configuration,
+// the prefixes repositories commonly use for it, most common first
+var inferredTopicPrefixes = map[string][]string{
+	"feature": {"feature/", "feat/", "features/"},
+	"bugfix":  {"bugfix/", "fix/", "bug/"},
+	"release": {"release/", "releases/", "rel/"},
+	"hotfix":  {"hotfix/", "hotfixes/", "hot/"},
+	"support": {"support/"},
+}
+
+// InferConfig guesses a configuration for a repository that is not initialized
+// from its branch names. It starts from the default configuration, renames main
+// and develop to the names the repository uses, such as master or dev, and
+// gives each topic type the prefix most of its branches use, with the other
+// common prefixes found as aliases. The result is meant for read-only views
+// and is never saved.
+func InferConfig(branches []string) *Config {
+	cfg := DefaultConfig()
+	inferBaseBranches(cfg, branches)
+	inferTopicPrefixes(cfg, branches)
+	return cfg
+}
+
+// Inference is a configuration guessed from the history of a repository,
+// with the evidence for each guess
+type Inference struct {
+	Config   *Config
+	Findings []string
+}
+
+// versionTagPattern matches version tags such as v1.2.0 or release-2.0-rc1,
+// capturing the prefix before the version
+var versionTagPattern = regexp.MustCompile(`^(\D*)\d+(\.\d+)+([-+].*)?$`)
+
+// InferFromHistory guesses the configuration for 'git flow init --infer'.
+// Like InferConfig it takes main and develop from the existing branches, but
+// it also counts the branches named by merge commit messages, which are
+// usually deleted by now, towards the topic branch prefixes, and takes the
+// tag prefix of releases and hotfixes from the prefix most version tags use.
+func InferFromHistory(branches, mergedBranches, tags []string) *Inference {
+	cfg := DefaultConfig()
+	inference := &Inference{Config: cfg}
+
+	found := inferBaseBranches(cfg, branches)
+	for _, base := range inferredBaseNames {
+		if name, ok := found[base.name]; ok {
+			inference.Findings = append(inference.Findings, fmt.Sprintf("%s branch: '%s' (existing branch)", base.name, name))
+		} else {
+			inference.Findings = append(inference.Findings, fmt.Sprintf("%s branch: '%s' (no existing branch)", base.name, base.name))
+		}
+	}
+
+	// A branch merged more than once, or merged and still there, counts once
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range append(append([]string{}, branches...), mergedBranches...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	counts := inferTopicPrefixes(cfg, names)
+	types := make([]string, 0, len(inferredTopicPrefixes))
+	for branchType := range inferredTopicPrefixes {
+		types = append(types, branchType)
+	}
+	sort.Strings(types)
+	for _, branchType := range types {
+		branchConfig := cfg.Branches[branchType]
+		total := 0
+		for _, count := range counts[branchType] {
+			total += count
+		}
+		finding := fmt.Sprintf("%s prefix: '%s' (%d branch(es))", branchType, branchConfig.Prefix, counts[branchType][branchConfig.Prefix])
+		if total == 0 {
+			finding = fmt.Sprintf("%s prefix: '%s' (default, no branches found)", branchType, branchConfig.Prefix)
+		} else if branchConfig.PrefixAliases != "" {
+			finding += fmt.Sprintf(", aliases %s", branchConfig.PrefixAliases)
+		}
+		inference.Findings = append(inference.Findings, finding)
+	}
+
+	tagCounts := make(map[string]int)
+	versions := 0
+	for _, tag := range tags {
+		if match := versionTagPattern.FindStringSubmatch(tag); match != nil {
+			tagCounts[match[1]]++
+			versions++
+		}
+	}
+	if versions == 0 {
+		inference.Findings = append(inference.Findings, "tag prefix: none (no version tags found)")
+		return inference
+	}
+	prefixes := make([]string, 0, len(tagCounts))
+	for prefix := range tagCounts {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	tagPrefix := prefixes[0]
+	for _, prefix := range prefixes {
+		if tagCounts[prefix] > tagCounts[tagPrefix] {
+			tagPrefix = prefix
+		}
+	}
+	for _, branchType := range []string{"release", "hotfix"} {
+		branchConfig := cfg.Branches[branchType]
+		branchConfig.TagPrefix = tagPrefix
+		cfg.Branches[branchType] = branchConfig
+	}
+	if tagPrefix == "" {
+		inference.Findings = append(inference.Findings, fmt.Sprintf("tag prefix: none (%d of %d version tags)", tagCounts[tagPrefix], versions))
+	} else {
+		inference.Findings = append(inference.Findings, fmt.Sprintf("tag prefix: '%s' (%d of %d version tags)", tagPrefix, tagCounts[tagPrefix], versions))
+	}
+	return inference
+}
+
+// inferBaseBranches renames main and develop of cfg to the first of their
+// common names found among branches. It returns the names found, keyed by the
+// default name.
+func inferBaseBranches(cfg *Config, branches []string) map[string]string {
+	existing := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		existing[branch] = true
+	}
+
+	found := make(map[string]string)
+	for _, base := range inferredBaseNames {
+		for _, candidate := range base.candidates {
+			if !existing[candidate] {
+				continue
+			}
+			if candidate != base.name {
+				renameInferredBase(cfg, base.name, candidate)
+			}
+			found[base.name] = candidate
+			break
+		}
+	}
+	return found
+}
+
+// inferTopicPrefixes gives each topic type of cfg the prefix most of branches
+// use, with the other prefixes found as aliases. It returns how many branches
+// use each prefix, keyed by topic type.
+func inferTopicPrefixes(cfg *Config, branches []string) map[string]map[string]int {
+	result := make(map[string]map[string]int)
+	for branchType, candidates := range inferredTopicPrefixes {
+		counts := make(map[string]int)
+		for _, branch := range branches {
+			for _, prefix := range candidates {
+				if strings.HasPrefix(branch, prefix) && len(branch) > len(prefix) {
+					counts[prefix]++
+					break
+				}
+			}
+		}
+		result[branchType] = counts
+
+		// The most used prefix wins; ties go to the more common convention
+		primary := candidates[0]
+		for _, prefix := range candidates {
+			if counts[prefix] > counts[primary] {
+				primary = prefix
+			}
+		}
+		var aliases []string
+		for _, prefix := range candidates {
+			if prefix != primary && counts[prefix] > 0 {
+				aliases = append(aliases, prefix)
+			}
+		}
+
+		branchConfig := cfg.Branches[branchType]
+		branchConfig.Prefix = primary
+		branchConfig.PrefixAliases = strings.Join(aliases, ",")
+		cfg.Branches[branchType] = branchConfig
+	}
+	return result
+}
+
+// renameInferredBase renames a base branch of cfg and the references to it
+func renameInferredBase(cfg *Config, from, to string) {
+	branchConfig := cfg.Branches[from]
+	delete(cfg.Branches, from)
+	cfg.Branches[to] = branchConfig
+	for name, branch := range cfg.Branches {
+		if branch.Parent == from {
+			branch.Parent = to
+		}
+		if branch.StartPoint == from {
+			branch.StartPoint = to
+		}
+		cfg.Branches[name] = branch
+	}
+}
@@ -0,0 +1,534 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+//
+// Key names
+//
+
+// Global gitflow.* keys
+const (
+	KeyVersion             = "gitflow.version"
+	KeyInitialized         = "gitflow.initialized"
+	KeyOrigin              = "gitflow.origin"
+	KeyForge               = "gitflow.forge"
+	KeyVersionFile         = "gitflow.version.file"
+	KeyNotifyPlugin        = "gitflow.notify.plugin"
+	KeyNotifyDiscover      = "gitflow.notify.discover"
+	KeyHooksConfigWrite    = "gitflow.hooks.allowConfigWrite"
+	KeyReleaseNotesEnabled = "gitflow.releasenotes.enabled"
+	KeyReleaseNotesTrailer = "gitflow.releasenotes.trailer"
+	KeyReleaseNotesFile    = "gitflow.releasenotes.file"
+	KeyReleaseNotesTag     = "gitflow.releasenotes.tag"
+	KeyUniqueTopicNames    = "gitflow.uniqueTopicNames"
+	KeyUpdateOrder         = "gitflow.updateOrder"
+	KeyStabilization       = "gitflow.stabilization"
+	KeyInitCreateCommit    = "gitflow.init.createCommit"
+	KeyTrackUpstream       = "gitflow.trackUpstream"
+	KeyNetworkRetries      = "gitflow.network.retries"
+	KeyNetworkRetryDelay   = "gitflow.network.retryDelay"
+	KeyMirrorRemotes       = "gitflow.mirror.remotes"
+	KeyPrereleaseChannels  = "gitflow.prerelease.channels"
+	KeyPrereleaseBump      = "gitflow.prerelease.bump"
+	KeyPrereleasePush      = "gitflow.prerelease.push"
+	KeyPolicy              = "gitflow.policy"
+)
+
+// Mirror options in gitflow.mirror.<remote>.<option>
+const (
+	OptMirrorRequired = "required"
+)
+
+// Prerelease channel options in gitflow.prerelease.<channel>.<option>
+const (
+	OptChannelFormat = "format"
+	OptChannelBranch = "branch"
+)
+
+// Version line options in gitflow.line.<line>.<option>
+const (
+	OptLineBase      = "base"
+	OptLineTagPrefix = "tagprefix"
+)
+
+// Branch properties, stored as gitflow.branch.<name>.<property>
+const (
+	PropType                    = "type"
+	PropParent                  = "parent"
+	PropStartPoint              = "startPoint"
+	PropUpstreamStrategy        = "upstreamStrategy"
+	PropDownstreamStrategy      = "downstreamStrategy"
+	PropPrefix                  = "prefix"
+	PropAutoUpdate              = "autoUpdate"
+	PropTag                     = "tag"
+	PropTagPrefix               = "tagprefix"
+	PropPrefixAliases           = "prefixAliases"
+	PropConflictResolution      = "conflictResolution"
+	PropConflictResolutionPaths = "conflictResolutionPaths"
+	PropDeleteRemote            = "deleteRemote"
+	// PropTemplate names the template a topic branch type inherits from
+	PropTemplate = "template"
+
+	// PropBase records the base a topic branch was started from,
+	// keyed by the full branch name
+	PropBase = "base"
+	// PropReleaseCandidate records the release candidate tags of a branch as
+	// "<tag> <commit>" values, keyed by the full branch name
+	PropReleaseCandidate = "rc"
+)
+
+// Commands with options in gitflow.<type>.<command>.<option>
+const (
+	CommandStart   = "start"
+	CommandFinish  = "finish"
+	CommandUpdate  = "update"
+	CommandPublish = "publish"
+	CommandDelete  = "delete"
+	CommandCheck   = "check"
+	CommandRC      = "rc"
+)
+
+// Command options
+const (
+	OptNoTag                = "notag"
+	OptSign                 = "sign"
+	OptSigningKey           = "signingkey"
+	OptMessageFile          = "messagefile"
+	OptKeep                 = "keep"
+	OptKeepRemote           = "keepremote"
+	OptKeepLocal            = "keeplocal"
+	OptForceDelete          = "force-delete"
+	OptRebase               = "rebase"
+	OptNoRebase             = "no-rebase"
+	OptSquash               = "squash"
+	OptNoSquash             = "no-squash"
+	OptPreserveMerges       = "preserve-merges"
+	OptNoPreserveMerges     = "no-preserve-merges"
+	OptNoFF                 = "no-ff"
+	OptFF                   = "ff"
+	OptFFOnly               = "ff-only"
+	OptFetch                = "fetch"
+	OptPush                 = "push"
+	OptPushTag              = "pushtag"
+	OptNoVerify             = "noverify"
+	OptMergeMessage         = "mergemessage"
+	OptUpdateMessage        = "updatemessage"
+	OptBaseResolution       = "baseresolution"
+	OptRequireUpToDateTopic = "requireuptodatetopic"
+	OptNoVerifyChildren     = "noverifychildren"
+	OptAutostashUntracked   = "autostashuntracked"
+	OptVerifyBaseSignature  = "verifybasesignature"
+	OptAllowedSigningKeys   = "allowedsigningkeys"
+	OptExtraTag             = "extra-tag"
+	OptMergeTagToChildren   = "mergetagtochildren"
+	OptPushOption           = "push-option"
+	OptForce                = "force"
+	OptFromRemote           = "fromremote"
+	OptSlugify              = "slugify"
+	OptPublish              = "publish"
+	OptTrailer              = "trailer"
+	OptMaxBehind            = "maxbehind"
+	OptNamePattern          = "namepattern"
+	OptSummary              = "summary"
+	OptBackMerges           = "backmerges"
+	OptMaxBackMerges        = "maxbackmerges"
+	OptBackport             = "backport"
+	OptBackportPR           = "backportpr"
+)
+
+// Branch type options in gitflow.<type>.<option>
+const (
+	OptAllowTopicBase = "allowTopicBase"
+	OptTrackUpstream  = "trackUpstream"
+)
+
+// TypeKey returns the key of a branch type option, gitflow.<type>.<option>
+func TypeKey(branchType, option string) string {
+	return fmt.Sprintf("gitflow.%s.%s", branchType, option)
+}
+
+// MirrorKey returns the key of an option of a mirror remote,
+// gitflow.mirror.<remote>.<option>
+func MirrorKey(remote, option string) string {
+	return fmt.Sprintf("gitflow.mirror.%s.%s", remote, option)
+}
+
+// ChannelKey returns the key of an option of a prerelease channel,
+// gitflow.prerelease.<channel>.<option>
+func ChannelKey(channel, option string) string {
+	return fmt.Sprintf("gitflow.prerelease.%s.%s", channel, option)
+}
+
+// LineKey returns the key of an option of a version line,
+// gitflow.line.<line>.<option>
+func LineKey(line, option string) string {
+	return fmt.Sprintf("gitflow.line.%s.%s", line, option)
+}
+
+// LineSection returns the config section holding the options of line
+func LineSection(line string) string {
+	return "gitflow.line." + line
+}
+
+// TemplateKey returns the key of an option of a topic type template,
+// gitflow.template.<template>.<option>
+func TemplateKey(template, option string) string {
+	return fmt.Sprintf("gitflow.template.%s.%s", template, option)
+}
+
+// TemplateSection returns the config section holding the options of template
+func TemplateSection(template string) string {
+	return "gitflow.template." + template
+}
+
+// BranchKey returns the key of a branch property, gitflow.branch.<branch>.<property>
+func BranchKey(branch, property string) string {
+	return fmt.Sprintf("gitflow.branch.%s.%s", branch, property)
+}
+
+// BranchSection returns the config section holding the properties of branch
+func BranchSection(branch string) string {
+	return "gitflow.branch." + branch
+}
+
+// BaseKey returns the key recording the base of the topic branch fullBranch
+func BaseKey(fullBranch string) string {
+	return BranchKey(fullBranch, PropBase)
+}
+
+// CommandKey returns the key of a command option for a branch type,
+// gitflow.<type>.<command>.<option>, or the type-independent
+// gitflow.<command>.<option> if branchType is empty
+func CommandKey(branchType, command, option string) string {
+	if branchType == "" {
+		return fmt.Sprintf("gitflow.%s.%s", command, option)
+	}
+	return fmt.Sprintf("gitflow.%s.%s.%s", branchType, command, option)
+}
+
+//
+// Known keys
+//
+
+// KeyKind is the type of value a config key holds
+type KeyKind string
+
+const (
+	KindString   KeyKind = "string"
+	KindBool     KeyKind = "bool"
+	KindEnum     KeyKind = "enum"
+	KindDuration KeyKind = "duration"
+	KindList     KeyKind = "list" // multi-valued key
+)
+
+// KeySpec describes a known config key. Pattern uses <type> for a branch type
+// name, <remote> for a remote name, <channel> for a prerelease channel, <line>
+// for a version line and <branch> for a branch name, e.g.
+// gitflow.<type>.finish.keep.
+type KeySpec struct {
+	Pattern string
+	Kind    KeyKind
+	Values  []string // allowed values of an enum
+	Default string
+}
+
+var mergeStrategies = []string{
+	string(MergeStrategyNone), string(MergeStrategyMerge), string(MergeStrategyRebase), string(MergeStrategySquash),
+}
+
+var baseResolutions = []string{BaseResolutionConfigured, BaseResolutionStored, BaseResolutionPrompt}
+
+var backMergePolicies = []string{BackMergesAllow, BackMergesWarn, BackMergesRefuse}
+
+var versionParts = []string{util.VersionMajor, util.VersionMinor, util.VersionPatch}
+
+var knownKeys = []KeySpec{
+	{Pattern: KeyVersion, Kind: KindString, Default: SchemaVersion},
+	{Pattern: KeyInitialized, Kind: KindBool},
+	{Pattern: KeyOrigin, Kind: KindString, Default: "origin"},
+	{Pattern: KeyForge, Kind: KindString},
+	{Pattern: KeyVersionFile, Kind: KindList},
+	{Pattern: KeyNotifyPlugin, Kind: KindList},
+	{Pattern: KeyNotifyDiscover, Kind: KindBool, Default: "true"},
+	{Pattern: KeyHooksConfigWrite, Kind: KindBool, Default: "false"},
+	{Pattern: KeyReleaseNotesEnabled, Kind: KindBool, Default: "false"},
+	{Pattern: KeyReleaseNotesTrailer, Kind: KindString, Default: DefaultReleaseNotesTrailer},
+	{Pattern: KeyReleaseNotesFile, Kind: KindString},
+	{Pattern: KeyReleaseNotesTag, Kind: KindBool, Default: "false"},
+	{Pattern: KeyUniqueTopicNames, Kind: KindBool, Default: "false"},
+	{Pattern: KeyUpdateOrder, Kind: KindString},
+	{Pattern: KeyStabilization, Kind: KindString},
+	{Pattern: KeyInitCreateCommit, Kind: KindBool, Default: "true"},
+	{Pattern: KeyTrackUpstream, Kind: KindBool, Default: "true"},
+	{Pattern: KeyNetworkRetries, Kind: KindString, Default: "2"},
+	{Pattern: KeyNetworkRetryDelay, Kind: KindDuration, Default: "1s"},
+	{Pattern: KeyMirrorRemotes, Kind: KindString},
+	{Pattern: MirrorKey("<remote>", OptMirrorRequired), Kind: KindBool, Default: "false"},
+	{Pattern: KeyPrereleaseChannels, Kind: KindString, Default: strings.Join(DefaultPrereleaseChannels, ",")},
+	{Pattern: KeyPrereleaseBump, Kind: KindEnum, Values: versionParts, Default: util.VersionMinor},
+	{Pattern: KeyPrereleasePush, Kind: KindBool, Default: "false"},
+	{Pattern: ChannelKey("<channel>", OptChannelFormat), Kind: KindString, Default: DefaultPrereleaseFormat},
+	{Pattern: ChannelKey("<channel>", OptChannelBranch), Kind: KindString},
+	{Pattern: LineKey("<line>", OptLineBase), Kind: KindString},
+	{Pattern: LineKey("<line>", OptLineTagPrefix), Kind: KindString},
+	{Pattern: KeyPolicy, Kind: KindString},
+
+	{Pattern: BranchKey("<type>", PropType), Kind: KindEnum, Values: []string{string(BranchTypeBase), string(BranchTypeTopic)}},
+	{Pattern: BranchKey("<type>", PropParent), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropStartPoint), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropUpstreamStrategy), Kind: KindEnum, Values: mergeStrategies},
+	{Pattern: BranchKey("<type>", PropDownstreamStrategy), Kind: KindEnum, Values: mergeStrategies},
+	{Pattern: BranchKey("<type>", PropPrefix), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropAutoUpdate), Kind: KindBool, Default: "false"},
+	{Pattern: BranchKey("<type>", PropTag), Kind: KindBool, Default: "false"},
+	{Pattern: BranchKey("<type>", PropTagPrefix), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropPrefixAliases), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropConflictResolution), Kind: KindEnum, Values: []string{"ours", "theirs"}},
+	{Pattern: BranchKey("<type>", PropConflictResolutionPaths), Kind: KindString},
+	{Pattern: BranchKey("<type>", PropDeleteRemote), Kind: KindBool, Default: "false"},
+	{Pattern: BranchKey("<type>", PropTemplate), Kind: KindString},
+	{Pattern: BaseKey("<branch>"), Kind: KindString},
+	{Pattern: BranchKey("<branch>", PropReleaseCandidate), Kind: KindList},
+
+	{Pattern: TypeKey("<type>", OptAllowTopicBase), Kind: KindBool, Default: "false"},
+	{Pattern: TypeKey("<type>", OptTrackUpstream), Kind: KindBool, Default: "true"},
+	{Pattern: CommandKey("<type>", CommandStart, OptFetch), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoTag), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptSign), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptSigningKey), Kind: KindString},
+	{Pattern: CommandKey("<type>", CommandFinish, OptMessageFile), Kind: KindString},
+	{Pattern: CommandKey("<type>", CommandFinish, OptKeep), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptKeepRemote), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptKeepLocal), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptForceDelete), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptRebase), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoRebase), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptSquash), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoSquash), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptPreserveMerges), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoPreserveMerges), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoFF), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptFF), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptFFOnly), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptFetch), Kind: KindBool, Default: "true"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptPush), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptPushTag), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptNoVerify), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptMergeMessage), Kind: KindString},
+	{Pattern: CommandKey("<type>", CommandFinish, OptUpdateMessage), Kind: KindString},
+	{Pattern: CommandKey("<type>", CommandFinish, OptExtraTag), Kind: KindList},
+	{Pattern: CommandKey("<type>", CommandFinish, OptMergeTagToChildren), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandFinish, OptTrailer), Kind: KindList},
+	{Pattern: CommandKey("<type>", CommandFinish, OptBackport), Kind: KindString},
+	{Pattern: CommandKey("<type>", CommandFinish, OptBackportPR), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandPublish, OptPushOption), Kind: KindList},
+	{Pattern: CommandKey("<type>", CommandRC, OptPush), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("<type>", CommandDelete, OptForce), Kind: KindBool, Default: "false"},
+}
+
+// typeOrGlobalKeys are the start, finish and update options that can be set
+// for a branch type and for all branch types
+var typeOrGlobalKeys = []KeySpec{
+	{Pattern: CommandKey("", CommandStart, OptFromRemote), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandStart, OptSlugify), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandStart, OptPublish), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandFinish, OptBaseResolution), Kind: KindEnum, Values: baseResolutions, Default: BaseResolutionConfigured},
+	{Pattern: CommandKey("", CommandFinish, OptRequireUpToDateTopic), Kind: KindBool, Default: "true"},
+	{Pattern: CommandKey("", CommandFinish, OptNoVerifyChildren), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandFinish, OptAutostashUntracked), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandFinish, OptVerifyBaseSignature), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandFinish, OptAllowedSigningKeys), Kind: KindString},
+	{Pattern: CommandKey("", CommandFinish, OptSummary), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandFinish, OptBackMerges), Kind: KindEnum, Values: backMergePolicies, Default: BackMergesWarn},
+	{Pattern: CommandKey("", CommandFinish, OptMaxBackMerges), Kind: KindString, Default: "1"},
+	{Pattern: CommandKey("", CommandUpdate, OptNoVerify), Kind: KindBool, Default: "false"},
+	{Pattern: CommandKey("", CommandCheck, OptMaxBehind), Kind: KindString},
+	{Pattern: CommandKey("", CommandCheck, OptNamePattern), Kind: KindString},
+}
+
+// KnownKeys returns the gitflow.* keys git-flow reads, in documentation order
+func KnownKeys() []KeySpec {
+	keys := make([]KeySpec, 0, len(knownKeys)+len(ExperimentalFeatures)+2*len(typeOrGlobalKeys))
+	keys = append(keys, knownKeys...)
+	for _, feature := range ExperimentalFeatures {
+		keys = append(keys, KeySpec{Pattern: ExperimentalKey(feature.Name), Kind: KindBool, Default: "false"})
+	}
+	for _, spec := range typeOrGlobalKeys {
+		typed := spec
+		typed.Pattern = "gitflow.<type>." + strings.TrimPrefix(spec.Pattern, "gitflow.")
+		keys = append(keys, typed, spec)
+	}
+	return keys
+}
+
+// LookupKey returns the spec of a known key. Names are matched case-insensitively,
+// like Git does for section and variable names.
+func LookupKey(key string) (KeySpec, bool) {
+	for _, spec := range KnownKeys() {
+		if keyPattern(spec.Pattern).MatchString(key) {
+			return spec, true
+		}
+	}
+	return KeySpec{}, false
+}
+
+// keyPattern compiles a key pattern: <type>, <remote> and <channel> match one
+// key segment, <line> and <branch> any name, as version lines such as 1.x
+// contain dots
+func keyPattern(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, "<type>", `[^.]+`)
+	expr = strings.ReplaceAll(expr, "<remote>", `[^.]+`)
+	expr = strings.ReplaceAll(expr, "<channel>", `[^.]+`)
+	expr = strings.ReplaceAll(expr, "<line>", `.+`)
+	expr = strings.ReplaceAll(expr, "<branch>", `.+`)
+	return regexp.MustCompile("(?i)^" + expr + "$")
+}
+
+// Validate checks that value is valid for the key described by spec
+func (spec KeySpec) Validate(key, value string) error {
+	switch spec.Kind {
+	case KindBool:
+		if _, ok := ParseBool(value); !ok {
+			return &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: []string{"true", "false"}}
+		}
+	case KindEnum:
+		if !containsFold(spec.Values, value) {
+			return &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: spec.Values}
+		}
+	case KindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: []string{"a duration such as 30s or 5m"}}
+		}
+	}
+	return nil
+}
+
+//
+// Typed accessors
+//
+
+// ParseBool parses a Git boolean: true, yes, on and 1, or false, no, off, 0
+// and the empty string. ok is false for any other value.
+func ParseBool(value string) (result bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0", "":
+		return false, true
+	}
+	return false, false
+}
+
+// lookup returns the value of the first of keys that is set. A branch type
+// option that is not set is looked up in the template the type inherits from.
+func (c *Config) lookup(keys ...string) (value string, key string, ok bool) {
+	for _, key := range keys {
+		if value, ok := c.CommandConfig[strings.ToLower(key)]; ok {
+			return value, key, true
+		}
+		if value, templateKey, ok := c.templateValue(key); ok {
+			return value, templateKey, true
+		}
+	}
+	return "", "", false
+}
+
+// GetString returns the value of the first of keys that is set
+func (c *Config) GetString(keys ...string) (string, bool) {
+	value, _, ok := c.lookup(keys...)
+	return value, ok
+}
+
+// GetBool returns the value of the first of keys that is set. Values that
+// are not Git booleans read as false.
+func (c *Config) GetBool(keys ...string) (value bool, ok bool) {
+	raw, _, ok := c.lookup(keys...)
+	if !ok {
+		return false, false
+	}
+	value, _ = ParseBool(raw)
+	return value, true
+}
+
+// GetEnum returns the lowercased value of the first of keys that is set, or ""
+// if none is. A value outside allowed is reported as an InvalidConfigValueError.
+func (c *Config) GetEnum(allowed []string, keys ...string) (string, error) {
+	value, key, ok := c.lookup(keys...)
+	if !ok {
+		return "", nil
+	}
+	if !containsFold(allowed, value) {
+		return "", &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: allowed}
+	}
+	return strings.ToLower(strings.TrimSpace(value)), nil
+}
+
+// GetStrategy returns the merge strategy of the first of keys that is set,
+// or "" if none is
+func (c *Config) GetStrategy(keys ...string) (MergeStrategy, error) {
+	value, err := c.GetEnum(mergeStrategies, keys...)
+	return MergeStrategy(value), err
+}
+
+// GetDuration returns the duration of the first of keys that is set, or 0 if
+// none is. Values use Go's duration syntax, e.g. 30s or 5m.
+func (c *Config) GetDuration(keys ...string) (time.Duration, error) {
+	value, key, ok := c.lookup(keys...)
+	if !ok {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: []string{"a duration such as 30s or 5m"}}
+	}
+	return duration, nil
+}
+
+// SetString writes key to the local Git config
+func SetString(key, value string) error {
+	return git.SetConfig(key, value)
+}
+
+// SetBool writes key to the local Git config as true or false
+func SetBool(key string, value bool) error {
+	return git.SetConfig(key, strconv.FormatBool(value))
+}
+
+// SetStrategy writes a merge strategy to the local Git config
+func SetStrategy(key string, strategy MergeStrategy) error {
+	if !containsFold(mergeStrategies, string(strategy)) {
+		return &errors.InvalidConfigValueError{Key: key, Value: string(strategy), Allowed: mergeStrategies}
+	}
+	return git.SetConfig(key, string(strategy))
+}
+
+// SetDuration writes a duration to the local Git config
+func SetDuration(key string, value time.Duration) error {
+	return git.SetConfig(key, value.String())
+}
+
+// Unset removes key from the local Git config
+func Unset(key string) error {
+	return git.UnsetConfig(key)
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
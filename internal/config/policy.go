@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is an organization's policy for the git-flow configuration of a
+// repository, read from the file named by gitflow.policy. git-flow never
+// writes the file.
+type Policy struct {
+	// Pinned maps settings to the values the policy requires. Keys leave out
+	// the "gitflow." prefix, like the settings of 'git flow config export',
+	// e.g. "branch.feature.upstreamStrategy".
+	Pinned map[string]string `yaml:"pinned,omitempty"`
+	// Protected lists branches whose configuration cannot be changed
+	Protected []string `yaml:"protected,omitempty"`
+
+	// Path is the file the policy was read from
+	Path string `yaml:"-"`
+}
+
+// LoadPolicy reads the policy named by gitflow.policy, or returns nil if none
+// is set. A relative path is relative to the root of the working tree.
+func LoadPolicy(cfg *Config) (*Policy, error) {
+	path, _ := cfg.GetString(KeyPolicy)
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		root, err := git.GetTopLevelDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(root, path)
+	}
+	return ReadPolicy(path)
+}
+
+// ReadPolicy reads and validates the policy file at path
+func ReadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	var policy Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy '%s': %w", path, err)
+	}
+	for setting, value := range policy.Pinned {
+		key := "gitflow." + setting
+		spec, ok := LookupKey(key)
+		if !ok {
+			return nil, fmt.Errorf("policy '%s' pins '%s', which is not a git-flow configuration key", path, key)
+		}
+		if err := spec.Validate(key, value); err != nil {
+			return nil, fmt.Errorf("policy '%s': %w", path, err)
+		}
+	}
+	for _, branch := range policy.Protected {
+		if branch == "" {
+			return nil, fmt.Errorf("policy '%s' protects a branch with an empty name", path)
+		}
+	}
+	policy.Path = path
+	return &policy, nil
+}
+
+// ChangeViolations returns what the policy forbids when the configuration
+// current is changed into planned. currentValues and plannedValues hold their
+// gitflow.* keys and values. Pinned settings that already differ from the
+// policy may stay as they are; they are drift, reported by Drift.
+func (p *Policy) ChangeViolations(current, planned *Config, currentValues, plannedValues map[string]string) []string {
+	before, after := lowerKeys(currentValues), lowerKeys(plannedValues)
+
+	var violations []string
+	for _, setting := range p.pinnedSettings() {
+		key := strings.ToLower("gitflow." + setting)
+		was, wasSet := before[key]
+		value, isSet := after[key]
+		if was == value && wasSet == isSet {
+			continue
+		}
+		if isSet && policyValueEqual(value, p.Pinned[setting]) {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("gitflow.%s is pinned to '%s'", setting, p.Pinned[setting]))
+	}
+	for _, branch := range p.Protected {
+		was, wasConfigured := current.Branches[branch]
+		branchConfig, isConfigured := planned.Branches[branch]
+		if was != branchConfig || wasConfigured != isConfigured {
+			violations = append(violations, fmt.Sprintf("the configuration of '%s' is protected", branch))
+		}
+	}
+	return violations
+}
+
+// Drift returns how the configuration cfg differs from the policy. An unset
+// pinned setting only drifts when its default differs from the pinned value.
+func (p *Policy) Drift(cfg *Config) []string {
+	values := lowerKeys(cfg.CommandConfig)
+
+	var drift []string
+	for _, setting := range p.pinnedSettings() {
+		key := "gitflow." + setting
+		pinned := p.Pinned[setting]
+		value, ok := values[strings.ToLower(key)]
+		if !ok {
+			if spec, _ := LookupKey(key); spec.Default != "" && policyValueEqual(spec.Default, pinned) {
+				continue
+			}
+			drift = append(drift, fmt.Sprintf("%s is not set, the policy pins it to '%s'", key, pinned))
+			continue
+		}
+		if !policyValueEqual(value, pinned) {
+			drift = append(drift, fmt.Sprintf("%s is '%s', the policy pins it to '%s'", key, value, pinned))
+		}
+	}
+	for _, branch := range p.Protected {
+		if _, ok := cfg.Branches[branch]; !ok {
+			drift = append(drift, fmt.Sprintf("protected branch '%s' is not configured", branch))
+		}
+	}
+	return drift
+}
+
+// pinnedSettings returns the pinned settings in a stable order
+func (p *Policy) pinnedSettings() []string {
+	settings := make([]string, 0, len(p.Pinned))
+	for setting := range p.Pinned {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+	return settings
+}
+
+// policyValueEqual compares config values the way git-flow reads them:
+// booleans by meaning, everything else ignoring case and surrounding space
+func policyValueEqual(a, b string) bool {
+	if x, ok := ParseBool(a); ok {
+		if y, ok := ParseBool(b); ok {
+			return x == y
+		}
+	}
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// lowerKeys returns values with lowercased keys, so keys compare the way Git
+// matches them
+func lowerKeys(values map[string]string) map[string]string {
+	lowered := make(map[string]string, len(values))
+	for key, value := range values {
+		lowered[strings.ToLower(key)] = value
+	}
+	return lowered
+}
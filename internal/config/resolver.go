@@ -1,6 +1,17 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/util"
+)
 
 // ResolvedFinishOptions contains all resolved configuration options for the finish command
 type ResolvedFinishOptions struct {
@@ -11,6 +22,7 @@ type ResolvedFinishOptions struct {
 	SigningKey  string
 	TagMessage  string
 	MessageFile string
+	ExistingTag string
 
 	// Branch retention options
 	Keep        bool
@@ -19,16 +31,21 @@ type ResolvedFinishOptions struct {
 	ForceDelete bool
 
 	// Merge strategy options
-	MergeStrategy  string // Final resolved strategy (merge/rebase/squash)
-	UseRebase      bool   // Whether to use rebase
-	PreserveMerges bool   // Whether to preserve merges during rebase
-	NoFastForward  bool   // Whether to create merge commit for fast-forward
-	UseSquash      bool   // Whether to squash commits
-	SquashMessage  string // Custom commit message for squash merge
+	MergeStrategy   string // Final resolved strategy (merge/rebase/squash)
+	UseRebase       bool   // Whether to use rebase
+	PreserveMerges  bool   // Whether to preserve merges during rebase
+	NoFastForward   bool   // Whether to create merge commit for fast-forward
+	FastForwardOnly bool   // Whether to refuse merges that need a merge commit
+	UseSquash       bool   // Whether to squash commits
+	SquashMessage   string // Custom commit message for squash merge
 
 	// Fetch options
 	ShouldFetch bool // Whether to fetch from remote before finishing
 
+	// Push options
+	ShouldPush    bool // Whether to push the updated branches and tags after finishing
+	ShouldPushTag bool // Whether to push only the created tag after finishing
+
 	// Custom merge commit messages
 	MergeMessage  string // Custom commit message for upstream merge
 	UpdateMessage string // Custom commit message for child updates
@@ -46,8 +63,19 @@ type TagOptions struct {
 	Message     string
 	MessageFile string
 	TagName     string
+	ExtraTags   []string // --extra-tag specs, added to the configured extra tags
+	NoExtraTags bool     // --no-extra-tags suppresses all extra tags
+	Retag       bool     // --retag moves an existing tag to the finished commit
+	SkipTag     bool     // --skip-tag keeps an existing tag and creates none
 }
 
+// What finish does when the tag it would create already exists; without
+// either, it fails
+const (
+	ExistingTagRetag = "retag"
+	ExistingTagSkip  = "skip"
+)
+
 // BranchRetentionOptions represents command-line retention options
 // Note: This should match the BranchRetentionOptions type in cmd package
 type BranchRetentionOptions struct {
@@ -60,21 +88,38 @@ type BranchRetentionOptions struct {
 // MergeStrategyOptions represents command-line merge strategy options
 // Note: This should match the MergeStrategyOptions type in cmd package
 type MergeStrategyOptions struct {
-	Strategy       *string // Override for entire strategy
-	Rebase         *bool   // --rebase/--no-rebase override
-	PreserveMerges *bool   // --preserve-merges/--no-preserve-merges
-	NoFF           *bool   // --no-ff/--ff
-	Squash         *bool   // --squash/--no-squash override
-	SquashMessage  *string // --squash-message custom commit message
-	MergeMessage   *string // --merge-message custom commit message for upstream merge
-	UpdateMessage  *string // --update-message custom commit message for child updates
+	Strategy       *string  // Override for entire strategy
+	Rebase         *bool    // --rebase/--no-rebase override
+	PreserveMerges *bool    // --preserve-merges/--no-preserve-merges
+	NoFF           *bool    // --no-ff/--ff
+	FFOnly         *bool    // --ff-only
+	Squash         *bool    // --squash/--no-squash override
+	SquashMessage  *string  // --squash-message custom commit message
+	MergeMessage   *string  // --merge-message custom commit message for upstream merge
+	UpdateMessage  *string  // --update-message custom commit message for child updates
+	ChildStrategy  []string // --child-strategy <branch>=<strategy> overrides for child updates
+	NoUpdate       []string // --no-update <branch> skips the update of an auto-update child
+	Edit           bool     // --edit opens the commit and tag messages in the editor
+	IfMerged       bool     // --if-merged skips the merge of a branch already merged into the target
+	Batch          []string // --batch: branches to finish after this one, updating the children only after the last
+	Trailers       []string // --trailer "<key>: <value>" added to the merge or squash commit
+	// --autostash-untracked/--no-autostash-untracked: stash untracked files around the child updates
+	AutostashUntracked *bool
+	// --summary/--no-summary: show the commits and changes the merge brings in before finishing
+	Summary *bool
+	// --backport <branch>: maintenance branches to backport the finished branch
+	// to, replacing the configured ones; --no-backport backports to none
+	Backport   []string
+	NoBackport bool
+	// --backport-pr/--no-backport-pr: open a pull request for each backport
+	BackportPR *bool
 }
 
 // ResolveFinishOptions resolves all finish command options using three-layer precedence:
 // Layer 1: Branch configuration defaults
 // Layer 2: Command-specific git config (gitflow.<branchtype>.finish.*)
 // Layer 3: Command-line arguments (highest priority)
-func ResolveFinishOptions(cfg *Config, branchType string, branchName string, tagOpts *TagOptions, retentionOpts *BranchRetentionOptions, mergeOpts *MergeStrategyOptions, fetch *bool, noVerify *bool) *ResolvedFinishOptions {
+func ResolveFinishOptions(cfg *Config, branchType string, branchName string, tagOpts *TagOptions, retentionOpts *BranchRetentionOptions, mergeOpts *MergeStrategyOptions, fetch *bool, push *bool, noVerify *bool) *ResolvedFinishOptions {
 	branchConfig := cfg.Branches[branchType]
 
 	// Compute full branch name from prefix + branchName
@@ -83,13 +128,17 @@ func ResolveFinishOptions(cfg *Config, branchType string, branchName string, tag
 	// Resolve merge strategy components
 	strategy, useRebase, preserveMerges, noFastForward, useSquash := resolveMergeStrategy(cfg, branchConfig, branchType, mergeOpts)
 
+	// Branches started on a version line are tagged with its tag prefix
+	storedBase, _ := cfg.GetString(BaseKey(fullBranchName))
+
 	return &ResolvedFinishOptions{
 		// Tag resolution
 		ShouldTag:   resolveFinishShouldTag(cfg, branchConfig, branchType, tagOpts),
-		TagName:     resolveFinishTagName(branchConfig, branchType, branchName, tagOpts),
+		TagName:     resolveFinishTagName(lineBranchConfig(cfg, branchConfig, storedBase), branchType, branchName, tagOpts),
 		ShouldSign:  resolveFinishShouldSign(cfg, branchType, tagOpts),
 		SigningKey:  resolveFinishSigningKey(cfg, branchType, tagOpts),
 		TagMessage:  resolveFinishTagMessage(branchName, tagOpts),
+		ExistingTag: resolveFinishExistingTag(tagOpts),
 		MessageFile: resolveFinishMessageFile(cfg, branchType, tagOpts),
 
 		// Retention resolution
@@ -99,16 +148,21 @@ func ResolveFinishOptions(cfg *Config, branchType string, branchName string, tag
 		ForceDelete: resolveFinishForceDelete(cfg, branchType, retentionOpts),
 
 		// Merge strategy resolution
-		MergeStrategy:  strategy,
-		UseRebase:      useRebase,
-		PreserveMerges: preserveMerges,
-		NoFastForward:  noFastForward,
-		UseSquash:      useSquash,
-		SquashMessage:  resolveSquashMessage(fullBranchName, mergeOpts),
+		MergeStrategy:   strategy,
+		UseRebase:       useRebase,
+		PreserveMerges:  preserveMerges,
+		NoFastForward:   noFastForward,
+		FastForwardOnly: resolveFinishFFOnly(cfg, branchType, mergeOpts),
+		UseSquash:       useSquash,
+		SquashMessage:   resolveSquashMessage(fullBranchName, mergeOpts),
 
 		// Fetch resolution
 		ShouldFetch: resolveFinishShouldFetch(cfg, branchType, fetch),
 
+		// Push resolution
+		ShouldPush:    resolveFinishShouldPush(cfg, branchType, push),
+		ShouldPushTag: resolveFinishShouldPushTag(cfg, branchType),
+
 		// Merge commit message resolution
 		MergeMessage:  resolveMergeMessage(cfg, branchType, fullBranchName, branchConfig.Parent, mergeOpts),
 		UpdateMessage: resolveUpdateMessage(cfg, branchType, mergeOpts),
@@ -124,7 +178,7 @@ func resolveFinishShouldTag(cfg *Config, branchConfig BranchConfig, branchType s
 	shouldTag := branchConfig.Tag
 
 	// Layer 2: Command-specific config (notag inverts the default)
-	if notag := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.notag", branchType)); notag {
+	if notag := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptNoTag)); notag {
 		shouldTag = false
 	}
 
@@ -160,7 +214,7 @@ func resolveFinishShouldSign(cfg *Config, branchType string, tagOpts *TagOptions
 	shouldSign := false
 
 	// Layer 2: Check command-specific signing config
-	if sign := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.sign", branchType)); sign {
+	if sign := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptSign)); sign {
 		shouldSign = true
 	}
 
@@ -178,7 +232,7 @@ func resolveFinishSigningKey(cfg *Config, branchType string, tagOpts *TagOptions
 	signingKey := ""
 
 	// Layer 2: Check command-specific signing key
-	if key := getCommandConfigString(cfg, fmt.Sprintf("gitflow.%s.finish.signingkey", branchType)); key != "" {
+	if key := getCommandConfigString(cfg, CommandKey(branchType, CommandFinish, OptSigningKey)); key != "" {
 		signingKey = key
 	}
 
@@ -205,13 +259,29 @@ func resolveFinishTagMessage(branchName string, tagOpts *TagOptions) string {
 	return message
 }
 
+// resolveFinishExistingTag resolves what to do when the tag already exists
+// Layer 1: Default is "", failing the finish
+// Layer 2: No config, the choice is made per finish
+// Layer 3: --retag / --skip-tag
+func resolveFinishExistingTag(tagOpts *TagOptions) string {
+	switch {
+	case tagOpts == nil:
+		return ""
+	case tagOpts.Retag:
+		return ExistingTagRetag
+	case tagOpts.SkipTag:
+		return ExistingTagSkip
+	}
+	return ""
+}
+
 // resolveFinishMessageFile resolves the message file path
 func resolveFinishMessageFile(cfg *Config, branchType string, tagOpts *TagOptions) string {
 	// Layer 1: Default is empty
 	messageFile := ""
 
 	// Layer 2: Check command-specific message file config
-	if file := getCommandConfigString(cfg, fmt.Sprintf("gitflow.%s.finish.messagefile", branchType)); file != "" {
+	if file := getCommandConfigString(cfg, CommandKey(branchType, CommandFinish, OptMessageFile)); file != "" {
 		messageFile = file
 	}
 
@@ -229,7 +299,7 @@ func resolveFinishKeep(cfg *Config, branchType string, retentionOpts *BranchRete
 	keep := false
 
 	// Layer 2: Check command-specific config
-	if keepConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.keep", branchType)); keepConfig {
+	if keepConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptKeep)); keepConfig {
 		keep = true
 	}
 
@@ -247,7 +317,7 @@ func resolveFinishKeepRemote(cfg *Config, branchType string, retentionOpts *Bran
 	keepRemote := false
 
 	// Layer 2: Check command-specific config
-	if keepRemoteConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.keepremote", branchType)); keepRemoteConfig {
+	if keepRemoteConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptKeepRemote)); keepRemoteConfig {
 		keepRemote = true
 	}
 
@@ -265,7 +335,7 @@ func resolveFinishKeepLocal(cfg *Config, branchType string, retentionOpts *Branc
 	keepLocal := false
 
 	// Layer 2: Check command-specific config
-	if keepLocalConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.keeplocal", branchType)); keepLocalConfig {
+	if keepLocalConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptKeepLocal)); keepLocalConfig {
 		keepLocal = true
 	}
 
@@ -283,7 +353,7 @@ func resolveFinishForceDelete(cfg *Config, branchType string, retentionOpts *Bra
 	forceDelete := false
 
 	// Layer 2: Check command-specific config
-	if forceDeleteConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.force-delete", branchType)); forceDeleteConfig {
+	if forceDeleteConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptForceDelete)); forceDeleteConfig {
 		forceDelete = true
 	}
 
@@ -297,16 +367,13 @@ func resolveFinishForceDelete(cfg *Config, branchType string, retentionOpts *Bra
 
 // getCommandConfigBool gets a boolean config value from preloaded config
 func getCommandConfigBool(cfg *Config, configKey string) bool {
-	value, exists := cfg.CommandConfig[configKey]
-	return exists && value == "true"
+	value, _ := cfg.GetBool(configKey)
+	return value
 }
 
 // getCommandConfigString gets a string config value from preloaded config
 func getCommandConfigString(cfg *Config, configKey string) string {
-	value, exists := cfg.CommandConfig[configKey]
-	if !exists {
-		return ""
-	}
+	value, _ := cfg.GetString(configKey)
 	return value
 }
 
@@ -343,11 +410,11 @@ func resolveFinishRebase(cfg *Config, branchType string, baseStrategy string, me
 	useRebase := baseStrategy == "rebase"
 
 	// Layer 2: Command-specific config
-	if rebaseConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.rebase", branchType)); rebaseConfig {
+	if rebaseConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptRebase)); rebaseConfig {
 		useRebase = true
 	}
 	// Check for explicit no-rebase config
-	if noRebaseConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.no-rebase", branchType)); noRebaseConfig {
+	if noRebaseConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptNoRebase)); noRebaseConfig {
 		useRebase = false
 	}
 
@@ -371,11 +438,11 @@ func resolveFinishSquash(cfg *Config, branchType string, baseStrategy string, me
 	useSquash := baseStrategy == "squash"
 
 	// Layer 2: Command-specific config
-	if squashConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.squash", branchType)); squashConfig {
+	if squashConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptSquash)); squashConfig {
 		useSquash = true
 	}
 	// Check for explicit no-squash config
-	if noSquashConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.no-squash", branchType)); noSquashConfig {
+	if noSquashConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptNoSquash)); noSquashConfig {
 		useSquash = false
 	}
 
@@ -399,11 +466,11 @@ func resolveFinishPreserveMerges(cfg *Config, branchType string, mergeOpts *Merg
 	preserveMerges := false
 
 	// Layer 2: Command-specific config
-	if preserveConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.preserve-merges", branchType)); preserveConfig {
+	if preserveConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptPreserveMerges)); preserveConfig {
 		preserveMerges = true
 	}
 	// Check for explicit no-preserve-merges config
-	if noPreserveConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.no-preserve-merges", branchType)); noPreserveConfig {
+	if noPreserveConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptNoPreserveMerges)); noPreserveConfig {
 		preserveMerges = false
 	}
 
@@ -421,11 +488,11 @@ func resolveFinishNoFF(cfg *Config, branchType string, mergeOpts *MergeStrategyO
 	noFF := false
 
 	// Layer 2: Command-specific config
-	if noFFConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.no-ff", branchType)); noFFConfig {
+	if noFFConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptNoFF)); noFFConfig {
 		noFF = true
 	}
 	// Check for explicit fast-forward config
-	if ffConfig := getCommandConfigBool(cfg, fmt.Sprintf("gitflow.%s.finish.ff", branchType)); ffConfig {
+	if ffConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptFF)); ffConfig {
 		noFF = false
 	}
 
@@ -434,18 +501,46 @@ func resolveFinishNoFF(cfg *Config, branchType string, mergeOpts *MergeStrategyO
 		noFF = *mergeOpts.NoFF
 	}
 
+	// Fast-forward-only merges never create a merge commit
+	if resolveFinishFFOnly(cfg, branchType, mergeOpts) {
+		noFF = false
+	}
+
 	return noFF
 }
 
+// resolveFinishFFOnly resolves whether the merge must be a fast-forward
+func resolveFinishFFOnly(cfg *Config, branchType string, mergeOpts *MergeStrategyOptions) bool {
+	// Layer 1: Default is to allow merge commits
+	ffOnly := false
+
+	// Layer 2: Command-specific config
+	if ffOnlyConfig := getCommandConfigBool(cfg, CommandKey(branchType, CommandFinish, OptFFOnly)); ffOnlyConfig {
+		ffOnly = true
+	}
+
+	// Layer 3: Command-line flags override config; --ff and --no-ff lift a configured ff-only
+	if mergeOpts != nil {
+		if mergeOpts.NoFF != nil {
+			ffOnly = false
+		}
+		if mergeOpts.FFOnly != nil {
+			ffOnly = *mergeOpts.FFOnly
+		}
+	}
+
+	return ffOnly
+}
+
 // resolveFinishShouldFetch resolves whether to fetch from remote before finishing
 func resolveFinishShouldFetch(cfg *Config, branchType string, fetch *bool) bool {
 	// Layer 1: Default is to fetch (ensures sync check has accurate data)
 	shouldFetch := true
 
 	// Layer 2: Check command-specific config (can set true OR false)
-	configKey := fmt.Sprintf("gitflow.%s.finish.fetch", branchType)
-	if value, exists := cfg.CommandConfig[configKey]; exists {
-		shouldFetch = value == "true"
+	configKey := CommandKey(branchType, CommandFinish, OptFetch)
+	if value, exists := cfg.GetBool(configKey); exists {
+		shouldFetch = value
 	}
 
 	// Layer 3: Command-line flags override config
@@ -456,6 +551,41 @@ func resolveFinishShouldFetch(cfg *Config, branchType string, fetch *bool) bool
 	return shouldFetch
 }
 
+// resolveFinishShouldPush resolves whether to push the updated branches and tags after finishing
+func resolveFinishShouldPush(cfg *Config, branchType string, push *bool) bool {
+	// Layer 1: Default is to leave pushing to the user
+	shouldPush := false
+
+	// Layer 2: Check command-specific config
+	configKey := CommandKey(branchType, CommandFinish, OptPush)
+	if value, exists := cfg.GetBool(configKey); exists {
+		shouldPush = value
+	}
+
+	// Layer 3: Command-line flags override config
+	if push != nil {
+		shouldPush = *push
+	}
+
+	return shouldPush
+}
+
+// resolveFinishShouldPushTag resolves whether to push only the created tag after finishing.
+// This is a config-only setting for workflows where branches reach the remote through
+// pull requests but tags are pushed to trigger releases.
+func resolveFinishShouldPushTag(cfg *Config, branchType string) bool {
+	// Layer 1: Default is to leave pushing to the user
+	shouldPushTag := false
+
+	// Layer 2: Check command-specific config
+	configKey := CommandKey(branchType, CommandFinish, OptPushTag)
+	if value, exists := cfg.GetBool(configKey); exists {
+		shouldPushTag = value
+	}
+
+	return shouldPushTag
+}
+
 // resolveFinishNoVerify resolves whether to skip pre-commit and commit-msg hooks
 func resolveFinishNoVerify(cfg *Config, branchType string, noVerify *bool) bool {
 	// Layer 1: Default is to run hooks (no-verify = false)
@@ -463,9 +593,9 @@ func resolveFinishNoVerify(cfg *Config, branchType string, noVerify *bool) bool
 
 	// Layer 2: Check command-specific config
 	// Note: Git config keys are stored lowercase, so we use "noverify" not "noVerify"
-	configKey := fmt.Sprintf("gitflow.%s.finish.noverify", branchType)
-	if value, exists := cfg.CommandConfig[configKey]; exists {
-		skipVerify = value == "true"
+	configKey := CommandKey(branchType, CommandFinish, OptNoVerify)
+	if value, exists := cfg.GetBool(configKey); exists {
+		skipVerify = value
 	}
 
 	// Layer 3: Command-line flags override config
@@ -499,7 +629,7 @@ func resolveMergeMessage(cfg *Config, branchType string, fullBranchName string,
 	}
 
 	// Layer 2: Command-specific config
-	if msg := getCommandConfigString(cfg, fmt.Sprintf("gitflow.%s.finish.mergemessage", branchType)); msg != "" {
+	if msg := getCommandConfigString(cfg, CommandKey(branchType, CommandFinish, OptMergeMessage)); msg != "" {
 		return msg
 	}
 
@@ -517,10 +647,630 @@ func resolveUpdateMessage(cfg *Config, branchType string, mergeOpts *MergeStrate
 	}
 
 	// Layer 2: Command-specific config
-	if msg := getCommandConfigString(cfg, fmt.Sprintf("gitflow.%s.finish.updatemessage", branchType)); msg != "" {
+	if msg := getCommandConfigString(cfg, CommandKey(branchType, CommandFinish, OptUpdateMessage)); msg != "" {
 		return msg
 	}
 
 	// Empty string signals to use the default auto-generated message
 	return ""
 }
+
+// Base resolution policies for finishing topic branches
+const (
+	// BaseResolutionConfigured merges into the parent from the branch type configuration
+	BaseResolutionConfigured = "configured"
+	// BaseResolutionStored merges into the base recorded when the branch was started
+	BaseResolutionStored = "stored"
+	// BaseResolutionPrompt asks which base to use when the two differ
+	BaseResolutionPrompt = "prompt"
+)
+
+// ResolveBaseResolution resolves how finish picks the target branch.
+// Layer 1: Default is "configured"
+// Layer 2: gitflow.<branchtype>.finish.baseResolution, then gitflow.finish.baseResolution
+// The returned key names the setting the value came from, for error reporting.
+func ResolveBaseResolution(cfg *Config, branchType string) (policy string, key string) {
+	typeKey := CommandKey(branchType, CommandFinish, OptBaseResolution)
+	if value := getCommandConfigString(cfg, typeKey); value != "" {
+		return strings.ToLower(value), typeKey
+	}
+
+	globalKey := CommandKey("", CommandFinish, OptBaseResolution)
+	if value := getCommandConfigString(cfg, globalKey); value != "" {
+		return strings.ToLower(value), globalKey
+	}
+
+	return BaseResolutionConfigured, ""
+}
+
+// Policies for finishing a branch that merged its parent in repeatedly
+const (
+	// BackMergesAllow finishes the branch without a word
+	BackMergesAllow = "allow"
+	// BackMergesWarn suggests finishing with --rebase or --squash
+	BackMergesWarn = "warn"
+	// BackMergesRefuse refuses to merge the branch with its back-merges
+	BackMergesRefuse = "refuse"
+)
+
+// BackMergePolicy is how finish treats back-merges: merge commits on a topic
+// branch that brought its parent in, which a merge would add to the parent's history
+type BackMergePolicy struct {
+	Action string // allow, warn or refuse
+	Max    int    // Back-merges tolerated before the action applies
+}
+
+// ResolveFinishBackMerges resolves how finish treats back-merges.
+// Layer 1: Default is to warn about more than one back-merge
+// Layer 2: gitflow.<branchtype>.finish.backMerges and finish.maxBackMerges, then
+// the gitflow.finish.* keys
+// Layer 3: --rebase and --squash drop the merges and are handled by the caller
+func ResolveFinishBackMerges(cfg *Config, branchType string) (BackMergePolicy, error) {
+	keys := func(option string) []string {
+		return []string{CommandKey(branchType, CommandFinish, option), CommandKey("", CommandFinish, option)}
+	}
+
+	policy := BackMergePolicy{Action: BackMergesWarn, Max: 1}
+	action, err := cfg.GetEnum(backMergePolicies, keys(OptBackMerges)...)
+	if err != nil {
+		return policy, err
+	}
+	if action != "" {
+		policy.Action = action
+	}
+	if value, key, ok := cfg.lookup(keys(OptMaxBackMerges)...); ok {
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return policy, &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: []string{"a number of merges"}}
+		}
+		policy.Max = limit
+	}
+	return policy, nil
+}
+
+// ResolveAllowTopicBase resolves whether branches of a type can be started from
+// another topic branch, such as a bugfix from a release branch, and finished
+// back into it.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.allowTopicBase
+func ResolveAllowTopicBase(cfg *Config, branchType string) bool {
+	value, _ := cfg.GetBool(TypeKey(branchType, OptAllowTopicBase))
+	return value
+}
+
+// ResolveRequireUpToDateTopic resolves whether finish refuses to merge a topic
+// branch that is behind or diverged from its remote counterpart.
+// Layer 1: Default is true
+// Layer 2: gitflow.<branchtype>.finish.requireUpToDateTopic, then gitflow.finish.requireUpToDateTopic
+// Layer 3: --force skips the check altogether (handled by the caller)
+func ResolveRequireUpToDateTopic(cfg *Config, branchType string) bool {
+	if value, exists := cfg.GetBool(
+		CommandKey(branchType, CommandFinish, OptRequireUpToDateTopic),
+		CommandKey("", CommandFinish, OptRequireUpToDateTopic),
+	); exists {
+		return value
+	}
+	return true
+}
+
+// ResolveUpdateNoVerify resolves whether update (and rebase) bypasses the
+// commit hooks and the pre-rebase hook.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.update.noVerify, then gitflow.update.noVerify
+// Layer 3: --no-verify
+func ResolveUpdateNoVerify(cfg *Config, branchType string, noVerify *bool) bool {
+	if noVerify != nil {
+		return *noVerify
+	}
+	keys := []string{CommandKey("", CommandUpdate, OptNoVerify)}
+	if branchType != "" {
+		keys = append([]string{CommandKey(branchType, CommandUpdate, OptNoVerify)}, keys...)
+	}
+	value, _ := cfg.GetBool(keys...)
+	return value
+}
+
+// ResolveFinishNoVerifyChildren resolves whether finish bypasses the commit
+// hooks when it updates child branches. Independent of --no-verify, which only
+// covers the merge of the topic branch.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.finish.noVerifyChildren, then gitflow.finish.noVerifyChildren
+// Layer 3: --no-verify-children
+func ResolveFinishNoVerifyChildren(cfg *Config, branchType string, noVerifyChildren *bool) bool {
+	if noVerifyChildren != nil {
+		return *noVerifyChildren
+	}
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandFinish, OptNoVerifyChildren),
+		CommandKey("", CommandFinish, OptNoVerifyChildren),
+	)
+	return value
+}
+
+// ResolveFinishAutostashUntracked resolves whether finish stashes untracked
+// files that checking out a child base branch would overwrite, and restores
+// them after the child updates.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.finish.autostashUntracked, then gitflow.finish.autostashUntracked
+// Layer 3: --autostash-untracked / --no-autostash-untracked
+func ResolveFinishAutostashUntracked(cfg *Config, branchType string, mergeOpts *MergeStrategyOptions) bool {
+	if mergeOpts != nil && mergeOpts.AutostashUntracked != nil {
+		return *mergeOpts.AutostashUntracked
+	}
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandFinish, OptAutostashUntracked),
+		CommandKey("", CommandFinish, OptAutostashUntracked),
+	)
+	return value
+}
+
+// ResolveFinishSummary reports whether finish shows the commits and changes
+// the merge brings into the target branch before anything is changed.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.finish.summary, then gitflow.finish.summary
+// Layer 3: --summary/--no-summary
+func ResolveFinishSummary(cfg *Config, branchType string, mergeOpts *MergeStrategyOptions) bool {
+	if mergeOpts != nil && mergeOpts.Summary != nil {
+		return *mergeOpts.Summary
+	}
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandFinish, OptSummary),
+		CommandKey("", CommandFinish, OptSummary),
+	)
+	return value
+}
+
+// ResolveFinishBackports resolves the maintenance branches the finished
+// branch is backported to, and whether a pull request is opened for each.
+// Layer 1: Default is no backports, without pull requests
+// Layer 2: gitflow.<branchtype>.finish.backport (comma-separated) and
+// gitflow.<branchtype>.finish.backportpr
+// Layer 3: --backport replaces the configured branches, --no-backport clears
+// them; --backport-pr/--no-backport-pr
+func ResolveFinishBackports(cfg *Config, branchType string, mergeOpts *MergeStrategyOptions) ([]string, bool) {
+	var targets []string
+	if value, ok := cfg.GetString(CommandKey(branchType, CommandFinish, OptBackport)); ok {
+		targets = strings.Split(value, ",")
+	}
+	openPR, _ := cfg.GetBool(CommandKey(branchType, CommandFinish, OptBackportPR))
+
+	if mergeOpts != nil {
+		if len(mergeOpts.Backport) > 0 {
+			targets = mergeOpts.Backport
+		}
+		if mergeOpts.NoBackport {
+			targets = nil
+		}
+		if mergeOpts.BackportPR != nil {
+			openPR = *mergeOpts.BackportPR
+		}
+	}
+
+	var branches []string
+	for _, target := range targets {
+		if target = strings.TrimSpace(target); target != "" && !slices.Contains(branches, target) {
+			branches = append(branches, target)
+		}
+	}
+	return branches, openPR
+}
+
+// ResolveFinishMergeTagToChildren reports whether child base branches are
+// updated from the created tag rather than the parent branch, so they record
+// the tag object like git-flow-avh does.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.finish.mergeTagToChildren
+func ResolveFinishMergeTagToChildren(cfg *Config, branchType string) bool {
+	value, _ := cfg.GetBool(CommandKey(branchType, CommandFinish, OptMergeTagToChildren))
+	return value
+}
+
+// ResolveForge returns the hosting service configured for compare URLs.
+// Layer 1: Default is "" (detect the service from the remote URL)
+// Layer 2: gitflow.forge
+func ResolveForge(cfg *Config) string {
+	return strings.ToLower(getCommandConfigString(cfg, KeyForge))
+}
+
+// DefaultReleaseNotesTrailer is the commit trailer release notes are collected from
+const DefaultReleaseNotesTrailer = "Release-Note"
+
+// ReleaseNotesOptions controls how release notes are collected on finish
+type ReleaseNotesOptions struct {
+	Enabled bool   // Whether to collect notes when a tagged branch is finished
+	Trailer string // Commit trailer holding the notes
+	File    string // Where to write the notes; empty writes them to the git directory
+	Tag     bool   // Whether to append the notes to the tag annotation
+}
+
+// ResolveReleaseNotes resolves the release notes settings.
+// Layer 1: Default is disabled, collecting the "Release-Note" trailer
+// Layer 2: gitflow.releasenotes.enabled, .trailer, .file and .tag
+func ResolveReleaseNotes(cfg *Config) ReleaseNotesOptions {
+	options := ReleaseNotesOptions{
+		Enabled: getCommandConfigBool(cfg, KeyReleaseNotesEnabled),
+		Trailer: getCommandConfigString(cfg, KeyReleaseNotesTrailer),
+		File:    getCommandConfigString(cfg, KeyReleaseNotesFile),
+		Tag:     getCommandConfigBool(cfg, KeyReleaseNotesTag),
+	}
+	if options.Trailer == "" {
+		options.Trailer = DefaultReleaseNotesTrailer
+	}
+	return options
+}
+
+// BaseSignatureOptions controls the signature check of the base branch on finish
+type BaseSignatureOptions struct {
+	Verify      bool     // Whether the tip of the base branch must carry a good signature
+	AllowedKeys []string // Key IDs or fingerprints the signature must be made with; empty allows any key
+}
+
+// ResolveBaseSignature resolves the base branch signature check for finish.
+// Layer 1: Default is disabled, allowing any key
+// Layer 2: gitflow.<branchtype>.finish.verifybasesignature and .allowedsigningkeys,
+// then gitflow.finish.verifybasesignature and gitflow.finish.allowedsigningkeys
+func ResolveBaseSignature(cfg *Config, branchType string) BaseSignatureOptions {
+	keys := func(option string) []string {
+		return []string{CommandKey(branchType, CommandFinish, option), CommandKey("", CommandFinish, option)}
+	}
+
+	options := BaseSignatureOptions{}
+	options.Verify, _ = cfg.GetBool(keys(OptVerifyBaseSignature)...)
+	if value, exists := cfg.GetString(keys(OptAllowedSigningKeys)...); exists {
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				options.AllowedKeys = append(options.AllowedKeys, key)
+			}
+		}
+	}
+	return options
+}
+
+// ResolveStartFromRemote resolves whether start bases the new branch on the
+// remote counterpart of its start point.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.start.fromRemote, then gitflow.start.fromRemote
+// Layer 3: --from-remote / --no-from-remote
+func ResolveStartFromRemote(cfg *Config, branchType string, fromRemote *bool) bool {
+	if fromRemote != nil {
+		return *fromRemote
+	}
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandStart, OptFromRemote),
+		CommandKey("", CommandStart, OptFromRemote),
+	)
+	return value
+}
+
+// ResolveStartSlugify resolves whether start normalizes the branch name when
+// no version filter script is installed.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.start.slugify, then gitflow.start.slugify
+func ResolveStartSlugify(cfg *Config, branchType string) bool {
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandStart, OptSlugify),
+		CommandKey("", CommandStart, OptSlugify),
+	)
+	return value
+}
+
+// ResolveStartPublish resolves whether start publishes the new branch right
+// after creating it.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.start.publish, then gitflow.start.publish
+// Layer 3: --publish / --no-publish
+func ResolveStartPublish(cfg *Config, branchType string, publish *bool) bool {
+	if publish != nil {
+		return *publish
+	}
+	value, _ := cfg.GetBool(
+		CommandKey(branchType, CommandStart, OptPublish),
+		CommandKey("", CommandStart, OptPublish),
+	)
+	return value
+}
+
+// ResolveTrackUpstream resolves whether start, publish and track set up Git's
+// upstream tracking (branch.<name>.remote and branch.<name>.merge) for the
+// remote branch of the same name.
+// Layer 1: Default is true
+// Layer 2: gitflow.<branchtype>.trackUpstream, then gitflow.trackUpstream
+// Layer 3: --track-upstream / --no-track-upstream
+func ResolveTrackUpstream(cfg *Config, branchType string, trackUpstream *bool) bool {
+	if trackUpstream != nil {
+		return *trackUpstream
+	}
+	value, ok := cfg.GetBool(TypeKey(branchType, OptTrackUpstream), KeyTrackUpstream)
+	if !ok {
+		return true
+	}
+	return value
+}
+
+// ResolveNetworkRetry resolves how often fetches and pushes retry transient
+// network failures, and how long they wait before the first retry.
+// Layer 1: Default is 2 retries, waiting 1s and then 2s
+// Layer 2: gitflow.network.retries and gitflow.network.retryDelay
+func ResolveNetworkRetry(cfg *Config) (git.RetryPolicy, error) {
+	policy := git.DefaultRetryPolicy
+	if value, key, ok := cfg.lookup(KeyNetworkRetries); ok {
+		retries, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || retries < 0 {
+			return policy, &errors.InvalidConfigValueError{Key: key, Value: value, Allowed: []string{"a number of retries"}}
+		}
+		policy.Retries = retries
+	}
+	if _, _, ok := cfg.lookup(KeyNetworkRetryDelay); ok {
+		delay, err := cfg.GetDuration(KeyNetworkRetryDelay)
+		if err != nil {
+			return policy, err
+		}
+		policy.Delay = delay
+	}
+	return policy, nil
+}
+
+// ResolveRCPush resolves whether release rc pushes the release candidate tag.
+// Layer 1: Default is false
+// Layer 2: gitflow.<branchtype>.rc.push
+// Layer 3: --push / --no-push
+func ResolveRCPush(cfg *Config, branchType string, push *bool) bool {
+	if push != nil {
+		return *push
+	}
+	value, _ := cfg.GetBool(CommandKey(branchType, CommandRC, OptPush))
+	return value
+}
+
+// DefaultPrereleaseChannels are the channels prerelease tags can be created
+// for unless gitflow.prerelease.channels lists others
+var DefaultPrereleaseChannels = []string{"alpha", "beta", "nightly"}
+
+// DefaultPrereleaseFormat names prerelease tags after the tag of the upcoming
+// release, e.g. v1.3.0-beta.4
+const DefaultPrereleaseFormat = "%t-%c.%n"
+
+// PrereleaseOptions controls the prerelease tags of a channel
+type PrereleaseOptions struct {
+	Channel string
+	Format  string // Tag name format, see util.ExpandPrereleasePlaceholders
+	Branch  string // Base branch the channel is tagged on
+	Bump    string // Version part the upcoming release increments
+	Push    bool
+}
+
+// ResolvePrerelease resolves the options of a prerelease channel.
+// Layer 1: Default channels are alpha, beta and nightly, formatted as
+// %t-%c.%n on the branch releases start from, bumping the minor version
+// Layer 2: gitflow.prerelease.channels, .bump and .push, then
+// gitflow.prerelease.<channel>.format and .branch
+// Layer 3: --branch and --push / --no-push
+func ResolvePrerelease(cfg *Config, channel string, branch string, push *bool) (PrereleaseOptions, error) {
+	channels := DefaultPrereleaseChannels
+	if value, ok := cfg.GetString(KeyPrereleaseChannels); ok {
+		channels = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				channels = append(channels, name)
+			}
+		}
+	}
+	if !containsFold(channels, channel) {
+		return PrereleaseOptions{}, &errors.InvalidInputError{Message: fmt.Sprintf("unknown prerelease channel '%s' (configured: %s; see gitflow.prerelease.channels)", channel, strings.Join(channels, ", "))}
+	}
+
+	options := PrereleaseOptions{Channel: channel, Format: DefaultPrereleaseFormat, Bump: util.VersionMinor}
+	if value, ok := cfg.GetString(ChannelKey(channel, OptChannelFormat)); ok && value != "" {
+		if !strings.Contains(value, "%n") {
+			return options, &errors.InvalidConfigValueError{Key: ChannelKey(channel, OptChannelFormat), Value: value, Allowed: []string{"a format containing %n"}}
+		}
+		options.Format = value
+	}
+	bump, err := cfg.GetEnum(versionParts, KeyPrereleaseBump)
+	if err != nil {
+		return options, err
+	}
+	if bump != "" {
+		options.Bump = bump
+	}
+
+	// Releases start from the branch their prereleases are cut from
+	options.Branch = cfg.Branches["release"].StartPoint
+	if options.Branch == "" {
+		options.Branch = cfg.Branches["release"].Parent
+	}
+	if value, ok := cfg.GetString(ChannelKey(channel, OptChannelBranch)); ok && value != "" {
+		options.Branch = value
+	}
+	if branch != "" {
+		options.Branch = branch
+	}
+
+	options.Push, _ = cfg.GetBool(KeyPrereleasePush)
+	if push != nil {
+		options.Push = *push
+	}
+	return options, nil
+}
+
+// Mirror is a secondary remote finish pushes the finished branches and tags to
+type Mirror struct {
+	Remote   string
+	Required bool // a failed push to the mirror fails the finish
+}
+
+// ResolveMirrors resolves the mirror remotes, in the listed order.
+// Layer 1: Default is no mirrors
+// Layer 2: gitflow.mirror.remotes (comma-separated), and
+// gitflow.mirror.<remote>.required for each listed remote
+func ResolveMirrors(cfg *Config) []Mirror {
+	value, ok := cfg.GetString(KeyMirrorRemotes)
+	if !ok {
+		return nil
+	}
+	var mirrors []Mirror
+	seen := make(map[string]bool)
+	for _, remote := range strings.Split(value, ",") {
+		remote = strings.TrimSpace(remote)
+		if remote == "" || seen[remote] || remote == cfg.Remote {
+			continue
+		}
+		seen[remote] = true
+		required, _ := cfg.GetBool(MirrorKey(remote, OptMirrorRequired))
+		mirrors = append(mirrors, Mirror{Remote: remote, Required: required})
+	}
+	return mirrors
+}
+
+// VersionLine is a maintained family of versions, such as 1.x, whose releases
+// and hotfixes start from and finish into their own base branch
+type VersionLine struct {
+	Name      string
+	Base      string // base branch of the line, e.g. support/1.x
+	TagPrefix string // tag prefix of the line's releases, if it has its own
+}
+
+// Contains reports whether version belongs to the line. A line named like
+// 1.x or 2.3.x holds the versions starting with 1. or 2.3.; other lines hold
+// any version.
+func (line VersionLine) Contains(version string) bool {
+	family, ok := strings.CutSuffix(line.Name, ".x")
+	if !ok {
+		return true
+	}
+	return version == family || strings.HasPrefix(version, family+".")
+}
+
+// VersionLines returns the configured version lines, ordered by name. A line
+// is configured by its gitflow.line.<line>.base.
+func VersionLines(cfg *Config) []VersionLine {
+	prefix, suffix := LineSection(""), "."+OptLineBase
+	var lines []VersionLine
+	for key, base := range cfg.CommandConfig {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(key, prefix), suffix)
+		if !strings.HasPrefix(key, prefix) || !ok || name == "" || base == "" {
+			continue
+		}
+		tagPrefix, _ := cfg.GetString(LineKey(name, OptLineTagPrefix))
+		lines = append(lines, VersionLine{Name: name, Base: base, TagPrefix: tagPrefix})
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Name < lines[j].Name })
+	return lines
+}
+
+// ResolveVersionLine returns the configured version line called name
+func ResolveVersionLine(cfg *Config, name string) (VersionLine, error) {
+	lines := VersionLines(cfg)
+	names := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.EqualFold(line.Name, name) {
+			return line, nil
+		}
+		names[i] = line.Name
+	}
+	if len(names) == 0 {
+		return VersionLine{}, &errors.InvalidInputError{Message: fmt.Sprintf("unknown version line '%s'; configure its base branch with %s", name, LineKey(name, OptLineBase))}
+	}
+	return VersionLine{}, &errors.InvalidInputError{Message: fmt.Sprintf("unknown version line '%s' (configured: %s)", name, strings.Join(names, ", "))}
+}
+
+// VersionLineOfBase returns the version line whose base branch is base
+func VersionLineOfBase(cfg *Config, base string) (VersionLine, bool) {
+	if base == "" {
+		return VersionLine{}, false
+	}
+	for _, line := range VersionLines(cfg) {
+		if line.Base == base {
+			return line, true
+		}
+	}
+	return VersionLine{}, false
+}
+
+// lineBranchConfig returns branchConfig with the tag prefix of the version
+// line the branch was started on, if that line has its own
+func lineBranchConfig(cfg *Config, branchConfig BranchConfig, base string) BranchConfig {
+	if line, ok := VersionLineOfBase(cfg, base); ok && line.TagPrefix != "" {
+		branchConfig.TagPrefix = line.TagPrefix
+	}
+	return branchConfig
+}
+
+// ResolveStartTagName resolves the tag finish will create for a new branch of
+// the type started from base, or "" when finish does not tag it. Start checks
+// it up front, so a taken tag fails the start rather than the finish.
+func ResolveStartTagName(cfg *Config, branchType string, name string, base string) string {
+	branchConfig := cfg.Branches[branchType]
+	if !resolveFinishShouldTag(cfg, branchConfig, branchType, nil) {
+		return ""
+	}
+	return resolveFinishTagName(lineBranchConfig(cfg, branchConfig, base), branchType, name, nil)
+}
+
+// SortByUpdateOrder sorts child base branches into the order finish updates
+// them: the branches listed in gitflow.updateOrder (comma-separated) first, in
+// the listed order, then the others by name.
+func SortByUpdateOrder(cfg *Config, branches []string) {
+	rank := make(map[string]int)
+	if value, ok := cfg.GetString(KeyUpdateOrder); ok {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				if _, listed := rank[name]; !listed {
+					rank[name] = len(rank)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		ri, iListed := rank[branches[i]]
+		rj, jListed := rank[branches[j]]
+		switch {
+		case iListed && jListed:
+			return ri < rj
+		case iListed != jListed:
+			return iListed
+		default:
+			return branches[i] < branches[j]
+		}
+	})
+}
+
+// CheckOptions holds the policy 'git flow check' enforces on a topic branch
+type CheckOptions struct {
+	MaxBehind   int            // Commits the branch may lag behind its parent; -1 for no limit
+	NamePattern *regexp.Regexp // Pattern the short name must match; nil allows any name
+}
+
+// ResolveCheckOptions resolves the policy 'git flow check' enforces on a
+// branch of branchType. Invalid values are reported as errors.
+// Layer 1: Default is no limit and no name pattern
+// Layer 2: gitflow.<branchtype>.check.maxBehind and .namePattern, then
+// gitflow.check.maxBehind and gitflow.check.namePattern
+// Layer 3: --max-behind
+func ResolveCheckOptions(cfg *Config, branchType string, maxBehind *int) (CheckOptions, error) {
+	keys := func(option string) []string {
+		return []string{CommandKey(branchType, CommandCheck, option), CommandKey("", CommandCheck, option)}
+	}
+
+	options := CheckOptions{MaxBehind: -1}
+	if value, key, ok := cfg.lookup(keys(OptMaxBehind)...); ok {
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 0 {
+			return options, fmt.Errorf("invalid value '%s' for %s: expected a number of commits", value, key)
+		}
+		options.MaxBehind = limit
+	}
+	if maxBehind != nil {
+		options.MaxBehind = *maxBehind
+	}
+
+	if value, key, ok := cfg.lookup(keys(OptNamePattern)...); ok && value != "" {
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return options, fmt.Errorf("invalid value '%s' for %s: %v", value, key, err)
+		}
+		options.NamePattern = pattern
+	}
+	return options, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import "strings"
+
+// TemplateOptions returns the options of the topic type template name, set as
+// gitflow.template.<name>.<option>, keyed by the lowercased option, e.g.
+// "prefix" or "finish.squash". It is empty if the template is not defined.
+func TemplateOptions(cfg *Config, name string) map[string]string {
+	prefix := strings.ToLower(TemplateSection(name)) + "."
+	options := make(map[string]string)
+	for key, value := range cfg.CommandConfig {
+		if option, ok := strings.CutPrefix(key, prefix); ok && option != "" {
+			options[option] = value
+		}
+	}
+	return options
+}
+
+// ApplyTemplate sets the branch properties of branchConfig to those set in
+// the template options, e.g. gitflow.template.<name>.prefix. The type and
+// parent of a branch type are never taken from a template.
+func ApplyTemplate(branchConfig *BranchConfig, options map[string]string) {
+	for option, value := range options {
+		switch option {
+		case strings.ToLower(PropType), strings.ToLower(PropParent):
+			continue
+		}
+		setBranchProperty(branchConfig, option, value)
+	}
+}
+
+// templateValue returns the value a branch type option such as
+// gitflow.<type>.finish.squash inherits from the template of the type, and the
+// template key holding it
+func (c *Config) templateValue(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(strings.ToLower(key), "gitflow.")
+	if !ok {
+		return "", "", false
+	}
+	branchType, option, ok := strings.Cut(rest, ".")
+	if !ok {
+		return "", "", false
+	}
+	branchConfig, exists := c.Branches[branchType]
+	if !exists || branchConfig.Template == "" {
+		return "", "", false
+	}
+	templateKey := TemplateKey(branchConfig.Template, option)
+	value, ok := c.CommandConfig[strings.ToLower(templateKey)]
+	return value, templateKey, ok
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Only the subset of TOML needed for exported documents is supported: tables
+// with bare or quoted names, and keys holding strings or booleans.
+
+// bareKey matches keys that need no quotes in TOML
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// encodeTOML writes a document as TOML
+func encodeTOML(doc *Document) []byte {
+	var buf bytes.Buffer
+	writeTOMLString(&buf, "version", doc.Version)
+	if doc.Remote != "" {
+		writeTOMLString(&buf, "remote", doc.Remote)
+	}
+
+	for _, name := range sortedKeys(doc.Branches) {
+		branch := doc.Branches[name]
+		fmt.Fprintf(&buf, "\n[branches.%s]\n", tomlKey(name))
+		writeTOMLString(&buf, "type", branch.Type)
+		for _, field := range []struct{ key, value string }{
+			{"parent", branch.Parent},
+			{"startPoint", branch.StartPoint},
+			{"upstreamStrategy", branch.UpstreamStrategy},
+			{"downstreamStrategy", branch.DownstreamStrategy},
+			{"prefix", branch.Prefix},
+			{"tagPrefix", branch.TagPrefix},
+			{"prefixAliases", branch.PrefixAliases},
+			{"conflictResolution", branch.ConflictResolution},
+			{"conflictResolutionPaths", branch.ConflictResolutionPaths},
+			{"template", branch.Template},
+		} {
+			if field.value != "" {
+				writeTOMLString(&buf, field.key, field.value)
+			}
+		}
+		if branch.AutoUpdate {
+			buf.WriteString("autoUpdate = true\n")
+		}
+		if branch.Tag {
+			buf.WriteString("tag = true\n")
+		}
+	}
+
+	if len(doc.Settings) > 0 {
+		buf.WriteString("\n[settings]\n")
+		for _, key := range sortedKeys(doc.Settings) {
+			writeTOMLString(&buf, key, doc.Settings[key])
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// writeTOMLString writes a key with a string value
+func writeTOMLString(buf *bytes.Buffer, key, value string) {
+	fmt.Fprintf(buf, "%s = %s\n", tomlKey(key), strconv.Quote(value))
+}
+
+// tomlKey quotes a key unless it is a valid bare key
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// decodeTOML parses TOML into nested tables
+func decodeTOML(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			path, rest, err := parseTOMLKeyPath(line[1:])
+			if err != nil || !strings.HasPrefix(rest, "]") || !isTOMLLineEnd(rest[1:]) {
+				return nil, fmt.Errorf("line %d: invalid table header", lineNumber)
+			}
+			current = root
+			for _, part := range path {
+				next, ok := current[part].(map[string]any)
+				if !ok {
+					if _, exists := current[part]; exists {
+						return nil, fmt.Errorf("line %d: '%s' is not a table", lineNumber, part)
+					}
+					next = make(map[string]any)
+					current[part] = next
+				}
+				current = next
+			}
+			continue
+		}
+
+		path, rest, err := parseTOMLKeyPath(line)
+		if err != nil || len(path) != 1 || !strings.HasPrefix(rest, "=") {
+			return nil, fmt.Errorf("line %d: expected 'key = value'", lineNumber)
+		}
+		value, rest, err := parseTOMLValue(strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if !isTOMLLineEnd(rest) {
+			return nil, fmt.Errorf("line %d: unexpected text after value", lineNumber)
+		}
+		if _, exists := current[path[0]]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key '%s'", lineNumber, path[0])
+		}
+		current[path[0]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// parseTOMLKeyPath parses a dotted key and returns its parts and the remaining text
+func parseTOMLKeyPath(s string) ([]string, string, error) {
+	var parts []string
+	for {
+		s = strings.TrimSpace(s)
+		var part string
+		switch {
+		case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+			value, rest, err := parseTOMLString(s)
+			if err != nil {
+				return nil, "", err
+			}
+			part, s = value, rest
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("missing key")
+			}
+			part, s = s[:end], s[end:]
+		}
+		parts = append(parts, part)
+
+		s = strings.TrimSpace(s)
+		if !strings.HasPrefix(s, ".") {
+			return parts, s, nil
+		}
+		s = s[1:]
+	}
+}
+
+// parseTOMLValue parses a string or boolean value and returns the remaining text
+func parseTOMLValue(s string) (any, string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return parseTOMLString(s)
+	case strings.HasPrefix(s, "true"):
+		return true, s[len("true"):], nil
+	case strings.HasPrefix(s, "false"):
+		return false, s[len("false"):], nil
+	}
+	return nil, "", fmt.Errorf("unsupported value (only strings and booleans are supported)")
+}
+
+// parseTOMLString parses a basic ("...") or literal ('...') string
+func parseTOMLString(s string) (string, string, error) {
+	if strings.HasPrefix(s, "'") {
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+	}
+
+	// Find the closing quote, skipping escaped characters
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid string %s", s[:i+1])
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+// isTOMLLineEnd reports whether only whitespace and a comment remain
+func isTOMLLineEnd(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
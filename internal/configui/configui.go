@@ -0,0 +1,611 @@
+// Package configui implements the terminal UI of 'git flow config ui' and
+// 'git flow init --interactive-ui'. It shows the branch hierarchy as a tree
+// whose branches expand into their settings, which are edited in place:
+// choices such as parents and merge strategies are cycled with the arrow
+// keys, prefixes are typed into the row. Changes stay in memory until the
+// user saves; writing them is left to the caller.
+package configui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+// Start is a configuration the editor can start from
+type Start struct {
+	Name   string
+	Config *config.Config
+}
+
+// Options configure the editor
+type Options struct {
+	// Starts, if set, are offered before the tree; the chosen one is edited
+	Starts []Start
+	// Validate checks the edited configuration before it is saved
+	Validate func(cfg *config.Config) error
+	// Preview returns the changes saving would make, shown for confirmation
+	Preview func(cfg *config.Config, removed []string) (string, error)
+}
+
+// Result is the outcome of an editing session
+type Result struct {
+	Config  *config.Config
+	Removed []string // branch sections to drop when saving
+	Saved   bool
+}
+
+// mode is what the keys currently act on
+type mode int
+
+const (
+	modeStart   mode = iota // choosing the configuration to start from
+	modeBrowse              // moving through the tree
+	modeEdit                // typing the value of a field in place
+	modeName                // typing the name of a new branch
+	modeConfirm             // answering a yes/no question
+)
+
+// Fields shown under an expanded branch
+const (
+	fieldParent             = "Parent"
+	fieldStartPoint         = "Start point"
+	fieldPrefix             = "Prefix"
+	fieldUpstreamStrategy   = "Upstream strategy"
+	fieldDownstreamStrategy = "Downstream strategy"
+	fieldAutoUpdate         = "Auto-update"
+	fieldTag                = "Create tags"
+	fieldTagPrefix          = "Tag prefix"
+)
+
+// strategies are the merge strategies the strategy fields cycle through
+var strategies = []string{
+	string(config.MergeStrategyMerge),
+	string(config.MergeStrategyRebase),
+	string(config.MergeStrategySquash),
+	string(config.MergeStrategyNone),
+}
+
+// Model is the state of the editor. It implements tea.Model.
+type Model struct {
+	options Options
+	cfg     *config.Config
+	removed []string
+	changed bool
+	saved   bool
+
+	mode     mode
+	expanded map[string]bool
+	cursor   int // selected row, or selected start in modeStart
+	offset   int // first row shown
+	height   int // terminal height, 0 until known
+
+	input    []rune         // text typed in modeEdit and modeName
+	newType  string         // type of the branch named in modeName
+	parent   string         // parent of the branch named in modeName
+	question string         // asked in modeConfirm
+	onYes    func() tea.Cmd // run when the question is answered with y
+	preview  string         // changes shown while confirming a save
+	message  string         // feedback on the last action
+}
+
+// New returns an editor for cfg, which it changes in place. With
+// options.Starts, cfg may be nil; the chosen start is edited instead.
+func New(cfg *config.Config, options Options) *Model {
+	m := &Model{options: options, cfg: cfg, expanded: make(map[string]bool)}
+	if len(options.Starts) > 0 {
+		m.mode = modeStart
+	} else {
+		m.mode = modeBrowse
+	}
+	return m
+}
+
+// Run shows the editor on the terminal until the user saves or quits
+func Run(cfg *config.Config, options Options) (Result, error) {
+	final, err := tea.NewProgram(New(cfg, options), tea.WithAltScreen()).Run()
+	if err != nil {
+		return Result{}, err
+	}
+	return final.(*Model).Result(), nil
+}
+
+// Result returns the edited configuration and whether the user saved it
+func (m *Model) Result() Result {
+	return Result{Config: m.cfg, Removed: m.removed, Saved: m.saved}
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		var cmd tea.Cmd
+		switch m.mode {
+		case modeStart:
+			cmd = m.updateStart(msg)
+		case modeBrowse:
+			m.message = ""
+			cmd = m.updateBrowse(msg)
+		case modeEdit, modeName:
+			cmd = m.updateInput(msg)
+		case modeConfirm:
+			cmd = m.updateConfirm(msg)
+		}
+		m.scroll()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateStart handles the keys of the start selection
+func (m *Model) updateStart(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.options.Starts)-1 {
+			m.cursor++
+		}
+	case "enter", " ":
+		m.cfg = m.options.Starts[m.cursor].Config
+		m.mode = modeBrowse
+		m.cursor = 0
+	case "q", "esc":
+		return tea.Quit
+	}
+	return nil
+}
+
+// updateBrowse handles the keys of the tree
+func (m *Model) updateBrowse(msg tea.KeyMsg) tea.Cmd {
+	rows := m.rows()
+	var selected row
+	if m.cursor < len(rows) {
+		selected = rows[m.cursor]
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(rows)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(rows) - 1
+	case "enter", " ":
+		switch {
+		case selected.branch == "":
+		case selected.field == "":
+			m.expanded[selected.branch] = !m.expanded[selected.branch]
+		case isTextField(selected.field):
+			m.startEdit(selected)
+		default:
+			m.cycle(selected, 1)
+		}
+	case "right", "l":
+		switch {
+		case selected.branch == "":
+		case selected.field == "":
+			m.expanded[selected.branch] = true
+		case isTextField(selected.field):
+			m.startEdit(selected)
+		default:
+			m.cycle(selected, 1)
+		}
+	case "left", "h":
+		switch {
+		case selected.branch == "":
+		case selected.field == "":
+			m.expanded[selected.branch] = false
+		case !isTextField(selected.field):
+			m.cycle(selected, -1)
+		}
+	case "a":
+		m.startName(string(config.BranchTypeTopic), selected.branch)
+	case "b":
+		m.startName(string(config.BranchTypeBase), selected.branch)
+	case "d", "delete":
+		if selected.branch != "" {
+			m.confirmDelete(selected.branch)
+		}
+	case "s":
+		return m.save()
+	case "q", "esc":
+		if !m.changed {
+			return tea.Quit
+		}
+		m.confirm("Discard unsaved changes?", func() tea.Cmd { return tea.Quit })
+	}
+	return nil
+}
+
+// updateInput handles the keys while a value or name is typed
+func (m *Model) updateInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyRunes:
+		m.input = append(m.input, msg.Runes...)
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyCtrlU:
+		m.input = nil
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+	case tea.KeyEnter:
+		text := strings.TrimSpace(string(m.input))
+		naming := m.mode == modeName
+		m.mode = modeBrowse
+		if naming {
+			m.add(text)
+		} else {
+			m.commitEdit(text)
+		}
+	}
+	return nil
+}
+
+// updateConfirm handles the answer to a yes/no question
+func (m *Model) updateConfirm(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		m.mode = modeBrowse
+		m.preview = ""
+		return m.onYes()
+	case "n", "N", "esc", "q":
+		m.mode = modeBrowse
+		m.preview = ""
+	}
+	return nil
+}
+
+// confirm asks a yes/no question and runs onYes if it is answered with y
+func (m *Model) confirm(question string, onYes func() tea.Cmd) {
+	m.mode = modeConfirm
+	m.question = question
+	m.onYes = onYes
+}
+
+// startEdit starts typing the value of a text field in place
+func (m *Model) startEdit(selected row) {
+	m.mode = modeEdit
+	m.input = []rune(value(m.cfg.Branches[selected.branch], selected.field))
+}
+
+// commitEdit sets the typed value of the selected text field
+func (m *Model) commitEdit(text string) {
+	selected := m.rows()[m.cursor]
+	if selected.field == fieldPrefix && text == "" {
+		m.message = "The prefix cannot be empty"
+		return
+	}
+	if text != value(m.cfg.Branches[selected.branch], selected.field) {
+		m.set(selected.branch, selected.field, text)
+	}
+}
+
+// cycle moves a choice field to the next or previous of its values
+func (m *Model) cycle(selected row, delta int) {
+	values := m.choices(selected.branch, selected.field)
+	if len(values) == 0 {
+		return
+	}
+	current := value(m.cfg.Branches[selected.branch], selected.field)
+	index := -1
+	for i, v := range values {
+		if v == current {
+			index = i
+		}
+	}
+	if index == -1 && delta < 0 {
+		index = 0
+	}
+	next := values[(index+delta+len(values))%len(values)]
+	if next == current {
+		return
+	}
+	m.set(selected.branch, selected.field, next)
+	// The branch moves in the tree when its parent changes
+	m.selectRow(selected.branch, selected.field)
+}
+
+// choices returns the values a choice field cycles through
+func (m *Model) choices(name, field string) []string {
+	switch field {
+	case fieldParent:
+		return m.parentCandidates(name)
+	case fieldStartPoint:
+		return m.baseBranches("")
+	case fieldUpstreamStrategy, fieldDownstreamStrategy:
+		return strategies
+	case fieldAutoUpdate, fieldTag:
+		return []string{"no", "yes"}
+	}
+	return nil
+}
+
+// set changes a field of a branch
+func (m *Model) set(name, field, v string) {
+	branch := m.cfg.Branches[name]
+	switch field {
+	case fieldParent:
+		// Topic types that start from their parent keep doing so
+		if branch.StartPoint == branch.Parent {
+			branch.StartPoint = v
+		}
+		branch.Parent = v
+		if v == "" {
+			branch.UpstreamStrategy = string(config.MergeStrategyNone)
+			branch.DownstreamStrategy = string(config.MergeStrategyNone)
+		}
+	case fieldStartPoint:
+		branch.StartPoint = v
+	case fieldPrefix:
+		branch.Prefix = v
+	case fieldTagPrefix:
+		branch.TagPrefix = v
+	case fieldUpstreamStrategy:
+		branch.UpstreamStrategy = v
+	case fieldDownstreamStrategy:
+		branch.DownstreamStrategy = v
+	case fieldAutoUpdate:
+		branch.AutoUpdate = v == "yes"
+	case fieldTag:
+		branch.Tag = v == "yes"
+	}
+	m.cfg.Branches[name] = branch
+	m.changed = true
+}
+
+// parentCandidates returns the base branches name can be moved under without
+// creating a cycle, and the top level for a base branch
+func (m *Model) parentCandidates(name string) []string {
+	var candidates []string
+	for _, base := range m.baseBranches(name) {
+		if !m.descends(base, name) {
+			candidates = append(candidates, base)
+		}
+	}
+	if m.cfg.Branches[name].Type == string(config.BranchTypeBase) {
+		candidates = append(candidates, "")
+	}
+	return candidates
+}
+
+// descends reports whether ancestor is branch or one of its parents
+func (m *Model) descends(branch, ancestor string) bool {
+	seen := make(map[string]bool)
+	for current := branch; current != "" && !seen[current]; current = m.cfg.Branches[current].Parent {
+		if current == ancestor {
+			return true
+		}
+		seen[current] = true
+	}
+	return false
+}
+
+// baseBranches returns the configured base branches except exclude, sorted
+func (m *Model) baseBranches(exclude string) []string {
+	var names []string
+	for name, branch := range m.cfg.Branches {
+		if branch.Type == string(config.BranchTypeBase) && name != exclude {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// startName starts typing the name of a new branch of branchType. It is
+// added under the selected base branch, or the parent of the selected topic
+// branch type.
+func (m *Model) startName(branchType, selected string) {
+	parent := selected
+	if branch, ok := m.cfg.Branches[selected]; ok && branch.Type != string(config.BranchTypeBase) {
+		parent = branch.Parent
+	}
+	if branchType == string(config.BranchTypeTopic) && parent == "" {
+		m.message = "Select the base branch to add the topic branch type to"
+		return
+	}
+	m.mode = modeName
+	m.newType = branchType
+	m.parent = parent
+	m.input = nil
+}
+
+// add adds the named branch, selects it and, for a topic branch type, starts
+// editing its prefix
+func (m *Model) add(name string) {
+	branchType := m.newType
+	if name == "" {
+		return
+	}
+	if err := util.ValidateBranchName(name); err != nil {
+		m.message = fmt.Sprintf("Invalid name: %v", err)
+		return
+	}
+	if _, exists := m.cfg.Branches[name]; exists {
+		m.message = fmt.Sprintf("'%s' is already configured", name)
+		return
+	}
+
+	branch := config.BranchConfig{
+		Type:               branchType,
+		Parent:             m.parent,
+		UpstreamStrategy:   string(config.MergeStrategyMerge),
+		DownstreamStrategy: string(config.MergeStrategyMerge),
+	}
+	if branchType == string(config.BranchTypeBase) && m.parent == "" {
+		branch.UpstreamStrategy = string(config.MergeStrategyNone)
+		branch.DownstreamStrategy = string(config.MergeStrategyNone)
+	}
+	if branchType == string(config.BranchTypeTopic) {
+		branch.StartPoint = m.parent
+		branch.Prefix = name + "/"
+	}
+	m.cfg.Branches[name] = branch
+	m.changed = true
+	m.expanded[name] = true
+	m.message = fmt.Sprintf("Added %s '%s'", branchType, name)
+
+	if branchType == string(config.BranchTypeTopic) {
+		m.selectRow(name, fieldPrefix)
+		m.startEdit(row{branch: name, field: fieldPrefix})
+	} else {
+		m.selectRow(name, "")
+	}
+}
+
+// confirmDelete asks whether to remove a branch from the configuration,
+// unless other branches depend on it
+func (m *Model) confirmDelete(name string) {
+	for _, other := range sortedNames(m.cfg) {
+		branch := m.cfg.Branches[other]
+		if branch.Parent == name || branch.StartPoint == name {
+			m.message = fmt.Sprintf("Cannot delete '%s': '%s' depends on it", name, other)
+			return
+		}
+	}
+	m.confirm(fmt.Sprintf("Delete '%s' from the configuration?", name), func() tea.Cmd {
+		delete(m.cfg.Branches, name)
+		delete(m.expanded, name)
+		m.removed = append(m.removed, name)
+		m.changed = true
+		m.message = fmt.Sprintf("Deleted '%s' (the Git branch is kept)", name)
+		if rows := m.rows(); m.cursor >= len(rows) {
+			m.cursor = len(rows) - 1
+		}
+		return nil
+	})
+}
+
+// save validates the configuration, shows the changes and asks for
+// confirmation
+func (m *Model) save() tea.Cmd {
+	if m.options.Validate != nil {
+		if err := m.options.Validate(m.cfg); err != nil {
+			m.message = fmt.Sprintf("Cannot save: %v", err)
+			return nil
+		}
+	}
+	if m.options.Preview != nil {
+		preview, err := m.options.Preview(m.cfg, m.removed)
+		if err != nil {
+			m.message = fmt.Sprintf("Cannot save: %v", err)
+			return nil
+		}
+		if preview == "" {
+			preview = "No configuration changes\n"
+		}
+		m.preview = preview
+	}
+	m.confirm("Save this configuration?", func() tea.Cmd {
+		m.saved = true
+		return tea.Quit
+	})
+	return nil
+}
+
+// selectRow moves the cursor to the row of a branch or one of its fields
+func (m *Model) selectRow(branch, field string) {
+	for i, r := range m.rows() {
+		if r.branch == branch && r.field == field {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// scroll keeps the cursor within the rows shown
+func (m *Model) scroll() {
+	visible := m.visibleRows()
+	if visible <= 0 {
+		m.offset = 0
+		return
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+// fields returns the fields shown under an expanded branch
+func fields(branch config.BranchConfig) []string {
+	if branch.Type == string(config.BranchTypeBase) {
+		return []string{fieldParent, fieldUpstreamStrategy, fieldDownstreamStrategy, fieldAutoUpdate}
+	}
+	return []string{fieldParent, fieldStartPoint, fieldPrefix, fieldUpstreamStrategy, fieldDownstreamStrategy, fieldTag, fieldTagPrefix}
+}
+
+// isTextField reports whether a field is typed rather than chosen
+func isTextField(field string) bool {
+	return field == fieldPrefix || field == fieldTagPrefix
+}
+
+// value returns the current value of a field; booleans are "yes" or "no"
+func value(branch config.BranchConfig, field string) string {
+	switch field {
+	case fieldParent:
+		return branch.Parent
+	case fieldStartPoint:
+		if branch.StartPoint == "" {
+			return branch.Parent
+		}
+		return branch.StartPoint
+	case fieldPrefix:
+		return branch.Prefix
+	case fieldUpstreamStrategy:
+		return branch.UpstreamStrategy
+	case fieldDownstreamStrategy:
+		return branch.DownstreamStrategy
+	case fieldAutoUpdate:
+		return formatYesNo(branch.AutoUpdate)
+	case fieldTag:
+		return formatYesNo(branch.Tag)
+	case fieldTagPrefix:
+		return branch.TagPrefix
+	}
+	return ""
+}
+
+// formatYesNo formats a boolean for display
+func formatYesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// sortedNames returns the configured branch names, sorted
+func sortedNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Branches))
+	for name := range cfg.Branches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,236 @@
+package configui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gittower/git-flow-next/internal/config"
+)
+
+// row is a line of the tree: a branch, or a field of an expanded branch
+type row struct {
+	branch string
+	field  string // empty for the branch itself
+	indent string // the tree lines drawn before the row
+	detail string // note shown after a branch
+}
+
+var (
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	helpStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+// chrome is the number of lines around the rows: the title and the blank
+// lines, the message and up to two lines of input and help
+const chrome = 6
+
+// View implements tea.Model
+func (m *Model) View() string {
+	var b strings.Builder
+	if m.mode == modeStart {
+		b.WriteString("Start from\n\n")
+		for i, start := range m.options.Starts {
+			b.WriteString(m.line(i == m.cursor, start.Name))
+		}
+		b.WriteString("\n" + helpStyle.Render("↑/↓ move  enter choose  q quit") + "\n")
+		return b.String()
+	}
+
+	if m.preview != "" {
+		b.WriteString(m.preview)
+		b.WriteString("\n" + m.question + " (y/n)\n")
+		return b.String()
+	}
+
+	b.WriteString("Branch hierarchy\n\n")
+	rows := m.rows()
+	end := len(rows)
+	if visible := m.visibleRows(); visible > 0 && m.offset+visible < end {
+		end = m.offset + visible
+	}
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.line(i == m.cursor, m.label(rows[i], i == m.cursor)))
+	}
+
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.message + "\n")
+	}
+	switch m.mode {
+	case modeName:
+		under := ""
+		if m.parent != "" {
+			under = fmt.Sprintf(" under '%s'", m.parent)
+		}
+		fmt.Fprintf(&b, "New %s branch%s: %s█\n", m.newType, under, string(m.input))
+		b.WriteString(helpStyle.Render("enter add  esc cancel") + "\n")
+	case modeEdit:
+		b.WriteString(helpStyle.Render("enter set  esc cancel  ctrl+u clear") + "\n")
+	case modeConfirm:
+		b.WriteString(m.question + " (y/n)\n")
+	default:
+		b.WriteString(helpStyle.Render("↑/↓ move  enter expand/edit  ←/→ change  a add topic type  b add base branch  d delete  s save  q quit") + "\n")
+	}
+	return b.String()
+}
+
+// line formats one line of a list, marking the selected one
+func (m *Model) line(selected bool, text string) string {
+	if selected {
+		return "> " + selectedStyle.Render(text) + "\n"
+	}
+	return "  " + text + "\n"
+}
+
+// label formats a row of the tree; the selected field shows the text being
+// typed into it
+func (m *Model) label(r row, selected bool) string {
+	if r.field == "" {
+		return r.indent + describe(r.branch, m.cfg.Branches[r.branch]) + r.detail
+	}
+	v := value(m.cfg.Branches[r.branch], r.field)
+	if m.mode == modeEdit && selected {
+		v = string(m.input) + "█"
+	} else if v == "" {
+		v = "(none)"
+	}
+	return fmt.Sprintf("%s%-20s %s", r.indent, r.field+":", v)
+}
+
+// visibleRows returns how many rows fit on the terminal, or 0 if its size
+// is not known
+func (m *Model) visibleRows() int {
+	if m.height == 0 {
+		return 0
+	}
+	if visible := m.height - chrome; visible > 0 {
+		return visible
+	}
+	return 1
+}
+
+// rows returns the lines of the tree: the branches in hierarchy order, each
+// expanded one followed by its fields
+func (m *Model) rows() []row {
+	if m.cfg == nil {
+		return nil
+	}
+	var rows []row
+	for _, r := range treeRows(m.cfg) {
+		rows = append(rows, r.row)
+		if !m.expanded[r.branch] {
+			continue
+		}
+		indent := r.next + "    "
+		if r.hasChildren {
+			indent = r.next + "│   "
+		}
+		for _, field := range fields(m.cfg.Branches[r.branch]) {
+			rows = append(rows, row{branch: r.branch, field: field, indent: indent})
+		}
+	}
+	return rows
+}
+
+// treeRow is a branch of the tree with the lines drawn below it
+type treeRow struct {
+	row
+	next        string // the tree lines drawn before the rows below the branch
+	hasChildren bool
+}
+
+// treeRows returns the branches of cfg in hierarchy order with the lines
+// that connect them. Branches whose parent is not configured come last.
+func treeRows(cfg *config.Config) []treeRow {
+	var rows []treeRow
+	visited := make(map[string]bool)
+	var walk func(parent, indent string)
+	walk = func(parent, indent string) {
+		children := childBranches(cfg, parent)
+		for i, name := range children {
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			connector, next := "├── ", "│   "
+			if i == len(children)-1 {
+				connector, next = "└── ", "    "
+			}
+			if parent == "" {
+				connector, next = "", ""
+			}
+			rows = append(rows, treeRow{
+				row:         row{branch: name, indent: indent + connector},
+				next:        indent + next,
+				hasChildren: len(childBranches(cfg, name)) > 0,
+			})
+			walk(name, indent+next)
+		}
+	}
+	walk("", "")
+
+	for _, name := range sortedNames(cfg) {
+		if !visited[name] {
+			rows = append(rows, treeRow{row: row{
+				branch: name,
+				detail: fmt.Sprintf(" (parent '%s' is not configured)", cfg.Branches[name].Parent),
+			}})
+		}
+	}
+	return rows
+}
+
+// childBranches returns the branches with the given parent, base branches first.
+// Only base branches appear at the top level.
+func childBranches(cfg *config.Config, parent string) []string {
+	var bases, topics []string
+	for _, name := range sortedNames(cfg) {
+		branch := cfg.Branches[name]
+		if branch.Parent != parent {
+			continue
+		}
+		if branch.Type == string(config.BranchTypeBase) {
+			bases = append(bases, name)
+		} else if parent != "" {
+			topics = append(topics, name)
+		}
+	}
+	return append(bases, topics...)
+}
+
+// describe summarizes a branch on a single line of the tree
+func describe(name string, branch config.BranchConfig) string {
+	details := []string{
+		"upstream " + branch.UpstreamStrategy,
+		"downstream " + branch.DownstreamStrategy,
+	}
+	if branch.Type == string(config.BranchTypeBase) {
+		if branch.AutoUpdate {
+			details = append(details, "auto-update")
+		}
+		return fmt.Sprintf("%s  [base]  %s", name, strings.Join(details, ", "))
+	}
+
+	details = append([]string{"prefix " + branch.Prefix}, details...)
+	if branch.StartPoint != "" && branch.StartPoint != branch.Parent {
+		details = append(details, "start point "+branch.StartPoint)
+	}
+	if branch.Tag {
+		details = append(details, "tags")
+	}
+	if branch.TagPrefix != "" {
+		details = append(details, "tag prefix "+branch.TagPrefix)
+	}
+	return fmt.Sprintf("%s  [topic]  %s", name, strings.Join(details, ", "))
+}
+
+// FormatTree returns the branch hierarchy of cfg as the editor shows it, one
+// branch per line
+func FormatTree(cfg *config.Config) string {
+	var b strings.Builder
+	for _, r := range treeRows(cfg) {
+		b.WriteString(r.indent + describe(r.branch, cfg.Branches[r.branch]) + r.detail + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,246 @@
+// Package dashboard collects the state of a git-flow repository, the branch
+// hierarchy, the open topic branches and the operations in progress, and
+// serves it as a small local web page for 'git flow web'.
+package dashboard
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// Snapshot is the state of the repository shown on the dashboard
+type Snapshot struct {
+	Repository      string        `json:"repository"`
+	CurrentBranch   string        `json:"currentBranch"`
+	Bases           []BaseBranch  `json:"bases"`
+	TopicTypes      []TopicType   `json:"topicTypes"`
+	Topics          []TopicBranch `json:"topics"`
+	Operation       *Operation    `json:"operation,omitempty"`
+	OtherOperations []Operation   `json:"otherOperations,omitempty"`
+	Collected       time.Time     `json:"collected"`
+}
+
+// BaseBranch is a base branch in the hierarchy. Bases are listed parents
+// first, each followed by its children; Depth is the distance to the root.
+type BaseBranch struct {
+	Name       string     `json:"name"`
+	Parent     string     `json:"parent,omitempty"`
+	Depth      int        `json:"depth"`
+	AutoUpdate bool       `json:"autoUpdate"`
+	Exists     bool       `json:"exists"`
+	Ahead      int        `json:"ahead"`  // commits not in the parent
+	Behind     int        `json:"behind"` // commits of the parent not merged in yet
+	Remote     RemoteSync `json:"remote"`
+}
+
+// TopicType is a configured topic branch type
+type TopicType struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	Parent string `json:"parent"`
+	Tag    bool   `json:"tag"`
+}
+
+// TopicBranch is an open topic branch, compared with the base it was started
+// from, or the parent of its type
+type TopicBranch struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Base     string     `json:"base"`
+	Ahead    int        `json:"ahead"`  // commits not in the base
+	Behind   int        `json:"behind"` // commits of the base not in the branch
+	Current  bool       `json:"current"`
+	Worktree string     `json:"worktree,omitempty"` // set when checked out in another worktree
+	Remote   RemoteSync `json:"remote"`
+}
+
+// RemoteSync is the state of a local branch relative to its remote-tracking
+// branch, as reported by git.CompareBranchWithRemote
+type RemoteSync struct {
+	Status git.BranchSyncStatus `json:"status"`
+	Count  int                  `json:"count,omitempty"`
+}
+
+// Operation is a finish, update or sync-bases waiting to be continued
+type Operation struct {
+	Action   string   `json:"action"`
+	Branch   string   `json:"branch"`
+	Target   string   `json:"target"`
+	Step     string   `json:"step"`
+	Children []string `json:"children,omitempty"`
+	Updated  []string `json:"updated,omitempty"`
+	Worktree string   `json:"worktree,omitempty"` // set for operations in another worktree
+}
+
+// Collect reads the current state of the repository for cfg
+func Collect(cfg *config.Config) (*Snapshot, error) {
+	snapshot := &Snapshot{Collected: time.Now()}
+	if root, err := git.GetTopLevelDir(); err == nil {
+		snapshot.Repository = filepath.Base(root)
+	}
+	current, err := git.GetCurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.CurrentBranch = current
+
+	branches, err := git.ListBranches()
+	if err != nil {
+		return nil, err
+	}
+	local := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		local[branch] = true
+	}
+
+	snapshot.Bases = collectBases(cfg, local)
+	snapshot.TopicTypes = collectTopicTypes(cfg)
+	snapshot.Topics = collectTopics(cfg, branches, current)
+
+	if state, err := mergestate.LoadMergeState(); err == nil && state != nil {
+		snapshot.Operation = operation(state, "")
+	}
+	if states, err := mergestate.OtherWorktreeStates(); err == nil {
+		for _, other := range states {
+			snapshot.OtherOperations = append(snapshot.OtherOperations, *operation(other.State, other.Worktree))
+		}
+	}
+	return snapshot, nil
+}
+
+// collectBases walks the base branch hierarchy depth-first from its roots,
+// in alphabetical order on each level
+func collectBases(cfg *config.Config, local map[string]bool) []BaseBranch {
+	children := make(map[string][]string)
+	for name, branch := range cfg.Branches {
+		if branch.Type == string(config.BranchTypeBase) {
+			children[branch.Parent] = append(children[branch.Parent], name)
+		}
+	}
+	for _, names := range children {
+		sort.Strings(names)
+	}
+
+	var bases []BaseBranch
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		for _, name := range children[parent] {
+			branch := cfg.Branches[name]
+			base := BaseBranch{
+				Name:       name,
+				Parent:     branch.Parent,
+				Depth:      depth,
+				AutoUpdate: branch.AutoUpdate,
+				Exists:     local[name],
+			}
+			if base.Exists {
+				if base.Parent != "" && local[base.Parent] {
+					base.Ahead, base.Behind, _ = git.AheadBehind(name, base.Parent)
+				}
+				base.Remote = remoteSync(name)
+			}
+			bases = append(bases, base)
+			walk(name, depth+1)
+		}
+	}
+	walk("", 0)
+	return bases
+}
+
+func collectTopicTypes(cfg *config.Config) []TopicType {
+	var types []TopicType
+	for name, branch := range cfg.Branches {
+		if branch.Type == string(config.BranchTypeTopic) {
+			types = append(types, TopicType{Name: name, Prefix: branch.Prefix, Parent: branch.Parent, Tag: branch.Tag})
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+func collectTopics(cfg *config.Config, branches []string, current string) []TopicBranch {
+	otherWorktrees := make(map[string]string)
+	if worktrees, err := git.ListWorktrees(); err == nil {
+		for _, worktree := range worktrees {
+			if !worktree.Current && worktree.Branch != "" {
+				otherWorktrees[worktree.Branch] = worktree.Path
+			}
+		}
+	}
+
+	var topics []TopicBranch
+	for _, branch := range branches {
+		branchType := config.TopicBranchType(cfg, branch)
+		if branchType == "" {
+			continue
+		}
+		topic := TopicBranch{
+			Name:     branch,
+			Type:     branchType,
+			Base:     cfg.Branches[branchType].Parent,
+			Current:  branch == current,
+			Worktree: otherWorktrees[branch],
+			Remote:   remoteSync(branch),
+		}
+		if stored, err := git.GetBaseBranch(branch); err == nil && stored != "" {
+			topic.Base = stored
+		}
+		if topic.Base != "" {
+			topic.Ahead, topic.Behind, _ = git.AheadBehind(branch, topic.Base)
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func remoteSync(branch string) RemoteSync {
+	status, count, err := git.CompareBranchWithRemote(branch)
+	if err != nil {
+		return RemoteSync{Status: git.SyncStatusNoTracking}
+	}
+	return RemoteSync{Status: status, Count: count}
+}
+
+func operation(state *mergestate.MergeState, worktree string) *Operation {
+	return &Operation{
+		Action:   state.Action,
+		Branch:   state.FullBranchName,
+		Target:   state.ParentBranch,
+		Step:     state.CurrentStep,
+		Children: state.ChildBranches,
+		Updated:  state.UpdatedBranches,
+		Worktree: worktree,
+	}
+}
+
+// TopicsOn returns the open topic branches based on base
+func (s *Snapshot) TopicsOn(base string) []TopicBranch {
+	var topics []TopicBranch
+	for _, topic := range s.Topics {
+		if topic.Base == base {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// DetachedTopics returns the open topic branches whose base is not a base
+// branch, such as a feature started from another feature
+func (s *Snapshot) DetachedTopics() []TopicBranch {
+	bases := make(map[string]bool, len(s.Bases))
+	for _, base := range s.Bases {
+		bases[base.Name] = true
+	}
+	var topics []TopicBranch
+	for _, topic := range s.Topics {
+		if !bases[topic.Base] {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
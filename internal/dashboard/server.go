@@ -0,0 +1,53 @@
+package dashboard
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// RefreshSeconds is how often the page reloads itself
+const RefreshSeconds = 5
+
+//go:embed page.html
+var pageSource string
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{
+	"indent": func(depth int) string { return strings.Repeat("    ", depth) },
+}).Parse(pageSource))
+
+// Handler serves the dashboard: the page at / and the snapshot as JSON at
+// /api/snapshot. Each request collects a new snapshot.
+func Handler(collect func() (*Snapshot, error)) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		snapshot, err := collect()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page.Execute(w, struct {
+			*Snapshot
+			Refresh int
+		}{snapshot, RefreshSeconds})
+	})
+	mux.HandleFunc("/api/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		snapshot, err := collect()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(snapshot)
+	})
+	return mux
+}
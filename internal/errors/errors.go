@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ExitCode represents the process exit code
 type ExitCode int
@@ -20,6 +23,26 @@ const (
 	ExitCodeBranchNotFound ExitCode = 5
 	// ExitCodeValidationError indicates a validation error
 	ExitCodeValidationError ExitCode = 6
+	// ExitCodeUpdated indicates sync-bases updated base branches, or found
+	// base branches to update with --check
+	ExitCodeUpdated ExitCode = 7
+	// ExitCodeConflict indicates the operation stopped on conflicts that need
+	// to be resolved by hand
+	ExitCodeConflict ExitCode = 8
+	// ExitCodeCheckPrefix indicates 'git flow check' found a branch that is
+	// neither a base branch nor starts with a topic branch prefix
+	ExitCodeCheckPrefix ExitCode = 9
+	// ExitCodeCheckName indicates 'git flow check' found a branch name that does
+	// not match gitflow.check.namePattern
+	ExitCodeCheckName ExitCode = 10
+	// ExitCodeCheckParent indicates 'git flow check' found a branch not started
+	// from its configured parent
+	ExitCodeCheckParent ExitCode = 11
+	// ExitCodeCheckBehind indicates 'git flow check' found a branch too far
+	// behind its parent
+	ExitCodeCheckBehind ExitCode = 12
+	// ExitCodeInterrupted indicates the operation was stopped by SIGINT or SIGTERM
+	ExitCodeInterrupted ExitCode = 130
 )
 
 // Error is the base interface for all git-flow errors
@@ -37,13 +60,21 @@ type ExitCoder interface {
 type NotInitializedError struct{}
 
 func (e *NotInitializedError) Error() string {
-	return "git flow is not initialized (run 'git flow init' first)"
+	return fmt.Sprintf("git flow is not initialized (%s)", e.Hint())
+}
+
+func (e *NotInitializedError) Hint() string {
+	return "run 'git flow init' first"
 }
 
 func (e *NotInitializedError) ExitCode() ExitCode {
 	return ExitCodeNotInitialized
 }
 
+func (e *NotInitializedError) Code() string {
+	return "not_initialized"
+}
+
 // EmptyBranchNameError indicates that a branch name was not provided
 type EmptyBranchNameError struct{}
 
@@ -55,6 +86,27 @@ func (e *EmptyBranchNameError) ExitCode() ExitCode {
 	return ExitCodeInvalidInput
 }
 
+func (e *EmptyBranchNameError) Code() string {
+	return "empty_branch_name"
+}
+
+// EmptyMessageError indicates a message edited with --edit was left empty
+type EmptyMessageError struct {
+	Kind string // e.g. "tag" or "merge commit"
+}
+
+func (e *EmptyMessageError) Error() string {
+	return fmt.Sprintf("aborting due to empty %s message", e.Kind)
+}
+
+func (e *EmptyMessageError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+func (e *EmptyMessageError) Code() string {
+	return "empty_message"
+}
+
 // InvalidBranchTypeError indicates an unknown branch type
 type InvalidBranchTypeError struct {
 	BranchType string
@@ -64,10 +116,43 @@ func (e *InvalidBranchTypeError) Error() string {
 	return fmt.Sprintf("unknown branch type: %s", e.BranchType)
 }
 
+func (e *InvalidBranchTypeError) Hint() string {
+	return "run 'git flow config list' to see the configured branch types"
+}
+
 func (e *InvalidBranchTypeError) ExitCode() ExitCode {
 	return ExitCodeInvalidInput
 }
 
+func (e *InvalidBranchTypeError) Code() string {
+	return "invalid_branch_type"
+}
+
+// TopicTypeMismatchError indicates a branch name given for one topic branch
+// type that starts with the prefix of another type
+type TopicTypeMismatchError struct {
+	Name       string
+	BranchType string // the type of the command
+	OtherType  string // the type whose prefix the name starts with
+	ShortName  string // the name without the other type's prefix
+}
+
+func (e *TopicTypeMismatchError) Error() string {
+	return fmt.Sprintf("'%s' is a %s branch, not a %s branch", e.Name, e.OtherType, e.BranchType)
+}
+
+func (e *TopicTypeMismatchError) Hint() string {
+	return fmt.Sprintf("use 'git flow %s' with the name '%s' for it", e.OtherType, e.ShortName)
+}
+
+func (e *TopicTypeMismatchError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+func (e *TopicTypeMismatchError) Code() string {
+	return "topic_type_mismatch"
+}
+
 // BranchExistsError indicates a branch already exists
 type BranchExistsError struct {
 	BranchName string
@@ -81,6 +166,168 @@ func (e *BranchExistsError) ExitCode() ExitCode {
 	return ExitCodeBranchExists
 }
 
+func (e *BranchExistsError) Code() string {
+	return "branch_exists"
+}
+
+// DuplicateTopicNameError indicates a topic branch of another type already
+// uses the short name, with gitflow.uniqueTopicNames enabled
+type DuplicateTopicNameError struct {
+	Name           string
+	ExistingBranch string
+}
+
+func (e *DuplicateTopicNameError) Error() string {
+	return fmt.Sprintf("topic name '%s' is already used by branch '%s' (%s)", e.Name, e.ExistingBranch, e.Hint())
+}
+
+func (e *DuplicateTopicNameError) Hint() string {
+	return "gitflow.uniqueTopicNames is enabled; choose another name"
+}
+
+func (e *DuplicateTopicNameError) ExitCode() ExitCode {
+	return ExitCodeBranchExists
+}
+
+func (e *DuplicateTopicNameError) Code() string {
+	return "duplicate_topic_name"
+}
+
+// TagConflictError indicates a new branch collides with an existing ref: the
+// tag finish would create exists, or a branch or tag shares its name
+type TagConflictError struct {
+	BranchName string
+	TagName    string
+	Reason     string
+}
+
+func (e *TagConflictError) Error() string {
+	return fmt.Sprintf("cannot start '%s': %s (%s)", e.BranchName, e.Reason, e.Hint())
+}
+
+func (e *TagConflictError) Hint() string {
+	return fmt.Sprintf("finish would create tag '%s'; choose another name or remove the conflicting ref", e.TagName)
+}
+
+func (e *TagConflictError) ExitCode() ExitCode {
+	return ExitCodeBranchExists
+}
+
+func (e *TagConflictError) Code() string {
+	return "tag_conflict"
+}
+
+// TagExistsError indicates the tag finish would create already exists
+type TagExistsError struct {
+	TagName    string
+	BranchType string
+	BranchName string
+	Continue   bool // The finish stopped at the tag and is resumed with --continue
+}
+
+func (e *TagExistsError) Error() string {
+	return fmt.Sprintf("tag '%s' already exists.\n\n%s", e.TagName, e.Hint())
+}
+
+func (e *TagExistsError) Hint() string {
+	shortName := shortBranchName(e.BranchName)
+	command := fmt.Sprintf("git flow %s finish", e.BranchType)
+	if e.Continue {
+		command += " --continue"
+	}
+
+	return fmt.Sprintf(`To move it to the finished commit:
+  %s --retag %s
+
+To keep it and finish without creating a tag:
+  %s --skip-tag %s`,
+		command, shortName,
+		command, shortName)
+}
+
+func (e *TagExistsError) ExitCode() ExitCode {
+	return ExitCodeBranchExists
+}
+
+func (e *TagExistsError) Code() string {
+	return "tag_exists"
+}
+
+// UnexpectedBranchError indicates another branch was checked out while a
+// finish was stopped on a conflict, and HEAD can't be switched back safely
+type UnexpectedBranchError struct {
+	Expected   string
+	Current    string // Empty when HEAD is detached
+	BranchType string
+	BranchName string
+}
+
+func (e *UnexpectedBranchError) Error() string {
+	current := fmt.Sprintf("'%s' is checked out", e.Current)
+	if e.Current == "" {
+		current = "HEAD is detached"
+	}
+	return fmt.Sprintf("%s, but the finish of '%s' stopped on a conflict on '%s'.\n\n%s", current, e.BranchName, e.Expected, e.Hint())
+}
+
+func (e *UnexpectedBranchError) Hint() string {
+	return fmt.Sprintf(`Commit, stash or discard the changes in the working tree, then run:
+  git checkout %s
+  git flow %s finish --continue
+
+Or start the finish over with:
+  git flow %s finish --abort`,
+		e.Expected, e.BranchType, e.BranchType)
+}
+
+func (e *UnexpectedBranchError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *UnexpectedBranchError) Code() string {
+	return "unexpected_branch"
+}
+
+// UntrackedFilesError indicates untracked files would be overwritten by
+// checking out a child base branch to update it
+type UntrackedFilesError struct {
+	ChildBranch string
+	Files       []string
+	BranchType  string
+	BranchName  string
+	Continue    bool // The finish stopped before the child update and is resumed with --continue
+}
+
+func (e *UntrackedFilesError) Error() string {
+	return fmt.Sprintf("untracked files would be overwritten by checking out '%s' to update it:\n  %s\n\n%s",
+		e.ChildBranch, strings.Join(e.Files, "\n  "), e.Hint())
+}
+
+func (e *UntrackedFilesError) Hint() string {
+	command := fmt.Sprintf("git flow %s finish", e.BranchType)
+	shortName := " " + shortBranchName(e.BranchName)
+	if e.Continue {
+		command += " --continue"
+		shortName = ""
+	}
+
+	return fmt.Sprintf(`To stash them during the child updates and restore them afterwards:
+  %s --autostash-untracked%s
+
+Or move them out of the way and run:
+  %s%s`,
+		command, shortName,
+		command, shortName)
+}
+
+func (e *UntrackedFilesError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *UntrackedFilesError) Code() string {
+	return "untracked_files"
+}
+
 // BranchNotFoundError indicates a required branch does not exist
 type BranchNotFoundError struct {
 	BranchName string
@@ -94,6 +341,10 @@ func (e *BranchNotFoundError) ExitCode() ExitCode {
 	return ExitCodeBranchNotFound
 }
 
+func (e *BranchNotFoundError) Code() string {
+	return "branch_not_found"
+}
+
 // LocalBranchNotFoundError indicates a local branch does not exist
 type LocalBranchNotFoundError struct {
 	BranchName string
@@ -107,20 +358,52 @@ func (e *LocalBranchNotFoundError) ExitCode() ExitCode {
 	return ExitCodeBranchNotFound
 }
 
-// RemoteBranchExistsError indicates a branch already exists on the remote
+func (e *LocalBranchNotFoundError) Code() string {
+	return "local_branch_not_found"
+}
+
+// RemoteBranchExistsError indicates a branch already exists on the remote with
+// commits the local branch does not contain
 type RemoteBranchExistsError struct {
 	Remote     string
 	BranchName string
+	Compared   bool // whether Ahead and Behind were determined
+	Ahead      int  // local commits missing on the remote branch
+	Behind     int  // remote commits missing in the local branch
 }
 
 func (e *RemoteBranchExistsError) Error() string {
-	return fmt.Sprintf("branch '%s' already exists on remote '%s'", e.BranchName, e.Remote)
+	msg := fmt.Sprintf("branch '%s' already exists on remote '%s'", e.BranchName, e.Remote)
+	if !e.Compared {
+		return msg
+	}
+	switch {
+	case e.Ahead == 0:
+		return msg + fmt.Sprintf(" and has %d commit(s) the local branch does not have.\n%s", e.Behind, e.Hint())
+	default:
+		return msg + fmt.Sprintf(" and has diverged from the local branch (%d local and %d remote commit(s) differ).\n%s", e.Ahead, e.Behind, e.Hint())
+	}
+}
+
+func (e *RemoteBranchExistsError) Hint() string {
+	switch {
+	case !e.Compared:
+		return "Use --track-instead to track the remote branch"
+	case e.Ahead == 0:
+		return "Use --track-instead to track the remote branch, then pull its changes"
+	default:
+		return "Use --track-instead to track the remote branch and integrate its changes, or --force-with-lease to overwrite it with the local branch"
+	}
 }
 
 func (e *RemoteBranchExistsError) ExitCode() ExitCode {
 	return ExitCodeBranchExists
 }
 
+func (e *RemoteBranchExistsError) Code() string {
+	return "remote_branch_exists"
+}
+
 // GitError indicates a Git operation failed
 type GitError struct {
 	Operation string
@@ -135,6 +418,15 @@ func (e *GitError) ExitCode() ExitCode {
 	return ExitCodeGitError
 }
 
+func (e *GitError) Code() string {
+	return "git_error"
+}
+
+// Command returns the git command line that failed, if the cause records one
+func (e *GitError) Command() string {
+	return FailedCommand(e.Err)
+}
+
 func (e *GitError) Unwrap() error {
 	return e.Err
 }
@@ -145,13 +437,21 @@ type MergeInProgressError struct {
 }
 
 func (e *MergeInProgressError) Error() string {
-	return fmt.Sprintf("a merge is already in progress for branch '%s'. Use --continue or --abort", e.BranchName)
+	return fmt.Sprintf("a merge is already in progress for branch '%s'. %s", e.BranchName, e.Hint())
+}
+
+func (e *MergeInProgressError) Hint() string {
+	return "Use --continue or --abort"
 }
 
 func (e *MergeInProgressError) ExitCode() uint8 {
 	return 1
 }
 
+func (e *MergeInProgressError) Code() string {
+	return "merge_in_progress"
+}
+
 // NoMergeInProgressError represents an error when no merge is in progress
 type NoMergeInProgressError struct{}
 
@@ -163,6 +463,10 @@ func (e *NoMergeInProgressError) ExitCode() uint8 {
 	return 1
 }
 
+func (e *NoMergeInProgressError) Code() string {
+	return "no_merge_in_progress"
+}
+
 // InvalidBranchNameError represents an error when an invalid branch name is provided
 type InvalidBranchNameError struct {
 	BranchName string
@@ -176,6 +480,10 @@ func (e *InvalidBranchNameError) ExitCode() ExitCode {
 	return ExitCodeInvalidInput
 }
 
+func (e *InvalidBranchNameError) Code() string {
+	return "invalid_branch_name"
+}
+
 // InvalidMergeStrategyError indicates an invalid merge strategy
 type InvalidMergeStrategyError struct {
 	Strategy string
@@ -189,6 +497,10 @@ func (e *InvalidMergeStrategyError) ExitCode() ExitCode {
 	return ExitCodeInvalidInput
 }
 
+func (e *InvalidMergeStrategyError) Code() string {
+	return "invalid_merge_strategy"
+}
+
 // CircularDependencyError indicates a circular dependency in branch configuration
 type CircularDependencyError struct {
 	BranchName string
@@ -202,6 +514,10 @@ func (e *CircularDependencyError) ExitCode() ExitCode {
 	return ExitCodeValidationError
 }
 
+func (e *CircularDependencyError) Code() string {
+	return "circular_dependency"
+}
+
 // BranchHasDependentsError indicates a branch cannot be deleted because it has dependents
 type BranchHasDependentsError struct {
 	BranchName string
@@ -216,6 +532,10 @@ func (e *BranchHasDependentsError) ExitCode() ExitCode {
 	return ExitCodeValidationError
 }
 
+func (e *BranchHasDependentsError) Code() string {
+	return "branch_has_dependents"
+}
+
 // UnresolvedConflictsError represents an error when there are unresolved conflicts
 type UnresolvedConflictsError struct{}
 
@@ -223,53 +543,82 @@ func (e *UnresolvedConflictsError) Error() string {
 	return "there are still unresolved conflicts. Resolve them and try again"
 }
 
+func (e *UnresolvedConflictsError) Hint() string {
+	return "stage the resolved files with 'git add', then run the command again with --continue"
+}
+
 func (e *UnresolvedConflictsError) ExitCode() uint8 {
 	return 1
 }
 
+func (e *UnresolvedConflictsError) Code() string {
+	return "unresolved_conflicts"
+}
+
 // RemoteBranchNotFoundError indicates the branch doesn't exist on the remote
 type RemoteBranchNotFoundError struct {
-	Remote     string
-	BranchName string
+	Remote      string
+	BranchName  string
+	Suggestions []string // similarly named branches on the remote
 }
 
 func (e *RemoteBranchNotFoundError) Error() string {
-	return fmt.Sprintf("branch '%s' not found on remote '%s'", e.BranchName, e.Remote)
+	msg := fmt.Sprintf("branch '%s' not found on remote '%s'", e.BranchName, e.Remote)
+	if len(e.Suggestions) > 0 {
+		msg += "\nSimilar branches on the remote:\n  " + strings.Join(e.Suggestions, "\n  ")
+	}
+	return msg
+}
+
+func (e *RemoteBranchNotFoundError) Hint() string {
+	return fmt.Sprintf("run 'git fetch %s' if the branch was pushed recently", e.Remote)
 }
 
 func (e *RemoteBranchNotFoundError) ExitCode() ExitCode {
 	return ExitCodeBranchNotFound
 }
 
+func (e *RemoteBranchNotFoundError) Code() string {
+	return "remote_branch_not_found"
+}
+
 // BranchBehindRemoteError indicates the local branch is behind its remote tracking branch.
 // Finishing would discard the remote commits, which is likely unintended.
+// Untracked is set when the local branch has no upstream and was compared with
+// the branch of the same name on the remote.
 type BranchBehindRemoteError struct {
 	BranchName   string
 	RemoteBranch string
 	CommitCount  int
 	BranchType   string
+	Untracked    bool
 }
 
 func (e *BranchBehindRemoteError) Error() string {
-	// Get the short name by extracting the part after the last slash if it exists
-	shortName := e.BranchName
-	if idx := lastSlashIndex(e.BranchName); idx != -1 {
-		shortName = e.BranchName[idx+1:]
-	}
-
 	return fmt.Sprintf(`local branch '%s' is behind '%s' by %d commit(s).
 
 The remote branch has commits not present locally. Finishing now
 would discard those changes.
 
-To resolve:
-  git flow %s update %s    # merge/rebase remote changes
+%s`,
+		e.BranchName, e.RemoteBranch, e.CommitCount, e.Hint())
+}
+
+func (e *BranchBehindRemoteError) Hint() string {
+	shortName := shortBranchName(e.BranchName)
+
+	track := ""
+	if e.Untracked {
+		track = fmt.Sprintf("  git branch --set-upstream-to=%s %s    # track the remote branch first\n", e.RemoteBranch, e.BranchName)
+	}
+
+	return fmt.Sprintf(`To resolve:
+%s  git flow %s update %s    # merge/rebase remote changes
   git pull                       # or pull directly
 
 To finish anyway (discarding remote changes):
   git flow %s finish --force %s`,
-		e.BranchName, e.RemoteBranch, e.CommitCount,
-		e.BranchType, shortName,
+		track, e.BranchType, shortName,
 		e.BranchType, shortName)
 }
 
@@ -277,6 +626,264 @@ func (e *BranchBehindRemoteError) ExitCode() ExitCode {
 	return ExitCodeValidationError
 }
 
+func (e *BranchBehindRemoteError) Code() string {
+	return "branch_behind_remote"
+}
+
+// FastForwardNotPossibleError indicates a fast-forward-only finish would need a
+// merge commit because the target branch has commits the topic branch lacks.
+type FastForwardNotPossibleError struct {
+	BranchType   string
+	BranchName   string
+	TargetBranch string
+}
+
+func (e *FastForwardNotPossibleError) Error() string {
+	return fmt.Sprintf("cannot fast-forward '%s' to '%s': '%s' has commits that are not in '%s'.\n\n%s",
+		e.TargetBranch, e.BranchName, e.TargetBranch, e.BranchName, e.Hint())
+}
+
+func (e *FastForwardNotPossibleError) Hint() string {
+	shortName := shortBranchName(e.BranchName)
+
+	return fmt.Sprintf(`To resolve:
+  git flow %s update %s    # bring the branch up to date first
+
+To finish with a merge commit instead:
+  git flow %s finish --ff %s`,
+		e.BranchType, shortName,
+		e.BranchType, shortName)
+}
+
+func (e *FastForwardNotPossibleError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *FastForwardNotPossibleError) Code() string {
+	return "fast_forward_not_possible"
+}
+
+// BackMergesError indicates a topic branch merged its target branch in more
+// often than allowed, and merging it would add those merges to the target's history.
+type BackMergesError struct {
+	BranchType   string
+	BranchName   string
+	TargetBranch string
+	Count        int
+	Max          int
+}
+
+func (e *BackMergesError) Error() string {
+	return fmt.Sprintf("refusing to merge '%s' into '%s': it merged '%s' in %d times, at most %d allowed.\n\n%s",
+		e.BranchName, e.TargetBranch, e.TargetBranch, e.Count, e.Max, e.Hint())
+}
+
+func (e *BackMergesError) Hint() string {
+	shortName := shortBranchName(e.BranchName)
+
+	return fmt.Sprintf(`To finish without the merges:
+  git flow %s finish --rebase %s    # replay the commits onto '%s'
+  git flow %s finish --squash %s    # merge the changes as one commit`,
+		e.BranchType, shortName, e.TargetBranch,
+		e.BranchType, shortName)
+}
+
+func (e *BackMergesError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *BackMergesError) Code() string {
+	return "back_merges"
+}
+
+// PublishedRebaseError indicates finish would rebase a child base branch that
+// is published on the remote, rewriting history others may have built on.
+type PublishedRebaseError struct {
+	BranchType   string
+	BranchName   string
+	ChildBranch  string
+	ParentBranch string
+	Remote       string
+}
+
+func (e *PublishedRebaseError) Error() string {
+	return fmt.Sprintf("refusing to rebase '%s' onto '%s': '%s' is published on '%s', and rebasing rewrites its history.\n\n%s",
+		e.ChildBranch, e.ParentBranch, e.ChildBranch, e.Remote, e.Hint())
+}
+
+func (e *PublishedRebaseError) Hint() string {
+	shortName := shortBranchName(e.BranchName)
+
+	return fmt.Sprintf(`To rebase it anyway and force-push it with --push:
+  git flow %s finish --force %s
+
+To merge '%s' into '%s' this time instead:
+  git flow %s finish --child-strategy %s=merge %s`,
+		e.BranchType, shortName,
+		e.ParentBranch, e.ChildBranch,
+		e.BranchType, e.ChildBranch, shortName)
+}
+
+func (e *PublishedRebaseError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *PublishedRebaseError) Code() string {
+	return "published_rebase"
+}
+
+// BaseSignatureError indicates the tip of the base branch a topic branch would be
+// finished into is not signed, or not signed by an allowed key.
+type BaseSignatureError struct {
+	BaseBranch string
+	Commit     string
+	Reason     string
+}
+
+func (e *BaseSignatureError) Error() string {
+	return fmt.Sprintf(`refusing to merge into '%s': the signature of its tip %s %s.
+
+The local base branch may have been tampered with.
+%s`,
+		e.BaseBranch, e.Commit, e.Reason, e.Hint())
+}
+
+func (e *BaseSignatureError) Hint() string {
+	return fmt.Sprintf("Compare it with the remote before finishing:\n  git log --show-signature -1 %s", e.BaseBranch)
+}
+
+func (e *BaseSignatureError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *BaseSignatureError) Code() string {
+	return "base_signature_invalid"
+}
+
+// BaseBranchMissingError indicates the branch a topic branch would be finished into no longer exists,
+// typically because it was renamed or deleted after the topic branch was started.
+type BaseBranchMissingError struct {
+	BranchType string
+	BranchName string
+	BaseBranch string
+	Source     string
+	Candidates []string
+}
+
+func (e *BaseBranchMissingError) Error() string {
+	msg := fmt.Sprintf("cannot finish '%s': base branch '%s' (%s) does not exist.\n\nIt may have been renamed or deleted.", e.BranchName, e.BaseBranch, e.Source)
+	if len(e.Candidates) > 0 {
+		msg += "\n\nCandidate branches:"
+		for _, candidate := range e.Candidates {
+			msg += "\n  " + candidate
+		}
+	}
+	return msg + "\n\n" + e.Hint()
+}
+
+func (e *BaseBranchMissingError) Hint() string {
+	return fmt.Sprintf("To finish into a different branch:\n  git flow %s finish %s --to <branch>", e.BranchType, shortBranchName(e.BranchName))
+}
+
+func (e *BaseBranchMissingError) ExitCode() ExitCode {
+	return ExitCodeBranchNotFound
+}
+
+func (e *BaseBranchMissingError) Code() string {
+	return "base_branch_missing"
+}
+
+// PreflightFailedError reports every problem found by pre-flight validation
+type PreflightFailedError struct {
+	Problems []string
+}
+
+func (e *PreflightFailedError) Error() string {
+	return fmt.Sprintf("pre-flight checks failed:\n\n%s", strings.Join(e.Problems, "\n\n"))
+}
+
+func (e *PreflightFailedError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *PreflightFailedError) Code() string {
+	return "preflight_failed"
+}
+
+// PolicyCheckError reports the rules of the branching model a branch violates
+type PolicyCheckError struct {
+	BranchName string
+	Problems   []string
+	Rule       string // first rule violated: "prefix", "name", "parent" or "behind"
+}
+
+func (e *PolicyCheckError) Error() string {
+	return fmt.Sprintf("branch '%s' violates the branching model:\n  %s", e.BranchName, strings.Join(e.Problems, "\n  "))
+}
+
+// ExitCode distinguishes the violated rule, so pipelines can tell them apart
+func (e *PolicyCheckError) ExitCode() ExitCode {
+	switch e.Rule {
+	case "prefix":
+		return ExitCodeCheckPrefix
+	case "name":
+		return ExitCodeCheckName
+	case "parent":
+		return ExitCodeCheckParent
+	case "behind":
+		return ExitCodeCheckBehind
+	default:
+		return ExitCodeValidationError
+	}
+}
+
+func (e *PolicyCheckError) Code() string {
+	return "check_failed"
+}
+
+// DoctorError indicates 'git flow doctor' found problems with the repository
+type DoctorError struct {
+	Problems []string
+}
+
+func (e *DoctorError) Error() string {
+	return fmt.Sprintf("found %d problem(s):\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+func (e *DoctorError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *DoctorError) Code() string {
+	return "doctor_failed"
+}
+
+// AuditError indicates 'git flow audit' found history that bypasses the
+// branching model
+type AuditError struct {
+	Violations int
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("found %d violation(s) of the branching model in the history", e.Violations)
+}
+
+func (e *AuditError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *AuditError) Code() string {
+	return "audit_failed"
+}
+
+// shortBranchName returns the part of a branch name after the last slash
+func shortBranchName(branch string) string {
+	if idx := lastSlashIndex(branch); idx != -1 {
+		return branch[idx+1:]
+	}
+	return branch
+}
+
 // lastSlashIndex returns the index of the last slash in a string, or -1 if not found
 func lastSlashIndex(s string) int {
 	for i := len(s) - 1; i >= 0; i-- {
@@ -291,9 +898,468 @@ func lastSlashIndex(s string) int {
 type AlreadyInitializedError struct{}
 
 func (e *AlreadyInitializedError) Error() string {
-	return "git-flow is already initialized in this repository. Use --force to reconfigure"
+	return "git-flow is already initialized in this repository. " + e.Hint()
+}
+
+func (e *AlreadyInitializedError) Hint() string {
+	return "Use --force to reconfigure"
 }
 
 func (e *AlreadyInitializedError) ExitCode() ExitCode {
 	return ExitCodeValidationError
 }
+
+func (e *AlreadyInitializedError) Code() string {
+	return "already_initialized"
+}
+
+// DetachedHeadError indicates a command needs the current branch while HEAD is detached
+type DetachedHeadError struct {
+	Usage string // Command line that names the branch explicitly
+}
+
+func (e *DetachedHeadError) Error() string {
+	return "HEAD is detached, so there is no current branch; " + e.Hint()
+}
+
+func (e *DetachedHeadError) Hint() string {
+	if e.Usage == "" {
+		return "check out a branch first"
+	}
+	return fmt.Sprintf("check out a branch first or name it: %s", e.Usage)
+}
+
+func (e *DetachedHeadError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+func (e *DetachedHeadError) Code() string {
+	return "detached_head"
+}
+
+// InvalidInputError indicates a command was invoked with unusable arguments or flags
+type InvalidInputError struct {
+	Message string
+}
+
+func (e *InvalidInputError) Error() string {
+	return e.Message
+}
+
+func (e *InvalidInputError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+func (e *InvalidInputError) Code() string {
+	return "invalid_input"
+}
+
+// InvalidConfigValueError indicates a git-flow config key holds an unsupported value
+type InvalidConfigValueError struct {
+	Key     string
+	Value   string
+	Allowed []string
+}
+
+func (e *InvalidConfigValueError) Error() string {
+	return fmt.Sprintf("invalid value '%s' for %s (valid options: %s)", e.Value, e.Key, strings.Join(e.Allowed, ", "))
+}
+
+func (e *InvalidConfigValueError) Hint() string {
+	return fmt.Sprintf("set a valid value with 'git config %s <value>' or remove it with 'git config --unset %s'", e.Key, e.Key)
+}
+
+func (e *InvalidConfigValueError) ExitCode() ExitCode {
+	return ExitCodeInvalidInput
+}
+
+func (e *InvalidConfigValueError) Code() string {
+	return "invalid_config_value"
+}
+
+// ManagedInstallError indicates self-update was refused because a package
+// manager installed git-flow-next and would not know about the new binary.
+type ManagedInstallError struct {
+	Manager        string
+	UpgradeCommand string
+}
+
+func (e *ManagedInstallError) Error() string {
+	return fmt.Sprintf("git-flow-next was installed with %s; %s", e.Manager, e.Hint())
+}
+
+func (e *ManagedInstallError) Hint() string {
+	if e.UpgradeCommand == "" {
+		return fmt.Sprintf("update it with %s instead", e.Manager)
+	}
+	return fmt.Sprintf("update it with '%s' instead", e.UpgradeCommand)
+}
+
+func (e *ManagedInstallError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *ManagedInstallError) Code() string {
+	return "managed_install"
+}
+
+// ReleaseVerificationError indicates a downloaded release failed its checksum or
+// signature check and was not installed.
+type ReleaseVerificationError struct {
+	Release string
+	Reason  string
+}
+
+func (e *ReleaseVerificationError) Error() string {
+	return fmt.Sprintf("refusing to install %s: %s", e.Release, e.Reason)
+}
+
+func (e *ReleaseVerificationError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *ReleaseVerificationError) Code() string {
+	return "release_verification_failed"
+}
+
+// SelfUpdateError indicates a release could not be looked up, downloaded or installed
+type SelfUpdateError struct {
+	Operation string
+	Err       error
+}
+
+func (e *SelfUpdateError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Operation, e.Err)
+}
+
+func (e *SelfUpdateError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+func (e *SelfUpdateError) Code() string {
+	return "self_update_failed"
+}
+
+func (e *SelfUpdateError) Unwrap() error {
+	return e.Err
+}
+
+// InterruptedError indicates an operation stopped at a step boundary after receiving a signal
+type InterruptedError struct {
+	Signal        string
+	Step          string // step the operation will resume at; empty when nothing was changed
+	ResumeCommand string
+}
+
+func (e *InterruptedError) Error() string {
+	if e.Step == "" {
+		return fmt.Sprintf("interrupted by %s before any changes were made", e.Signal)
+	}
+	return fmt.Sprintf("interrupted by %s; progress was saved and the operation will resume at step '%s'.\n%s", e.Signal, e.Step, e.Hint())
+}
+
+func (e *InterruptedError) Hint() string {
+	if e.Step == "" {
+		return ""
+	}
+	return fmt.Sprintf("Run '%s' to resume, or 'git flow state show' to inspect the saved state", e.ResumeCommand)
+}
+
+func (e *InterruptedError) ExitCode() ExitCode {
+	return ExitCodeInterrupted
+}
+
+func (e *InterruptedError) Code() string {
+	return "interrupted"
+}
+
+// UnreadableStateError indicates the saved state of an interrupted operation no
+// longer parses, e.g. after a crash while it was written
+type UnreadableStateError struct {
+	Path       string
+	Err        error
+	BackupStep string // step the backup of the previous state resumes at; empty without a usable backup
+}
+
+func (e *UnreadableStateError) Error() string {
+	return fmt.Sprintf("the saved state of the interrupted operation in %s is unreadable: %v", e.Path, e.Err)
+}
+
+func (e *UnreadableStateError) Hint() string {
+	if e.BackupStep == "" {
+		return "Run 'git flow state repair' to discard it"
+	}
+	return fmt.Sprintf("Run 'git flow state repair' to restore the backup of the previous state, which runs step '%s' again, or to discard it", e.BackupStep)
+}
+
+func (e *UnreadableStateError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+func (e *UnreadableStateError) Code() string {
+	return "unreadable_state"
+}
+
+// RejectedRef is a ref a push did not update
+type RejectedRef struct {
+	Ref    string // ref as git names it in the push output, e.g. develop
+	Reason string // reason git gives, e.g. "pre-receive hook declined" or "non-fast-forward"
+	Remote bool   // refused by the remote rather than by git before sending it
+}
+
+// PushRejectedError indicates a push was refused for some refs, by the
+// remote's hooks or because the remote branch has commits the pushed branch
+// lacks. Messages are the lines the remote printed, kept verbatim.
+type PushRejectedError struct {
+	Remote   string
+	Refs     []RejectedRef
+	Messages []string
+	// ForceWithLease is set when replacing the remote branch with
+	// --force-with-lease loses no commits, because the local branch holds
+	// rebased copies of all of them
+	ForceWithLease bool
+}
+
+func (e *PushRejectedError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "push to '%s' was rejected", e.Remote)
+	for _, ref := range e.Refs {
+		fmt.Fprintf(&msg, "\n  ! %s (%s)", ref.Ref, ref.Reason)
+	}
+	if len(e.Messages) > 0 {
+		fmt.Fprintf(&msg, "\nThe remote said:")
+		for _, line := range e.Messages {
+			fmt.Fprintf(&msg, "\n  %s", line)
+		}
+	}
+	return msg.String()
+}
+
+func (e *PushRejectedError) Hint() string {
+	for _, ref := range e.Refs {
+		switch {
+		case ref.Remote && ref.Reason != "atomic push failed":
+			return "The remote refused the push; address the messages above and push again"
+		case ref.Reason == "stale info":
+			return "The remote branch changed since the last fetch; fetch and review its commits before pushing again"
+		case ref.Reason == "non-fast-forward" && e.ForceWithLease:
+			return "The remote branch only has commits the local branch holds rebased copies of; use --force-with-lease to replace it"
+		case ref.Reason == "non-fast-forward" || ref.Reason == "fetch first":
+			return "The remote branch has commits the local branch lacks; pull or rebase onto them, then push again"
+		}
+	}
+	return ""
+}
+
+func (e *PushRejectedError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+func (e *PushRejectedError) Code() string {
+	return "push_rejected"
+}
+
+// Reasons git could not talk to a remote, as reported by RemoteError.Kind
+const (
+	RemoteAuthFailed   = "auth_failed"   // credentials or SSH key were refused
+	RemoteUnreachable  = "unreachable"   // host unknown, refusing connections or timing out
+	RemoteProxyFailed  = "proxy_failed"  // the configured proxy failed
+	RemoteServerFailed = "server_failed" // the server answered with an error
+	RemoteNotFound     = "not_found"     // no repository at the remote URL
+)
+
+// RemoteError indicates a fetch or push failed because git could not talk to
+// the remote. Output is what git printed, which names the underlying problem.
+type RemoteError struct {
+	Remote    string
+	URL       string
+	Operation string // what failed, e.g. "fetch from" or "push to"
+	Kind      string
+	Output    string
+	Attempts  int // how often the operation was tried
+}
+
+func (e *RemoteError) Error() string {
+	var reason string
+	switch e.Kind {
+	case RemoteAuthFailed:
+		reason = "authentication failed"
+	case RemoteUnreachable:
+		reason = "the remote is unreachable"
+	case RemoteProxyFailed:
+		reason = "the proxy failed"
+	case RemoteServerFailed:
+		reason = "the server failed"
+	case RemoteNotFound:
+		reason = "the repository was not found"
+	}
+	msg := fmt.Sprintf("%s '%s' failed: %s", e.Operation, e.Remote, reason)
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (tried %d times)", e.Attempts)
+	}
+	for _, line := range strings.Split(e.Output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			msg += "\n  " + line
+		}
+	}
+	return msg
+}
+
+func (e *RemoteError) Hint() string {
+	https := strings.HasPrefix(e.URL, "https://") || strings.HasPrefix(e.URL, "http://")
+	switch e.Kind {
+	case RemoteAuthFailed:
+		if https {
+			return "Check the credentials for " + e.URL + ": update the stored password or token in your credential helper, or use an SSH URL"
+		}
+		return "Check that your SSH key is loaded ('ssh-add -l') and accepted by the server ('ssh -T' with the host of " + e.URL + ")"
+	case RemoteUnreachable:
+		return fmt.Sprintf("Check your network connection and the URL of '%s' ('git remote get-url %s')", e.Remote, e.Remote)
+	case RemoteProxyFailed:
+		return "Check the proxy settings: http.proxy, https.proxy and the https_proxy and all_proxy environment variables"
+	case RemoteServerFailed:
+		return "The server reported an error; try again later"
+	case RemoteNotFound:
+		return fmt.Sprintf("Check the URL of '%s' ('git remote get-url %s') and that you have access to the repository", e.Remote, e.Remote)
+	}
+	return ""
+}
+
+func (e *RemoteError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+func (e *RemoteError) Code() string {
+	return "remote_" + e.Kind
+}
+
+// Transient reports whether the failure may go away when the operation is
+// retried, as for timeouts and server errors
+func (e *RemoteError) Transient() bool {
+	return e.Kind == RemoteUnreachable || e.Kind == RemoteServerFailed
+}
+
+// CherryPickConflictError indicates a commit did not apply cleanly when it was
+// cherry-picked onto Target
+type CherryPickConflictError struct {
+	Commit string   // commit that did not apply
+	Target string   // branch the commit was cherry-picked onto
+	Files  []string // files with conflicts
+}
+
+func (e *CherryPickConflictError) Error() string {
+	return fmt.Sprintf("commit %s conflicts with '%s' in %s", e.Commit, e.Target, strings.Join(e.Files, ", "))
+}
+
+func (e *CherryPickConflictError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+func (e *CherryPickConflictError) Code() string {
+	return "cherry_pick_conflict"
+}
+
+// PolicyViolationError indicates a configuration change the organization
+// policy in Policy forbids
+type PolicyViolationError struct {
+	Policy     string   // path of the policy file
+	Violations []string // what the change breaks, e.g. "gitflow.x is pinned to 'y'"
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("the change conflicts with the policy in %s: %s", e.Policy, strings.Join(e.Violations, "; "))
+}
+
+func (e *PolicyViolationError) Hint() string {
+	return "the policy is maintained by your organization; run 'git flow doctor' to compare the configuration with it"
+}
+
+func (e *PolicyViolationError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *PolicyViolationError) Code() string {
+	return "policy_violation"
+}
+
+// WorktreeConflictError indicates a branch the operation needs to check out,
+// delete or rewrite is in use by another worktree of the repository
+type WorktreeConflictError struct {
+	BranchName string
+	Worktree   string // path of the other worktree
+	Operation  string // operation in progress there, "" if the branch is only checked out
+}
+
+func (e *WorktreeConflictError) Error() string {
+	if e.Operation != "" {
+		return fmt.Sprintf("a %s in progress in worktree '%s' changes branch '%s'", e.Operation, e.Worktree, e.BranchName)
+	}
+	return fmt.Sprintf("branch '%s' is checked out in worktree '%s'", e.BranchName, e.Worktree)
+}
+
+func (e *WorktreeConflictError) Hint() string {
+	if e.Operation != "" {
+		return fmt.Sprintf("continue or abort it in '%s' first", e.Worktree)
+	}
+	return fmt.Sprintf("run the command in '%s', or switch that worktree to another branch", e.Worktree)
+}
+
+func (e *WorktreeConflictError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *WorktreeConflictError) Code() string {
+	return "worktree_conflict"
+}
+
+// ForgeAuthError indicates no usable API token for a hosting service
+type ForgeAuthError struct {
+	Host   string
+	Source string // where the rejected token was found; "" if none was found
+	Err    error  // why the token was rejected
+}
+
+func (e *ForgeAuthError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("no API token for %s found", e.Host)
+	}
+	return fmt.Sprintf("the API token for %s from %s was rejected: %v", e.Host, e.Source, e.Err)
+}
+
+func (e *ForgeAuthError) Hint() string {
+	if e.Source == "" {
+		return "set GH_TOKEN, GITLAB_TOKEN or BITBUCKET_TOKEN, store a token with a Git credential helper, or log in with gh, hub or glab"
+	}
+	return fmt.Sprintf("replace the token in %s or give it access to the repository", e.Source)
+}
+
+func (e *ForgeAuthError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *ForgeAuthError) Code() string {
+	return "forge_auth_failed"
+}
+
+// ReplayFailedError indicates a step of a replay script that failed
+type ReplayFailedError struct {
+	Script  string
+	Err     error  // the failed step
+	Fixture string // directory kept for inspection
+}
+
+func (e *ReplayFailedError) Error() string {
+	return fmt.Sprintf("replay of '%s' failed at %v", e.Script, e.Err)
+}
+
+func (e *ReplayFailedError) Hint() string {
+	return fmt.Sprintf("the fixture repository was kept in %s for inspection", e.Fixture)
+}
+
+func (e *ReplayFailedError) ExitCode() ExitCode {
+	return ExitCodeValidationError
+}
+
+func (e *ReplayFailedError) Code() string {
+	return "replay_failed"
+}
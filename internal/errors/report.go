@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Coder is implemented by errors with a machine-readable code such as
+// "branch_not_found". Codes are stable and meant for scripts.
+type Coder interface {
+	Code() string
+}
+
+// Hinter is implemented by errors that can suggest how to resolve them
+type Hinter interface {
+	Hint() string
+}
+
+// CommandError records a git invocation that failed. The git package wraps
+// its errors in it, so the failing command can be reported along with any
+// error built on top of it.
+type CommandError struct {
+	Args []string // arguments passed to git
+	Err  error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CommandError) Code() string {
+	return "git_command_failed"
+}
+
+func (e *CommandError) ExitCode() ExitCode {
+	return ExitCodeGitError
+}
+
+// Command returns the failed command line
+func (e *CommandError) Command() string {
+	return "git " + strings.Join(e.Args, " ")
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Report is the structured form of an error as printed by the CLI
+type Report struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Command string `json:"command,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// NewReport describes err. The code, command and hint are taken from the
+// first error in the chain that provides one; errors without a code are
+// reported as "error".
+func NewReport(err error) Report {
+	report := Report{
+		Code:    "error",
+		Message: strings.TrimSpace(err.Error()),
+		Command: FailedCommand(err),
+	}
+	var coder Coder
+	if stderrors.As(err, &coder) {
+		report.Code = coder.Code()
+	}
+	for e := err; e != nil; e = stderrors.Unwrap(e) {
+		if hinter, ok := e.(Hinter); ok && hinter.Hint() != "" {
+			report.Hint = hinter.Hint()
+			break
+		}
+	}
+	return report
+}
+
+// FailedCommand returns the git command line recorded in err's chain, or ""
+func FailedCommand(err error) string {
+	var cmdErr *CommandError
+	if stderrors.As(err, &cmdErr) {
+		return cmdErr.Command()
+	}
+	return ""
+}
+
+// PushRejection returns the PushRejectedError in err's chain, or nil
+func PushRejection(err error) *PushRejectedError {
+	var rejected *PushRejectedError
+	if stderrors.As(err, &rejected) {
+		return rejected
+	}
+	return nil
+}
+
+// RemoteFailure returns the RemoteError in err's chain, or nil
+func RemoteFailure(err error) *RemoteError {
+	var remoteErr *RemoteError
+	if stderrors.As(err, &remoteErr) {
+		return remoteErr
+	}
+	return nil
+}
+
+// Render writes err as the CLI reports it: "Error: <message>", followed by
+// the failed git command and the hint. A hint the message already contains
+// is not repeated.
+func Render(w io.Writer, err error) {
+	report := NewReport(err)
+	fmt.Fprintf(w, "Error: %s\n", report.Message)
+	if report.Command != "" && !strings.Contains(report.Message, report.Command) {
+		fmt.Fprintf(w, "Command: %s\n", report.Command)
+	}
+	if report.Hint != "" && !strings.Contains(report.Message, report.Hint) {
+		fmt.Fprintf(w, "Hint: %s\n", report.Hint)
+	}
+}
+
+// RenderJSON writes err as a single-line JSON object {"error": <Report>}
+func RenderJSON(w io.Writer, err error) {
+	data, jsonErr := json.Marshal(struct {
+		Error Report `json:"error"`
+	}{NewReport(err)})
+	if jsonErr != nil {
+		Render(w, err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
@@ -0,0 +1,125 @@
+// Package events reports the lifecycle of git-flow operations to observers.
+//
+// Embedders of the commands subscribe an Observer to render progress or
+// collect metrics; the CLI's --porcelain mode is one such observer. Events
+// are delivered synchronously, in the order the operation reaches them, on
+// the goroutine running the operation.
+package events
+
+import "sync"
+
+// StepStart is reported when an operation begins one of its steps
+type StepStart struct {
+	Operation string `json:"operation"` // finish, update or sync-bases
+	Branch    string `json:"branch"`    // Branch the operation works on
+	Step      string `json:"step"`      // Step identifier, as in the merge state
+	Label     string `json:"label"`     // Human-readable description of the step
+}
+
+// Conflict is reported when merging or rebasing stopped on conflicts that
+// need to be resolved by hand
+type Conflict struct {
+	Branch string   `json:"branch"` // Branch being merged into or rebased
+	Source string   `json:"source"` // Branch whose changes conflict
+	Files  []string `json:"files"`  // Files with unresolved conflicts
+}
+
+// TagCreated is reported when a tag is created or moved
+type TagCreated struct {
+	Tag    string `json:"tag"`
+	Branch string `json:"branch"` // Branch the tag points at
+	Moved  bool   `json:"moved"`  // The tag existed and was moved
+}
+
+// BranchDeleted is reported when a local or remote branch is deleted
+type BranchDeleted struct {
+	Branch string `json:"branch"`
+	Remote string `json:"remote,omitempty"` // Remote the branch was deleted from, empty for the local branch
+}
+
+// Observer receives the events of operations. Embed NopObserver to implement
+// only some of the methods.
+type Observer interface {
+	OnStepStart(event StepStart)
+	OnConflict(event Conflict)
+	OnTagCreated(event TagCreated)
+	OnBranchDeleted(event BranchDeleted)
+}
+
+// NopObserver ignores all events
+type NopObserver struct{}
+
+func (NopObserver) OnStepStart(StepStart)         {}
+func (NopObserver) OnConflict(Conflict)           {}
+func (NopObserver) OnTagCreated(TagCreated)       {}
+func (NopObserver) OnBranchDeleted(BranchDeleted) {}
+
+// Bus delivers events to the subscribed observers. A nil Bus drops events.
+type Bus struct {
+	mu        sync.Mutex
+	observers []Observer
+}
+
+// NewBus returns a bus without observers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds observer to the bus
+func (b *Bus) Subscribe(observer Observer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.observers = append(b.observers, observer)
+}
+
+// StepStart reports the start of a step to every observer
+func (b *Bus) StepStart(event StepStart) {
+	for _, observer := range b.snapshot() {
+		observer.OnStepStart(event)
+	}
+}
+
+// Conflict reports conflicts to every observer
+func (b *Bus) Conflict(event Conflict) {
+	for _, observer := range b.snapshot() {
+		observer.OnConflict(event)
+	}
+}
+
+// TagCreated reports a created tag to every observer
+func (b *Bus) TagCreated(event TagCreated) {
+	for _, observer := range b.snapshot() {
+		observer.OnTagCreated(event)
+	}
+}
+
+// BranchDeleted reports a deleted branch to every observer
+func (b *Bus) BranchDeleted(event BranchDeleted) {
+	for _, observer := range b.snapshot() {
+		observer.OnBranchDeleted(event)
+	}
+}
+
+// snapshot returns the observers, so they can subscribe others while an
+// event is delivered
+func (b *Bus) snapshot() []Observer {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Observer(nil), b.observers...)
+}
+
+// defaultBus carries the events of the running process
+var defaultBus = NewBus()
+
+// Default returns the bus the operations of the running process report to
+func Default() *Bus {
+	return defaultBus
+}
+
+// Subscribe adds observer to the default bus
+func Subscribe(observer Observer) {
+	defaultBus.Subscribe(observer)
+}
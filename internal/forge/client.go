@@ -0,0 +1,434 @@
+package forge
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIURLEnv names the environment variable that replaces the API endpoint of
+// the hosting service, e.g. to go through a proxy
+const APIURLEnv = "GITFLOW_FORGE_API_URL"
+
+// DefaultCacheTTL is how long a cached response is used without asking the
+// hosting service whether it changed
+const DefaultCacheTTL = time.Minute
+
+// DefaultMaxRateLimitWait is the longest a request waits for an exhausted rate
+// limit to reset before it fails
+const DefaultMaxRateLimitWait = time.Minute
+
+// Client talks to the REST API of a hosting service on behalf of the
+// integrations. Responses to GET requests are cached on disk and revalidated
+// with their ETag, which GitHub does not count against the rate limit. A
+// request that hits the rate limit waits for it to reset if that is soon
+// enough, and fails with a RateLimitError otherwise.
+type Client struct {
+	Repo             *Repository
+	Token            *Token
+	BaseURL          string        // API endpoint, without a trailing slash
+	CacheDir         string        // directory of cached responses; "" disables the cache
+	CacheTTL         time.Duration // how long cached responses are used without revalidating them
+	MaxRateLimitWait time.Duration
+	RateLimit        RateLimit // as reported by the last response
+
+	http *http.Client
+}
+
+// RateLimit is the request budget the hosting service grants the token
+type RateLimit struct {
+	Limit     int // 0 if the service did not report a limit
+	Remaining int
+	Reset     time.Time
+}
+
+// APIError is an unsuccessful response of the hosting service
+type APIError struct {
+	Method  string
+	URL     string
+	Status  int
+	Message string // message of the response body, if any
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s returned %d %s", e.Method, e.URL, e.Status, http.StatusText(e.Status))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Unauthorized reports whether the token was rejected or lacks permissions
+func (e *APIError) Unauthorized() bool {
+	return e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden
+}
+
+// RateLimitError indicates the rate limit is exhausted for longer than a
+// request waits
+type RateLimitError struct {
+	Host  string
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("the API rate limit of %s is exhausted until %s", e.Host, e.Reset.Local().Format("15:04:05"))
+}
+
+// NewClient returns a client for the API of the repository's hosting service
+// that authenticates with token, which may be nil for anonymous requests
+func NewClient(repo *Repository, token *Token, cacheDir string) *Client {
+	return &Client{
+		Repo:             repo,
+		Token:            token,
+		BaseURL:          APIURL(repo),
+		CacheDir:         cacheDir,
+		CacheTTL:         DefaultCacheTTL,
+		MaxRateLimitWait: DefaultMaxRateLimitWait,
+		http:             &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// APIURL returns the REST API endpoint of the repository's hosting service,
+// honoring APIURLEnv
+func APIURL(repo *Repository) string {
+	if override := os.Getenv(APIURLEnv); override != "" {
+		return strings.TrimSuffix(override, "/")
+	}
+	switch repo.Kind {
+	case GitLab:
+		return "https://" + repo.Host + "/api/v4"
+	case Bitbucket:
+		return "https://api.bitbucket.org/2.0"
+	default:
+		if strings.EqualFold(repo.Host, "github.com") {
+			return "https://api.github.com"
+		}
+		return "https://" + repo.Host + "/api/v3"
+	}
+}
+
+// Get decodes the JSON document at path, relative to the API endpoint, into result
+func (c *Client) Get(path string, result interface{}) error {
+	return c.request(http.MethodGet, path, nil, result)
+}
+
+// Post sends body as JSON to path and decodes the response into result
+func (c *Client) Post(path string, body interface{}, result interface{}) error {
+	return c.request(http.MethodPost, path, body, result)
+}
+
+// cacheEntry is a cached response to a GET request
+type cacheEntry struct {
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Fetched      time.Time       `json:"fetched"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// request sends a request and decodes the JSON response into result
+func (c *Client) request(method, path string, body interface{}, result interface{}) error {
+	target := c.BaseURL + path
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	var cached *cacheEntry
+	if method == http.MethodGet {
+		cached = c.readCache(target)
+		if cached != nil && time.Since(cached.Fetched) < c.CacheTTL {
+			return decodeBody(cached.Body, result)
+		}
+	}
+
+	// The budget is spent, so the request would be refused anyway
+	if c.RateLimit.Limit > 0 && c.RateLimit.Remaining == 0 && time.Now().Before(c.RateLimit.Reset) {
+		if err := c.waitForRateLimit(time.Until(c.RateLimit.Reset)); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, target, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		c.authorize(req)
+		req.Header.Set("Accept", "application/json")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		c.updateRateLimit(resp.Header)
+
+		if wait, limited := c.rateLimited(resp); limited {
+			if attempt > 0 {
+				return &RateLimitError{Host: c.Repo.Host, Reset: time.Now().Add(wait)}
+			}
+			if err := c.waitForRateLimit(wait); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified && cached != nil:
+			cached.Fetched = time.Now()
+			c.writeCache(target, cached)
+			return decodeBody(cached.Body, result)
+		case resp.StatusCode < 200 || resp.StatusCode > 299:
+			return &APIError{Method: method, URL: target, Status: resp.StatusCode, Message: errorMessage(data)}
+		}
+
+		if method == http.MethodGet {
+			c.writeCache(target, &cacheEntry{
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				Fetched:      time.Now(),
+				Body:         data,
+			})
+		}
+		return decodeBody(data, result)
+	}
+}
+
+// authorize adds the token to req: as a bearer token, or with basic
+// authentication for Bitbucket credentials that come with a user name
+func (c *Client) authorize(req *http.Request) {
+	if c.Token == nil {
+		return
+	}
+	if c.Repo.Kind == Bitbucket && c.Token.Username != "" {
+		req.SetBasicAuth(c.Token.Username, c.Token.Value)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token.Value)
+}
+
+// updateRateLimit records the rate limit headers of a response, named
+// X-RateLimit-* by GitHub and RateLimit-* by GitLab
+func (c *Client) updateRateLimit(header http.Header) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, err := strconv.Atoi(header.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		remaining, _ := strconv.Atoi(header.Get(prefix + "Remaining"))
+		reset, _ := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+		c.RateLimit = RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+		return
+	}
+}
+
+// rateLimited reports whether resp refused the request because of the rate
+// limit, and how long to wait before retrying it
+func (c *Client) rateLimited(resp *http.Response) (time.Duration, bool) {
+	retryAfter := resp.Header.Get("Retry-After")
+	exhausted := c.RateLimit.Limit > 0 && c.RateLimit.Remaining == 0
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode == http.StatusForbidden && (exhausted || retryAfter != ""):
+	default:
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if exhausted {
+		return time.Until(c.RateLimit.Reset), true
+	}
+	return time.Minute, true
+}
+
+// waitForRateLimit sleeps for wait, or fails if that is longer than the
+// client waits
+func (c *Client) waitForRateLimit(wait time.Duration) error {
+	if wait > c.MaxRateLimitWait {
+		return &RateLimitError{Host: c.Repo.Host, Reset: time.Now().Add(wait)}
+	}
+	if wait > 0 {
+		fmt.Fprintf(os.Stderr, "API rate limit of %s reached, waiting %s\n", c.Repo.Host, wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+	return nil
+}
+
+// cachePath returns the cache file of a URL. The token is part of the key, as
+// responses depend on what the token may see.
+func (c *Client) cachePath(target string) string {
+	key := target
+	if c.Token != nil {
+		key = c.Token.Value + "\n" + target
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// readCache returns the cached response to a GET of target, or nil
+func (c *Client) readCache(target string) *cacheEntry {
+	if c.CacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.cachePath(target))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil {
+		return nil
+	}
+	return &entry
+}
+
+// writeCache stores the response to a GET of target. The cache is an
+// optimization, so failing to write it is not an error.
+func (c *Client) writeCache(target string, entry *cacheEntry) {
+	if c.CacheDir == "" || !json.Valid(entry.Body) {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil || os.MkdirAll(c.CacheDir, 0700) != nil {
+		return
+	}
+	os.WriteFile(c.cachePath(target), data, 0600)
+}
+
+// decodeBody decodes a JSON response into result, unless result is nil
+func decodeBody(data []byte, result interface{}) error {
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// errorMessage extracts the message of an error response, as sent by GitHub
+// ("message"), GitLab ("message" or "error") and Bitbucket ("error.message")
+func errorMessage(data []byte) string {
+	var body struct {
+		Message interface{} `json:"message"`
+		Error   interface{} `json:"error"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return ""
+	}
+	for _, value := range []interface{}{body.Message, body.Error} {
+		switch v := value.(type) {
+		case string:
+			return v
+		case map[string]interface{}:
+			if message, ok := v["message"].(string); ok {
+				return message
+			}
+		case nil:
+		default:
+			if encoded, err := json.Marshal(v); err == nil {
+				return string(encoded)
+			}
+		}
+	}
+	return ""
+}
+
+// CurrentUser returns the account the token belongs to. The response is
+// always revalidated, so the token is checked even when it is cached.
+func (c *Client) CurrentUser() (string, error) {
+	ttl := c.CacheTTL
+	c.CacheTTL = 0
+	defer func() { c.CacheTTL = ttl }()
+
+	var user struct {
+		Login    string `json:"login"`    // GitHub
+		Username string `json:"username"` // GitLab, Bitbucket
+	}
+	if err := c.Get("/user", &user); err != nil {
+		return "", err
+	}
+	if user.Login != "" {
+		return user.Login, nil
+	}
+	return user.Username, nil
+}
+
+// CreatePullRequest opens a pull request through the API and returns its URL
+func (c *Client) CreatePullRequest(pr PullRequest) (string, error) {
+	var created struct {
+		HTMLURL string `json:"html_url"` // GitHub
+		WebURL  string `json:"web_url"`  // GitLab
+		Links   struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"` // Bitbucket
+	}
+
+	var err error
+	switch c.Repo.Kind {
+	case GitLab:
+		title := pr.Title
+		if pr.Draft {
+			title = "Draft: " + title
+		}
+		err = c.Post("/projects/"+url.PathEscape(c.Repo.Path)+"/merge_requests", map[string]interface{}{
+			"source_branch": pr.Head,
+			"target_branch": pr.Base,
+			"title":         title,
+			"description":   pr.Body,
+		}, &created)
+	case Bitbucket:
+		err = c.Post("/repositories/"+c.Repo.Path+"/pullrequests", map[string]interface{}{
+			"title":       pr.Title,
+			"description": pr.Body,
+			"source":      map[string]interface{}{"branch": map[string]string{"name": pr.Head}},
+			"destination": map[string]interface{}{"branch": map[string]string{"name": pr.Base}},
+			"draft":       pr.Draft,
+		}, &created)
+	default:
+		err = c.Post("/repos/"+c.Repo.Path+"/pulls", map[string]interface{}{
+			"title": pr.Title,
+			"body":  pr.Body,
+			"head":  pr.Head,
+			"base":  pr.Base,
+			"draft": pr.Draft,
+		}, &created)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, link := range []string{created.HTMLURL, created.WebURL, created.Links.HTML.Href} {
+		if link != "" {
+			return link, nil
+		}
+	}
+	return "", fmt.Errorf("%s did not report the URL of the pull request", c.Repo.Host)
+}
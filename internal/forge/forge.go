@@ -0,0 +1,164 @@
+// Package forge builds web URLs for repositories hosted on GitHub, GitLab and
+// Bitbucket from the URL of a Git remote.
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Kind identifies a hosting service
+type Kind string
+
+const (
+	// GitHub is github.com or a GitHub Enterprise instance
+	GitHub Kind = "github"
+	// GitLab is gitlab.com or a self-managed GitLab instance
+	GitLab Kind = "gitlab"
+	// Bitbucket is bitbucket.org
+	Bitbucket Kind = "bitbucket"
+)
+
+// Kinds lists the supported hosting services
+var Kinds = []Kind{GitHub, GitLab, Bitbucket}
+
+// Repository is a repository on a hosting service
+type Repository struct {
+	Kind Kind
+	Host string // host name, including a port for non-default HTTP ports
+	Path string // repository path without ".git", e.g. "owner/repo"
+}
+
+// ParseRemoteURL derives the hosting service and repository from a remote URL.
+// It understands scp-like SSH URLs (git@host:owner/repo.git) as well as
+// ssh://, git://, http:// and https:// URLs. kind overrides detection from the
+// host name, which is needed for self-hosted instances; pass "" to detect it.
+func ParseRemoteURL(remoteURL string, kind Kind) (*Repository, error) {
+	host, path, err := splitRemoteURL(strings.TrimSpace(remoteURL))
+	if err != nil {
+		return nil, err
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return nil, fmt.Errorf("'%s' does not look like a hosted repository URL", remoteURL)
+	}
+
+	if kind == "" {
+		kind = detectKind(host)
+		if kind == "" {
+			return nil, fmt.Errorf("cannot tell which hosting service '%s' is", host)
+		}
+	} else if !Supported(kind) {
+		return nil, fmt.Errorf("unsupported hosting service '%s'", kind)
+	}
+
+	return &Repository{Kind: kind, Host: host, Path: path}, nil
+}
+
+// splitRemoteURL returns the web host and repository path of a remote URL
+func splitRemoteURL(remoteURL string) (string, string, error) {
+	if !strings.Contains(remoteURL, "://") {
+		// scp-like syntax: [user@]host:path
+		hostPart, path, ok := strings.Cut(remoteURL, ":")
+		if !ok || strings.Contains(hostPart, "/") {
+			return "", "", fmt.Errorf("'%s' is not a network remote", remoteURL)
+		}
+		if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+			hostPart = hostPart[at+1:]
+		}
+		return hostPart, path, nil
+	}
+
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid remote URL '%s': %w", remoteURL, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		// Keep non-default ports, the web interface is served there too
+		return parsed.Host, parsed.Path, nil
+	case "ssh", "git", "git+ssh", "ssh+git":
+		// SSH and git ports say nothing about the web interface
+		return parsed.Hostname(), parsed.Path, nil
+	default:
+		return "", "", fmt.Errorf("'%s' is not a network remote", remoteURL)
+	}
+}
+
+// detectKind guesses the hosting service from a host name
+func detectKind(host string) Kind {
+	host = strings.ToLower(host)
+	if name, _, ok := strings.Cut(host, ":"); ok {
+		host = name
+	}
+	for _, kind := range Kinds {
+		// Matches the public services as well as hosts like gitlab.example.com
+		if strings.Contains(host, string(kind)) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// Supported reports whether kind is a supported hosting service
+func Supported(kind Kind) bool {
+	for _, known := range Kinds {
+		if kind == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareURL returns the web page comparing head against base.
+func (r *Repository) CompareURL(base, head string) string {
+	root := "https://" + r.Host + "/" + r.Path
+	switch r.Kind {
+	case GitLab:
+		return fmt.Sprintf("%s/-/compare/%s...%s", root, escapeBranch(base), escapeBranch(head))
+	case Bitbucket:
+		// Bitbucket separates the branches with an encoded carriage return
+		return fmt.Sprintf("%s/branches/compare/%s%%0D%s", root, escapeBranch(head), escapeBranch(base))
+	default:
+		return fmt.Sprintf("%s/compare/%s...%s", root, escapeBranch(base), escapeBranch(head))
+	}
+}
+
+// escapeBranch escapes a branch name for use in a URL path, keeping the
+// slashes that separate branch prefixes
+func escapeBranch(branch string) string {
+	segments := strings.Split(branch, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// OpenBrowser opens url in the user's web browser. $BROWSER takes precedence
+// over the platform's default handler.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		cmd = exec.Command(browser, url)
+	} else {
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Don't wait for the browser; release the process so it outlives git-flow
+	return cmd.Process.Release()
+}
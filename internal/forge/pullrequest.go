@@ -0,0 +1,103 @@
+package forge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// PullRequest describes a pull request (merge request on GitLab) to open
+type PullRequest struct {
+	Base  string // branch the changes are merged into
+	Head  string // branch with the changes
+	Title string
+	Body  string
+	Draft bool
+}
+
+// pullRequestClients are the command line clients used to open pull requests
+var pullRequestClients = map[Kind]string{
+	GitHub: "gh",
+	GitLab: "glab",
+}
+
+// PullRequestClient returns the command line client that opens pull requests
+// on the hosting service, or an error if there is none or it is not installed
+func (r *Repository) PullRequestClient() (string, error) {
+	client, ok := pullRequestClients[r.Kind]
+	if !ok {
+		return "", fmt.Errorf("opening pull requests on %s is not supported", r.Kind)
+	}
+	if _, err := exec.LookPath(client); err != nil {
+		return "", fmt.Errorf("'%s' is required to open pull requests on %s and was not found in PATH", client, r.Kind)
+	}
+	return client, nil
+}
+
+// CheckPullRequests reports whether pull requests can be opened on the
+// hosting service, with its command line client or through the API with a
+// discovered token
+func (r *Repository) CheckPullRequests() error {
+	_, err := r.PullRequestClient()
+	if err == nil || r.DiscoverToken() != nil {
+		return nil
+	}
+	if _, ok := pullRequestClients[r.Kind]; !ok {
+		return fmt.Errorf("opening pull requests on %s requires an API token and none was found (run 'git flow auth status')", r.Kind)
+	}
+	return fmt.Errorf("%v, and no API token was found (run 'git flow auth status')", err)
+}
+
+// CreatePullRequest opens a pull request with the hosting service's command
+// line client, gh for GitHub and glab for GitLab, which handles authentication.
+// Without the client, and on Bitbucket, the pull request is opened through the
+// API with a discovered token. It returns the URL of the new pull request.
+func (r *Repository) CreatePullRequest(pr PullRequest) (string, error) {
+	client, err := r.PullRequestClient()
+	if err != nil {
+		token := r.DiscoverToken()
+		if token == nil {
+			return "", r.CheckPullRequests()
+		}
+		return NewClient(r, token, "").CreatePullRequest(pr)
+	}
+
+	var args []string
+	switch r.Kind {
+	case GitLab:
+		args = []string{"mr", "create", "--repo", "https://" + r.Host + "/" + r.Path,
+			"--source-branch", pr.Head, "--target-branch", pr.Base,
+			"--title", pr.Title, "--description", pr.Body, "--yes"}
+	default:
+		repo := r.Path
+		if !strings.EqualFold(r.Host, "github.com") {
+			repo = r.Host + "/" + r.Path
+		}
+		args = []string{"pr", "create", "--repo", repo,
+			"--base", pr.Base, "--head", pr.Head,
+			"--title", pr.Title, "--body", pr.Body}
+	}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(client, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w", client, err)
+	}
+
+	// The clients print the URL of the new pull request last
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); strings.HasPrefix(line, "https://") || strings.HasPrefix(line, "http://") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("%s did not report the URL of the pull request", client)
+}
@@ -0,0 +1,191 @@
+package forge
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Token is a credential for the API of a hosting service
+type Token struct {
+	Value    string
+	Username string // set for credentials that need basic authentication, such as Bitbucket app passwords
+	Source   string // where the token was found, e.g. "GH_TOKEN" or "git credential"
+}
+
+// Masked returns the token with all but its last four characters hidden
+func (t *Token) Masked() string {
+	if len(t.Value) <= 8 {
+		return strings.Repeat("*", len(t.Value))
+	}
+	return strings.Repeat("*", 4) + t.Value[len(t.Value)-4:]
+}
+
+// tokenVariables returns the environment variables that hold tokens for the
+// repository's host, in the order the service's own client reads them
+func (r *Repository) tokenVariables() []string {
+	switch r.Kind {
+	case GitLab:
+		return []string{"GITLAB_TOKEN"}
+	case Bitbucket:
+		return []string{"BITBUCKET_TOKEN"}
+	default:
+		if strings.EqualFold(r.Host, "github.com") {
+			return []string{"GH_TOKEN", "GITHUB_TOKEN"}
+		}
+		return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+}
+
+// DiscoverToken looks for a token for the repository's host in the
+// environment, the Git credential helpers and the configuration files of the
+// gh, hub and glab clients, in this order. It returns nil if there is none.
+func (r *Repository) DiscoverToken() *Token {
+	for _, name := range r.tokenVariables() {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return &Token{Value: value, Source: name}
+		}
+	}
+	if token := credentialToken(r.Host); token != nil {
+		return token
+	}
+	lookups := map[Kind][]func(host string) *Token{
+		GitHub: {ghToken, hubToken},
+		GitLab: {glabToken},
+	}
+	for _, lookup := range lookups[r.Kind] {
+		if token := lookup(r.Host); token != nil {
+			return token
+		}
+	}
+	return nil
+}
+
+// credentialToken asks the Git credential helpers for the password stored for
+// host. Git must not prompt for one, so terminal and askpass prompts are
+// disabled.
+func credentialToken(host string) *Token {
+	cmd := exec.Command("git", "-c", "credential.interactive=false", "credential", "fill")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=", "GCM_INTERACTIVE=never")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	token := &Token{Source: "git credential"}
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, ok := strings.CutPrefix(line, "password="); ok {
+			token.Value = value
+		} else if value, ok := strings.CutPrefix(line, "username="); ok {
+			token.Username = value
+		}
+	}
+	if token.Value == "" {
+		return nil
+	}
+	return token
+}
+
+// configDir returns the configuration directory of a client: the directory
+// named by variable if it is set, or name in the XDG configuration directory.
+// It returns "" if the home directory is unknown.
+func configDir(variable, name string) string {
+	if variable != "" && os.Getenv(variable) != "" {
+		return os.Getenv(variable)
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", name)
+}
+
+// readYAML decodes the YAML file at path into v and reports whether it could
+func readYAML(path string, v interface{}) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && yaml.Unmarshal(data, v) == nil
+}
+
+// hostEntry returns the entry of hosts for host, ignoring case and a port
+func hostEntry[T any](hosts map[string]T, host string) (T, bool) {
+	name, _, _ := strings.Cut(host, ":")
+	for key, entry := range hosts {
+		if strings.EqualFold(key, host) || strings.EqualFold(key, name) {
+			return entry, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// ghToken reads the token gh stored in hosts.yml. Recent versions of gh keep
+// it in the system keyring instead, where it is not found.
+func ghToken(host string) *Token {
+	dir := configDir("GH_CONFIG_DIR", "gh")
+	if dir == "" {
+		return nil
+	}
+	path := filepath.Join(dir, "hosts.yml")
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if !readYAML(path, &hosts) {
+		return nil
+	}
+	if entry, ok := hostEntry(hosts, host); ok && entry.OAuthToken != "" {
+		return &Token{Value: entry.OAuthToken, Source: "gh config (" + path + ")"}
+	}
+	return nil
+}
+
+// hubToken reads the token hub stored in its configuration file
+func hubToken(host string) *Token {
+	path := os.Getenv("HUB_CONFIG")
+	if path == "" {
+		path = configDir("", "hub")
+	}
+	if path == "" {
+		return nil
+	}
+	var hosts map[string][]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if !readYAML(path, &hosts) {
+		return nil
+	}
+	if entries, ok := hostEntry(hosts, host); ok {
+		for _, entry := range entries {
+			if entry.OAuthToken != "" {
+				return &Token{Value: entry.OAuthToken, Source: "hub config (" + path + ")"}
+			}
+		}
+	}
+	return nil
+}
+
+// glabToken reads the token glab stored in config.yml
+func glabToken(host string) *Token {
+	dir := configDir("GLAB_CONFIG_DIR", "glab-cli")
+	if dir == "" {
+		return nil
+	}
+	path := filepath.Join(dir, "config.yml")
+	var config struct {
+		Hosts map[string]struct {
+			Token string `yaml:"token"`
+		} `yaml:"hosts"`
+	}
+	if !readYAML(path, &config) {
+		return nil
+	}
+	if entry, ok := hostEntry(config.Hosts, host); ok && entry.Token != "" {
+		return &Token{Value: entry.Token, Source: "glab config (" + path + ")"}
+	}
+	return nil
+}
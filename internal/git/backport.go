@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// BackportCommits returns the commits of branch that are not in base, oldest
+// first, leaving out merge commits
+func BackportCommits(base, branch string) ([]string, error) {
+	output, err := command("rev-list", "--reverse", "--no-merges", base+".."+branch).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits of '%s' not in '%s': %w", branch, base, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// UnappliedCommits returns those of commits, a series oldest first, whose
+// changes are not in target yet, comparing patch IDs like git cherry does
+func UnappliedCommits(target string, commits []string) ([]string, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	args := []string{"cherry", target, commits[len(commits)-1], commits[0] + "^"}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare the commits with '%s': %w", target, err)
+	}
+	pending := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		if commit, ok := strings.CutPrefix(line, "+ "); ok {
+			pending[strings.TrimSpace(commit)] = true
+		}
+	}
+	var unapplied []string
+	for _, commit := range commits {
+		if pending[commit] {
+			unapplied = append(unapplied, commit)
+		}
+	}
+	return unapplied, nil
+}
+
+// CherryPickOnto creates branch at startPoint with commits cherry-picked onto
+// it, recording where each came from (-x). The cherry-picks run in a temporary
+// worktree, so the current checkout is left alone. When a commit does not
+// apply, the cherry-pick is aborted, branch is not created and the error is a
+// *errors.CherryPickConflictError. Cancelling ctx stops the cherry-picks and
+// removes the worktree.
+func CherryPickOnto(ctx context.Context, branch, startPoint string, commits []string) error {
+	dir, err := os.MkdirTemp("", "gitflow-backport-*")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary worktree: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if output, err := command("worktree", "add", "--detach", dir, startPoint).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out '%s' in a temporary worktree: %w\n%s", startPoint, err, strings.TrimSpace(string(output)))
+	}
+	defer command("worktree", "remove", "--force", dir).Run()
+
+	for _, commit := range commits {
+		output, err := localCommand(ctx, "-C", dir, "cherry-pick", "-x", commit).CombinedOutput()
+		if err == nil {
+			continue
+		}
+		conflict := &errors.CherryPickConflictError{Commit: commit, Target: startPoint}
+		if files, err := command("-C", dir, "diff", "--name-only", "--diff-filter=U").Output(); err == nil {
+			conflict.Files = strings.Fields(string(files))
+		}
+		command("-C", dir, "cherry-pick", "--abort").Run()
+		if len(conflict.Files) == 0 {
+			return fmt.Errorf("failed to cherry-pick %s onto '%s': %w\n%s", commit, startPoint, err, strings.TrimSpace(string(output)))
+		}
+		return conflict
+	}
+
+	head, err := command("-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("failed to resolve the backported commits: %w", err)
+	}
+	args := []string{"update-ref", "refs/heads/" + branch, strings.TrimSpace(string(head)), ""}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return commandError(args, fmt.Errorf("failed to create branch %s: %s", branch, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
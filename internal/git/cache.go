@@ -0,0 +1,364 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A single git-flow command asks Git the same questions many times: where the
+// git directory is, whether a branch exists and what a gitflow.* key holds.
+// Spawning a process for each answer is noticeable on Windows, so a Cache
+// answers them from state that lives as long as the Cache:
+//
+//   - the git directory, resolved once
+//   - a snapshot of the gitflow.* keys from 'git config --list -z', dropped
+//     whenever git-flow writes configuration or a hook script runs
+//   - a persistent 'git cat-file --batch-check' process for ref existence checks
+//   - the remote-tracking branches per remote, dropped whenever git-flow fetches
+//     from or pushes to a remote
+//
+// A Cache belongs to a single command, which creates it with NewCache and
+// closes it when it ends, so changes made with Git in between commands are
+// never missed. The package functions of the same names answer without a
+// cache. Cached state belongs to the working directory the Cache was used in
+// and is discarded when the working directory changes. Whenever the cache
+// cannot be used, a regular git process answers the question instead.
+
+// snapshotPrefix limits the config snapshot to keys only git-flow writes, so
+// changes made by Git itself (e.g. branch.* sections) can never be stale
+const snapshotPrefix = "gitflow."
+
+var errConfigNotSet = errors.New("key is not set")
+
+// configGeneration and remoteGeneration count the writes to the gitflow.*
+// configuration and the remote-tracking branches. A Cache drops what it holds
+// of either when the count changed since it was loaded, so a write by any
+// command of the process reaches the caches of all.
+var (
+	configGeneration atomic.Uint64
+	remoteGeneration atomic.Uint64
+)
+
+// noCache answers every question with a git process; the package functions
+// use it
+var noCache *Cache
+
+// Cache answers repeated Git queries of a command. It is safe for concurrent
+// use. A nil *Cache answers every question with a git process.
+type Cache struct {
+	mu               sync.Mutex
+	dir              string
+	gitDir           string
+	config           map[string]string
+	configGeneration uint64
+	refChecker       *batchCheck
+	refCheckerDown   bool
+	remoteBranches   map[string][]string
+	remoteGeneration uint64
+}
+
+// NewCache returns an empty cache for a command
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Close drops all cached state and stops the persistent helper process, if
+// one is running. The cache can be used again afterwards.
+func (c *Cache) Close() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+	c.dir = ""
+}
+
+// reset drops all cached state. The caller must hold c.mu.
+func (c *Cache) reset() {
+	c.gitDir = ""
+	c.config = nil
+	c.remoteBranches = nil
+	if c.refChecker != nil {
+		c.refChecker.close()
+		c.refChecker = nil
+	}
+	c.refCheckerDown = false
+}
+
+// refresh drops all cached state when the working directory changed, and the
+// configuration snapshot and remote branch lists when they were written since
+// they were loaded. The caller must hold c.mu.
+func (c *Cache) refresh() {
+	if wd, err := os.Getwd(); err == nil && wd != c.dir {
+		c.dir = wd
+		c.reset()
+	}
+	if c.configGeneration != configGeneration.Load() {
+		c.config = nil
+	}
+	if c.remoteGeneration != remoteGeneration.Load() {
+		c.remoteBranches = nil
+	}
+}
+
+// cachedGitDir returns the git directory, resolving it only once
+func (c *Cache) cachedGitDir() (string, error) {
+	if c == nil {
+		return resolveGitDir()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	if c.gitDir != "" {
+		return c.gitDir, nil
+	}
+	gitDir, err := resolveGitDir()
+	if err != nil {
+		return "", err
+	}
+	c.gitDir = gitDir
+	return gitDir, nil
+}
+
+// resolveGitDir asks Git for the git directory
+func resolveGitDir() (string, error) {
+	output, err := command("rev-parse", "--git-dir").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// InvalidateConfigSnapshot drops the cached configuration of every Cache so
+// the next read sees changes made outside git-flow, e.g. by hook scripts.
+func InvalidateConfigSnapshot() {
+	configGeneration.Add(1)
+}
+
+// cachedConfig returns the gitflow.* configuration snapshot, loading it on first use
+func (c *Cache) cachedConfig() (map[string]string, error) {
+	if c == nil {
+		return nil, errors.New("no command cache")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	if c.config != nil {
+		return c.config, nil
+	}
+	// A write while the snapshot is read makes it stale right away
+	generation := configGeneration.Load()
+	output, err := command("config", "--list", "-z").Output()
+	if err != nil {
+		return nil, err
+	}
+	c.config = parseConfigList(output, snapshotPrefix)
+	c.configGeneration = generation
+	return c.config, nil
+}
+
+// parseConfigList parses 'git config --list -z' output into a map, keeping
+// only keys with the given prefix. Entries are "key\nvalue" separated by NUL;
+// a key without a value has no newline. Later values win, like 'git config --get'.
+func parseConfigList(data []byte, prefix string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range strings.Split(string(data), "\x00") {
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "\n")
+		if strings.HasPrefix(key, prefix) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// canonicalConfigKey lowercases the section and variable name of a config key
+// the way 'git config --list' prints them; subsection names keep their case
+func canonicalConfigKey(key string) string {
+	first := strings.Index(key, ".")
+	last := strings.LastIndex(key, ".")
+	if first < 0 {
+		return strings.ToLower(key)
+	}
+	return strings.ToLower(key[:first]) + key[first:last] + strings.ToLower(key[last:])
+}
+
+// lookupConfig answers a config read from the snapshot. handled is false when
+// the key is not covered by the snapshot or the snapshot could not be loaded.
+func (c *Cache) lookupConfig(key string) (value string, handled bool, err error) {
+	canonical := canonicalConfigKey(key)
+	if !strings.HasPrefix(canonical, snapshotPrefix) {
+		return "", false, nil
+	}
+	snapshot, loadErr := c.cachedConfig()
+	if loadErr != nil {
+		return "", false, nil
+	}
+	value, ok := snapshot[canonical]
+	if !ok {
+		return "", true, errConfigNotSet
+	}
+	return strings.TrimSpace(value), true, nil
+}
+
+// lookupConfigRegexp answers a 'git config --get-regexp' read from the snapshot.
+// handled is false when the pattern may match keys outside the snapshot.
+func (c *Cache) lookupConfigRegexp(pattern string) (map[string]string, bool) {
+	if !strings.HasPrefix(pattern, "gitflow\\.") {
+		return nil, false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, false
+	}
+	snapshot, err := c.cachedConfig()
+	if err != nil {
+		return nil, false
+	}
+	result := make(map[string]string)
+	for key, value := range snapshot {
+		if re.MatchString(key) {
+			result[key] = value
+		}
+	}
+	return result, true
+}
+
+// cachedRemoteBranches returns the cached branch list of a remote, if any
+func (c *Cache) cachedRemoteBranches(remote string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	branches, ok := c.remoteBranches[remote]
+	return branches, ok
+}
+
+// storeRemoteBranches caches the branch list of a remote as listed when the
+// remote-tracking branches had been written generation times
+func (c *Cache) storeRemoteBranches(remote string, branches []string, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	if generation != remoteGeneration.Load() {
+		return
+	}
+	if c.remoteBranches == nil {
+		c.remoteBranches = make(map[string][]string)
+	}
+	c.remoteBranches[remote] = branches
+	c.remoteGeneration = generation
+}
+
+// invalidateRemoteBranches drops the cached remote branch lists of every Cache
+// after an operation that may have changed remote-tracking branches
+func invalidateRemoteBranches() {
+	remoteGeneration.Add(1)
+}
+
+// batchCheck is a persistent 'git cat-file --batch-check' process
+type batchCheck struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+// startBatchCheck starts a cat-file process that prints the object name of each
+// revision written to it, or "<rev> missing" when it does not resolve
+func startBatchCheck() (*batchCheck, error) {
+	cmd := command("cat-file", "--batch-check=%(objectname)")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &batchCheck{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// exists reports whether rev resolves to an object
+func (b *batchCheck) exists(rev string) (bool, error) {
+	// A line break would split the request; no revision contains one
+	if strings.ContainsAny(rev, "\n\r") {
+		return false, nil
+	}
+	if _, err := io.WriteString(b.stdin, rev+"\n"); err != nil {
+		return false, err
+	}
+	line, err := b.stdout.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	// Resolved revisions print only the object name; failures append a reason
+	return !strings.Contains(strings.TrimSpace(line), " "), nil
+}
+
+// close ends the cat-file process
+func (b *batchCheck) close() {
+	b.stdin.Close()
+	b.cmd.Wait()
+}
+
+// revisionExists reports whether rev resolves, using the persistent batch
+// process when possible and 'git rev-parse --verify' otherwise
+func (c *Cache) revisionExists(rev string) bool {
+	if c != nil {
+		if found, ok := c.checkRevision(rev); ok {
+			return found
+		}
+	}
+	return command("rev-parse", "--verify", "--quiet", rev).Run() == nil
+}
+
+// checkRevision asks the persistent batch process whether rev resolves,
+// starting it on first use. ok is false when the process cannot answer.
+func (c *Cache) checkRevision(rev string) (found bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+
+	if c.refChecker == nil && !c.refCheckerDown {
+		checker, err := startBatchCheck()
+		if err != nil {
+			c.refCheckerDown = true
+		} else {
+			c.refChecker = checker
+		}
+	}
+	if c.refChecker == nil {
+		return false, false
+	}
+	found, err := c.refChecker.exists(rev)
+	if err == nil {
+		return found, true
+	}
+	// The process died (e.g. outside a repository); stop using it
+	c.refChecker.close()
+	c.refChecker = nil
+	c.refCheckerDown = true
+	return false, false
+}
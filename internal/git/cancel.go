@@ -0,0 +1,38 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// cancelGracePeriod is how long a cancelled git process may take to clean up
+// (e.g. remove lock files) after being interrupted before it is killed
+const cancelGracePeriod = 5 * time.Second
+
+// commandContext creates a git command that is stopped when ctx is cancelled.
+// The process is interrupted first so it can exit cleanly and is killed if it
+// is still running after cancelGracePeriod.
+func commandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Cancel = func() error {
+		// Interrupt is not available on every platform; kill right away there
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = cancelGracePeriod
+	return cmd
+}
+
+// cancelledError reports ctx's error for a git operation stopped by cancellation.
+// It returns nil when ctx is still active, so the caller reports the git error.
+func cancelledError(ctx context.Context, operation string) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return fmt.Errorf("%s was cancelled: %w", operation, ctx.Err())
+}
@@ -0,0 +1,28 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+)
+
+// command creates a git command for a local operation that is not cancelled,
+// such as a query or a step that restores a consistent state
+func command(args ...string) *exec.Cmd {
+	return localCommand(context.Background(), args...)
+}
+
+// localCommand creates a git command for a local operation that is stopped
+// when ctx is cancelled. The command runs in a process group of its own, so
+// the SIGINT a terminal sends to its foreground process group on Ctrl-C
+// reaches git-flow but not git: git-flow lets the running step complete and
+// stops after it, instead of leaving a merge, rebase or checkout half done.
+//
+// Commands that may need the terminal, such as fetches and pushes asking for
+// credentials or a rebase opening the editor, are created with commandContext
+// alone instead. A process outside the foreground process group is stopped
+// when it reads from the terminal.
+func localCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := commandContext(ctx, args...)
+	detachFromTerminalSignals(cmd)
+	return cmd
+}
@@ -0,0 +1,8 @@
+//go:build !unix && !windows
+
+package git
+
+import "os/exec"
+
+// detachFromTerminalSignals does nothing on platforms without process groups
+func detachFromTerminalSignals(cmd *exec.Cmd) {}
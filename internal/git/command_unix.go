@@ -0,0 +1,13 @@
+//go:build unix
+
+package git
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachFromTerminalSignals starts cmd in a new process group
+func detachFromTerminalSignals(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package git
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachFromTerminalSignals starts cmd in a new process group, which the
+// console does not send Ctrl-C to
+func detachFromTerminalSignals(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
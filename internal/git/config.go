@@ -2,7 +2,7 @@ package git
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
 	"strings"
 )
 
@@ -24,7 +24,19 @@ const (
 
 // GetConfig gets a Git config value
 func GetConfig(key string) (string, error) {
-	cmd := exec.Command("git", "config", "--get", key)
+	return noCache.GetConfig(key)
+}
+
+// GetConfig gets a Git config value, reading gitflow.* keys from the snapshot
+func (c *Cache) GetConfig(key string) (string, error) {
+	if value, handled, err := c.lookupConfig(key); handled {
+		if err != nil {
+			return "", fmt.Errorf("failed to get git config %s: %w", key, err)
+		}
+		return value, nil
+	}
+
+	cmd := command("config", "--get", key)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get git config %s: %w", key, err)
@@ -39,7 +51,7 @@ func GetConfigAllValues(key string) ([]string, error) {
 
 // GetConfigAllValuesInDir gets all values for a multi-value Git config key in the specified directory
 func GetConfigAllValuesInDir(dir, key string) ([]string, error) {
-	cmd := exec.Command("git", "config", "--get-all", key)
+	cmd := command("config", "--get-all", key)
 	if dir != "" {
 		cmd.Dir = dir
 	}
@@ -64,7 +76,12 @@ func GetConfigAllValuesInDir(dir, key string) ([]string, error) {
 
 // GetConfigInDir gets a Git config value in the specified directory
 func GetConfigInDir(dir, key string) (string, error) {
-	cmd := exec.Command("git", "config", "--get", key)
+	// The working directory is served from the config snapshot
+	if wd, err := os.Getwd(); err == nil && wd == dir {
+		return GetConfig(key)
+	}
+
+	cmd := command("config", "--get", key)
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -75,7 +92,9 @@ func GetConfigInDir(dir, key string) (string, error) {
 
 // SetConfig sets a Git config value
 func SetConfig(key string, value string) error {
-	cmd := exec.Command("git", "config", key, value)
+	defer InvalidateConfigSnapshot()
+
+	cmd := command("config", key, value)
 	_, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to set git config %s: %w", key, err)
@@ -85,7 +104,9 @@ func SetConfig(key string, value string) error {
 
 // UnsetConfigSection removes all Git config values matching a pattern
 func UnsetConfigSection(pattern string) error {
-	cmd := exec.Command("git", "config", "--remove-section", pattern)
+	defer InvalidateConfigSnapshot()
+
+	cmd := command("config", "--remove-section", pattern)
 	_, err := cmd.Output()
 	if err != nil {
 		// Don't treat "section not found" as an error
@@ -99,7 +120,17 @@ func UnsetConfigSection(pattern string) error {
 
 // GetAllConfig gets all Git config values matching a pattern
 func GetAllConfig(pattern string) (map[string]string, error) {
-	cmd := exec.Command("git", "config", "--get-regexp", pattern)
+	return noCache.GetAllConfig(pattern)
+}
+
+// GetAllConfig gets all Git config values matching a pattern, reading those
+// of gitflow.* patterns from the snapshot
+func (c *Cache) GetAllConfig(pattern string) (map[string]string, error) {
+	if values, handled := c.lookupConfigRegexp(pattern); handled {
+		return values, nil
+	}
+
+	cmd := command("config", "--get-regexp", pattern)
 	output, err := cmd.Output()
 	if err != nil {
 		// If no config values match, don't treat it as an error
@@ -126,7 +157,9 @@ func GetAllConfig(pattern string) (map[string]string, error) {
 
 // UnsetConfig unsets a Git config value
 func UnsetConfig(key string) error {
-	cmd := exec.Command("git", "config", "--unset", key)
+	defer InvalidateConfigSnapshot()
+
+	cmd := command("config", "--unset", key)
 	_, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to unset git config %s: %w", key, err)
@@ -151,7 +184,7 @@ func GetConfigWithScope(key string, scope ConfigScope, filePath string) (string,
 		// ConfigScopeDefault: no flag = merged config
 	}
 	args = append(args, "--get", key)
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get git config %s: %w", key, err)
@@ -162,6 +195,8 @@ func GetConfigWithScope(key string, scope ConfigScope, filePath string) (string,
 // SetConfigWithScope sets a Git config value at a specific scope.
 // For ConfigScopeDefault, writes to local (git's standard behavior).
 func SetConfigWithScope(key, value string, scope ConfigScope, filePath string) error {
+	defer InvalidateConfigSnapshot()
+
 	args := []string{"config"}
 	switch scope {
 	case ConfigScopeLocal:
@@ -175,7 +210,7 @@ func SetConfigWithScope(key, value string, scope ConfigScope, filePath string) e
 		// ConfigScopeDefault: no flag = local (git's default for writes)
 	}
 	args = append(args, key, value)
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	_, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to set git config %s: %w", key, err)
@@ -185,6 +220,8 @@ func SetConfigWithScope(key, value string, scope ConfigScope, filePath string) e
 
 // UnsetConfigWithScope unsets a Git config value at a specific scope.
 func UnsetConfigWithScope(key string, scope ConfigScope, filePath string) error {
+	defer InvalidateConfigSnapshot()
+
 	args := []string{"config"}
 	switch scope {
 	case ConfigScopeLocal:
@@ -198,7 +235,7 @@ func UnsetConfigWithScope(key string, scope ConfigScope, filePath string) error
 		// ConfigScopeDefault: no flag = local (git's default for writes)
 	}
 	args = append(args, "--unset", key)
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	_, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to unset git config %s: %w", key, err)
@@ -217,3 +254,71 @@ func SetBaseBranch(branchName, baseBranch string) error {
 	configKey := fmt.Sprintf("gitflow.branch.%s.base", branchName)
 	return SetConfig(configKey, baseBranch)
 }
+
+// ReleaseCandidateSuffix separates the release tag from the release candidate number,
+// as in v1.2.0-rc.3
+const ReleaseCandidateSuffix = "-rc."
+
+// ReleaseCandidate is a release candidate tag recorded for a release branch
+type ReleaseCandidate struct {
+	Tag    string
+	Commit string
+}
+
+// ReleaseCandidates returns the release candidates recorded for a branch,
+// oldest first
+func ReleaseCandidates(branchName string) ([]ReleaseCandidate, error) {
+	values, err := GetConfigAllValues(fmt.Sprintf("gitflow.branch.%s.rc", branchName))
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]ReleaseCandidate, 0, len(values))
+	for _, value := range values {
+		tag, commit, _ := strings.Cut(value, " ")
+		candidates = append(candidates, ReleaseCandidate{Tag: tag, Commit: commit})
+	}
+	return candidates, nil
+}
+
+// AddReleaseCandidate records a release candidate tag of a branch
+func AddReleaseCandidate(branchName string, candidate ReleaseCandidate) error {
+	defer InvalidateConfigSnapshot()
+
+	key := fmt.Sprintf("gitflow.branch.%s.rc", branchName)
+	cmd := command("config", "--add", key, candidate.Tag+" "+candidate.Commit)
+	if _, err := cmd.Output(); err != nil {
+		return fmt.Errorf("failed to record release candidate %s: %w", candidate.Tag, err)
+	}
+	return nil
+}
+
+// ClearReleaseCandidates removes the release candidates recorded for a branch
+func ClearReleaseCandidates(branchName string) error {
+	defer InvalidateConfigSnapshot()
+
+	key := fmt.Sprintf("gitflow.branch.%s.rc", branchName)
+	cmd := command("config", "--unset-all", key)
+	if _, err := cmd.Output(); err != nil {
+		// Exit status 5: nothing was recorded
+		if strings.Contains(err.Error(), "exit status 5") {
+			return nil
+		}
+		return fmt.Errorf("failed to unset git config %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetBranchDescription returns the description of a branch, or "" if it has none
+func GetBranchDescription(branchName string) string {
+	description, err := GetConfig(fmt.Sprintf("branch.%s.description", branchName))
+	if err != nil {
+		return ""
+	}
+	return description
+}
+
+// SetBranchDescription stores the description Git shows for a branch, as
+// git branch --edit-description does
+func SetBranchDescription(branchName, description string) error {
+	return SetConfig(fmt.Sprintf("branch.%s.description", branchName), description)
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sides of a conflict as seen by the merge that stopped: "ours" is the checked
+// out branch (stage 2), "theirs" the branch being merged (stage 3)
+const (
+	ConflictSideOurs   = "ours"
+	ConflictSideTheirs = "theirs"
+)
+
+// UnmergedFiles returns the paths with unresolved conflicts, relative to the
+// root of the working tree
+func UnmergedFiles() ([]string, error) {
+	output, err := command("diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unmerged files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ResolveConflictFavoring resolves the conflict in path like "git merge -X
+// <side>" would: hunks changed on both sides take side's version, all other
+// changes of both sides are kept. When one side deleted the file, side's
+// choice wins. The resolution is staged. path is relative to the root of the
+// working tree.
+func ResolveConflictFavoring(path, side string) error {
+	if side != ConflictSideOurs && side != ConflictSideTheirs {
+		return fmt.Errorf("invalid conflict side '%s'", side)
+	}
+	root, err := GetTopLevelDir()
+	if err != nil {
+		return err
+	}
+	run := func(args ...string) ([]byte, error) {
+		cmd := command(args...)
+		cmd.Dir = root
+		return cmd.Output()
+	}
+
+	// Each line is "<mode> <object> <stage>\t<path>"
+	output, err := run("ls-files", "-u", "--", path)
+	if err != nil {
+		return fmt.Errorf("failed to read conflict stages of '%s': %w", path, err)
+	}
+	stages := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 3 {
+			stages[fields[2]] = true
+		}
+	}
+
+	keep, other := "2", "3"
+	if side == ConflictSideTheirs {
+		keep, other = "3", "2"
+	}
+	switch {
+	case !stages[keep]:
+		// The favored side deleted the file
+		if _, err := run("rm", "--quiet", "--", path); err != nil {
+			return fmt.Errorf("failed to remove '%s': %w", path, err)
+		}
+		return nil
+	case !stages[other]:
+		// The other side deleted the file; keep the favored version
+		if _, err := run("checkout", "--"+side, "--", path); err != nil {
+			return fmt.Errorf("failed to check out %s version of '%s': %w", side, path, err)
+		}
+		if _, err := run("add", "--", path); err != nil {
+			return fmt.Errorf("failed to stage '%s': %w", path, err)
+		}
+		return nil
+	}
+
+	// Both sides changed the file: merge it again, favoring side in conflicting hunks
+	tmpDir, err := os.MkdirTemp("", "git-flow-conflict-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	versions := make(map[string]string)
+	for _, stage := range []string{"1", "2", "3"} {
+		file := filepath.Join(tmpDir, stage)
+		var content []byte
+		if stages[stage] {
+			if content, err = run("show", ":"+stage+":"+path); err != nil {
+				return fmt.Errorf("failed to read stage %s of '%s': %w", stage, path, err)
+			}
+		}
+		if err := os.WriteFile(file, content, 0600); err != nil {
+			return fmt.Errorf("failed to write temporary file: %w", err)
+		}
+		versions[stage] = file
+	}
+
+	merged, err := run("merge-file", "-p", "--"+side, versions["2"], versions["1"], versions["3"])
+	if err != nil {
+		return fmt.Errorf("failed to merge '%s': %w", path, err)
+	}
+
+	target := filepath.Join(root, path)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(target); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(target, merged, mode); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	if _, err := run("add", "--", path); err != nil {
+		return fmt.Errorf("failed to stage '%s': %w", path, err)
+	}
+	return nil
+}
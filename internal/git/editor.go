@@ -0,0 +1,67 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GetEditor returns the editor Git uses for messages: GIT_EDITOR, core.editor,
+// VISUAL, EDITOR or the compiled-in default, in that order
+func GetEditor() (string, error) {
+	output, err := command("var", "GIT_EDITOR").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the editor: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// EditMessage opens template in the editor, like git commit does, and returns
+// the edited message with comment lines and surrounding blank lines removed.
+// The message file is written to the git directory as name, e.g. TAG_EDITMSG.
+func EditMessage(name, template string) (string, error) {
+	editor, err := GetEditor()
+	if err != nil {
+		return "", err
+	}
+	gitDir, err := GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(gitDir, name)
+	if err := os.WriteFile(path, []byte(template), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer os.Remove(path)
+
+	// Like Git, run the editor through the shell so it may carry arguments
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return CleanupMessage(string(edited)), nil
+}
+
+// CleanupMessage removes comment lines starting with '#', trailing whitespace
+// and leading and trailing blank lines from a message
+func CleanupMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
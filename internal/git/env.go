@@ -0,0 +1,31 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// pathEnv lists the environment variables holding paths that Git resolves
+// against the current directory
+var pathEnv = []string{"GIT_DIR", "GIT_WORK_TREE", "GIT_COMMON_DIR", "GIT_INDEX_FILE"}
+
+// AbsolutizePathEnv makes the paths in GIT_DIR, GIT_WORK_TREE, GIT_COMMON_DIR
+// and GIT_INDEX_FILE absolute. 'git --git-dir=<dir> flow' passes a relative
+// path on as is, which would point elsewhere for the Git processes and hooks
+// git-flow starts in another directory, e.g. the root of the working tree.
+func AbsolutizePathEnv() error {
+	for _, name := range pathEnv {
+		value := os.Getenv(name)
+		if value == "" || filepath.IsAbs(value) {
+			continue
+		}
+		abs, err := filepath.Abs(value)
+		if err != nil {
+			return err
+		}
+		if err := os.Setenv(name, abs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
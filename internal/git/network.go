@@ -0,0 +1,115 @@
+package git
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// RetryPolicy controls how fetches and pushes retry failures that may be
+// transient, such as timeouts and server errors
+type RetryPolicy struct {
+	Retries int           // retries after the first attempt
+	Delay   time.Duration // wait before the first retry, doubled for each further one
+	// OnRetry, if set, is called before waiting for a retry
+	OnRetry func(err *errors.RemoteError, delay time.Duration)
+}
+
+// DefaultRetryPolicy retries twice, after one and two seconds
+var DefaultRetryPolicy = RetryPolicy{Retries: 2, Delay: time.Second}
+
+// retryPolicyKey is the context key of the retry policy
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a copy of ctx that makes the fetches and pushes run
+// with it retry transient failures as policy allows
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// retryPolicyOf returns the retry policy of ctx, DefaultRetryPolicy if it has none
+func retryPolicyOf(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// remoteFailures map what git prints when it cannot talk to a remote to the
+// kind of failure. Kinds are tried in order, so a proxy failure that also
+// mentions a refused connection is reported as a proxy failure.
+var remoteFailures = []struct {
+	kind     string
+	patterns []string
+}{
+	{errors.RemoteProxyFailed, []string{"proxy connect aborted", "could not resolve proxy", "received http code 407", "proxy authentication required"}},
+	{errors.RemoteAuthFailed, []string{"permission denied (publickey", "authentication failed", "could not read username", "could not read password", "terminal prompts disabled", "invalid username or password", "host key verification failed", "returned error: 401", "returned error: 403"}},
+	{errors.RemoteNotFound, []string{"repository not found", "does not appear to be a git repository", "returned error: 404"}},
+	{errors.RemoteServerFailed, []string{"returned error: 5", "rpc failed", "internal server error", "the remote end hung up unexpectedly", "early eof"}},
+	{errors.RemoteUnreachable, []string{"could not resolve host", "connection refused", "connection timed out", "operation timed out", "network is unreachable", "no route to host", "connection reset", "failed to connect to", "couldn't connect to server", "temporary failure in name resolution", "name or service not known"}},
+}
+
+// classifyRemoteFailure returns the kind of remote failure git's output
+// describes, or "" for other failures. A push the remote rejected is not a
+// failure to talk to it.
+func classifyRemoteFailure(output string) string {
+	lower := strings.ToLower(output)
+	for _, line := range strings.Split(output, "\n") {
+		if pushRejection.MatchString(strings.TrimRight(line, " \t\r")) {
+			return ""
+		}
+	}
+	for _, failure := range remoteFailures {
+		for _, pattern := range failure.patterns {
+			if strings.Contains(lower, pattern) {
+				return failure.kind
+			}
+		}
+	}
+	return ""
+}
+
+// runRemote runs a git command that talks to remote, retrying transient
+// failures with exponential backoff as the retry policy of ctx allows. When git
+// could not talk to the remote, the error is a RemoteError; operation
+// describes the command for it, e.g. "fetch from". Failures of remotes that
+// are not configured are returned as they are.
+func runRemote(ctx context.Context, remote, operation string, args []string) ([]byte, error) {
+	retryPolicy := retryPolicyOf(ctx)
+	delay := retryPolicy.Delay
+	for attempt := 1; ; attempt++ {
+		output, err := commandContext(ctx, args...).CombinedOutput()
+		if err == nil || ctx.Err() != nil {
+			return output, err
+		}
+		// Without the remote configured, git takes its name for a path
+		url, urlErr := GetRemoteURL(remote)
+		kind := classifyRemoteFailure(string(output))
+		if kind == "" || urlErr != nil {
+			return output, err
+		}
+
+		remoteErr := &errors.RemoteError{
+			Remote:    remote,
+			URL:       url,
+			Operation: operation,
+			Kind:      kind,
+			Output:    strings.TrimSpace(string(output)),
+			Attempts:  attempt,
+		}
+		if !remoteErr.Transient() || attempt > retryPolicy.Retries {
+			return output, remoteErr
+		}
+		if retryPolicy.OnRetry != nil {
+			retryPolicy.OnRetry(remoteErr, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return output, remoteErr
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
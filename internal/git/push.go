@@ -0,0 +1,122 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+// pushRejection matches the lines git push prints for refs it did not update,
+// e.g. " ! [remote rejected] develop -> develop (pre-receive hook declined)"
+var pushRejection = regexp.MustCompile(`^\s*!\s+\[(remote rejected|rejected)\]\s+(\S+)\s+->\s+(\S+)\s+\((.*)\)$`)
+
+// pushError describes a failed push. When git reports refs it did not update,
+// the error is a PushRejectedError holding them and the lines the remote
+// printed. A RemoteError from runRemote is kept; otherwise the error is
+// failure followed by the output of git.
+func pushError(args []string, remote string, failure string, output []byte, err error) error {
+	if errors.RemoteFailure(err) != nil {
+		return commandError(args, err)
+	}
+	rejected := &errors.PushRejectedError{Remote: remote}
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.HasPrefix(line, "remote:") {
+			if strings.TrimSpace(strings.TrimPrefix(line, "remote:")) != "" {
+				rejected.Messages = append(rejected.Messages, line)
+			}
+			continue
+		}
+		match := pushRejection.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		ref := match[2]
+		if match[3] != match[2] {
+			ref += " -> " + match[3]
+		}
+		rejected.Refs = append(rejected.Refs, errors.RejectedRef{Ref: ref, Reason: match[4], Remote: match[1] == "remote rejected"})
+	}
+	if len(rejected.Refs) == 0 {
+		return commandError(args, fmt.Errorf("%s: %s", failure, strings.TrimSpace(string(output))))
+	}
+	return commandError(args, rejected)
+}
+
+// branchRefspec returns the refspec pushing branch to remoteBranch
+func branchRefspec(branch, remoteBranch string) string {
+	if remoteBranch == "" || remoteBranch == branch {
+		return branch
+	}
+	return branch + ":refs/heads/" + remoteBranch
+}
+
+// PushDestination returns the branch of remote a push of branch updates,
+// following push.default like git push without a refspec does: with upstream
+// (or tracking), a branch that tracks a branch of remote is pushed to it, and
+// with simple, pushing it to a tracked branch of another name is refused.
+// Otherwise, and when push.default is not set, the branch of the same name
+// is updated.
+func PushDestination(branch, remote string) (string, error) {
+	upstreamRemote, _ := GetConfig("branch." + branch + ".remote")
+	merge, _ := GetConfig("branch." + branch + ".merge")
+	tracked := strings.TrimPrefix(merge, "refs/heads/")
+	if upstreamRemote != remote || tracked == "" || tracked == branch {
+		return branch, nil
+	}
+
+	mode, _ := GetConfig("push.default")
+	switch strings.ToLower(mode) {
+	case "upstream", "tracking":
+		return tracked, nil
+	case "simple":
+		return "", fmt.Errorf("'%s' tracks '%s/%s', which has another name, and push.default is 'simple'; set push.default to 'upstream' to push to the tracked branch", branch, remote, tracked)
+	}
+	return branch, nil
+}
+
+// PushExclusion returns the negative refspec of remote.<remote>.push, such as
+// ^refs/heads/private/*, that excludes ref, a full ref name, from pushes to
+// remote, or "" if none does
+func PushExclusion(remote, ref string) string {
+	refspecs, _ := GetConfigAllValues("remote." + remote + ".push")
+	for _, refspec := range refspecs {
+		pattern, negative := strings.CutPrefix(strings.TrimSpace(refspec), "^")
+		if negative && refspecMatches(pattern, ref) {
+			return refspec
+		}
+	}
+	return ""
+}
+
+// refspecMatches reports whether the source of a refspec matches ref. A *
+// matches any sequence of characters, and a source not starting with refs/
+// names a branch.
+func refspecMatches(pattern, ref string) bool {
+	if !strings.HasPrefix(pattern, "refs/") {
+		pattern = "refs/heads/" + pattern
+	}
+	prefix, suffix, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return pattern == ref
+	}
+	return len(ref) >= len(prefix)+len(suffix) && strings.HasPrefix(ref, prefix) && strings.HasSuffix(ref, suffix)
+}
+
+// OnlyRebasedCommits reports whether every commit of upstream that branch
+// lacks has a patch-equivalent commit in branch, as after rebasing branch.
+// Replacing upstream with branch then loses no changes.
+func OnlyRebasedCommits(branch, upstream string) bool {
+	output, err := command("cherry", branch, upstream).Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "+") {
+			return false
+		}
+	}
+	return true
+}
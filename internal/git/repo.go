@@ -1,11 +1,17 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/gittower/git-flow-next/internal/errors"
 )
 
 // BranchSyncStatus represents the sync status between a local branch and its remote tracking branch
@@ -24,9 +30,15 @@ const (
 	SyncStatusNoTracking BranchSyncStatus = "no_tracking"
 )
 
+// commandError records the git invocation that failed with err, so the CLI can
+// report the command along with the message
+func commandError(args []string, err error) error {
+	return &errors.CommandError{Args: args, Err: err}
+}
+
 // IsGitRepo checks if the current directory is a Git repository
 func IsGitRepo() bool {
-	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd := command("rev-parse", "--is-inside-work-tree")
 	err := cmd.Run()
 	return err == nil
 }
@@ -35,12 +47,17 @@ func IsGitRepo() bool {
 // For regular repositories, this returns ".git".
 // For worktrees, this returns the actual git directory path (e.g., "/repo/.git/worktrees/work1").
 func GetGitDir() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	output, err := cmd.Output()
+	return noCache.GetGitDir()
+}
+
+// GetGitDir returns the git directory like the package function, resolving it
+// only once
+func (c *Cache) GetGitDir() (string, error) {
+	gitDir, err := c.cachedGitDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get git directory: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return gitDir, nil
 }
 
 // GetCurrentBranch returns the current Git branch
@@ -56,7 +73,7 @@ func GetCurrentBranch() (string, error) {
 		return "", nil
 	}
 
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := command("rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
@@ -64,10 +81,30 @@ func GetCurrentBranch() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// IsDetachedHead reports whether HEAD points at a commit rather than a branch.
+// An unborn branch in a repository without commits is not detached.
+func IsDetachedHead() bool {
+	return command("symbolic-ref", "-q", "HEAD").Run() != nil
+}
+
+// RemoteDefaultBranch returns the branch the remote's HEAD points at, as
+// recorded by clone or 'git remote set-head', or "" when it is not known
+func RemoteDefaultBranch(remote string) string {
+	output, err := command("symbolic-ref", "-q", "--short", "refs/remotes/"+remote+"/HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), remote+"/")
+}
+
 // BranchExists checks if a branch exists
 func BranchExists(branch string) error {
-	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
-	if err := cmd.Run(); err != nil {
+	return noCache.BranchExists(branch)
+}
+
+// BranchExists checks if a branch exists with the persistent ref check
+func (c *Cache) BranchExists(branch string) error {
+	if !c.revisionExists("refs/heads/" + branch) {
 		return fmt.Errorf("branch '%s' does not exist", branch)
 	}
 	return nil
@@ -75,8 +112,13 @@ func BranchExists(branch string) error {
 
 // BranchOrCommitExists checks if a branch, tag, or commit exists
 func BranchOrCommitExists(ref string) error {
-	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref)
-	if err := cmd.Run(); err != nil {
+	return noCache.BranchOrCommitExists(ref)
+}
+
+// BranchOrCommitExists checks if a branch, tag, or commit exists with the
+// persistent ref check
+func (c *Cache) BranchOrCommitExists(ref string) error {
+	if !c.revisionExists(ref) {
 		return fmt.Errorf("reference '%s' does not exist", ref)
 	}
 	return nil
@@ -84,6 +126,16 @@ func BranchOrCommitExists(ref string) error {
 
 // CreateBranch creates a new branch
 func CreateBranch(name string, startPoint string) error {
+	return createBranch(name, startPoint)
+}
+
+// CreateBranchNoTrack creates and checks out a new branch like CreateBranch,
+// without setting up tracking when startPoint is a remote-tracking branch
+func CreateBranchNoTrack(name string, startPoint string) error {
+	return createBranch(name, startPoint, "--no-track")
+}
+
+func createBranch(name string, startPoint string, options ...string) error {
 	// Check if we have any commits
 	hasCommits, err := HasCommits()
 	if err != nil {
@@ -108,20 +160,23 @@ func CreateBranch(name string, startPoint string) error {
 		startPoint = currentBranch
 	}
 
-	cmd := exec.Command("git", "checkout", "-b", name, startPoint)
+	args := append([]string{"checkout", "-b", name}, options...)
+	args = append(args, startPoint)
+	cmd := command(args...)
 	_, err = cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to create branch: %w", err)
+		return commandError(args, fmt.Errorf("failed to create branch: %w", err))
 	}
 	return nil
 }
 
 // Checkout checks out a branch
 func Checkout(branch string) error {
-	cmd := exec.Command("git", "checkout", branch)
+	args := []string{"checkout", branch}
+	cmd := command(args...)
 	_, err := cmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to checkout branch: %w", err)
+		return commandError(args, fmt.Errorf("failed to checkout branch: %w", err))
 	}
 	return nil
 }
@@ -133,76 +188,73 @@ func DeleteBranch(branch string, force bool) error {
 		flag = "-D"
 	}
 
-	cmd := exec.Command("git", "branch", flag, branch)
+	args := []string{"branch", flag, branch}
+	cmd := command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to delete branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to delete branch: %s", string(output)))
 	}
 	return nil
 }
 
 // HasCommits checks if the repository has any commits
 func HasCommits() (bool, error) {
-	cmd := exec.Command("git", "rev-parse", "--verify", "HEAD")
-	err := cmd.Run()
-	if err != nil {
-		// If error, there are no commits
-		return false, nil
-	}
-	return true, nil
+	// If HEAD does not resolve, there are no commits
+	return noCache.revisionExists("HEAD"), nil
 }
 
-// CreateInitialCommit creates an initial commit and branch
+// CreateInitialCommit creates an empty root commit on branch and points HEAD
+// at it. The index and working tree are left alone, so files the user has
+// staged stay staged and are not part of the commit.
 func CreateInitialCommit(branch string) error {
-	// Create a README.md file if it doesn't exist
-	if _, err := os.Stat("README.md"); os.IsNotExist(err) {
-		content := fmt.Sprintf("# Git Flow Repository\n\nThis repository is using git-flow with the following branches:\n- %s: Production releases\n- develop: Development\n", branch)
-		err = os.WriteFile("README.md", []byte(content), 0644)
-		if err != nil {
-			return fmt.Errorf("failed to create README.md: %w", err)
-		}
+	// The empty tree is written through mktree so it exists in any object format
+	args := []string{"mktree"}
+	cmd := command(args...)
+	cmd.Stdin = strings.NewReader("")
+	output, err := cmd.Output()
+	if err != nil {
+		return commandError(args, fmt.Errorf("failed to create empty tree: %w", err))
 	}
+	tree := strings.TrimSpace(string(output))
 
-	// Add the file to git
-	cmd := exec.Command("git", "add", "README.md")
-	_, err := cmd.Output()
+	args = []string{"commit-tree", tree, "-m", "Initial commit"}
+	cmd = command(args...)
+	output, err = cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to add README.md: %w", err)
+		return commandError(args, fmt.Errorf("failed to create initial commit: %s", strings.TrimSpace(string(output))))
 	}
+	commit := strings.TrimSpace(string(output))
 
-	// Create the initial commit
-	cmd = exec.Command("git", "commit", "-m", "Initial commit")
-	_, err = cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to create initial commit: %w", err)
+	ref := "refs/heads/" + branch
+	args = []string{"update-ref", ref, commit, ""}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return commandError(args, fmt.Errorf("failed to create branch %s: %s", branch, strings.TrimSpace(string(output))))
 	}
 
-	// Create the branch (it will be created automatically as the first branch)
-	cmd = exec.Command("git", "branch", "-m", branch)
-	_, err = cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to rename branch to %s: %w", branch, err)
+	args = []string{"symbolic-ref", "HEAD", ref}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return commandError(args, fmt.Errorf("failed to switch to %s: %s", branch, strings.TrimSpace(string(output))))
 	}
 
 	return nil
 }
 
 // Merge merges a branch into the current branch
-func Merge(branch string, noVerify bool) error {
+func Merge(ctx context.Context, branch string, noVerify bool) error {
 	args := []string{"merge", "--no-ff"}
 	if noVerify {
 		args = append(args, "--no-verify")
 	}
 	args = append(args, branch)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 
 	// Check for merge conflicts - Git returns exit code 1 and specific output patterns
 	if err != nil {
 		// Check if there are unmerged paths (conflicts)
-		conflictCmd := exec.Command("git", "ls-files", "--unmerged")
+		conflictCmd := command("ls-files", "--unmerged")
 		conflictOutput, _ := conflictCmd.Output()
 
 		if len(conflictOutput) > 0 ||
@@ -210,42 +262,48 @@ func Merge(branch string, noVerify bool) error {
 			strings.Contains(outputStr, "CONFLICT") ||
 			strings.Contains(outputStr, "merge failed") ||
 			strings.Contains(outputStr, "needs merge") {
-			return fmt.Errorf("merge conflict: %s", outputStr)
+			return commandError(args, fmt.Errorf("merge conflict: %s", outputStr))
 		}
-		return fmt.Errorf("failed to merge branch: %s", outputStr)
+		return commandError(args, fmt.Errorf("failed to merge branch: %s", outputStr))
 	}
 
 	return nil
 }
 
 // Rebase rebases the current branch onto another branch
-func Rebase(branch string) error {
-	cmd := exec.Command("git", "rebase", branch)
+func Rebase(ctx context.Context, branch string, noVerify bool) error {
+	args := []string{"rebase"}
+	if noVerify {
+		args = append(args, "--no-verify")
+	}
+	args = append(args, branch)
+
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if strings.Contains(string(output), "conflict") {
-			return fmt.Errorf("rebase conflict: %s", string(output))
+			return commandError(args, fmt.Errorf("rebase conflict: %s", string(output)))
 		}
-		return fmt.Errorf("failed to rebase branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to rebase branch: %s", string(output)))
 	}
 	return nil
 }
 
 // SquashMerge performs a squash merge of a branch into the current branch
-func SquashMerge(branch string, noVerify bool) error {
+func SquashMerge(ctx context.Context, branch string, noVerify bool) error {
 	args := []string{"merge", "--squash"}
 	if noVerify {
 		args = append(args, "--no-verify")
 	}
 	args = append(args, branch)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if strings.Contains(string(output), "conflict") {
-			return fmt.Errorf("squash merge conflict: %s", string(output))
+			return commandError(args, fmt.Errorf("squash merge conflict: %s", string(output)))
 		}
-		return fmt.Errorf("failed to squash merge branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to squash merge branch: %s", string(output)))
 	}
 
 	// Commit the squashed changes
@@ -253,10 +311,10 @@ func SquashMerge(branch string, noVerify bool) error {
 	if noVerify {
 		commitArgs = append(commitArgs, "--no-verify")
 	}
-	cmd = exec.Command("git", commitArgs...)
+	cmd = localCommand(ctx, commitArgs...)
 	output, err = cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to commit squashed changes: %s", string(output))
+		return commandError(commitArgs, fmt.Errorf("failed to commit squashed changes: %s", string(output)))
 	}
 
 	return nil
@@ -264,7 +322,7 @@ func SquashMerge(branch string, noVerify bool) error {
 
 // ListBranches returns a list of all branches in the repository
 func ListBranches() ([]string, error) {
-	cmd := exec.Command("git", "branch", "--format=%(refname:short)")
+	cmd := command("branch", "--format=%(refname:short)")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list branches: %w", err)
@@ -284,7 +342,7 @@ func ListBranches() ([]string, error) {
 // HasConflicts checks if there are unresolved conflicts
 func HasConflicts() bool {
 	// Check for unmerged paths
-	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd := command("diff", "--name-only", "--diff-filter=U")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -294,19 +352,41 @@ func HasConflicts() bool {
 
 // MergeAbort aborts the current merge
 func MergeAbort() error {
-	cmd := exec.Command("git", "merge", "--abort")
+	args := []string{"merge", "--abort"}
+	cmd := command(args...)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to abort merge: %w", err)
+		return commandError(args, fmt.Errorf("failed to abort merge: %w", err))
 	}
 	return nil
 }
 
 // RebaseAbort aborts the current rebase
 func RebaseAbort() error {
-	cmd := exec.Command("git", "rebase", "--abort")
+	args := []string{"rebase", "--abort"}
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(args, fmt.Errorf("failed to abort rebase: %s", string(output)))
+	}
+	return nil
+}
+
+// UndoStoppedOperation undoes what a git process stopped halfway left behind:
+// it aborts a rebase in progress and otherwise resets the index and the files
+// of a merge, squash merge or cherry-pick, keeping unrelated local changes
+func UndoStoppedOperation() error {
+	operation, err := GetOperationInProgress()
+	if err != nil {
+		return err
+	}
+	if operation == "rebase" {
+		return RebaseAbort()
+	}
+	args := []string{"reset", "--merge"}
+	cmd := command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to abort rebase: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to reset the stopped operation: %s", string(output)))
 	}
 	return nil
 }
@@ -316,40 +396,136 @@ func RebaseAbort() error {
 func RenameBranch(oldBranch, newBranch string) error {
 	args := []string{"branch", "-m", oldBranch, newBranch}
 
-	cmd := exec.Command("git", args...)
+	cmd := command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to rename branch: %s", strings.TrimSpace(string(output)))
+		return commandError(args, fmt.Errorf("failed to rename branch: %s", strings.TrimSpace(string(output))))
 	}
 	return nil
 }
 
-// Fetch performs a git fetch from the specified remote
-func Fetch(remote string) error {
-	cmd := exec.Command("git", "fetch", remote)
-	output, err := cmd.CombinedOutput()
+// Fetch performs a git fetch from the specified remote and stops the fetch
+// when ctx is cancelled
+func Fetch(ctx context.Context, remote string) error {
+	defer invalidateRemoteBranches()
+	args := []string{"fetch", remote}
+	output, err := runRemote(ctx, remote, "fetch from", args)
 	if err != nil {
-		return fmt.Errorf("failed to fetch from remote '%s': %s", remote, string(output))
+		if cancelErr := cancelledError(ctx, fmt.Sprintf("fetch from remote '%s'", remote)); cancelErr != nil {
+			return cancelErr
+		}
+		if errors.RemoteFailure(err) != nil {
+			return commandError(args, err)
+		}
+		return commandError(args, fmt.Errorf("failed to fetch from remote '%s': %s", remote, string(output)))
 	}
 	return nil
 }
 
 // DeleteRemoteBranch deletes a branch from a remote repository
-func DeleteRemoteBranch(remote, branch string) error {
-	cmd := exec.Command("git", "push", remote, ":"+branch)
+func DeleteRemoteBranch(ctx context.Context, remote, branch string) error {
+	defer invalidateRemoteBranches()
+	args := []string{"push", remote, ":" + branch}
+	cmd := commandContext(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to delete remote branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to delete remote branch: %s", string(output)))
 	}
 	return nil
 }
 
 // RemoteBranchExists checks if a remote branch exists
 func RemoteBranchExists(remote, branch string) bool {
+	return noCache.RemoteBranchExists(remote, branch)
+}
+
+// RemoteBranchExists checks if a remote branch exists with the persistent ref check
+func (c *Cache) RemoteBranchExists(remote, branch string) bool {
 	// Check if the remote tracking branch exists
-	ref := fmt.Sprintf("refs/remotes/%s/%s", remote, branch)
-	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref)
-	return cmd.Run() == nil
+	return c.revisionExists(fmt.Sprintf("refs/remotes/%s/%s", remote, branch))
+}
+
+// RemoteBranches lists the branches of a remote as of the last fetch, i.e. its
+// remote-tracking branches without the "<remote>/" prefix.
+func RemoteBranches(remote string) ([]string, error) {
+	return noCache.RemoteBranches(remote)
+}
+
+// RemoteBranches lists the branches of a remote like the package function. The
+// list is cached until git-flow fetches from or pushes to a remote.
+func (c *Cache) RemoteBranches(remote string) ([]string, error) {
+	if branches, ok := c.cachedRemoteBranches(remote); ok {
+		return branches, nil
+	}
+	generation := remoteGeneration.Load()
+
+	prefix := fmt.Sprintf("refs/remotes/%s/", remote)
+	cmd := command("for-each-ref", "--format=%(refname)", prefix)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches of remote '%s': %w", remote, err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		branch := strings.TrimPrefix(line, prefix)
+		// Skip the symbolic <remote>/HEAD ref
+		if branch == "" || branch == "HEAD" {
+			continue
+		}
+		branches = append(branches, branch)
+	}
+
+	c.storeRemoteBranches(remote, branches, generation)
+	return branches, nil
+}
+
+// PushBranchWithLease pushes a local branch over an existing branch of the
+// remote, remoteBranch, and sets up tracking. The push is refused unless the
+// remote branch still points to the commit its remote-tracking branch
+// records, so work pushed by someone else since the last fetch is never
+// overwritten.
+func PushBranchWithLease(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string) error {
+	return pushBranchWithLease(ctx, remote, branch, remoteBranch, pushOptions, "-u")
+}
+
+// PushBranchWithLeaseNoTrack pushes like PushBranchWithLease, without setting
+// up tracking
+func PushBranchWithLeaseNoTrack(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string) error {
+	return pushBranchWithLease(ctx, remote, branch, remoteBranch, pushOptions)
+}
+
+func pushBranchWithLease(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string, options ...string) error {
+	defer invalidateRemoteBranches()
+
+	expected, err := command("rev-parse", "--verify", fmt.Sprintf("refs/remotes/%s/%s", remote, remoteBranch)).Output()
+	if err != nil {
+		return fmt.Errorf("failed to resolve remote branch '%s/%s': %w", remote, remoteBranch, err)
+	}
+
+	args := append([]string{"push"}, options...)
+	args = append(args, fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", remoteBranch, strings.TrimSpace(string(expected))), remote)
+	for _, opt := range pushOptions {
+		args = append(args, "-o", opt)
+	}
+	args = append(args, branchRefspec(branch, remoteBranch))
+
+	output, err := runRemote(ctx, remote, "push to", args)
+	if err != nil {
+		return pushError(args, remote, fmt.Sprintf("failed to push branch '%s' to '%s'", branch, remote), output, err)
+	}
+	return nil
+}
+
+// SetUpstream makes a local branch track a branch of a remote
+func SetUpstream(branch, remote, remoteBranch string) error {
+	args := []string{"branch", "--set-upstream-to=" + remote + "/" + remoteBranch, branch}
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(args, fmt.Errorf("failed to set upstream of '%s': %s", branch, strings.TrimSpace(string(output))))
+	}
+	return nil
 }
 
 // TagOptions contains options for tag creation
@@ -358,19 +534,24 @@ type TagOptions struct {
 	MessageFile string // File containing the message (optional, overrides Message)
 	Sign        bool   // Whether to sign the tag (optional)
 	SigningKey  string // Key to use for signing (optional, implies Sign=true)
+	Force       bool   // Replace an existing tag instead of keeping it (optional)
+	Target      string // Commit or branch to tag, HEAD if empty (optional)
 }
 
 // CreateTag creates a Git tag with the specified options
 func CreateTag(tagName string, options *TagOptions) error {
 	// Check if tag already exists
-	cmd := exec.Command("git", "show-ref", "--tags", tagName)
-	if err := cmd.Run(); err == nil {
+	cmd := command("show-ref", "--tags", tagName)
+	if err := cmd.Run(); err == nil && !options.Force {
 		// Tag already exists, skip creation
 		return nil
 	}
 
 	// Build command arguments
 	args := []string{"tag"}
+	if options.Force {
+		args = append(args, "-f")
+	}
 
 	// Use annotated tag
 	args = append(args, "-a")
@@ -388,6 +569,9 @@ func CreateTag(tagName string, options *TagOptions) error {
 
 	// Apply tag name
 	args = append(args, tagName)
+	if options.Target != "" {
+		args = append(args, options.Target)
+	}
 
 	// Apply message
 	if options.MessageFile != "" {
@@ -399,36 +583,385 @@ func CreateTag(tagName string, options *TagOptions) error {
 	}
 
 	// Execute tag command
-	cmd = exec.Command("git", args...)
+	cmd = command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to create tag '%s': %w (output: %s)", tagName, err, string(output))
+		return commandError(args, fmt.Errorf("failed to create tag '%s': %w (output: %s)", tagName, err, string(output)))
 	}
 
 	return nil
 }
 
+// LatestTag returns the most recent tag reachable from rev, or "" when there is none
+func LatestTag(rev string) (string, error) {
+	return LatestTagExcluding(rev, "")
+}
+
+// LatestTagExcluding returns the most recent tag reachable from rev that does
+// not match the glob exclude, or "" when there is none
+func LatestTagExcluding(rev string, exclude string) (string, error) {
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if exclude != "" {
+		args = append(args, "--exclude", exclude)
+	}
+	cmd := command(append(args, rev)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "No names found") || strings.Contains(string(output), "No tags can describe") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find latest tag of '%s': %w (output: %s)", rev, err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// BranchCommit returns the SHA of the commit a local branch points to
+func BranchCommit(branch string) (string, error) {
+	output, err := command("rev-parse", "--verify", "refs/heads/"+branch+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve branch '%s': %w", branch, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ShortCommit abbreviates a commit hash for messages
+func ShortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// ListTags returns the local tags matching the glob pattern
+func ListTags(pattern string) ([]string, error) {
+	output, err := command("tag", "--list", pattern).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags matching '%s': %w", pattern, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// MergedTags returns the local tags matching the glob pattern that are
+// reachable from rev
+func MergedTags(rev, pattern string) ([]string, error) {
+	output, err := command("tag", "--merged", rev, "--list", pattern).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of '%s' matching '%s': %w", rev, pattern, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// TagExists reports whether the tag exists locally
+func TagExists(name string) bool {
+	return noCache.TagExists(name)
+}
+
+// TagExists reports whether the tag exists locally with the persistent ref check
+func (c *Cache) TagExists(name string) bool {
+	return c.revisionExists("refs/tags/" + name)
+}
+
+// TagCommit returns the commit the tag points to
+func TagCommit(name string) (string, error) {
+	output, err := command("rev-parse", "--verify", "refs/tags/"+name+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tag '%s': %w", name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsValidTagName reports whether name can be used as a tag name
+func IsValidTagName(name string) bool {
+	return command("check-ref-format", "refs/tags/"+name).Run() == nil
+}
+
+// MoveTags points each given lightweight tag at the commit its branch refers
+// to, creating tags that don't exist yet. All tags are updated in a single
+// transaction: either every tag moves or none does.
+func MoveTags(targets map[string]string) error {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var input strings.Builder
+	input.WriteString("start\n")
+	for _, name := range names {
+		commit, err := BranchCommit(targets[name])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&input, "update refs/tags/%s %s\n", name, commit)
+	}
+	input.WriteString("commit\n")
+
+	cmd := command("update-ref", "--stdin")
+	cmd.Stdin = strings.NewReader(input.String())
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// PushRefsAtomic pushes the given refspecs to a remote in a single atomic
+// push: the remote either accepts every ref or none of them
+func PushRefsAtomic(ctx context.Context, remote string, refspecs []string) error {
+	defer invalidateRemoteBranches()
+	args := append([]string{"push", "--atomic", remote}, refspecs...)
+	output, err := runRemote(ctx, remote, "push to", args)
+	if err != nil {
+		return pushError(args, remote, fmt.Sprintf("failed to push to '%s'", remote), output, err)
+	}
+	return nil
+}
+
+// PushRefsAtomicWithLease pushes refspecs like PushRefsAtomic, allowing the
+// remote branches in leased to be rewritten as long as each still points to
+// the commit its remote-tracking branch records
+func PushRefsAtomicWithLease(ctx context.Context, remote string, refspecs []string, leased []string) error {
+	defer invalidateRemoteBranches()
+	args := []string{"push", "--atomic"}
+	for _, branch := range leased {
+		expected, err := command("rev-parse", "--verify", fmt.Sprintf("refs/remotes/%s/%s", remote, branch)).Output()
+		if err != nil {
+			return fmt.Errorf("failed to resolve remote branch '%s/%s': %w", remote, branch, err)
+		}
+		args = append(args, fmt.Sprintf("--force-with-lease=refs/heads/%s:%s", branch, strings.TrimSpace(string(expected))))
+	}
+	args = append(append(args, remote), refspecs...)
+	output, err := runRemote(ctx, remote, "push to", args)
+	if err != nil {
+		return pushError(args, remote, fmt.Sprintf("failed to push to '%s'", remote), output, err)
+	}
+	return nil
+}
+
+// CommitTrailers returns the values of the given trailer in the commits of
+// revRange, oldest commit first. Multi-line values are unfolded.
+func CommitTrailers(revRange string, key string) ([]string, error) {
+	format := fmt.Sprintf("--format=%%(trailers:key=%s,valueonly,unfold)", key)
+	cmd := command("log", "--reverse", format, revRange)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' trailers of '%s': %w", key, revRange, err)
+	}
+
+	var values []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if value := strings.TrimSpace(line); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// GetTopLevelDir returns the root directory of the working tree
+func GetTopLevelDir() (string, error) {
+	output, err := command("rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working tree root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetTopLevelDirInDir returns the root of the working tree of the repository
+// containing dir
+func GetTopLevelDirInDir(dir string) (string, error) {
+	cmd := command("rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working tree root in dir %s: %w", dir, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitVersion is the version of the installed git executable
+type GitVersion struct {
+	Major, Minor, Patch int
+	Raw                 string // Version as reported, e.g. "2.39.3 (Apple Git-146)"
+}
+
+// AtLeast reports whether the version is major.minor or newer
+func (v GitVersion) AtLeast(major, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+// String returns the version as reported by git
+func (v GitVersion) String() string {
+	return v.Raw
+}
+
+// GetGitVersion returns the version of the git executable
+func GetGitVersion() (GitVersion, error) {
+	output, err := command("--version").Output()
+	if err != nil {
+		return GitVersion{}, fmt.Errorf("failed to get git version: %w", err)
+	}
+	version, ok := ParseGitVersion(string(output))
+	if !ok {
+		return GitVersion{}, fmt.Errorf("unrecognized git version: %s", strings.TrimSpace(string(output)))
+	}
+	return version, nil
+}
+
+// ParseGitVersion parses the output of 'git --version', such as "git version 2.39.5"
+// or "git version 2.45.1.windows.1".
+func ParseGitVersion(output string) (GitVersion, bool) {
+	raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(output), "git version"))
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return GitVersion{}, false
+	}
+	numbers := strings.SplitN(fields[0], ".", 4)
+	if len(numbers) < 2 {
+		return GitVersion{}, false
+	}
+	parts := make([]int, 3)
+	for i := 0; i < len(numbers) && i < 3; i++ {
+		n, err := strconv.Atoi(numbers[i])
+		if err != nil {
+			if i < 2 {
+				return GitVersion{}, false
+			}
+			break
+		}
+		parts[i] = n
+	}
+	return GitVersion{Major: parts[0], Minor: parts[1], Patch: parts[2], Raw: raw}, true
+}
+
+// GetCommonGitDir returns the absolute path of the git directory shared by all
+// worktrees of the repository
+func GetCommonGitDir() (string, error) {
+	output, err := command("rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get common git directory: %w", err)
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// IsLinkedWorktree reports whether the current directory is in a worktree added with
+// 'git worktree add', rather than the main working tree
+func IsLinkedWorktree() (bool, error) {
+	output, err := command("rev-parse", "--git-dir", "--git-common-dir").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get git directories: %w", err)
+	}
+	dirs := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(dirs) != 2 {
+		return false, fmt.Errorf("unexpected output of git rev-parse: %s", output)
+	}
+	gitDir, err := filepath.Abs(dirs[0])
+	if err != nil {
+		return false, err
+	}
+	commonDir, err := filepath.Abs(dirs[1])
+	if err != nil {
+		return false, err
+	}
+	return gitDir != commonDir, nil
+}
+
+// Worktree is a working tree of the repository, as listed by 'git worktree list'
+type Worktree struct {
+	Path    string
+	GitDir  string // absolute git directory of the worktree, "" if it is missing
+	Branch  string // checked out branch, "" for a detached HEAD or a bare repository
+	Current bool   // whether it is the worktree the command runs in
+}
+
+// ListWorktrees returns the main working tree and the linked worktrees of the
+// repository
+func ListWorktrees() ([]Worktree, error) {
+	args := []string{"worktree", "list", "--porcelain"}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	current := ""
+	if topLevel, err := GetTopLevelDir(); err == nil {
+		current = resolvedPath(topLevel)
+	}
+
+	var worktrees []Worktree
+	for _, block := range strings.Split(strings.TrimSpace(string(output)), "\n\n") {
+		var worktree Worktree
+		bare := false
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "worktree "):
+				worktree.Path = strings.TrimPrefix(line, "worktree ")
+			case strings.HasPrefix(line, "branch "):
+				worktree.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+			case line == "bare":
+				bare = true
+			}
+		}
+		if worktree.Path == "" || bare {
+			continue
+		}
+		cmd := command("rev-parse", "--absolute-git-dir")
+		cmd.Dir = worktree.Path
+		if gitDir, err := cmd.Output(); err == nil {
+			worktree.GitDir = strings.TrimSpace(string(gitDir))
+		}
+		worktree.Current = resolvedPath(worktree.Path) == current
+		worktrees = append(worktrees, worktree)
+	}
+	return worktrees, nil
+}
+
+// WorktreeOfBranch returns the path of another worktree that has branch
+// checked out, or "" if none has
+func WorktreeOfBranch(branch string) string {
+	worktrees, err := ListWorktrees()
+	if err != nil {
+		return ""
+	}
+	for _, worktree := range worktrees {
+		if !worktree.Current && worktree.Branch == branch {
+			return worktree.Path
+		}
+	}
+	return ""
+}
+
+// resolvedPath returns path with symbolic links resolved, so paths reported
+// by different git commands compare equal
+func resolvedPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
 // RebaseWithOptions rebases the current branch onto another branch with optional preserve-merges
-func RebaseWithOptions(targetBranch string, preserveMerges bool) error {
+func RebaseWithOptions(ctx context.Context, targetBranch string, preserveMerges bool, noVerify bool) error {
 	args := []string{"rebase"}
 	if preserveMerges {
 		args = append(args, "--preserve-merges")
 	}
+	if noVerify {
+		args = append(args, "--no-verify")
+	}
 	args = append(args, targetBranch)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if strings.Contains(string(output), "conflict") {
-			return fmt.Errorf("rebase conflict: %s", string(output))
+			return commandError(args, fmt.Errorf("rebase conflict: %s", string(output)))
 		}
-		return fmt.Errorf("failed to rebase branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to rebase branch: %s", string(output)))
 	}
 	return nil
 }
 
 // MergeWithOptions merges a branch into current branch with optional no-fast-forward
-func MergeWithOptions(branchName string, noFF bool, noVerify bool) error {
+func MergeWithOptions(ctx context.Context, branchName string, noFF bool, noVerify bool) error {
 	args := []string{"merge"}
 	if noFF {
 		args = append(args, "--no-ff")
@@ -438,14 +971,14 @@ func MergeWithOptions(branchName string, noFF bool, noVerify bool) error {
 	}
 	args = append(args, branchName)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 
 	// Check for merge conflicts - Git returns exit code 1 and specific output patterns
 	if err != nil {
 		// Check if there are unmerged paths (conflicts)
-		conflictCmd := exec.Command("git", "ls-files", "--unmerged")
+		conflictCmd := command("ls-files", "--unmerged")
 		conflictOutput, _ := conflictCmd.Output()
 
 		if len(conflictOutput) > 0 ||
@@ -453,16 +986,16 @@ func MergeWithOptions(branchName string, noFF bool, noVerify bool) error {
 			strings.Contains(outputStr, "CONFLICT") ||
 			strings.Contains(outputStr, "merge failed") ||
 			strings.Contains(outputStr, "needs merge") {
-			return fmt.Errorf("merge conflict: %s", outputStr)
+			return commandError(args, fmt.Errorf("merge conflict: %s", outputStr))
 		}
-		return fmt.Errorf("failed to merge branch: %s", outputStr)
+		return commandError(args, fmt.Errorf("failed to merge branch: %s", outputStr))
 	}
 
 	return nil
 }
 
 // MergeWithMessage merges a branch into current branch with a custom commit message
-func MergeWithMessage(branchName string, message string, noFF bool, noVerify bool) error {
+func MergeWithMessage(ctx context.Context, branchName string, message string, noFF bool, noVerify bool) error {
 	args := []string{"merge"}
 	if noFF {
 		args = append(args, "--no-ff")
@@ -472,14 +1005,14 @@ func MergeWithMessage(branchName string, message string, noFF bool, noVerify boo
 	}
 	args = append(args, "-m", message, branchName)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 
 	// Check for merge conflicts - Git returns exit code 1 and specific output patterns
 	if err != nil {
 		// Check if there are unmerged paths (conflicts)
-		conflictCmd := exec.Command("git", "ls-files", "--unmerged")
+		conflictCmd := command("ls-files", "--unmerged")
 		conflictOutput, _ := conflictCmd.Output()
 
 		if len(conflictOutput) > 0 ||
@@ -487,31 +1020,102 @@ func MergeWithMessage(branchName string, message string, noFF bool, noVerify boo
 			strings.Contains(outputStr, "CONFLICT") ||
 			strings.Contains(outputStr, "merge failed") ||
 			strings.Contains(outputStr, "needs merge") {
-			return fmt.Errorf("merge conflict: %s", outputStr)
+			return commandError(args, fmt.Errorf("merge conflict: %s", outputStr))
 		}
-		return fmt.Errorf("failed to merge branch: %s", outputStr)
+		return commandError(args, fmt.Errorf("failed to merge branch: %s", outputStr))
+	}
+
+	return nil
+}
+
+// MergeInMemory merges source into the local branch, which must not be checked
+// out, like 'git merge --no-ff' but without touching the index or the working
+// tree (git merge-tree --write-tree, Git 2.38). No hooks run. merged is false
+// and nothing is changed when the merge has conflicts.
+func MergeInMemory(ctx context.Context, branch, source, message string) (merged bool, err error) {
+	branchCommit, err := BranchCommit(branch)
+	if err != nil {
+		return false, err
+	}
+	args := []string{"rev-parse", "--verify", source + "^{commit}"}
+	output, err := command(args...).Output()
+	if err != nil {
+		return false, commandError(args, fmt.Errorf("failed to resolve '%s': %w", source, err))
+	}
+	sourceCommit := strings.TrimSpace(string(output))
+	if IsAncestor(sourceCommit, branchCommit) {
+		return true, nil
+	}
+
+	// Exit status 1 means conflicts; the first line is the tree either way
+	args = []string{"merge-tree", "--write-tree", branchCommit, sourceCommit}
+	output, err = localCommand(ctx, args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	} else if err != nil {
+		return false, commandError(args, fmt.Errorf("failed to merge '%s' into '%s': %w", source, branch, err))
+	}
+	tree := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+
+	args = []string{"commit-tree", tree, "-p", branchCommit, "-p", sourceCommit, "-m", message}
+	output, err = command(args...).CombinedOutput()
+	if err != nil {
+		return false, commandError(args, fmt.Errorf("failed to create merge commit: %s", strings.TrimSpace(string(output))))
 	}
+	commit := strings.TrimSpace(string(output))
 
+	// The old value makes the update fail if the branch moved in the meantime
+	args = []string{"update-ref", "-m", "merge " + source + ": in-memory merge", "refs/heads/" + branch, commit, branchCommit}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return false, commandError(args, fmt.Errorf("failed to update branch %s: %s", branch, strings.TrimSpace(string(output))))
+	}
+	return true, nil
+}
+
+// MergeFastForwardOnly fast-forwards the current branch to branchName and fails
+// without changing anything if that would require a merge commit
+func MergeFastForwardOnly(ctx context.Context, branchName string) error {
+	args := []string{"merge", "--ff-only", branchName}
+	cmd := localCommand(ctx, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(args, fmt.Errorf("failed to fast-forward to '%s': %s", branchName, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// FastForwardBranch moves the local branch, which must not be checked out, to
+// target. It fails without changing anything if that is not a fast-forward or
+// the branch is checked out in another worktree.
+func FastForwardBranch(branch, target string) error {
+	args := []string{"fetch", "--quiet", ".", target + ":refs/heads/" + branch}
+	cmd := command(args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(args, fmt.Errorf("failed to fast-forward '%s' to '%s': %s", branch, target, strings.TrimSpace(string(output))))
+	}
 	return nil
 }
 
 // Commit creates a commit with the given message
-func Commit(message string, noVerify bool) error {
+func Commit(ctx context.Context, message string, noVerify bool) error {
 	args := []string{"commit", "-m", message}
 	if noVerify {
 		args = append(args, "--no-verify")
 	}
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to commit: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to commit: %s", string(output)))
 	}
 	return nil
 }
 
 // RebaseContinue continues an ongoing rebase operation after conflicts are resolved
-func RebaseContinue() error {
-	cmd := exec.Command("git", "rebase", "--continue")
+func RebaseContinue(ctx context.Context) error {
+	args := []string{"rebase", "--continue"}
+	// Git may open the editor for the commit message, which needs the terminal
+	cmd := commandContext(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 	if err != nil {
@@ -520,28 +1124,28 @@ func RebaseContinue() error {
 			return nil
 		}
 		if strings.Contains(outputStr, "conflict") || strings.Contains(outputStr, "CONFLICT") {
-			return fmt.Errorf("rebase conflict: %s", outputStr)
+			return commandError(args, fmt.Errorf("rebase conflict: %s", outputStr))
 		}
-		return fmt.Errorf("failed to continue rebase: %s", outputStr)
+		return commandError(args, fmt.Errorf("failed to continue rebase: %s", outputStr))
 	}
 	return nil
 }
 
 // MergeSquashWithMessage performs a squash merge with a custom commit message
-func MergeSquashWithMessage(branchName string, message string, noVerify bool) error {
+func MergeSquashWithMessage(ctx context.Context, branchName string, message string, noVerify bool) error {
 	args := []string{"merge", "--squash"}
 	if noVerify {
 		args = append(args, "--no-verify")
 	}
 	args = append(args, branchName)
 
-	cmd := exec.Command("git", args...)
+	cmd := localCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if strings.Contains(string(output), "conflict") {
-			return fmt.Errorf("squash merge conflict: %s", string(output))
+			return commandError(args, fmt.Errorf("squash merge conflict: %s", string(output)))
 		}
-		return fmt.Errorf("failed to squash merge branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to squash merge branch: %s", string(output)))
 	}
 
 	// Commit the squashed changes with custom message
@@ -549,29 +1153,43 @@ func MergeSquashWithMessage(branchName string, message string, noVerify bool) er
 	if noVerify {
 		commitArgs = append(commitArgs, "--no-verify")
 	}
-	cmd = exec.Command("git", commitArgs...)
+	cmd = localCommand(ctx, commitArgs...)
 	output, err = cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to commit squashed changes: %s", string(output))
+		return commandError(commitArgs, fmt.Errorf("failed to commit squashed changes: %s", string(output)))
 	}
 
 	return nil
 }
 
-// PushBranch pushes a local branch to a remote and sets up tracking
-func PushBranch(remote, branch string, pushOptions []string) error {
-	args := []string{"push", "-u", remote}
+// PushBranch pushes a local branch to the branch remoteBranch of a remote and
+// sets up tracking, stopping the push when ctx is cancelled
+func PushBranch(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string) error {
+	return pushBranch(ctx, remote, branch, remoteBranch, pushOptions, "-u")
+}
+
+// PushBranchNoTrack pushes a local branch to a remote without setting up tracking
+func PushBranchNoTrack(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string) error {
+	return pushBranch(ctx, remote, branch, remoteBranch, pushOptions)
+}
+
+func pushBranch(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string, options ...string) error {
+	defer invalidateRemoteBranches()
+	args := append([]string{"push"}, options...)
+	args = append(args, remote)
 
 	for _, opt := range pushOptions {
 		args = append(args, "-o", opt)
 	}
 
-	args = append(args, branch)
+	args = append(args, branchRefspec(branch, remoteBranch))
 
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := runRemote(ctx, remote, "push to", args)
 	if err != nil {
-		return fmt.Errorf("failed to push branch '%s' to '%s': %s", branch, remote, strings.TrimSpace(string(output)))
+		if cancelErr := cancelledError(ctx, fmt.Sprintf("push of branch '%s' to '%s'", branch, remote)); cancelErr != nil {
+			return cancelErr
+		}
+		return pushError(args, remote, fmt.Sprintf("failed to push branch '%s' to '%s'", branch, remote), output, err)
 	}
 	return nil
 }
@@ -579,11 +1197,11 @@ func PushBranch(remote, branch string, pushOptions []string) error {
 // CreateTrackingBranch creates a local branch that tracks a remote branch
 func CreateTrackingBranch(localBranch, remote, remoteBranch string) error {
 	// git checkout -b <local> --track <remote>/<branch>
-	cmd := exec.Command("git", "checkout", "-b", localBranch, "--track",
-		fmt.Sprintf("%s/%s", remote, remoteBranch))
+	args := []string{"checkout", "-b", localBranch, "--track", fmt.Sprintf("%s/%s", remote, remoteBranch)}
+	cmd := command(args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to create tracking branch: %s", string(output))
+		return commandError(args, fmt.Errorf("failed to create tracking branch: %s", string(output)))
 	}
 	return nil
 }
@@ -592,7 +1210,7 @@ func CreateTrackingBranch(localBranch, remote, remoteBranch string) error {
 // Returns the full tracking reference (e.g., "origin/feature/foo") or an error
 // if no tracking branch is configured.
 func GetTrackingBranch(branch string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", branch+"@{upstream}")
+	cmd := command("rev-parse", "--abbrev-ref", branch+"@{upstream}")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Check if the error is because there's no tracking branch
@@ -617,50 +1235,459 @@ func CompareBranchWithRemote(branch string) (BranchSyncStatus, int, error) {
 		return SyncStatusNoTracking, 0, err
 	}
 
+	return CompareBranches(branch, trackingBranch)
+}
+
+// CompareBranches compares a local branch with another branch, typically a
+// remote-tracking branch. The status and count are reported from the point of
+// view of branch, as in CompareBranchWithRemote.
+func CompareBranches(branch, other string) (BranchSyncStatus, int, error) {
+	ahead, behind, err := AheadBehind(branch, other)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// Determine status based on ahead/behind counts
+	switch {
+	case ahead == 0 && behind == 0:
+		return SyncStatusEqual, 0, nil
+	case ahead > 0 && behind == 0:
+		return SyncStatusAhead, ahead, nil
+	case ahead == 0 && behind > 0:
+		return SyncStatusBehind, behind, nil
+	default:
+		return SyncStatusDiverged, ahead + behind, nil
+	}
+}
+
+// CommitSubjects returns the subjects of the commits on branch that are not on
+// base, oldest first
+func CommitSubjects(base, branch string) ([]string, error) {
+	args := []string{"log", "--reverse", "--format=%s", base + ".." + branch}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// LogEntry is a commit as listed by FirstParentLog
+type LogEntry struct {
+	Hash    string
+	Parents int
+	Subject string
+}
+
+// FirstParentLog returns the commits on the first-parent history of rev,
+// newest first. A non-empty since limits it to commits made after that date,
+// in any format 'git log --since' accepts.
+func FirstParentLog(rev, since string) ([]LogEntry, error) {
+	args := []string{"log", "--first-parent", "--format=%H%x00%P%x00%s"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	args = append(args, rev, "--")
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	var entries []LogEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		entries = append(entries, LogEntry{Hash: fields[0], Parents: len(strings.Fields(fields[1])), Subject: fields[2]})
+	}
+	return entries, nil
+}
+
+// MergeSubjects returns the subjects of the merge commits reachable from any
+// ref, newest first
+func MergeSubjects() ([]string, error) {
+	args := []string{"log", "--all", "--merges", "--format=%s"}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	var subjects []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// TagsSince returns the local tags, sorted by name. A non-empty since limits
+// them to tags on commits made after that date.
+func TagsSince(since string) ([]string, error) {
+	tags, err := ListTags("*")
+	if err != nil || since == "" {
+		return tags, err
+	}
+	args := []string{"rev-list", "--no-walk", "--since=" + since, "--tags"}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	recent := make(map[string]bool)
+	for _, hash := range strings.Fields(string(output)) {
+		recent[hash] = true
+	}
+	var result []string
+	for _, tag := range tags {
+		if commit, err := TagCommit(tag); err == nil && recent[commit] {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// AheadBehind counts the commits in branch that are not in other (ahead) and
+// the commits in other that are not in branch (behind).
+func AheadBehind(branch, other string) (int, int, error) {
 	// Use git rev-list to count commits ahead and behind
 	// Format: <ahead>\t<behind>
-	cmd := exec.Command("git", "rev-list", "--left-right", "--count", branch+"..."+trackingBranch)
+	cmd := command("rev-list", "--left-right", "--count", branch+"..."+other)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to compare branches: %s", string(output))
+		return 0, 0, fmt.Errorf("failed to compare branches: %s", string(output))
 	}
 
 	// Parse the output (format: "ahead\tbehind")
 	parts := strings.Fields(strings.TrimSpace(string(output)))
 	if len(parts) != 2 {
-		return "", 0, fmt.Errorf("unexpected output format from rev-list: %s", string(output))
+		return 0, 0, fmt.Errorf("unexpected output format from rev-list: %s", string(output))
 	}
 
 	ahead, err := strconv.Atoi(parts[0])
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to parse ahead count: %w", err)
+		return 0, 0, fmt.Errorf("failed to parse ahead count: %w", err)
 	}
 
 	behind, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to parse behind count: %w", err)
+		return 0, 0, fmt.Errorf("failed to parse behind count: %w", err)
 	}
 
-	// Determine status based on ahead/behind counts
-	switch {
-	case ahead == 0 && behind == 0:
-		return SyncStatusEqual, 0, nil
-	case ahead > 0 && behind == 0:
-		return SyncStatusAhead, ahead, nil
-	case ahead == 0 && behind > 0:
-		return SyncStatusBehind, behind, nil
-	default:
-		return SyncStatusDiverged, ahead + behind, nil
-	}
+	return ahead, behind, nil
 }
 
 // FetchBranch fetches a specific branch from a remote.
 // This is a targeted fetch that only updates the specified branch reference.
-func FetchBranch(remote, branch string) error {
-	cmd := exec.Command("git", "fetch", remote, branch)
+// Cancelling ctx stops the fetch.
+func FetchBranch(ctx context.Context, remote, branch string) error {
+	defer invalidateRemoteBranches()
+	args := []string{"fetch", remote, branch}
+	output, err := runRemote(ctx, remote, "fetch from", args)
+	if err != nil {
+		if cancelErr := cancelledError(ctx, fmt.Sprintf("fetch of branch '%s' from '%s'", branch, remote)); cancelErr != nil {
+			return cancelErr
+		}
+		if errors.RemoteFailure(err) != nil {
+			return commandError(args, err)
+		}
+		return commandError(args, fmt.Errorf("failed to fetch branch '%s' from '%s': %s", branch, remote, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// UntrackedFilesIn returns the untracked files in the working tree that branch
+// tracks, which checking out branch would overwrite. Ignored files are not
+// reported, since checkout overwrites them without asking.
+func UntrackedFilesIn(branch string) ([]string, error) {
+	output, err := command("ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	untracked := strings.Split(strings.TrimRight(string(output), "\x00"), "\x00")
+	if untracked[0] == "" {
+		return nil, nil
+	}
+
+	args := append([]string{"ls-tree", "-r", "--name-only", "-z", branch, "--"}, untracked...)
+	output, err = command(args...).Output()
+	if err != nil {
+		return nil, commandError(args[:5], fmt.Errorf("failed to list files of '%s': %w", branch, err))
+	}
+	tracked := strings.TrimRight(string(output), "\x00")
+	if tracked == "" {
+		return nil, nil
+	}
+	return strings.Split(tracked, "\x00"), nil
+}
+
+// StashUntracked stashes the untracked files of the working tree, together
+// with any local changes, and returns the stash commit
+func StashUntracked(message string) (string, error) {
+	args := []string{"stash", "push", "--include-untracked", "-m", message}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return "", commandError(args, fmt.Errorf("failed to stash untracked files: %w (output: %s)", err, strings.TrimSpace(string(output))))
+	}
+	output, err := command("rev-parse", "--verify", "refs/stash").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the stash: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RestoreStash applies the stash commit to the working tree and drops it from
+// the stash list
+func RestoreStash(commit string) error {
+	args := []string{"stash", "apply", commit}
+	if output, err := command(args...).CombinedOutput(); err != nil {
+		return commandError(args, fmt.Errorf("failed to apply stash %s: %w (output: %s)", commit, err, strings.TrimSpace(string(output))))
+	}
+
+	// Other stashes may have been pushed since, so drop the entry by its commit
+	output, err := command("stash", "list", "--format=%H").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list stashes: %w", err)
+	}
+	for i, entry := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if entry == commit {
+			args := []string{"stash", "drop", fmt.Sprintf("stash@{%d}", i)}
+			if err := command(args...).Run(); err != nil {
+				return commandError(args, fmt.Errorf("failed to drop stash %s: %w", commit, err))
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// HasUncommittedChanges reports whether tracked files have staged or unstaged changes.
+// Untracked files are ignored since they don't interfere with merges.
+func HasUncommittedChanges() (bool, error) {
+	cmd := command("status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working tree status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// GetOperationInProgress returns the name of a Git operation (merge, rebase,
+// cherry-pick, revert) that is currently in progress, or "" if there is none.
+func GetOperationInProgress() (string, error) {
+	gitDir, err := GetGitDir()
+	if err != nil {
+		return "", err
+	}
+
+	markers := []struct {
+		path      string
+		operation string
+	}{
+		{"MERGE_HEAD", "merge"},
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	}
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, marker.path)); err == nil {
+			return marker.operation, nil
+		}
+	}
+	return "", nil
+}
+
+// RemoteExists checks if a remote with the given name is configured
+func RemoteExists(remote string) bool {
+	cmd := command("remote", "get-url", remote)
+	return cmd.Run() == nil
+}
+
+// CheckRemoteReachable verifies that the remote can be contacted
+func CheckRemoteReachable(ctx context.Context, remote string) error {
+	cmd := commandContext(ctx, "ls-remote", "--heads", remote)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("remote '%s' is not reachable: %s", remote, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// IsAncestor reports whether ancestor is reachable from descendant
+func IsAncestor(ancestor, descendant string) bool {
+	cmd := command("merge-base", "--is-ancestor", ancestor, descendant)
+	return cmd.Run() == nil
+}
+
+// MergeBase returns the best common ancestor of two commits
+func MergeBase(a, b string) (string, error) {
+	args := []string{"merge-base", a, b}
+	output, err := command(args...).Output()
+	if err != nil {
+		return "", commandError(args, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// DiffStat summarizes the changes between two commits
+type DiffStat struct {
+	Files      int
+	Insertions int
+	Deletions  int
+}
+
+var shortStatPattern = regexp.MustCompile(`(\d+) (file|insertion|deletion)`)
+
+// DiffShortStat returns the changes branch makes since it diverged from base,
+// as git diff --shortstat base...branch reports them
+func DiffShortStat(base, branch string) (DiffStat, error) {
+	args := []string{"diff", "--shortstat", base + "..." + branch}
+	output, err := command(args...).Output()
+	if err != nil {
+		return DiffStat{}, commandError(args, err)
+	}
+	var stat DiffStat
+	for _, match := range shortStatPattern.FindAllStringSubmatch(string(output), -1) {
+		count, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "file":
+			stat.Files = count
+		case "insertion":
+			stat.Insertions = count
+		case "deletion":
+			stat.Deletions = count
+		}
+	}
+	return stat, nil
+}
+
+// ColorEnabled reports whether output for the color slot, such as color.diff,
+// is colored, following color.ui like Git does. stdoutIsTTY tells Git
+// whether the output goes to a terminal, for the auto setting.
+func ColorEnabled(slot string, stdoutIsTTY bool) bool {
+	output, err := command("config", "--get-colorbool", slot, strconv.FormatBool(stdoutIsTTY)).Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// BackMerges returns the merge commits on branch that are not on parent and
+// merged parent into branch: one of their merged-in commits is reachable from parent
+func BackMerges(branch, parent string) ([]string, error) {
+	args := []string{"rev-list", "--merges", "--parents", parent + ".." + branch}
+	output, err := command(args...).Output()
+	if err != nil {
+		return nil, commandError(args, err)
+	}
+	var merges []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		for _, mergedIn := range fields[2:] {
+			if IsAncestor(mergedIn, parent) {
+				merges = append(merges, fields[0])
+				break
+			}
+		}
+	}
+	return merges, nil
+}
+
+// IsFirstParentAncestor reports whether commit is on the first-parent history
+// of branch, as it is after commit was fast-forwarded or built on by branch,
+// but not after it was brought in by a merge commit
+func IsFirstParentAncestor(commit, branch string) bool {
+	commitID, err := command("rev-parse", commit+"^{commit}").Output()
+	if err != nil {
+		return false
+	}
+	output, err := command("rev-list", "--first-parent", branch, "^"+commit).Output()
+	if err != nil {
+		return false
+	}
+	history := strings.Fields(string(output))
+	if len(history) == 0 {
+		return IsAncestor(commit, branch)
+	}
+	// The walk stops at the first commit reachable from commit; on the
+	// first-parent history that is commit itself
+	parent, err := command("rev-parse", history[len(history)-1]+"^1").Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(parent)) == strings.TrimSpace(string(commitID))
+}
+
+// ChangesApplied reports whether every commit of branch that is missing in
+// target has an equivalent change in target, as when the branch was rebased
+// onto target elsewhere. A branch without such commits reports false.
+func ChangesApplied(branch, target string) bool {
+	output, err := command("cherry", target, branch).Output()
+	if err != nil {
+		return false
+	}
+	lines := strings.Fields(string(output))
+	if len(lines) == 0 {
+		return false
+	}
+	for i := 0; i < len(lines); i += 2 {
+		if lines[i] != "-" {
+			return false
+		}
+	}
+	return true
+}
+
+// CommitSignature describes the signature of a commit as verified by git with
+// the configured GPG or SSH settings
+type CommitSignature struct {
+	Status             string // %G? code: G good, U good with unknown validity, B bad, X/Y expired, R revoked, E can't be checked, N none
+	Key                string // Key ID used to sign
+	Fingerprint        string // Fingerprint of the signing key
+	PrimaryFingerprint string // Fingerprint of the primary key, for GPG subkeys
+	Signer             string // Name of the signer
+}
+
+// GetCommitSignature verifies the signature of the commit rev points to
+func GetCommitSignature(rev string) (*CommitSignature, error) {
+	cmd := command("log", "-1", "--format=%G?%x00%GK%x00%GF%x00%GP%x00%GS", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature of '%s': %w", rev, err)
+	}
+
+	fields := strings.Split(strings.TrimRight(string(output), "\n"), "\x00")
+	for len(fields) < 5 {
+		fields = append(fields, "")
+	}
+	return &CommitSignature{
+		Status:             fields[0],
+		Key:                fields[1],
+		Fingerprint:        fields[2],
+		PrimaryFingerprint: fields[3],
+		Signer:             fields[4],
+	}, nil
+}
+
+// GetRemoteURL returns the fetch URL of a remote
+func GetRemoteURL(remote string) (string, error) {
+	cmd := command("remote", "get-url", remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote '%s': %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CloneShallow clones the latest commit of a repository into dir
+func CloneShallow(url, dir string) error {
+	cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", url, dir)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to fetch branch '%s' from '%s': %s", branch, remote, strings.TrimSpace(string(output)))
+		return fmt.Errorf("failed to clone '%s': %s", url, strings.TrimSpace(string(output)))
 	}
 	return nil
 }
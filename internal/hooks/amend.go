@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// Environment variables offering a hook to amend the configuration of the run
+const (
+	// EnvAllowConfigWrite is set to 1 when the hook may amend the configuration
+	EnvAllowConfigWrite = "GITFLOW_ALLOW_CONFIG_WRITE"
+	// EnvConfigResponse names the file the hook writes key=value lines to
+	EnvConfigResponse = "GITFLOW_CONFIG_RESPONSE"
+)
+
+// ConfigAmender applies the configuration a hook wrote to its response file
+type ConfigAmender func(values map[string]string) error
+
+// configWriteAllowed reports whether gitflow.hooks.allowConfigWrite lets hooks
+// amend the configuration of the run
+func configWriteAllowed(gitDir string) bool {
+	value, err := git.GetConfigInDir(workTreeRoot(gitDir), config.KeyHooksConfigWrite)
+	if err != nil {
+		return false
+	}
+	allowed, _ := config.ParseBool(value)
+	return allowed
+}
+
+// withConfigResponse runs a hook or filter, offering it a response file when
+// amend is set and gitflow.hooks.allowConfigWrite is enabled. The values the
+// script wrote are passed to amend after run succeeded; a failed run leaves the
+// configuration as it was. script names the hook or filter in errors.
+func withConfigResponse(gitDir string, script string, amend ConfigAmender, env []string, run func(env []string) error) error {
+	if amend == nil || !configWriteAllowed(gitDir) {
+		return run(env)
+	}
+
+	file, err := os.CreateTemp("", "gitflow-config-response-*")
+	if err != nil {
+		return fmt.Errorf("failed to create config response file: %w", err)
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	if env == nil {
+		env = os.Environ()
+	}
+	env = append(env, EnvAllowConfigWrite+"=1", EnvConfigResponse+"="+path)
+	if err := run(env); err != nil {
+		return err
+	}
+
+	values, err := readConfigResponse(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", script, err)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if err := amend(values); err != nil {
+		return fmt.Errorf("%s wrote an invalid configuration response: %w", script, err)
+	}
+	return nil
+}
+
+// readConfigResponse reads the key=value lines of a response file. Empty
+// lines and lines starting with # are skipped; a later line for the same key
+// replaces an earlier one.
+func readConfigResponse(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config response file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("config response line %d: expected key=value, got '%s'", lineNo, line)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config response file: %w", err)
+	}
+	return values, nil
+}
@@ -13,6 +13,13 @@ import (
 // If the filter does not exist or is not executable, the original version is returned.
 // If the filter exits with a non-zero status, an error is returned.
 func RunVersionFilter(gitDir string, branchType string, version string) (string, error) {
+	return RunVersionFilterWithConfig(gitDir, branchType, version, nil)
+}
+
+// RunVersionFilterWithConfig executes a version filter like RunVersionFilter.
+// With amend set and gitflow.hooks.allowConfigWrite enabled, the configuration
+// the filter wrote to GITFLOW_CONFIG_RESPONSE is applied through it.
+func RunVersionFilterWithConfig(gitDir string, branchType string, version string, amend ConfigAmender) (string, error) {
 	filterName := GetFilterName(branchType, "start", FilterTargetVersion)
 	hooksDir := getHooksDir(gitDir)
 	scriptPath := filepath.Join(hooksDir, filterName)
@@ -24,9 +31,17 @@ func RunVersionFilter(gitDir string, branchType string, version string) (string,
 
 	// Execute the filter with version as argument
 	repoRoot := filepath.Dir(getCommonGitDir(gitDir))
-	result, err := runFilter(scriptPath, version, nil, repoRoot)
+	var result string
+	err := withConfigResponse(gitDir, "version filter '"+filterName+"'", amend, nil, func(env []string) error {
+		var err error
+		result, err = runFilter(scriptPath, version, env, repoRoot)
+		if err != nil {
+			return fmt.Errorf("version filter '%s' failed: %w", filterName, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("version filter '%s' failed: %w", filterName, err)
+		return "", err
 	}
 
 	// If the filter returned empty output, use the original version
@@ -37,6 +52,11 @@ func RunVersionFilter(gitDir string, branchType string, version string) (string,
 	return result, nil
 }
 
+// HasVersionFilter reports whether an executable version filter exists for the given branch type
+func HasVersionFilter(gitDir string, branchType string) bool {
+	return isExecutable(filepath.Join(getHooksDir(gitDir), GetFilterName(branchType, "start", FilterTargetVersion)))
+}
+
 // RunTagMessageFilter executes a tag message filter for the given branch type and returns the modified message.
 // The filter script name is: filter-flow-{branchType}-finish-tag-message
 // If the filter does not exist or is not executable, the original message is returned.
@@ -1,35 +1,81 @@
 package hooks
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/profile"
 )
 
 // RunPreHook executes a pre-hook script. Returns an error if the hook fails (non-zero exit).
 // If the hook does not exist or is not executable, it returns nil (no error).
+// With ctx.AmendConfig set and gitflow.hooks.allowConfigWrite enabled, the
+// configuration the hook wrote to GITFLOW_CONFIG_RESPONSE is applied through it.
 func RunPreHook(gitDir string, branchType string, action HookAction, ctx HookContext) error {
-	result := runHook(gitDir, HookPre, branchType, action, ctx)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.Executed && result.ExitCode != 0 {
-		if result.Output != "" {
-			return fmt.Errorf("pre-hook '%s-flow-%s-%s' failed with exit code %d:\n%s",
-				HookPre, branchType, action, result.ExitCode, result.Output)
+	hookName := resolveHookName(gitDir, HookPre, branchType, action)
+	return withConfigResponse(gitDir, "pre-hook '"+hookName+"'", ctx.AmendConfig, buildHookEnv(ctx, HookPre), func(env []string) error {
+		result := executeHook(gitDir, hookName, BuildHookArgs(action, ctx), env)
+		if result.Error != nil {
+			return result.Error
 		}
-		return fmt.Errorf("pre-hook '%s-flow-%s-%s' failed with exit code %d",
-			HookPre, branchType, action, result.ExitCode)
-	}
-	return nil
+		if result.Executed && result.ExitCode != 0 {
+			if result.Output != "" {
+				return fmt.Errorf("pre-hook '%s' failed with exit code %d:\n%s",
+					hookName, result.ExitCode, result.Output)
+			}
+			return fmt.Errorf("pre-hook '%s' failed with exit code %d",
+				hookName, result.ExitCode)
+		}
+		return nil
+	})
 }
 
 // RunPostHook executes a post-hook script. The result is returned but errors do not
 // cause the operation to fail. If the hook does not exist or is not executable,
 // it returns a result with Executed=false.
 func RunPostHook(gitDir string, branchType string, action HookAction, ctx HookContext) HookResult {
-	return runHook(gitDir, HookPost, branchType, action, ctx)
+	return runHook(gitDir, resolveHookName(gitDir, HookPost, branchType, action), HookPost, action, ctx)
+}
+
+// FindNonExecutableScripts returns the names of hook and filter scripts for the given
+// branch type and action that exist but lack the executable bit. Such scripts are
+// skipped silently at run time, which is rarely what the author intended.
+func FindNonExecutableScripts(gitDir string, branchType string, action HookAction) []string {
+	hooksDir := getHooksDir(gitDir)
+
+	candidates := []string{
+		resolveHookName(gitDir, HookPre, branchType, action),
+		resolveHookName(gitDir, HookPost, branchType, action),
+	}
+	filters, _ := filepath.Glob(filepath.Join(hooksDir, fmt.Sprintf("filter-flow-%s-%s-*", branchType, action)))
+	for _, filter := range filters {
+		candidates = append(candidates, filepath.Base(filter))
+	}
+
+	nonExecutable := []string{}
+	for _, name := range candidates {
+		path := filepath.Join(hooksDir, name)
+		if _, err := os.Stat(path); err == nil && !isExecutable(path) {
+			nonExecutable = append(nonExecutable, name)
+		}
+	}
+	return nonExecutable
+}
+
+// workTreeRoot returns the directory hooks run in: the root of the working
+// tree, like Git runs its own hooks, which differs from the parent of the git
+// directory with GIT_WORK_TREE or core.worktree
+func workTreeRoot(gitDir string) string {
+	parent := filepath.Dir(gitDir)
+	if root, err := git.GetTopLevelDirInDir(parent); err == nil && root != "" {
+		return root
+	}
+	return parent
 }
 
 // getHooksDir returns the directory where hooks are stored.
@@ -90,9 +136,26 @@ func BuildHookArgs(action HookAction, ctx HookContext) []string {
 	}
 }
 
-// runHook executes a hook script and returns the result.
-func runHook(gitDir string, phase HookPhase, branchType string, action HookAction, ctx HookContext) HookResult {
+// resolveHookName returns the name of the hook script to run for the given branch
+// type and action. The type-specific hook ({phase}-flow-{type}-{action}) takes
+// precedence; when it does not exist, the generic topic hook
+// ({phase}-flow-topic-{action}) is used so one script can handle all branch types.
+func resolveHookName(gitDir string, phase HookPhase, branchType string, action HookAction) string {
 	hookName := fmt.Sprintf("%s-flow-%s-%s", phase, branchType, action)
+	if _, err := os.Stat(filepath.Join(getHooksDir(gitDir), hookName)); !os.IsNotExist(err) {
+		return hookName
+	}
+	return fmt.Sprintf("%s-flow-%s-%s", phase, GenericHookType, action)
+}
+
+// runHook executes a hook script and returns the result.
+func runHook(gitDir string, hookName string, phase HookPhase, action HookAction, ctx HookContext) HookResult {
+	// Build positional arguments for git-flow-avh compatibility
+	return executeHook(gitDir, hookName, BuildHookArgs(action, ctx), buildHookEnv(ctx, phase))
+}
+
+// executeHook runs the named hook script with the given arguments and environment.
+func executeHook(gitDir string, hookName string, args []string, env []string) HookResult {
 	hooksDir := getHooksDir(gitDir)
 	hookPath := filepath.Join(hooksDir, hookName)
 
@@ -111,19 +174,18 @@ func runHook(gitDir string, phase HookPhase, branchType string, action HookActio
 		return HookResult{Executed: false}
 	}
 
-	// Build environment variables
-	env := buildHookEnv(ctx, phase)
-
-	// Build positional arguments for git-flow-avh compatibility
-	args := BuildHookArgs(action, ctx)
+	defer profile.Start("hook " + hookName)()
 
 	// Execute hook with arguments
 	cmd := exec.Command(hookPath, args...)
 	cmd.Env = env
-	cmd.Dir = filepath.Dir(gitDir) // Repository root
+	cmd.Dir = workTreeRoot(gitDir)
 
 	output, err := cmd.CombinedOutput()
 
+	// Hook scripts may change gitflow.* configuration
+	git.InvalidateConfigSnapshot()
+
 	exitCode := 0
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -161,6 +223,22 @@ func buildHookEnv(ctx HookContext, phase HookPhase) []string {
 		env = append(env, fmt.Sprintf("VERSION=%s", ctx.Version))
 	}
 
+	if ctx.BaseSignatureStatus != "" {
+		env = append(env,
+			fmt.Sprintf("BASE_SIGNATURE_STATUS=%s", ctx.BaseSignatureStatus),
+			fmt.Sprintf("BASE_SIGNATURE_KEY=%s", ctx.BaseSignatureKey),
+			fmt.Sprintf("BASE_SIGNATURE_ERROR=%s", ctx.BaseSignatureError),
+		)
+	}
+
+	if ctx.ReleaseNotesFile != "" {
+		env = append(env, fmt.Sprintf("RELEASE_NOTES_FILE=%s", ctx.ReleaseNotesFile))
+	}
+
+	if ctx.Finish != nil {
+		env = append(env, buildFinishResultEnv(ctx.Finish)...)
+	}
+
 	// For post-hooks, include the exit code of the operation
 	if phase == HookPost {
 		env = append(env, fmt.Sprintf("EXIT_CODE=%d", ctx.ExitCode))
@@ -169,9 +247,31 @@ func buildHookEnv(ctx HookContext, phase HookPhase) []string {
 	return env
 }
 
+// buildFinishResultEnv builds the environment variables describing a completed finish.
+func buildFinishResultEnv(result *FinishResult) []string {
+	names := make([]string, 0, len(result.UpdatedBranches))
+	strategies := make([]string, 0, len(result.UpdatedBranches))
+	for _, branch := range result.UpdatedBranches {
+		names = append(names, branch.Name)
+		strategies = append(strategies, fmt.Sprintf("%s:%s", branch.Name, branch.Strategy))
+	}
+
+	env := []string{
+		fmt.Sprintf("TAG_NAME=%s", result.TagName),
+		fmt.Sprintf("MERGE_COMMIT=%s", result.MergeCommit),
+		fmt.Sprintf("UPDATED_BRANCHES=%s", strings.Join(names, " ")),
+		fmt.Sprintf("UPDATED_BRANCH_STRATEGIES=%s", strings.Join(strategies, " ")),
+	}
+	if data, err := json.Marshal(result); err == nil {
+		env = append(env, fmt.Sprintf("GITFLOW_RESULT_JSON=%s", data))
+	}
+	return env
+}
+
 // WithHooks wraps an operation with pre and post hooks.
 // The pre-hook is run before the operation. If it fails, the operation is not executed.
-// The post-hook is run after the operation, regardless of success or failure.
+// The post-hook is run after the operation, regardless of success or failure,
+// followed by the notifier plugins.
 // The context's ExitCode is set based on the operation result before running the post-hook.
 func WithHooks(gitDir string, branchType string, action HookAction, ctx HookContext, operation func() error) error {
 	// Run pre-hook
@@ -195,6 +295,7 @@ func WithHooks(gitDir string, branchType string, action HookAction, ctx HookCont
 		// Print post-hook output for visibility
 		fmt.Print(result.Output)
 	}
+	Notify(gitDir, BranchEvent(action, ctx))
 
 	return opErr
 }
@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// scriptPrefixes are the name prefixes of the scripts git-flow runs
+var scriptPrefixes = []string{
+	string(HookPre) + "-flow-",
+	string(HookPost) + "-flow-",
+	"filter-flow-",
+}
+
+// isFlowScript reports whether name is a git-flow hook or filter script name
+func isFlowScript(name string) bool {
+	for _, prefix := range scriptPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallScripts copies the hook and filter scripts in sourceDir into the hooks
+// directory of the repository and makes them executable. Other files, such as a
+// README, are ignored. Existing scripts with different content are only replaced
+// when overwrite is set; otherwise nothing is installed and an error names them.
+// The names of the installed scripts are returned in sorted order.
+func InstallScripts(gitDir string, sourceDir string, overwrite bool) ([]string, error) {
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook scripts: %w", err)
+	}
+
+	hooksDir := getHooksDir(gitDir)
+	scripts := make(map[string][]byte)
+	var conflicts []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isFlowScript(entry.Name()) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(sourceDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read hook script '%s': %w", entry.Name(), err)
+		}
+		scripts[entry.Name()] = content
+
+		existing, err := os.ReadFile(filepath.Join(hooksDir, entry.Name()))
+		if err == nil && !bytes.Equal(existing, content) {
+			conflicts = append(conflicts, entry.Name())
+		}
+	}
+
+	if len(conflicts) > 0 && !overwrite {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("hook scripts already exist with different content: %s", strings.Join(conflicts, ", "))
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	installed := make([]string, 0, len(scripts))
+	for name, content := range scripts {
+		path := filepath.Join(hooksDir, name)
+		if err := os.WriteFile(path, content, 0755); err != nil {
+			return nil, fmt.Errorf("failed to install hook script '%s': %w", name, err)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(path, 0755); err != nil {
+			return nil, fmt.Errorf("failed to make hook script '%s' executable: %w", name, err)
+		}
+		installed = append(installed, name)
+	}
+	sort.Strings(installed)
+	return installed, nil
+}
@@ -0,0 +1,246 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/profile"
+)
+
+// Notifier plugins receive a JSON description of every completed operation on
+// stdin. Unlike hooks they are not installed per repository: any executable
+// named gitflow-notify-<name> on PATH is run, as well as the plugins listed in
+// gitflow.notify.plugin. Setting gitflow.notify.discover to false limits
+// notifications to the listed plugins.
+
+// NotifierPrefix is the name prefix of notifier plugin executables.
+const NotifierPrefix = "gitflow-notify-"
+
+// NotifyProtocolVersion is the version of the JSON payload passed to notifiers.
+// It is increased when fields change meaning or are removed.
+const NotifyProtocolVersion = 1
+
+// notifyTimeout bounds how long a single notifier may run.
+const notifyTimeout = 30 * time.Second
+
+// NotifyEvent is the payload a notifier plugin receives on stdin.
+type NotifyEvent struct {
+	Protocol   int    `json:"protocol"`
+	Event      string `json:"event"` // <type>.<action> for branch actions, <action> for repository actions
+	Action     string `json:"action"`
+	Success    bool   `json:"success"`
+	ExitCode   int    `json:"exitCode"`
+	Repository string `json:"repository"` // Root of the repository
+	Timestamp  string `json:"timestamp"`  // RFC 3339
+
+	BranchType string `json:"branchType,omitempty"`
+	BranchName string `json:"branchName,omitempty"`
+	FullBranch string `json:"branch,omitempty"`
+	BaseBranch string `json:"baseBranch,omitempty"`
+	Origin     string `json:"origin,omitempty"`
+	Version    string `json:"version,omitempty"`
+
+	Finish *FinishResult `json:"finish,omitempty"` // For finish: outcome of the finish
+	Config *ConfigChange `json:"config,omitempty"` // For config: the configuration change
+}
+
+// ConfigChange describes a configuration change in a notification.
+type ConfigChange struct {
+	Change    string `json:"change"`
+	Branch    string `json:"branch,omitempty"`
+	OldBranch string `json:"oldBranch,omitempty"`
+}
+
+// NotifyResult is the result of running one notifier plugin.
+type NotifyResult struct {
+	Notifier string // Path of the plugin executable
+	HookResult
+}
+
+// BranchEvent builds the notification for a branch action from its hook context.
+func BranchEvent(action HookAction, ctx HookContext) NotifyEvent {
+	return NotifyEvent{
+		Event:      fmt.Sprintf("%s.%s", ctx.BranchType, action),
+		Action:     string(action),
+		Success:    ctx.ExitCode == 0,
+		ExitCode:   ctx.ExitCode,
+		BranchType: ctx.BranchType,
+		BranchName: ctx.BranchName,
+		FullBranch: ctx.FullBranch,
+		BaseBranch: ctx.BaseBranch,
+		Origin:     ctx.Origin,
+		Version:    ctx.Version,
+		Finish:     ctx.Finish,
+	}
+}
+
+// RepoEvent builds the notification for a repository action from its hook context.
+func RepoEvent(action HookAction, ctx RepoHookContext) NotifyEvent {
+	event := NotifyEvent{
+		Event:    string(action),
+		Action:   string(action),
+		Success:  ctx.ExitCode == 0,
+		ExitCode: ctx.ExitCode,
+		Origin:   ctx.Origin,
+	}
+	if ctx.Change != "" {
+		event.Config = &ConfigChange{Change: ctx.Change, Branch: ctx.Branch, OldBranch: ctx.OldBranch}
+	}
+	return event
+}
+
+// Notify sends event to every notifier plugin. Notifiers can't affect the
+// operation: failures are reported as warnings on stderr and returned.
+func Notify(gitDir string, event NotifyEvent) []NotifyResult {
+	repoRoot := workTreeRoot(gitDir)
+	notifiers, missing := FindNotifiers(repoRoot)
+	for _, name := range missing {
+		fmt.Fprintf(os.Stderr, "Warning: notifier '%s' from gitflow.notify.plugin not found\n", name)
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	event.Protocol = NotifyProtocolVersion
+	event.Repository = repoRoot
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode notification: %v\n", err)
+		return nil
+	}
+
+	results := make([]NotifyResult, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		result := runNotifier(notifier, event.Event, payload, repoRoot)
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Warning: notifier '%s' failed: %v\n", filepath.Base(notifier), result.Error)
+		} else if result.ExitCode != 0 {
+			fmt.Fprintf(os.Stderr, "Warning: notifier '%s' failed with exit code %d\n", filepath.Base(notifier), result.ExitCode)
+		}
+		if (result.Error != nil || result.ExitCode != 0) && result.Output != "" {
+			fmt.Fprint(os.Stderr, result.Output)
+		}
+		results = append(results, NotifyResult{Notifier: notifier, HookResult: result})
+	}
+	return results
+}
+
+// FindNotifiers returns the notifier plugins to run for the repository at
+// repoRoot: the plugins listed in gitflow.notify.plugin followed by those found
+// on PATH, each once. Listed plugins that can't be found are returned as missing.
+func FindNotifiers(repoRoot string) (notifiers []string, missing []string) {
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			notifiers = append(notifiers, path)
+		}
+	}
+
+	configured, _ := git.GetConfigAllValuesInDir(repoRoot, config.KeyNotifyPlugin)
+	for _, name := range configured {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if path, ok := resolveNotifier(name, repoRoot); ok {
+			add(path)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if discover, err := git.GetConfigInDir(repoRoot, config.KeyNotifyDiscover); err == nil {
+		if enabled, ok := config.ParseBool(discover); ok && !enabled {
+			return notifiers, missing
+		}
+	}
+	for _, path := range discoverNotifiers() {
+		add(path)
+	}
+	return notifiers, missing
+}
+
+// resolveNotifier finds a plugin listed in gitflow.notify.plugin. A name with a
+// path separator is a path, relative to the repository root; otherwise it is
+// looked up on PATH, with or without the gitflow-notify- prefix.
+func resolveNotifier(name string, repoRoot string) (string, bool) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.ContainsRune(name, '/') {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(repoRoot, name)
+		}
+		return name, isExecutable(name)
+	}
+	if !strings.HasPrefix(name, NotifierPrefix) {
+		name = NotifierPrefix + name
+	}
+	path, err := exec.LookPath(name)
+	return path, err == nil
+}
+
+// discoverNotifiers returns the executables on PATH named gitflow-notify-*. Like
+// the shell, the first directory on PATH wins when a name appears more than once.
+func discoverNotifiers() []string {
+	byName := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, NotifierPrefix+"*"))
+		for _, path := range matches {
+			name := filepath.Base(path)
+			if _, exists := byName[name]; exists || !isExecutable(path) {
+				continue
+			}
+			byName[name] = path
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, byName[name])
+	}
+	return paths
+}
+
+// runNotifier runs one notifier with the event name as argument and the JSON
+// payload on stdin.
+func runNotifier(path string, eventName string, payload []byte, repoRoot string) HookResult {
+	defer profile.Start("notify " + filepath.Base(path))()
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, eventName)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("GITFLOW_EVENT=%s", eventName))
+	cmd.Dir = repoRoot
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return HookResult{Executed: true, ExitCode: 1, Output: string(output), Error: fmt.Errorf("timed out after %s", notifyTimeout)}
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return HookResult{Executed: true, ExitCode: exitErr.ExitCode(), Output: string(output)}
+		}
+		return HookResult{Executed: true, ExitCode: 1, Output: string(output), Error: err}
+	}
+	return HookResult{Executed: true, ExitCode: 0, Output: string(output)}
+}
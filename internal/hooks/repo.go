@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+)
+
+// Repository hooks run around changes to the branching model rather than
+// around actions on a branch, so their names carry no branch type:
+//   - {pre,post}-flow-init around git flow init
+//   - {pre,post}-flow-config around configuration changes
+
+// RunRepoPreHook executes the pre-hook of a repository action. Returns an error if
+// the hook fails (non-zero exit). A missing or non-executable hook is skipped.
+func RunRepoPreHook(gitDir string, action HookAction, ctx RepoHookContext) error {
+	hookName := fmt.Sprintf("%s-flow-%s", HookPre, action)
+	result := executeHook(gitDir, hookName, BuildRepoHookArgs(action, ctx), buildRepoHookEnv(ctx, HookPre))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Executed && result.ExitCode != 0 {
+		if result.Output != "" {
+			return fmt.Errorf("pre-hook '%s' failed with exit code %d:\n%s", hookName, result.ExitCode, result.Output)
+		}
+		return fmt.Errorf("pre-hook '%s' failed with exit code %d", hookName, result.ExitCode)
+	}
+	return nil
+}
+
+// RunRepoPostHook executes the post-hook of a repository action. The result is
+// returned but errors do not cause the operation to fail.
+func RunRepoPostHook(gitDir string, action HookAction, ctx RepoHookContext) HookResult {
+	hookName := fmt.Sprintf("%s-flow-%s", HookPost, action)
+	return executeHook(gitDir, hookName, BuildRepoHookArgs(action, ctx), buildRepoHookEnv(ctx, HookPost))
+}
+
+// WithRepoHooks wraps an operation with the pre and post hooks of a repository
+// action and notifies the notifier plugins, like WithHooks does for branch actions.
+func WithRepoHooks(gitDir string, action HookAction, ctx RepoHookContext, operation func() error) error {
+	if err := RunRepoPreHook(gitDir, action, ctx); err != nil {
+		return err
+	}
+
+	opErr := operation()
+
+	if opErr != nil {
+		ctx.ExitCode = 1
+	} else {
+		ctx.ExitCode = 0
+	}
+
+	// Run post-hook (ignore errors from post-hook)
+	result := RunRepoPostHook(gitDir, action, ctx)
+	if result.Executed && result.Output != "" {
+		fmt.Print(result.Output)
+	}
+	Notify(gitDir, RepoEvent(action, ctx))
+
+	return opErr
+}
+
+// BuildRepoHookArgs constructs the positional arguments for a repository hook.
+//
+// Arguments by action:
+//   - init:   [origin]
+//   - config: [change, branch, old branch]
+func BuildRepoHookArgs(action HookAction, ctx RepoHookContext) []string {
+	switch action {
+	case HookActionConfig:
+		return []string{ctx.Change, ctx.Branch, ctx.OldBranch}
+	default:
+		return []string{ctx.Origin}
+	}
+}
+
+// buildRepoHookEnv builds environment variables for repository hook execution.
+func buildRepoHookEnv(ctx RepoHookContext, phase HookPhase) []string {
+	env := os.Environ()
+	env = append(env,
+		fmt.Sprintf("ORIGIN=%s", ctx.Origin),
+		fmt.Sprintf("CONFIG_FILE=%s", ctx.ConfigFile),
+	)
+
+	if ctx.Change != "" {
+		env = append(env,
+			fmt.Sprintf("CONFIG_CHANGE=%s", ctx.Change),
+			fmt.Sprintf("CONFIG_BRANCH=%s", ctx.Branch),
+			fmt.Sprintf("CONFIG_OLD_BRANCH=%s", ctx.OldBranch),
+		)
+	}
+
+	// For post-hooks, include the exit code of the operation
+	if phase == HookPost {
+		env = append(env, fmt.Sprintf("EXIT_CODE=%d", ctx.ExitCode))
+	}
+
+	return env
+}
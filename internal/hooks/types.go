@@ -5,7 +5,11 @@
 //
 // All scripts are located in .git/hooks/ following these patterns:
 //   - Filters: filter-flow-{type}-{action}-{target}
-//   - Hooks: {pre,post}-flow-{type}-{action}
+//   - Hooks: {pre,post}-flow-{type}-{action}, falling back to {pre,post}-flow-topic-{action}
+//   - Repository hooks: {pre,post}-flow-{init,config}
+//
+// Notifier plugins (gitflow-notify-* on PATH) receive every completed operation
+// as JSON on stdin.
 package hooks
 
 import "fmt"
@@ -49,6 +53,10 @@ const (
 	HookPost HookPhase = "post"
 )
 
+// GenericHookType is the type name of hooks that run for every topic branch type
+// without a type-specific hook of its own (e.g., pre-flow-topic-start).
+const GenericHookType = "topic"
+
 // HookAction represents the git-flow action being performed.
 type HookAction string
 
@@ -70,6 +78,12 @@ const (
 
 	// HookActionUpdate is the update action.
 	HookActionUpdate HookAction = "update"
+
+	// HookActionInit is the repository initialization action.
+	HookActionInit HookAction = "init"
+
+	// HookActionConfig is the configuration change action.
+	HookActionConfig HookAction = "config"
 )
 
 // HookContext contains data passed to hooks via environment variables.
@@ -81,6 +95,48 @@ type HookContext struct {
 	Origin     string // The remote name
 	Version    string // The version (for branches with tagging)
 	ExitCode   int    // For post-hooks: exit code of the operation
+
+	BaseSignatureStatus string // For pre-finish hooks: %G? status of the base branch tip, if verified
+	BaseSignatureKey    string // For pre-finish hooks: key that signed the base branch tip
+	BaseSignatureError  string // For pre-finish hooks: why the signature check failed, empty if it passed
+
+	ReleaseNotesFile string        // For post-finish hooks: file holding the collected release notes
+	Finish           *FinishResult // For post-finish hooks: outcome of the finish
+
+	AmendConfig ConfigAmender // For pre-hooks: applies the configuration the hook wrote, if allowed
+}
+
+// FinishResult describes a completed finish. Post-finish hooks receive it as
+// individual environment variables and as JSON in GITFLOW_RESULT_JSON.
+type FinishResult struct {
+	BranchType       string          `json:"branchType"`
+	BranchName       string          `json:"branchName"`
+	FullBranch       string          `json:"branch"`
+	BaseBranch       string          `json:"baseBranch"`
+	MergeStrategy    string          `json:"mergeStrategy"`
+	MergeCommit      string          `json:"mergeCommit"`     // Commit of the base branch after the merge
+	TagName          string          `json:"tag"`             // Tag created by finish, empty if none
+	ExtraTags        []string        `json:"extraTags"`       // Extra tags moved by finish
+	UpdatedBranches  []UpdatedBranch `json:"updatedBranches"` // Child branches updated from the base branch
+	ReleaseNotesFile string          `json:"releaseNotesFile,omitempty"`
+}
+
+// UpdatedBranch is a child branch updated by finish
+type UpdatedBranch struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"`
+	Commit   string `json:"commit"` // Commit of the child branch after the update
+}
+
+// RepoHookContext contains data passed to repository hooks (init and config)
+// via environment variables.
+type RepoHookContext struct {
+	Change     string // For config hooks: the kind of change (e.g., "add-base", "import")
+	Branch     string // For config hooks: the configured branch the change affects, if any
+	OldBranch  string // For config hooks: the previous name of a renamed branch
+	Origin     string // The remote name
+	ConfigFile string // YAML file holding the configuration resulting from the change
+	ExitCode   int    // For post-hooks: exit code of the operation
 }
 
 // HookResult contains the result of hook execution.
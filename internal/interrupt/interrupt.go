@@ -0,0 +1,118 @@
+// Package interrupt defers SIGINT and SIGTERM while a multi-step operation runs,
+// so the operation can stop at the next step boundary with consistent state
+// instead of being killed halfway through. The git processes of a step run in
+// a process group of their own (see internal/git), so the SIGINT a terminal
+// sends on Ctrl-C does not stop them either.
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Watcher records the first SIGINT or SIGTERM an operation receives. Each
+// operation watches with a Watcher of its own; a signal reaches all of them.
+type Watcher struct {
+	mu       sync.Mutex
+	received os.Signal
+	signals  chan os.Signal
+	done     chan struct{}
+}
+
+// Watch starts trapping SIGINT and SIGTERM. The first signal is recorded and can
+// be queried with Received; a second signal exits immediately, leaving a running
+// git process to complete on its own. Stop ends the trapping and restores the
+// default signal behavior.
+func Watch() *Watcher {
+	return watch(func() {})
+}
+
+// NotifyContext is like Watch, but also returns a copy of parent that is
+// cancelled when the first signal arrives, and a function that stops watching.
+func NotifyContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	w := watch(cancel)
+	return ctx, func() {
+		w.Stop()
+		cancel()
+	}
+}
+
+// watch implements Watch and calls onFirst when the first signal arrives
+func watch(onFirst func()) *Watcher {
+	w := &Watcher{
+		signals: make(chan os.Signal, 2),
+		done:    make(chan struct{}),
+	}
+	signal.Notify(w.signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		for {
+			select {
+			case sig := <-w.signals:
+				w.mu.Lock()
+				first := w.received == nil
+				if first {
+					w.received = sig
+				}
+				w.mu.Unlock()
+
+				if first {
+					fmt.Fprintf(os.Stderr, "\nReceived %s, stopping after the current step (press Ctrl-C again to exit immediately)\n", Name(sig))
+					onFirst()
+					continue
+				}
+				fmt.Fprintf(os.Stderr, "\nReceived %s again, exiting immediately\n", Name(sig))
+				os.Exit(130)
+			case <-w.done:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// Stop ends trapping the signals for this watcher
+func (w *Watcher) Stop() {
+	signal.Stop(w.signals)
+	close(w.done)
+}
+
+// Received returns the signal caught since Watch was called, or nil if none.
+func (w *Watcher) Received() os.Signal {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.received
+}
+
+// Stopped reports whether the operation should stop at this step boundary:
+// a signal was received or ctx was cancelled.
+func (w *Watcher) Stopped(ctx context.Context) bool {
+	return ctx.Err() != nil || w.Received() != nil
+}
+
+// Reason describes what stopped the operation: the name of the signal
+// received since Watch was called, or "cancellation" when the context was
+// cancelled by the caller.
+func (w *Watcher) Reason() string {
+	if sig := w.Received(); sig != nil {
+		return Name(sig)
+	}
+	return "cancellation"
+}
+
+// Name returns the conventional name of a signal, e.g. "SIGINT".
+func Name(sig os.Signal) string {
+	switch sig {
+	case os.Interrupt:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	default:
+		return sig.String()
+	}
+}
@@ -0,0 +1,146 @@
+// Package journal records the operations git-flow performed that leave work
+// for later, so it can be picked up by another command. The journal is kept
+// in gitflow/journal in the common git directory, shared by all worktrees,
+// with one JSON entry per line.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+const journalFile = "gitflow/journal"
+
+// Operations recorded in the journal
+const (
+	OperationFinish    = "finish"
+	OperationSyncBases = "sync-bases"
+)
+
+// Update is the update of a base branch from its parent
+type Update struct {
+	Branch string `json:"branch"`
+	Parent string `json:"parent"`
+}
+
+// Entry is one operation in the journal
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Branch    string    `json:"branch,omitempty"` // branch the operation ran on, e.g. the finished topic branch
+
+	// Child updates skipped with finish --no-update
+	SkippedUpdates []Update `json:"skippedUpdates,omitempty"`
+	// Skipped updates that were made up for later
+	ReconciledUpdates []Update `json:"reconciledUpdates,omitempty"`
+}
+
+func path() (string, error) {
+	commonDir, err := git.GetCommonGitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, journalFile), nil
+}
+
+// Append adds an entry to the journal, stamping it with the current time if
+// it has none
+func Append(entry Entry) error {
+	journalPath, err := path()
+	if err != nil {
+		return err
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(journalPath), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	file, err := os.OpenFile(journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Load returns the entries of the journal, oldest first. Lines that cannot be
+// decoded are skipped.
+func Load() ([]Entry, error) {
+	journalPath, err := path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(journalPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// PendingUpdates returns the skipped child updates that have not been
+// reconciled yet, oldest first and each branch only once
+func PendingUpdates() ([]Update, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Update
+	for _, entry := range entries {
+		for _, skipped := range entry.SkippedUpdates {
+			if !containsUpdate(pending, skipped) {
+				pending = append(pending, skipped)
+			}
+		}
+		for _, reconciled := range entry.ReconciledUpdates {
+			for i, update := range pending {
+				if update == reconciled {
+					pending = append(pending[:i], pending[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return pending, nil
+}
+
+func containsUpdate(updates []Update, update Update) bool {
+	for _, existing := range updates {
+		if existing == update {
+			return true
+		}
+	}
+	return false
+}
@@ -6,27 +6,48 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
 )
 
 const (
 	stateDirName = "gitflow/state"
 	stateFile    = "merge.json"
+	backupSuffix = ".bak"
 )
 
-// getStateDir returns the path to the state directory, resolving the git directory
-// correctly for both regular repos and worktrees.
-func getStateDir() (string, error) {
-	gitDir, err := git.GetGitDir()
-	if err != nil {
-		return "", err
+// Steps of a finish, in the order they run
+const (
+	StepMerge          = "merge"
+	StepCreateTag      = "create_tag"
+	StepUpdateChildren = "update_children"
+	StepExtraTags      = "extra_tags"
+	StepPush           = "push"
+	StepDeleteBranch   = "delete_branch"
+)
+
+// Store keeps the merge state in the git directory GitDir. The zero Store
+// uses the git directory of the repository in the current directory, which
+// resolves correctly for both regular repos and worktrees.
+type Store struct {
+	GitDir string
+}
+
+// stateDir returns the path to the state directory
+func (s Store) stateDir() (string, error) {
+	gitDir := s.GitDir
+	if gitDir == "" {
+		var err error
+		if gitDir, err = git.GetGitDir(); err != nil {
+			return "", err
+		}
 	}
 	return filepath.Join(gitDir, stateDirName), nil
 }
 
-// getStatePath returns the full path to the state file.
-func getStatePath() (string, error) {
-	stateDir, err := getStateDir()
+// statePath returns the full path to the state file.
+func (s Store) statePath() (string, error) {
+	stateDir, err := s.stateDir()
 	if err != nil {
 		return "", err
 	}
@@ -35,10 +56,10 @@ func getStatePath() (string, error) {
 
 // MergeState represents the state of a merge operation
 type MergeState struct {
-	Action          string   `json:"action"`          // "finish"
+	Action          string   `json:"action"`          // "finish", "update" or "sync-bases"
 	BranchType      string   `json:"branchType"`      // feature, release, hotfix, etc.
 	BranchName      string   `json:"branchName"`      // name of the branch being merged
-	CurrentStep     string   `json:"currentStep"`     // current step in the process (merge, update_children, delete_branch)
+	CurrentStep     string   `json:"currentStep"`     // current step in the process (merge, create_tag, update_children, extra_tags, push, delete_branch)
 	ParentBranch    string   `json:"parentBranch"`    // target branch for the merge
 	MergeStrategy   string   `json:"mergeStrategy"`   // merge strategy being used
 	FullBranchName  string   `json:"fullBranchName"`  // full name of the branch (with prefix)
@@ -48,6 +69,8 @@ type MergeState struct {
 	// Enhanced child branch tracking
 	CurrentChildBranch string            `json:"currentChildBranch,omitempty"` // The child branch currently being updated
 	ChildStrategies    map[string]string `json:"childStrategies,omitempty"`    // Merge strategies for each child branch
+	SkippedBranches    []string          `json:"skippedBranches,omitempty"`    // Child branches not updated: because of --no-update (finish), or already up to date (sync-bases)
+	ChildParents       map[string]string `json:"childParents,omitempty"`       // Branch each child is updated from, when it differs per child (sync-bases)
 
 	// Squash merge options
 	SquashMessage string `json:"squashMessage,omitempty"` // Custom commit message for squash merge
@@ -56,14 +79,95 @@ type MergeState struct {
 	MergeMessage  string `json:"mergeMessage,omitempty"`  // Custom commit message for upstream merge
 	UpdateMessage string `json:"updateMessage,omitempty"` // Custom commit message for child updates
 
+	// Commit the branch being merged into pointed to when the merge or the
+	// current child update started, to recognize a commit made outside git-flow
+	StepStartCommit string `json:"stepStartCommit,omitempty"`
+
+	// Branch that must be checked out to continue a merge or squash stopped
+	// on a conflict; empty for rebases, which detach HEAD
+	ExpectedHead string `json:"expectedHead,omitempty"`
+
+	// Stash untracked files a child checkout would overwrite, and the stash
+	// commit holding them until the child updates are done
+	AutostashUntracked bool   `json:"autostashUntracked,omitempty"`
+	AutostashCommit    string `json:"autostashCommit,omitempty"`
+
+	// Tag created by the create_tag step
+	TagName string `json:"tagName,omitempty"`
+
+	// What to do when the tag exists (--retag or --skip-tag), and whether it was moved
+	ExistingTag string `json:"existingTag,omitempty"`
+	Retagged    bool   `json:"retagged,omitempty"`
+
+	// The branch was already merged into the parent, so the merge is skipped
+	AlreadyMerged bool `json:"alreadyMerged,omitempty"`
+
+	// Update the child branches from the created tag instead of the parent
+	MergeTagToChildren bool `json:"mergeTagToChildren,omitempty"`
+
+	// Tag message written in the editor with --edit, kept for --continue
+	EditedTagMessage string `json:"editedTagMessage,omitempty"`
+
+	// Commits the parent and child branches point to after the merge and the
+	// child updates, passed to the post-finish hook
+	MergeCommit  string            `json:"mergeCommit,omitempty"`
+	ChildCommits map[string]string `json:"childCommits,omitempty"`
+
+	// Release notes written by the create_tag step, passed to the post-finish hook
+	ReleaseNotesFile string `json:"releaseNotesFile,omitempty"`
+
+	// Extra tags moved by the extra_tags step
+	ExtraTags []ExtraTag `json:"extraTags,omitempty"`
+
+	// Published child branches rebased with --force, pushed with a lease
+	ForcePushBranches []string `json:"forcePushBranches,omitempty"`
+
+	// Branches still to finish after this one with --batch
+	Batch []string `json:"batch,omitempty"`
+
+	// Configuration the pre-hook amended for this finish, applied again on --continue
+	ConfigAmendments map[string]string `json:"configAmendments,omitempty"`
+
+	// Push the parent, child branches and tags in the push step
+	Push bool `json:"push,omitempty"`
+
+	// Push only the created tag in the push step (ignored when Push is set)
+	PushTag bool `json:"pushTag,omitempty"`
+
+	// Maintenance branches the finished branch is backported to once the finish
+	// is complete, the commits to cherry-pick, oldest first, and whether a pull
+	// request is opened for each backport
+	Backports       []string `json:"backports,omitempty"`
+	BackportCommits []string `json:"backportCommits,omitempty"`
+	BackportPR      bool     `json:"backportPR,omitempty"`
+
 	// Hook options
-	NoVerify bool `json:"noVerify,omitempty"` // Skip pre-commit and commit-msg hooks
+	NoVerify         bool `json:"noVerify,omitempty"`         // Skip pre-commit and commit-msg hooks
+	NoVerifyChildren bool `json:"noVerifyChildren,omitempty"` // Also skip them when updating child branches
+
+	// Interruption tracking
+	Interrupted bool `json:"interrupted,omitempty"` // Stopped by a signal between steps rather than by a conflict
 }
 
-// SaveMergeState saves the current merge state to a file
+// ExtraTag is an additional lightweight tag pointed at a branch after finishing
+type ExtraTag struct {
+	Name   string `json:"name"`   // tag name with placeholders expanded
+	Target string `json:"target"` // branch the tag points to
+}
+
+// SaveMergeState saves the current merge state of the repository in the
+// current directory to a file
 func SaveMergeState(state *MergeState) error {
+	return Store{}.Save(state)
+}
+
+// Save saves the merge state to a file.
+// The write is atomic: the state is written to a temporary file, synced and
+// renamed into place, so an interrupted write never leaves truncated JSON
+// behind. The previous state is kept as merge.json.bak.
+func (s Store) Save(state *MergeState) error {
 	// Get the state directory path (handles worktrees correctly)
-	stateDir, err := getStateDir()
+	stateDir, err := s.stateDir()
 	if err != nil {
 		return fmt.Errorf("failed to determine state directory: %w", err)
 	}
@@ -79,18 +183,78 @@ func SaveMergeState(state *MergeState) error {
 		return fmt.Errorf("failed to marshal state: %w", err)
 	}
 
-	// Write state to file
+	// Keep the previous state as a backup, but only if it is intact
 	statePath := filepath.Join(stateDir, stateFile)
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
+	if previous, err := os.ReadFile(statePath); err == nil && json.Valid(previous) {
+		if err := writeFileAtomic(statePath+backupSuffix, previous); err != nil {
+			return fmt.Errorf("failed to write state backup: %w", err)
+		}
+	}
+
+	// Write state to file
+	if err := writeFileAtomic(statePath, data); err != nil {
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
 
 	return nil
 }
 
-// LoadMergeState loads the current merge state from file
+// writeFileAtomic replaces path with data via a synced temporary file and a rename
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file on any failure before the rename
+	committed := false
+	defer func() {
+		if !committed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	committed = true
+
+	// Sync the directory so the rename itself survives a crash
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// LoadMergeState loads the current merge state of the repository in the
+// current directory, or returns nil if there is none
 func LoadMergeState() (*MergeState, error) {
-	statePath, err := getStatePath()
+	return Store{}.Load()
+}
+
+// Load loads the merge state from file. A state file that no longer parses is
+// reported as an UnreadableStateError rather than replaced by its backup: the
+// backup is one step behind, so resuming from it would run a completed step,
+// such as creating the tag or pushing, again.
+func (s Store) Load() (*MergeState, error) {
+	statePath, err := s.statePath()
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine state path: %w", err)
 	}
@@ -105,28 +269,177 @@ func LoadMergeState() (*MergeState, error) {
 
 	var state MergeState
 	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+		unreadable := &errors.UnreadableStateError{Path: statePath, Err: err}
+		if backup, backupErr := s.LoadBackup(); backupErr == nil && backup != nil {
+			unreadable.BackupStep = backup.CurrentStep
+		}
+		return nil, unreadable
+	}
+
+	return &state, nil
+}
+
+// LoadBackupMergeState loads the previous merge state of the repository in the
+// current directory, or returns nil if there is no backup
+func LoadBackupMergeState() (*MergeState, error) {
+	return Store{}.LoadBackup()
+}
+
+// LoadBackup loads the previous merge state kept as merge.json.bak, or returns
+// nil if there is no backup
+func (s Store) LoadBackup() (*MergeState, error) {
+	statePath, err := s.statePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine state path: %w", err)
+	}
+
+	data, err := os.ReadFile(statePath + backupSuffix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state backup: %w", err)
+	}
+
+	var state MergeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state backup: %w", err)
 	}
 
 	return &state, nil
 }
 
-// ClearMergeState removes the merge state file
+// ClearMergeState removes the merge state of the repository in the current
+// directory
 func ClearMergeState() error {
-	statePath, err := getStatePath()
+	return Store{}.Clear()
+}
+
+// Clear removes the merge state file and its backup
+func (s Store) Clear() error {
+	statePath, err := s.statePath()
 	if err != nil {
 		return fmt.Errorf("failed to determine state path: %w", err)
 	}
 
-	err = os.Remove(statePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove state file: %w", err)
+	for _, path := range []string{statePath, statePath + backupSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove state file: %w", err)
+		}
 	}
 	return nil
 }
 
-// IsMergeInProgress checks if there's a merge in progress
+// GetStatePath returns the path of the merge state file, whether or not it exists
+func GetStatePath() (string, error) {
+	return Store{}.Path()
+}
+
+// Path returns the path of the merge state file, whether or not it exists
+func (s Store) Path() (string, error) {
+	return s.statePath()
+}
+
+// ReadRawMergeState returns the unparsed contents of the state file, or nil if
+// there is none. It is used to inspect state files that no longer parse.
+func ReadRawMergeState() ([]byte, error) {
+	return Store{}.ReadRaw()
+}
+
+// ReadRaw returns the unparsed contents of the state file, or nil if there is
+// none
+func (s Store) ReadRaw() ([]byte, error) {
+	statePath, err := s.statePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine state path: %w", err)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	return data, nil
+}
+
+// IsMergeInProgress checks if there's a merge in progress in the repository in
+// the current directory
 func IsMergeInProgress() bool {
-	state, err := LoadMergeState()
+	return Store{}.InProgress()
+}
+
+// InProgress checks if there's a merge in progress. An unreadable state file
+// counts as one, so it is repaired rather than overwritten.
+func (s Store) InProgress() bool {
+	state, err := s.Load()
+	if _, unreadable := err.(*errors.UnreadableStateError); unreadable {
+		return true
+	}
 	return err == nil && state != nil
 }
+
+// WorktreeState is an operation in progress in another worktree of the
+// repository
+type WorktreeState struct {
+	Worktree string // path of the worktree
+	State    *MergeState
+}
+
+// OtherWorktreeStates returns the operations in progress in the other
+// worktrees. Each worktree keeps its state in its own git directory, so
+// operations in different worktrees don't share a state file. State files
+// that cannot be read are skipped.
+func OtherWorktreeStates() ([]WorktreeState, error) {
+	worktrees, err := git.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	return StatesOfWorktrees(worktrees), nil
+}
+
+// StatesOfWorktrees returns the operations in progress in worktrees, leaving
+// out the current one
+func StatesOfWorktrees(worktrees []git.Worktree) []WorktreeState {
+	var states []WorktreeState
+	for _, worktree := range worktrees {
+		if worktree.Current || worktree.GitDir == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(worktree.GitDir, stateDirName, stateFile))
+		if err != nil {
+			continue
+		}
+		var state MergeState
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		states = append(states, WorktreeState{Worktree: worktree.Path, State: &state})
+	}
+	return states
+}
+
+// Touches reports whether the operation changes branch: the branch it runs
+// on, its target or one of the child branches it updates
+func (s *MergeState) Touches(branch string) bool {
+	if s.FullBranchName == branch || s.ParentBranch == branch {
+		return true
+	}
+	for _, child := range s.ChildBranches {
+		if child == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// IsChildUpdated reports whether the child branch has already been updated
+func (s *MergeState) IsChildUpdated(child string) bool {
+	for _, updated := range s.UpdatedBranches {
+		if updated == child {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ActionOutput is a step output of a GitHub Actions step
+type ActionOutput struct {
+	Name  string
+	Value string
+}
+
+// InGitHubActions reports whether git-flow runs in a GitHub Actions step
+func InGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// WriteActionOutputs appends outputs to the file named by GITHUB_OUTPUT, where
+// later steps read them as steps.<id>.outputs.<name>. Multi-line values are
+// written with a random delimiter.
+func WriteActionOutputs(outputs []ActionOutput) error {
+	var content strings.Builder
+	for _, out := range outputs {
+		if !strings.Contains(out.Value, "\n") {
+			fmt.Fprintf(&content, "%s=%s\n", out.Name, out.Value)
+			continue
+		}
+		delimiter, err := actionDelimiter()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&content, "%s<<%s\n%s\n%s\n", out.Name, delimiter, out.Value, delimiter)
+	}
+	return appendToActionFile("GITHUB_OUTPUT", content.String())
+}
+
+// WriteActionSummary appends markdown to the job summary in the file named by
+// GITHUB_STEP_SUMMARY
+func WriteActionSummary(markdown string) error {
+	return appendToActionFile("GITHUB_STEP_SUMMARY", strings.TrimSuffix(markdown, "\n")+"\n\n")
+}
+
+// appendToActionFile appends content to the file named by the environment
+// variable. Without the variable nothing is written.
+func appendToActionFile(variable string, content string) error {
+	path := os.Getenv(variable)
+	if path == "" || content == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", variable, err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", variable, err)
+	}
+	return nil
+}
+
+// actionDelimiter returns a delimiter for a multi-line output value that the
+// value cannot end early
+func actionDelimiter() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "ghadelimiter_" + hex.EncodeToString(buf), nil
+}
+
+// ReportToActions writes the results of a command as GitHub Actions step
+// outputs and appends summary to the job summary. Outside GitHub Actions
+// nothing is written. Failures are only warned about, as the command itself
+// succeeded.
+func ReportToActions(outputs []ActionOutput, summary string) {
+	if !InGitHubActions() {
+		return
+	}
+	if err := WriteActionOutputs(outputs); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write the GitHub Actions step outputs: %v\n", err)
+	}
+	if summary == "" {
+		return
+	}
+	if err := WriteActionSummary(summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write the GitHub Actions job summary: %v\n", err)
+	}
+}
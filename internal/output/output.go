@@ -0,0 +1,92 @@
+// Package output implements the global --quiet and --porcelain modes.
+//
+// In quiet mode informational messages written to standard output are discarded.
+// Only results reported through Result reach the original standard output, one
+// value per line, so commands can be used in shell pipelines. Errors are always
+// written to standard error. In porcelain mode errors and operation events are
+// written as JSON.
+// Inside GitHub Actions, results are also written as step outputs and to the
+// job summary.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gittower/git-flow-next/internal/events"
+)
+
+var (
+	quiet     bool
+	porcelain bool
+	stdout    io.Writer = os.Stdout
+)
+
+// EnableQuiet switches to quiet mode by redirecting os.Stdout to the null device.
+func EnableQuiet() error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	stdout = os.Stdout
+	os.Stdout = devNull
+	quiet = true
+	return nil
+}
+
+// IsQuiet reports whether quiet mode is enabled.
+func IsQuiet() bool {
+	return quiet
+}
+
+// EnablePorcelain switches to porcelain mode, in which errors and the events
+// of the operation are reported as JSON lines on standard error.
+func EnablePorcelain() {
+	porcelain = true
+	events.Subscribe(porcelainObserver{w: os.Stderr})
+}
+
+// IsPorcelain reports whether porcelain mode is enabled.
+func IsPorcelain() bool {
+	return porcelain
+}
+
+// IsTerminal reports whether informational messages reach a terminal, so
+// they may be colored. It is false in quiet mode.
+func IsTerminal() bool {
+	if quiet {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ResultWriter returns where the results of a command go: the original
+// standard output in quiet mode and nil otherwise, where the informational
+// messages already carry them.
+func ResultWriter() io.Writer {
+	if quiet {
+		return stdout
+	}
+	return nil
+}
+
+// Result prints one line of the command's stdout contract. In normal mode the
+// informational messages already carry this information, so nothing is printed.
+func Result(format string, args ...interface{}) {
+	if quiet {
+		fmt.Fprintf(stdout, format+"\n", args...)
+	}
+}
+
+// Prompt prints text for an interactive question. It goes to standard output
+// normally and to standard error in quiet mode, so the question stays visible
+// without becoming part of the command's result.
+func Prompt(format string, args ...interface{}) {
+	if quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gittower/git-flow-next/internal/events"
+)
+
+// porcelainObserver writes each event as a single line of JSON, such as
+// {"event":"tag_created","tag":"1.2.0","branch":"main","moved":false}
+type porcelainObserver struct {
+	w io.Writer
+}
+
+func (o porcelainObserver) OnStepStart(event events.StepStart) {
+	o.write(struct {
+		Event string `json:"event"`
+		events.StepStart
+	}{"step_start", event})
+}
+
+func (o porcelainObserver) OnConflict(event events.Conflict) {
+	o.write(struct {
+		Event string `json:"event"`
+		events.Conflict
+	}{"conflict", event})
+}
+
+func (o porcelainObserver) OnTagCreated(event events.TagCreated) {
+	o.write(struct {
+		Event string `json:"event"`
+		events.TagCreated
+	}{"tag_created", event})
+}
+
+func (o porcelainObserver) OnBranchDeleted(event events.BranchDeleted) {
+	o.write(struct {
+		Event string `json:"event"`
+		events.BranchDeleted
+	}{"branch_deleted", event})
+}
+
+func (o porcelainObserver) write(line interface{}) {
+	if data, err := json.Marshal(line); err == nil {
+		fmt.Fprintln(o.w, string(data))
+	}
+}
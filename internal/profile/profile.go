@@ -0,0 +1,92 @@
+// Package profile records how long the stages of an operation take and reports
+// them on standard error when the global --profile flag is given.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stage is a single timed part of an operation
+type stage struct {
+	name     string
+	depth    int // nesting level, e.g. a hook run inside a step
+	duration time.Duration
+}
+
+var (
+	mu      sync.Mutex
+	enabled bool
+	started time.Time
+	stages  []stage
+	depth   int
+)
+
+// Enable turns on stage recording for the current invocation.
+func Enable() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+	started = time.Now()
+}
+
+// Start begins timing a stage and returns a function that records its duration.
+// Stages started before the returned function is called are reported as nested.
+// When profiling is disabled, Start does nothing.
+func Start(name string) func() {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return func() {}
+	}
+
+	index := len(stages)
+	stages = append(stages, stage{name: name, depth: depth})
+	depth++
+	begin := time.Now()
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if index >= len(stages) {
+			return // reported before the stage ended
+		}
+		stages[index].duration = time.Since(begin)
+		depth--
+	}
+}
+
+// Report writes the recorded stages and the total running time to standard error.
+// Nothing is printed when profiling is disabled or no stage was recorded.
+func Report() {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled || len(stages) == 0 {
+		return
+	}
+
+	labels := make([]string, len(stages))
+	width := len("total")
+	for i, s := range stages {
+		labels[i] = strings.Repeat("  ", s.depth) + s.name
+		if len(labels[i]) > width {
+			width = len(labels[i])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\nProfile:\n")
+	for i, s := range stages {
+		fmt.Fprintf(os.Stderr, "  %-*s %9s\n", width, labels[i], formatDuration(s.duration))
+	}
+	fmt.Fprintf(os.Stderr, "  %-*s %9s\n", width, "total", formatDuration(time.Since(started)))
+
+	stages = nil
+}
+
+// formatDuration formats a duration in seconds with millisecond precision
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
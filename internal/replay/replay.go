@@ -0,0 +1,300 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Epoch is the time of the first step. The clock advances by a minute per
+// step, so commits get the same dates and hashes on every run.
+var Epoch = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// StepError reports a step of a script that failed
+type StepError struct {
+	Step    Step
+	Message string
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("line %d (%s): %s", e.Step.Line, e.Step.Source, e.Message)
+}
+
+// Runner runs a script in a fixture directory
+type Runner struct {
+	Binary string    // git-flow executable run by flow steps
+	Out    io.Writer // receives the transcript
+	Dir    string    // fixture directory holding the repository and its remotes
+
+	clock    time.Time
+	input    string
+	output   string
+	exitCode int
+}
+
+// NewRunner creates a runner with a new fixture directory. The caller removes
+// the directory when it is no longer needed.
+func NewRunner(binary string, out io.Writer) (*Runner, error) {
+	dir, err := os.MkdirTemp("", "git-flow-replay-")
+	if err != nil {
+		return nil, err
+	}
+	// Resolve symlinks, such as /tmp on macOS, so paths in the output match
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	for _, sub := range []string{"home", "repo"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gitconfig"), nil, 0644); err != nil {
+		return nil, err
+	}
+	return &Runner{Binary: binary, Out: out, Dir: dir}, nil
+}
+
+// Run runs the steps of script in order and stops at the first that fails
+func (r *Runner) Run(script *Script) error {
+	for i, step := range script.Steps {
+		r.clock = Epoch.Add(time.Duration(i) * time.Minute)
+		if step.Directive != "expect" {
+			fmt.Fprintf(r.Out, "$ %s\n", step.Source)
+		}
+		if err := r.step(step); err != nil {
+			return &StepError{Step: step, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+func (r *Runner) repoDir() string {
+	return filepath.Join(r.Dir, "repo")
+}
+
+func (r *Runner) remoteDir(name string) string {
+	return filepath.Join(r.Dir, name+".git")
+}
+
+// env is the environment of all commands: only the search path of the
+// user's environment, with a fixed identity, clock and empty configuration
+func (r *Runner) env() []string {
+	date := fmt.Sprintf("%d +0000", r.clock.Unix())
+	env := []string{
+		"HOME=" + filepath.Join(r.Dir, "home"),
+		"XDG_CONFIG_HOME=" + filepath.Join(r.Dir, "home", ".config"),
+		"GIT_CONFIG_GLOBAL=" + filepath.Join(r.Dir, "gitconfig"),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Replay",
+		"GIT_AUTHOR_EMAIL=replay@example.com",
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=Replay",
+		"GIT_COMMITTER_EMAIL=replay@example.com",
+		"GIT_COMMITTER_DATE=" + date,
+		"GIT_EDITOR=:",
+		"GIT_TERMINAL_PROMPT=0",
+		"LC_ALL=C",
+		"TZ=UTC",
+	}
+	for _, name := range []string{"PATH", "TMPDIR", "SYSTEMROOT"} {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// run runs a command in dir and returns its combined output, failing if the
+// command fails
+func (r *Runner) run(dir string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = r.env()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("%s %s failed: %v\n%s", name, strings.Join(args, " "), err, output)
+	}
+	return string(output), nil
+}
+
+func (r *Runner) step(step Step) error {
+	args := step.Args
+	switch step.Directive {
+	case "repo":
+		branch := "main"
+		if len(args) == 1 {
+			branch = args[0]
+		}
+		return r.setupRepo(branch)
+	case "remote":
+		if len(args) == 1 {
+			return r.addRemote(args[0])
+		}
+		return r.setRemoteState(args[0], args[1])
+	case "remote-commit":
+		message := fmt.Sprintf("Change %s on %s", args[2], args[0])
+		if len(args) == 5 {
+			message = args[4]
+		}
+		return r.remoteCommit(args[0], args[1], args[2], args[3], message)
+	case "write":
+		return r.writeFile(r.repoDir(), args[0], args[1])
+	case "commit":
+		if _, err := r.run(r.repoDir(), "git", "add", "-A"); err != nil {
+			return err
+		}
+		_, err := r.run(r.repoDir(), "git", "commit", "-q", "-m", args[0])
+		return err
+	case "input":
+		r.input = args[0]
+		return nil
+	case "git":
+		return r.command(step, "git", args)
+	case "flow":
+		return r.command(step, r.Binary, args)
+	case "expect":
+		return r.expect(args[0], args[1])
+	}
+	return fmt.Errorf("unknown directive '%s'", step.Directive)
+}
+
+func (r *Runner) setupRepo(branch string) error {
+	repo := r.repoDir()
+	if _, err := r.run(repo, "git", "init", "-q"); err != nil {
+		return err
+	}
+	if _, err := r.run(repo, "git", "symbolic-ref", "HEAD", "refs/heads/"+branch); err != nil {
+		return err
+	}
+	if err := r.writeFile(repo, "README.md", "# Replay\n"); err != nil {
+		return err
+	}
+	if _, err := r.run(repo, "git", "add", "README.md"); err != nil {
+		return err
+	}
+	_, err := r.run(repo, "git", "commit", "-q", "-m", "Initial commit")
+	return err
+}
+
+func (r *Runner) addRemote(name string) error {
+	if _, err := r.run(r.Dir, "git", "init", "-q", "--bare", r.remoteDir(name)); err != nil {
+		return err
+	}
+	if _, err := r.run(r.repoDir(), "git", "remote", "add", name, r.remoteDir(name)); err != nil {
+		return err
+	}
+	_, err := r.run(r.repoDir(), "git", "push", "-q", name, "--all")
+	return err
+}
+
+// setRemoteState simulates a failing remote. A rejecting remote has a
+// pre-receive hook that refuses every push; an offline remote is moved away,
+// so fetches and pushes cannot reach it.
+func (r *Runner) setRemoteState(name, state string) error {
+	remote := r.remoteDir(name)
+	offline := remote + ".offline"
+	hook := filepath.Join(remote, "hooks", "pre-receive")
+	switch state {
+	case "reject":
+		script := "#!/bin/sh\necho 'push rejected by the replay script' >&2\nexit 1\n"
+		return os.WriteFile(hook, []byte(script), 0755)
+	case "accept":
+		if err := os.Remove(hook); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	case "offline":
+		return os.Rename(remote, offline)
+	case "online":
+		return os.Rename(offline, remote)
+	}
+	return fmt.Errorf("unknown remote state '%s'", state)
+}
+
+// remoteCommit commits to branch on the remote from a separate clone, as a
+// teammate pushing in the meantime
+func (r *Runner) remoteCommit(name, branch, file, content, message string) error {
+	clone := filepath.Join(r.Dir, "elsewhere")
+	defer os.RemoveAll(clone)
+	if _, err := r.run(r.Dir, "git", "clone", "-q", "-b", branch, r.remoteDir(name), clone); err != nil {
+		return err
+	}
+	if err := r.writeFile(clone, file, content); err != nil {
+		return err
+	}
+	if _, err := r.run(clone, "git", "add", "-A"); err != nil {
+		return err
+	}
+	if _, err := r.run(clone, "git", "commit", "-q", "-m", message); err != nil {
+		return err
+	}
+	_, err := r.run(clone, "git", "push", "-q", "origin", branch)
+	return err
+}
+
+// writeFile writes content to file in dir, refusing paths outside of it
+func (r *Runner) writeFile(dir, file, content string) error {
+	path := filepath.Join(dir, file)
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("'%s' is outside the repository", file)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// command runs git or git-flow in the repository and prints its output with
+// the fixture directory replaced, so transcripts of different runs match
+func (r *Runner) command(step Step, name string, args []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = r.repoDir()
+	cmd.Env = r.env()
+	cmd.Stdin = strings.NewReader(r.input)
+	r.input = ""
+	output, err := cmd.CombinedOutput()
+
+	r.output = strings.ReplaceAll(string(output), r.Dir, "<fixture>")
+	r.exitCode = 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		r.exitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return err
+	}
+
+	io.WriteString(r.Out, r.output)
+	if r.output != "" && !strings.HasSuffix(r.output, "\n") {
+		io.WriteString(r.Out, "\n")
+	}
+	if r.exitCode != 0 {
+		fmt.Fprintf(r.Out, "[exit %d]\n", r.exitCode)
+		if !step.Checked {
+			return fmt.Errorf("the command failed with exit code %d", r.exitCode)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) expect(kind, value string) error {
+	switch kind {
+	case "exit":
+		code, _ := strconv.Atoi(value)
+		if r.exitCode != code {
+			return fmt.Errorf("expected exit code %d, got %d", code, r.exitCode)
+		}
+	case "output":
+		if !strings.Contains(r.output, value) {
+			return fmt.Errorf("expected the output to contain %q", value)
+		}
+	case "no-output":
+		if strings.Contains(r.output, value) {
+			return fmt.Errorf("expected the output not to contain %q", value)
+		}
+	}
+	return nil
+}
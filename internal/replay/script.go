@@ -0,0 +1,231 @@
+// Package replay runs scripted scenarios against fixture repositories, for
+// reproducible bug reports and tests. A script sets up a repository and its
+// remotes, simulates remote failures, runs git and git-flow commands and
+// checks their results. Everything runs in a new temporary directory with a
+// fixed identity, clock and configuration, so a script gives the same commits
+// and the same output on every machine.
+//
+// A script has one directive per line. Empty lines and lines starting with #
+// are ignored. Arguments are separated by spaces and may be quoted with
+// single quotes, or with double quotes in which \n, \t, \" and \\ are
+// escapes.
+//
+//	repo [branch]                      create the repository with an initial commit on branch (default main)
+//	remote <name>                      create a bare repository as remote name and push all branches to it
+//	remote <name> reject|accept        make the remote reject pushes, or accept them again
+//	remote <name> offline|online       make the remote unreachable, or reachable again
+//	remote-commit <name> <branch> <file> <content> [message]
+//	                                   commit to branch on the remote from another clone
+//	write <file> <content>             write a file in the repository
+//	commit <message>                   commit all changes in the repository
+//	input <text>                       standard input of the next git or flow command
+//	git <args...>                      run git in the repository
+//	flow <args...>                     run git-flow in the repository
+//	expect exit <code>                 the previous command exited with code
+//	expect output <text>               the output of the previous command contains text
+//	expect no-output <text>            the output of the previous command does not contain text
+//
+// The first directive must be repo. A git or flow command that fails ends the
+// script, unless it is followed by expect exit.
+package replay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Step is a directive of a script
+type Step struct {
+	Line      int      // line number in the script
+	Source    string   // the line as written
+	Directive string   // e.g. "flow" or "expect"
+	Args      []string // arguments after the directive
+	Checked   bool     // an expect exit follows, so a failing command does not end the script
+}
+
+// Script is a parsed replay script
+type Script struct {
+	Steps []Step
+}
+
+// ParseError reports an invalid line of a script
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// argCounts are the minimum and maximum number of arguments of each
+// directive; -1 means any number
+var argCounts = map[string][2]int{
+	"repo":          {0, 1},
+	"remote":        {1, 2},
+	"remote-commit": {4, 5},
+	"write":         {2, 2},
+	"commit":        {1, 1},
+	"input":         {1, 1},
+	"git":           {1, -1},
+	"flow":          {1, -1},
+	"expect":        {2, 2},
+}
+
+// Parse reads a script
+func Parse(r io.Reader) (*Script, error) {
+	script := &Script{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		source := strings.TrimSpace(scanner.Text())
+		if source == "" || strings.HasPrefix(source, "#") {
+			continue
+		}
+		words, err := splitWords(source)
+		if err != nil {
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+		step := Step{Line: line, Source: source, Directive: words[0], Args: words[1:]}
+		if err := script.check(step); err != nil {
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+		if step.Directive == "expect" && step.Args[0] == "exit" {
+			script.Steps[len(script.Steps)-1].Checked = true
+		}
+		script.Steps = append(script.Steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(script.Steps) == 0 {
+		return nil, &ParseError{Line: line, Message: "the script has no directives"}
+	}
+	return script, nil
+}
+
+// check validates step in the context of the steps before it
+func (s *Script) check(step Step) error {
+	counts, ok := argCounts[step.Directive]
+	if !ok {
+		return fmt.Errorf("unknown directive '%s'", step.Directive)
+	}
+	if len(step.Args) < counts[0] || (counts[1] >= 0 && len(step.Args) > counts[1]) {
+		return fmt.Errorf("wrong number of arguments for '%s'", step.Directive)
+	}
+	if (len(s.Steps) == 0) != (step.Directive == "repo") {
+		return fmt.Errorf("the script must start with 'repo', and only once")
+	}
+
+	switch step.Directive {
+	case "remote":
+		if !validRemoteName(step.Args[0]) {
+			return fmt.Errorf("invalid remote name '%s'", step.Args[0])
+		}
+		if len(step.Args) == 2 {
+			switch step.Args[1] {
+			case "reject", "accept", "offline", "online":
+			default:
+				return fmt.Errorf("unknown remote state '%s' (valid options: reject, accept, offline, online)", step.Args[1])
+			}
+		}
+	case "remote-commit":
+		if !validRemoteName(step.Args[0]) {
+			return fmt.Errorf("invalid remote name '%s'", step.Args[0])
+		}
+	case "expect":
+		// The first step is repo, so the search ends there at the latest
+		command := len(s.Steps) - 1
+		for s.Steps[command].Directive == "expect" {
+			command--
+		}
+		if directive := s.Steps[command].Directive; directive != "git" && directive != "flow" {
+			return fmt.Errorf("'expect' must follow a git or flow command")
+		}
+		switch step.Args[0] {
+		case "exit":
+			if _, err := strconv.Atoi(step.Args[1]); err != nil {
+				return fmt.Errorf("invalid exit code '%s'", step.Args[1])
+			}
+			if s.Steps[len(s.Steps)-1].Directive == "expect" {
+				return fmt.Errorf("'expect exit' must directly follow the command")
+			}
+		case "output", "no-output":
+		default:
+			return fmt.Errorf("unknown expectation '%s' (valid options: exit, output, no-output)", step.Args[0])
+		}
+	}
+	return nil
+}
+
+// validRemoteName reports whether name can be used as a remote and as the
+// name of its directory in the fixture
+func validRemoteName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// splitWords splits line into words separated by spaces, honoring quotes
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] != '\\' || i+1 == len(line) {
+					word.WriteByte(line[i])
+					continue
+				}
+				i++
+				switch line[i] {
+				case 'n':
+					word.WriteByte('\n')
+				case 't':
+					word.WriteByte('\t')
+				default:
+					word.WriteByte(line[i])
+				}
+			}
+			if i == len(line) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
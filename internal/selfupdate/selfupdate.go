@@ -0,0 +1,386 @@
+// Package selfupdate replaces the running git-flow binary with a release
+// published on GitHub.
+//
+// A release provides one archive per platform and a checksums file listing the
+// SHA-256 of every archive. When the build carries a release public key, the
+// checksums file must also be signed with the matching Ed25519 key; the raw or
+// base64-encoded signature is published next to it with a .sig suffix.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReleasesURL is the GitHub API endpoint of the git-flow-next repository.
+const DefaultReleasesURL = "https://api.github.com/repos/gittower/git-flow-next"
+
+// ReleasesURLEnv names the environment variable that replaces DefaultReleasesURL,
+// e.g. to use a mirror.
+const ReleasesURLEnv = "GITFLOW_RELEASES_URL"
+
+// httpClient is used for all requests; downloads of a few megabytes should not
+// take longer than this.
+var httpClient = &http.Client{Timeout: 2 * time.Minute}
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Asset returns the asset named name, or nil.
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// ReleasesURL returns the API endpoint to query, honoring ReleasesURLEnv.
+func ReleasesURL() string {
+	if url := os.Getenv(ReleasesURLEnv); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return DefaultReleasesURL
+}
+
+// FetchLatestRelease returns the newest release. Preview releases are only
+// considered when prerelease is true.
+func FetchLatestRelease(baseURL string, prerelease bool) (*Release, error) {
+	if !prerelease {
+		var release Release
+		if err := getJSON(baseURL+"/releases/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []Release
+	if err := getJSON(baseURL+"/releases?per_page=30", &releases); err != nil {
+		return nil, err
+	}
+	var latest *Release
+	for i := range releases {
+		if releases[i].Draft {
+			continue
+		}
+		if latest == nil || CompareVersions(releases[i].TagName, latest.TagName) > 0 {
+			latest = &releases[i]
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no releases found at %s", baseURL)
+	}
+	return latest, nil
+}
+
+// getJSON decodes the JSON document at url into v.
+func getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Download returns the content at url.
+func Download(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// CompareVersions compares two versions like "v1.2.0" or "1.3.0-rc.1" and returns
+// -1, 0 or 1. A release is newer than its previews.
+func CompareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+	if c := compareIdentifiers(coreA, coreB); c != 0 {
+		return c
+	}
+	switch {
+	case len(preA) == 0 && len(preB) == 0:
+		return 0
+	case len(preA) == 0:
+		return 1
+	case len(preB) == 0:
+		return -1
+	}
+	return compareIdentifiers(preA, preB)
+}
+
+// splitVersion splits a version into its dot-separated core and pre-release parts,
+// ignoring a leading "v" and build metadata.
+func splitVersion(version string) ([]string, []string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	core, pre, _ := strings.Cut(version, "-")
+	var preParts []string
+	if pre != "" {
+		preParts = strings.Split(pre, ".")
+	}
+	return strings.Split(core, "."), preParts
+}
+
+// compareIdentifiers compares version parts numerically where both are numbers
+// and lexically otherwise. Missing parts count as lower.
+func compareIdentifiers(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i >= len(a) {
+			return -1
+		}
+		if i >= len(b) {
+			return 1
+		}
+		numA, errA := strconv.Atoi(a[i])
+		numB, errB := strconv.Atoi(b[i])
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				if numA < numB {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// ArchiveName returns the name of the release archive for a platform, as created
+// by scripts/build.sh.
+func ArchiveName(tag, goos, goarch string) string {
+	if goos == "windows" {
+		return fmt.Sprintf("git-flow-next-%s-%s-%s.zip", tag, goos, goarch)
+	}
+	return fmt.Sprintf("git-flow-next-%s-%s-%s.tar.gz", tag, goos, goarch)
+}
+
+// BinaryName returns the name of the binary inside the release archive.
+func BinaryName(tag, goos, goarch string) string {
+	if goos == "windows" {
+		return fmt.Sprintf("git-flow-%s-%s-%s.exe", tag, goos, goarch)
+	}
+	return fmt.Sprintf("git-flow-%s-%s-%s", tag, goos, goarch)
+}
+
+// ChecksumsName returns the name of the checksums file of a release.
+func ChecksumsName(tag string) string {
+	return fmt.Sprintf("git-flow-next-%s-checksums.txt", tag)
+}
+
+// VerifyChecksum checks data against the SHA-256 listed for name in a checksums
+// file in the format of shasum.
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum of %s does not match", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// VerifySignature checks the Ed25519 signature of the checksums file with the
+// base64-encoded public key. The signature may be raw or base64-encoded.
+func VerifySignature(checksums []byte, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("the release public key of this build is invalid")
+	}
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("the signature is neither raw nor base64-encoded")
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, signature) {
+		return fmt.Errorf("the signature does not match the release public key")
+	}
+	return nil
+}
+
+// ExtractBinary returns the file named binaryName from a .tar.gz or .zip archive.
+func ExtractBinary(archive []byte, archiveName, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range reader.File {
+			if filepath.Base(file.Name) != binaryName {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s does not contain %s", archiveName, binaryName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s does not contain %s", archiveName, binaryName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == binaryName {
+			return io.ReadAll(reader)
+		}
+	}
+}
+
+// ReplaceExecutable atomically replaces the executable at path with data, keeping
+// its permissions. The new file is written next to it and renamed over it; on
+// Windows, where a running executable can't be replaced, the old one is moved
+// aside first.
+func ReplaceExecutable(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, info.Mode().Perm()|0111)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			os.Remove(tmpName)
+			return err
+		}
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// SystemPackageManager is the package manager reported for executables in
+// the system directories package managers such as apt and rpm install into
+const SystemPackageManager = "the system package manager"
+
+// systemDirs are the directories only the system package manager installs
+// into; /usr/local and /opt are left to the administrator
+var systemDirs = []string{"/bin/", "/sbin/", "/usr/bin/", "/usr/sbin/", "/usr/lib/", "/usr/libexec/", "/snap/", "/nix/store/"}
+
+// PackageManager returns the package manager that installed the executable at
+// path: installMethod when the build was made for one, otherwise one recognized
+// from the install location. It returns "" for standalone installations.
+func PackageManager(installMethod, path string) string {
+	if installMethod != "" {
+		return installMethod
+	}
+	normalized := strings.ToLower(strings.ReplaceAll(path, `\`, "/"))
+	switch {
+	case strings.Contains(normalized, "/cellar/") || strings.Contains(normalized, "/homebrew/") || strings.Contains(normalized, "/linuxbrew/"):
+		return "homebrew"
+	case strings.Contains(normalized, "/scoop/"):
+		return "scoop"
+	}
+	for _, dir := range systemDirs {
+		if strings.HasPrefix(normalized, dir) {
+			return SystemPackageManager
+		}
+	}
+	return ""
+}
+
+// UpgradeCommand returns the command that updates git-flow-next with a package
+// manager, or "" when unknown.
+func UpgradeCommand(manager string) string {
+	switch manager {
+	case "homebrew":
+		return "brew upgrade git-flow-next"
+	case "scoop":
+		return "scoop update git-flow-next"
+	}
+	return ""
+}
@@ -1,73 +1,261 @@
 package update
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/gittower/git-flow-next/internal/config"
 	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
 	"github.com/gittower/git-flow-next/internal/git"
 	"github.com/gittower/git-flow-next/internal/mergestate"
 )
 
-// UpdateBranchFromParent updates a branch with changes from its parent branch using the configured strategy
-func UpdateBranchFromParent(branchName string, parentBranch string, strategy string, saveState bool, state *mergestate.MergeState) error {
-	return UpdateBranchFromParentWithMessage(branchName, parentBranch, strategy, "", saveState, state)
+// Git is the repository access of an update
+type Git interface {
+	GetCurrentBranch() (string, error)
+	BranchExists(branch string) error
+	WorktreeOfBranch(branch string) string
+	GetGitVersion() (git.GitVersion, error)
+	Checkout(branch string) error
+	Merge(ctx context.Context, branch string, noVerify bool) error
+	MergeWithMessage(ctx context.Context, branchName string, message string, noFF bool, noVerify bool) error
+	SquashMerge(ctx context.Context, branch string, noVerify bool) error
+	MergeSquashWithMessage(ctx context.Context, branchName string, message string, noVerify bool) error
+	MergeInMemory(ctx context.Context, branch, source, message string) (merged bool, err error)
+	Rebase(ctx context.Context, branch string, noVerify bool) error
+	RebaseContinue(ctx context.Context) error
+	Commit(ctx context.Context, message string, noVerify bool) error
+	UnmergedFiles() ([]string, error)
+	ResolveConflictFavoring(path, side string) error
 }
 
-// UpdateBranchFromParentWithMessage updates a branch with changes from its parent branch using the configured strategy and optional custom message
-func UpdateBranchFromParentWithMessage(branchName string, parentBranch string, strategy string, customMessage string, saveState bool, state *mergestate.MergeState) error {
+// StateSaver saves the state of an update stopped by conflicts
+type StateSaver interface {
+	Save(state *mergestate.MergeState) error
+}
+
+// Updater updates branches with the changes of their parent branches
+type Updater struct {
+	Git Git
+	// Config enables the experimental features of the repository; nil enables none
+	Config *config.Config
+	// State saves the state of an update stopped by conflicts; nil doesn't save it
+	State StateSaver
+	// Events receives the conflicts; nil drops them
+	Events *events.Bus
+	Out    io.Writer
+}
+
+// FromParent updates a branch with changes from its parent branch using the configured strategy.
+// noVerify bypasses the commit hooks (and the pre-rebase hook for the rebase strategy).
+// state is saved when the update stops on conflicts. Cancelling ctx stops the
+// running git command; the caller undoes what it left behind.
+func (u Updater) FromParent(ctx context.Context, branchName string, parentBranch string, strategy string, noVerify bool, state *mergestate.MergeState) error {
+	return u.FromParentWithResolution(ctx, branchName, parentBranch, strategy, "", noVerify, nil, state)
+}
+
+// ConflictResolution makes an update prefer one side in conflicts
+type ConflictResolution struct {
+	Side  string   // "ours" keeps the branch's changes, "theirs" takes the parent's
+	Paths []string // Path patterns the preference is limited to; empty means all paths
+}
+
+// ConflictResolutionFor returns the conflict resolution configured for a
+// branch, or nil when none (or an unknown side) is configured
+func ConflictResolutionFor(branchConfig config.BranchConfig) *ConflictResolution {
+	side := strings.ToLower(strings.TrimSpace(branchConfig.ConflictResolution))
+	if side != git.ConflictSideOurs && side != git.ConflictSideTheirs {
+		return nil
+	}
+	resolution := &ConflictResolution{Side: side}
+	for _, pattern := range strings.Split(branchConfig.ConflictResolutionPaths, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			resolution.Paths = append(resolution.Paths, pattern)
+		}
+	}
+	return resolution
+}
+
+// Matches reports whether the resolution applies to path. Patterns without a
+// slash match the file name in any directory.
+func (r *ConflictResolution) Matches(path string) bool {
+	if len(r.Paths) == 0 {
+		return true
+	}
+	for _, pattern := range r.Paths {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FromParentWithResolution updates a branch like FromParent, with the custom
+// merge message customMessage unless it is empty. When the update stops on
+// conflicts and resolution is set, the conflicts it covers are resolved in favor of its side;
+// the update completes if no other conflicts remain. As the conflicts are
+// resolved in the working tree, a resolution also skips the in-memory merge.
+func (u Updater) FromParentWithResolution(ctx context.Context, branchName string, parentBranch string, strategy string, customMessage string, noVerify bool, resolution *ConflictResolution, state *mergestate.MergeState) error {
 	// Checkout the branch if needed
-	currentBranch, err := git.GetCurrentBranch()
+	currentBranch, err := u.Git.GetCurrentBranch()
 	if err != nil {
 		return &errors.GitError{Operation: "get current branch", Err: err}
 	}
+	if currentBranch != branchName && resolution == nil && u.mergesInMemory(branchName, parentBranch, strategy) {
+		message := customMessage
+		if message == "" {
+			message = fmt.Sprintf("Merge branch '%s' into %s", parentBranch, branchName)
+		}
+		fmt.Fprintf(u.Out, "Using in-memory merge strategy for '%s'\n", branchName)
+		merged, err := u.Git.MergeInMemory(ctx, branchName, parentBranch, message)
+		if err != nil {
+			return &errors.GitError{Operation: fmt.Sprintf("merge %s into %s", parentBranch, branchName), Err: err}
+		}
+		if merged {
+			fmt.Fprintf(u.Out, "Successfully updated branch '%s' from '%s'\n", branchName, parentBranch)
+			return nil
+		}
+		// Conflicts are resolved in the working tree as usual
+		fmt.Fprintf(u.Out, "In-memory merge has conflicts, checking out '%s'\n", branchName)
+	}
 	if currentBranch != branchName {
-		if err := git.Checkout(branchName); err != nil {
+		if err := u.Git.Checkout(branchName); err != nil {
 			return &errors.GitError{Operation: fmt.Sprintf("checkout branch '%s'", branchName), Err: err}
 		}
 	}
 
 	// Use the configured merge strategy
-	// Note: noVerify is false for update operations (only finish supports --no-verify)
 	var mergeErr error
 	switch strings.ToLower(strategy) {
 	case "rebase":
-		fmt.Printf("Using rebase strategy for '%s'\n", branchName)
-		mergeErr = git.Rebase(parentBranch)
+		fmt.Fprintf(u.Out, "Using rebase strategy for '%s'\n", branchName)
+		mergeErr = u.Git.Rebase(ctx, parentBranch, noVerify)
 	case "squash":
-		fmt.Printf("Using squash strategy for '%s'\n", branchName)
+		fmt.Fprintf(u.Out, "Using squash strategy for '%s'\n", branchName)
 		if customMessage != "" {
-			mergeErr = git.MergeSquashWithMessage(parentBranch, customMessage, false)
+			mergeErr = u.Git.MergeSquashWithMessage(ctx, parentBranch, customMessage, noVerify)
 		} else {
-			mergeErr = git.SquashMerge(parentBranch, false)
+			mergeErr = u.Git.SquashMerge(ctx, parentBranch, noVerify)
 		}
 	default:
-		fmt.Printf("Using merge strategy for '%s'\n", branchName)
+		fmt.Fprintf(u.Out, "Using merge strategy for '%s'\n", branchName)
 		if customMessage != "" {
-			mergeErr = git.MergeWithMessage(parentBranch, customMessage, true, false)
+			mergeErr = u.Git.MergeWithMessage(ctx, parentBranch, customMessage, true, noVerify)
 		} else {
-			mergeErr = git.Merge(parentBranch, false)
+			mergeErr = u.Git.Merge(ctx, parentBranch, noVerify)
 		}
 	}
 
+	if mergeErr != nil && resolution != nil && strings.Contains(mergeErr.Error(), "conflict") {
+		mergeErr = u.resolveConflicts(ctx, branchName, parentBranch, strategy, customMessage, noVerify, resolution)
+	}
+
 	if mergeErr != nil {
 		if strings.Contains(mergeErr.Error(), "conflict") {
-			if saveState && state != nil {
-				// Save merge state if requested
-				if err := mergestate.SaveMergeState(state); err != nil {
+			if u.State != nil && state != nil {
+				if err := u.State.Save(state); err != nil {
 					return &errors.GitError{Operation: "save merge state", Err: err}
 				}
 			}
+			files, _ := u.Git.UnmergedFiles()
+			u.Events.Conflict(events.Conflict{Branch: branchName, Source: parentBranch, Files: files})
 			return &errors.UnresolvedConflictsError{}
 		}
 		return &errors.GitError{Operation: fmt.Sprintf("merge %s into %s", parentBranch, branchName), Err: mergeErr}
 	}
 
-	fmt.Printf("Successfully updated branch '%s' from '%s'\n", branchName, parentBranch)
+	fmt.Fprintf(u.Out, "Successfully updated branch '%s' from '%s'\n", branchName, parentBranch)
 	return nil
 }
 
+// mergesInMemory reports whether the update of a branch that is not checked
+// out is merged without checking it out, with the experimental in-memory-merge
+// feature. Only merges of a local branch qualify, as merging a tag records
+// the tag object, and only while no other worktree has the branch checked out.
+func (u Updater) mergesInMemory(branchName, parentBranch, strategy string) bool {
+	switch strings.ToLower(strategy) {
+	case "", string(config.MergeStrategyMerge):
+	default:
+		return false
+	}
+	if u.Config == nil || !u.Config.ExperimentalEnabled(config.ExperimentalInMemoryMerge) {
+		return false
+	}
+	if version, err := u.Git.GetGitVersion(); err != nil || !version.AtLeast(2, 38) {
+		return false
+	}
+	return u.Git.BranchExists(parentBranch) == nil && u.Git.WorktreeOfBranch(branchName) == ""
+}
+
+// resolveConflicts resolves the conflicts covered by resolution and completes
+// the stopped merge, squash or rebase. It returns a conflict error when other
+// conflicts remain.
+func (u Updater) resolveConflicts(ctx context.Context, branchName, parentBranch, strategy, customMessage string, noVerify bool, resolution *ConflictResolution) error {
+	rebase := strings.ToLower(strategy) == "rebase"
+	side, favored := resolution.Side, parentBranch
+	if side == git.ConflictSideOurs {
+		favored = branchName
+	}
+	if rebase {
+		// A rebase replays the branch onto the parent, which swaps the sides
+		if side == git.ConflictSideOurs {
+			side = git.ConflictSideTheirs
+		} else {
+			side = git.ConflictSideOurs
+		}
+	}
+
+	for {
+		files, err := u.Git.UnmergedFiles()
+		if err != nil {
+			return err
+		}
+		remaining := 0
+		for _, file := range files {
+			if !resolution.Matches(file) {
+				remaining++
+				continue
+			}
+			if err := u.Git.ResolveConflictFavoring(file, side); err != nil {
+				return err
+			}
+			fmt.Fprintf(u.Out, "Resolved conflict in '%s' in favor of '%s' (conflictResolution=%s)\n", file, favored, resolution.Side)
+		}
+		if remaining > 0 {
+			return fmt.Errorf("merge conflict: %d file(s) not covered by the conflict resolution of '%s'", remaining, branchName)
+		}
+
+		if !rebase {
+			break
+		}
+		err = u.Git.RebaseContinue(ctx)
+		if err == nil || !strings.Contains(err.Error(), "conflict") {
+			return err
+		}
+		// The next commit stopped on conflicts as well
+	}
+
+	// Complete the stopped merge or squash with the message it would have used
+	message := customMessage
+	switch {
+	case message != "":
+	case strings.ToLower(strategy) == "squash":
+		message = fmt.Sprintf("Squashed commit of branch '%s'", parentBranch)
+	default:
+		message = fmt.Sprintf("Merge branch '%s' into %s", parentBranch, branchName)
+	}
+	return u.Git.Commit(ctx, message, noVerify)
+}
+
 // GetParentBranch returns the parent branch for a given branch name
 func GetParentBranch(cfg *config.Config, branchName string) (string, error) {
 	// Find the branch type and its configuration
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffLine is a single line of an edit script: ' ' kept, '-' removed, '+' added
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns the changes from before to after in unified diff format,
+// labelling the two sides fromName and toName. It returns "" when both sides
+// are equal.
+func UnifiedDiff(fromName, toName string, before, after []string) string {
+	script := editScript(before, after)
+
+	changed := false
+	for _, line := range script {
+		if line.op != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// Line numbers of each script entry in before and after
+	oldAt := make([]int, len(script))
+	newAt := make([]int, len(script))
+	oldLine, newLine := 1, 1
+	for i, line := range script {
+		oldAt[i], newAt[i] = oldLine, newLine
+		if line.op != '+' {
+			oldLine++
+		}
+		if line.op != '-' {
+			newLine++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Emit one hunk per group of changes whose context overlaps
+	for start := 0; start < len(script); {
+		first := start
+		for first < len(script) && script[first].op == ' ' {
+			first++
+		}
+		if first == len(script) {
+			break
+		}
+
+		// Extend the hunk until a run of unchanged lines is long enough to split it
+		last := first
+		for i := first; i < len(script) && i-last <= 2*diffContext; i++ {
+			if script[i].op != ' ' {
+				last = i
+			}
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(last+diffContext+1, len(script))
+		oldCount, newCount := 0, 0
+		var body strings.Builder
+		for _, line := range script[hunkStart:hunkEnd] {
+			body.WriteByte(line.op)
+			body.WriteString(line.text)
+			body.WriteByte('\n')
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(oldAt[hunkStart], oldCount), hunkRange(newAt[hunkStart], newCount))
+		out.WriteString(body.String())
+
+		start = hunkEnd
+	}
+
+	return out.String()
+}
+
+// hunkRange formats the start and length of one side of a hunk header
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// An empty side names the line before the hunk
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// editScript returns the shortest sequence of kept, removed and added lines
+// turning before into after, based on their longest common subsequence
+func editScript(before, after []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var script []diffLine
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			script = append(script, diffLine{' ', before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			script = append(script, diffLine{'-', before[i]})
+			i++
+		default:
+			script = append(script, diffLine{'+', after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		script = append(script, diffLine{'-', before[i]})
+	}
+	for ; j < len(after); j++ {
+		script = append(script, diffLine{'+', after[j]})
+	}
+	return script
+}
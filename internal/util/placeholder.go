@@ -22,3 +22,63 @@ func ExpandMessagePlaceholders(message, branch, parent string) string {
 	result := replacer.Replace(message)
 	return strings.ReplaceAll(result, "\x00", "%")
 }
+
+// ExpandTrailerPlaceholders expands placeholders in finish trailer templates.
+// Supported placeholders:
+//
+//	%b - branch name (e.g., release/1.2.0)
+//	%p - parent branch name (e.g., main)
+//	%v - version, the short name of the finished branch (e.g., 1.2.0)
+//	%t - name of the tag created by finish (e.g., v1.2.0), empty without a tag
+//	%% - literal percent sign
+func ExpandTrailerPlaceholders(template, branch, parent, version, tag string) string {
+	replacer := strings.NewReplacer(
+		"%%", "\x00", // Temporarily escape %%
+		"%b", branch,
+		"%p", parent,
+		"%v", version,
+		"%t", tag,
+	)
+	result := replacer.Replace(template)
+	return strings.ReplaceAll(result, "\x00", "%")
+}
+
+// ExpandTagPlaceholders expands placeholders in extra tag name templates.
+// Supported placeholders:
+//
+//	%v - version, the short name of the finished branch (e.g., 1.2.0)
+//	%t - name of the tag created by finish (e.g., v1.2.0)
+//	%p - parent branch name (e.g., main)
+//	%% - literal percent sign
+func ExpandTagPlaceholders(template, version, tag, parent string) string {
+	replacer := strings.NewReplacer(
+		"%%", "\x00", // Temporarily escape %%
+		"%v", version,
+		"%t", tag,
+		"%p", parent,
+	)
+	result := replacer.Replace(template)
+	return strings.ReplaceAll(result, "\x00", "%")
+}
+
+// ExpandPrereleasePlaceholders expands placeholders in prerelease tag formats.
+// Supported placeholders:
+//
+//	%v - upcoming release version (e.g., 1.3.0)
+//	%t - tag the release will get (e.g., v1.3.0)
+//	%c - channel name (e.g., beta)
+//	%n - prerelease number (e.g., 4)
+//	%d - date in UTC as YYYYMMDD (e.g., 20240315)
+//	%% - literal percent sign
+func ExpandPrereleasePlaceholders(format, version, tag, channel, number, date string) string {
+	replacer := strings.NewReplacer(
+		"%%", "\x00", // Temporarily escape %%
+		"%v", version,
+		"%t", tag,
+		"%c", channel,
+		"%n", number,
+		"%d", date,
+	)
+	result := replacer.Replace(format)
+	return strings.ReplaceAll(result, "\x00", "%")
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations maps letters with diacritics and ligatures to ASCII
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slugify turns free text into a valid branch name: it lowercases the text,
+// replaces letters with diacritics by their ASCII base letter, and turns
+// whitespace and any character not allowed in a branch name into single
+// dashes. Dots, underscores and slashes are kept, so versions and nested
+// names pass through. The result is empty if nothing usable remains.
+func Slugify(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '/':
+			b.WriteRune(r)
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed input, e.g. "ü"
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	// Clean up each path component: no runs of dashes or dots, and no
+	// leading or trailing separators
+	var parts []string
+	for _, part := range strings.Split(b.String(), "/") {
+		for strings.Contains(part, "--") {
+			part = strings.ReplaceAll(part, "--", "-")
+		}
+		for strings.Contains(part, "..") {
+			part = strings.ReplaceAll(part, "..", ".")
+		}
+		part = strings.Trim(strings.TrimSuffix(part, ".lock"), "-.")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
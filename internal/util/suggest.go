@@ -0,0 +1,79 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// ClosestMatches returns up to limit candidates that look like a mistyped
+// target, closest first. Names are compared ignoring case; a candidate matches
+// when one name contains the other or when its edit distance to target is at
+// most a third of the target's length, but no less than 2.
+func ClosestMatches(target string, candidates []string, limit int) []string {
+	maxDistance := len(target) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	lowerTarget := strings.ToLower(target)
+	for _, candidate := range candidates {
+		lowerCandidate := strings.ToLower(candidate)
+		distance := editDistance(lowerTarget, lowerCandidate)
+		if distance <= maxDistance || containsEither(lowerTarget, lowerCandidate) {
+			matches = append(matches, match{name: candidate, distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var result []string
+	for _, m := range matches {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, m.name)
+	}
+	return result
+}
+
+// containsEither reports whether one name contains the other. Names shorter
+// than three characters are ignored since they occur in almost any name.
+func containsEither(a, b string) bool {
+	if len(a) < 3 || len(b) < 3 {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a release version of the form major.minor.patch
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Version parts Bump can increment
+const (
+	VersionMajor = "major"
+	VersionMinor = "minor"
+	VersionPatch = "patch"
+)
+
+// ParseVersion parses a version of the form major.minor.patch or major.minor,
+// whose patch is 0. ok is false for anything else, including versions with a
+// pre-release or build suffix.
+func ParseVersion(s string) (version Version, ok bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || part != strconv.Itoa(number) {
+			return Version{}, false
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, true
+}
+
+// Bump returns the version following v when part, one of VersionMajor,
+// VersionMinor and VersionPatch, is incremented
+func (v Version) Bump(part string) Version {
+	switch part {
+	case VersionMajor:
+		return Version{Major: v.Major + 1}
+	case VersionPatch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// Less reports whether v precedes other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
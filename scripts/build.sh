@@ -18,6 +18,17 @@ BUILD_TIME=$(date -u '+%Y-%m-%d %H:%M:%S')
 # Combined with -trimpath and CGO_ENABLED=0 for minimal binary size
 BUILD_FLAGS="-s -w -X 'github.com/gittower/git-flow-next/version.BuildTime=${BUILD_TIME}' -X 'github.com/gittower/git-flow-next/version.GitCommit=${GIT_COMMIT}'"
 
+# Key that self-update verifies the signature of release checksums with
+if [[ -n "$RELEASE_PUBLIC_KEY" ]]; then
+    BUILD_FLAGS="${BUILD_FLAGS} -X 'github.com/gittower/git-flow-next/version.ReleasePublicKey=${RELEASE_PUBLIC_KEY}'"
+fi
+
+# Package manager the build is made for (e.g. homebrew, apt), which disables self-update;
+# left empty for the standalone archives of the GitHub release
+if [[ -n "$INSTALL_METHOD" ]]; then
+    BUILD_FLAGS="${BUILD_FLAGS} -X 'github.com/gittower/git-flow-next/version.InstallMethod=${INSTALL_METHOD}'"
+fi
+
 # Create build directory if it doesn't exist
 mkdir -p $BUILD_DIR
 
@@ -0,0 +1,113 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// auditReport mirrors the JSON report of 'git flow audit --format json'
+type auditReport struct {
+	Branches   []string `json:"branches"`
+	Passed     bool     `json:"passed"`
+	Violations []struct {
+		Rule   string `json:"rule"`
+		Branch string `json:"branch"`
+		Tag    string `json:"tag"`
+	} `json:"violations"`
+}
+
+// TestAuditPassesForGitFlowHistory tests that history made with git-flow passes the audit.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with the tag prefix v
+// 2. Finishes a feature with --no-ff and a release
+// 3. Runs git flow audit and verifies it passes
+func TestAuditPassesForGitFlowHistory(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults", "--tag", "v")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--no-ff"); err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "audit")
+	if err != nil {
+		t.Fatalf("Expected the audit to pass: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No violations found") {
+		t.Errorf("Expected no violations, got: %s", output)
+	}
+}
+
+// TestAuditReportsViolations tests that the audit reports history that bypasses the branching model.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with the tag prefix v
+// 2. Commits directly on develop and merges a branch without a topic prefix into it
+// 3. Tags main without the prefix and tags a commit on main that never reaches develop
+// 4. Runs git flow audit and verifies each rule is reported with exit code 6
+// 5. Verifies the JSON report lists the violations
+// 6. Verifies --since leaves out the older history
+func TestAuditReportsViolations(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults", "--tag", "v")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	commitOn(t, dir, "develop", "direct.txt", "direct")
+	testutil.RunGit(t, dir, "checkout", "-b", "hack", "develop")
+	commitOn(t, dir, "hack", "hack.txt", "hack")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "merge", "--no-ff", "--no-edit", "hack")
+	testutil.RunGit(t, dir, "tag", "1.0", "main")
+	commitOn(t, dir, "main", "hotfix.txt", "hotfix")
+	testutil.RunGit(t, dir, "tag", "v1.0.1", "main")
+
+	output, err = testutil.RunGitFlow(t, dir, "audit")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	for _, expected := range []string{
+		"committed directly on 'develop': Change direct.txt on develop",
+		"'hack' merged into 'develop' is neither a base branch nor a topic branch",
+		"tag '1.0' does not start with a tag prefix (v)",
+		"tag 'v1.0.1' on 'main' is not merged into 'develop'",
+		"committed directly on 'main': Change hotfix.txt on main",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the audit to report %q, got: %s", expected, output)
+		}
+	}
+
+	output, _ = testutil.RunGitFlow(t, dir, "audit", "--format", "json")
+	var report auditReport
+	if err := json.Unmarshal([]byte(output[:strings.LastIndex(output, "}")+1]), &report); err != nil {
+		t.Fatalf("Failed to parse the JSON report: %v\nOutput: %s", err, output)
+	}
+	if report.Passed || len(report.Violations) != 5 {
+		t.Errorf("Expected 5 violations, got: %+v", report)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "audit", "--since", "2099-12-31")
+	if err != nil {
+		t.Fatalf("Expected the audit of future history to pass: %v\nOutput: %s", err, output)
+	}
+}
@@ -0,0 +1,83 @@
+package cmd_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// isolateForgeTokens hides the tokens of the environment, the Git credential
+// helpers and the user's gh, hub and glab configuration from git-flow
+func isolateForgeTokens(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GITLAB_TOKEN", "BITBUCKET_TOKEN", "GH_CONFIG_DIR", "HUB_CONFIG", "GLAB_CONFIG_DIR"} {
+		t.Setenv(name, "")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(configHome, "gitconfig"))
+}
+
+// TestAuthStatus tests that auth status verifies the discovered token with the hosting service.
+// Steps:
+// 1. Sets up a test repository with a GitHub remote and an API server that knows one token
+// 2. Runs 'git flow auth status' without a token and verifies it fails with exit code 6
+// 3. Runs it with GH_TOKEN set to the known token and verifies the account and rate limit are shown
+// 4. Runs it with an unknown token and verifies it is reported as rejected
+func TestAuthStatus(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	isolateForgeTokens(t)
+	testutil.RunGit(t, dir, "remote", "add", "origin", "git@github.com:acme/app.git")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" || r.Header.Get("Authorization") != "Bearer ghp_valid1234" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Bad credentials"}`)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		fmt.Fprint(w, `{"login": "octocat"}`)
+	}))
+	defer server.Close()
+	t.Setenv(forge.APIURLEnv, server.URL)
+
+	output, err := testutil.RunGitFlow(t, dir, "auth", "status")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "no API token for github.com found") {
+		t.Errorf("Expected the missing token to be reported, got: %s", output)
+	}
+
+	t.Setenv("GH_TOKEN", "ghp_valid1234")
+	output, err = testutil.RunGitFlow(t, dir, "auth", "status")
+	if err != nil {
+		t.Fatalf("Expected the token to be verified: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"Remote 'origin': github.com/acme/app (github)",
+		"Token:          ****1234 (from GH_TOKEN)",
+		"Account:        octocat",
+		"Rate limit:     4999 of 5000 requests left",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	t.Setenv("GH_TOKEN", "ghp_revoked5678")
+	output, err = testutil.RunGitFlow(t, dir, "auth", "status")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "the API token for github.com from GH_TOKEN was rejected") || !strings.Contains(output, "Bad credentials") {
+		t.Errorf("Expected the token to be rejected, got: %s", output)
+	}
+}
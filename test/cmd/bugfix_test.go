@@ -0,0 +1,164 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestBugfixLifecycle tests that the bugfix type supports the same commands as feature.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Starts a bugfix branch from develop and commits to it
+// 3. Lists, publishes, updates and renames the bugfix branch
+// 4. Finishes it using the shorthand command
+// 5. Verifies the change is merged into develop and the branch is deleted
+func TestBugfixLifecycle(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "crash")
+	if err != nil {
+		t.Fatalf("Failed to start bugfix: %v\nOutput: %s", err, output)
+	}
+	if testutil.GetCurrentBranch(t, dir) != "bugfix/crash" {
+		t.Fatalf("Expected to be on bugfix/crash, got %s", testutil.GetCurrentBranch(t, dir))
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fixed")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Fix crash")
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "list")
+	if err != nil || !strings.Contains(output, "crash") {
+		t.Errorf("Expected bugfix list to show 'crash': %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "publish", "crash")
+	if err != nil {
+		t.Fatalf("Failed to publish bugfix: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/bugfix/crash"); err != nil {
+		t.Error("Expected bugfix branch to be published")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "update", "crash")
+	if err != nil {
+		t.Fatalf("Failed to update bugfix: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "rename", "crash", "login-crash")
+	if err != nil {
+		t.Fatalf("Failed to rename bugfix: %v\nOutput: %s", err, output)
+	}
+
+	// Shorthand finish detects the bugfix type from the current branch
+	output, err = testutil.RunGitFlow(t, dir, "finish", "--push")
+	if err != nil {
+		t.Fatalf("Failed to finish bugfix: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "bugfix/login-crash") {
+		t.Error("Expected bugfix branch to be deleted")
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if !testutil.FileExists(t, dir, "fix.txt") {
+		t.Error("Expected the fix to be merged into develop")
+	}
+}
+
+// TestBugfixTrackAndDelete tests tracking and deleting a bugfix branch started elsewhere.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Creates a bugfix branch on the remote only
+// 3. Tracks it with 'git flow bugfix track'
+// 4. Deletes it with 'git flow bugfix delete'
+func TestBugfixTrackAndDelete(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "develop:refs/heads/bugfix/shared"); err != nil {
+		t.Fatalf("Failed to create remote bugfix branch: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "track", "shared")
+	if err != nil {
+		t.Fatalf("Failed to track bugfix: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "bugfix/shared") {
+		t.Fatal("Expected local bugfix branch to be created")
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "delete", "shared")
+	if err != nil {
+		t.Fatalf("Failed to delete bugfix: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "bugfix/shared") {
+		t.Error("Expected bugfix branch to be deleted")
+	}
+}
+
+// TestBugfixConfigIndependentOfFeature tests that bugfix settings don't leak into feature and vice versa.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures squash as the bugfix upstream strategy
+// 3. Finishes a bugfix and a feature branch
+// 4. Verifies only the bugfix was squashed
+func TestBugfixConfigIndependentOfFeature(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.branch.bugfix.upstreamstrategy", "squash")
+
+	for _, branchType := range []string{"bugfix", "feature"} {
+		output, err = testutil.RunGitFlow(t, dir, branchType, "start", "change")
+		if err != nil {
+			t.Fatalf("Failed to start %s: %v\nOutput: %s", branchType, err, output)
+		}
+		testutil.WriteFile(t, dir, branchType+".txt", branchType)
+		testutil.RunGit(t, dir, "add", branchType+".txt")
+		testutil.RunGit(t, dir, "commit", "-m", "Add "+branchType)
+
+		output, err = testutil.RunGitFlow(t, dir, branchType, "finish", "change")
+		if err != nil {
+			t.Fatalf("Failed to finish %s: %v\nOutput: %s", branchType, err, output)
+		}
+		expected := "Merging using strategy: merge"
+		if branchType == "bugfix" {
+			expected = "Merging using strategy: squash"
+		}
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected %s finish to report %q, got: %s", branchType, expected, output)
+		}
+	}
+}
+
+// TestBugfixWithoutInitialization tests that bugfix commands exist before git-flow is initialized.
+// Steps:
+// 1. Sets up a test repository without git-flow init
+// 2. Runs 'git flow bugfix start'
+// 3. Verifies the not-initialized error instead of an unknown command
+func TestBugfixWithoutInitialization(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "crash")
+	if err == nil {
+		t.Fatalf("Expected bugfix start to fail without initialization, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != int(errors.ExitCodeNotInitialized) {
+		t.Errorf("Expected exit code %d, got %d", errors.ExitCodeNotInitialized, exitErr.ExitCode)
+	}
+	if !strings.Contains(output, "git flow is not initialized") {
+		t.Errorf("Expected 'not initialized' error message, got: %s", output)
+	}
+}
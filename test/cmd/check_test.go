@@ -0,0 +1,185 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestCheckPassesForBranchFollowingTheModel tests that check passes for a feature branch started from develop.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a feature branch and commits to it
+// 3. Runs git flow check and verifies it exits with 0 and reports each rule as ok
+func TestCheckPassesForBranchFollowingTheModel(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+
+	output, err := testutil.RunGitFlow(t, dir, "check")
+	if err != nil {
+		t.Fatalf("Expected check to pass: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Branch 'feature/login' (feature, parent 'develop')") {
+		t.Errorf("Expected the branch type and parent in the output, got: %s", output)
+	}
+	if !strings.Contains(output, "based on 'develop'") || !strings.Contains(output, "0 commit(s) behind 'develop'") {
+		t.Errorf("Expected the parent and behind rules to pass, got: %s", output)
+	}
+	if strings.Contains(output, "FAIL") {
+		t.Errorf("Expected no failed rule, got: %s", output)
+	}
+}
+
+// TestCheckFailsWithoutTopicPrefix tests that check rejects a branch that matches no topic branch prefix.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Creates the branch login-page from develop
+// 3. Runs git flow check login-page and verifies it exits with 9 and names the expected prefixes
+func TestCheckFailsWithoutTopicPrefix(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "login-page", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "check", "login-page")
+	assertExitCode(t, err, errors.ExitCodeCheckPrefix, output)
+	if !strings.Contains(output, "no topic branch prefix matches") || !strings.Contains(output, "feature/") {
+		t.Errorf("Expected the prefix rule to fail with the known prefixes, got: %s", output)
+	}
+}
+
+// TestCheckFailsWhenTooFarBehind tests that check enforces --max-behind and gitflow.check.maxBehind.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a feature branch and commits twice to develop
+// 3. Runs git flow check --max-behind 1 and verifies it exits with 12
+// 4. Sets gitflow.feature.check.maxBehind to 2 and verifies check passes
+func TestCheckFailsWhenTooFarBehind(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "develop", "one.txt", "one")
+	commitOn(t, dir, "develop", "two.txt", "two")
+
+	output, err := testutil.RunGitFlow(t, dir, "check", "--max-behind", "1")
+	assertExitCode(t, err, errors.ExitCodeCheckBehind, output)
+	if !strings.Contains(output, "2 commit(s) behind 'develop', at most 1 allowed") {
+		t.Errorf("Expected the behind rule to fail, got: %s", output)
+	}
+
+	testutil.RunGit(t, dir, "config", "gitflow.feature.check.maxBehind", "2")
+	output, err = testutil.RunGitFlow(t, dir, "check")
+	if err != nil {
+		t.Fatalf("Expected check to pass with the configured limit: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestCheckEnforcesNamePattern tests that check matches the short name against gitflow.check.namePattern.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Sets gitflow.check.namePattern to require a ticket number and starts feature/login
+// 3. Runs git flow check and verifies it exits with 10 and reports the name rule
+func TestCheckEnforcesNamePattern(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.check.namePattern", "^[A-Z]+-[0-9]+-")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "check")
+	assertExitCode(t, err, errors.ExitCodeCheckName, output)
+	if !strings.Contains(output, "'login' does not match '^[A-Z]+-[0-9]+-'") {
+		t.Errorf("Expected the name rule to fail, got: %s", output)
+	}
+}
+
+// TestCheckDetectsWrongParent tests that check reports a feature branch containing commits of another base branch.
+// Steps:
+// 1. Sets up a test repository with staging as a base branch below develop
+// 2. Commits to staging and creates feature/login from staging without git-flow
+// 3. Runs git flow check and verifies it exits with 11 and names staging
+func TestCheckDetectsWrongParent(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupStagingBase(t, dir)
+	commitOn(t, dir, "staging", "staging.txt", "staging")
+	testutil.RunGit(t, dir, "checkout", "-b", "feature/login", "staging")
+
+	output, err := testutil.RunGitFlow(t, dir, "check")
+	assertExitCode(t, err, errors.ExitCodeCheckParent, output)
+	if !strings.Contains(output, "contains commits of 'staging' that are not in 'develop'") {
+		t.Errorf("Expected the parent rule to fail, got: %s", output)
+	}
+}
+
+// TestCheckJSONFormat tests that check --format json prints a machine-readable report.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a feature branch
+// 3. Runs git flow check --format json and verifies the decoded report
+func TestCheckJSONFormat(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "check", "feature/login", "--format", "json")
+	if err != nil {
+		t.Fatalf("Expected check to pass: %v\nOutput: %s", err, output)
+	}
+
+	var report struct {
+		Branch string
+		Type   string
+		Parent string
+		Passed bool
+		Checks []struct {
+			Rule   string
+			Passed bool
+		}
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Expected a JSON report: %v\nOutput: %s", err, output)
+	}
+	if report.Branch != "feature/login" || report.Type != "feature" || report.Parent != "develop" || !report.Passed {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	rules := []string{}
+	for _, check := range report.Checks {
+		rules = append(rules, check.Rule)
+	}
+	if strings.Join(rules, ",") != "prefix,parent,behind" {
+		t.Errorf("Expected the prefix, parent and behind rules, got: %v", rules)
+	}
+}
@@ -0,0 +1,66 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestComparePrintsForgeURL tests printing the compare URL of a feature branch.
+// Steps:
+// 1. Sets up a test repository with git-flow initialized
+// 2. Adds an origin remote pointing at GitHub
+// 3. Starts a feature branch
+// 4. Runs 'git flow feature compare --print' on it
+// 5. Verifies the GitHub compare URL against develop is printed
+func TestComparePrintsForgeURL(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	testutil.RunGit(t, dir, "remote", "add", "origin", "git@github.com:example/project.git")
+
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "compare-me"); err != nil {
+		t.Fatalf("Failed to create feature branch: %v", err)
+	}
+
+	// Print the compare URL for the current branch
+	output, err := testutil.RunGitFlow(t, dir, "feature", "compare", "--print")
+	if err != nil {
+		t.Fatalf("Failed to run compare: %v\nOutput: %s", err, output)
+	}
+
+	expected := "https://github.com/example/project/compare/develop...feature/compare-me"
+	if strings.TrimSpace(output) != expected {
+		t.Errorf("Expected URL %q, got: %s", expected, output)
+	}
+}
+
+// TestCompareUnknownForge tests that compare fails for a remote that is not a known forge.
+// Steps:
+// 1. Sets up a test repository with a local path remote
+// 2. Starts a feature branch
+// 3. Runs 'git flow feature browse --print'
+// 4. Verifies it fails and mentions gitflow.forge
+func TestCompareUnknownForge(t *testing.T) {
+	// Setup
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "local-remote"); err != nil {
+		t.Fatalf("Failed to create feature branch: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "browse", "--print")
+	if err == nil {
+		t.Fatalf("Expected compare to fail for a local remote, got: %s", output)
+	}
+	if !strings.Contains(output, "gitflow.forge") {
+		t.Errorf("Expected hint about gitflow.forge, got: %s", output)
+	}
+}
@@ -0,0 +1,189 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestConfigExportImport tests bootstrapping a repository from an exported configuration.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and customizes it
+// 2. Exports the configuration as YAML
+// 3. Imports the file into a fresh, uninitialized repository
+// 4. Verifies the branch types, settings and base branches were applied
+// 5. Verifies exporting the new repository yields the same file
+func TestConfigExportImport(t *testing.T) {
+	// Setup source repository
+	source := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, source)
+
+	if _, err := testutil.RunGitFlow(t, source, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, source, "config", "add", "base", "staging", "main"); err != nil {
+		t.Fatalf("Failed to add base branch: %v", err)
+	}
+	testutil.RunGit(t, source, "config", "gitflow.feature.finish.rebase", "true")
+
+	exported, err := testutil.RunGitFlow(t, source, "config", "export", "--format", "yaml")
+	if err != nil {
+		t.Fatalf("Failed to export: %v\nOutput: %s", err, exported)
+	}
+	if !strings.Contains(exported, "staging:") || !strings.Contains(exported, "feature.finish.rebase:") {
+		t.Errorf("Expected export to contain the customizations, got:\n%s", exported)
+	}
+
+	// Import into a fresh repository
+	target := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, target)
+
+	file := filepath.Join(t.TempDir(), "branching.yml")
+	if err := os.WriteFile(file, []byte(exported), 0644); err != nil {
+		t.Fatalf("Failed to write export: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, target, "config", "import", file)
+	if err != nil {
+		t.Fatalf("Failed to import: %v\nOutput: %s", err, output)
+	}
+
+	for _, branch := range []string{"develop", "staging"} {
+		if !testutil.BranchExists(t, target, branch) {
+			t.Errorf("Expected base branch '%s' to be created", branch)
+		}
+	}
+	if value, _ := testutil.RunGit(t, target, "config", "gitflow.feature.finish.rebase"); strings.TrimSpace(value) != "true" {
+		t.Errorf("Expected setting to be imported, got %q", value)
+	}
+
+	roundTrip, err := testutil.RunGitFlow(t, target, "config", "export")
+	if err != nil {
+		t.Fatalf("Failed to export imported configuration: %v", err)
+	}
+	if roundTrip != exported {
+		t.Errorf("Expected the same export after import.\nBefore:\n%s\nAfter:\n%s", exported, roundTrip)
+	}
+}
+
+// TestConfigImportReplacesBranchTypes tests that importing removes branch types missing from the file.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Imports a JSON file that only defines main and feature
+// 3. Verifies the other branch types are removed from the configuration
+func TestConfigImportReplacesBranchTypes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "github-flow.json")
+	content := `{
+  "version": "1.0",
+  "branches": {
+    "main": {"type": "base", "upstreamStrategy": "none", "downstreamStrategy": "none"},
+    "feature": {"type": "topic", "parent": "main", "prefix": "feature/", "upstreamStrategy": "merge", "downstreamStrategy": "rebase"}
+  }
+}
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "import", file)
+	if err != nil {
+		t.Fatalf("Failed to import: %v\nOutput: %s", err, output)
+	}
+
+	for _, removed := range []string{"develop", "release", "hotfix"} {
+		if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch."+removed+".type"); err == nil {
+			t.Errorf("Expected branch type '%s' to be removed", removed)
+		}
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.parent"); strings.TrimSpace(value) != "main" {
+		t.Errorf("Expected feature parent 'main', got %q", value)
+	}
+}
+
+// TestConfigImportRejectsInvalidFile tests that an invalid file is rejected before anything is written.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Imports a YAML file whose topic type refers to an unknown parent
+// 3. Verifies the command fails with an invalid input exit code
+// 4. Verifies the existing configuration is unchanged
+func TestConfigImportRejectsInvalidFile(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "broken.yaml")
+	content := "version: \"1.0\"\nbranches:\n  feature:\n    type: topic\n    parent: trunk\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "import", file)
+	if err == nil {
+		t.Fatalf("Expected import to fail, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d, got: %v", errors.ExitCodeInvalidInput, err)
+	}
+	if !strings.Contains(output, "trunk") {
+		t.Errorf("Expected error to name the unknown parent, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.develop.type"); strings.TrimSpace(value) != "base" {
+		t.Errorf("Expected configuration to be unchanged, got %q", value)
+	}
+}
+
+// TestConfigGraph tests rendering the branching model as a diagram.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and adds a staging base branch
+// 2. Runs 'git flow config graph' and 'git flow config graph --format mermaid'
+// 3. Verifies both outputs contain the new branch and its relation to main
+// 4. Verifies an unknown format fails with an invalid input exit code
+func TestConfigGraph(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "config", "add", "base", "staging", "main"); err != nil {
+		t.Fatalf("Failed to add base branch: %v", err)
+	}
+
+	dot, err := testutil.RunGitFlow(t, dir, "config", "graph")
+	if err != nil {
+		t.Fatalf("Failed to render DOT graph: %v\nOutput: %s", err, dot)
+	}
+	if !strings.HasPrefix(dot, "digraph gitflow {") || !strings.Contains(dot, `"main" -> "staging"`) {
+		t.Errorf("Expected DOT graph with staging under main, got:\n%s", dot)
+	}
+
+	mermaid, err := testutil.RunGitFlow(t, dir, "config", "graph", "--format", "mermaid")
+	if err != nil {
+		t.Fatalf("Failed to render Mermaid graph: %v\nOutput: %s", err, mermaid)
+	}
+	if !strings.HasPrefix(mermaid, "flowchart TD") || !strings.Contains(mermaid, `["staging"]`) {
+		t.Errorf("Expected Mermaid flowchart with staging, got:\n%s", mermaid)
+	}
+
+	_, err = testutil.RunGitFlow(t, dir, "config", "graph", "--format", "svg")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d for an unknown format, got: %v", errors.ExitCodeInvalidInput, err)
+	}
+}
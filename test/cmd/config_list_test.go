@@ -0,0 +1,103 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestConfigListFilterByType tests listing only base branches or only topic types.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs config list --type base and --type topic
+// 3. Verifies each listing contains only the requested kind of branch
+func TestConfigListFilterByType(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "list", "--type", "base")
+	if err != nil {
+		t.Fatalf("Failed to list base branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "develop → main") || strings.Contains(output, "Topic branch types:") || strings.Contains(output, "Configuration commands:") {
+		t.Errorf("Expected only base branches, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "list", "--type", "topic")
+	if err != nil {
+		t.Fatalf("Failed to list topic types: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "feature:") || strings.Contains(output, "Base branches:") {
+		t.Errorf("Expected only topic branch types, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "list", "--type", "release-ish")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected invalid type to fail with exit code %d, got %v: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+}
+
+// TestConfigListJSONByName tests querying a single branch type as JSON.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs config list --name feature --format json
+// 3. Verifies the output parses and contains only the feature type with its prefix
+// 4. Verifies an unknown name fails
+func TestConfigListJSONByName(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "list", "--name", "feature", "--format", "json")
+	if err != nil {
+		t.Fatalf("Failed to list feature type: %v\nOutput: %s", err, output)
+	}
+	var doc config.Document
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("Expected JSON output, got %v: %s", err, output)
+	}
+	if len(doc.Branches) != 1 || doc.Branches["feature"].Prefix != "feature/" || doc.Branches["feature"].Parent != "develop" {
+		t.Errorf("Expected only the feature type, got: %s", output)
+	}
+	if doc.Settings != nil {
+		t.Errorf("Expected no settings in the listing, got: %v", doc.Settings)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "list", "--type", "base", "--name", "feature", "--format", "json")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected a type mismatch to fail with exit code %d, got %v: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+}
+
+// TestConfigListTOMLByType tests listing base branches as TOML.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs config list --type base --format toml
+// 3. Verifies the base branch tables are present and topic tables are not
+func TestConfigListTOMLByType(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "list", "--type", "base", "--format", "toml")
+	if err != nil {
+		t.Fatalf("Failed to list base branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "[branches.develop]") || !strings.Contains(output, "[branches.main]") || strings.Contains(output, "[branches.feature]") {
+		t.Errorf("Expected only base branch tables, got: %s", output)
+	}
+}
@@ -0,0 +1,108 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestStabilizationRoutesFeaturesToRelease tests that features started while a
+// release is stabilized start from and finish into it, until it is finished.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and starts release 2.0
+// 2. Runs 'git flow config set stabilization release/2.0'
+// 3. Starts and finishes feature 'polish' with a commit
+// 4. Verifies the feature was merged into release/2.0 and not into develop
+// 5. Finishes the release and verifies gitflow.stabilization was removed
+// 6. Starts feature 'next' and verifies it starts from develop again
+func TestStabilizationRoutesFeaturesToRelease(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "2.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "config", "set", "stabilization", "release/2.0")
+	if err != nil {
+		t.Fatalf("Failed to set stabilization: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "bugfix and feature") {
+		t.Errorf("Expected the output to name the routed types, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "polish")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "from 'release/2.0'") {
+		t.Errorf("Expected the feature to start from the release branch, got: %s", output)
+	}
+	testutil.WriteFile(t, dir, "polish.txt", "polish")
+	testutil.RunGit(t, dir, "add", "polish.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Polish")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "polish")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using base branch 'release/2.0' (stabilization)") {
+		t.Errorf("Expected the feature to finish into the release branch, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "release/2.0:polish.txt"); err != nil {
+		t.Error("Expected the feature to be merged into release/2.0")
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "develop:polish.txt"); err == nil {
+		t.Error("Expected the feature not to be merged into develop")
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "2.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	if value, err := testutil.RunGit(t, dir, "config", "gitflow.stabilization"); err == nil {
+		t.Errorf("Expected gitflow.stabilization to be removed, got: %s", value)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "next")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "from 'develop'") {
+		t.Errorf("Expected the feature to start from develop, got: %s", output)
+	}
+}
+
+// TestConfigSetValidatesStabilization tests that only an existing topic branch
+// can be stabilized and that unknown settings are rejected.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs 'git flow config set stabilization develop' and verifies exit code 2
+// 3. Runs 'git flow config set stabilization release/9.9' and verifies exit code 5
+// 4. Runs 'git flow config set unknown value' and verifies exit code 2
+func TestConfigSetValidatesStabilization(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code errors.ExitCode
+	}{
+		{[]string{"config", "set", "stabilization", "develop"}, errors.ExitCodeInvalidInput},
+		{[]string{"config", "set", "stabilization", "release/9.9"}, errors.ExitCodeBranchNotFound},
+		{[]string{"config", "set", "unknown", "value"}, errors.ExitCodeInvalidInput},
+	} {
+		output, err := testutil.RunGitFlow(t, dir, tc.args...)
+		exitErr, ok := err.(*testutil.ExitError)
+		if !ok || exitErr.ExitCode != int(tc.code) {
+			t.Errorf("%v: expected exit code %d, got %v\nOutput: %s", tc.args, tc.code, err, output)
+		}
+	}
+}
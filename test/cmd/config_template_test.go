@@ -0,0 +1,112 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestConfigAddTopicWithTemplate tests adding a topic type that inherits from a template.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and defines template shortlived
+// 2. Adds topic type spike with --template shortlived and type chore with an overriding option
+// 3. Verifies the branch properties are copied from the template unless overridden
+// 4. Starts and finishes a spike branch
+// 5. Verifies the finish option of the template applies without being copied to the type
+func TestConfigAddTopicWithTemplate(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.template.shortlived.prefix", "exp/")
+	testutil.RunGit(t, dir, "config", "gitflow.template.shortlived.upstreamStrategy", "squash")
+	testutil.RunGit(t, dir, "config", "gitflow.template.shortlived.finish.keep", "true")
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "add", "topic", "spike", "develop", "--template", "shortlived")
+	if err != nil {
+		t.Fatalf("Failed to add topic type with template: %v\nOutput: %s", err, output)
+	}
+	for key, expected := range map[string]string{
+		"gitflow.branch.spike.prefix":             "exp/",
+		"gitflow.branch.spike.upstreamStrategy":   "squash",
+		"gitflow.branch.spike.downstreamStrategy": "merge",
+		"gitflow.branch.spike.template":           "shortlived",
+	} {
+		if value, _ := testutil.RunGit(t, dir, "config", "--get", key); strings.TrimSpace(value) != expected {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, expected, strings.TrimSpace(value))
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "add", "topic", "chore", "develop", "--template", "shortlived", "--prefix", "chore/", "--upstream-strategy", "rebase")
+	if err != nil {
+		t.Fatalf("Failed to add topic type with template and options: %v\nOutput: %s", err, output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.chore.prefix"); strings.TrimSpace(value) != "chore/" {
+		t.Errorf("Expected --prefix to override the template, got '%s'", strings.TrimSpace(value))
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.chore.upstreamStrategy"); strings.TrimSpace(value) != "rebase" {
+		t.Errorf("Expected --upstream-strategy to override the template, got '%s'", strings.TrimSpace(value))
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "spike", "start", "idea")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "exp/idea", "idea.txt", "idea")
+
+	output, err = testutil.RunGitFlow(t, dir, "spike", "finish", "idea")
+	if err != nil {
+		t.Fatalf("Failed to finish spike branch: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "exp/idea") {
+		t.Error("Expected exp/idea to be kept, as the template sets finish.keep")
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.spike.finish.keep"); strings.TrimSpace(value) != "" {
+		t.Errorf("Expected the template option not to be copied to the type, got gitflow.spike.finish.keep=%s", value)
+	}
+
+	// The option of the type takes precedence over the template
+	testutil.RunGit(t, dir, "config", "gitflow.spike.finish.keep", "false")
+	output, err = testutil.RunGitFlow(t, dir, "spike", "start", "other")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "exp/other", "other.txt", "other")
+	output, err = testutil.RunGitFlow(t, dir, "spike", "finish", "other")
+	if err != nil {
+		t.Fatalf("Failed to finish spike branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "exp/other") {
+		t.Error("Expected exp/other to be deleted, as gitflow.spike.finish.keep overrides the template")
+	}
+}
+
+// TestConfigAddTopicWithUndefinedTemplate tests that a template must be defined.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Adds topic type spike with --template naming an undefined template
+// 3. Verifies the command fails and spike is not configured
+func TestConfigAddTopicWithUndefinedTemplate(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "add", "topic", "spike", "develop", "--template", "missing")
+	if err == nil {
+		t.Fatalf("Expected adding a topic type with an undefined template to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "template 'missing' is not defined") {
+		t.Errorf("Expected error about the undefined template, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.spike.type"); strings.TrimSpace(value) != "" {
+		t.Errorf("Expected spike not to be configured, got type '%s'", strings.TrimSpace(value))
+	}
+}
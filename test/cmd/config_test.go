@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gittower/git-flow-next/internal/config"
@@ -521,6 +522,192 @@ func TestCircularDependencyValidation(t *testing.T) {
 	})
 }
 
+// TestConfigDryRun tests previewing configuration changes with --dry-run.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs 'git flow config rename base develop integration --dry-run'
+// 3. Verifies the output is a diff removing develop keys and adding integration keys
+// 4. Verifies neither the configuration nor the Git branch changed
+// 5. Runs 'git flow config delete topic hotfix --dry-run' and verifies the section is kept
+func TestConfigDryRun(t *testing.T) {
+	// Setup test repository
+	tempDir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, tempDir)
+
+	_, err := testutil.RunGitFlow(t, tempDir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, tempDir, "config", "rename", "base", "develop", "integration", "--dry-run")
+	if err != nil {
+		t.Fatalf("Dry run failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"--- a/gitflow",
+		"+++ b/gitflow",
+		"-gitflow.branch.develop.type=base",
+		"+gitflow.branch.integration.type=base",
+		"-gitflow.branch.feature.parent=develop",
+		"+gitflow.branch.feature.parent=integration",
+		"Would rename Git branch: develop → integration",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected dry run output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	// Nothing was applied
+	if !testutil.BranchExists(t, tempDir, "develop") || testutil.BranchExists(t, tempDir, "integration") {
+		t.Error("Expected Git branch 'develop' to be left alone")
+	}
+	if value, _ := testutil.RunGit(t, tempDir, "config", "gitflow.branch.develop.type"); strings.TrimSpace(value) != "base" {
+		t.Errorf("Expected develop configuration to be kept, got %q", value)
+	}
+	if _, err := testutil.RunGit(t, tempDir, "config", "gitflow.branch.integration.type"); err == nil {
+		t.Error("Expected no configuration to be written for 'integration'")
+	}
+
+	output, err = testutil.RunGitFlow(t, tempDir, "config", "delete", "topic", "hotfix", "--dry-run")
+	if err != nil {
+		t.Fatalf("Dry run failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "-gitflow.branch.hotfix.prefix=hotfix/") {
+		t.Errorf("Expected hotfix keys to be shown as removed, got:\n%s", output)
+	}
+	if value, _ := testutil.RunGit(t, tempDir, "config", "gitflow.branch.hotfix.type"); strings.TrimSpace(value) != "topic" {
+		t.Errorf("Expected hotfix configuration to be kept, got %q", value)
+	}
+}
+
+// TestConfigRenameTopicRemovesOldKeys tests that renaming a topic type removes its old configuration.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Renames the bugfix topic type to fix
+// 3. Verifies the gitflow.branch.bugfix section is gone and fix is configured
+func TestConfigRenameTopicRemovesOldKeys(t *testing.T) {
+	// Setup test repository
+	tempDir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, tempDir)
+
+	_, err := testutil.RunGitFlow(t, tempDir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, tempDir, "config", "rename", "topic", "bugfix", "fix")
+	if err != nil {
+		t.Fatalf("Rename failed: %v\nOutput: %s", err, output)
+	}
+
+	if _, err := testutil.RunGit(t, tempDir, "config", "gitflow.branch.bugfix.type"); err == nil {
+		t.Error("Expected gitflow.branch.bugfix to be removed")
+	}
+	if value, _ := testutil.RunGit(t, tempDir, "config", "gitflow.branch.fix.prefix"); strings.TrimSpace(value) != "bugfix/" {
+		t.Errorf("Expected renamed type to keep its prefix, got %q", value)
+	}
+}
+
+// TestConfigRenameTopicApplyToBranches tests renaming existing branches along with their type.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts two feature branches, one of them from main
+// 3. Renames the feature type to feat with --apply-to-branches
+// 4. Verifies the prefix, the branch names and their stored base branches moved
+// 5. Verifies the branches are listed as feat branches and the stored base is used on finish
+func TestConfigRenameTopicApplyToBranches(t *testing.T) {
+	// Setup test repository
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	for _, args := range [][]string{{"feature", "start", "login"}, {"feature", "start", "hotpatch", "main"}} {
+		if output, err := testutil.RunGitFlow(t, dir, args...); err != nil {
+			t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+		}
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "rename", "topic", "feature", "feat", "--apply-to-branches")
+	if err != nil {
+		t.Fatalf("Rename failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"Renamed Git branch: feature/login → feat/login",
+		"Renamed Git branch: feature/hotpatch → feat/hotpatch",
+		"Renamed prefix: feature/ → feat/ (2 branch(es) renamed)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feat.prefix"); strings.TrimSpace(value) != "feat/" {
+		t.Errorf("Expected prefix feat/, got %q", value)
+	}
+	if testutil.BranchExists(t, dir, "feature/login") || !testutil.BranchExists(t, dir, "feat/login") {
+		t.Error("Expected feature/login to be renamed to feat/login")
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feat/hotpatch.base"); strings.TrimSpace(value) != "main" {
+		t.Errorf("Expected the stored base of feat/hotpatch to be main, got %q", value)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/hotpatch.base"); err == nil {
+		t.Error("Expected the stored base of feature/hotpatch to be removed")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feat", "list")
+	if err != nil || !strings.Contains(output, "login") || !strings.Contains(output, "hotpatch") {
+		t.Errorf("Expected feat list to show both branches: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.finish.baseresolution", "stored")
+	output, err = testutil.RunGitFlow(t, dir, "feat", "finish", "hotpatch")
+	if err != nil {
+		t.Fatalf("Failed to finish feat branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using base branch 'main' (stored base)") {
+		t.Errorf("Expected the branch to be finished into its stored base main, got: %s", output)
+	}
+}
+
+// TestConfigRenameTopicApplyToBranchesConflict tests that the cascade stops before
+// changing anything when a renamed branch would overwrite an existing one.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Starts feature/login and creates a plain feat/login branch
+// 3. Renames the feature type to feat with --apply-to-branches
+// 4. Verifies the command fails and neither branches nor configuration changed
+func TestConfigRenameTopicApplyToBranchesConflict(t *testing.T) {
+	// Setup test repository
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "feat/login", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "rename", "topic", "feature", "feat", "--apply-to-branches")
+	if err == nil {
+		t.Fatalf("Expected rename to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "feat/login") {
+		t.Errorf("Expected error to name the existing branch, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to be kept")
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.type"); strings.TrimSpace(value) != "topic" {
+		t.Errorf("Expected the feature type to stay configured, got %q", value)
+	}
+}
+
 // Helper functions to capture command execution without exiting
 
 func captureConfigAddBase(t *testing.T, dir string, name, parent, upstreamStrategy, downstreamStrategy string, autoUpdate bool) error {
@@ -620,3 +807,52 @@ func captureConfigDeleteTopic(t *testing.T, dir string, name string) error {
 	_, err := testutil.RunGitFlow(t, dir, args...)
 	return err
 }
+
+// TestConfigTopicTypeNameCollidesWithCommand tests that topic types can't be named like built-in commands.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Tries to add a topic type named 'finish' and rename 'support' to 'config'
+// 3. Verifies both are rejected and the configuration is unchanged
+// 4. Configures a colliding type directly in Git config
+// 5. Verifies commands warn about it and the generic start form still works
+func TestConfigTopicTypeNameCollidesWithCommand(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "add", "topic", "finish", "develop")
+	if err == nil {
+		t.Fatalf("Expected adding topic type 'finish' to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "'finish' is a built-in git-flow command") {
+		t.Errorf("Expected error about the built-in command, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "rename", "topic", "support", "config")
+	if err == nil {
+		t.Fatalf("Expected renaming to 'config' to fail, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.support.type"); strings.TrimSpace(value) != "topic" {
+		t.Errorf("Expected support to stay configured, got type %q", value)
+	}
+
+	// A colliding type configured by hand is skipped with a warning
+	testutil.RunGit(t, dir, "config", "gitflow.branch.state.type", "topic")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.state.parent", "develop")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.state.prefix", "state/")
+
+	output, err = testutil.RunGitFlow(t, dir, "start", "state", "machine")
+	if err != nil {
+		t.Fatalf("Failed to start colliding type with the generic form: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Topic branch type 'state' has the same name as a built-in command") {
+		t.Errorf("Expected a warning about the colliding type, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "state/machine") {
+		t.Error("Expected state/machine to be created")
+	}
+}
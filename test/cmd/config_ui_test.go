@@ -0,0 +1,87 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestConfigUIRequiresTerminal tests that 'git flow config ui' refuses to run without a terminal.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs 'git flow config ui' with piped input
+// 3. Verifies it exits with an invalid input error that points to the config subcommands
+// 4. Verifies the configuration is unchanged
+func TestConfigUIRequiresTerminal(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "s\ny\n", "config", "ui")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Fatalf("Expected exit code %d, got: %v\nOutput: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+	if !strings.Contains(output, "needs a terminal") {
+		t.Errorf("Expected the missing terminal to be reported, got:\n%s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.prefix"); strings.TrimSpace(value) != "feature/" {
+		t.Errorf("Expected prefix to be unchanged, got %q", value)
+	}
+}
+
+// TestConfigUINotInitialized tests that 'git flow config ui' requires an initialized repository.
+// Steps:
+// 1. Sets up a test repository without initializing git-flow
+// 2. Runs 'git flow config ui'
+// 3. Verifies it exits with the not initialized error before checking for a terminal
+func TestConfigUINotInitialized(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "ui")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeNotInitialized) {
+		t.Fatalf("Expected exit code %d, got: %v\nOutput: %s", errors.ExitCodeNotInitialized, err, output)
+	}
+}
+
+// TestInitInteractiveUIRequiresTerminal tests that 'git flow init --interactive-ui' refuses to run without a terminal.
+// Steps:
+// 1. Sets up a fresh test repository
+// 2. Runs 'git flow init --interactive-ui' with piped input
+// 3. Verifies it exits with an invalid input error and git-flow is not initialized
+func TestInitInteractiveUIRequiresTerminal(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "\ns\ny\n", "init", "--interactive-ui")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Fatalf("Expected exit code %d, got: %v\nOutput: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.initialized"); strings.TrimSpace(value) == "true" {
+		t.Error("Expected git-flow not to be initialized")
+	}
+}
+
+// TestInitInteractiveUIExclusive tests that --interactive-ui cannot be combined with a preset.
+// Steps:
+// 1. Sets up a fresh test repository
+// 2. Runs 'git flow init --interactive-ui --preset=github'
+// 3. Verifies it exits with an invalid input error
+func TestInitInteractiveUIExclusive(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--interactive-ui", "--preset=github")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Fatalf("Expected exit code %d, got: %v\nOutput: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+}
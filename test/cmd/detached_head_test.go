@@ -0,0 +1,130 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupDetachedFeature initializes git-flow, starts a feature with one commit
+// and detaches HEAD at that commit.
+func setupDetachedFeature(t *testing.T, dir string, name string) {
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, name+".txt", "feature content")
+	testutil.RunGit(t, dir, "add", name+".txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add "+name)
+	if _, err := testutil.RunGit(t, dir, "checkout", "--detach"); err != nil {
+		t.Fatalf("Failed to detach HEAD: %v", err)
+	}
+}
+
+// TestDetachedHeadCommandsNeedBranchName tests the error of commands that act on the
+// current branch when HEAD is detached.
+// Steps:
+// 1. Sets up a feature branch and detaches HEAD
+// 2. Runs finish, publish and update without a branch name, in shorthand and topic form
+// 3. Verifies each fails with exit code 2 and suggests naming the branch
+func TestDetachedHeadCommandsNeedBranchName(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupDetachedFeature(t, dir, "detached")
+
+	tests := []struct {
+		args  []string
+		usage string
+	}{
+		{[]string{"finish"}, "git flow finish <branch>"},
+		{[]string{"publish"}, "git flow publish <branch>"},
+		{[]string{"update"}, "git flow update <branch>"},
+		{[]string{"feature", "finish"}, "git flow feature finish <name>"},
+		{[]string{"feature", "publish"}, "git flow feature publish <name>"},
+	}
+
+	for _, test := range tests {
+		output, err := testutil.RunGitFlow(t, dir, test.args...)
+		if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+			t.Errorf("Expected '%s' to fail with exit code %d, got %v: %s", strings.Join(test.args, " "), errors.ExitCodeInvalidInput, err, output)
+			continue
+		}
+		if !strings.Contains(output, "HEAD is detached") || !strings.Contains(output, test.usage) {
+			t.Errorf("Expected '%s' to suggest %q, got: %s", strings.Join(test.args, " "), test.usage, output)
+		}
+	}
+}
+
+// TestDetachedHeadShorthandFinishWithBranchName tests that the shorthand finish
+// accepts an explicit branch while HEAD is detached.
+// Steps:
+// 1. Sets up a feature branch and detaches HEAD
+// 2. Runs 'git flow finish feature/named'
+// 3. Verifies the feature was merged into develop and deleted
+func TestDetachedHeadShorthandFinishWithBranchName(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupDetachedFeature(t, dir, "named")
+
+	output, err := testutil.RunGitFlow(t, dir, "finish", "feature/named")
+	if err != nil {
+		t.Fatalf("Failed to finish named feature: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "develop:named.txt"); err != nil {
+		t.Error("Expected feature to be merged into develop")
+	}
+	if testutil.BranchExists(t, dir, "feature/named") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestDetachedHeadShorthandFinishContinue tests continuing a finish whose rebase
+// stopped with a detached HEAD.
+// Steps:
+// 1. Creates conflicting changes on a feature and on develop
+// 2. Finishes the feature with --rebase, which stops in the rebase
+// 3. Resolves the conflict and runs the shorthand 'git flow finish --continue'
+// 4. Verifies the finish completes
+func TestDetachedHeadShorthandFinishContinue(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "rebased"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "feature")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Feature change")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "shared.txt", "develop")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop change")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "rebased", "--rebase"); err == nil {
+		t.Fatalf("Expected finish to stop at the conflict, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "symbolic-ref", "-q", "HEAD"); err == nil {
+		t.Fatal("Expected HEAD to be detached during the rebase")
+	}
+
+	testutil.WriteFile(t, dir, "shared.txt", "resolved")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	content, _ := testutil.RunGit(t, dir, "show", "develop:shared.txt")
+	if strings.TrimSpace(content) != "resolved" {
+		t.Errorf("Expected resolved content on develop, got %q", content)
+	}
+}
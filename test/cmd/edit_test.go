@@ -0,0 +1,208 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// createEditorScript writes an editor script that saves the template it is
+// given to saveDir and replaces the message with "Edited <file name>".
+func createEditorScript(t *testing.T, saveDir string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	script := `#!/bin/sh
+name=$(basename "$1")
+cp "$1" "` + saveDir + `/$name"
+printf 'Edited %s\n' "$name" > "$1"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create editor script: %v", err)
+	}
+	return path
+}
+
+// TestFinishEditMergeAndTagMessages tests that --edit opens the merge commit and tag messages in the editor.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a release branch and commits to it
+// 3. Finishes the release with --no-ff, --edit and an editor that replaces the messages
+// 4. Verifies the templates held the default messages and commented instructions
+// 5. Verifies the merge commit on main and the tag carry the edited messages
+func TestFinishEditMergeAndTagMessages(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v", err)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Prepare release")
+
+	saveDir := t.TempDir()
+	output, err := testutil.RunGitFlowWithEditor(t, dir, createEditorScript(t, saveDir), "release", "finish", "1.0.0", "--no-ff", "--edit")
+	if err != nil {
+		t.Fatalf("Failed to finish release with --edit: %v\nOutput: %s", err, output)
+	}
+
+	mergeTemplate, err := os.ReadFile(filepath.Join(saveDir, "MERGE_EDITMSG"))
+	if err != nil {
+		t.Fatalf("Expected the merge message to be edited: %v", err)
+	}
+	if !strings.HasPrefix(string(mergeTemplate), "Merge branch 'release/1.0.0' into main\n") {
+		t.Errorf("Expected the default merge message in the template, got:\n%s", mergeTemplate)
+	}
+	if !strings.Contains(string(mergeTemplate), "# Lines starting with '#' will be ignored") {
+		t.Errorf("Expected commented instructions in the template, got:\n%s", mergeTemplate)
+	}
+	tagTemplate, err := os.ReadFile(filepath.Join(saveDir, "TAG_EDITMSG"))
+	if err != nil {
+		t.Fatalf("Expected the tag message to be edited: %v", err)
+	}
+	if !strings.HasPrefix(string(tagTemplate), "Tagging version 1.0.0\n") {
+		t.Errorf("Expected the default tag message in the template, got:\n%s", tagTemplate)
+	}
+
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "main")
+	if strings.TrimSpace(subject) != "Edited MERGE_EDITMSG" {
+		t.Errorf("Expected the edited merge message on main, got %q", subject)
+	}
+	tagMessage, _ := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.0.0")
+	if strings.TrimSpace(tagMessage) != "Edited TAG_EDITMSG" {
+		t.Errorf("Expected the edited tag message, got %q", tagMessage)
+	}
+}
+
+// TestFinishEditSquashMessage tests that --edit opens the squash commit message in the editor.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a feature branch and commits to it
+// 3. Finishes the feature with --squash, --squash-message and --edit
+// 4. Verifies the template held the given squash message
+// 5. Verifies the squash commit on develop carries the edited message
+func TestFinishEditSquashMessage(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "squashed"); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature")
+
+	saveDir := t.TempDir()
+	output, err := testutil.RunGitFlowWithEditor(t, dir, createEditorScript(t, saveDir), "feature", "finish", "squashed", "--squash", "--squash-message", "Add the squashed feature", "-e")
+	if err != nil {
+		t.Fatalf("Failed to finish feature with --edit: %v\nOutput: %s", err, output)
+	}
+
+	template, err := os.ReadFile(filepath.Join(saveDir, "SQUASH_EDITMSG"))
+	if err != nil {
+		t.Fatalf("Expected the squash message to be edited: %v", err)
+	}
+	if !strings.HasPrefix(string(template), "Add the squashed feature\n") {
+		t.Errorf("Expected the given squash message in the template, got:\n%s", template)
+	}
+
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Edited SQUASH_EDITMSG" {
+		t.Errorf("Expected the edited squash message on develop, got %q", subject)
+	}
+}
+
+// TestFinishEditEmptyMessageAborts tests that an empty edited message aborts the finish before anything is changed.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a feature branch and commits to it
+// 3. Finishes the feature with --edit and an editor that leaves only comments
+// 4. Verifies the command fails with exit code 2 and reports the empty message
+// 5. Verifies the feature branch still exists and no finish is in progress
+func TestFinishEditEmptyMessageAborts(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "empty"); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature")
+
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\necho '# nothing to say' > \"$1\"\n"), 0755); err != nil {
+		t.Fatalf("Failed to create editor script: %v", err)
+	}
+
+	output, err := testutil.RunGitFlowWithEditor(t, dir, editor, "feature", "finish", "empty", "--no-ff", "--edit")
+	if err == nil {
+		t.Fatalf("Expected finish to fail with an empty message\nOutput: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 2 {
+		t.Errorf("Expected exit code 2, got: %v", err)
+	}
+	if !strings.Contains(output, "aborting due to empty merge commit message") {
+		t.Errorf("Expected the empty message to be reported, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/empty") {
+		t.Error("Expected the feature branch to be kept")
+	}
+	if testutil.IsMergeInProgress(t, dir) {
+		t.Error("Expected no finish to be in progress")
+	}
+}
+
+// TestStartEditDescription tests that start --edit stores a branch description written in core.editor.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Configures core.editor and leaves GIT_EDITOR unset
+// 3. Starts a feature branch with --edit
+// 4. Verifies the description is stored in branch.<name>.description
+// 5. Starts another feature with an editor that leaves only comments and verifies no description is stored
+func TestStartEditDescription(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	saveDir := t.TempDir()
+	testutil.RunGit(t, dir, "config", "core.editor", createEditorScript(t, saveDir))
+
+	output, err := testutil.RunGitFlowWithEditor(t, dir, "", "feature", "start", "described", "--edit")
+	if err != nil {
+		t.Fatalf("Failed to start feature with --edit: %v\nOutput: %s", err, output)
+	}
+	template, err := os.ReadFile(filepath.Join(saveDir, "BRANCH_DESCRIPTION"))
+	if err != nil {
+		t.Fatalf("Expected core.editor to be opened: %v", err)
+	}
+	if !strings.Contains(string(template), "# Please enter the description for branch 'feature/described'.") {
+		t.Errorf("Expected commented instructions in the template, got:\n%s", template)
+	}
+	description, _ := testutil.RunGit(t, dir, "config", "branch.feature/described.description")
+	if strings.TrimSpace(description) != "Edited BRANCH_DESCRIPTION" {
+		t.Errorf("Expected the edited description, got %q", description)
+	}
+
+	output, err = testutil.RunGitFlowWithEditor(t, dir, ":", "feature", "start", "undescribed", "-e")
+	if err != nil {
+		t.Fatalf("Failed to start feature with --edit: %v\nOutput: %s", err, output)
+	}
+	if description, err := testutil.RunGit(t, dir, "config", "branch.feature/undescribed.description"); err == nil {
+		t.Errorf("Expected no description to be stored, got %q", description)
+	}
+}
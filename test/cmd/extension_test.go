@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// createExtension creates an executable extension command on a new PATH entry.
+func createExtension(t *testing.T, name, script string) {
+	t.Helper()
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "git-flow-"+name), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create extension %s: %v", name, err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestExtensionCommand tests that an unknown command runs git-flow-<name> from PATH
+// with the repository context.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Places a git-flow-hello extension on PATH that prints its arguments and context
+// 3. Runs 'git flow hello' with arguments
+// 4. Verifies the arguments, environment variables, configuration file and exit code
+func TestExtensionCommand(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	createExtension(t, "hello", `#!/bin/sh
+echo "args=$1|$2"
+echo "gitdir=$GITFLOW_GIT_DIR"
+echo "worktree=$GITFLOW_WORK_TREE"
+echo "remote=$GITFLOW_REMOTE"
+grep -q "prefix: feature/" "$GITFLOW_CONFIG_FILE" && echo "config=ok"
+exit 7
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "hello", "one", "two words")
+	exitErr, ok := err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != 7 {
+		t.Fatalf("Expected the extension's exit code 7, got: %v\nOutput: %s", err, output)
+	}
+
+	realDir, _ := filepath.EvalSymlinks(dir)
+	for _, expected := range []string{
+		"args=one|two words",
+		"gitdir=" + filepath.Join(realDir, ".git"),
+		"worktree=" + realDir,
+		"remote=origin",
+		"config=ok",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+// TestExtensionDoesNotShadowBuiltins tests that built-in commands and topic branch
+// types take precedence over extensions of the same name, and that extensions are
+// listed in the help.
+// Steps:
+// 1. Places git-flow-overview, git-flow-feature and git-flow-deploy extensions on PATH
+// 2. Runs 'git flow overview' and 'git flow feature list'
+// 3. Verifies the built-in commands ran
+// 4. Verifies the help lists only the deploy extension
+func TestExtensionDoesNotShadowBuiltins(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	createExtension(t, "overview", "#!/bin/sh\necho extension-ran\n")
+	createExtension(t, "feature", "#!/bin/sh\necho extension-ran\n")
+	createExtension(t, "deploy", "#!/bin/sh\necho deploying\n")
+
+	for _, args := range [][]string{{"overview"}, {"feature", "list"}} {
+		output, err := testutil.RunGitFlow(t, dir, args...)
+		if err != nil {
+			t.Fatalf("Expected 'git flow %s' to succeed: %v\nOutput: %s", strings.Join(args, " "), err, output)
+		}
+		if strings.Contains(output, "extension-ran") {
+			t.Errorf("Expected built-in 'git flow %s' to run, got: %s", strings.Join(args, " "), output)
+		}
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "--help")
+	if err != nil {
+		t.Fatalf("Failed to show help: %v\nOutput: %s", err, output)
+	}
+	index := strings.Index(output, "Extension commands found on PATH:")
+	if index < 0 {
+		t.Fatalf("Expected help to list extensions, got: %s", output)
+	}
+	help := output[index:]
+	if !strings.Contains(help, "deploy") || strings.Contains(help, "  overview") || strings.Contains(help, "  feature") {
+		t.Errorf("Expected only the deploy extension to be listed, got: %s", help)
+	}
+}
+
+// TestUnknownCommandWithoutExtension tests that an unknown command without an
+// extension still fails as before.
+func TestUnknownCommandWithoutExtension(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "no-such-command")
+	if err == nil {
+		t.Fatalf("Expected unknown command to fail, got: %s", output)
+	}
+	if !strings.Contains(output, `unknown command "no-such-command"`) {
+		t.Errorf("Expected unknown command error, got: %s", output)
+	}
+}
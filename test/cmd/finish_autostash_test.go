@@ -0,0 +1,98 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupUntrackedOnHotfix tracks generated.txt on develop only, starts hotfix 1.0.1
+// with a commit and leaves an untracked generated.txt in the working tree
+func setupUntrackedOnHotfix(t *testing.T, dir string) {
+	t.Helper()
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "generated.txt", "tracked on develop")
+	testutil.RunGit(t, dir, "add", "generated.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Track generated file")
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.1", "main")
+	testutil.WriteFile(t, dir, "generated.txt", "local build output")
+}
+
+// TestFinishFailsOnUntrackedFilesInChild tests that finish stops before merging when a child checkout would overwrite untracked files.
+// Steps:
+// 1. Tracks generated.txt on develop and leaves an untracked generated.txt on hotfix 1.0.1
+// 2. Runs 'git flow hotfix finish 1.0.1'
+// 3. Verifies it fails with exit code 6, names the file and suggests --autostash-untracked
+// 4. Verifies main is unchanged and the untracked file is kept
+func TestFinishFailsOnUntrackedFilesInChild(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	setupUntrackedOnHotfix(t, dir)
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "generated.txt") || !strings.Contains(output, "--autostash-untracked") {
+		t.Errorf("Expected the file and --autostash-untracked to be reported\nOutput: %s", output)
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+	if content := testutil.ReadFile(t, dir, "generated.txt"); content != "local build output" {
+		t.Errorf("Expected the untracked file to be kept, got %q", content)
+	}
+}
+
+// TestFinishAutostashUntracked tests that --autostash-untracked stashes untracked files around the child updates.
+// Steps:
+// 1. Tracks generated.txt on develop and leaves an untracked generated.txt on hotfix 1.0.1
+// 2. Runs 'git flow hotfix finish --autostash-untracked 1.0.1'
+// 3. Verifies develop contains the fix and the untracked file is restored on main
+// 4. Verifies no stash is left behind
+func TestFinishAutostashUntracked(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	setupUntrackedOnHotfix(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--autostash-untracked", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish with --autostash-untracked: %v\nOutput: %s", err, output)
+	}
+
+	if branch, _ := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); strings.TrimSpace(branch) != "main" {
+		t.Errorf("Expected main to be checked out, got %s", branch)
+	}
+	if content := testutil.ReadFile(t, dir, "generated.txt"); content != "local build output" {
+		t.Errorf("Expected the untracked file to be restored, got %q", content)
+	}
+	if stashes, _ := testutil.RunGit(t, dir, "stash", "list"); strings.TrimSpace(stashes) != "" {
+		t.Errorf("Expected no stash to be left, got: %s", stashes)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to be updated from main")
+	}
+}
+
+// TestFinishAutostashUntrackedConfig tests that gitflow.finish.autostashUntracked enables the stash.
+// Steps:
+// 1. Tracks generated.txt on develop and leaves an untracked generated.txt on hotfix 1.0.1
+// 2. Sets gitflow.finish.autostashUntracked=true and runs 'git flow hotfix finish 1.0.1'
+// 3. Verifies the finish succeeds and the untracked file is restored
+func TestFinishAutostashUntrackedConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	setupUntrackedOnHotfix(t, dir)
+	testutil.RunGit(t, dir, "config", "gitflow.finish.autostashUntracked", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish with autostash configured: %v\nOutput: %s", err, output)
+	}
+	if content := testutil.ReadFile(t, dir, "generated.txt"); content != "local build output" {
+		t.Errorf("Expected the untracked file to be restored, got %q", content)
+	}
+}
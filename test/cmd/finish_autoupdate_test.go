@@ -1,9 +1,12 @@
 package cmd_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/test/testutil"
 )
 
@@ -253,3 +256,121 @@ func TestFinishNoChildrenWithAutoUpdate(t *testing.T) {
 		t.Error("Staging should not have been updated (autoUpdate=false)")
 	}
 }
+
+// TestFinishChildUpdateOrder tests that gitflow.updateOrder controls the order in which child base branches are updated.
+// Steps:
+// 1. Sets up a test repository with staging and qa as auto-updated children of main next to develop
+// 2. Finishes a hotfix and verifies the children are updated by name: develop, qa, staging
+// 3. Sets gitflow.updateOrder=staging,develop and finishes another hotfix
+// 4. Verifies the listed branches are updated first, in order, followed by qa
+func TestFinishChildUpdateOrder(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	for _, branch := range []string{"staging", "qa"} {
+		testutil.RunGit(t, dir, "branch", branch, "main")
+		testutil.RunGit(t, dir, "config", "gitflow.branch."+branch+".type", "base")
+		testutil.RunGit(t, dir, "config", "gitflow.branch."+branch+".parent", "main")
+		testutil.RunGit(t, dir, "config", "gitflow.branch."+branch+".autoUpdate", "true")
+	}
+
+	finishHotfix := func(name string) string {
+		t.Helper()
+		if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", name); err != nil {
+			t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+		}
+		testutil.WriteFile(t, dir, name+".txt", name)
+		testutil.RunGit(t, dir, "add", name+".txt")
+		testutil.RunGit(t, dir, "commit", "-m", "Fix "+name)
+		output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", name)
+		if err != nil {
+			t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+		}
+		return output
+	}
+	assertOrder := func(output string, expected ...string) {
+		t.Helper()
+		last := -1
+		for _, branch := range expected {
+			index := strings.Index(output, "Updating child base branch '"+branch+"'")
+			if index < 0 || index < last {
+				t.Errorf("Expected the children to be updated in the order %v, got: %s", expected, output)
+				return
+			}
+			last = index
+		}
+	}
+
+	assertOrder(finishHotfix("1.0.1"), "develop", "qa", "staging")
+
+	testutil.RunGit(t, dir, "config", "gitflow.updateOrder", "staging, develop")
+	assertOrder(finishHotfix("1.0.2"), "staging", "develop", "qa")
+}
+
+// TestFinishNoUpdateSkipsChild tests that --no-update skips updating a child base branch for one finish and records it in the journal.
+// Steps:
+// 1. Sets up a test repository with staging as an auto-updated child of main next to develop
+// 2. Finishes a hotfix with --no-update staging
+// 3. Verifies develop is updated from main while staging is unchanged
+// 4. Verifies the skipped update is recorded in the operation journal
+// 5. Verifies --no-update with a branch that is not an auto-updated child fails with exit code 2
+func TestFinishNoUpdateSkipsChild(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "staging", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.staging.type", "base")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.staging.parent", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.staging.autoUpdate", "true")
+	stagingBefore, _ := testutil.RunGit(t, dir, "rev-parse", "staging")
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Fix")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--no-update", "staging")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Skipping child base branch 'staging' (--no-update)") {
+		t.Errorf("Expected the skipped child to be reported, got: %s", output)
+	}
+	if strings.Contains(output, "Updating child base branch 'staging'") {
+		t.Errorf("Expected staging not to be updated, got: %s", output)
+	}
+
+	if stagingAfter, _ := testutil.RunGit(t, dir, "rev-parse", "staging"); stagingAfter != stagingBefore {
+		t.Errorf("Expected staging to stay at %s, got %s", strings.TrimSpace(stagingBefore), strings.TrimSpace(stagingAfter))
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to be updated from main")
+	}
+
+	journal, err := os.ReadFile(filepath.Join(dir, ".git", "gitflow", "journal"))
+	if err != nil {
+		t.Fatalf("Expected the operation journal to be written: %v", err)
+	}
+	if !strings.Contains(string(journal), `"skippedUpdates":[{"branch":"staging","parent":"main"}]`) {
+		t.Errorf("Expected the skipped update in the journal, got: %s", journal)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.2"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.2", "--no-update", "feature")
+	if err == nil {
+		t.Fatalf("Expected --no-update with an unknown child to fail, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d, got: %v\nOutput: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// backMergeDevelop commits to develop and merges it into the feature branch
+func backMergeDevelop(t *testing.T, dir string, feature string, file string) {
+	t.Helper()
+	commitOn(t, dir, "develop", file, file)
+	testutil.RunGit(t, dir, "checkout", feature)
+	if _, err := testutil.RunGit(t, dir, "merge", "--no-ff", "-m", "Merge develop into "+feature, "develop"); err != nil {
+		t.Fatalf("Failed to merge develop into %s: %v", feature, err)
+	}
+}
+
+// TestFinishWarnsAboutBackMerges tests that finish warns about a branch that merged develop in repeatedly.
+// Steps:
+// 1. Sets up a test repository and starts feature 'login' with a commit
+// 2. Merges develop into the feature once and finishes a second feature the same way
+// 3. Verifies a single back-merge is finished without a warning
+// 4. Merges develop into the remaining feature twice and finishes it
+// 5. Verifies finish succeeds and warns with the --rebase and --squash hints
+func TestFinishWarnsAboutBackMerges(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "signup"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/signup", "signup.txt", "signup")
+
+	backMergeDevelop(t, dir, "feature/signup", "one.txt")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "signup")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Warning:") {
+		t.Errorf("Expected no warning for a single back-merge, got: %s", output)
+	}
+
+	backMergeDevelop(t, dir, "feature/login", "two.txt")
+	backMergeDevelop(t, dir, "feature/login", "three.txt")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: 'feature/login' merged 'develop' in 2 times") {
+		t.Errorf("Expected a back-merge warning, got: %s", output)
+	}
+	if !strings.Contains(output, "git flow feature finish --rebase login") || !strings.Contains(output, "git flow feature finish --squash login") {
+		t.Errorf("Expected the --rebase and --squash hints, got: %s", output)
+	}
+}
+
+// TestFinishRefusesBackMerges tests that gitflow.finish.backMerges refuse stops the finish unless the merges are dropped.
+// Steps:
+// 1. Sets up a test repository, starts feature 'login' and sets gitflow.feature.finish.backMerges to refuse
+// 2. Merges develop into the feature twice
+// 3. Verifies finish exits with 6 and leaves develop unchanged
+// 4. Finishes with --squash and verifies develop has no merge commits
+func TestFinishRefusesBackMerges(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.backMerges", "refuse")
+	backMergeDevelop(t, dir, "feature/login", "one.txt")
+	backMergeDevelop(t, dir, "feature/login", "two.txt")
+	developBefore := testutil.RevParse(t, dir, "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "it merged 'develop' in 2 times, at most 1 allowed") {
+		t.Errorf("Expected the back-merge error, got: %s", output)
+	}
+	if testutil.RevParse(t, dir, "develop") != developBefore {
+		t.Error("Expected develop to be unchanged")
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--squash"); err != nil {
+		t.Fatalf("Failed to finish feature with --squash: %v\nOutput: %s", err, output)
+	}
+	if merges, _ := testutil.RunGit(t, dir, "rev-list", "--merges", developBefore+"..develop"); strings.TrimSpace(merges) != "" {
+		t.Errorf("Expected no merge commits on develop, got: %s", merges)
+	}
+}
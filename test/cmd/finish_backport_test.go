@@ -0,0 +1,114 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishBackport tests that finishing a hotfix backports its commits to the configured maintenance branches.
+// Steps:
+// 1. Sets up a repository with support/1.x and support/2.x, where support/2.x changed the file the fix touches
+// 2. Configures gitflow.hotfix.finish.backport with both branches
+// 3. Starts a hotfix, commits a fix and finishes it
+// 4. Verifies backport/support/1.x/1.0.1 holds the cherry-picked fix and the checkout is unchanged
+// 5. Verifies support/2.x is reported as conflicting, gets no backport branch, and leaves no worktree behind
+func TestFinishBackport(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "main", "app.txt", "original")
+	testutil.RunGit(t, dir, "branch", "support/1.x", "main")
+	testutil.RunGit(t, dir, "branch", "support/2.x", "main")
+	commitOn(t, dir, "support/2.x", "app.txt", "rewritten")
+	testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.backport", "support/1.x, support/2.x")
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.txt"), []byte("fixed"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	testutil.RunGit(t, dir, "commit", "-am", "Fix the app")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "✓ support/1.x: backport/support/1.x/1.0.1 (1 commit(s))") {
+		t.Errorf("Expected the backport to support/1.x to succeed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "✗ support/2.x:") || !strings.Contains(output, "conflicts in app.txt") {
+		t.Errorf("Expected the backport to support/2.x to conflict, got:\n%s", output)
+	}
+
+	message, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%B", "backport/support/1.x/1.0.1")
+	if !strings.Contains(message, "Fix the app") || !strings.Contains(message, "(cherry picked from commit") {
+		t.Errorf("Expected the cherry-picked fix on the backport branch, got:\n%s", message)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "support/1.x", "backport/support/1.x/1.0.1"); err != nil {
+		t.Error("Expected the backport branch to start from support/1.x")
+	}
+	if branches, _ := testutil.RunGit(t, dir, "branch", "--list", "backport/support/2.x/*"); branches != "" {
+		t.Errorf("Expected no backport branch for the conflicting target, got:\n%s", branches)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "main" {
+		t.Errorf("Expected main to stay checked out, got %s", current)
+	}
+	if worktrees, _ := testutil.RunGit(t, dir, "worktree", "list", "--porcelain"); strings.Count(worktrees, "worktree ") != 1 {
+		t.Errorf("Expected no temporary worktree to be left, got:\n%s", worktrees)
+	}
+}
+
+// TestFinishBackportFlags tests that --backport replaces the configured targets, --no-backport clears them, and unknown targets are refused.
+// Steps:
+// 1. Sets up a repository with support/1.x and configures a backport to a missing branch
+// 2. Verifies finishing a hotfix is refused before anything is merged
+// 3. Finishes it with --no-backport and verifies no backport branch is created
+// 4. Finishes a second hotfix with --backport support/1.x and verifies its backport branch
+func TestFinishBackportFlags(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "support/1.x", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.hotfix.finish.backport", "support/9.x")
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "hotfix/1.0.1", "fix.txt", "fix")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	assertExitCode(t, err, errors.ExitCodeBranchNotFound, output)
+	if !testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Fatal("Expected the hotfix branch to be left alone")
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--no-backport", "1.0.1"); err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if branches, _ := testutil.RunGit(t, dir, "branch", "--list", "backport/*"); branches != "" {
+		t.Errorf("Expected no backport branch with --no-backport, got:\n%s", branches)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.2"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "hotfix/1.0.2", "other.txt", "other")
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--backport", "support/1.x", "1.0.2"); err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "backport/support/1.x/1.0.2") {
+		t.Error("Expected backport/support/1.x/1.0.2 to be created")
+	}
+}
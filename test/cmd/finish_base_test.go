@@ -0,0 +1,306 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishBaseResolutionDefaultsToConfigured tests that finish uses the configured parent by default.
+// Steps:
+// 1. Sets up a test repository and starts a feature from 'main'
+// 2. Finishes the feature without setting gitflow.finish.baseResolution
+// 3. Verifies the feature was merged into 'develop' and the output names the configured parent
+func TestFinishBaseResolutionDefaultsToConfigured(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "default-base", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "default-base")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "Using base branch 'develop' (configured parent)") {
+		t.Errorf("Expected output to report the configured parent, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "show", "develop:default-base.txt"); err != nil {
+		t.Error("Expected feature to be merged into develop")
+	}
+}
+
+// TestFinishBaseResolutionStored tests that finish honors the stored base when configured.
+// Steps:
+// 1. Sets up a test repository and starts a feature from 'main'
+// 2. Sets gitflow.finish.baseResolution to 'stored'
+// 3. Finishes the feature
+// 4. Verifies the feature was merged into 'main'
+func TestFinishBaseResolutionStored(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "stored-base", "main")
+
+	_, err := testutil.RunGit(t, dir, "config", "gitflow.finish.baseResolution", "stored")
+	if err != nil {
+		t.Fatalf("Failed to set base resolution: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "stored-base")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "Using base branch 'main' (stored base)") {
+		t.Errorf("Expected output to report the stored base, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "show", "main:stored-base.txt"); err != nil {
+		t.Error("Expected feature to be merged into main")
+	}
+}
+
+// TestFinishBaseResolutionPrompt tests that finish asks which base to use when set to 'prompt'.
+// Steps:
+// 1. Sets up a test repository and starts a feature from 'main'
+// 2. Sets gitflow.feature.finish.baseResolution to 'prompt'
+// 3. Finishes the feature answering 's' to the prompt
+// 4. Verifies the feature was merged into 'main'
+func TestFinishBaseResolutionPrompt(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "prompt-base", "main")
+
+	_, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.baseResolution", "prompt")
+	if err != nil {
+		t.Fatalf("Failed to set base resolution: %v", err)
+	}
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "s\n", "feature", "finish", "prompt-base")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "[c]onfigured parent 'develop' or [s]tored base 'main'") {
+		t.Errorf("Expected a base resolution prompt, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "show", "main:prompt-base.txt"); err != nil {
+		t.Error("Expected feature to be merged into main")
+	}
+}
+
+// TestFinishBaseResolutionInvalid tests that an unknown policy is rejected before any changes.
+// Steps:
+// 1. Sets up a test repository and starts a feature from 'main'
+// 2. Sets gitflow.finish.baseResolution to an unsupported value
+// 3. Attempts to finish the feature
+// 4. Verifies the command fails with an invalid input exit code and the branch is kept
+func TestFinishBaseResolutionInvalid(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "invalid-base", "main")
+
+	_, err := testutil.RunGit(t, dir, "config", "gitflow.finish.baseResolution", "newest")
+	if err != nil {
+		t.Fatalf("Failed to set base resolution: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "invalid-base")
+	if err == nil {
+		t.Fatalf("Expected finish to fail with invalid base resolution, got output: %s", output)
+	}
+
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeInvalidInput, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+
+	if !strings.Contains(output, "invalid value 'newest' for gitflow.finish.baseresolution") {
+		t.Errorf("Expected invalid value error, got: %s", output)
+	}
+
+	if !testutil.BranchExists(t, dir, "feature/invalid-base") {
+		t.Error("Expected feature branch to be kept")
+	}
+}
+
+// TestFinishMissingBaseListsCandidates tests that finish stops early when the base branch was renamed.
+// Steps:
+// 1. Sets up a test repository and starts a feature branch
+// 2. Renames 'develop' to 'integration'
+// 3. Attempts to finish the feature
+// 4. Verifies the error names the missing base, lists candidates and suggests --to
+// 5. Verifies no merge state was left behind and the feature branch is kept
+func TestFinishMissingBaseListsCandidates(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "orphaned")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+
+	_, err = testutil.RunGit(t, dir, "branch", "-m", "develop", "integration")
+	if err != nil {
+		t.Fatalf("Failed to rename develop: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "orphaned")
+	if err == nil {
+		t.Fatalf("Expected finish to fail with missing base, got output: %s", output)
+	}
+
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeBranchNotFound) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeBranchNotFound, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+
+	for _, expected := range []string{
+		"base branch 'develop' (configured parent) does not exist",
+		"Candidate branches:",
+		"  main",
+		"  integration",
+		"git flow feature finish orphaned --to <branch>",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	if state, _ := testutil.LoadMergeState(t, dir); state != nil {
+		t.Error("Expected no merge state after pre-flight failure")
+	}
+	if !testutil.BranchExists(t, dir, "feature/orphaned") {
+		t.Error("Expected feature branch to be kept")
+	}
+}
+
+// TestFinishWithToTarget tests that --to finishes into an explicitly chosen branch.
+// Steps:
+// 1. Sets up a test repository and starts a feature branch with a commit
+// 2. Renames 'develop' to 'integration'
+// 3. Finishes the feature with '--to integration'
+// 4. Verifies the feature was merged into 'integration'
+func TestFinishWithToTarget(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "retarget")
+
+	if _, err := testutil.RunGit(t, dir, "branch", "-m", "develop", "integration"); err != nil {
+		t.Fatalf("Failed to rename develop: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "retarget", "--to", "integration")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "Using base branch 'integration' (--to)") {
+		t.Errorf("Expected output to report the --to target, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "show", "integration:retarget.txt"); err != nil {
+		t.Error("Expected feature to be merged into integration")
+	}
+}
+
+// TestBugfixFromReleaseFinishesIntoRelease tests that a bugfix started from a
+// release branch with gitflow.bugfix.allowTopicBase finishes back into it.
+// Steps:
+// 1. Sets up a test repository, starts release 1.2.0 and sets gitflow.bugfix.allowTopicBase
+// 2. Starts bugfix 'fix' from 'release/1.2.0' and commits a file
+// 3. Finishes the bugfix
+// 4. Verifies the fix was merged into the release branch and not into develop
+func TestBugfixFromReleaseFinishesIntoRelease(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.2.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.bugfix.allowTopicBase", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "fix", "release/1.2.0")
+	if err != nil {
+		t.Fatalf("Failed to start bugfix from the release branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Fix release")
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "finish", "fix")
+	if err != nil {
+		t.Fatalf("Failed to finish bugfix: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using base branch 'release/1.2.0' (stored topic base)") {
+		t.Errorf("Expected output to report the stored topic base, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "release/1.2.0:fix.txt"); err != nil {
+		t.Error("Expected bugfix to be merged into the release branch")
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "develop:fix.txt"); err == nil {
+		t.Error("Expected bugfix not to be merged into develop")
+	}
+}
+
+// TestStartFromTopicBaseRequiresConfig tests that a topic branch is only a
+// valid base when gitflow.<type>.allowTopicBase is set, and must exist.
+// Steps:
+// 1. Sets up a test repository and starts release 1.2.0
+// 2. Starts bugfix 'fix' from 'release/1.2.0' and verifies it fails with invalid input
+// 3. Sets gitflow.bugfix.allowTopicBase and starts from 'release/9.9.9'
+// 4. Verifies it fails because the release branch doesn't exist
+func TestStartFromTopicBaseRequiresConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.2.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "fix", "release/1.2.0")
+	exitErr, ok := err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Fatalf("Expected exit code %d, got %v\nOutput: %s", errors.ExitCodeInvalidInput, err, output)
+	}
+	if !strings.Contains(output, "gitflow.bugfix.allowTopicBase") {
+		t.Errorf("Expected the error to name the setting, got: %s", output)
+	}
+
+	testutil.RunGit(t, dir, "config", "gitflow.bugfix.allowTopicBase", "true")
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "start", "fix", "release/9.9.9")
+	exitErr, ok = err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != int(errors.ExitCodeBranchNotFound) {
+		t.Fatalf("Expected exit code %d, got %v\nOutput: %s", errors.ExitCodeBranchNotFound, err, output)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishBatch tests that --batch finishes several hotfixes with one tag each
+// and updates develop only once, after the last one.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts hotfix branches 1.0.1 and 1.0.2 from main, each with its own commit
+// 3. Runs 'git flow hotfix finish --batch 1.0.1 1.0.2'
+// 4. Verifies both tags exist and both hotfix branches are deleted
+// 5. Verifies develop was updated once and contains both fixes
+func TestFinishBatch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.1", "main")
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.2", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--batch", "1.0.1", "1.0.2")
+	if err != nil {
+		t.Fatalf("Failed to finish the batch: %v\nOutput: %s", err, output)
+	}
+
+	tags, _ := testutil.RunGit(t, dir, "tag", "--list")
+	for _, tag := range []string{"1.0.1", "1.0.2"} {
+		if !strings.Contains(tags, tag) {
+			t.Errorf("Expected tag '%s', got tags: %s", tag, tags)
+		}
+		if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "hotfix/"+tag); err == nil {
+			t.Errorf("Expected hotfix/%s to be deleted", tag)
+		}
+	}
+
+	if count := strings.Count(output, "Updating child base branch 'develop'"); count != 1 {
+		t.Errorf("Expected develop to be updated once, got %d updates\nOutput: %s", count, output)
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	for _, file := range []string{"1.0.1.txt", "1.0.2.txt"} {
+		if !testutil.FileExists(t, dir, file) {
+			t.Errorf("Expected develop to contain %s", file)
+		}
+	}
+}
+
+// TestFinishBatchContinueAfterConflict tests that a batch stopped on a conflict
+// finishes the rest of the batch after --continue.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts hotfixes 1.0.1 and 1.0.2 changing the same file, and hotfix 1.0.3
+// 3. Runs 'git flow hotfix finish --batch 1.0.1 1.0.2 1.0.3' and verifies it stops on 1.0.2
+// 4. Resolves the conflict and runs 'git flow hotfix finish --continue'
+// 5. Verifies all three tags exist and develop contains the fix of 1.0.3
+func TestFinishBatchContinueAfterConflict(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.1", "main")
+	testutil.CommitFile(t, dir, "shared.txt", "fix a", "Fix "+"1.0.1")
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.2", "main")
+	testutil.CommitFile(t, dir, "shared.txt", "fix b", "Fix "+"1.0.2")
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.3", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--batch", "1.0.1", "1.0.2", "1.0.3")
+	if err == nil {
+		t.Fatalf("Expected the batch to stop on a conflict\nOutput: %s", output)
+	}
+	if strings.Contains(output, "Updating child base branch") {
+		t.Errorf("Expected no child update before the last branch\nOutput: %s", output)
+	}
+
+	testutil.WriteFile(t, dir, "shared.txt", "fix a and b")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue the batch: %v\nOutput: %s", err, output)
+	}
+
+	tags, _ := testutil.RunGit(t, dir, "tag", "--list")
+	for _, tag := range []string{"1.0.1", "1.0.2", "1.0.3"} {
+		if !strings.Contains(tags, tag) {
+			t.Errorf("Expected tag '%s', got tags: %s", tag, tags)
+		}
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if !testutil.FileExists(t, dir, "1.0.3.txt") {
+		t.Error("Expected develop to contain the fix of the last hotfix")
+	}
+}
+
+// TestFinishBatchValidatesBranchesFirst tests that a batch with a missing
+// branch or --tagname fails before any branch is finished.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts hotfix 1.0.1
+// 3. Runs 'git flow hotfix finish --batch 1.0.1 missing' and verifies it fails
+// 4. Runs 'git flow hotfix finish --batch 1.0.1 1.0.1 --tagname v1' and verifies it fails
+// 5. Verifies hotfix/1.0.1 still exists and no tag was created
+func TestFinishBatchValidatesBranchesFirst(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.StartBranchWithCommit(t, dir, "hotfix", "1.0.1", "main")
+
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--batch", "1.0.1", "missing"); err == nil {
+		t.Errorf("Expected a batch with a missing branch to fail\nOutput: %s", output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--batch", "1.0.1", "1.0.1", "--tagname", "v1")
+	if err == nil || !strings.Contains(output, "--tagname") {
+		t.Errorf("Expected --tagname to be rejected with --batch\nOutput: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "hotfix/1.0.1"); err != nil {
+		t.Error("Expected hotfix/1.0.1 to still exist")
+	}
+	if tags, _ := testutil.RunGit(t, dir, "tag", "--list"); strings.TrimSpace(tags) != "" {
+		t.Errorf("Expected no tags, got: %s", tags)
+	}
+}
@@ -0,0 +1,222 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishPrintsChildStrategy tests that finish reports the strategy used for each child branch.
+// Steps:
+// 1. Sets up a repository with git-flow defaults
+// 2. Starts a release branch and adds a commit
+// 3. Finishes the release
+// 4. Verifies the output names the downstream strategy used to update develop
+func TestFinishPrintsChildStrategy(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v", err)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, _ = testutil.RunGit(t, dir, "add", "release.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--notag", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Found child base branch 'develop' with auto-update enabled (downstream strategy: merge)") {
+		t.Errorf("Expected the downstream strategy of develop in the output, got: %s", output)
+	}
+	if !strings.Contains(output, "Updating child base branch 'develop' from 'main' (strategy: merge)") {
+		t.Errorf("Expected the strategy in the child update message, got: %s", output)
+	}
+}
+
+// TestFinishChildStrategyOverride tests that --child-strategy overrides the downstream
+// strategy of a child branch and is kept through --continue.
+// Steps:
+// 1. Sets up a repository with a change to the same file on develop and on a hotfix
+// 2. Finishes the hotfix with --child-strategy develop=squash
+// 3. Verifies the output reports the override and the develop update stops on a conflict
+// 4. Verifies the override is stored in the merge state
+// 5. Resolves the conflict and continues without the flag
+// 6. Verifies develop was updated with a squash commit rather than a merge commit
+func TestFinishChildStrategyOverride(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "develop version")
+	_, _ = testutil.RunGit(t, dir, "add", "shared.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Develop change")
+
+	if _, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v", err)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "hotfix version")
+	_, _ = testutil.RunGit(t, dir, "add", "shared.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Hotfix change")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--notag", "--child-strategy", "develop=squash", "1.0.1")
+	if err == nil {
+		t.Fatalf("Expected the update of develop to stop on a conflict, got: %s", output)
+	}
+	if !strings.Contains(output, "Found child base branch 'develop' with auto-update enabled (--child-strategy: squash)") {
+		t.Errorf("Expected the override in the output, got: %s", output)
+	}
+
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil || state == nil {
+		t.Fatalf("Expected a merge state: %v", err)
+	}
+	if state.ChildStrategies["develop"] != "squash" {
+		t.Errorf("Expected the override to be stored in the merge state, got '%s'", state.ChildStrategies["develop"])
+	}
+
+	testutil.WriteFile(t, dir, "shared.txt", "resolved version")
+	_, _ = testutil.RunGit(t, dir, "add", "shared.txt")
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+
+	parents, err := testutil.RunGit(t, dir, "rev-list", "--parents", "-n", "1", "develop")
+	if err != nil {
+		t.Fatalf("Failed to read develop: %v", err)
+	}
+	if fields := strings.Fields(parents); len(fields) != 2 {
+		t.Errorf("Expected develop to be updated with a squash commit, got parents: %s", parents)
+	}
+}
+
+// TestFinishChildStrategyValidation tests that invalid --child-strategy values are
+// rejected before anything is merged.
+// Steps:
+// 1. Sets up a feature branch with a commit
+// 2. Finishes with a malformed override, an unknown strategy and a branch that is not a child
+// 3. Verifies each attempt exits with status 2 and the feature branch still exists
+func TestFinishChildStrategyValidation(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "child"); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, _ = testutil.RunGit(t, dir, "add", "feature.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+
+	for _, spec := range []string{"develop", "develop=octopus", "main=merge"} {
+		output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--child-strategy", spec, "child")
+		exitErr, ok := err.(*testutil.ExitError)
+		if !ok || exitErr.ExitCode != 2 {
+			t.Errorf("Expected exit status 2 for --child-strategy %s, got %v\nOutput: %s", spec, err, output)
+		}
+		if !strings.Contains(output, "--child-strategy") {
+			t.Errorf("Expected the error to name --child-strategy for %s, got: %s", spec, output)
+		}
+	}
+	if !testutil.BranchExists(t, dir, "feature/child") {
+		t.Error("Expected feature/child to still exist")
+	}
+}
+
+// TestFinishRebasesDevelopOntoMain tests that a release finish rebases develop
+// onto main when develop's downstream strategy is rebase, keeping it linear.
+// Steps:
+// 1. Sets up a repository with git-flow defaults and develop's downstreamStrategy=rebase
+// 2. Starts a release branch with a commit, then adds a commit to develop
+// 3. Finishes the release
+// 4. Verifies develop contains main and has no merge commits on top of it
+func TestFinishRebasesDevelopOntoMain(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.branch.develop.downstreamStrategy", "rebase")
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Release commit")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop.txt", "develop")
+	testutil.RunGit(t, dir, "add", "develop.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop commit")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to contain main")
+	}
+	merges, _ := testutil.RunGit(t, dir, "rev-list", "--merges", "main..develop")
+	if strings.TrimSpace(merges) != "" {
+		t.Errorf("Expected no merge commits on develop after main, got: %s", merges)
+	}
+}
+
+// TestFinishRefusesToRebasePublishedDevelop tests that finish refuses to rebase
+// a published develop unless --force is given, and force-pushes it then.
+// Steps:
+// 1. Sets up a repository with a remote and develop's downstreamStrategy=rebase
+// 2. Starts a release branch with a commit and adds a pushed commit to develop
+// 3. Finishes the release and verifies it fails with exit code 6 before merging
+// 4. Finishes the release with --force --push
+// 5. Verifies the remote develop matches the rebased local develop
+func TestFinishRefusesToRebasePublishedDevelop(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.branch.develop.downstreamStrategy", "rebase")
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Release commit")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop.txt", "develop")
+	testutil.RunGit(t, dir, "add", "develop.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop commit")
+	testutil.RunGit(t, dir, "push", "origin", "develop")
+	testutil.RunGit(t, dir, "checkout", "release/1.0.0")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "is published on 'origin'") {
+		t.Errorf("Expected the error to explain the published branch, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "show", "main:release.txt"); err == nil {
+		t.Error("Expected nothing to be merged before the check")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--force", "--push")
+	if err != nil {
+		t.Fatalf("Failed to finish release with --force: %v\nOutput: %s", err, output)
+	}
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	remote, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	if strings.TrimSpace(local) != strings.TrimSpace(remote) {
+		t.Errorf("Expected the remote develop %s to match the local develop %s", remote, local)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupVersionConflict initializes git-flow and starts hotfix/1.0.1 so that
+// develop and the hotfix both change version.txt, and optionally notes.txt
+func setupVersionConflict(t *testing.T, dir string, withNotes bool) {
+	t.Helper()
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "1.1.0-dev\n")
+	if withNotes {
+		testutil.WriteFile(t, dir, "notes.txt", "develop notes\n")
+	}
+	_, _ = testutil.RunGit(t, dir, "add", ".")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Bump develop version")
+
+	if _, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v", err)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "1.0.1\n")
+	if withNotes {
+		testutil.WriteFile(t, dir, "notes.txt", "hotfix notes\n")
+	}
+	_, _ = testutil.RunGit(t, dir, "add", ".")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Bump hotfix version")
+}
+
+// TestFinishChildConflictResolution tests that conflictResolution resolves the
+// recurring conflict in a version file when develop is updated from main.
+// Steps:
+// 1. Changes version.txt on develop and on a hotfix branch
+// 2. Sets gitflow.branch.develop.conflictResolution=ours for version.txt
+// 3. Finishes the hotfix
+// 4. Verifies the finish completes and develop keeps its own version
+func TestFinishChildConflictResolution(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupVersionConflict(t, dir, false)
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolution", "ours")
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolutionPaths", "version.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--notag", "1.0.1")
+	if err != nil {
+		t.Fatalf("Expected finish to resolve the version conflict: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Resolved conflict in 'version.txt' in favor of 'develop'") {
+		t.Errorf("Expected the resolution to be reported, got: %s", output)
+	}
+
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+	if content := testutil.ReadFile(t, dir, "version.txt"); content != "1.1.0-dev\n" {
+		t.Errorf("Expected develop to keep its version, got %q", content)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected main to be merged into develop")
+	}
+	if testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix/1.0.1 to be deleted")
+	}
+}
+
+// TestFinishChildConflictResolutionRebase tests that the sides keep their meaning
+// when the child branch is rebased.
+// Steps:
+// 1. Changes version.txt on develop and on a hotfix branch
+// 2. Sets develop to rebase and conflictResolution=theirs without path patterns
+// 3. Finishes the hotfix
+// 4. Verifies the finish completes and develop has the version from main
+func TestFinishChildConflictResolutionRebase(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupVersionConflict(t, dir, false)
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.downstreamStrategy", "rebase")
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolution", "theirs")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--notag", "1.0.1")
+	if err != nil {
+		t.Fatalf("Expected finish to resolve the version conflict: %v\nOutput: %s", err, output)
+	}
+
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+	if content := testutil.ReadFile(t, dir, "version.txt"); content != "1.0.1\n" {
+		t.Errorf("Expected develop to take the version from main, got %q", content)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to be rebased onto main")
+	}
+}
+
+// TestFinishChildConflictResolutionLimitedToPaths tests that conflicts outside the
+// configured paths still stop the finish.
+// Steps:
+// 1. Changes version.txt and notes.txt on develop and on a hotfix branch
+// 2. Sets conflictResolution=theirs for version.txt only
+// 3. Finishes the hotfix and verifies it stops on the conflict in notes.txt
+// 4. Verifies version.txt is already resolved and notes.txt is not
+// 5. Resolves notes.txt, continues and verifies the finish completes
+func TestFinishChildConflictResolutionLimitedToPaths(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupVersionConflict(t, dir, true)
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolution", "theirs")
+	_, _ = testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolutionPaths", "*.md, version.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "--notag", "1.0.1")
+	if err == nil {
+		t.Fatalf("Expected finish to stop on the conflict in notes.txt, got: %s", output)
+	}
+
+	unmerged, _ := testutil.RunGit(t, dir, "diff", "--name-only", "--diff-filter=U")
+	if strings.TrimSpace(unmerged) != "notes.txt" {
+		t.Errorf("Expected only notes.txt to be unmerged, got %q", unmerged)
+	}
+	if content := testutil.ReadFile(t, dir, "version.txt"); content != "1.0.1\n" {
+		t.Errorf("Expected version.txt to be resolved to main's version, got %q", content)
+	}
+
+	testutil.WriteFile(t, dir, "notes.txt", "merged notes\n")
+	_, _ = testutil.RunGit(t, dir, "add", "notes.txt")
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "hotfix/1.0.1") {
+		t.Error("Expected hotfix/1.0.1 to be deleted")
+	}
+}
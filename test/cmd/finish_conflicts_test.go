@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/test/testutil"
 )
 
@@ -802,3 +803,231 @@ func TestFinishWithConsecutiveConflicts(t *testing.T) {
 		t.Errorf("Expected develop branch to have both release and develop-specific content, got: %s", developContent)
 	}
 }
+
+// startConflictingFeature starts a feature changing conflict.txt and then
+// changes the same lines on develop, so that finishing it conflicts
+func startConflictingFeature(t *testing.T, dir, name string) {
+	t.Helper()
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+		t.Fatalf("Failed to create feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Feature version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Feature changes")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "conflict.txt", "Develop version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop changes")
+	testutil.RunGit(t, dir, "checkout", "feature/"+name)
+}
+
+// TestFinishContinueAfterManualCommit tests that --continue picks up a conflicted merge committed with git directly.
+// Steps:
+// 1. Sets up a feature that conflicts with develop and runs 'git flow feature finish'
+// 2. Resolves the conflict and commits it with 'git commit --no-edit'
+// 3. Runs 'git flow feature finish --continue' and verifies it finishes the feature
+// 4. Verifies develop has a single merge commit for the feature
+func TestFinishContinueAfterManualCommit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startConflictingFeature(t, dir, "manual")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "manual"); err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Resolved version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	if output, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit the merge: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue after the manual commit: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "already committed") {
+		t.Errorf("Expected the manual commit to be recognized\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/manual") {
+		t.Error("Expected feature/manual to be deleted")
+	}
+	merges, _ := testutil.RunGit(t, dir, "rev-list", "--merges", "--count", "develop")
+	if strings.TrimSpace(merges) != "1" {
+		t.Errorf("Expected one merge commit on develop, got %s", merges)
+	}
+}
+
+// TestFinishContinueAfterManualSquashCommit tests that --continue picks up a conflicted squash committed with git directly.
+// Steps:
+// 1. Sets up a feature that conflicts with develop and runs 'git flow feature finish --squash'
+// 2. Resolves the conflict and commits it with 'git commit'
+// 3. Runs 'git flow feature finish --continue' and verifies it finishes the feature
+// 4. Verifies develop contains the resolved content
+func TestFinishContinueAfterManualSquashCommit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startConflictingFeature(t, dir, "squashed")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--squash", "squashed"); err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Resolved version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	if output, err := testutil.RunGit(t, dir, "commit", "-m", "Squashed feature"); err != nil {
+		t.Fatalf("Failed to commit the squash: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue after the manual commit: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/squashed") {
+		t.Error("Expected feature/squashed to be deleted")
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if content := testutil.ReadFile(t, dir, "conflict.txt"); content != "Resolved version" {
+		t.Errorf("Expected the resolved content on develop, got %q", content)
+	}
+}
+
+// TestFinishContinueAfterManualChildMerge tests that --continue picks up a conflicted child update committed with git directly.
+// Steps:
+// 1. Sets up a test repository, starts a release and changes the same file on it and on develop
+// 2. Runs 'git flow release finish' and verifies it stops on the update of develop
+// 3. Resolves the conflict and commits it with 'git commit --no-edit'
+// 4. Runs 'git flow release finish --continue' and verifies the release is finished
+func TestFinishContinueAfterManualChildMerge(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "1.0")
+	testutil.RunGit(t, dir, "add", "version.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Bump version")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "version.txt", "2.0-dev")
+	testutil.RunGit(t, dir, "add", "version.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Start 2.0")
+	testutil.RunGit(t, dir, "checkout", "release/1.0")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0")
+	if err == nil {
+		t.Fatalf("Expected the update of develop to conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "version.txt", "2.0-dev")
+	testutil.RunGit(t, dir, "add", "version.txt")
+	if output, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit the update: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue after the manual commit: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "The update of 'develop' was already committed") {
+		t.Errorf("Expected the manual commit to be recognized\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "release/1.0") {
+		t.Error("Expected release/1.0 to be deleted")
+	}
+}
+
+// TestFinishContinueAfterManualAbort tests that --continue explains a merge aborted with git directly.
+// Steps:
+// 1. Sets up a feature that conflicts with develop and runs 'git flow feature finish'
+// 2. Aborts the merge with 'git merge --abort'
+// 3. Runs 'git flow feature finish --continue' and verifies it fails pointing to --abort
+func TestFinishContinueAfterManualAbort(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startConflictingFeature(t, dir, "aborted")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "aborted"); err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.RunGit(t, dir, "merge", "--abort")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue")
+	if err == nil {
+		t.Fatalf("Expected --continue to fail after the merge was aborted\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "finish --abort") {
+		t.Errorf("Expected the error to point to --abort\nOutput: %s", output)
+	}
+}
+
+// TestFinishContinueSwitchesBackToMergeBranch tests that --continue returns to the branch the finish stopped on.
+// Steps:
+// 1. Sets up a feature that conflicts with develop and runs 'git flow feature finish'
+// 2. Resolves and commits the conflict, then checks out main
+// 3. Runs 'git flow feature finish --continue'
+// 4. Verifies it switches back to develop and finishes the feature without touching main
+func TestFinishContinueSwitchesBackToMergeBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startConflictingFeature(t, dir, "switched")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "switched"); err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Resolved version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "--no-edit")
+	testutil.RunGit(t, dir, "checkout", "main")
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue")
+	if err != nil {
+		t.Fatalf("Failed to continue: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Switched back to 'develop'") {
+		t.Errorf("Expected to switch back to develop\nOutput: %s", output)
+	}
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+	if testutil.BranchExists(t, dir, "feature/switched") {
+		t.Error("Expected feature/switched to be deleted")
+	}
+}
+
+// TestFinishContinueRefusesDirtyOtherBranch tests that --continue explains how to get back when switching would lose changes.
+// Steps:
+// 1. Sets up a feature that conflicts with develop and runs 'git flow feature finish'
+// 2. Resolves and commits the conflict, checks out main and changes a tracked file
+// 3. Runs 'git flow feature finish --continue'
+// 4. Verifies it fails with exit code 6, names develop and keeps the change and the merge state
+func TestFinishContinueRefusesDirtyOtherBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startConflictingFeature(t, dir, "dirty")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "dirty"); err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Resolved version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "--no-edit")
+	testutil.RunGit(t, dir, "checkout", "main")
+	testutil.WriteFile(t, dir, "README.md", "Work in progress")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "git checkout develop") {
+		t.Errorf("Expected the error to explain how to switch back\nOutput: %s", output)
+	}
+	if content := testutil.ReadFile(t, dir, "README.md"); content != "Work in progress" {
+		t.Error("Expected the change on main to be kept")
+	}
+	if state, err := testutil.LoadMergeState(t, dir); err != nil || state == nil {
+		t.Error("Expected the merge state to be kept")
+	}
+}
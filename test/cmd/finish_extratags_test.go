@@ -0,0 +1,227 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// finishRelease starts a release, commits a file to it and finishes it with the given flags
+func finishRelease(t *testing.T, dir string, version string, flags ...string) string {
+	t.Helper()
+	output, err := testutil.RunGitFlow(t, dir, "release", "start", version)
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, version+".txt", version)
+	testutil.RunGit(t, dir, "add", version+".txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Release "+version)
+
+	args := append([]string{"release", "finish"}, flags...)
+	args = append(args, version)
+	output, err = testutil.RunGitFlow(t, dir, args...)
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	return output
+}
+
+// TestFinishReleaseMovesExtraTags tests the configured extra tags on release finish.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a moving 'latest' tag and a templated tag on develop
+// 3. Finishes release 1.0.0
+// 4. Verifies 'latest' points at main and 'deployed/1.0.0' at develop
+// 5. Finishes release 1.1.0
+// 6. Verifies 'latest' moved to the new main commit
+func TestFinishReleaseMovesExtraTags(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	testutil.RunGit(t, dir, "config", "--add", "gitflow.release.finish.extra-tag", "latest")
+	testutil.RunGit(t, dir, "config", "--add", "gitflow.release.finish.extra-tag", "deployed/%v:develop")
+
+	output = finishRelease(t, dir, "1.0.0")
+	if !strings.Contains(output, "Moved tag 'latest' to 'main'") {
+		t.Errorf("Expected output to report the moved tag, got: %s", output)
+	}
+	if testutil.RevParse(t, dir, "latest") != testutil.RevParse(t, dir, "main") {
+		t.Error("Expected 'latest' to point at main")
+	}
+	if testutil.RevParse(t, dir, "deployed/1.0.0") != testutil.RevParse(t, dir, "develop") {
+		t.Error("Expected 'deployed/1.0.0' to point at develop")
+	}
+	if testutil.RevParse(t, dir, "1.0.0") != testutil.RevParse(t, dir, "main") {
+		t.Error("Expected the release tag to still be created on main")
+	}
+
+	finishRelease(t, dir, "1.1.0")
+	if testutil.RevParse(t, dir, "latest") != testutil.RevParse(t, dir, "main") {
+		t.Error("Expected 'latest' to move to the new main commit")
+	}
+	if testutil.RevParse(t, dir, "latest") == testutil.RevParse(t, dir, "1.0.0") {
+		t.Error("Expected 'latest' to no longer point at release 1.0.0")
+	}
+}
+
+// TestFinishExtraTagFlags tests the --extra-tag and --no-extra-tags flags.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Configures a 'latest' extra tag
+// 3. Finishes a release with --no-extra-tags and --extra-tag using %t
+// 4. Verifies only the tag given on the command line was created
+func TestFinishExtraTagFlags(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.release.finish.extra-tag", "latest")
+
+	finishRelease(t, dir, "1.0.0", "--no-extra-tags")
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "refs/tags/latest"); err == nil {
+		t.Error("Expected --no-extra-tags to skip the configured tag")
+	}
+
+	finishRelease(t, dir, "2.0.0", "--extra-tag", "%t-stable:develop")
+	if testutil.RevParse(t, dir, "2.0.0-stable") != testutil.RevParse(t, dir, "develop") {
+		t.Error("Expected '2.0.0-stable' to point at develop")
+	}
+	if testutil.RevParse(t, dir, "latest") != testutil.RevParse(t, dir, "main") {
+		t.Error("Expected the configured 'latest' tag alongside the command-line tag")
+	}
+}
+
+// TestFinishExtraTagInvalidTarget tests that a bad extra tag stops the finish before any change.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a commit
+// 3. Finishes it with an extra tag targeting a missing branch
+// 4. Verifies the command fails with an input error
+// 5. Verifies the release branch still exists and main is unchanged
+func TestFinishExtraTagInvalidTarget(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	mainBefore := testutil.RevParse(t, dir, "main")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Release")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--extra-tag", "latest:production", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected finish to fail, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != 2 {
+		t.Errorf("Expected exit code 2, got %d", exitErr.ExitCode)
+	}
+	if !strings.Contains(output, "branch 'production', which does not exist") {
+		t.Errorf("Expected error about the missing target, got: %s", output)
+	}
+
+	if !testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be kept")
+	}
+	if testutil.RevParse(t, dir, "main") != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+}
+
+// TestFinishReleasePush tests pushing branches and tags with --push.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Finishes a release with --push and a 'latest' extra tag
+// 3. Verifies the remote has main, develop, the release tag and 'latest'
+// 4. Finishes another release with --push
+// 5. Verifies 'latest' moved on the remote
+func TestFinishReleasePush(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.release.finish.extra-tag", "latest")
+
+	output := finishRelease(t, dir, "1.0.0", "--push")
+	if !strings.Contains(output, "Pushed to 'origin'") {
+		t.Errorf("Expected output to report the push, got: %s", output)
+	}
+	for _, ref := range []string{"main", "develop", "1.0.0", "latest"} {
+		if testutil.RevParse(t, remoteDir, ref) != testutil.RevParse(t, dir, ref) {
+			t.Errorf("Expected remote '%s' to match the local one", ref)
+		}
+	}
+
+	finishRelease(t, dir, "1.1.0", "--push")
+	if testutil.RevParse(t, remoteDir, "latest") != testutil.RevParse(t, dir, "main") {
+		t.Error("Expected 'latest' to move on the remote")
+	}
+}
+
+// TestFinishWithoutPushLeavesRemote tests that finish doesn't push by default.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Finishes a release without --push
+// 3. Verifies the release tag was not pushed
+func TestFinishWithoutPushLeavesRemote(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	finishRelease(t, dir, "1.0.0")
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/tags/1.0.0"); err == nil {
+		t.Error("Expected the release tag not to be pushed without --push")
+	}
+}
+
+// TestFinishReleasePushTag tests that gitflow.<type>.finish.pushtag pushes only the tag.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Configures gitflow.release.finish.pushtag=true
+// 3. Finishes a release
+// 4. Verifies the release tag is on the remote but main and develop are not updated
+func TestFinishReleasePushTag(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.release.finish.pushtag", "true")
+	remoteMain := testutil.RevParse(t, remoteDir, "main")
+	remoteDevelop := testutil.RevParse(t, remoteDir, "develop")
+
+	output := finishRelease(t, dir, "1.0.0")
+	if !strings.Contains(output, "Pushed tag '1.0.0' to 'origin'") {
+		t.Errorf("Expected output to report the tag push, got: %s", output)
+	}
+	if testutil.RevParse(t, remoteDir, "1.0.0") != testutil.RevParse(t, dir, "1.0.0") {
+		t.Error("Expected the release tag to be pushed")
+	}
+	if testutil.RevParse(t, remoteDir, "main") != remoteMain {
+		t.Error("Expected remote main to be left alone")
+	}
+	if testutil.RevParse(t, remoteDir, "develop") != remoteDevelop {
+		t.Error("Expected remote develop to be left alone")
+	}
+}
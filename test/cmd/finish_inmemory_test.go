@@ -0,0 +1,115 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishUpdatesChildrenInMemory tests that the experimental in-memory-merge feature updates child base branches without checking them out.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and enables gitflow.experimental.in-memory-merge
+// 2. Commits on develop and on a hotfix branch
+// 3. Finishes the hotfix
+// 4. Verifies develop was updated with a merge commit of main without being checked out
+func TestFinishUpdatesChildrenInMemory(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.experimental.in-memory-merge", "true")
+	commitOn(t, dir, "develop", "develop.txt", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "hotfix/1.0.1", "fix.txt", "fix")
+
+	before, _ := testutil.RunGit(t, dir, "reflog", "--format=%gs")
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using in-memory merge strategy for 'develop'") {
+		t.Errorf("Expected develop to be merged in memory, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected main to be merged into develop")
+	}
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Merge branch 'main' into develop" {
+		t.Errorf("Expected a merge commit on develop, got '%s'", strings.TrimSpace(subject))
+	}
+	// The reflog of HEAD lists the newest entries first
+	after, _ := testutil.RunGit(t, dir, "reflog", "--format=%gs")
+	if finish := strings.TrimSuffix(after, before); strings.Contains(finish, "to develop") {
+		t.Errorf("Expected develop not to be checked out, got reflog:\n%s", finish)
+	}
+}
+
+// TestFinishInMemoryMergeFallsBackOnConflicts tests that a child update with conflicts is merged in the working tree.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and enables gitflow.experimental.in-memory-merge
+// 2. Changes the same file on develop and on a hotfix branch
+// 3. Finishes the hotfix and verifies it stops on the conflict with develop checked out
+func TestFinishInMemoryMergeFallsBackOnConflicts(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.experimental.in-memory-merge", "true")
+	commitOn(t, dir, "develop", "shared.txt", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "hotfix/1.0.1", "shared.txt", "fix")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "In-memory merge has conflicts, checking out 'develop'") {
+		t.Errorf("Expected the in-memory merge to fall back, got: %s", output)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "develop" {
+		t.Errorf("Expected develop to be checked out for the conflict, got '%s'", current)
+	}
+}
+
+// TestFinishInMemoryMergeSkippedWithConflictResolution tests that a child with a conflictResolution preference is merged in the working tree.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and enables gitflow.experimental.in-memory-merge
+// 2. Sets gitflow.branch.develop.conflictResolution to theirs
+// 3. Changes the same file on develop and on a hotfix branch
+// 4. Finishes the hotfix and verifies develop was not merged in memory and took the hotfix's change
+func TestFinishInMemoryMergeSkippedWithConflictResolution(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.experimental.in-memory-merge", "true")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.develop.conflictResolution", "theirs")
+	commitOn(t, dir, "develop", "shared.txt", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "hotfix/1.0.1", "shared.txt", "fix")
+
+	output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1")
+	if err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "in-memory merge") || strings.Contains(output, "In-memory merge") {
+		t.Errorf("Expected develop not to be merged in memory, got: %s", output)
+	}
+	if content, _ := testutil.RunGit(t, dir, "show", "develop:shared.txt"); strings.TrimSpace(content) != "fix" {
+		t.Errorf("Expected the conflict in shared.txt to be resolved with the hotfix's change, got '%s'", content)
+	}
+}
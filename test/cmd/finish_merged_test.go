@@ -0,0 +1,151 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// mergeLikePullRequest merges branch into target with a merge commit, as a
+// pull request would, and returns the new tip of target.
+func mergeLikePullRequest(t *testing.T, dir string, branch string, target string) string {
+	t.Helper()
+	testutil.RunGit(t, dir, "checkout", target)
+	if _, err := testutil.RunGit(t, dir, "merge", "--no-ff", "-m", "Merge pull request #1", branch); err != nil {
+		t.Fatalf("Failed to merge %s into %s: %v", branch, target, err)
+	}
+	tip, _ := testutil.RunGit(t, dir, "rev-parse", target)
+	testutil.RunGit(t, dir, "checkout", branch)
+	return strings.TrimSpace(tip)
+}
+
+// TestFinishAlreadyMergedPrompt tests that finish offers to skip the merge of a branch merged through a pull request.
+// Steps:
+// 1. Sets up a test repository and starts a feature with a commit
+// 2. Merges the feature into develop with a merge commit, like a pull request
+// 3. Finishes the feature and answers 'y' to the prompt
+// 4. Verifies the merge was skipped and develop was not changed
+// 5. Verifies the feature branch and its base config were removed
+func TestFinishAlreadyMergedPrompt(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "merged")
+	developTip := mergeLikePullRequest(t, dir, "feature/merged", "develop")
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "y\n", "feature", "finish", "merged")
+	if err != nil {
+		t.Fatalf("Failed to finish merged feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Skip the merge and only tag and clean up?") {
+		t.Errorf("Expected to be asked about skipping the merge, got: %s", output)
+	}
+	if !strings.Contains(output, "skipping the merge") {
+		t.Errorf("Expected the merge to be skipped, got: %s", output)
+	}
+
+	tip, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if strings.TrimSpace(tip) != developTip {
+		t.Errorf("Expected develop to stay at %s, got %s", developTip, tip)
+	}
+	if testutil.BranchExists(t, dir, "feature/merged") {
+		t.Error("Expected the feature branch to be deleted")
+	}
+	if base, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature/merged.base"); err == nil {
+		t.Errorf("Expected the base config to be removed, got %q", base)
+	}
+}
+
+// TestFinishAlreadyMergedDeclined tests that declining the prompt finishes the merged branch as usual.
+// Steps:
+// 1. Sets up a test repository and starts a feature with a commit
+// 2. Merges the feature into develop with a merge commit, like a pull request
+// 3. Finishes the feature with --no-ff and answers the prompt with the default
+// 4. Verifies the regular merge ran instead of the fast path
+func TestFinishAlreadyMergedDeclined(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "declined")
+	mergeLikePullRequest(t, dir, "feature/declined", "develop")
+
+	output, _ := testutil.RunGitFlowWithInput(t, dir, "\n", "feature", "finish", "declined", "--no-ff")
+	if !strings.Contains(output, "Skip the merge and only tag and clean up?") {
+		t.Errorf("Expected to be asked about skipping the merge, got: %s", output)
+	}
+	if strings.Contains(output, "skipping the merge") {
+		t.Errorf("Expected the merge not to be skipped, got: %s", output)
+	}
+	if !strings.Contains(output, "Merging using strategy") {
+		t.Errorf("Expected the regular merge to run, got: %s", output)
+	}
+}
+
+// TestFinishIfMergedRebasedRelease tests that --if-merged finishes a release whose commits were rebased onto main elsewhere.
+// Steps:
+// 1. Sets up a test repository and starts release 1.0.0 with a commit
+// 2. Cherry-picks the release commit onto main, like a rebase merge
+// 3. Finishes the release with --if-merged
+// 4. Verifies no prompt was shown and the merge was skipped
+// 5. Verifies the tag points at main, develop was updated and the release branch was deleted
+func TestFinishIfMergedRebasedRelease(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0.0")
+	testutil.RunGit(t, dir, "checkout", "main")
+	if _, err := testutil.RunGit(t, dir, "cherry-pick", "release/1.0.0"); err != nil {
+		t.Fatalf("Failed to cherry-pick the release commit: %v", err)
+	}
+	mainTip, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--if-merged")
+	if err != nil {
+		t.Fatalf("Failed to finish release with --if-merged: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "[y/N]") {
+		t.Errorf("Expected no prompt with --if-merged, got: %s", output)
+	}
+	if !strings.Contains(output, "skipping the merge") {
+		t.Errorf("Expected the merge to be skipped, got: %s", output)
+	}
+
+	tagged, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	if strings.TrimSpace(tagged) != strings.TrimSpace(mainTip) {
+		t.Errorf("Expected the tag to point at main %s, got %s", mainTip, tagged)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to be updated from main")
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected the release branch to be deleted")
+	}
+}
+
+// TestFinishIfMergedNotMerged tests that --if-merged finishes a branch that is not merged yet as usual.
+// Steps:
+// 1. Sets up a test repository and starts a feature with a commit
+// 2. Finishes the feature with --if-merged
+// 3. Verifies the feature was merged into develop
+func TestFinishIfMergedNotMerged(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "pending")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "pending", "--if-merged")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "skipping the merge") {
+		t.Errorf("Expected the merge not to be skipped, got: %s", output)
+	}
+	if !testutil.FileExists(t, dir, "pending.txt") {
+		t.Error("Expected the feature to be merged into develop")
+	}
+}
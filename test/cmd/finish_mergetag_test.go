@@ -0,0 +1,94 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishMergeTagToChildren tests that mergeTagToChildren updates develop from the release tag object.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0.0 and commits to it
+// 2. Sets gitflow.release.finish.mergeTagToChildren=true
+// 3. Finishes the release
+// 4. Verifies the merge commit on develop is named after the tag and carries the tag message
+// 5. Verifies the second parent of the merge commit is the tagged commit
+func TestFinishMergeTagToChildren(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0.0")
+	testutil.RunGit(t, dir, "config", "gitflow.release.finish.mergeTagToChildren", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "from tag '1.0.0'") {
+		t.Errorf("Expected the child update to name the tag, got: %s", output)
+	}
+
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Merge tag '1.0.0' into develop" {
+		t.Errorf("Expected a tag merge on develop, got %q", subject)
+	}
+	body, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%b", "develop")
+	if !strings.Contains(body, "Tagging version 1.0.0") {
+		t.Errorf("Expected the tag message in the develop merge commit, got:\n%s", body)
+	}
+	tagged, _ := testutil.RunGit(t, dir, "rev-parse", "1.0.0^{commit}")
+	secondParent, _ := testutil.RunGit(t, dir, "rev-parse", "develop^2")
+	if strings.TrimSpace(secondParent) != strings.TrimSpace(tagged) {
+		t.Errorf("Expected develop to merge the tagged commit %s, got %s", tagged, secondParent)
+	}
+}
+
+// TestFinishMergesBranchToChildrenByDefault tests that develop is updated from the main branch without mergeTagToChildren.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0.0 and commits to it
+// 2. Finishes the release without mergeTagToChildren
+// 3. Verifies the merge commit on develop merges main
+func TestFinishMergesBranchToChildrenByDefault(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0.0")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Merge branch 'main' into develop" {
+		t.Errorf("Expected a branch merge on develop, got %q", subject)
+	}
+}
+
+// TestFinishMergeTagToChildrenWithoutTag tests that develop is updated from main when no tag is created.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0.0 and commits to it
+// 2. Sets gitflow.release.finish.mergeTagToChildren=true
+// 3. Finishes the release with --notag
+// 4. Verifies develop merges main
+func TestFinishMergeTagToChildrenWithoutTag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0.0")
+	testutil.RunGit(t, dir, "config", "gitflow.release.finish.mergeTagToChildren", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0", "--notag")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	subject, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%s", "develop")
+	if strings.TrimSpace(subject) != "Merge branch 'main' into develop" {
+		t.Errorf("Expected a branch merge on develop, got %q", subject)
+	}
+}
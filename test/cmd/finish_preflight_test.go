@@ -0,0 +1,133 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishPreflightPrintsChecklist tests that a successful finish prints the pre-flight checklist.
+// Steps:
+// 1. Sets up a test repository and creates a feature branch with a commit
+// 2. Finishes the feature branch
+// 3. Verifies the checklist shows passed checks and the skipped remote check
+func TestFinishPreflightPrintsChecklist(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "checklist")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "checklist.txt", "content")
+	_, err = testutil.RunGit(t, dir, "add", "checklist.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add checklist")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "checklist")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"Pre-flight checks:",
+		"✓ Branch 'feature/checklist' exists",
+		"✓ No Git operation in progress",
+		"✓ Working tree is clean",
+		"✓ Base branch 'develop' exists (configured parent)",
+		"- Remote 'origin' is reachable (skipped: remote not configured)",
+		"✓ Hooks are executable",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+// TestFinishPreflightReportsAllProblems tests that all pre-flight problems are reported at once.
+// Steps:
+// 1. Sets up a test repository and creates a feature branch
+// 2. Leaves an uncommitted change, installs a non-executable pre-finish hook and renames 'develop'
+// 3. Attempts to finish the feature branch
+// 4. Verifies the command fails with a validation exit code and reports all three problems
+// 5. Verifies nothing was changed: no merge state and the feature branch still exists
+func TestFinishPreflightReportsAllProblems(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "broken")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Uncommitted change to a tracked file
+	testutil.WriteFile(t, dir, "README.md", "modified")
+
+	// Hook without the executable bit
+	hookPath := filepath.Join(dir, ".git", "hooks", "pre-flow-feature-finish")
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\nexit 0\n"), 0644); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	// Renamed base branch
+	_, err = testutil.RunGit(t, dir, "branch", "-m", "develop", "integration")
+	if err != nil {
+		t.Fatalf("Failed to rename develop: %v", err)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "broken")
+	if err == nil {
+		t.Fatalf("Expected finish to fail pre-flight checks, got output: %s", output)
+	}
+
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeValidationError) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeValidationError, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+
+	for _, expected := range []string{
+		"✗ Working tree is clean",
+		"✗ Base branch 'develop' exists (configured parent)",
+		"✗ Hooks are executable",
+		"pre-flight checks failed",
+		"working tree has uncommitted changes",
+		"base branch 'develop' (configured parent) does not exist",
+		"pre-flow-feature-finish",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	if state, _ := testutil.LoadMergeState(t, dir); state != nil {
+		t.Error("Expected no merge state after pre-flight failure")
+	}
+	if !testutil.BranchExists(t, dir, "feature/broken") {
+		t.Error("Expected feature branch to be kept")
+	}
+}
@@ -0,0 +1,128 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishReleaseCollectsReleaseNotes tests collecting Release-Note trailers on release finish.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and tags a previous release
+// 2. Enables release notes with a notes file and tag annotation
+// 3. Creates a release branch with commits carrying Release-Note trailers
+// 4. Installs a post-finish hook that copies $RELEASE_NOTES_FILE
+// 5. Finishes the release
+// 6. Verifies the notes file, the tag annotation and the hook copy hold only the new notes
+func TestFinishReleaseCollectsReleaseNotes(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// A note from before the previous release must not be collected
+	testutil.WriteFile(t, dir, "old.txt", "old")
+	testutil.RunGit(t, dir, "add", "old.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Old change\n\nRelease-Note: Old note")
+	testutil.RunGit(t, dir, "tag", "-a", "0.9.0", "-m", "Previous release")
+
+	testutil.RunGit(t, dir, "config", "gitflow.releasenotes.enabled", "true")
+	testutil.RunGit(t, dir, "config", "gitflow.releasenotes.file", "NOTES.md")
+	testutil.RunGit(t, dir, "config", "gitflow.releasenotes.tag", "true")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+
+	testutil.WriteFile(t, dir, "login.txt", "login")
+	testutil.RunGit(t, dir, "add", "login.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add login\n\nRelease-Note: Users can log in")
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Internal cleanup")
+	testutil.WriteFile(t, dir, "logout.txt", "logout")
+	testutil.RunGit(t, dir, "add", "logout.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add logout\n\nRelease-Note: Users can log out")
+
+	hookCopy := filepath.Join(dir, "hook-notes.txt")
+	createHookScript(t, dir, "post-flow-release-finish", `#!/bin/sh
+cp "$RELEASE_NOTES_FILE" "`+hookCopy+`"
+`)
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	expected := "- Users can log in\n- Users can log out\n"
+
+	notes, err := os.ReadFile(filepath.Join(dir, "NOTES.md"))
+	if err != nil {
+		t.Fatalf("Expected notes file to be written: %v", err)
+	}
+	if string(notes) != expected {
+		t.Errorf("Expected notes %q, got %q", expected, string(notes))
+	}
+
+	annotation, err := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to read tag annotation: %v", err)
+	}
+	if !strings.Contains(annotation, expected) {
+		t.Errorf("Expected tag annotation to contain the notes, got: %s", annotation)
+	}
+	if strings.Contains(annotation, "Old note") {
+		t.Errorf("Expected notes before the previous tag to be skipped, got: %s", annotation)
+	}
+
+	copied, err := os.ReadFile(hookCopy)
+	if err != nil {
+		t.Fatalf("Expected post-finish hook to read RELEASE_NOTES_FILE: %v", err)
+	}
+	if string(copied) != expected {
+		t.Errorf("Expected hook to see notes %q, got %q", expected, string(copied))
+	}
+}
+
+// TestFinishReleaseWithoutReleaseNotesConfig tests that release notes are off by default.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Creates a release branch with a Release-Note trailer
+// 3. Finishes the release
+// 4. Verifies the tag annotation does not contain the note
+func TestFinishReleaseWithoutReleaseNotesConfig(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "login.txt", "login")
+	testutil.RunGit(t, dir, "add", "login.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add login\n\nRelease-Note: Users can log in")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	annotation, _ := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.0.0")
+	if strings.Contains(annotation, "Users can log in") {
+		t.Errorf("Expected no release notes without configuration, got: %s", annotation)
+	}
+}
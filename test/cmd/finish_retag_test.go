@@ -0,0 +1,179 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// startReleaseWithTakenTag starts release 1.0 with a commit and then tags develop as 1.0
+func startReleaseWithTakenTag(t *testing.T, dir string) {
+	t.Helper()
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0")
+	testutil.RunGit(t, dir, "tag", "-a", "1.0", "-m", "Old tag", "develop")
+}
+
+// TestFinishFailsOnExistingTag tests that finish fails before merging when the tag already exists.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0 with a commit and tags develop as 1.0
+// 2. Runs 'git flow release finish 1.0'
+// 3. Verifies it fails with exit code 4 and points to --retag and --skip-tag
+// 4. Verifies main is unchanged and release/1.0 still exists
+func TestFinishFailsOnExistingTag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startReleaseWithTakenTag(t, dir)
+	mainBefore, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0")
+	assertExitCode(t, err, errors.ExitCodeBranchExists, output)
+	if !strings.Contains(output, "--retag") || !strings.Contains(output, "--skip-tag") {
+		t.Errorf("Expected the error to explain --retag and --skip-tag\nOutput: %s", output)
+	}
+
+	if mainAfter, _ := testutil.RunGit(t, dir, "rev-parse", "main"); mainAfter != mainBefore {
+		t.Error("Expected main to be unchanged")
+	}
+	if !testutil.BranchExists(t, dir, "release/1.0") {
+		t.Error("Expected release/1.0 to still exist")
+	}
+}
+
+// TestFinishRetag tests that --retag moves an existing tag to the finished commit.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0 with a commit and tags develop as 1.0
+// 2. Runs 'git flow release finish --retag 1.0'
+// 3. Verifies tag 1.0 points at main and release/1.0 is deleted
+func TestFinishRetag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startReleaseWithTakenTag(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--retag", "1.0")
+	if err != nil {
+		t.Fatalf("Failed to finish with --retag: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Moved tag '1.0'") {
+		t.Errorf("Expected the tag to be reported as moved\nOutput: %s", output)
+	}
+
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if testutil.RevParse(t, dir, "1.0") != strings.TrimSpace(mainCommit) {
+		t.Error("Expected tag 1.0 to point at main")
+	}
+	if testutil.BranchExists(t, dir, "release/1.0") {
+		t.Error("Expected release/1.0 to be deleted")
+	}
+}
+
+// TestFinishSkipTag tests that --skip-tag keeps an existing tag and finishes without creating one.
+// Steps:
+// 1. Sets up a test repository, starts release 1.0 with a commit and tags develop as 1.0
+// 2. Runs 'git flow release finish --skip-tag 1.0'
+// 3. Verifies tag 1.0 still points at its old commit and release/1.0 is merged and deleted
+func TestFinishSkipTag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	startReleaseWithTakenTag(t, dir)
+	oldCommit := testutil.RevParse(t, dir, "1.0")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--skip-tag", "1.0")
+	if err != nil {
+		t.Fatalf("Failed to finish with --skip-tag: %v\nOutput: %s", err, output)
+	}
+
+	if testutil.RevParse(t, dir, "1.0") != oldCommit {
+		t.Error("Expected tag 1.0 to keep pointing at its old commit")
+	}
+	testutil.RunGit(t, dir, "checkout", "main")
+	if !testutil.FileExists(t, dir, "1.0.txt") {
+		t.Error("Expected main to contain the release")
+	}
+	if testutil.BranchExists(t, dir, "release/1.0") {
+		t.Error("Expected release/1.0 to be deleted")
+	}
+}
+
+// TestFinishContinueWithRetag tests that a tag created while a finish was stopped
+// on a conflict stops the --continue, which can then be run again with --retag.
+// Steps:
+// 1. Sets up a test repository and starts release 1.0 changing a file that main changes too
+// 2. Runs 'git flow release finish 1.0' and verifies it stops on the conflict
+// 3. Tags develop as 1.0, resolves the conflict and runs 'git flow release finish --continue'
+// 4. Verifies it fails with exit code 4 and suggests '--continue --retag'
+// 5. Runs 'git flow release finish --continue --retag' and verifies tag 1.0 points at main
+func TestFinishContinueWithRetag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "release")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Release change")
+	testutil.RunGit(t, dir, "checkout", "main")
+	testutil.WriteFile(t, dir, "shared.txt", "main")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Main change")
+	testutil.RunGit(t, dir, "checkout", "release/1.0")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+
+	testutil.RunGit(t, dir, "tag", "1.0", "develop")
+	testutil.WriteFile(t, dir, "shared.txt", "resolved")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue")
+	assertExitCode(t, err, errors.ExitCodeBranchExists, output)
+	if !strings.Contains(output, "--continue --retag") {
+		t.Errorf("Expected the error to suggest '--continue --retag'\nOutput: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "--retag")
+	if err != nil {
+		t.Fatalf("Failed to continue with --retag: %v\nOutput: %s", err, output)
+	}
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if testutil.RevParse(t, dir, "1.0") != strings.TrimSpace(mainCommit) {
+		t.Error("Expected tag 1.0 to point at main")
+	}
+}
+
+// TestFinishRerunKeepsOwnTag tests that finishing a kept branch again accepts the tag the first finish created.
+// Steps:
+// 1. Sets up a test repository and starts release 1.0 with a commit
+// 2. Runs 'git flow release finish --keep 1.0'
+// 3. Runs 'git flow release finish --if-merged 1.0' again
+// 4. Verifies it succeeds and tag 1.0 still points at main
+func TestFinishRerunKeepsOwnTag(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "release", "1.0")
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--keep", "1.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "checkout", "release/1.0")
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--if-merged", "1.0")
+	if err != nil {
+		t.Fatalf("Expected finishing again to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Tag '1.0' already points at 'main'") {
+		t.Errorf("Expected the existing tag to be reused\nOutput: %s", output)
+	}
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	if testutil.RevParse(t, dir, "1.0") != strings.TrimSpace(mainCommit) {
+		t.Error("Expected tag 1.0 to point at main")
+	}
+}
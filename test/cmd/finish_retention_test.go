@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gittower/git-flow-next/test/testutil"
@@ -58,13 +59,14 @@ func TestFinishFeatureBranchDefaultLocalDeletion(t *testing.T) {
 	}
 }
 
-// TestFinishFeatureBranchDefaultRemoteDeletion tests that feature branches are deleted both locally and remotely by default.
+// TestFinishFeatureBranchDefaultRemoteDeletion tests that feature branches are deleted both locally and remotely by default
+// once the merge has been pushed.
 // Steps:
 // 1. Sets up a test repository and initializes git-flow
 // 2. Creates a feature branch
 // 3. Adds changes to the feature branch
 // 4. Adds a remote repository
-// 5. Finishes the feature branch
+// 5. Finishes the feature branch with --push
 // 6. Verifies both local and remote branches are deleted
 func TestFinishFeatureBranchDefaultRemoteDeletion(t *testing.T) {
 	// Setup
@@ -109,8 +111,8 @@ func TestFinishFeatureBranchDefaultRemoteDeletion(t *testing.T) {
 		t.Fatalf("Failed to push feature branch: %v", err)
 	}
 
-	// Finish the feature branch
-	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "my-feature")
+	// Finish the feature branch, pushing the merge
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--push", "my-feature")
 	if err != nil {
 		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
 	}
@@ -130,6 +132,164 @@ func TestFinishFeatureBranchDefaultRemoteDeletion(t *testing.T) {
 	}
 }
 
+// TestFinishFeatureBranchCustomRemoteDeletion tests that finish deletes the remote branch on the configured gitflow.origin.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Adds a remote named 'upstream' and sets gitflow.origin to it
+// 3. Creates a feature branch and pushes it to 'upstream'
+// 4. Finishes the feature branch with --push
+// 5. Verifies the branch is deleted on 'upstream'
+func TestFinishFeatureBranchCustomRemoteDeletion(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	// Initialize git-flow with defaults and create branches
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	// Add a custom remote and configure it as the git-flow remote
+	remoteDir, err := testutil.AddRemote(t, dir, "upstream", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	_, err = testutil.RunGit(t, dir, "config", "gitflow.origin", "upstream")
+	if err != nil {
+		t.Fatalf("Failed to set custom remote: %v", err)
+	}
+
+	// Create a feature branch with a commit
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "my-feature")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "test.txt", "test content")
+	_, err = testutil.RunGit(t, dir, "add", "test.txt")
+	if err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	_, err = testutil.RunGit(t, dir, "commit", "-m", "Add test file")
+	if err != nil {
+		t.Fatalf("Failed to commit file: %v", err)
+	}
+
+	// Push the feature branch to the custom remote
+	_, err = testutil.RunGit(t, dir, "push", "upstream", "feature/my-feature")
+	if err != nil {
+		t.Fatalf("Failed to push feature branch: %v", err)
+	}
+
+	// Finish the feature branch, pushing the merge
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--push", "my-feature")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Verify that the branch is gone from the custom remote
+	output, err = testutil.RunGit(t, dir, "ls-remote", "--heads", "upstream", "feature/my-feature")
+	if err != nil {
+		t.Fatalf("Failed to list remote heads: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Expected feature branch to be deleted on 'upstream', got: %s", output)
+	}
+}
+
+// TestFinishFeatureBranchUnpushedMergeKeepsRemote tests that finish keeps the remote branch
+// when the merge into the parent has not been pushed.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Creates a feature branch with a commit and pushes it
+// 3. Finishes the feature branch without --push
+// 4. Verifies the local branch is deleted but the remote branch is kept with a warning
+func TestFinishFeatureBranchUnpushedMergeKeepsRemote(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "unpushed")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "test.txt", "test content")
+	testutil.RunGit(t, dir, "add", "test.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add test file")
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "feature/unpushed"); err != nil {
+		t.Fatalf("Failed to push feature branch: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "unpushed")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Kept remote branch 'origin/feature/unpushed'") {
+		t.Errorf("Expected a warning about the kept remote branch, got: %s", output)
+	}
+
+	if testutil.BranchExists(t, dir, "feature/unpushed") {
+		t.Error("Expected local feature branch to be deleted")
+	}
+	heads, err := testutil.RunGit(t, dir, "ls-remote", "--heads", "origin", "feature/unpushed")
+	if err != nil {
+		t.Fatalf("Failed to list remote heads: %v", err)
+	}
+	if heads == "" {
+		t.Error("Expected remote feature branch to be kept while the merge is unpushed")
+	}
+}
+
+// TestFinishFeatureBranchMergedOnRemoteDeletesRemote tests that finish deletes the remote branch
+// when it is already contained in the parent on the remote.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow
+// 2. Creates a feature branch with a commit and pushes it
+// 3. Fast-forwards develop on the remote to the feature, as a pull request would
+// 4. Finishes the feature branch without --push
+// 5. Verifies the remote branch is deleted
+func TestFinishFeatureBranchMergedOnRemoteDeletesRemote(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "reviewed")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "test.txt", "test content")
+	testutil.RunGit(t, dir, "add", "test.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add test file")
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "feature/reviewed"); err != nil {
+		t.Fatalf("Failed to push feature branch: %v\nOutput: %s", err, output)
+	}
+
+	// Merge on the remote side, as a pull request would
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "feature/reviewed:develop"); err != nil {
+		t.Fatalf("Failed to update remote develop: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "fetch", "origin")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "reviewed")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Deleted remote branch 'origin/feature/reviewed'") {
+		t.Errorf("Expected the remote branch to be deleted, got: %s", output)
+	}
+	heads, err := testutil.RunGit(t, dir, "ls-remote", "--heads", "origin", "feature/reviewed")
+	if err != nil {
+		t.Fatalf("Failed to list remote heads: %v", err)
+	}
+	if heads != "" {
+		t.Errorf("Expected remote feature branch to be deleted, got: %s", heads)
+	}
+}
+
 // TestFinishFeatureBranchKeepLocal tests that the keep-local option preserves the local branch when finishing.
 // Steps:
 // 1. Sets up a test repository and initializes git-flow
@@ -0,0 +1,167 @@
+package cmd_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupSignedBase configures SSH commit signing in dir and adds a signed
+// commit to develop. It returns the path of the public signing key.
+func setupSignedBase(t *testing.T, dir string) string {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	keyDir := t.TempDir()
+	keyPath := filepath.Join(keyDir, "signing_key")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "tester", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to generate signing key: %v\nOutput: %s", err, output)
+	}
+	publicKey, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		t.Fatalf("Failed to read public key: %v", err)
+	}
+	allowedSigners := filepath.Join(keyDir, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte("test@example.com "+string(publicKey)), 0644); err != nil {
+		t.Fatalf("Failed to write allowed signers file: %v", err)
+	}
+
+	testutil.RunGit(t, dir, "config", "user.email", "test@example.com")
+	testutil.RunGit(t, dir, "config", "gpg.format", "ssh")
+	testutil.RunGit(t, dir, "config", "user.signingkey", keyPath+".pub")
+	testutil.RunGit(t, dir, "config", "gpg.ssh.allowedSignersFile", allowedSigners)
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if output, err := testutil.RunGit(t, dir, "commit", "-S", "--allow-empty", "-m", "Signed develop commit"); err != nil {
+		t.Fatalf("Failed to create signed commit: %v\nOutput: %s", err, output)
+	}
+	return keyPath + ".pub"
+}
+
+// TestFinishVerifiesSignedBase tests that finish accepts a base branch whose tip
+// has a good signature.
+// Steps:
+// 1. Sets up a test repository with SSH signing and a signed develop tip
+// 2. Enables gitflow.finish.verifyBaseSignature
+// 3. Finishes a feature branch
+// 4. Verifies the finish succeeds and reports the verified signature
+func TestFinishVerifiesSignedBase(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	setupSignedBase(t, dir)
+	testutil.RunGit(t, dir, "config", "gitflow.finish.verifybasesignature", "true")
+	testutil.StartBranchWithCommit(t, dir, "feature", "signed-base")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "signed-base")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Verified signature of 'develop'") {
+		t.Errorf("Expected verified signature message, got: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/signed-base") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
+
+// TestFinishRefusesUnsignedBase tests that finish refuses to merge into a base
+// branch whose tip is not signed, and passes the failure to the pre-finish hook.
+// Steps:
+// 1. Sets up a test repository with SSH signing and an unsigned develop tip
+// 2. Enables gitflow.feature.finish.verifyBaseSignature and adds a pre-finish hook
+// 3. Finishes a feature branch
+// 4. Verifies the finish fails with a validation error and nothing was merged
+// 5. Verifies the hook received the signature status and error
+func TestFinishRefusesUnsignedBase(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	setupSignedBase(t, dir)
+	testutil.RunGit(t, dir, "commit", "--no-gpg-sign", "--allow-empty", "-m", "Unsigned develop commit")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.verifybasesignature", "true")
+	testutil.StartBranchWithCommit(t, dir, "feature", "unsigned-base")
+
+	hookOutput := filepath.Join(dir, "hook-output.txt")
+	createHookScript(t, dir, "pre-flow-feature-finish", `#!/bin/sh
+echo "$BASE_SIGNATURE_STATUS|$BASE_SIGNATURE_ERROR" > "`+hookOutput+`"
+exit 0
+`)
+
+	developBefore := testutil.RevParse(t, dir, "develop")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "unsigned-base")
+	if err == nil {
+		t.Fatalf("Expected finish to fail, got output: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 6 {
+		t.Errorf("Expected exit code 6, got: %v", err)
+	}
+	if !strings.Contains(output, "refusing to merge into 'develop'") || !strings.Contains(output, "is missing") {
+		t.Errorf("Expected missing signature error, got: %s", output)
+	}
+	if testutil.RevParse(t, dir, "develop") != developBefore {
+		t.Error("Expected develop to be unchanged")
+	}
+	if !testutil.BranchExists(t, dir, "feature/unsigned-base") {
+		t.Error("Expected feature branch to still exist")
+	}
+
+	content, err := os.ReadFile(hookOutput)
+	if err != nil {
+		t.Fatalf("Expected pre-finish hook to run: %v", err)
+	}
+	if strings.TrimSpace(string(content)) != "N|is missing" {
+		t.Errorf("Expected hook to receive signature failure, got: %q", string(content))
+	}
+}
+
+// TestFinishRefusesBaseSignedByOtherKey tests that a good signature is refused
+// when its key is not listed in allowedSigningKeys.
+// Steps:
+// 1. Sets up a test repository with SSH signing and a signed develop tip
+// 2. Restricts gitflow.finish.allowedSigningKeys to an unrelated key
+// 3. Verifies finish fails naming the key
+// 4. Adds the signing key's fingerprint to the list and verifies finish succeeds
+func TestFinishRefusesBaseSignedByOtherKey(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	setupSignedBase(t, dir)
+	testutil.RunGit(t, dir, "config", "gitflow.finish.verifybasesignature", "true")
+	testutil.RunGit(t, dir, "config", "gitflow.finish.allowedsigningkeys", "SHA256:not-a-real-key")
+	testutil.StartBranchWithCommit(t, dir, "feature", "other-key")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "other-key")
+	if err == nil {
+		t.Fatalf("Expected finish to fail, got output: %s", output)
+	}
+	if !strings.Contains(output, "which is not in allowedSigningKeys") {
+		t.Errorf("Expected disallowed key error, got: %s", output)
+	}
+
+	fingerprint, err := testutil.RunGit(t, dir, "log", "-1", "--format=%GK", "develop")
+	if err != nil {
+		t.Fatalf("Failed to read signing key: %v", err)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.finish.allowedsigningkeys", "SHA256:not-a-real-key, "+strings.TrimSpace(fingerprint))
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "other-key")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed with the allowed key: %v\nOutput: %s", err, output)
+	}
+}
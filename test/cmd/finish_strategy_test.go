@@ -225,6 +225,107 @@ func TestFinishWithNoFFFlag(t *testing.T) {
 	}
 }
 
+// TestFinishWithFFOnlyFlag tests that --ff-only fast-forwards the base branch.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Creates a feature branch with a commit
+// 3. Verifies --ff-only can't be combined with --no-ff
+// 4. Finishes the feature branch with --ff-only
+// 5. Verifies develop was fast-forwarded to the feature commit
+func TestFinishWithFFOnlyFlag(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "ff-only-test")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	featureCommit := testutil.RevParse(t, dir, "HEAD")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ff-only-test", "--ff-only", "--no-ff")
+	if err == nil {
+		t.Fatalf("Expected --ff-only with --no-ff to fail, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != 2 {
+		t.Errorf("Expected exit code 2, got %d", exitErr.ExitCode)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "ff-only-test", "--ff-only")
+	if err != nil {
+		t.Fatalf("Failed to finish feature branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.RevParse(t, dir, "develop") != featureCommit {
+		t.Error("Expected develop to be fast-forwarded to the feature commit")
+	}
+}
+
+// TestFinishFFOnlyConfigRefusesMergeCommit tests that gitflow.<type>.finish.ff-only
+// stops a finish that would need a merge commit before anything changes.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Configures gitflow.feature.finish.ff-only=true
+// 3. Creates a feature branch and a diverging commit on develop
+// 4. Verifies finish fails, develop is unchanged and the branch is kept
+// 5. Verifies --ff overrides the configuration and finishes with a merge
+func TestFinishFFOnlyConfigRefusesMergeCommit(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.ff-only", "true")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "diverged")
+	if err != nil {
+		t.Fatalf("Failed to start feature branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop.txt", "develop content")
+	testutil.RunGit(t, dir, "add", "develop.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add develop file")
+	developBefore := testutil.RevParse(t, dir, "develop")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "diverged")
+	if err == nil {
+		t.Fatalf("Expected finish to fail without a possible fast-forward, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != 6 {
+		t.Errorf("Expected exit code 6, got %d", exitErr.ExitCode)
+	}
+	if !strings.Contains(output, "cannot fast-forward 'develop' to 'feature/diverged'") {
+		t.Errorf("Expected fast-forward error, got: %s", output)
+	}
+	if testutil.RevParse(t, dir, "develop") != developBefore {
+		t.Error("Expected develop to be unchanged")
+	}
+	if !testutil.BranchExists(t, dir, "feature/diverged") {
+		t.Error("Expected feature branch to be kept")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "diverged", "--ff")
+	if err != nil {
+		t.Fatalf("Expected --ff to override ff-only: %v\nOutput: %s", err, output)
+	}
+	if !testutil.FileExists(t, dir, "feature.txt") {
+		t.Error("Expected feature.txt to be merged into develop")
+	}
+}
+
 // TestMergeStrategyFlagOverridesConfig tests that command-line flags override configuration.
 // Steps:
 // 1. Sets up a test repository and initializes git-flow with defaults
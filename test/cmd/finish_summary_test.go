@@ -0,0 +1,65 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishSummary tests that finish --summary shows what the merge brings in.
+// Steps:
+// 1. Sets up a test repository and starts feature 'login' with a commit adding one line
+// 2. Finishes the feature with --summary
+// 3. Verifies the output counts one commit, one file and one added line, without colors
+func TestFinishSummary(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--summary")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	expected := "Merging 'feature/login' into 'develop': 1 commit(s), 1 file(s) changed, +1/-0 lines"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected summary %q, got: %s", expected, output)
+	}
+}
+
+// TestFinishSummaryFromConfig tests that gitflow.finish.summary enables the summary and --no-summary disables it.
+// Steps:
+// 1. Sets up a test repository, starts feature 'login' and sets gitflow.finish.summary
+// 2. Finishes the feature with --no-summary and verifies no summary is shown
+// 3. Starts feature 'signup' and finishes it without flags
+// 4. Verifies the summary is shown
+func TestFinishSummaryFromConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	testutil.RunGit(t, dir, "config", "gitflow.finish.summary", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--no-summary")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "commit(s),") {
+		t.Errorf("Expected no summary with --no-summary, got: %s", output)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "signup"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/signup", "signup.txt", "signup")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "signup")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Merging 'feature/signup' into 'develop': 1 commit(s)") {
+		t.Errorf("Expected the configured summary, got: %s", output)
+	}
+}
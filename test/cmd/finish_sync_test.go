@@ -721,3 +721,85 @@ func TestFinishFetchFailsButSyncCheckStillRuns(t *testing.T) {
 		t.Error("Expected feature branch to still exist after aborted finish")
 	}
 }
+
+// TestFinishFeatureBranchBehindUntrackedRemote tests that finish compares with the
+// remote branch of the same name when no tracking branch is configured.
+// Steps:
+// 1. Sets up a test repository with remote and initializes git-flow
+// 2. Creates a feature branch and pushes it without setting an upstream
+// 3. Pushes another commit to the remote feature branch from a clone
+// 4. Fetches and attempts to finish the feature branch
+// 5. Verifies the finish is refused with a hint to track the remote branch
+func TestFinishFeatureBranchBehindUntrackedRemote(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	_, err := testutil.RunGitFlow(t, dir, "feature", "start", "untracked")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "feature/untracked"); err != nil {
+		t.Fatalf("Failed to push branch: %v\nOutput: %s", err, output)
+	}
+
+	testutil.PushFromClone(t, remoteDir, "feature/untracked", "remote-change.txt")
+	testutil.RunGit(t, dir, "fetch", "origin")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "untracked")
+	if err == nil {
+		t.Fatalf("Expected finish to fail when behind the untracked remote branch. Output: %s", output)
+	}
+	if !strings.Contains(output, "behind 'origin/feature/untracked'") {
+		t.Errorf("Expected error to name the remote branch. Output: %s", output)
+	}
+	if !strings.Contains(output, "git branch --set-upstream-to=origin/feature/untracked feature/untracked") {
+		t.Errorf("Expected error to suggest tracking the remote branch. Output: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/untracked") {
+		t.Error("Expected feature branch to still exist after aborted finish")
+	}
+}
+
+// TestFinishRequireUpToDateTopicDisabled tests that gitflow.finish.requireUpToDateTopic=false
+// turns the remote check into a warning.
+// Steps:
+// 1. Sets up a test repository with remote and initializes git-flow
+// 2. Creates a tracked feature branch that is behind its remote
+// 3. Disables gitflow.finish.requireUpToDateTopic
+// 4. Finishes the feature branch
+// 5. Verifies the finish succeeds with a warning
+func TestFinishRequireUpToDateTopicDisabled(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	_, err := testutil.RunGitFlow(t, dir, "feature", "start", "stale")
+	if err != nil {
+		t.Fatalf("Failed to create feature branch: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+	if output, err := testutil.RunGit(t, dir, "push", "--set-upstream", "origin", "feature/stale"); err != nil {
+		t.Fatalf("Failed to push branch: %v\nOutput: %s", err, output)
+	}
+
+	testutil.PushFromClone(t, remoteDir, "feature/stale", "remote-change.txt")
+	testutil.RunGit(t, dir, "fetch", "origin")
+	testutil.RunGit(t, dir, "config", "gitflow.finish.requireUpToDateTopic", "false")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "stale")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed with the check disabled. Error: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Warning: Local branch 'feature/stale' is behind") {
+		t.Errorf("Expected a warning about the stale branch. Output: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "feature/stale") {
+		t.Error("Expected feature branch to be deleted")
+	}
+}
@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// trailerValue returns the values of a trailer in the commit at rev
+func trailerValue(t *testing.T, dir string, rev string, key string) string {
+	t.Helper()
+	value, err := testutil.RunGit(t, dir, "log", "-1", "--format=%(trailers:key="+key+",valueonly)", rev)
+	if err != nil {
+		t.Fatalf("Failed to read trailer '%s': %v", key, err)
+	}
+	return strings.TrimSpace(value)
+}
+
+// TestFinishConfiguredTrailer tests that a trailer template configured for a
+// branch type is expanded into the merge commit.
+// Steps:
+// 1. Sets up a test repository and configures gitflow.feature.finish.trailer "Feature: %v into %p"
+// 2. Starts feature 'login' with a commit and finishes it
+// 3. Verifies the merge commit on develop has the expanded trailer
+func TestFinishConfiguredTrailer(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.trailer", "Feature: %v into %p")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login"); err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if value := trailerValue(t, dir, "develop", "Feature"); value != "login into develop" {
+		t.Errorf("Expected trailer 'Feature: login into develop', got '%s'", value)
+	}
+	if parents, _ := testutil.RunGit(t, dir, "log", "-1", "--format=%p", "develop"); len(strings.Fields(parents)) != 2 {
+		t.Errorf("Expected a merge commit carrying the trailer, got parents: %s", parents)
+	}
+}
+
+// TestFinishRequiredTrailer tests that a configured trailer without a value is
+// required, and is taken from --trailer or the prompt.
+// Steps:
+// 1. Sets up a test repository and configures gitflow.feature.finish.trailer "Reviewed-by:"
+// 2. Finishes feature 'login' without the trailer and verifies it fails with exit code 2
+// 3. Finishes it with --trailer "Reviewed-by: Jane Doe <jane@example.com>"
+// 4. Verifies the merge commit on develop has the given trailer
+func TestFinishRequiredTrailer(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.trailer", "Reviewed-by:")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Fatal("Expected the feature branch to be kept when the trailer is missing")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--trailer", "Reviewed-by: Jane Doe <jane@example.com>")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if value := trailerValue(t, dir, "develop", "Reviewed-by"); value != "Jane Doe <jane@example.com>" {
+		t.Errorf("Expected the Reviewed-by trailer, got '%s'", value)
+	}
+}
+
+// TestFinishRequiredTrailerPrompt tests that a required trailer is asked for
+// when it isn't given on the command line.
+// Steps:
+// 1. Sets up a test repository and configures gitflow.feature.finish.trailer "Reviewed-by:"
+// 2. Finishes feature 'login' answering the prompt with a reviewer
+// 3. Verifies the merge commit on develop has the answered trailer
+func TestFinishRequiredTrailerPrompt(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.trailer", "Reviewed-by:")
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "Max Mustermann <max@example.com>\n", "feature", "finish", "login")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if value := trailerValue(t, dir, "develop", "Reviewed-by"); value != "Max Mustermann <max@example.com>" {
+		t.Errorf("Expected the answered Reviewed-by trailer, got '%s'", value)
+	}
+}
+
+// TestFinishTrailerOnSquash tests that --trailer is added to the squash commit.
+// Steps:
+// 1. Sets up a test repository and starts feature 'login' with a commit
+// 2. Finishes it with --squash --trailer "Ticket: ABC-1"
+// 3. Verifies the squash commit on develop has the trailer
+func TestFinishTrailerOnSquash(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--squash", "--trailer", "Ticket: ABC-1")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if value := trailerValue(t, dir, "develop", "Ticket"); value != "ABC-1" {
+		t.Errorf("Expected the Ticket trailer on the squash commit, got '%s'", value)
+	}
+}
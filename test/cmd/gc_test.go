@@ -0,0 +1,97 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestGCRemovesStaleBranchConfig tests that gc removes the stored base of a branch deleted with plain Git.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts features 'login' and 'signup' and deletes feature/login with git branch -D
+// 3. Runs git flow gc --dry-run and verifies the stale base is listed but kept
+// 4. Runs git flow gc and verifies the stale base is removed
+// 5. Verifies the base of feature/signup and the branch type definitions are kept
+func TestGCRemovesStaleBranchConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	for _, name := range []string{"login", "signup"} {
+		if output, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+			t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+		}
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/login")
+
+	output, err := testutil.RunGitFlow(t, dir, "gc", "--dry-run")
+	if err != nil {
+		t.Fatalf("Failed to run gc --dry-run: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Would remove the configuration of 'feature/login'") || !strings.Contains(output, "gitflow.branch.feature/login.base=develop") {
+		t.Errorf("Expected the stale base to be listed, got: %s", output)
+	}
+	if strings.Contains(output, "feature/signup") {
+		t.Errorf("Expected the base of an existing branch not to be listed, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/login.base"); err != nil {
+		t.Error("Expected --dry-run to keep the stale base")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "gc")
+	if err != nil {
+		t.Fatalf("Failed to run gc: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Removed 1 stale branch section(s)") {
+		t.Errorf("Expected one section to be removed, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/login.base"); err == nil {
+		t.Error("Expected the stale base to be removed")
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/signup.base"); err != nil {
+		t.Error("Expected the base of feature/signup to be kept")
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature.type"); err != nil {
+		t.Error("Expected the feature branch type to be kept")
+	}
+}
+
+// TestGCKeepsConfigOfRemoteBranches tests that gc keeps the configuration of branches that only exist on the remote.
+// Steps:
+// 1. Sets up a test repository with a remote and initializes git-flow with defaults
+// 2. Starts and publishes feature 'login', then deletes the local branch
+// 3. Runs git flow gc and verifies nothing is removed
+func TestGCKeepsConfigOfRemoteBranches(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "login"); err != nil {
+		t.Fatalf("Failed to publish feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/login")
+
+	output, err := testutil.RunGitFlow(t, dir, "gc")
+	if err != nil {
+		t.Fatalf("Failed to run gc: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No stale branch configuration found") {
+		t.Errorf("Expected nothing to be removed, got: %s", output)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestGitSubcommandExitCodes tests that exit codes reach the caller of 'git flow'.
+// Steps:
+// 1. Runs 'git flow init --defaults' and 'git flow feature start login' through git
+// 2. Starts feature/login again and verifies git exits with status 4
+// 3. Finishes a feature that doesn't exist and verifies git exits with status 5
+func TestGitSubcommandExitCodes(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	for _, args := range [][]string{{"flow", "init", "--defaults"}, {"flow", "feature", "start", "login"}} {
+		if output, err := testutil.RunGitSubcommand(t, dir, nil, args...); err != nil {
+			t.Fatalf("Failed to run git %s: %v\nOutput: %s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	output, err := testutil.RunGitSubcommand(t, dir, nil, "flow", "feature", "start", "login")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 4 {
+		t.Errorf("Expected exit status 4 for an existing branch, got %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitSubcommand(t, dir, nil, "flow", "feature", "finish", "missing")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != 5 {
+		t.Errorf("Expected exit status 5 for a missing branch, got %v\nOutput: %s", err, output)
+	}
+}
+
+// TestGitSubcommandRelativeGitDir tests 'git --git-dir --work-tree flow' with relative paths.
+// Steps:
+// 1. Initializes git-flow and adds a post-start hook that records HEAD with git
+// 2. Runs 'git --git-dir=repo/.git --work-tree=repo flow feature start login' from the parent directory
+// 3. Verifies the branch is created and the hook, run at the working tree root, found the repository
+func TestGitSubcommandRelativeGitDir(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	createHookScript(t, dir, "post-flow-feature-start", "#!/bin/sh\ngit rev-parse --abbrev-ref HEAD > hook-head.txt\n")
+
+	parent, name := filepath.Dir(dir), filepath.Base(dir)
+	output, err := testutil.RunGitSubcommand(t, parent, nil,
+		"--git-dir="+filepath.Join(name, ".git"), "--work-tree="+name, "flow", "feature", "start", "login")
+	if err != nil {
+		t.Fatalf("Failed to start the feature through git: %v\nOutput: %s", err, output)
+	}
+
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to be created")
+	}
+	head, err := os.ReadFile(filepath.Join(dir, "hook-head.txt"))
+	if err != nil || strings.TrimSpace(string(head)) != "feature/login" {
+		t.Errorf("Expected the hook to see feature/login checked out, got %q (%v)", head, err)
+	}
+}
+
+// TestGitSubcommandConfigParameters tests that 'git -c key=value flow' overrides the config.
+// Steps:
+// 1. Initializes git-flow and starts feature/login
+// 2. Runs 'git -c gitflow.feature.finish.keep=true flow feature finish login'
+// 3. Verifies the finish keeps feature/login and the override is not written to the config
+func TestGitSubcommandConfigParameters(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+
+	output, err := testutil.RunGitSubcommand(t, dir, nil, "-c", "gitflow.feature.finish.keep=true", "flow", "feature", "finish", "login")
+	if err != nil {
+		t.Fatalf("Failed to finish the feature through git: %v\nOutput: %s", err, output)
+	}
+
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to be kept by the -c override")
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.keep"); err == nil {
+		t.Error("Expected the -c override not to be written to the config")
+	}
+}
@@ -0,0 +1,101 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupGitHubActions makes git-flow run as in a GitHub Actions step and returns the output and summary files.
+func setupGitHubActions(t *testing.T) (string, string) {
+	t.Helper()
+	actionsDir := t.TempDir()
+	outputFile := filepath.Join(actionsDir, "output")
+	summaryFile := filepath.Join(actionsDir, "summary")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_OUTPUT", outputFile)
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryFile)
+	return outputFile, summaryFile
+}
+
+// readActionFile returns the content of a GitHub Actions file, or "" if it wasn't written.
+func readActionFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// TestFinishWritesGitHubActionsOutputs tests that finish reports its results to GitHub Actions.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Starts a release branch and commits to it
+// 3. Finishes the release with GITHUB_ACTIONS, GITHUB_OUTPUT and GITHUB_STEP_SUMMARY set
+// 4. Verifies the step outputs name the branch, target, tag and updated develop
+// 5. Verifies the job summary describes the finish
+func TestFinishWritesGitHubActionsOutputs(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.2.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "release/1.2.0", "release.txt", "release")
+
+	outputFile, summaryFile := setupGitHubActions(t)
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.2.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	outputs := readActionFile(t, outputFile)
+	for _, expected := range []string{"branch=release/1.2.0\n", "base=main\n", "tag=1.2.0\n", "updated-branches=develop\n"} {
+		if !strings.Contains(outputs, expected) {
+			t.Errorf("Expected step output %q, got: %s", expected, outputs)
+		}
+	}
+	summary := readActionFile(t, summaryFile)
+	if !strings.Contains(summary, "### Finished `release/1.2.0`") || !strings.Contains(summary, "- Tagged `1.2.0`") || !strings.Contains(summary, "- Updated `develop`") {
+		t.Errorf("Expected the job summary to describe the finish, got: %s", summary)
+	}
+}
+
+// TestStartWithoutGitHubActionsWritesNoOutputs tests that step outputs are only written inside GitHub Actions.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Sets GITHUB_OUTPUT without GITHUB_ACTIONS and starts a feature branch
+// 3. Verifies no output file was written
+// 4. Sets GITHUB_ACTIONS and starts another feature branch
+// 5. Verifies the branch and base outputs were written
+func TestStartWithoutGitHubActionsWritesNoOutputs(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	outputFile, _ := setupGitHubActions(t)
+	t.Setenv("GITHUB_ACTIONS", "")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "one"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if outputs := readActionFile(t, outputFile); outputs != "" {
+		t.Errorf("Expected no step outputs outside GitHub Actions, got: %s", outputs)
+	}
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "two"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if outputs := readActionFile(t, outputFile); outputs != "branch=feature/two\nbase=develop\n" {
+		t.Errorf("Expected the branch and base outputs, got: %q", outputs)
+	}
+}
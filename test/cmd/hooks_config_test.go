@@ -0,0 +1,154 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// =============================================================================
+// Init Hook Tests
+// =============================================================================
+
+// TestInitPreHookBlocks tests that a failing pre-flow-init hook leaves the repository uninitialized.
+func TestInitPreHookBlocks(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	createHookScript(t, dir, "pre-flow-init", `#!/bin/sh
+echo "Error: initialization is managed centrally" >&2
+exit 1
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err == nil {
+		t.Fatalf("Expected init to fail when pre-hook fails, got: %s", output)
+	}
+	if !strings.Contains(output, "initialization is managed centrally") {
+		t.Errorf("Expected hook output in error, got: %s", output)
+	}
+
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.initialized"); strings.TrimSpace(value) != "" {
+		t.Errorf("Expected repository to stay uninitialized, got gitflow.initialized=%s", value)
+	}
+}
+
+// TestInitHooksReceiveConfiguration tests that the init hooks see the new branching model.
+func TestInitHooksReceiveConfiguration(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	markerFile := filepath.Join(dir, "init-hook.txt")
+	createHookScript(t, dir, "pre-flow-init", `#!/bin/sh
+grep -q "develop:" "$CONFIG_FILE" && echo "pre saw develop" >> "`+markerFile+`"
+`)
+	createHookScript(t, dir, "post-flow-init", `#!/bin/sh
+echo "post-$EXIT_CODE $(git config gitflow.branch.develop.parent)" >> "`+markerFile+`"
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Init hooks did not run: %v", err)
+	}
+	expected := "pre saw develop\npost-0 main\n"
+	if string(content) != expected {
+		t.Errorf("Expected hook markers %q, got %q", expected, string(content))
+	}
+}
+
+// =============================================================================
+// Config Hook Tests
+// =============================================================================
+
+// TestConfigPreHookBlocks tests that a failing pre-flow-config hook prevents the change.
+func TestConfigPreHookBlocks(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	createHookScript(t, dir, "pre-flow-config", `#!/bin/sh
+if [ "$CONFIG_CHANGE" = "delete-topic" ] && [ "$CONFIG_BRANCH" = "release" ]; then
+	echo "Error: release branches are required" >&2
+	exit 1
+fi
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "delete", "topic", "release")
+	if err == nil {
+		t.Fatalf("Expected config delete to fail when pre-hook fails, got: %s", output)
+	}
+	if !strings.Contains(output, "release branches are required") {
+		t.Errorf("Expected hook output in error, got: %s", output)
+	}
+
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.release.type"); strings.TrimSpace(value) != "topic" {
+		t.Errorf("Expected release to stay configured, got type %q", value)
+	}
+}
+
+// TestConfigPostHookReceivesChange tests that post-flow-config describes the change.
+func TestConfigPostHookReceivesChange(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	markerFile := filepath.Join(dir, "config-hook.txt")
+	createHookScript(t, dir, "post-flow-config", `#!/bin/sh
+echo "$1 $2 $3 EXIT_CODE=$EXIT_CODE" >> "`+markerFile+`"
+grep -q "staging:" "$CONFIG_FILE" && echo "file has staging" >> "`+markerFile+`"
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "add", "base", "staging", "main")
+	if err != nil {
+		t.Fatalf("Failed to add base branch: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "rename", "base", "staging", "qa")
+	if err != nil {
+		t.Fatalf("Failed to rename base branch: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Post-hook did not run: %v", err)
+	}
+	expected := "add-base staging  EXIT_CODE=0\nfile has staging\nrename-base qa staging EXIT_CODE=0\n"
+	if string(content) != expected {
+		t.Errorf("Expected hook markers %q, got %q", expected, string(content))
+	}
+}
+
+// TestConfigDryRunSkipsHooks tests that --dry-run changes nothing and runs no config hooks.
+func TestConfigDryRunSkipsHooks(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	createHookScript(t, dir, "pre-flow-config", `#!/bin/sh
+exit 1
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "config", "add", "base", "staging", "main", "--dry-run")
+	if err != nil {
+		t.Fatalf("Expected dry run to skip the failing hook: %v\nOutput: %s", err, output)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd_test
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -248,6 +249,87 @@ echo "EXIT_CODE=$EXIT_CODE" > "` + markerFile + `"
 	}
 }
 
+// TestFinishPostHookReceivesResult tests that the post-finish hook receives the tag,
+// the merge commit and the updated child branches.
+func TestFinishPostHookReceivesResult(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+
+	_, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v", err)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release")
+	_, _ = testutil.RunGit(t, dir, "add", "release.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Prepare release")
+
+	markerFile := filepath.Join(dir, "post-finish-result.txt")
+	jsonFile := filepath.Join(dir, "post-finish-result.json")
+	script := `#!/bin/sh
+echo "TAG_NAME=$TAG_NAME" > "` + markerFile + `"
+echo "MERGE_COMMIT=$MERGE_COMMIT" >> "` + markerFile + `"
+echo "UPDATED_BRANCHES=$UPDATED_BRANCHES" >> "` + markerFile + `"
+echo "UPDATED_BRANCH_STRATEGIES=$UPDATED_BRANCH_STRATEGIES" >> "` + markerFile + `"
+printf '%s' "$GITFLOW_RESULT_JSON" > "` + jsonFile + `"
+`
+	createHookScript(t, dir, "post-flow-release-finish", script)
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	mainCommit, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	developCommit, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	mainCommit = strings.TrimSpace(mainCommit)
+	developCommit = strings.TrimSpace(developCommit)
+
+	content, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Post-hook did not run - marker file not found: %v", err)
+	}
+	for _, expected := range []string{
+		"TAG_NAME=1.0.0",
+		"MERGE_COMMIT=" + mainCommit,
+		"UPDATED_BRANCHES=develop",
+		"UPDATED_BRANCH_STRATEGIES=develop:merge",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %s in hook output, got: %s", expected, string(content))
+		}
+	}
+
+	data, err := os.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatalf("Failed to read hook JSON: %v", err)
+	}
+	var result struct {
+		Branch          string `json:"branch"`
+		BaseBranch      string `json:"baseBranch"`
+		Tag             string `json:"tag"`
+		MergeCommit     string `json:"mergeCommit"`
+		UpdatedBranches []struct {
+			Name     string `json:"name"`
+			Strategy string `json:"strategy"`
+			Commit   string `json:"commit"`
+		} `json:"updatedBranches"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Expected GITFLOW_RESULT_JSON to be valid JSON: %v\n%s", err, string(data))
+	}
+	if result.Branch != "release/1.0.0" || result.BaseBranch != "main" || result.Tag != "1.0.0" || result.MergeCommit != mainCommit {
+		t.Errorf("Unexpected finish result: %+v", result)
+	}
+	if len(result.UpdatedBranches) != 1 || result.UpdatedBranches[0].Name != "develop" || result.UpdatedBranches[0].Commit != developCommit {
+		t.Errorf("Expected develop as updated branch at %s, got: %+v", developCommit, result.UpdatedBranches)
+	}
+}
+
 // =============================================================================
 // Version Filter Tests - Verify filters modify branch names
 // =============================================================================
@@ -820,6 +902,67 @@ echo "BASE_BRANCH=$BASE_BRANCH" >> "` + markerFile + `"
 	}
 }
 
+// TestCustomTopicTypeHooks tests the type-specific and generic topic hooks of a custom type.
+// Steps:
+// 1. Sets up a test repository and adds a custom 'spike' topic type
+// 2. Creates pre-flow-spike-start and a generic post-flow-topic-start hook
+// 3. Starts a spike branch
+// 4. Verifies both hooks ran and the generic hook received the spike type
+// 5. Verifies a type-specific post hook takes precedence over the generic one
+func TestCustomTopicTypeHooks(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "config", "add", "topic", "spike", "develop", "--prefix=spike/")
+	if err != nil {
+		t.Fatalf("Failed to add topic type: %v\nOutput: %s", err, output)
+	}
+
+	markerFile := filepath.Join(dir, "topic-hooks.txt")
+	createHookScript(t, dir, "pre-flow-spike-start", `#!/bin/sh
+echo "pre-spike $1" >> "`+markerFile+`"
+`)
+	createHookScript(t, dir, "post-flow-topic-start", `#!/bin/sh
+echo "post-topic $BRANCH_TYPE $BRANCH" >> "`+markerFile+`"
+`)
+
+	output, err = testutil.RunGitFlow(t, dir, "spike", "start", "cache")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch: %v\nOutput: %s", err, output)
+	}
+
+	content, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Hooks did not run: %v", err)
+	}
+	expected := "pre-spike cache\npost-topic spike spike/cache\n"
+	if string(content) != expected {
+		t.Errorf("Expected hook markers %q, got %q", expected, string(content))
+	}
+
+	// A type-specific hook replaces the generic one for that type
+	createHookScript(t, dir, "post-flow-spike-start", `#!/bin/sh
+echo "post-spike $1" >> "`+markerFile+`"
+`)
+	output, err = testutil.RunGitFlow(t, dir, "start", "spike", "queue")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch: %v\nOutput: %s", err, output)
+	}
+
+	content, err = os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Failed to read marker file: %v", err)
+	}
+	expected += "pre-spike queue\npost-spike queue\n"
+	if string(content) != expected {
+		t.Errorf("Expected hook markers %q, got %q", expected, string(content))
+	}
+}
+
 // =============================================================================
 // Update Hook Tests - Verify hooks run for update operations
 // =============================================================================
@@ -1314,3 +1457,182 @@ fi
 		t.Error("hotfix/1.0.1 should not exist - filter should have changed it to hotfix-1.0.1")
 	}
 }
+
+// =============================================================================
+// Notifier Plugin Tests
+// =============================================================================
+
+// TestNotifierPluginReceivesEvents tests that a gitflow-notify-* plugin on PATH is
+// notified of start and finish without any hooks installed in the repository.
+// Steps:
+// 1. Places a notifier plugin that appends each payload to a log on PATH
+// 2. Starts and finishes a feature branch
+// 3. Verifies the plugin received feature.start and feature.finish with the finish result
+func TestNotifierPluginReceivesEvents(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	binDir := t.TempDir()
+	logFile := filepath.Join(t.TempDir(), "events.log")
+	script := `#!/bin/sh
+cat >> "` + logFile + `"
+echo >> "` + logFile + `"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "gitflow-notify-log"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "notify-me"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "notify.txt", "content")
+	testutil.RunGit(t, dir, "add", "notify.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add notify file")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "notify-me"); err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Expected notifier to run: %v", err)
+	}
+	events := map[string]map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &payload); err != nil {
+			t.Fatalf("Expected JSON payload, got %q: %v", line, err)
+		}
+		events[payload["event"].(string)] = payload
+	}
+
+	for _, name := range []string{"init", "feature.start", "feature.finish"} {
+		if _, ok := events[name]; !ok {
+			t.Errorf("Expected %s event, got %v", name, events)
+		}
+	}
+	finish := events["feature.finish"]
+	if finish == nil || finish["branch"] != "feature/notify-me" || finish["success"] != true {
+		t.Fatalf("Unexpected finish event: %v", finish)
+	}
+	result, ok := finish["finish"].(map[string]interface{})
+	if !ok || result["mergeCommit"] != strings.TrimSpace(testutil.RevParse(t, dir, "develop")) {
+		t.Errorf("Expected finish result with the merge commit, got %v", finish["finish"])
+	}
+}
+
+// =============================================================================
+// Config Response Tests - Verify hooks can amend the configuration of a run
+// =============================================================================
+
+// TestFinishPreHookAmendsTagPrefix tests that a pre-finish hook can change the tag prefix of one finish.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and enables gitflow.hooks.allowConfigWrite
+// 2. Creates a pre-hook writing gitflow.branch.release.tagprefix to GITFLOW_CONFIG_RESPONSE
+// 3. Starts and finishes a release
+// 4. Verifies the tag carries the amended prefix and the stored tag prefix is unchanged
+func TestFinishPreHookAmendsTagPrefix(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.hooks.allowConfigWrite", "true")
+
+	script := `#!/bin/sh
+[ "$GITFLOW_ALLOW_CONFIG_WRITE" = "1" ] || exit 1
+echo "gitflow.branch.release.tagprefix=rel-" >> "$GITFLOW_CONFIG_RESPONSE"
+`
+	createHookScript(t, dir, "pre-flow-release-finish", script)
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	tags, _ := testutil.RunGit(t, dir, "tag", "-l")
+	if strings.TrimSpace(tags) != "rel-1.0.0" {
+		t.Errorf("Expected the tag rel-1.0.0, got: %s", tags)
+	}
+	if prefix, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.release.tagprefix"); strings.TrimSpace(prefix) == "rel-" {
+		t.Error("Expected the amendment not to be written to Git configuration")
+	}
+}
+
+// TestPreHookConfigResponseRequiresOptIn tests that hooks are only offered a response file with gitflow.hooks.allowConfigWrite.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Creates a pre-start hook recording GITFLOW_ALLOW_CONFIG_WRITE
+// 3. Starts a feature and verifies the variable was not set
+// 4. Enables gitflow.hooks.allowConfigWrite, starts another feature and verifies it was set
+func TestPreHookConfigResponseRequiresOptIn(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	logFile := filepath.Join(t.TempDir(), "allow.log")
+	script := `#!/bin/sh
+echo "allow=$GITFLOW_ALLOW_CONFIG_WRITE" >> "` + logFile + `"
+`
+	createHookScript(t, dir, "pre-flow-feature-start", script)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "one"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.hooks.allowConfigWrite", "true")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "two"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Expected the hook to run: %v", err)
+	}
+	if string(data) != "allow=\nallow=1\n" {
+		t.Errorf("Expected GITFLOW_ALLOW_CONFIG_WRITE only with the opt-in, got: %q", string(data))
+	}
+}
+
+// TestPreHookInvalidConfigResponseBlocks tests that an invalid response stops the operation.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and enables gitflow.hooks.allowConfigWrite
+// 2. Creates a pre-finish hook writing an unknown key to the response file
+// 3. Starts and tries to finish a feature
+// 4. Verifies finish fails naming the key and the feature branch still exists
+func TestPreHookInvalidConfigResponseBlocks(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.hooks.allowConfigWrite", "true")
+
+	script := `#!/bin/sh
+echo "gitflow.feature.finish.bogus=true" >> "$GITFLOW_CONFIG_RESPONSE"
+`
+	createHookScript(t, dir, "pre-flow-feature-finish", script)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	if err == nil {
+		t.Fatalf("Expected finish to fail on the invalid response\nOutput: %s", output)
+	}
+	if !strings.Contains(output, "gitflow.feature.finish.bogus") {
+		t.Errorf("Expected the error to name the key, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "feature/login"); err != nil {
+		t.Error("Expected the feature branch to be kept")
+	}
+}
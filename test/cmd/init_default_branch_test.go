@@ -0,0 +1,137 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupMasterRepo creates a test repository whose only branch is master
+func setupMasterRepo(t *testing.T) string {
+	t.Helper()
+	dir := testutil.SetupTestRepo(t)
+	if _, err := testutil.RunGit(t, dir, "branch", "-M", "master"); err != nil {
+		t.Fatalf("Failed to rename main to master: %v", err)
+	}
+	return dir
+}
+
+// TestInitDefaultsAdoptsMaster tests that init --defaults uses an existing
+// master branch as main branch instead of creating main next to it.
+// Steps:
+// 1. Creates a repository whose default branch is master
+// 2. Runs 'git flow init --defaults'
+// 3. Verifies master is configured as base branch and main was not created
+// 4. Verifies develop and the topic types use master as parent
+func TestInitDefaultsAdoptsMaster(t *testing.T) {
+	dir := setupMasterRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Init failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Using existing branch 'master' instead of 'main'") {
+		t.Errorf("Expected the adoption to be reported, got: %s", output)
+	}
+
+	if testutil.BranchExists(t, dir, "main") {
+		t.Error("Expected no main branch to be created")
+	}
+	if value := getGitConfig(t, dir, "gitflow.branch.master.type"); value != "base" {
+		t.Errorf("Expected master to be a base branch, got %q", value)
+	}
+	if value := getGitConfig(t, dir, "gitflow.branch.main.type"); value != "" {
+		t.Errorf("Expected no configuration for main, got type %q", value)
+	}
+	for _, key := range []string{"gitflow.branch.develop.parent", "gitflow.branch.hotfix.parent", "gitflow.branch.release.parent"} {
+		if value := getGitConfig(t, dir, key); value != "master" {
+			t.Errorf("Expected %s to be master, got %q", key, value)
+		}
+	}
+}
+
+// TestInitAdoptsRemoteDefaultBranch tests that init uses the branch the
+// remote's HEAD points at when main does not exist.
+// Steps:
+// 1. Creates a repository with a remote whose default branch is trunk
+// 2. Runs 'git flow init --defaults'
+// 3. Verifies trunk is used as main branch and develop starts from it
+func TestInitAdoptsRemoteDefaultBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", false)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	for _, args := range [][]string{
+		{"branch", "-M", "trunk"},
+		{"push", "origin", "trunk"},
+		{"remote", "set-head", "origin", "trunk"},
+	} {
+		if output, err := testutil.RunGit(t, dir, args...); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Init failed: %v\nOutput: %s", err, output)
+	}
+
+	if testutil.BranchExists(t, dir, "main") {
+		t.Error("Expected no main branch to be created")
+	}
+	if value := getGitConfig(t, dir, "gitflow.branch.develop.parent"); value != "trunk" {
+		t.Errorf("Expected develop to use trunk as parent, got %q", value)
+	}
+}
+
+// TestInitNoAdoptDefaultBranch tests that --no-adopt-default-branch keeps the
+// preset's main branch even if the repository uses master.
+// Steps:
+// 1. Creates a repository whose default branch is master
+// 2. Runs 'git flow init --defaults --no-adopt-default-branch'
+// 3. Verifies main is created and configured next to master
+func TestInitNoAdoptDefaultBranch(t *testing.T) {
+	dir := setupMasterRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults", "--no-adopt-default-branch")
+	if err != nil {
+		t.Fatalf("Init failed: %v\nOutput: %s", err, output)
+	}
+
+	if !testutil.BranchExists(t, dir, "main") {
+		t.Error("Expected main to be created")
+	}
+	if value := getGitConfig(t, dir, "gitflow.branch.develop.parent"); value != "main" {
+		t.Errorf("Expected develop to use main as parent, got %q", value)
+	}
+}
+
+// TestInitExplicitMainSkipsAdoption tests that an explicit --main is used
+// as given even if the repository uses master.
+// Steps:
+// 1. Creates a repository whose default branch is master
+// 2. Runs 'git flow init --defaults --main production'
+// 3. Verifies production is created and master stays unconfigured
+func TestInitExplicitMainSkipsAdoption(t *testing.T) {
+	dir := setupMasterRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults", "--main", "production")
+	if err != nil {
+		t.Fatalf("Init failed: %v\nOutput: %s", err, output)
+	}
+
+	if !testutil.BranchExists(t, dir, "production") {
+		t.Error("Expected production to be created")
+	}
+	if value := getGitConfig(t, dir, "gitflow.branch.master.type"); value != "" {
+		t.Errorf("Expected master to stay unconfigured, got type %q", value)
+	}
+}
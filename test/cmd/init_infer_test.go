@@ -0,0 +1,111 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupEstablishedRepo creates a repository with the conventions of a team
+// that has not used git-flow yet: master and dev, feat/ branches that were
+// merged and deleted, and v-prefixed release tags
+func setupEstablishedRepo(t *testing.T) string {
+	t.Helper()
+	dir := testutil.SetupTestRepo(t)
+	testutil.RunGit(t, dir, "branch", "-m", "main", "master")
+	testutil.RunGit(t, dir, "tag", "v1.0.0")
+	testutil.RunGit(t, dir, "checkout", "-b", "dev")
+	for _, branch := range []string{"feat/login", "feat/search"} {
+		testutil.RunGit(t, dir, "checkout", "-b", branch, "dev")
+		commitOn(t, dir, branch, strings.TrimPrefix(branch, "feat/")+".txt", branch)
+		testutil.RunGit(t, dir, "checkout", "dev")
+		testutil.RunGit(t, dir, "merge", "--no-ff", "--no-edit", branch)
+		testutil.RunGit(t, dir, "branch", "-d", branch)
+	}
+	return dir
+}
+
+// TestInitInferFromHistory tests that init --infer saves the confirmed inferred model.
+// Steps:
+// 1. Sets up a repository using master, dev, merged feat/ branches and v tags
+// 2. Runs 'git flow init --infer' and confirms the inferred model
+// 3. Verifies master and dev are the base branches, feature uses feat/ and tags use v
+// 4. Verifies no develop branch was created
+func TestInitInferFromHistory(t *testing.T) {
+	dir := setupEstablishedRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "y\n", "init", "--infer")
+	if err != nil {
+		t.Fatalf("Failed to initialize from the inferred model: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"main branch: 'master' (existing branch)", "feature prefix: 'feat/' (2 branch(es))", "tag prefix: 'v' (1 of 1 version tags)"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the findings to contain %q, got: %s", expected, output)
+		}
+	}
+
+	expected := map[string]string{
+		"gitflow.branch.master.type":       "base",
+		"gitflow.branch.dev.parent":        "master",
+		"gitflow.branch.feature.parent":    "dev",
+		"gitflow.branch.feature.prefix":    "feat/",
+		"gitflow.branch.release.tagprefix": "v",
+	}
+	for key, value := range expected {
+		actual, _ := testutil.RunGit(t, dir, "config", "--get", key)
+		if strings.TrimSpace(actual) != value {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, value, strings.TrimSpace(actual))
+		}
+	}
+	if testutil.BranchExists(t, dir, "develop") {
+		t.Error("Expected no develop branch to be created")
+	}
+}
+
+// TestInitInferCanBeDeclined tests that nothing is saved when the inferred model is declined.
+// Steps:
+// 1. Sets up a repository using master, dev, merged feat/ branches and v tags
+// 2. Runs 'git flow init --infer' and declines the inferred model
+// 3. Verifies git-flow is not initialized
+// 4. Runs 'git flow init --infer --defaults' and verifies it saves without asking
+func TestInitInferCanBeDeclined(t *testing.T) {
+	dir := setupEstablishedRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlowWithInput(t, dir, "n\n", "init", "--infer")
+	if err != nil {
+		t.Fatalf("Expected declining to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Initialization cancelled.") {
+		t.Errorf("Expected the initialization to be cancelled, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature.prefix"); strings.TrimSpace(value) != "" {
+		t.Errorf("Expected nothing to be saved, got feature prefix '%s'", strings.TrimSpace(value))
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "init", "--infer", "--defaults")
+	if err != nil {
+		t.Fatalf("Expected --defaults to accept the inferred model: %v\nOutput: %s", err, output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature.prefix"); strings.TrimSpace(value) != "feat/" {
+		t.Errorf("Expected feature prefix 'feat/', got '%s'", strings.TrimSpace(value))
+	}
+}
+
+// TestInitInferRejectsOtherOptions tests that --infer cannot be combined with options that choose the model.
+// Steps:
+// 1. Sets up a test repository
+// 2. Runs 'git flow init --infer --preset github' and verifies it fails with exit code 2
+func TestInitInferRejectsOtherOptions(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--infer", "--preset", "github")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "--infer cannot be combined with --preset") {
+		t.Errorf("Expected the conflicting options to be named, got: %s", output)
+	}
+}
@@ -0,0 +1,207 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// templateConfig is a minimal branching model used as template configuration
+const templateConfig = `version: "1.0"
+branches:
+  main:
+    type: base
+    upstreamStrategy: none
+    downstreamStrategy: none
+  trunk:
+    type: base
+    parent: main
+    upstreamStrategy: merge
+    downstreamStrategy: merge
+    autoUpdate: true
+  feature:
+    type: topic
+    parent: trunk
+    startPoint: trunk
+    upstreamStrategy: merge
+    downstreamStrategy: rebase
+    prefix: feat/
+settings:
+  feature.finish.squash: "true"
+`
+
+// createTemplate writes a template directory with a configuration file, a
+// pre-start hook and a README that must not be installed
+func createTemplate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "hooks"), 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+	files := map[string]string{
+		"gitflow.yml":                  templateConfig,
+		"hooks/pre-flow-feature-start": "#!/bin/sh\nexit 0\n",
+		"hooks/README":                 "Hooks of the template\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+// TestInitTemplateFromDirectory tests initializing git-flow from a local template directory.
+// Steps:
+// 1. Creates a template directory with a configuration file and a hook script
+// 2. Runs 'git flow init --template <dir>' in a fresh repository
+// 3. Verifies the branch model and settings from the template are configured
+// 4. Verifies the base branches were created
+// 5. Verifies only the hook script was installed, and that it is executable
+func TestInitTemplateFromDirectory(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	template := createTemplate(t)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", template)
+	if err != nil {
+		t.Fatalf("Failed to initialize from template: %v\nOutput: %s", err, output)
+	}
+
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.prefix"); strings.TrimSpace(value) != "feat/" {
+		t.Errorf("Expected feature prefix 'feat/', got %q", value)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.feature.finish.squash"); strings.TrimSpace(value) != "true" {
+		t.Errorf("Expected template setting to be applied, got %q", value)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.develop.type"); err == nil {
+		t.Error("Expected no develop branch type outside the template")
+	}
+	if !testutil.BranchExists(t, dir, "trunk") {
+		t.Error("Expected base branch 'trunk' to be created")
+	}
+
+	info, err := os.Stat(filepath.Join(dir, ".git", "hooks", "pre-flow-feature-start"))
+	if err != nil {
+		t.Fatalf("Expected hook to be installed: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Error("Expected installed hook to be executable")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "hooks", "README")); err == nil {
+		t.Error("Expected non-hook files to be skipped")
+	}
+}
+
+// TestInitTemplateFromGitURL tests initializing git-flow from a template repository.
+// Steps:
+// 1. Commits a template into a separate repository
+// 2. Runs 'git flow init --template file://<repo>' in a fresh repository
+// 3. Verifies the template configuration and hook were applied
+func TestInitTemplateFromGitURL(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	templateRepo := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, templateRepo)
+	if err := os.MkdirAll(filepath.Join(templateRepo, "hooks"), 0755); err != nil {
+		t.Fatalf("Failed to create hooks directory: %v", err)
+	}
+	testutil.WriteFile(t, templateRepo, "gitflow.yml", templateConfig)
+	testutil.WriteFile(t, templateRepo, "hooks/pre-flow-feature-start", "#!/bin/sh\nexit 0\n")
+	testutil.RunGit(t, templateRepo, "add", ".")
+	testutil.RunGit(t, templateRepo, "commit", "-m", "Add template")
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", "file://"+filepath.ToSlash(templateRepo))
+	if err != nil {
+		t.Fatalf("Failed to initialize from template: %v\nOutput: %s", err, output)
+	}
+
+	if value, _ := testutil.RunGit(t, dir, "config", "gitflow.branch.trunk.type"); strings.TrimSpace(value) != "base" {
+		t.Errorf("Expected base branch 'trunk' to be configured, got %q", value)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "hooks", "pre-flow-feature-start")); err != nil {
+		t.Errorf("Expected hook to be installed: %v", err)
+	}
+}
+
+// TestInitTemplateHookConflict tests that differing existing hooks stop the template without --force.
+// Steps:
+// 1. Creates a template and a repository with a different pre-flow-feature-start hook
+// 2. Runs 'git flow init --template <dir>' and verifies it fails without changing anything
+// 3. Runs it again with --force and verifies the hook is replaced
+func TestInitTemplateHookConflict(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	template := createTemplate(t)
+	createHookScript(t, dir, "pre-flow-feature-start", "#!/bin/sh\necho local\n")
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", template)
+	if err == nil {
+		t.Fatalf("Expected init to fail, got: %s", output)
+	}
+	if !strings.Contains(output, "pre-flow-feature-start") {
+		t.Errorf("Expected error to name the conflicting hook, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.trunk.type"); err == nil {
+		t.Error("Expected no configuration to be written")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "init", "--template", template, "--force")
+	if err != nil {
+		t.Fatalf("Failed to initialize with --force: %v\nOutput: %s", err, output)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, ".git", "hooks", "pre-flow-feature-start"))
+	if string(content) != "#!/bin/sh\nexit 0\n" {
+		t.Errorf("Expected hook to be replaced, got %q", content)
+	}
+}
+
+// TestInitTemplateWithoutConfig tests that a template without a configuration file is rejected.
+// Steps:
+// 1. Creates a directory that only holds a hooks directory
+// 2. Runs 'git flow init --template <dir>'
+// 3. Verifies the command fails and git-flow stays uninitialized
+func TestInitTemplateWithoutConfig(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	template := createTemplate(t)
+	if err := os.Remove(filepath.Join(template, "gitflow.yml")); err != nil {
+		t.Fatalf("Failed to remove configuration: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", template)
+	if err == nil {
+		t.Fatalf("Expected init to fail, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.initialized"); err == nil {
+		t.Error("Expected git-flow to stay uninitialized")
+	}
+}
+
+// TestInitTemplateRejectsOtherOptions tests that --template cannot be combined with other configuration options.
+// Steps:
+// 1. Runs 'git flow init --template <dir> --preset github'
+// 2. Verifies the command fails with an invalid input exit code
+func TestInitTemplateRejectsOtherOptions(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	template := createTemplate(t)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", template, "--preset", "github")
+	if err == nil {
+		t.Fatalf("Expected init to fail, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d, got: %v", errors.ExitCodeInvalidInput, err)
+	}
+}
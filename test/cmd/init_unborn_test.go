@@ -0,0 +1,108 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupUnbornRepo creates a repository without any commits whose HEAD points
+// at an unborn master branch
+func setupUnbornRepo(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "git-flow-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "--initial-branch=master"},
+		{"config", "user.name", "Test User"},
+		{"config", "user.email", "test@example.com"},
+	} {
+		if output, err := testutil.RunGit(t, dir, args...); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	return dir
+}
+
+// TestInitUnbornRepoCreatesEmptyCommit tests that init in a repository without
+// commits creates an empty root commit and leaves staged files alone.
+// Steps:
+// 1. Creates a repository without commits and stages a file
+// 2. Runs 'git flow init --defaults'
+// 3. Verifies main has a single empty commit and develop starts from it
+// 4. Verifies develop is checked out and the file is still staged
+func TestInitUnbornRepoCreatesEmptyCommit(t *testing.T) {
+	dir := setupUnbornRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if err := testutil.WriteFile(t, dir, "work.txt", "work in progress"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.RunGit(t, dir, "add", "work.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Init failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Created initial empty commit on 'main'") {
+		t.Errorf("Expected the initial commit to be reported, got: %s", output)
+	}
+
+	count, _ := testutil.RunGit(t, dir, "rev-list", "--count", "main")
+	if strings.TrimSpace(count) != "1" {
+		t.Errorf("Expected main to have one commit, got %s", count)
+	}
+	files, _ := testutil.RunGit(t, dir, "ls-tree", "-r", "--name-only", "main")
+	if strings.TrimSpace(files) != "" {
+		t.Errorf("Expected the initial commit to be empty, got files: %s", files)
+	}
+	mainTip, _ := testutil.RunGit(t, dir, "rev-parse", "main")
+	developTip, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if mainTip != developTip {
+		t.Errorf("Expected develop to start at main, got %s and %s", developTip, mainTip)
+	}
+
+	if current := testutil.GetCurrentBranch(t, dir); current != "develop" {
+		t.Errorf("Expected develop to be checked out, got %s", current)
+	}
+	status, _ := testutil.RunGit(t, dir, "status", "--porcelain")
+	if !strings.Contains(status, "A  work.txt") {
+		t.Errorf("Expected work.txt to stay staged, got status: %s", status)
+	}
+}
+
+// TestInitUnbornRepoCreateCommitDisabled tests that init refuses to run in a
+// repository without commits when gitflow.init.createCommit is false.
+// Steps:
+// 1. Creates a repository without commits
+// 2. Sets gitflow.init.createCommit to false
+// 3. Runs 'git flow init --defaults' and verifies it fails with exit code 2
+// 4. Verifies no commit was created and git-flow is not marked initialized
+func TestInitUnbornRepoCreateCommitDisabled(t *testing.T) {
+	dir := setupUnbornRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.init.createCommit", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "gitflow.init.createCommit") {
+		t.Errorf("Expected the error to name the setting, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "rev-parse", "--verify", "HEAD"); err == nil {
+		t.Error("Expected the repository to stay without commits")
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.initialized"); err == nil {
+		t.Error("Expected git-flow not to be marked initialized")
+	}
+}
@@ -0,0 +1,138 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestInspectTopicBranch tests the effective settings reported for a topic branch.
+// Steps:
+// 1. Sets up a test repository and starts a feature from 'main' instead of 'develop'
+// 2. Configures finish to squash and to use the stored base
+// 3. Publishes the feature and runs inspect on it
+// 4. Verifies type, name, stored base, finish target, strategy and published state
+func TestInspectTopicBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "login", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.squash", "true")
+	testutil.RunGit(t, dir, "config", "gitflow.finish.baseResolution", "stored")
+
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", false)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "login"); err != nil {
+		t.Fatalf("Failed to publish feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "inspect", "feature/login")
+	if err != nil {
+		t.Fatalf("Failed to inspect branch: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"Type:                feature (topic branch)",
+		"Name:                login",
+		"Prefix:              feature/",
+		"Configured parent:   develop",
+		"Stored base:         main",
+		"Finish target:       main (stored base)",
+		"Upstream strategy:   squash",
+		"Tagging:             no",
+		"Published:           yes, tracking origin/feature/login (up to date)",
+		"Pending operation:   none",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+// TestInspectBaseBranch tests inspecting a base branch and the current branch default.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs inspect without arguments on 'develop'
+// 3. Verifies parent, strategies and the dependent branch types are reported
+func TestInspectBaseBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "inspect")
+	if err != nil {
+		t.Fatalf("Failed to inspect branch: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"Branch:              develop",
+		"Type:                base branch",
+		"Parent:              main",
+		"Auto-update:         yes",
+		"Children:            bugfix (topic), feature (topic)",
+		"Published:           no",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "inspect", "feature/missing")
+	if exitErr, ok := err.(*testutil.ExitError); !ok || exitErr.ExitCode != int(errors.ExitCodeBranchNotFound) {
+		t.Errorf("Expected a missing branch to fail with exit code %d, got %v: %s", errors.ExitCodeBranchNotFound, err, output)
+	}
+}
+
+// TestInspectPendingFinish tests that a finish stopped by a conflict is reported.
+// Steps:
+// 1. Sets up a test repository and creates a conflicting change on a feature and on develop
+// 2. Finishes the feature, which stops at the merge conflict
+// 3. Verifies inspect reports the feature and develop as involved in the finish
+func TestInspectPendingFinish(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "clash"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "shared.txt", "feature")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Feature change")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "shared.txt", "develop")
+	testutil.RunGit(t, dir, "add", "shared.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop change")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "clash"); err == nil {
+		t.Fatalf("Expected finish to stop at the conflict, got: %s", output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "inspect", "feature/clash")
+	if err != nil {
+		t.Fatalf("Failed to inspect branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Pending operation:   being finished into develop, stopped at step 'merge'") {
+		t.Errorf("Expected the pending finish to be reported, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "inspect", "develop")
+	if err != nil {
+		t.Fatalf("Failed to inspect branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Pending operation:   target of the finish of feature/clash") {
+		t.Errorf("Expected develop to be reported as the finish target, got: %s", output)
+	}
+}
@@ -0,0 +1,185 @@
+//go:build unix
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishInterruptedDuringMerge tests that a Ctrl-C in the terminal during a merge lets the merge complete.
+// Steps:
+// 1. Sets up a test repository and creates a release branch with a commit
+// 2. Installs a pre-merge-commit hook that holds the first merge commit until the test signals
+// 3. Starts finish in its own process group and waits until the merge of main into develop is running
+// 4. Sends SIGINT to the whole process group, as a terminal does on Ctrl-C, and releases the hook
+// 5. Verifies finish exits with the interrupted exit code after the merge into develop completed
+// 6. Verifies the saved state resumes at 'update_children'
+// 7. Runs finish --continue and verifies the release branch is deleted
+func TestFinishInterruptedDuringMerge(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+
+	markers := t.TempDir()
+	merging := filepath.Join(markers, "merging")
+	signalled := filepath.Join(markers, "signalled")
+	script := fmt.Sprintf(`#!/bin/sh
+[ -e %[1]q ] && exit 0
+touch %[1]q
+for i in $(seq 100); do
+	[ -e %[2]q ] && exit 0
+	sleep 0.1
+done
+exit 1
+`, merging, signalled)
+	createHookScript(t, dir, "pre-merge-commit", script)
+
+	var buffer bytes.Buffer
+	cmd := exec.Command(testutil.GitFlowPath(), "release", "finish", "1.0.0")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=:")
+	cmd.Stdout = &buffer
+	cmd.Stderr = &buffer
+	// Like a shell job, git-flow leads its own process group
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start finish: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(merging); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			cmd.Wait()
+			t.Fatalf("Merge did not start, output: %s", buffer.String())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGINT); err != nil {
+		t.Fatalf("Failed to signal the process group: %v", err)
+	}
+	// Give git-flow time to receive the signal before the merge completes
+	time.Sleep(200 * time.Millisecond)
+	if err := os.WriteFile(signalled, nil, 0644); err != nil {
+		t.Fatalf("Failed to release the hook: %v", err)
+	}
+
+	err = cmd.Wait()
+	output = buffer.String()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("Expected finish to be interrupted, got error %v and output: %s", err, output)
+	}
+	if exitErr.ExitCode() != int(errors.ExitCodeInterrupted) {
+		t.Errorf("Expected exit code %d, got %d\nOutput: %s", errors.ExitCodeInterrupted, exitErr.ExitCode(), output)
+	}
+	for _, expected := range []string{
+		"Received SIGINT, stopping after the current step",
+		"interrupted by SIGINT",
+		"git flow release finish --continue 1.0.0",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	// main is fast-forwarded, so the held merge is the update of develop
+	if _, err := testutil.RunGit(t, dir, "show", "develop:release.txt"); err != nil {
+		t.Errorf("Expected the running merge into develop to complete, output: %s", output)
+	}
+	if tags, _ := testutil.RunGit(t, dir, "tag", "-l", "1.0.0"); !strings.Contains(tags, "1.0.0") {
+		t.Error("Expected the tag to be created before the interruption")
+	}
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil || state == nil {
+		t.Fatalf("Expected saved merge state: %v", err)
+	}
+	if state.CurrentStep != "update_children" {
+		t.Errorf("Expected CurrentStep to be 'update_children', got: %s", state.CurrentStep)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release branch to be deleted after continuing")
+	}
+	if state, _ := testutil.LoadMergeState(t, dir); state != nil {
+		t.Error("Expected merge state to be cleared after continuing")
+	}
+}
+
+// TestUpdateInterruptedBeforeMerge tests that a signal before the update merge leaves the branch untouched.
+// Steps:
+// 1. Sets up a test repository and creates a feature branch
+// 2. Adds a commit to develop and installs a pre-update hook that sends SIGINT to git-flow
+// 3. Runs update and verifies it exits with the interrupted exit code
+// 4. Verifies the feature branch does not contain the develop commit
+func TestUpdateInterruptedBeforeMerge(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "paused")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop.txt", "develop content")
+	testutil.RunGit(t, dir, "add", "develop.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop change")
+	testutil.RunGit(t, dir, "checkout", "feature/paused")
+
+	createHookScript(t, dir, "pre-flow-feature-update", "#!/bin/sh\nkill -INT $PPID\nsleep 1\nexit 0\n")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update", "paused")
+	if err == nil {
+		t.Fatalf("Expected update to be interrupted, got output: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeInterrupted) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeInterrupted, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+	if !strings.Contains(output, "interrupted by SIGINT before any changes were made") {
+		t.Errorf("Expected interruption message, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "show", "feature/paused:develop.txt"); err == nil {
+		t.Error("Expected feature branch to be left untouched")
+	}
+}
@@ -0,0 +1,88 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestReleaseStartLine tests that release start --line starts and finishes a release on the base of a version line.
+// Steps:
+// 1. Sets up a repository with a support/1.x branch and configures line 1.x on it with tag prefix v
+// 2. Runs release start --line 1.x 1.5.0 and verifies it starts from support/1.x
+// 3. Adds a commit and finishes the release
+// 4. Verifies support/1.x received the release, main did not, and the tag is v1.5.0
+func TestReleaseStartLine(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "support/1.x", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.line.1.x.base", "support/1.x")
+	testutil.RunGit(t, dir, "config", "gitflow.line.1.x.tagprefix", "v")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "start", "--line", "1.x", "1.5.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Created branch 'release/1.5.0' from 'support/1.x'") {
+		t.Errorf("Expected the release to start from support/1.x, got:\n%s", output)
+	}
+	commitOn(t, dir, "release/1.5.0", "fix.txt", "fix")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "1.5.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "(version line 1.x)") {
+		t.Errorf("Expected the base to come from the version line, got:\n%s", output)
+	}
+
+	tagged, _ := testutil.RunGit(t, dir, "rev-parse", "v1.5.0^{commit}")
+	support, _ := testutil.RunGit(t, dir, "rev-parse", "support/1.x")
+	if tagged == "" || tagged != support {
+		t.Errorf("Expected v1.5.0 at the head of support/1.x, got %q and %q", strings.TrimSpace(tagged), strings.TrimSpace(support))
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "support/1.x", "main"); err == nil {
+		t.Error("Expected main not to receive the release of line 1.x")
+	}
+}
+
+// TestStartLineValidation tests that --line refuses unknown lines, versions of other lines and an explicit base.
+// Steps:
+// 1. Sets up a repository and configures line 1.x on support/1.x
+// 2. Verifies release start --line 3.x is refused as an unknown line
+// 3. Verifies hotfix start --line 1.x 2.0.1 is refused as a version of another line
+// 4. Verifies release start --line 1.x with a base argument is refused
+func TestStartLineValidation(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "support/1.x", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.line.1.x.base", "support/1.x")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "start", "--line", "3.x", "3.0.0")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "unknown version line '3.x' (configured: 1.x)") {
+		t.Errorf("Expected the unknown line to be refused, got:\n%s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "hotfix", "start", "--line", "1.x", "2.0.1")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "'2.0.1' is not a version of line 1.x") {
+		t.Errorf("Expected a version of another line to be refused, got:\n%s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "--line", "1.x", "1.6.0", "main")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if branches, _ := testutil.RunGit(t, dir, "branch", "--list", "release/*"); branches != "" {
+		t.Errorf("Expected no release branch to be created, got:\n%s", branches)
+	}
+}
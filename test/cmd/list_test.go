@@ -183,3 +183,66 @@ func TestListEmptyBranches(t *testing.T) {
 		t.Errorf("Expected output to contain 'No feature branches found', got: %s", output)
 	}
 }
+
+// TestListPrefixAliases tests listing local and remote branches named with prefix aliases.
+// Steps:
+// 1. Sets up a test repository with a remote
+// 2. Configures bug/ as a prefix alias of the bugfix type
+// 3. Creates bugfix/login and bug/crash locally and bug/legacy on the remote
+// 4. Verifies 'git flow bugfix list' shows both local branches
+// 5. Verifies 'git flow bugfix list --remote' also shows the remote alias branch
+func TestListPrefixAliases(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.branch.bugfix.prefixaliases", "bug/")
+	testutil.RunGit(t, dir, "branch", "bugfix/login", "develop")
+	testutil.RunGit(t, dir, "branch", "bug/crash", "develop")
+	testutil.RunGit(t, dir, "push", "origin", "develop:refs/heads/bug/legacy")
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "list")
+	if err != nil {
+		t.Fatalf("Failed to list bugfix branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "  login\n") || !strings.Contains(output, "  crash (bug/crash)\n") {
+		t.Errorf("Expected both local branches to be listed, got: %s", output)
+	}
+	if strings.Contains(output, "legacy") {
+		t.Errorf("Expected remote branches to be left out without --remote, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "bugfix", "list", "--remote")
+	if err != nil {
+		t.Fatalf("Failed to list bugfix branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Remote bugfix branches on 'origin':\n  legacy (bug/legacy)\n") {
+		t.Errorf("Expected the remote alias branch to be listed, got: %s", output)
+	}
+}
+
+// TestListBestEffortWithoutInit tests that a topic list infers the prefix of a
+// repository that is not initialized when --best-effort is given.
+// Steps:
+// 1. Sets up a repository without git-flow configuration and feat/ branches
+// 2. Runs 'git flow feature list --best-effort'
+// 3. Verifies the feat/ branches are listed by their short names
+func TestListBestEffortWithoutInit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	for _, branch := range []string{"feat/login", "feat/search"} {
+		if _, err := testutil.RunGit(t, dir, "branch", branch); err != nil {
+			t.Fatalf("Failed to create %s: %v", branch, err)
+		}
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "list", "--best-effort")
+	if err != nil {
+		t.Fatalf("Failed to list feature branches: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "  login\n") || !strings.Contains(output, "  search\n") {
+		t.Errorf("Expected the feat/ branches to be listed, got: %s", output)
+	}
+}
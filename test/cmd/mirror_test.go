@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishPushesToMirrors tests that finish --push also pushes the finished branches to the mirror remotes.
+// Steps:
+// 1. Sets up a repository with a remote, a bare mirror repository as remote 'backup' and mirrors 'backup,gone'
+// 2. Starts a feature branch with a commit and finishes it with --push
+// 3. Verifies develop on the mirror matches the local develop
+// 4. Verifies the summary lists the pushed mirror and the skipped, unconfigured 'gone'
+func TestFinishPushesToMirrors(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	mirrorDir := filepath.Join(t.TempDir(), "backup.git")
+	testutil.RunGit(t, dir, "init", "--bare", mirrorDir)
+	testutil.RunGit(t, dir, "remote", "add", "backup", mirrorDir)
+	testutil.RunGit(t, dir, "config", "gitflow.mirror.remotes", "backup, gone")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "mirrored"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/mirrored", "mirrored.txt", "mirrored")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "mirrored", "--push")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed with an unconfigured optional mirror: %v\nOutput: %s", err, output)
+	}
+
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	mirrored, err := testutil.RunGit(t, mirrorDir, "rev-parse", "refs/heads/develop")
+	if err != nil || mirrored != local {
+		t.Errorf("Expected develop on the mirror at %s, got %s", strings.TrimSpace(local), strings.TrimSpace(mirrored))
+	}
+	for _, expected := range []string{"✓ backup: develop", "! gone: remote 'gone' is not configured"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+// TestFinishRequiredMirrorFails tests that a failed push to a required mirror fails the finish after it completed.
+// Steps:
+// 1. Sets up a repository with a remote and a required mirror 'backup' pointing to a missing repository
+// 2. Starts a feature branch with a commit and finishes it with --push
+// 3. Verifies finish exits with code 3, naming the required mirror and how to push to it
+// 4. Verifies the feature was merged, pushed to origin and deleted nevertheless
+func TestFinishRequiredMirrorFails(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "remote", "add", "backup", filepath.Join(t.TempDir(), "missing.git"))
+	testutil.RunGit(t, dir, "config", "gitflow.mirror.remotes", "backup")
+	testutil.RunGit(t, dir, "config", "gitflow.mirror.backup.required", "true")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "required"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/required", "required.txt", "required")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "required", "--push")
+	assertExitCode(t, err, errors.ExitCodeGitError, output)
+	for _, expected := range []string{"✗ backup:", "required mirror 'backup'", "git push backup develop"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	pushed, _ := testutil.RunGit(t, remoteDir, "rev-parse", "refs/heads/develop")
+	if pushed != local {
+		t.Errorf("Expected develop to be pushed to origin at %s, got %s", strings.TrimSpace(local), strings.TrimSpace(pushed))
+	}
+	if testutil.BranchExists(t, dir, "feature/required") {
+		t.Error("Expected feature/required to be deleted")
+	}
+}
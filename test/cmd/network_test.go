@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// unreachableRemote points origin at a port nothing listens on and makes
+// git-flow retry once, without waiting long
+func unreachableRemote(t *testing.T, dir string) {
+	t.Helper()
+	testutil.RunGit(t, dir, "remote", "set-url", "origin", "http://127.0.0.1:1/repo.git")
+	testutil.RunGit(t, dir, "config", "gitflow.network.retries", "1")
+	testutil.RunGit(t, dir, "config", "gitflow.network.retryDelay", "10ms")
+}
+
+// TestFinishFallsBackWhenRemoteUnreachable tests that finish continues without fetching when the remote cannot be reached.
+// Steps:
+// 1. Sets up a repository with a remote and starts a feature branch with a commit
+// 2. Points origin at an unreachable address and allows one retry
+// 3. Runs feature finish
+// 4. Verifies the fetch was retried, the fallback to --no-fetch was announced and the feature was merged
+func TestFinishFallsBackWhenRemoteUnreachable(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "offline"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/offline", "offline.txt", "offline")
+	unreachableRemote(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "offline")
+	if err != nil {
+		t.Fatalf("Expected finish to succeed without the remote: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"fetch from 'origin' failed (attempt 1); retrying in 10ms",
+		"the remote is unreachable (tried 2 times)",
+		"as with --no-fetch",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "cat-file", "-e", "develop:offline.txt"); err != nil {
+		t.Error("Expected the feature to be merged into develop")
+	}
+}
+
+// TestTrackReportsRemoteFailure tests that track, which needs the remote, fails with a diagnosis of why it could not fetch.
+// Steps:
+// 1. Sets up a repository with a remote and points origin at an unreachable address
+// 2. Verifies feature track fails with exit code 3, naming the unreachable remote with a network hint
+// 3. Points origin at a path without a repository
+// 4. Verifies feature track fails naming the missing repository, without retrying
+func TestTrackReportsRemoteFailure(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	unreachableRemote(t, dir)
+	output, err := testutil.RunGitFlow(t, dir, "feature", "track", "remote-only")
+	assertExitCode(t, err, errors.ExitCodeGitError, output)
+	for _, expected := range []string{"the remote is unreachable", "Hint: Check your network connection"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	testutil.RunGit(t, dir, "remote", "set-url", "origin", remoteDir+"-missing")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "track", "remote-only")
+	assertExitCode(t, err, errors.ExitCodeGitError, output)
+	if !strings.Contains(output, "the repository was not found") {
+		t.Errorf("Expected the missing repository to be named, got:\n%s", output)
+	}
+	if strings.Contains(output, "retrying") {
+		t.Errorf("Expected a missing repository not to be retried, got:\n%s", output)
+	}
+}
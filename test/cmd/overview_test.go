@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/test/testutil"
 )
 
@@ -323,3 +324,51 @@ func TestOverviewWithAVHConfig(t *testing.T) {
 		t.Errorf("Expected output to contain '* feature/test-feature (feature)', got: %s", output)
 	}
 }
+
+// TestOverviewBestEffortWithoutInit tests that overview infers the branch model
+// of a repository that is not initialized when --best-effort is given.
+// Steps:
+// 1. Sets up a repository on master with dev, feat/ and fix/ branches
+// 2. Runs 'git flow overview' and verifies it fails as not initialized
+// 3. Runs 'git flow overview --best-effort'
+// 4. Verifies the inferred base branches, prefixes and active topic branches
+func TestOverviewBestEffortWithoutInit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	for _, args := range [][]string{
+		{"branch", "-M", "master"},
+		{"branch", "dev"},
+		{"branch", "feat/login"},
+		{"branch", "fix/crash"},
+	} {
+		if output, err := testutil.RunGit(t, dir, args...); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "overview")
+	assertExitCode(t, err, errors.ExitCodeNotInitialized, output)
+
+	output, err = testutil.RunGitFlow(t, dir, "overview", "--best-effort")
+	if err != nil {
+		t.Fatalf("Failed to run overview --best-effort: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"not initialized; branch names and prefixes are inferred",
+		"master (root)",
+		"dev → master",
+		"feat/*:",
+		"fix/*:",
+		"feat/login (feature)",
+		"fix/crash (bugfix)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.initialized"); err == nil {
+		t.Error("Expected --best-effort not to save any configuration")
+	}
+}
@@ -0,0 +1,160 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// testPolicy pins squash merges for features and protects main
+const testPolicy = `pinned:
+  branch.feature.upstreamStrategy: squash
+protected:
+  - main
+`
+
+// writePolicy writes policy to a file outside the repository and points
+// gitflow.policy at it
+func writePolicy(t *testing.T, dir string, policy string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gitflow-policy.yml")
+	if err := os.WriteFile(path, []byte(policy), 0444); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.policy", path)
+	return path
+}
+
+// TestConfigChangesFollowPolicy tests that config commands refuse changes the policy forbids.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and sets a policy pinning squash for features
+// 2. Edits feature to use rebase and verifies the change is refused with exit code 6
+// 3. Verifies an unrelated edit succeeds although feature still drifts from the policy
+// 4. Edits feature to use squash and verifies the change is accepted
+// 5. Renames and deletes the protected main branch and verifies both are refused
+func TestConfigChangesFollowPolicy(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	writePolicy(t, dir, testPolicy)
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "edit", "topic", "feature", "--upstream-strategy", "rebase")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "gitflow.branch.feature.upstreamStrategy is pinned to 'squash'") {
+		t.Errorf("Expected the pinned setting to be named, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature.upstreamStrategy"); strings.TrimSpace(value) != "merge" {
+		t.Errorf("Expected feature to keep its strategy, got '%s'", strings.TrimSpace(value))
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "edit", "topic", "bugfix", "--prefix", "fix/")
+	if err != nil {
+		t.Fatalf("Expected an unrelated edit to succeed: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "edit", "topic", "feature", "--upstream-strategy", "squash")
+	if err != nil {
+		t.Fatalf("Expected the pinned value to be accepted: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "rename", "base", "main", "production")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "the configuration of 'main' is protected") {
+		t.Errorf("Expected the protected branch to be named, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "delete", "base", "main")
+	if err == nil {
+		t.Fatalf("Expected deleting the protected main branch to fail, got: %s", output)
+	}
+	if value, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.main.type"); strings.TrimSpace(value) != "base" {
+		t.Errorf("Expected main to stay configured, got type '%s'", strings.TrimSpace(value))
+	}
+}
+
+// TestDoctorReportsPolicyDrift tests that doctor compares the configuration with the policy.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs doctor without a policy and verifies it passes
+// 3. Sets a policy the configuration drifts from and verifies doctor reports it with exit code 6
+// 4. Fixes the drift and verifies doctor passes
+func TestDoctorReportsPolicyDrift(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "doctor")
+	if err != nil {
+		t.Fatalf("Expected doctor to pass without a policy: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "gitflow.policy is not set") {
+		t.Errorf("Expected doctor to mention the missing policy, got: %s", output)
+	}
+
+	writePolicy(t, dir, testPolicy)
+	output, err = testutil.RunGitFlow(t, dir, "doctor")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "gitflow.branch.feature.upstreamStrategy is 'merge', the policy pins it to 'squash'") {
+		t.Errorf("Expected doctor to report the drift, got: %s", output)
+	}
+
+	testutil.RunGit(t, dir, "config", "gitflow.branch.feature.upstreamStrategy", "squash")
+	output, err = testutil.RunGitFlow(t, dir, "doctor")
+	if err != nil {
+		t.Fatalf("Expected doctor to pass once the drift is fixed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "the configuration follows the policy") {
+		t.Errorf("Expected doctor to confirm the policy, got: %s", output)
+	}
+}
+
+// TestInitTemplateInstallsPolicy tests that a template distributes its policy.
+// Steps:
+// 1. Creates a template directory with a configuration file and a policy
+// 2. Runs 'git flow init --template <dir>' in a fresh repository
+// 3. Verifies the policy is installed read-only and named by gitflow.policy
+// 4. Verifies config commands follow the installed policy
+func TestInitTemplateInstallsPolicy(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	template := createTemplate(t)
+	policy := "pinned:\n  branch.feature.prefix: feat/\n"
+	if err := os.WriteFile(filepath.Join(template, "gitflow-policy.yml"), []byte(policy), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--template", template)
+	if err != nil {
+		t.Fatalf("Failed to initialize from template: %v\nOutput: %s", err, output)
+	}
+
+	path, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.policy")
+	path = strings.TrimSpace(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the policy to be installed at gitflow.policy: %v", err)
+	}
+	if info.Mode().Perm()&0222 != 0 {
+		t.Errorf("Expected the installed policy to be read-only, got mode %v", info.Mode().Perm())
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "config", "edit", "topic", "feature", "--prefix", "f/")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+
+	output, err = testutil.RunGitFlow(t, dir, "doctor")
+	if err != nil {
+		t.Fatalf("Expected doctor to pass: %v\nOutput: %s", err, output)
+	}
+}
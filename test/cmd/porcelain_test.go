@@ -0,0 +1,105 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestPorcelainErrorReport tests that --porcelain reports a failed git command as JSON.
+// Steps:
+// 1. Sets up a repository with git-flow defaults and an unmerged feature branch
+// 2. Deletes the branch without --force using --porcelain
+// 3. Verifies the command fails and standard error holds a JSON error with code and failed command
+func TestPorcelainErrorReport(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", "unmerged"); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature\n")
+	_, _ = testutil.RunGit(t, dir, "add", "feature.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Feature work")
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "--porcelain", "feature", "delete", "unmerged")
+	exitErr, ok := err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != 3 {
+		t.Fatalf("Expected exit status 3, got %v\nOutput: %s", err, output)
+	}
+
+	start := strings.Index(output, "{")
+	if start < 0 {
+		t.Fatalf("Expected a JSON error report, got: %s", output)
+	}
+	var document struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Command string `json:"command"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output[start:])), &document); err != nil {
+		t.Fatalf("Expected a JSON error report, got %q: %v", output, err)
+	}
+	if document.Error.Code != "git_error" || document.Error.Command != "git branch -d feature/unmerged" {
+		t.Errorf("Unexpected error report: %+v", document.Error)
+	}
+}
+
+// TestPorcelainReportsFinishEvents tests that --porcelain writes the events of a finish as JSON lines on standard error.
+// Steps:
+// 1. Sets up a repository with git-flow defaults and a release branch with a commit
+// 2. Finishes the release with --porcelain
+// 3. Verifies step_start, tag_created and branch_deleted events are reported as JSON lines
+func TestPorcelainReportsFinishEvents(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v", err)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release\n")
+	_, _ = testutil.RunGit(t, dir, "add", "release.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Release work")
+
+	output, err := testutil.RunGitFlow(t, dir, "--porcelain", "release", "finish", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	seen := map[string]map[string]interface{}{}
+	steps := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("Expected a JSON event, got %q: %v", line, err)
+		}
+		name, _ := event["event"].(string)
+		seen[name] = event
+		if name == "step_start" {
+			steps = append(steps, event["step"].(string))
+		}
+	}
+	if len(steps) == 0 || steps[0] != "merge" || steps[len(steps)-1] != "delete_branch" {
+		t.Errorf("Expected the steps from merge to delete_branch, got %v", steps)
+	}
+	if tag := seen["tag_created"]; tag == nil || tag["tag"] != "1.0.0" || tag["branch"] != "main" {
+		t.Errorf("Expected a tag_created event for 1.0.0 on main, got %v", tag)
+	}
+	if deleted := seen["branch_deleted"]; deleted == nil || deleted["branch"] != "release/1.0.0" {
+		t.Errorf("Expected a branch_deleted event for release/1.0.0, got %v", deleted)
+	}
+}
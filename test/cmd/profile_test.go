@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFinishProfileReportsStages tests that --profile reports the duration of each finish stage.
+// Steps:
+// 1. Sets up a test repository and creates a release branch with a commit
+// 2. Installs a pre-finish hook
+// 3. Finishes the release with --profile
+// 4. Verifies the report lists pre-flight, hook, merge, tag, child update and delete stages and a total
+func TestFinishProfileReportsStages(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "3.1.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+
+	createHookScript(t, dir, "pre-flow-release-finish", "#!/bin/sh\nexit 0\n")
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "3.1.0", "--profile")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"Profile:",
+		"pre-flight checks",
+		"hook pre-flow-release-finish",
+		"merge release/3.1.0 into main",
+		"create tag",
+		"update develop from main",
+		"delete branch",
+		"total",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected profile to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+// TestFinishWithoutProfilePrintsNoReport tests that no report is printed without --profile.
+// Steps:
+// 1. Sets up a test repository and creates a feature branch
+// 2. Finishes the feature without --profile
+// 3. Verifies no profile report is printed
+func TestFinishWithoutProfilePrintsNoReport(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "unprofiled")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "unprofiled")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Profile:") {
+		t.Errorf("Expected no profile report, got: %s", output)
+	}
+}
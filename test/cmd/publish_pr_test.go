@@ -0,0 +1,173 @@
+package cmd_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/forge"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupHostedRemote points origin at a GitHub URL while pushes still go to
+// remoteDir, and makes fetching from the GitHub URL fail right away.
+func setupHostedRemote(t *testing.T, dir string, remoteDir string, remoteURL string) {
+	t.Helper()
+	testutil.RunGit(t, dir, "remote", "set-url", "origin", remoteURL)
+	testutil.RunGit(t, dir, "config", "url."+remoteDir+".pushInsteadOf", remoteURL)
+	t.Setenv("GIT_SSH_COMMAND", "false")
+}
+
+// createFakeGH places a gh client on PATH that records its arguments, one per
+// line, in argsFile and prints a pull request URL.
+func createFakeGH(t *testing.T, argsFile string) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do echo "$arg"; done > "` + argsFile + `"
+echo "Creating pull request"
+echo "https://github.com/acme/app/pull/7"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "gh"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create fake gh: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestPublishDraftPullRequest tests that publish --draft pushes the branch and opens a draft pull request.
+// Steps:
+// 1. Sets up a repository with a remote, starts a feature with two commits and a description
+// 2. Points origin at a GitHub URL and places a fake gh client on PATH
+// 3. Runs 'git flow feature publish --draft'
+// 4. Verifies the branch was pushed and the pull request URL is printed
+// 5. Verifies gh was asked for a draft against develop with the title and body from the description and commits
+func TestPublishDraftPullRequest(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	for _, file := range []string{"form.txt", "session.txt"} {
+		testutil.WriteFile(t, dir, file, file)
+		testutil.RunGit(t, dir, "add", file)
+		testutil.RunGit(t, dir, "commit", "-m", "Add "+file)
+	}
+	testutil.RunGit(t, dir, "config", "branch.feature/login.description", "Add the login page\n\nUsers can sign in with a password.")
+	setupHostedRemote(t, dir, remoteDir, "git@github.com:acme/app.git")
+	argsFile := filepath.Join(t.TempDir(), "gh-args")
+	createFakeGH(t, argsFile)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "--draft")
+	if err != nil {
+		t.Fatalf("Failed to publish with a draft pull request: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/login"); err != nil {
+		t.Error("Expected feature/login to be pushed")
+	}
+	if !strings.Contains(output, "Opened https://github.com/acme/app/pull/7") {
+		t.Errorf("Expected the pull request URL, got: %s", output)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("Expected gh to be run: %v", err)
+	}
+	args := string(data)
+	for _, expected := range []string{
+		"pr\ncreate\n--repo\nacme/app\n",
+		"--base\ndevelop\n--head\nfeature/login\n",
+		"--title\nAdd the login page\n",
+		"--body\nUsers can sign in with a password.\n\nCommits:\n- Add form.txt\n- Add session.txt\n",
+		"--draft\n",
+	} {
+		if !strings.Contains(args, expected) {
+			t.Errorf("Expected gh arguments to contain %q, got:\n%s", expected, args)
+		}
+	}
+}
+
+// TestPublishPullRequestWithoutToken tests that publish --pr fails before pushing when neither a pull request client nor a token is available.
+// Steps:
+// 1. Sets up a repository with a remote and starts a feature
+// 2. Points origin at a Bitbucket URL, which has no pull request client, and hides all tokens
+// 3. Runs 'git flow feature publish --pr'
+// 4. Verifies the command fails with exit code 2 and nothing was pushed
+func TestPublishPullRequestWithoutToken(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+	isolateForgeTokens(t)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	setupHostedRemote(t, dir, remoteDir, "git@bitbucket.org:acme/app.git")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "--pr")
+	if err == nil {
+		t.Fatalf("Expected publish --pr to fail on Bitbucket, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d, got %d", errors.ExitCodeInvalidInput, exitErr.ExitCode)
+	}
+	if !strings.Contains(output, "opening pull requests on bitbucket requires an API token and none was found") {
+		t.Errorf("Expected the missing token to be reported, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/login"); err == nil {
+		t.Error("Expected nothing to be pushed")
+	}
+}
+
+// TestPublishPullRequestThroughAPI tests that publish --pr opens the pull request through the API when a token is available.
+// Steps:
+// 1. Sets up a repository with a remote and starts a feature
+// 2. Points origin at a Bitbucket URL and the API at a test server, and sets BITBUCKET_TOKEN
+// 3. Runs 'git flow feature publish --pr'
+// 4. Verifies the pull request was created against develop with the token and its URL is printed
+func TestPublishPullRequestThroughAPI(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+	isolateForgeTokens(t)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	setupHostedRemote(t, dir, remoteDir, "git@bitbucket.org:acme/app.git")
+
+	var request string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request = r.Method + " " + r.URL.Path + " " + r.Header.Get("Authorization") + " " + string(body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"links": {"html": {"href": "https://bitbucket.org/acme/app/pull-requests/3"}}}`)
+	}))
+	defer server.Close()
+	t.Setenv(forge.APIURLEnv, server.URL)
+	t.Setenv("BITBUCKET_TOKEN", "bb-token")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "--pr")
+	if err != nil {
+		t.Fatalf("Failed to publish with a pull request: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Opened https://bitbucket.org/acme/app/pull-requests/3") {
+		t.Errorf("Expected the pull request URL, got: %s", output)
+	}
+	for _, expected := range []string{
+		"POST /repositories/acme/app/pullrequests Bearer bb-token ",
+		`"destination":{"branch":{"name":"develop"}}`,
+		`"source":{"branch":{"name":"feature/login"}}`,
+	} {
+		if !strings.Contains(request, expected) {
+			t.Errorf("Expected the request to contain %q, got: %s", expected, request)
+		}
+	}
+}
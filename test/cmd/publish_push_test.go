@@ -0,0 +1,155 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// createRemoteHook installs a hook script in a bare remote repository
+func createRemoteHook(t *testing.T, remoteDir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(remoteDir, "hooks", name), []byte(content), 0755); err != nil {
+		t.Fatalf("Failed to create remote hook %s: %v", name, err)
+	}
+}
+
+// TestPublishRejectedByRemoteHook tests that a push refused by a server-side hook reports the remote's message verbatim.
+// Steps:
+// 1. Sets up a repository with a remote whose pre-receive hook refuses every push with a message
+// 2. Starts and publishes a feature branch
+// 3. Verifies publish fails with exit code 3, prints the remote's message and the rejected ref
+// 4. Verifies the hint does not suggest --force-with-lease
+func TestPublishRejectedByRemoteHook(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	createRemoteHook(t, remoteDir, "pre-receive", `#!/bin/sh
+echo "Feature branches need a ticket number, like feature/ABC-123-name"
+exit 1
+`)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "no-ticket"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "no-ticket")
+	assertExitCode(t, err, errors.ExitCodeGitError, output)
+
+	for _, expected := range []string{
+		"remote: Feature branches need a ticket number, like feature/ABC-123-name",
+		"! feature/no-ticket (pre-receive hook declined)",
+		"Hint: The remote refused the push",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "--force-with-lease") {
+		t.Errorf("Expected no --force-with-lease suggestion, got:\n%s", output)
+	}
+}
+
+// TestPublishNegativeRefspec tests that publish refuses a branch excluded by a negative push refspec of the remote.
+// Steps:
+// 1. Sets up a repository with a remote and sets remote.origin.push to exclude feature/private-*
+// 2. Verifies publishing feature/private-notes fails with exit code 2 and nothing is pushed
+// 3. Verifies publishing feature/shared still succeeds
+func TestPublishNegativeRefspec(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "--add", "remote.origin.push", "refs/heads/*:refs/heads/*")
+	testutil.RunGit(t, dir, "config", "--add", "remote.origin.push", "^refs/heads/feature/private-*")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "private-notes"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "private-notes")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "^refs/heads/feature/private-*") {
+		t.Errorf("Expected the excluding refspec in the error, got:\n%s", output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/private-notes"); err == nil {
+		t.Error("Expected feature/private-notes not to be pushed")
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "shared", "--publish"); err != nil {
+		t.Fatalf("Failed to start and publish feature/shared: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestPublishPushDefaultUpstream tests that publish pushes to the tracked branch of another name with push.default=upstream, and refuses with simple.
+// Steps:
+// 1. Sets up a repository with a remote, pushes feature/renamed as bug/renamed and makes it track that branch
+// 2. Adds a commit and publishes with --force-with-lease and push.default=upstream
+// 3. Verifies origin/bug/renamed holds the commit and no feature/renamed branch was created on the remote
+// 4. Verifies publishing with push.default=simple fails with exit code 2
+func TestPublishPushDefaultUpstream(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "renamed"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "push", "-u", "origin", "feature/renamed:bug/renamed")
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Fix")
+	testutil.RunGit(t, dir, "config", "push.default", "upstream")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "renamed", "--force-with-lease")
+	if err != nil {
+		t.Fatalf("Failed to publish: %v\nOutput: %s", err, output)
+	}
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "feature/renamed")
+	remote, _ := testutil.RunGit(t, remoteDir, "rev-parse", "refs/heads/bug/renamed")
+	if strings.TrimSpace(local) != strings.TrimSpace(remote) {
+		t.Errorf("Expected bug/renamed on the remote at %s, got %s\nOutput: %s", strings.TrimSpace(local), strings.TrimSpace(remote), output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/renamed"); err == nil {
+		t.Error("Expected no feature/renamed branch on the remote")
+	}
+
+	testutil.RunGit(t, dir, "config", "push.default", "simple")
+	output, err = testutil.RunGitFlow(t, dir, "feature", "publish", "renamed", "--force-with-lease")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+}
+
+// TestFinishPushNegativeRefspec tests that finish --push leaves out branches excluded by a negative push refspec.
+// Steps:
+// 1. Sets up a repository with a remote and sets remote.origin.push to exclude develop
+// 2. Finishes a feature with --push
+// 3. Verifies the finish succeeds, reports that develop is not pushed, and origin/develop is unchanged
+func TestFinishPushNegativeRefspec(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "--add", "remote.origin.push", "refs/heads/*:refs/heads/*")
+	testutil.RunGit(t, dir, "config", "--add", "remote.origin.push", "^refs/heads/develop")
+	before, _ := testutil.RunGit(t, remoteDir, "rev-parse", "refs/heads/develop")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "local-only"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/local-only", "local.txt", "local")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "local-only", "--push")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Not pushing 'develop'") {
+		t.Errorf("Expected a note that develop is not pushed, got:\n%s", output)
+	}
+	after, _ := testutil.RunGit(t, remoteDir, "rev-parse", "refs/heads/develop")
+	if before != after {
+		t.Errorf("Expected origin/develop to stay at %s, got %s", strings.TrimSpace(before), strings.TrimSpace(after))
+	}
+}
@@ -126,12 +126,12 @@ func TestPublishBranchNotFoundLocally(t *testing.T) {
 	}
 }
 
-// TestPublishBranchAlreadyExistsOnRemote tests error when remote branch exists.
+// TestPublishBranchAlreadyExistsOnRemote tests publishing a branch the remote already has an older copy of.
 // Steps:
 // 1. Sets up a test repository with a remote
 // 2. Creates a local branch and publishes it
-// 3. Attempts to publish the same branch again
-// 4. Verifies appropriate error is returned
+// 3. Adds a commit and publishes the branch again
+// 4. Verifies the remote branch is fast-forwarded to the local one
 func TestPublishBranchAlreadyExistsOnRemote(t *testing.T) {
 	// Setup test repo with remote
 	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
@@ -149,24 +149,20 @@ func TestPublishBranchAlreadyExistsOnRemote(t *testing.T) {
 		t.Fatalf("Failed to publish feature first time: %v", err)
 	}
 
-	// Try to publish the same branch again
-	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "duplicate")
-	if err == nil {
-		t.Fatal("Expected error when publishing branch that already exists on remote, but succeeded")
-	}
+	testutil.WriteFile(t, dir, "duplicate.txt", "more work")
+	testutil.RunGit(t, dir, "add", "duplicate.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "More work")
 
-	// Check exit code
-	if exitErr, ok := err.(*testutil.ExitError); ok {
-		if exitErr.ExitCode != int(errors.ExitCodeBranchExists) {
-			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeBranchExists, exitErr.ExitCode)
-		}
-	} else {
-		t.Error("Expected ExitError")
+	// Publishing again fast-forwards the remote branch
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "duplicate")
+	if err != nil {
+		t.Fatalf("Expected publish to fast-forward the remote branch: %v\nOutput: %s", err, output)
 	}
 
-	// Verify error message
-	if !strings.Contains(output, "already exists on remote") {
-		t.Errorf("Expected 'already exists on remote' error message, got: %s", output)
+	localHead, _ := testutil.RunGit(t, dir, "rev-parse", "feature/duplicate")
+	remoteHead, _ := testutil.RunGit(t, remoteDir, "rev-parse", "feature/duplicate")
+	if strings.TrimSpace(localHead) != strings.TrimSpace(remoteHead) {
+		t.Errorf("Expected remote branch at %s, got %s", strings.TrimSpace(localHead), strings.TrimSpace(remoteHead))
 	}
 }
 
@@ -238,6 +234,51 @@ func TestPublishWithCustomRemote(t *testing.T) {
 	}
 }
 
+// TestPublishWithRemoteFlag tests that the global --remote flag overrides gitflow.origin.
+// Steps:
+// 1. Sets up a test repository with 'origin' and 'upstream' remotes
+// 2. Creates a feature branch
+// 3. Runs 'git flow --remote upstream feature publish remote-flag-feature'
+// 4. Verifies branch is pushed to 'upstream' and not to 'origin'
+func TestPublishWithRemoteFlag(t *testing.T) {
+	// Setup test repo with origin remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	// Add a second remote
+	upstreamDir, err := testutil.AddRemote(t, dir, "upstream", false)
+	if err != nil {
+		t.Fatalf("Failed to add upstream remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, upstreamDir)
+
+	_, err = testutil.RunGit(t, dir, "push", "upstream", "develop")
+	if err != nil {
+		t.Fatalf("Failed to push develop to upstream: %v", err)
+	}
+
+	// Create a feature branch
+	_, err = testutil.RunGitFlow(t, dir, "feature", "start", "remote-flag-feature")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+
+	// Publish using the global --remote flag
+	output, err := testutil.RunGitFlow(t, dir, "--remote", "upstream", "feature", "publish", "remote-flag-feature")
+	if err != nil {
+		t.Fatalf("Failed to publish feature: %v\nOutput: %s", err, output)
+	}
+
+	// Verify branch exists on upstream only
+	if !testutil.RemoteBranchExists(t, dir, "upstream", "feature/remote-flag-feature") {
+		t.Error("Expected remote branch on 'upstream' to exist")
+	}
+	if testutil.RemoteBranchExists(t, dir, "origin", "feature/remote-flag-feature") {
+		t.Error("Expected remote branch on 'origin' not to exist")
+	}
+}
+
 // TestPublishReleaseBranch tests publishing a release branch.
 // Steps:
 // 1. Sets up a test repository with a remote
@@ -429,3 +470,122 @@ func TestPublishCurrentBranchWrongType(t *testing.T) {
 		t.Errorf("Expected 'not a release branch' error message, got: %s", output)
 	}
 }
+
+// TestPublishDivergedRemoteBranch tests the error and --force-with-lease for a diverged remote branch.
+// Steps:
+// 1. Sets up a test repository with a remote and publishes a feature branch
+// 2. Pushes a commit, then replaces it locally with a different commit
+// 3. Publishes again and verifies the error explains the divergence and both options
+// 4. Publishes with --force-with-lease and verifies the remote branch matches the local one
+func TestPublishDivergedRemoteBranch(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "diverged"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "diverged"); err != nil {
+		t.Fatalf("Failed to publish feature: %v\nOutput: %s", err, output)
+	}
+
+	// Push one commit, then replace it locally with another
+	testutil.WriteFile(t, dir, "remote.txt", "remote content")
+	testutil.RunGit(t, dir, "add", "remote.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Remote commit")
+	testutil.RunGit(t, dir, "push", "origin", "feature/diverged")
+	testutil.RunGit(t, dir, "reset", "--hard", "HEAD~1")
+	testutil.WriteFile(t, dir, "local.txt", "local content")
+	testutil.RunGit(t, dir, "add", "local.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Local commit")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "diverged")
+	if err == nil {
+		t.Fatal("Expected publish of a diverged branch to fail")
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeBranchExists) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeBranchExists, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+	for _, expected := range []string{"has diverged", "--force-with-lease", "--track-instead"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected error to mention '%s', got: %s", expected, output)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "publish", "diverged", "--force-with-lease")
+	if err != nil {
+		t.Fatalf("Failed to publish with --force-with-lease: %v\nOutput: %s", err, output)
+	}
+
+	localHead, _ := testutil.RunGit(t, dir, "rev-parse", "feature/diverged")
+	remoteHead, _ := testutil.RunGit(t, remoteDir, "rev-parse", "feature/diverged")
+	if strings.TrimSpace(localHead) != strings.TrimSpace(remoteHead) {
+		t.Errorf("Expected remote branch at %s, got %s", strings.TrimSpace(localHead), strings.TrimSpace(remoteHead))
+	}
+}
+
+// TestPublishTrackInstead tests that --track-instead tracks an existing remote branch without pushing.
+// Steps:
+// 1. Sets up a test repository with a remote and starts a feature branch
+// 2. Pushes the branch with plain git, without setting up tracking
+// 3. Publishes with --track-instead
+// 4. Verifies the local branch now tracks the remote branch
+func TestPublishTrackInstead(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "pushed"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "push", "origin", "feature/pushed")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "pushed", "--track-instead")
+	if err != nil {
+		t.Fatalf("Failed to publish with --track-instead: %v\nOutput: %s", err, output)
+	}
+
+	upstream, err := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "feature/pushed@{upstream}")
+	if err != nil {
+		t.Fatalf("Expected branch to have an upstream: %v", err)
+	}
+	if strings.TrimSpace(upstream) != "origin/feature/pushed" {
+		t.Errorf("Expected upstream 'origin/feature/pushed', got '%s'", strings.TrimSpace(upstream))
+	}
+}
+
+// TestPublishNoTrackUpstream tests that --no-track-upstream pushes without setting up tracking.
+// Steps:
+// 1. Sets up a test repository with a remote and starts a feature branch
+// 2. Publishes it with --no-track-upstream
+// 3. Verifies the branch is on the remote and has no branch.<name>.remote/merge settings
+// 4. Verifies --track-instead cannot be combined with --no-track-upstream
+func TestPublishNoTrackUpstream(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "untracked"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "untracked", "--no-track-upstream")
+	if err != nil {
+		t.Fatalf("Failed to publish: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/untracked"); err != nil {
+		t.Error("Expected feature/untracked to be pushed to the remote")
+	}
+	for _, key := range []string{"branch.feature/untracked.remote", "branch.feature/untracked.merge"} {
+		if value, err := testutil.RunGit(t, dir, "config", "--get", key); err == nil {
+			t.Errorf("Expected %s not to be set, got %q", key, value)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "publish", "untracked", "--track-instead", "--no-track-upstream")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+}
@@ -0,0 +1,145 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestQuietStartPrintsBranchName tests that start in quiet mode prints only the created branch name.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow quietly
+// 2. Verifies init printed nothing
+// 3. Starts a feature branch with --quiet
+// 4. Verifies the output is exactly the full branch name
+func TestQuietStartPrintsBranchName(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "--quiet", "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output != "" {
+		t.Errorf("Expected no output from quiet init, got: %q", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "quiet-start", "-q")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if output != "feature/quiet-start\n" {
+		t.Errorf("Expected only the branch name, got: %q", output)
+	}
+}
+
+// TestQuietFinishPrintsTagName tests that finish in quiet mode prints only the created tag name.
+// Steps:
+// 1. Sets up a test repository and creates a release branch with a commit
+// 2. Finishes the release with --quiet
+// 3. Verifies the output is exactly the tag name
+// 4. Finishes a feature branch with --quiet and verifies nothing is printed
+func TestQuietFinishPrintsTagName(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	testutil.RunGit(t, dir, "add", "release.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+
+	output, err = testutil.RunGitFlow(t, dir, "--quiet", "release", "finish", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	if output != "2.0.0\n" {
+		t.Errorf("Expected only the tag name, got: %q", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "untagged")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	testutil.RunGit(t, dir, "add", "feature.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+
+	output, err = testutil.RunGitFlow(t, dir, "--quiet", "feature", "finish", "untagged")
+	if err != nil {
+		t.Fatalf("Failed to finish feature: %v\nOutput: %s", err, output)
+	}
+	if output != "" {
+		t.Errorf("Expected no output when no tag is created, got: %q", output)
+	}
+}
+
+// TestQuietKeepsDataAndErrors tests that quiet mode keeps listings and error messages.
+// Steps:
+// 1. Sets up a test repository and creates two feature branches
+// 2. Runs 'feature list' with --quiet and verifies both branches are listed
+// 3. Deletes a missing branch with --quiet and verifies the error is still printed
+func TestQuietKeepsDataAndErrors(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	for _, name := range []string{"one", "two"} {
+		if output, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+			t.Fatalf("Failed to start feature %s: %v\nOutput: %s", name, err, output)
+		}
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "--quiet", "feature", "list")
+	if err != nil {
+		t.Fatalf("Failed to list features: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "one") || !strings.Contains(output, "two") {
+		t.Errorf("Expected quiet list to include both branches, got: %q", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "--quiet", "feature", "delete", "missing")
+	if err == nil {
+		t.Fatalf("Expected delete of a missing branch to fail, got output: %s", output)
+	}
+	if !strings.Contains(output, "Error:") {
+		t.Errorf("Expected the error to be printed in quiet mode, got: %q", output)
+	}
+}
+
+// TestQuietRejectsInteractiveInit tests that interactive init is refused in quiet mode.
+// Steps:
+// 1. Sets up a test repository
+// 2. Runs 'init' with --quiet and no configuration options
+// 3. Verifies it fails with an invalid input exit code instead of waiting for answers
+func TestQuietRejectsInteractiveInit(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "--quiet", "init")
+	if err == nil {
+		t.Fatalf("Expected interactive init to fail in quiet mode, got output: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok {
+		if exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeInvalidInput, exitErr.ExitCode)
+		}
+	} else {
+		t.Error("Expected ExitError")
+	}
+	if !strings.Contains(output, "not available with --quiet") {
+		t.Errorf("Expected quiet mode error, got: %s", output)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestReleaseRC tests that release rc tags numbered release candidates and finish lists them in the tag message.
+// Steps:
+// 1. Sets up a repository, starts release 1.0 and adds a commit
+// 2. Runs release rc twice; verifies 1.0-rc.1 is created and the unchanged head is refused
+// 3. Adds a commit and runs release rc 1.0; verifies 1.0-rc.2 points at the new head
+// 4. Finishes the release and verifies the tag message lists both candidates
+// 5. Verifies the recorded candidates are removed with the branch
+func TestReleaseRC(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "release/1.0", "first.txt", "first")
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "rc")
+	if err != nil {
+		t.Fatalf("Failed to tag release candidate: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "release candidate '1.0-rc.1'") {
+		t.Errorf("Expected 1.0-rc.1 to be created, got:\n%s", output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "release", "rc")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "has not changed since release candidate '1.0-rc.1'") {
+		t.Errorf("Expected the unchanged head to be refused, got:\n%s", output)
+	}
+
+	commitOn(t, dir, "release/1.0", "second.txt", "second")
+	if output, err := testutil.RunGitFlow(t, dir, "release", "rc", "1.0"); err != nil {
+		t.Fatalf("Failed to tag second release candidate: %v\nOutput: %s", err, output)
+	}
+	head, _ := testutil.RunGit(t, dir, "rev-parse", "release/1.0")
+	tagged, _ := testutil.RunGit(t, dir, "rev-parse", "1.0-rc.2^{commit}")
+	if tagged != head {
+		t.Errorf("Expected 1.0-rc.2 at %s, got %s", strings.TrimSpace(head), strings.TrimSpace(tagged))
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "finish", "1.0"); err != nil {
+		t.Fatalf("Failed to finish release: %v\nOutput: %s", err, output)
+	}
+	message, _ := testutil.RunGit(t, dir, "tag", "-l", "--format=%(contents)", "1.0")
+	for _, expected := range []string{"Release candidates:", "- 1.0-rc.1 (", "- 1.0-rc.2 ("} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected the tag message to contain %q, got:\n%s", expected, message)
+		}
+	}
+	if recorded, _ := testutil.RunGit(t, dir, "config", "--get-all", "gitflow.branch.release/1.0.rc"); recorded != "" {
+		t.Errorf("Expected the recorded candidates to be removed, got:\n%s", recorded)
+	}
+}
+
+// TestReleaseRCPush tests that release rc --push pushes the release candidate tag, and refuses released branches.
+// Steps:
+// 1. Sets up a repository with a remote and starts release 2.0
+// 2. Runs release rc --push and verifies 2.0-rc.1 exists on the remote
+// 3. Creates the tag 2.0 and verifies release rc is refused
+func TestReleaseRCPush(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "2.0"); err != nil {
+		t.Fatalf("Failed to start release: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "release", "rc", "--push"); err != nil {
+		t.Fatalf("Failed to tag and push release candidate: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/tags/2.0-rc.1"); err != nil {
+		t.Error("Expected 2.0-rc.1 to be pushed")
+	}
+
+	testutil.RunGit(t, dir, "tag", "2.0")
+	output, err := testutil.RunGitFlow(t, dir, "release", "rc")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "already released as '2.0'") {
+		t.Errorf("Expected a released branch to be refused, got:\n%s", output)
+	}
+}
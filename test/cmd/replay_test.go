@@ -0,0 +1,79 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestReplayScripts tests that the scenarios in test/replay pass and replay the same way twice.
+// Steps:
+// 1. Runs 'git flow --replay' for each script in test/replay
+// 2. Verifies each script passes
+// 3. Runs each script again and verifies the transcript is identical
+func TestReplayScripts(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("..", "replay", "*.flow"))
+	if err != nil || len(scripts) == 0 {
+		t.Fatalf("Failed to find the replay scripts: %v", err)
+	}
+	dir := t.TempDir()
+	for _, script := range scripts {
+		path, _ := filepath.Abs(script)
+		t.Run(filepath.Base(script), func(t *testing.T) {
+			first, err := testutil.RunGitFlow(t, dir, "--replay", path)
+			if err != nil {
+				t.Fatalf("Expected the script to pass: %v\nOutput: %s", err, first)
+			}
+			second, _ := testutil.RunGitFlow(t, dir, "--replay", path)
+			if first != second {
+				t.Errorf("Expected identical transcripts, got:\n%s\nand:\n%s", first, second)
+			}
+		})
+	}
+}
+
+// TestReplayReportsFailedStep tests that a failed expectation ends the replay and keeps the fixture.
+// Steps:
+// 1. Writes a script whose expected exit code does not match
+// 2. Runs 'git flow --replay' and verifies it fails with exit code 6 naming the line
+// 3. Verifies the fixture repository was kept with the state at the failure
+func TestReplayReportsFailedStep(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "failing.flow")
+	testutil.WriteFile(t, dir, "failing.flow", "repo\nflow init --defaults\nflow feature start login\nexpect exit 4\n")
+
+	output, err := testutil.RunGitFlow(t, dir, "--replay", script)
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "failed at line 4 (expect exit 4): expected exit code 4, got 0") {
+		t.Errorf("Expected the failed step to be reported, got: %s", output)
+	}
+
+	match := regexp.MustCompile(`kept in (\S+) for inspection`).FindStringSubmatch(output)
+	if match == nil {
+		t.Fatalf("Expected the fixture directory to be named, got: %s", output)
+	}
+	defer os.RemoveAll(match[1])
+	if branch := testutil.GetCurrentBranch(t, filepath.Join(match[1], "repo")); branch != "feature/login" {
+		t.Errorf("Expected the fixture to be on feature/login, got '%s'", branch)
+	}
+}
+
+// TestReplayRejectsInvalidScript tests that an invalid script is rejected before anything runs.
+// Steps:
+// 1. Writes a script with an unknown directive
+// 2. Runs 'git flow --replay' and verifies it fails with exit code 2 naming the line
+func TestReplayRejectsInvalidScript(t *testing.T) {
+	dir := t.TempDir()
+	testutil.WriteFile(t, dir, "invalid.flow", "repo\n\nfinish feature login\n")
+
+	output, err := testutil.RunGitFlow(t, dir, "--replay", filepath.Join(dir, "invalid.flow"))
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "line 3: unknown directive 'finish'") {
+		t.Errorf("Expected the invalid line to be reported, got: %s", output)
+	}
+}
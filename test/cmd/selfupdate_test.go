@@ -0,0 +1,238 @@
+package cmd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/selfupdate"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// releaseKey signs the checksums of the fake releases; release builds made with
+// buildReleaseCopy carry its public key
+var releaseKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+
+// fakeRelease serves a GitHub release of tag for the current platform whose binary
+// is script, with checksums signed by releaseKey. Passing a wrong checksum
+// simulates a tampered download.
+func fakeRelease(t *testing.T, tag, script string, tamper bool) *httptest.Server {
+	t.Helper()
+	archiveName := selfupdate.ArchiveName(tag, runtime.GOOS, runtime.GOARCH)
+	binaryName := selfupdate.BinaryName(tag, runtime.GOOS, runtime.GOARCH)
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: binaryName, Mode: 0755, Size: int64(len(script)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(script))
+	tw.Close()
+	gz.Close()
+
+	sum := sha256.Sum256(archive.Bytes())
+	if tamper {
+		sum = sha256.Sum256([]byte("something else"))
+	}
+	checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), archiveName)
+	signature := ed25519.Sign(releaseKey, []byte(checksums))
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tag_name": tag,
+			"assets": []map[string]string{
+				{"name": archiveName, "browser_download_url": server.URL + "/download/" + archiveName},
+				{"name": selfupdate.ChecksumsName(tag), "browser_download_url": server.URL + "/download/checksums"},
+				{"name": selfupdate.ChecksumsName(tag) + ".sig", "browser_download_url": server.URL + "/download/checksums.sig"},
+			},
+		})
+	})
+	mux.HandleFunc("/download/"+archiveName, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive.Bytes())
+	})
+	mux.HandleFunc("/download/checksums", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(checksums))
+	})
+	mux.HandleFunc("/download/checksums.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(signature)
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+// installCopy copies the git-flow binary under test to dir/bin, so self-update can
+// replace it without affecting other tests.
+func installCopy(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(testutil.GitFlowPath())
+	if err != nil {
+		t.Fatalf("Failed to read git-flow binary: %v", err)
+	}
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	path := filepath.Join(binDir, "git-flow")
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatalf("Failed to copy git-flow binary: %v", err)
+	}
+	return path
+}
+
+// buildReleaseCopy builds git-flow into dir/bin the way release builds are
+// made, with the public key of releaseKey, so self-update can install the fake
+// releases.
+func buildReleaseCopy(t *testing.T, dir string) string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	path := filepath.Join(dir, "bin", "git-flow")
+	publicKey := base64.StdEncoding.EncodeToString(releaseKey.Public().(ed25519.PublicKey))
+	build := exec.Command("go", "build", "-o", path, "-ldflags", "-X github.com/gittower/git-flow-next/version.ReleasePublicKey="+publicKey, ".")
+	build.Dir = root
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build git-flow: %v\nOutput: %s", err, output)
+	}
+	return path
+}
+
+// runSelfUpdate runs self-update with the copied binary against server.
+func runSelfUpdate(t *testing.T, binary string, server *httptest.Server, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(binary, append([]string{"self-update"}, args...)...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), selfupdate.ReleasesURLEnv+"="+server.URL)
+	output, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(output), exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("Failed to run self-update: %v", err)
+	}
+	return string(output), 0
+}
+
+// TestSelfUpdateInstallsNewerRelease tests that self-update checks for and installs
+// a newer release.
+// Steps:
+// 1. Serves a fake v99.0.0 release and builds git-flow with its release key into a temporary directory
+// 2. Runs self-update --check and verifies the binary is unchanged
+// 3. Runs self-update and verifies the signature was checked and the binary replaced
+func TestSelfUpdateInstallsNewerRelease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake release binary is a shell script")
+	}
+	server := fakeRelease(t, "v99.0.0", "#!/bin/sh\necho updated-binary\n", false)
+	binary := buildReleaseCopy(t, t.TempDir())
+
+	output, code := runSelfUpdate(t, binary, server, "--check")
+	if code != 0 || !strings.Contains(output, "git-flow-next v99.0.0 is available") {
+		t.Fatalf("Expected newer release to be reported, got exit %d: %s", code, output)
+	}
+
+	output, code = runSelfUpdate(t, binary, server)
+	if code != 0 {
+		t.Fatalf("Expected self-update to succeed, got exit %d: %s", code, output)
+	}
+	if !strings.Contains(output, "Verified signature of") || !strings.Contains(output, "Verified checksum of") || !strings.Contains(output, "to v99.0.0") {
+		t.Errorf("Expected signature, checksum and update messages, got: %s", output)
+	}
+
+	result, err := exec.Command(binary).CombinedOutput()
+	if err != nil || strings.TrimSpace(string(result)) != "updated-binary" {
+		t.Errorf("Expected the binary to be replaced, got %q: %v", result, err)
+	}
+	info, err := os.Stat(binary)
+	if err != nil || info.Mode().Perm()&0111 == 0 {
+		t.Errorf("Expected the new binary to be executable: %v", err)
+	}
+}
+
+// TestSelfUpdateUpToDate tests that an older or equal release is not installed.
+func TestSelfUpdateUpToDate(t *testing.T) {
+	server := fakeRelease(t, "v0.1.0", "#!/bin/sh\necho old-binary\n", false)
+	binary := installCopy(t, t.TempDir())
+
+	output, code := runSelfUpdate(t, binary, server)
+	if code != 0 || !strings.Contains(output, "is up to date") {
+		t.Fatalf("Expected up to date message, got exit %d: %s", code, output)
+	}
+	if result, _ := exec.Command(binary, "version").CombinedOutput(); !strings.Contains(string(result), "git-flow-next version") {
+		t.Errorf("Expected the binary to be unchanged, got: %s", result)
+	}
+}
+
+// TestSelfUpdateRejectsBadChecksum tests that a download that doesn't match the
+// published checksum is not installed.
+func TestSelfUpdateRejectsBadChecksum(t *testing.T) {
+	server := fakeRelease(t, "v99.0.0", "#!/bin/sh\necho tampered-binary\n", true)
+	binary := buildReleaseCopy(t, t.TempDir())
+
+	output, code := runSelfUpdate(t, binary, server)
+	if code != 6 {
+		t.Errorf("Expected exit code 6, got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "refusing to install v99.0.0") || !strings.Contains(output, "does not match") {
+		t.Errorf("Expected checksum error, got: %s", output)
+	}
+	if result, _ := exec.Command(binary, "version").CombinedOutput(); !strings.Contains(string(result), "git-flow-next version") {
+		t.Errorf("Expected the binary to be unchanged, got: %s", result)
+	}
+}
+
+// TestSelfUpdateRefusesPackageManagerInstall tests that a binary installed by
+// Homebrew is not replaced.
+func TestSelfUpdateRefusesPackageManagerInstall(t *testing.T) {
+	server := fakeRelease(t, "v99.0.0", "#!/bin/sh\necho updated-binary\n", false)
+	binary := installCopy(t, filepath.Join(t.TempDir(), "Cellar", "git-flow-next", "1.0.0"))
+
+	output, code := runSelfUpdate(t, binary, server)
+	if code != 6 || !strings.Contains(output, "brew upgrade git-flow-next") {
+		t.Errorf("Expected Homebrew installation to be refused, got exit %d: %s", code, output)
+	}
+
+	output, code = runSelfUpdate(t, binary, server, "--check")
+	if code != 0 || !strings.Contains(output, "Update it with 'brew upgrade git-flow-next'") {
+		t.Errorf("Expected --check to suggest brew, got exit %d: %s", code, output)
+	}
+}
+
+// TestSelfUpdateRefusesWithoutReleaseKey tests that a build without a release key,
+// which cannot tell a genuine release from one of another server, installs nothing.
+// Steps:
+// 1. Serves a fake v99.0.0 release and copies the git-flow binary under test, built without a key
+// 2. Runs self-update --check and verifies the release is reported
+// 3. Runs self-update and verifies it fails with exit code 6 and leaves the binary unchanged
+func TestSelfUpdateRefusesWithoutReleaseKey(t *testing.T) {
+	server := fakeRelease(t, "v99.0.0", "#!/bin/sh\necho updated-binary\n", false)
+	binary := installCopy(t, t.TempDir())
+
+	output, code := runSelfUpdate(t, binary, server, "--check")
+	if code != 0 || !strings.Contains(output, "git-flow-next v99.0.0 is available") {
+		t.Fatalf("Expected newer release to be reported, got exit %d: %s", code, output)
+	}
+
+	output, code = runSelfUpdate(t, binary, server)
+	if code != 6 || !strings.Contains(output, "refusing to install v99.0.0: this build carries no release key") {
+		t.Errorf("Expected the update to be refused, got exit %d: %s", code, output)
+	}
+	if result, _ := exec.Command(binary, "version").CombinedOutput(); !strings.Contains(string(result), "git-flow-next version") {
+		t.Errorf("Expected the binary to be unchanged, got: %s", result)
+	}
+}
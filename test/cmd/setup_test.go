@@ -0,0 +1,137 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestSetupVersionMergeDriver tests that the version merge driver stops back-merges
+// from conflicting on the version file.
+// Steps:
+// 1. Sets up a repository with git-flow defaults and declares version.txt
+// 2. Runs 'git flow setup merge-driver version' twice
+// 3. Verifies the driver is configured and .gitattributes has a single entry
+// 4. Changes version.txt on main and on develop and merges main into develop
+// 5. Verifies the merge succeeds and develop keeps its version
+func TestSetupVersionMergeDriver(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	_, _ = testutil.RunGit(t, dir, "config", "--add", "gitflow.version.file", "version.txt")
+
+	for i := 0; i < 2; i++ {
+		output, err := testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version")
+		if err != nil {
+			t.Fatalf("Failed to set up the merge driver: %v\nOutput: %s", err, output)
+		}
+	}
+
+	driver, _ := testutil.RunGit(t, dir, "config", "merge.gitflow-version.driver")
+	if strings.TrimSpace(driver) != "true" {
+		t.Errorf("Expected the ours driver to be configured, got %q", driver)
+	}
+	attributes, err := os.ReadFile(filepath.Join(dir, ".gitattributes"))
+	if err != nil {
+		t.Fatalf("Failed to read .gitattributes: %v", err)
+	}
+	if count := strings.Count(string(attributes), "/version.txt merge=gitflow-version"); count != 1 {
+		t.Errorf("Expected one .gitattributes entry for version.txt, got %d:\n%s", count, attributes)
+	}
+
+	// Version bumps on both sides of a back-merge
+	_, _ = testutil.RunGit(t, dir, "checkout", "main")
+	testutil.WriteFile(t, dir, "version.txt", "1.0.0\n")
+	_, _ = testutil.RunGit(t, dir, "add", "version.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add version")
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+	_, _ = testutil.RunGit(t, dir, "merge", "main")
+	testutil.WriteFile(t, dir, "version.txt", "1.1.0-dev\n")
+	_, _ = testutil.RunGit(t, dir, "commit", "-am", "Bump develop version")
+	_, _ = testutil.RunGit(t, dir, "checkout", "main")
+	testutil.WriteFile(t, dir, "version.txt", "1.0.1\n")
+	_, _ = testutil.RunGit(t, dir, "commit", "-am", "Bump main version")
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+
+	if output, err := testutil.RunGit(t, dir, "merge", "--no-edit", "main"); err != nil {
+		t.Fatalf("Expected the back-merge to succeed: %v\nOutput: %s", err, output)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "version.txt"))
+	if string(content) != "1.1.0-dev\n" {
+		t.Errorf("Expected develop to keep its version, got %q", content)
+	}
+}
+
+// TestSetupVersionMergeDriverStatusAndRemove tests the status and remove subcommands.
+// Steps:
+// 1. Declares version.txt and writes an unrelated .gitattributes entry
+// 2. Sets up the merge driver with --strategy union
+// 3. Verifies status reports the driver and the assignment
+// 4. Removes the driver and verifies the config and the version entries are gone
+// 5. Verifies the unrelated .gitattributes entry is kept and status reports the removal
+func TestSetupVersionMergeDriverStatusAndRemove(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	_, _ = testutil.RunGit(t, dir, "config", "--add", "gitflow.version.file", "version.txt")
+	testutil.WriteFile(t, dir, ".gitattributes", "*.png binary\n")
+
+	if output, err := testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version", "--strategy", "union"); err != nil {
+		t.Fatalf("Failed to set up the merge driver: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version", "status")
+	if err != nil {
+		t.Fatalf("Failed to show status: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Merge driver:  configured (union)") || !strings.Contains(output, "version.txt: assigned in .gitattributes") {
+		t.Errorf("Expected status to report the union driver and the assignment, got: %s", output)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version", "remove"); err != nil {
+		t.Fatalf("Failed to remove the merge driver: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "merge.gitflow-version.driver"); err == nil {
+		t.Error("Expected the merge driver config to be removed")
+	}
+	attributes, err := os.ReadFile(filepath.Join(dir, ".gitattributes"))
+	if err != nil {
+		t.Fatalf("Failed to read .gitattributes: %v", err)
+	}
+	if string(attributes) != "*.png binary\n" {
+		t.Errorf("Expected only the unrelated entry to remain, got %q", attributes)
+	}
+
+	output, _ = testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version", "status")
+	if !strings.Contains(output, "Merge driver:  not configured") || !strings.Contains(output, "version.txt: not assigned in .gitattributes") {
+		t.Errorf("Expected status to report the removal, got: %s", output)
+	}
+}
+
+// TestSetupVersionMergeDriverWithoutVersionFiles tests that setup requires declared version files.
+// Steps:
+// 1. Sets up a repository without gitflow.version.file
+// 2. Runs 'git flow setup merge-driver version'
+// 3. Verifies it exits with status 2, names the config key and writes no .gitattributes
+func TestSetupVersionMergeDriverWithoutVersionFiles(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "setup", "merge-driver", "version")
+	exitErr, ok := err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != 2 {
+		t.Fatalf("Expected exit status 2, got %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "gitflow.version.file") {
+		t.Errorf("Expected the error to name gitflow.version.file, got: %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".gitattributes")); !os.IsNotExist(err) {
+		t.Error("Expected no .gitattributes to be written")
+	}
+}
@@ -2,6 +2,8 @@ package cmd_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -587,3 +589,409 @@ func TestStartStoresBaseBranch(t *testing.T) {
 		t.Errorf("Expected release base branch to be '%s', got '%s'", expectedReleaseBase, strings.TrimSpace(releaseBaseConfig))
 	}
 }
+
+// TestStartCustomTopicType tests the commands generated for a custom topic type.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Adds a custom topic type 'spike' based on develop
+// 3. Starts and finishes a spike branch with 'git flow spike'
+// 4. Starts another one with the generic 'git flow start spike' form
+// 5. Verifies both branches were created with the spike prefix
+func TestStartCustomTopicType(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err = testutil.RunGitFlow(t, dir, "config", "add", "topic", "spike", "develop", "--prefix=spike/")
+	if err != nil {
+		t.Fatalf("Failed to add topic type: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "spike", "start", "cache")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "cache.txt", "cache")
+	testutil.RunGit(t, dir, "add", "cache.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Try a cache")
+	output, err = testutil.RunGitFlow(t, dir, "spike", "finish", "cache")
+	if err != nil {
+		t.Fatalf("Failed to finish spike branch: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "spike/cache") {
+		t.Error("Expected spike branch to be deleted after finish")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "start", "spike", "queue", "main")
+	if err != nil {
+		t.Fatalf("Failed to start spike branch with the generic form: %v\nOutput: %s", err, output)
+	}
+	if testutil.GetCurrentBranch(t, dir) != "spike/queue" {
+		t.Errorf("Expected to be on spike/queue, got %s", testutil.GetCurrentBranch(t, dir))
+	}
+	base, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.spike/queue.base")
+	if strings.TrimSpace(base) != "main" {
+		t.Errorf("Expected the given base 'main' to be stored, got '%s'", strings.TrimSpace(base))
+	}
+}
+
+// TestStartGenericFormRejectsBaseBranchType tests that the generic start form only accepts topic types.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs 'git flow start develop test'
+// 3. Verifies the command fails with an unknown branch type error
+func TestStartGenericFormRejectsBaseBranchType(t *testing.T) {
+	// Setup
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "start", "develop", "test")
+	if err == nil {
+		t.Fatalf("Expected error when starting a base branch type, got: %s", output)
+	}
+	if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != int(errors.ExitCodeInvalidInput) {
+		t.Errorf("Expected exit code %d, got %d", errors.ExitCodeInvalidInput, exitErr.ExitCode)
+	}
+	if !strings.Contains(output, "unknown branch type: develop") {
+		t.Errorf("Expected unknown branch type error, got: %s", output)
+	}
+}
+
+// TestStartFromRemote tests that --from-remote bases the new branch on the fetched remote parent.
+// Steps:
+// 1. Sets up a repository with a remote
+// 2. Pushes a new commit to develop from another clone
+// 3. Runs 'git flow feature start fresh --from-remote' on main
+// 4. Verifies the feature starts at origin/develop without tracking it
+// 5. Verifies local develop was fast-forwarded and is stored as the base
+func TestStartFromRemote(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.PushFromClone(t, remoteDir, "develop", "remote-change.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "fresh", "--from-remote")
+	if err != nil {
+		t.Fatalf("Failed to start feature from remote: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Created branch 'feature/fresh' from 'origin/develop'") {
+		t.Errorf("Expected the branch to be created from origin/develop, got: %s", output)
+	}
+
+	featureTip, _ := testutil.RunGit(t, dir, "rev-parse", "feature/fresh")
+	remoteTip, _ := testutil.RunGit(t, dir, "rev-parse", "origin/develop")
+	developTip, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if featureTip != remoteTip {
+		t.Errorf("Expected feature/fresh to start at origin/develop %s, got %s", remoteTip, featureTip)
+	}
+	if developTip != remoteTip {
+		t.Errorf("Expected develop to be fast-forwarded to origin/develop, got %s", developTip)
+	}
+	if upstream, err := testutil.RunGit(t, dir, "config", "branch.feature/fresh.merge"); err == nil {
+		t.Errorf("Expected feature/fresh not to track a remote branch, got %q", upstream)
+	}
+	base, _ := testutil.RunGit(t, dir, "config", "--get", "gitflow.branch.feature/fresh.base")
+	if strings.TrimSpace(base) != "develop" {
+		t.Errorf("Expected 'develop' to be stored as the base, got '%s'", strings.TrimSpace(base))
+	}
+}
+
+// TestStartFromRemoteConfig tests that gitflow.start.fromRemote applies to all types and --no-from-remote overrides it.
+// Steps:
+// 1. Sets up a repository with a remote and sets gitflow.start.fromRemote=true
+// 2. Commits to local develop and pushes another commit to develop from a clone
+// 3. Starts a feature and verifies it starts at origin/develop while local develop is left alone
+// 4. Starts a second feature with --no-from-remote and verifies it starts at local develop
+func TestStartFromRemoteConfig(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.start.fromRemote", "true")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "local-change.txt", "local")
+	testutil.RunGit(t, dir, "add", "local-change.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Local change")
+	developTip, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	testutil.PushFromClone(t, remoteDir, "develop", "remote-change.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "remote")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "diverged from 'origin/develop'") {
+		t.Errorf("Expected the diverged local develop to be reported, got: %s", output)
+	}
+	featureTip, _ := testutil.RunGit(t, dir, "rev-parse", "feature/remote")
+	remoteTip, _ := testutil.RunGit(t, dir, "rev-parse", "origin/develop")
+	if featureTip != remoteTip {
+		t.Errorf("Expected feature/remote to start at origin/develop %s, got %s", remoteTip, featureTip)
+	}
+	if after, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); after != developTip {
+		t.Error("Expected the diverged local develop to be left alone")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "local", "--no-from-remote")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if featureTip, _ := testutil.RunGit(t, dir, "rev-parse", "feature/local"); featureTip != developTip {
+		t.Errorf("Expected feature/local to start at local develop %s, got %s", developTip, featureTip)
+	}
+}
+
+// TestStartRefusesDuplicateTopicName tests that gitflow.uniqueTopicNames refuses a short name used by another topic type.
+// Steps:
+// 1. Sets up a repository with a remote and enables gitflow.uniqueTopicNames
+// 2. Starts feature/login, and pushes hotfix/crash from another clone
+// 3. Verifies 'bugfix start login' and 'bugfix start crash' fail with exit code 4
+// 4. Verifies 'bugfix start other' succeeds, and the check is off without the setting
+func TestStartRefusesDuplicateTopicName(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.uniqueTopicNames", "true")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	cloneDir := t.TempDir()
+	testutil.RunGit(t, cloneDir, "clone", remoteDir, ".")
+	testutil.RunGit(t, cloneDir, "push", "origin", "origin/main:refs/heads/hotfix/crash")
+	testutil.RunGit(t, dir, "fetch", "origin")
+
+	for name, existing := range map[string]string{"login": "feature/login", "crash": "hotfix/crash"} {
+		output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", name)
+		if err == nil {
+			t.Fatalf("Expected bugfix start %s to fail, got: %s", name, output)
+		}
+		if exitErr, ok := err.(*testutil.ExitError); ok && exitErr.ExitCode != int(errors.ExitCodeBranchExists) {
+			t.Errorf("Expected exit code %d, got %d", errors.ExitCodeBranchExists, exitErr.ExitCode)
+		}
+		if !strings.Contains(output, "already used by branch '"+existing+"'") {
+			t.Errorf("Expected the conflict with %s to be reported, got: %s", existing, output)
+		}
+		if testutil.BranchExists(t, dir, "bugfix/"+name) {
+			t.Errorf("Expected bugfix/%s not to be created", name)
+		}
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "other"); err != nil {
+		t.Fatalf("Failed to start bugfix with a unique name: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.uniqueTopicNames", "false")
+	if output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "login"); err != nil {
+		t.Fatalf("Failed to start bugfix without gitflow.uniqueTopicNames: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestStartSlugify tests that gitflow.start.slugify normalizes free-text branch names.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and sets gitflow.start.slugify=true
+// 2. Runs 'git flow feature start "Add Ümlaut Support"'
+// 3. Verifies feature/add-umlaut-support is created and the normalized name is shown
+// 4. Installs a version filter for features and verifies it takes precedence over slugify
+func TestStartSlugify(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.start.slugify", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "Add Ümlaut Support")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Normalized branch name 'Add Ümlaut Support' to 'add-umlaut-support'") {
+		t.Errorf("Expected the normalized name to be shown, got: %s", output)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "feature/add-umlaut-support" {
+		t.Errorf("Expected to be on feature/add-umlaut-support, got %s", current)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	createHookScript(t, dir, "filter-flow-feature-start-version", `#!/bin/sh
+echo "filtered-$1"
+`)
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", "Other")
+	if err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/filtered-Other") {
+		t.Errorf("Expected the version filter to take precedence, got: %s", output)
+	}
+}
+
+// TestStartPublish tests that --publish pushes the new branch, sets up tracking and runs the publish hooks.
+// Steps:
+// 1. Sets up a repository with a remote and installs a post-flow-feature-publish hook
+// 2. Runs 'git flow feature start early-ci --publish'
+// 3. Verifies the branch exists on the remote and tracks it
+// 4. Verifies the publish hook ran
+func TestStartPublish(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	createHookScript(t, dir, "post-flow-feature-publish", `#!/bin/sh
+echo "$BRANCH" > "$(git rev-parse --git-dir)/published"
+`)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "early-ci", "--publish")
+	if err != nil {
+		t.Fatalf("Failed to start and publish feature: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/early-ci"); err != nil {
+		t.Error("Expected feature/early-ci to be pushed to the remote")
+	}
+	upstream, _ := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "feature/early-ci@{upstream}")
+	if strings.TrimSpace(upstream) != "origin/feature/early-ci" {
+		t.Errorf("Expected feature/early-ci to track origin/feature/early-ci, got %q", upstream)
+	}
+	published, err := os.ReadFile(filepath.Join(dir, ".git", "published"))
+	if err != nil || strings.TrimSpace(string(published)) != "feature/early-ci" {
+		t.Errorf("Expected the publish hook to run for feature/early-ci, got %q\nOutput: %s", published, output)
+	}
+}
+
+// TestStartPublishConfig tests that gitflow.start.publish publishes by default and --no-publish overrides it.
+// Steps:
+// 1. Sets up a repository with a remote and sets gitflow.start.publish=true
+// 2. Starts a feature and verifies it is pushed
+// 3. Starts another feature with --no-publish and verifies it is not pushed
+func TestStartPublishConfig(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.start.publish", "true")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "shared"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/shared"); err != nil {
+		t.Error("Expected feature/shared to be pushed to the remote")
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "private", "--no-publish"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, remoteDir, "rev-parse", "--verify", "refs/heads/feature/private"); err == nil {
+		t.Error("Expected feature/private not to be pushed with --no-publish")
+	}
+}
+
+// TestStartReleaseTagConflict tests that starting a release fails when the tag finish would create is taken.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow with defaults and sets the release tag prefix to 'v'
+// 2. Creates tag v1.0.0 and runs 'git flow release start 1.0.0'
+// 3. Verifies it fails with exit code 4 and release/1.0.0 is not created
+// 4. Creates branch v1.1.0 and verifies 'git flow release start 1.1.0' fails the same way
+// 5. Verifies 'git flow release start 1.2.0' succeeds
+func TestStartReleaseTagConflict(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "config", "gitflow.branch.release.tagprefix", "v")
+
+	testutil.RunGit(t, dir, "tag", "v1.0.0")
+	output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0")
+	assertExitCode(t, err, errors.ExitCodeBranchExists, output)
+	if !strings.Contains(output, "tag 'v1.0.0' already exists") {
+		t.Errorf("Expected the existing tag to be reported\nOutput: %s", output)
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release/1.0.0 not to be created")
+	}
+
+	testutil.RunGit(t, dir, "branch", "v1.1.0", "main")
+	output, err = testutil.RunGitFlow(t, dir, "release", "start", "1.1.0")
+	assertExitCode(t, err, errors.ExitCodeBranchExists, output)
+	if !strings.Contains(output, "branch 'v1.1.0' has the same name as the tag") {
+		t.Errorf("Expected the colliding branch to be reported\nOutput: %s", output)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "release", "start", "1.2.0"); err != nil {
+		t.Fatalf("Failed to start release 1.2.0: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestStartFeatureIgnoresTags tests that a tag named like a feature does not block starting it, as features are not tagged.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Creates tag 'login' and runs 'git flow feature start login'
+// 3. Verifies the feature is created
+func TestStartFeatureIgnoresTags(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "tag", "login")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestStartTracksUpstream tests that start never tracks its start point and tracks an existing remote branch of the same name.
+// Steps:
+// 1. Sets up a repository with a remote and starts a feature from origin/develop
+// 2. Verifies the new branch has no upstream
+// 3. Pushes a feature branch with plain git, deletes it locally and starts it again
+// 4. Verifies the restarted branch tracks origin/feature/again
+// 5. Repeats with --no-track-upstream and verifies no upstream is set
+func TestStartTracksUpstream(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "from-remote", "origin/develop"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if upstream, err := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "feature/from-remote@{upstream}"); err == nil {
+		t.Errorf("Expected feature/from-remote not to track its start point, got %q", upstream)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "again"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "push", "origin", "feature/again")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/again")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "start", "again")
+	if err != nil {
+		t.Fatalf("Failed to restart feature: %v\nOutput: %s", err, output)
+	}
+	upstream, _ := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "feature/again@{upstream}")
+	if strings.TrimSpace(upstream) != "origin/feature/again" {
+		t.Errorf("Expected feature/again to track origin/feature/again, got %q\nOutput: %s", upstream, output)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/again")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "again", "--no-track-upstream"); err != nil {
+		t.Fatalf("Failed to restart feature: %v\nOutput: %s", err, output)
+	}
+	if upstream, err := testutil.RunGit(t, dir, "config", "--get", "branch.feature/again.remote"); err == nil {
+		t.Errorf("Expected no upstream with --no-track-upstream, got remote %q", upstream)
+	}
+}
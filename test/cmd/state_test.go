@@ -0,0 +1,276 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// startConflictingFinish creates a feature branch that conflicts with develop and
+// runs finish so that it stops in the merge step with recorded state.
+func startConflictingFinish(t *testing.T, dir string, name string) {
+	t.Helper()
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "start", name)
+	if err != nil {
+		t.Fatalf("Failed to create feature: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "conflict.txt", "Feature version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Feature changes")
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "conflict.txt", "Develop version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop changes")
+
+	testutil.RunGit(t, dir, "checkout", "feature/"+name)
+	output, _ = testutil.RunGitFlow(t, dir, "feature", "finish", name)
+	if !strings.Contains(output, "conflict") {
+		t.Fatalf("Expected merge conflict, got: %s", output)
+	}
+}
+
+// writeStateFile overwrites the merge state file with raw content.
+func writeStateFile(t *testing.T, dir string, content string) string {
+	t.Helper()
+	stateDir := filepath.Join(dir, ".git", "gitflow", "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		t.Fatalf("Failed to create state directory: %v", err)
+	}
+	statePath := filepath.Join(stateDir, "merge.json")
+	if err := os.WriteFile(statePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+	return statePath
+}
+
+// TestStateShowNoOperation tests that 'state show' reports when nothing is in progress.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow
+// 2. Runs 'git flow state show'
+// 3. Verifies it reports that no operation is in progress
+func TestStateShowNoOperation(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "state", "show")
+	if err != nil {
+		t.Fatalf("Failed to show state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No git-flow operation in progress") {
+		t.Errorf("Expected no operation message, got: %s", output)
+	}
+}
+
+// TestStateShowDuringConflict tests that 'state show' prints the recorded finish state.
+// Steps:
+// 1. Sets up a finish that stops on a merge conflict
+// 2. Runs 'git flow state show'
+// 3. Verifies the operation, branch, target and step are shown without problems
+func TestStateShowDuringConflict(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	startConflictingFinish(t, dir, "show-state")
+
+	output, err := testutil.RunGitFlow(t, dir, "state", "show")
+	if err != nil {
+		t.Fatalf("Failed to show state: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"Operation:       finish",
+		"Branch:          feature/show-state (feature)",
+		"Target:          develop",
+		"Current step:    merge",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Problems:") {
+		t.Errorf("Expected no problems for a valid state, got: %s", output)
+	}
+}
+
+// TestStateRepairDiscardsCorruptState tests that an unreadable state file can be discarded.
+// Steps:
+// 1. Sets up a test repository and writes a truncated state file
+// 2. Runs 'git flow state show' and verifies it reports the file as unreadable
+// 3. Runs 'git flow state repair' answering 'y'
+// 4. Verifies the state file is removed
+func TestStateRepairDiscardsCorruptState(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "init", "--defaults")
+	if err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	statePath := writeStateFile(t, dir, `{"action":"finish","branchTy`)
+
+	output, err = testutil.RunGitFlow(t, dir, "state", "show")
+	if err != nil {
+		t.Fatalf("Failed to show state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "unreadable") {
+		t.Errorf("Expected unreadable state to be reported, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlowWithInput(t, dir, "y\n", "state", "repair")
+	if err != nil {
+		t.Fatalf("Failed to repair state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Discarded merge state") {
+		t.Errorf("Expected discard message, got: %s", output)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Error("Expected state file to be removed")
+	}
+}
+
+// TestStateRepairReconstructsManuallyResolvedMerge tests reconstructing state after a merge was completed by hand.
+// Steps:
+// 1. Sets up a finish that stops on a merge conflict
+// 2. Resolves the conflict and commits the merge with plain git
+// 3. Runs 'git flow state repair --reconstruct'
+// 4. Verifies the state advances past the merge step
+// 5. Runs finish --continue and verifies the feature branch is deleted
+func TestStateRepairReconstructsManuallyResolvedMerge(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	startConflictingFinish(t, dir, "manual")
+
+	testutil.WriteFile(t, dir, "conflict.txt", "Resolved version")
+	testutil.RunGit(t, dir, "add", "conflict.txt")
+	if _, err := testutil.RunGit(t, dir, "commit", "--no-edit"); err != nil {
+		t.Fatalf("Failed to commit merge: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "state", "repair", "--reconstruct")
+	if err != nil {
+		t.Fatalf("Failed to repair state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "'feature/manual' is already merged into 'develop'") {
+		t.Errorf("Expected stale merge to be detected, got: %s", output)
+	}
+
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil || state == nil {
+		t.Fatalf("Expected reconstructed merge state: %v", err)
+	}
+	if state.CurrentStep != "create_tag" {
+		t.Errorf("Expected CurrentStep to be 'create_tag', got: %s", state.CurrentStep)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "manual")
+	if err != nil {
+		t.Fatalf("Failed to continue finish: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/manual") {
+		t.Error("Expected feature branch to be deleted after finish")
+	}
+}
+
+// TestStateRepairMissingBranch tests that state referencing a deleted branch can only be discarded.
+// Steps:
+// 1. Sets up a finish that stops on a merge conflict
+// 2. Aborts the Git merge by hand and deletes the feature branch
+// 3. Runs 'git flow state repair --reconstruct' and verifies it fails
+// 4. Runs 'git flow state repair --discard' and verifies the state is removed
+func TestStateRepairMissingBranch(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	startConflictingFinish(t, dir, "gone")
+
+	testutil.RunGit(t, dir, "merge", "--abort")
+	if _, err := testutil.RunGit(t, dir, "branch", "-D", "feature/gone"); err != nil {
+		t.Fatalf("Failed to delete feature branch: %v", err)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "state", "repair", "--reconstruct")
+	if err == nil {
+		t.Fatalf("Expected reconstruct to fail, got output: %s", output)
+	}
+	if !strings.Contains(output, "branch 'feature/gone' no longer exists") {
+		t.Errorf("Expected missing branch to be reported, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "state", "repair", "--discard")
+	if err != nil {
+		t.Fatalf("Failed to discard state: %v\nOutput: %s", err, output)
+	}
+	if state, _ := testutil.LoadMergeState(t, dir); state != nil {
+		t.Error("Expected merge state to be removed")
+	}
+}
+
+// TestStateRepairRestoresBackup tests that an unreadable state file can be replaced by its backup.
+// Steps:
+// 1. Sets up a finish that stops on a merge conflict
+// 2. Copies the state file to merge.json.bak and truncates merge.json
+// 3. Runs finish --continue and verifies it refuses to resume from the backup and points to state repair
+// 4. Runs 'git flow state repair --reconstruct'
+// 5. Verifies merge.json is readable again and matches the backup
+func TestStateRepairRestoresBackup(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	startConflictingFinish(t, dir, "restore")
+
+	statePath := filepath.Join(dir, ".git", "gitflow", "state", "merge.json")
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if err := os.WriteFile(statePath+".bak", data, 0644); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	truncated := string(data[:len(data)/2])
+	writeStateFile(t, dir, truncated)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "--continue", "restore")
+	assertExitCode(t, err, errors.ExitCodeGitError, output)
+	for _, expected := range []string{"is unreadable", "git flow state repair", "runs step 'merge' again"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+	if current, _ := os.ReadFile(statePath); string(current) != truncated {
+		t.Error("Expected the unreadable state file to be left for repair")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "state", "repair", "--reconstruct")
+	if err != nil {
+		t.Fatalf("Failed to repair state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Restored merge state from backup") {
+		t.Errorf("Expected restore message, got: %s", output)
+	}
+
+	state, err := testutil.LoadMergeState(t, dir)
+	if err != nil || state == nil {
+		t.Fatalf("Expected readable merge state: %v", err)
+	}
+	if state.FullBranchName != "feature/restore" {
+		t.Errorf("Expected restored state for 'feature/restore', got '%s'", state.FullBranchName)
+	}
+}
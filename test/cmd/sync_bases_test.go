@@ -0,0 +1,280 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// assertExitCode fails the test unless err carries the expected exit code.
+func assertExitCode(t *testing.T, err error, expected errors.ExitCode, output string) {
+	t.Helper()
+	exitErr, ok := err.(*testutil.ExitError)
+	if !ok || exitErr.ExitCode != int(expected) {
+		t.Fatalf("Expected exit code %d, got: %v\nOutput: %s", expected, err, output)
+	}
+}
+
+// setupStagingBase initializes git-flow and adds staging as a base branch below develop.
+func setupStagingBase(t *testing.T, dir string) {
+	t.Helper()
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "branch", "staging", "develop")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.staging.type", "base")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.staging.parent", "develop")
+}
+
+// commitOn commits file with content to branch and switches back to the previous branch.
+func commitOn(t *testing.T, dir string, branch string, file string, content string) {
+	t.Helper()
+	current := testutil.GetCurrentBranch(t, dir)
+	testutil.RunGit(t, dir, "checkout", branch)
+	testutil.WriteFile(t, dir, file, content)
+	testutil.RunGit(t, dir, "add", file)
+	testutil.RunGit(t, dir, "commit", "-m", "Change "+file+" on "+branch)
+	testutil.RunGit(t, dir, "checkout", current)
+}
+
+// TestSyncBasesCascadesDownTheHierarchy tests that sync-bases updates each base branch from its parent, top down.
+// Steps:
+// 1. Sets up a repository with staging as a base branch below develop
+// 2. Commits a change to main and starts a feature branch
+// 3. Runs git flow sync-bases and verifies it exits with 7, develop contains main and staging contains develop
+// 4. Verifies the feature branch is checked out again and the summary lists both branches
+// 5. Runs git flow sync-bases again and verifies it exits with 0 and both branches are reported up to date
+func TestSyncBasesCascadesDownTheHierarchy(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupStagingBase(t, dir)
+	commitOn(t, dir, "main", "hotfix.txt", "hotfix")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "work"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to contain main")
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "staging"); err != nil {
+		t.Error("Expected staging to contain develop")
+	}
+	if developIndex, stagingIndex := strings.Index(output, "Updating base branch 'develop' from 'main'"), strings.Index(output, "Updating base branch 'staging' from 'develop'"); developIndex < 0 || stagingIndex < developIndex {
+		t.Errorf("Expected develop to be updated before staging, got: %s", output)
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "feature/work" {
+		t.Errorf("Expected feature/work to be checked out again, got %s", current)
+	}
+	if !strings.Contains(output, "updated from 'develop' (strategy: merge)") {
+		t.Errorf("Expected the summary to list staging as updated, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "sync-bases")
+	if err != nil {
+		t.Fatalf("Failed to sync bases again: %v\nOutput: %s", err, output)
+	}
+	if strings.Contains(output, "Updating base branch") {
+		t.Errorf("Expected no branch to be updated, got: %s", output)
+	}
+	if !strings.Contains(output, "up to date with 'main'") || !strings.Contains(output, "up to date with 'develop'") {
+		t.Errorf("Expected both branches to be reported up to date, got: %s", output)
+	}
+}
+
+// TestSyncBasesContinueAfterConflict tests that a conflict stops sync-bases and --continue resumes the cascade.
+// Steps:
+// 1. Sets up a repository with staging below develop and conflicting changes on main and develop
+// 2. Runs git flow sync-bases and verifies it stops on the conflict with exit code 8 and its state saved
+// 3. Resolves the conflict and runs git flow sync-bases --continue
+// 4. Verifies develop and staging are updated and the state is cleared
+func TestSyncBasesContinueAfterConflict(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupStagingBase(t, dir)
+	commitOn(t, dir, "main", "version.txt", "1.0.1")
+	commitOn(t, dir, "develop", "version.txt", "1.1.0-dev")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases")
+	assertExitCode(t, err, errors.ExitCodeConflict, output)
+	if !strings.Contains(output, "git flow sync-bases --continue") {
+		t.Errorf("Expected instructions to continue, got: %s", output)
+	}
+	if !testutil.FileExists(t, filepath.Join(dir, ".git", "gitflow", "state"), "merge.json") {
+		t.Fatal("Expected the merge state to be saved")
+	}
+
+	testutil.WriteFile(t, dir, "version.txt", "1.1.0-dev")
+	testutil.RunGit(t, dir, "add", "version.txt")
+
+	output, err = testutil.RunGitFlow(t, dir, "sync-bases", "--continue")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected develop to contain main")
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "staging"); err != nil {
+		t.Error("Expected staging to contain develop")
+	}
+	if testutil.FileExists(t, filepath.Join(dir, ".git", "gitflow", "state"), "merge.json") {
+		t.Error("Expected the merge state to be cleared")
+	}
+}
+
+// TestSyncBasesAbort tests that --abort undoes the update of the current branch and clears the state.
+// Steps:
+// 1. Sets up a repository with conflicting changes on main and develop
+// 2. Runs git flow sync-bases and verifies it stops on the conflict
+// 3. Runs git flow sync-bases --abort
+// 4. Verifies develop is unchanged, the original branch is checked out and the state is cleared
+func TestSyncBasesAbort(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupStagingBase(t, dir)
+	commitOn(t, dir, "main", "version.txt", "1.0.1")
+	commitOn(t, dir, "develop", "version.txt", "1.1.0-dev")
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	startBranch := testutil.GetCurrentBranch(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "sync-bases"); err == nil {
+		t.Fatalf("Expected sync-bases to stop on the conflict, got: %s", output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases", "--abort")
+	if err != nil {
+		t.Fatalf("Failed to abort sync-bases: %v\nOutput: %s", err, output)
+	}
+	if developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); developAfter != developBefore {
+		t.Error("Expected develop to be unchanged after abort")
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != startBranch {
+		t.Errorf("Expected %s to be checked out again, got %s", startBranch, current)
+	}
+	if testutil.FileExists(t, filepath.Join(dir, ".git", "gitflow", "state"), "merge.json") {
+		t.Error("Expected the merge state to be cleared")
+	}
+}
+
+// TestSyncBasesReconcilesSkippedUpdates tests that sync-bases records updates skipped with finish --no-update as reconciled.
+// Steps:
+// 1. Initializes git-flow and finishes a hotfix with --no-update develop
+// 2. Runs git flow sync-bases
+// 3. Verifies develop contains main and the journal records the reconciled update
+func TestSyncBasesReconcilesSkippedUpdates(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "start", "1.0.1"); err != nil {
+		t.Fatalf("Failed to start hotfix: %v\nOutput: %s", err, output)
+	}
+	testutil.WriteFile(t, dir, "fix.txt", "fix")
+	testutil.RunGit(t, dir, "add", "fix.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Fix")
+	if output, err := testutil.RunGitFlow(t, dir, "hotfix", "finish", "1.0.1", "--no-update", "develop"); err != nil {
+		t.Fatalf("Failed to finish hotfix: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+	if !strings.Contains(output, "Reconciled updates skipped by finish: 'develop' from 'main'") {
+		t.Errorf("Expected the skipped update to be reconciled, got: %s", output)
+	}
+
+	journal, err := os.ReadFile(filepath.Join(dir, ".git", "gitflow", "journal"))
+	if err != nil {
+		t.Fatalf("Failed to read the journal: %v", err)
+	}
+	if !strings.Contains(string(journal), `"reconciledUpdates":[{"branch":"develop","parent":"main"}]`) {
+		t.Errorf("Expected the reconciled update in the journal, got: %s", journal)
+	}
+}
+
+// TestSyncBasesCheck tests that --check reports the branches behind their parent without updating them.
+// Steps:
+// 1. Sets up a repository with staging below develop and commits a change to main
+// 2. Runs git flow sync-bases --check and verifies it exits with 7 and reports develop as behind
+// 3. Verifies develop was not updated and no state was saved
+// 4. Runs git flow sync-bases, then --check again and verifies it exits with 0
+func TestSyncBasesCheck(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupStagingBase(t, dir)
+	commitOn(t, dir, "main", "hotfix.txt", "hotfix")
+	developBefore, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases", "--check")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+	if !strings.Contains(output, "1 commit(s) behind 'main', would be updated (strategy: merge)") {
+		t.Errorf("Expected develop to be reported behind main, got: %s", output)
+	}
+	if developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); developAfter != developBefore {
+		t.Error("Expected --check not to update develop")
+	}
+	if testutil.FileExists(t, filepath.Join(dir, ".git", "gitflow", "state"), "merge.json") {
+		t.Error("Expected --check not to save a merge state")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "sync-bases")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+	if output, err := testutil.RunGitFlow(t, dir, "sync-bases", "--check"); err != nil {
+		t.Fatalf("Expected --check to exit with 0 once the branches are up to date: %v\nOutput: %s", err, output)
+	}
+}
+
+// TestSyncBasesPush tests that --push updates the base branches from the remote and pushes the updated branches.
+// Steps:
+// 1. Sets up a repository with a remote and pushes a commit to main from another clone
+// 2. Deletes the local develop branch, as in a fresh CI clone
+// 3. Runs git flow sync-bases --push
+// 4. Verifies develop is recreated from the remote, updated from main and pushed
+func TestSyncBasesPush(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.PushFromClone(t, remoteDir, "main", "main-change.txt")
+	testutil.RunGit(t, dir, "checkout", "main")
+	testutil.RunGit(t, dir, "branch", "-D", "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases", "--push")
+	assertExitCode(t, err, errors.ExitCodeUpdated, output)
+	if !strings.Contains(output, "Created local branch 'develop' from 'origin/develop'") {
+		t.Errorf("Expected develop to be created from the remote, got: %s", output)
+	}
+
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "origin/main", "develop"); err != nil {
+		t.Error("Expected develop to contain the new commit on main")
+	}
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	remote, _ := testutil.RunGit(t, remoteDir, "rev-parse", "develop")
+	if local != remote {
+		t.Errorf("Expected the updated develop to be pushed, got local %s and remote %s", local, remote)
+	}
+}
+
+// TestSyncBasesCheckRejectsPush tests that --check cannot be combined with --push.
+// Steps:
+// 1. Initializes git-flow
+// 2. Runs git flow sync-bases --check --push and verifies it fails with exit code 2
+func TestSyncBasesCheckRejectsPush(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	output, err := testutil.RunGitFlow(t, dir, "sync-bases", "--check", "--push")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+}
@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestSyncFastForwardsBasesAndUpdatesTopic tests that sync fetches, fast-forwards the base branches and updates the current topic branch.
+// Steps:
+// 1. Sets up a repository with a remote and starts a feature branch
+// 2. Pushes new commits to main and develop from another clone
+// 3. Runs git flow sync on the feature branch
+// 4. Verifies main and develop were fast-forwarded to their remote branches
+// 5. Verifies the feature branch contains the new develop commit and the summary lists every branch
+func TestSyncFastForwardsBasesAndUpdatesTopic(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "daily"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.PushFromClone(t, remoteDir, "main", "main-change.txt")
+	testutil.PushFromClone(t, remoteDir, "develop", "develop-change.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync")
+	if err != nil {
+		t.Fatalf("Failed to sync: %v\nOutput: %s", err, output)
+	}
+
+	for _, branch := range []string{"main", "develop"} {
+		local, _ := testutil.RunGit(t, dir, "rev-parse", branch)
+		remote, _ := testutil.RunGit(t, dir, "rev-parse", "origin/"+branch)
+		if local != remote {
+			t.Errorf("Expected %s to be fast-forwarded to origin/%s", branch, branch)
+		}
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "feature/daily"); err != nil {
+		t.Error("Expected the feature branch to be updated from develop")
+	}
+	if current := testutil.GetCurrentBranch(t, dir); current != "feature/daily" {
+		t.Errorf("Expected to stay on feature/daily, got %s", current)
+	}
+
+	for _, line := range []string{
+		"main           fast-forwarded 1 commit(s) from 'origin/main'",
+		"develop        fast-forwarded 1 commit(s) from 'origin/develop'",
+		"feature/daily  updated from its parent",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected summary line %q, got: %s", line, output)
+		}
+	}
+}
+
+// TestSyncSkipsDivergedBase tests that sync leaves a base branch with unpushed commits alone.
+// Steps:
+// 1. Sets up a repository with a remote
+// 2. Commits to develop locally and pushes another commit to develop from a clone
+// 3. Runs git flow sync on main
+// 4. Verifies develop was not changed and is reported as diverged
+func TestSyncSkipsDivergedBase(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "local-change.txt", "local")
+	testutil.RunGit(t, dir, "add", "local-change.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Local change")
+	before, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	testutil.RunGit(t, dir, "checkout", "main")
+	testutil.PushFromClone(t, remoteDir, "develop", "remote-change.txt")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync")
+	if err != nil {
+		t.Fatalf("Failed to sync: %v\nOutput: %s", err, output)
+	}
+
+	after, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	if after != before {
+		t.Error("Expected develop to be left alone")
+	}
+	if !strings.Contains(output, "diverged from 'origin/develop' (1 local, 1 remote commit(s)), not fast-forwarded") {
+		t.Errorf("Expected develop to be reported as diverged, got: %s", output)
+	}
+	if !strings.Contains(output, "main     up to date") {
+		t.Errorf("Expected main to be reported as up to date, got: %s", output)
+	}
+}
+
+// TestSyncWithoutRemote tests that sync updates the current topic branch when no remote is configured.
+// Steps:
+// 1. Sets up a repository without a remote and starts a feature branch
+// 2. Commits to develop
+// 3. Runs git flow sync on the feature branch
+// 4. Verifies the fetch was skipped and the feature branch was updated from develop
+func TestSyncWithoutRemote(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.InitGitFlow(t, dir)
+	testutil.StartBranchWithCommit(t, dir, "feature", "offline")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop-change.txt", "develop")
+	testutil.RunGit(t, dir, "add", "develop-change.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Develop change")
+	testutil.RunGit(t, dir, "checkout", "feature/offline")
+
+	output, err := testutil.RunGitFlow(t, dir, "sync")
+	if err != nil {
+		t.Fatalf("Failed to sync: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No remote configured") {
+		t.Errorf("Expected the fetch to be skipped, got: %s", output)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "feature/offline"); err != nil {
+		t.Error("Expected the feature branch to be updated from develop")
+	}
+}
@@ -0,0 +1,116 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestTagPrerelease tests that tag prerelease numbers the prerelease tags of a channel on develop.
+// Steps:
+// 1. Sets up a repository, tags main as 1.0.0 and adds a commit to develop
+// 2. Runs tag prerelease --channel beta; verifies 1.1.0-beta.1 is created on develop
+// 3. Runs it again; verifies the unchanged head reports the existing tag
+// 4. Adds a commit and runs it again; verifies 1.1.0-beta.2 points at the new head
+// 5. Runs it with an unknown channel and verifies it is refused
+func TestTagPrerelease(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGit(t, dir, "tag", "1.0.0", "main"); err != nil {
+		t.Fatalf("Failed to tag main: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "develop", "first.txt", "first")
+
+	output, err := testutil.RunGitFlow(t, dir, "tag", "prerelease", "--channel", "beta")
+	if err != nil {
+		t.Fatalf("Failed to tag prerelease: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Tagged 'develop'") || !strings.Contains(output, "'1.1.0-beta.1'") {
+		t.Errorf("Expected develop to be tagged as 1.1.0-beta.1, got:\n%s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "beta")
+	if err != nil {
+		t.Fatalf("Expected the unchanged head to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "already tagged as '1.1.0-beta.1'") {
+		t.Errorf("Expected the existing tag to be reported, got:\n%s", output)
+	}
+
+	commitOn(t, dir, "develop", "second.txt", "second")
+	if output, err := testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "beta"); err != nil {
+		t.Fatalf("Failed to tag second prerelease: %v\nOutput: %s", err, output)
+	}
+	head, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	tagged, _ := testutil.RunGit(t, dir, "rev-parse", "1.1.0-beta.2^{commit}")
+	if tagged != head {
+		t.Errorf("Expected 1.1.0-beta.2 at %s, got %s", strings.TrimSpace(head), strings.TrimSpace(tagged))
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "gamma")
+	assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+	if !strings.Contains(output, "unknown prerelease channel 'gamma'") {
+		t.Errorf("Expected the unknown channel to be refused, got:\n%s", output)
+	}
+}
+
+// TestTagPrereleaseConfiguredChannel tests that channel formats, version bumps and --version are honored.
+// Steps:
+// 1. Sets up a repository and tags main as 2.3.1
+// 2. Configures the nightly format with a date and a patch bump
+// 3. Runs tag prerelease --channel nightly --dry-run; verifies the tag name and that no tag is created
+// 4. Runs tag prerelease --channel alpha --version 3.0; verifies 3.0.0-alpha.1 is created
+func TestTagPrereleaseConfiguredChannel(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "tag", "2.3.1", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.prerelease.nightly.format", "%v-nightly.%d.%n")
+	testutil.RunGit(t, dir, "config", "gitflow.prerelease.bump", "patch")
+
+	output, err := testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "nightly", "--dry-run")
+	if err != nil {
+		t.Fatalf("Failed to preview prerelease: %v\nOutput: %s", err, output)
+	}
+	expected := "2.3.2-nightly." + time.Now().UTC().Format("20060102") + ".1"
+	if !strings.Contains(output, "Would tag 'develop'") || !strings.Contains(output, expected) {
+		t.Errorf("Expected %s to be previewed, got:\n%s", expected, output)
+	}
+	if tags, _ := testutil.RunGit(t, dir, "tag", "--list", "*nightly*"); tags != "" {
+		t.Errorf("Expected --dry-run to create no tag, got:\n%s", tags)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "alpha", "--version", "3.0"); err != nil {
+		t.Fatalf("Failed to tag prerelease: %v\nOutput: %s", err, output)
+	}
+	if tags, _ := testutil.RunGit(t, dir, "tag", "--list", "3.0.0-alpha.1"); strings.TrimSpace(tags) != "3.0.0-alpha.1" {
+		t.Errorf("Expected 3.0.0-alpha.1 to be created, got %q", tags)
+	}
+}
+
+// TestTagPrereleasePush tests that tag prerelease --push pushes the prerelease tag.
+// Steps:
+// 1. Sets up a repository with a remote
+// 2. Runs tag prerelease --channel beta --push and verifies 0.1.0-beta.1 exists on the remote
+func TestTagPrereleasePush(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "tag", "prerelease", "-c", "beta", "--push"); err != nil {
+		t.Fatalf("Failed to tag prerelease: %v\nOutput: %s", err, output)
+	}
+	if remote, _ := testutil.RunGit(t, dir, "ls-remote", "--tags", "origin", "0.1.0-beta.1"); !strings.Contains(remote, "refs/tags/0.1.0-beta.1") {
+		t.Errorf("Expected 0.1.0-beta.1 on the remote, got %q", remote)
+	}
+}
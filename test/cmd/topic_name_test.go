@@ -0,0 +1,75 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestTopicCommandsAcceptFullName tests that topic branch commands accept the full branch name like the short one.
+// Steps:
+// 1. Sets up a test repository with a remote
+// 2. Starts 'feature/login' by its full name and verifies no prefix is doubled
+// 3. Publishes and finishes it by its full name
+// 4. Verifies the branch was pushed, merged into develop and deleted
+func TestTopicCommandsAcceptFullName(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "feature/login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/login") || testutil.BranchExists(t, dir, "feature/feature/login") {
+		t.Fatal("Expected feature/login to be created without doubling the prefix")
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "feature/login"); err != nil {
+		t.Fatalf("Failed to publish by the full name: %v\nOutput: %s", err, output)
+	}
+	if output, _ := testutil.RunGit(t, dir, "ls-remote", "--heads", "origin", "feature/login"); !strings.Contains(output, "refs/heads/feature/login") {
+		t.Errorf("Expected feature/login to be pushed, got: %s", output)
+	}
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "feature/login"); err != nil {
+		t.Fatalf("Failed to finish by the full name: %v\nOutput: %s", err, output)
+	}
+	if testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to be deleted after finishing")
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "origin/feature/login", "develop"); err != nil {
+		t.Error("Expected feature/login to be merged into develop")
+	}
+}
+
+// TestTopicCommandsRejectNameOfOtherType tests that finish, delete and publish reject a name with another type's prefix.
+// Steps:
+// 1. Sets up a test repository with git-flow initialized and a bugfix branch
+// 2. Runs finish, delete and publish of 'bugfix/crash' as a feature
+// 3. Verifies each fails with exit code 2, names the bugfix type and suggests its command
+// 4. Verifies the bugfix branch is left alone
+func TestTopicCommandsRejectNameOfOtherType(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "bugfix", "start", "crash"); err != nil {
+		t.Fatalf("Failed to start bugfix: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "bugfix/crash", "crash.txt", "fix")
+
+	for _, command := range []string{"finish", "delete", "publish"} {
+		output, err := testutil.RunGitFlow(t, dir, "feature", command, "bugfix/crash")
+		assertExitCode(t, err, errors.ExitCodeInvalidInput, output)
+		if !strings.Contains(output, "'bugfix/crash' is a bugfix branch, not a feature branch") || !strings.Contains(output, "use 'git flow bugfix' with the name 'crash'") {
+			t.Errorf("Expected feature %s to point to the bugfix command, got: %s", command, output)
+		}
+	}
+	if !testutil.BranchExists(t, dir, "bugfix/crash") {
+		t.Error("Expected bugfix/crash to be left alone")
+	}
+}
@@ -464,3 +464,111 @@ func TestTrackHotfixBranch(t *testing.T) {
 		t.Errorf("Expected to be on 'hotfix/1.0.1', got '%s'", currentBranch)
 	}
 }
+
+// TestTrackSuggestsSimilarBranches tests that tracking a missing branch lists similar remote branches.
+// Steps:
+// 1. Sets up a test repository with a remote
+// 2. Publishes a feature branch and deletes it locally
+// 3. Tracks a misspelled name of the branch
+// 4. Verifies the error lists the published branch as a close match
+func TestTrackSuggestsSimilarBranches(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login-form"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "publish", "login-form"); err != nil {
+		t.Fatalf("Failed to publish feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/login-form")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "track", "lgoin-form")
+	if err == nil {
+		t.Fatal("Expected tracking a misspelled branch to fail")
+	}
+	if !strings.Contains(output, "Similar branches on the remote") || !strings.Contains(output, "feature/login-form") {
+		t.Errorf("Expected error to suggest 'feature/login-form', got: %s", output)
+	}
+}
+
+// TestTrackBranchWithPrefixAlias tests tracking a remote branch named with a prefix alias.
+// Steps:
+// 1. Sets up a test repository with a remote
+// 2. Configures bug/ as a prefix alias of the bugfix type
+// 3. Creates bug/crash on the remote
+// 4. Runs 'git flow bugfix track crash'
+// 5. Verifies the local bugfix/crash branch tracks origin/bug/crash
+func TestTrackBranchWithPrefixAlias(t *testing.T) {
+	// Setup test repository with remote
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.branch.bugfix.prefixaliases", "bug/, fix/")
+	if output, err := testutil.RunGit(t, dir, "push", "origin", "develop:refs/heads/bug/crash"); err != nil {
+		t.Fatalf("Failed to create remote branch: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "bugfix", "track", "crash")
+	if err != nil {
+		t.Fatalf("Failed to track bugfix branch: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Successfully created tracking branch 'bugfix/crash' from 'origin/bug/crash'") {
+		t.Errorf("Expected success message naming the alias branch, got: %s", output)
+	}
+
+	trackingInfo, err := testutil.RunGit(t, dir, "rev-parse", "--abbrev-ref", "bugfix/crash@{upstream}")
+	if err != nil {
+		t.Fatalf("Failed to get tracking info: %v", err)
+	}
+	if strings.TrimSpace(trackingInfo) != "origin/bug/crash" {
+		t.Errorf("Expected bugfix/crash to track 'origin/bug/crash', got '%s'", strings.TrimSpace(trackingInfo))
+	}
+}
+
+// TestTrackUpstreamConfig tests that gitflow.trackUpstream=false creates the local branch without tracking.
+// Steps:
+// 1. Sets up a test repository with a remote and pushes a feature branch, deleting it locally
+// 2. Sets gitflow.trackUpstream to false and runs 'git flow feature track'
+// 3. Verifies the local branch exists at the remote commit without an upstream
+// 4. Tracks it again with --track-upstream and verifies branch.<name>.remote/merge are set
+func TestTrackUpstreamConfig(t *testing.T) {
+	dir, remoteDir := testutil.SetupTestRepoWithRemote(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "shared"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	testutil.RunGit(t, dir, "push", "origin", "feature/shared")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/shared")
+	testutil.RunGit(t, dir, "config", "gitflow.trackUpstream", "false")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "track", "shared")
+	if err != nil {
+		t.Fatalf("Failed to track feature: %v\nOutput: %s", err, output)
+	}
+	local, _ := testutil.RunGit(t, dir, "rev-parse", "feature/shared")
+	remote, _ := testutil.RunGit(t, dir, "rev-parse", "origin/feature/shared")
+	if strings.TrimSpace(local) != strings.TrimSpace(remote) {
+		t.Errorf("Expected feature/shared at %s, got %s", strings.TrimSpace(remote), strings.TrimSpace(local))
+	}
+	if upstream, err := testutil.RunGit(t, dir, "config", "--get", "branch.feature/shared.remote"); err == nil {
+		t.Errorf("Expected no upstream with gitflow.trackUpstream=false, got remote %q", upstream)
+	}
+
+	testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.RunGit(t, dir, "branch", "-D", "feature/shared")
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "track", "shared", "--track-upstream"); err != nil {
+		t.Fatalf("Failed to track feature: %v\nOutput: %s", err, output)
+	}
+	remoteName, _ := testutil.RunGit(t, dir, "config", "--get", "branch.feature/shared.remote")
+	merge, _ := testutil.RunGit(t, dir, "config", "--get", "branch.feature/shared.merge")
+	if strings.TrimSpace(remoteName) != "origin" || strings.TrimSpace(merge) != "refs/heads/feature/shared" {
+		t.Errorf("Expected tracking of origin refs/heads/feature/shared, got %q %q", remoteName, merge)
+	}
+}
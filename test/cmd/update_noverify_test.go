@@ -0,0 +1,136 @@
+package cmd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupDivergedFeature creates feature/<name> with a commit and adds a commit
+// to develop, so updating the feature branch creates a merge commit
+func setupDivergedFeature(t *testing.T, dir, name string) {
+	t.Helper()
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.branch.feature.downstreamStrategy", "merge"); err != nil {
+		t.Fatalf("Failed to set downstream strategy: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+		t.Fatalf("Failed to start feature: %v", err)
+	}
+	testutil.WriteFile(t, dir, "feature.txt", "feature content")
+	_, _ = testutil.RunGit(t, dir, "add", "feature.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add feature file")
+
+	_, _ = testutil.RunGit(t, dir, "checkout", "develop")
+	testutil.WriteFile(t, dir, "develop.txt", "develop content")
+	_, _ = testutil.RunGit(t, dir, "add", "develop.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add develop file")
+	_, _ = testutil.RunGit(t, dir, "checkout", "feature/"+name)
+}
+
+// TestUpdateFeatureWithNoVerify tests that --no-verify bypasses the hooks on update.
+// Steps:
+// 1. Sets up a feature branch and a diverging commit on develop
+// 2. Installs pre-merge-commit and pre-commit hooks that reject
+// 3. Runs 'git flow feature update' and verifies it fails
+// 4. Runs 'git flow feature update --no-verify' and verifies it succeeds
+// 5. Verifies develop is merged into the feature branch
+func TestUpdateFeatureWithNoVerify(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupDivergedFeature(t, dir, "hooks")
+	createRejectingHooks(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "update")
+	if err == nil {
+		t.Fatalf("Expected update to be rejected by the hook, got: %s", output)
+	}
+	_, _ = testutil.RunGit(t, dir, "merge", "--abort")
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "update", "--no-verify")
+	if err != nil {
+		t.Fatalf("Expected update with --no-verify to succeed: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "feature/hooks"); err != nil {
+		t.Error("Expected develop to be merged into feature/hooks")
+	}
+}
+
+// TestUpdateNoVerifyFromConfig tests that gitflow.<type>.update.noVerify bypasses the hooks.
+// Steps:
+// 1. Sets up a feature branch and a diverging commit on develop
+// 2. Sets gitflow.feature.update.noVerify to true
+// 3. Installs hooks that reject
+// 4. Runs the shorthand 'git flow update' and verifies it succeeds
+func TestUpdateNoVerifyFromConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	setupDivergedFeature(t, dir, "config")
+	if _, err := testutil.RunGit(t, dir, "config", "gitflow.feature.update.noVerify", "true"); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+	createRejectingHooks(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "update")
+	if err != nil {
+		t.Fatalf("Expected update to skip the hooks per config: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "develop", "feature/config"); err != nil {
+		t.Error("Expected develop to be merged into feature/config")
+	}
+}
+
+// TestFinishReleaseWithNoVerifyChildren tests that --no-verify-children bypasses
+// the hooks for the back-merge of main into develop.
+// Steps:
+// 1. Sets up a release branch with a commit and a diverging commit on main
+// 2. Installs hooks that reject
+// 3. Finishes with --no-verify only and verifies the child update of develop fails
+// 4. Continues the finish with --no-verify-children
+// 5. Verifies the finish succeeds, develop contains main and the release branch is deleted
+func TestFinishReleaseWithNoVerifyChildren(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if _, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v", err)
+	}
+	if _, err := testutil.RunGitFlow(t, dir, "release", "start", "1.0.0"); err != nil {
+		t.Fatalf("Failed to start release: %v", err)
+	}
+	testutil.WriteFile(t, dir, "release.txt", "release content")
+	_, _ = testutil.RunGit(t, dir, "add", "release.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add release file")
+
+	_, _ = testutil.RunGit(t, dir, "checkout", "main")
+	testutil.WriteFile(t, dir, "main.txt", "main content")
+	_, _ = testutil.RunGit(t, dir, "add", "main.txt")
+	_, _ = testutil.RunGit(t, dir, "commit", "-m", "Add main file")
+	_, _ = testutil.RunGit(t, dir, "checkout", "release/1.0.0")
+
+	createRejectingHooks(t, dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "release", "finish", "--no-fetch", "--no-verify", "--notag", "1.0.0")
+	if err == nil {
+		t.Fatalf("Expected the update of develop to be rejected by the hook, got: %s", output)
+	}
+	if !strings.Contains(output, "develop") {
+		t.Errorf("Expected the failure to mention develop, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "release", "finish", "--continue", "--no-verify-children")
+	if err != nil {
+		t.Fatalf("Expected finish --continue with --no-verify-children to succeed: %v\nOutput: %s", err, output)
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "main", "develop"); err != nil {
+		t.Error("Expected main to be merged into develop")
+	}
+	if testutil.BranchExists(t, dir, "release/1.0.0") {
+		t.Error("Expected release/1.0.0 to be deleted")
+	}
+}
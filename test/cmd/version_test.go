@@ -0,0 +1,136 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestVersionReportInInitializedRepo tests the environment report of the version command.
+// Steps:
+// 1. Sets up a test repository and initializes git-flow with defaults
+// 2. Runs the version command
+// 3. Verifies the Git version, feature and repository sections are reported
+func TestVersionReportInInitializedRepo(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	output, err := testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{
+		"git-flow-next version",
+		"Git version:",
+		"merge-tree",
+		"worktrees",
+		"Config schema:  1.0 (local config)",
+		"AVH config:     not detected",
+		"Working tree:   main working tree",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+}
+
+// TestVersionReportsAVHConfig tests that git-flow-avh keys are listed in an
+// uninitialized repository.
+// Steps:
+// 1. Sets up a test repository with git-flow-avh configuration only
+// 2. Runs the version command
+// 3. Verifies the schema is reported as not initialized and the AVH keys are listed
+func TestVersionReportsAVHConfig(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.branch.master", "main")
+	testutil.RunGit(t, dir, "config", "gitflow.branch.develop", "develop")
+	testutil.RunGit(t, dir, "config", "gitflow.prefix.feature", "feature/")
+
+	output, err := testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(output, "Config schema:  not initialized") {
+		t.Errorf("Expected schema to be reported as not initialized, got: %s", output)
+	}
+	if !strings.Contains(output, "AVH config:     detected (gitflow.branch.develop, gitflow.branch.master, gitflow.prefix.feature)") {
+		t.Errorf("Expected AVH keys to be listed, got: %s", output)
+	}
+}
+
+// TestVersionOutsideRepository tests that the version command works outside a repository.
+// Steps:
+// 1. Creates an empty directory
+// 2. Runs the version command with and without --short
+// 3. Verifies the repository section notes the missing repository and --short prints only the version
+func TestVersionOutsideRepository(t *testing.T) {
+	dir, err := os.MkdirTemp("", "git-flow-version-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	output, err := testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Not in a Git repository") {
+		t.Errorf("Expected missing repository to be reported, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "version", "--short")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version --short: %v\nOutput: %s", err, output)
+	}
+	if strings.TrimSpace(output) != strings.SplitN(output, "\n", 2)[0] || !strings.HasPrefix(output, "git-flow-next version") {
+		t.Errorf("Expected only the version with --short, got: %s", output)
+	}
+}
+
+// TestVersionFeatures tests that version --features reports the experimental features of the repository.
+// Steps:
+// 1. Sets up a test repository and enables in-memory-merge and a feature this version does not know
+// 2. Runs 'git flow version --features' and verifies in-memory-merge is enabled and the unknown key is reported
+// 3. Runs 'git flow version' and verifies the enabled feature is part of the repository report
+func TestVersionFeatures(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.experimental.in-memory-merge", "true")
+	testutil.RunGit(t, dir, "config", "gitflow.experimental.time-travel", "true")
+
+	output, err := testutil.RunGitFlow(t, dir, "version", "--features")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version --features: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{
+		"merge-tree",
+		"in-memory-merge  enabled",
+		"gitflow.experimental.time-travel names no experimental feature",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "Config schema:") {
+		t.Errorf("Expected only the features with --features, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "version")
+	if err != nil {
+		t.Fatalf("Failed to run git-flow version: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "Experimental:   in-memory-merge") {
+		t.Errorf("Expected the enabled feature to be reported, got: %s", output)
+	}
+}
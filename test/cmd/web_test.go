@@ -0,0 +1,153 @@
+package cmd_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/dashboard"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// startWeb starts 'git flow web' in dir and returns the URL it serves the
+// dashboard at. The server is stopped when the test ends.
+func startWeb(t *testing.T, dir string) string {
+	t.Helper()
+	gitFlowPath, err := filepath.Abs(filepath.Join("..", "..", "git-flow"))
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	cmd := exec.Command(gitFlowPath, "web")
+	cmd.Dir = dir
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to capture output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start git flow web: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Signal(os.Interrupt)
+		cmd.Wait()
+	})
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read the dashboard URL: %v", err)
+	}
+	url, ok := strings.CutPrefix(line, "Serving the dashboard at ")
+	if !ok {
+		t.Fatalf("Expected the dashboard URL, got: %s", line)
+	}
+	return strings.Fields(url)[0]
+}
+
+// fetch returns the body of a GET request to url
+func fetch(t *testing.T, url string) string {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to get %s: %v", url, err)
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200 for %s, got %d: %s", url, response.StatusCode, body)
+	}
+	return string(body)
+}
+
+// TestWebDashboard tests that the dashboard shows the hierarchy, topic branches and pending operations.
+// Steps:
+// 1. Sets up a test repository with git-flow initialized and a feature two commits ahead of develop
+// 2. Commits on develop so the feature is one commit behind it
+// 3. Starts 'git flow web' and fetches /api/snapshot
+// 4. Verifies the base branches are listed parents first and the feature is +2 -1 against develop
+// 5. Verifies the page lists the feature under develop
+// 6. Starts a finish that conflicts and verifies the snapshot reports it
+func TestWebDashboard(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+	commitOn(t, dir, "feature/login", "shared.txt", "feature")
+	commitOn(t, dir, "develop", "shared.txt", "develop")
+
+	url := startWeb(t, dir)
+
+	var snapshot dashboard.Snapshot
+	if err := json.Unmarshal([]byte(fetch(t, url+"api/snapshot")), &snapshot); err != nil {
+		t.Fatalf("Failed to decode the snapshot: %v", err)
+	}
+	if len(snapshot.Bases) != 2 || snapshot.Bases[0].Name != "main" || snapshot.Bases[1].Name != "develop" || snapshot.Bases[1].Depth != 1 {
+		t.Errorf("Expected main with develop below it, got: %+v", snapshot.Bases)
+	}
+	if len(snapshot.Topics) != 1 {
+		t.Fatalf("Expected one topic branch, got: %+v", snapshot.Topics)
+	}
+	topic := snapshot.Topics[0]
+	if topic.Name != "feature/login" || topic.Type != "feature" || topic.Base != "develop" || topic.Ahead != 2 || topic.Behind != 1 {
+		t.Errorf("Expected feature/login 2 ahead of and 1 behind develop, got: %+v", topic)
+	}
+	if snapshot.Operation != nil {
+		t.Errorf("Expected no operation in progress, got: %+v", snapshot.Operation)
+	}
+
+	html := fetch(t, url)
+	if !strings.Contains(html, "feature/login</span> <span class=\"ahead\">+2</span> <span class=\"behind\">-1</span>") {
+		t.Errorf("Expected the page to show the feature under develop, got: %s", html)
+	}
+
+	if _, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login"); err == nil {
+		t.Fatal("Expected the finish to stop on a conflict")
+	}
+	if err := json.Unmarshal([]byte(fetch(t, url+"api/snapshot")), &snapshot); err != nil {
+		t.Fatalf("Failed to decode the snapshot: %v", err)
+	}
+	if snapshot.Operation == nil || snapshot.Operation.Action != "finish" || snapshot.Operation.Branch != "feature/login" || snapshot.Operation.Target != "develop" {
+		t.Errorf("Expected the conflicted finish to be reported, got: %+v", snapshot.Operation)
+	}
+	if !strings.Contains(fetch(t, url), "finish in progress:") {
+		t.Error("Expected the page to show the finish in progress")
+	}
+}
+
+// TestWebDashboardShowsBranchesStartedWhileServing tests that each page view reads the repository anew.
+// Steps:
+// 1. Sets up a test repository with git-flow initialized
+// 2. Starts 'git flow web'
+// 3. Starts a feature from main while the server runs
+// 4. Verifies the snapshot lists the feature with main, the base stored for it, as its base
+func TestWebDashboardShowsBranchesStartedWhileServing(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+
+	url := startWeb(t, dir)
+	fetch(t, url+"api/snapshot")
+
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "late", "main"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+
+	var snapshot dashboard.Snapshot
+	if err := json.Unmarshal([]byte(fetch(t, url+"api/snapshot")), &snapshot); err != nil {
+		t.Fatalf("Failed to decode the snapshot: %v", err)
+	}
+	if len(snapshot.Topics) != 1 || snapshot.Topics[0].Name != "feature/late" || snapshot.Topics[0].Base != "main" {
+		t.Errorf("Expected feature/late based on main, got: %+v", snapshot.Topics)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// addWorktree checks out branch in a new linked worktree of the repository in dir
+func addWorktree(t *testing.T, dir string, branch string) string {
+	t.Helper()
+	parent, err := os.MkdirTemp("", "git-flow-worktree-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory for worktree: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(parent) })
+	path := filepath.Join(parent, "worktree")
+	if output, err := testutil.RunGit(t, dir, "worktree", "add", path, branch); err != nil {
+		t.Fatalf("Failed to add worktree: %v\nOutput: %s", err, output)
+	}
+	return path
+}
+
+// TestFinishBranchCheckedOutInOtherWorktree tests that finish refuses to delete a branch another worktree has checked out.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and starts a feature
+// 2. Checks out the feature in a linked worktree
+// 3. Runs 'git flow feature finish' in the main working tree and verifies it fails with exit code 6
+// 4. Verifies develop is unchanged and the worktree is named
+// 5. Finishes with --keep and verifies the feature is merged and kept
+func TestFinishBranchCheckedOutInOtherWorktree(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+	testutil.RunGit(t, dir, "checkout", "develop")
+	worktree := addWorktree(t, dir, "feature/login")
+
+	before, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "branch 'feature/login' is checked out in worktree") || !strings.Contains(output, filepath.Base(worktree)) {
+		t.Errorf("Expected the worktree to be named, got: %s", output)
+	}
+	if after, _ := testutil.RunGit(t, dir, "rev-parse", "develop"); after != before {
+		t.Error("Expected develop to be unchanged")
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "feature", "finish", "login", "--keep")
+	if err != nil {
+		t.Fatalf("Expected finish with --keep to succeed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "is not checked out in another worktree (warning: it is checked out in") {
+		t.Errorf("Expected a warning about the worktree, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to be kept")
+	}
+	if _, err := testutil.RunGit(t, dir, "merge-base", "--is-ancestor", "feature/login", "develop"); err != nil {
+		t.Error("Expected feature/login to be merged into develop")
+	}
+}
+
+// TestFinishTargetCheckedOutInOtherWorktree tests that finish refuses to merge into a branch another worktree has checked out.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and starts a feature
+// 2. Checks out develop in a linked worktree
+// 3. Runs 'git flow feature finish' in the main working tree and verifies it fails with exit code 6
+// 4. Verifies the feature branch still exists
+func TestFinishTargetCheckedOutInOtherWorktree(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	if output, err := testutil.RunGitFlow(t, dir, "feature", "start", "login"); err != nil {
+		t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+	}
+	commitOn(t, dir, "feature/login", "login.txt", "login")
+	addWorktree(t, dir, "develop")
+
+	output, err := testutil.RunGitFlow(t, dir, "feature", "finish", "login")
+	assertExitCode(t, err, errors.ExitCodeValidationError, output)
+	if !strings.Contains(output, "branch 'develop' is checked out in worktree") {
+		t.Errorf("Expected develop to be reported, got: %s", output)
+	}
+	if !testutil.BranchExists(t, dir, "feature/login") {
+		t.Error("Expected feature/login to still exist")
+	}
+}
+
+// TestStatusShowsOtherWorktrees tests that state show and overview report the other worktrees.
+// Steps:
+// 1. Sets up a test repository, initializes git-flow and starts two features
+// 2. Checks out feature/login in a linked worktree
+// 3. Runs 'git flow overview' in the main working tree and verifies the worktree's branch is marked
+// 4. Finishes feature/login in the linked worktree with a conflict on develop
+// 5. Runs 'git flow state show' in the main working tree and verifies the operation is listed
+func TestStatusShowsOtherWorktrees(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if output, err := testutil.RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+	for _, name := range []string{"login", "search"} {
+		if output, err := testutil.RunGitFlow(t, dir, "feature", "start", name); err != nil {
+			t.Fatalf("Failed to start feature: %v\nOutput: %s", err, output)
+		}
+	}
+	commitOn(t, dir, "feature/login", "shared.txt", "login")
+	commitOn(t, dir, "develop", "shared.txt", "develop")
+	testutil.RunGit(t, dir, "checkout", "main")
+	worktree := addWorktree(t, dir, "feature/login")
+
+	output, err := testutil.RunGitFlow(t, dir, "overview")
+	if err != nil {
+		t.Fatalf("Failed to show overview: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "+ feature/login (feature) [worktree:") {
+		t.Errorf("Expected feature/login to be marked as checked out in another worktree, got: %s", output)
+	}
+	if !strings.Contains(output, "  feature/search (feature)\n") {
+		t.Errorf("Expected feature/search not to be marked, got: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, worktree, "feature", "finish", "login")
+	if err == nil {
+		t.Fatalf("Expected the finish to stop on a conflict\nOutput: %s", output)
+	}
+
+	output, err = testutil.RunGitFlow(t, dir, "state", "show")
+	if err != nil {
+		t.Fatalf("Failed to show state: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(output, "No git-flow operation in progress") || !strings.Contains(output, "finish of 'feature/login' into 'develop' at step 'merge'") {
+		t.Errorf("Expected the other worktree's operation to be listed, got: %s", output)
+	}
+}
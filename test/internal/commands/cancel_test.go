@@ -0,0 +1,199 @@
+package commands_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// withRepo creates a repository with the default git-flow configuration and
+// an origin remote, changes to it and runs testFunc with the dependencies of
+// the command line. Cancelling stops git processes, so these tests need a real
+// repository rather than a fake.
+func withRepo(t *testing.T, testFunc func(dir string, cfgCtx *config.Context, deps *commands.Deps)) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(oldDir); err != nil {
+			t.Fatalf("Failed to change back to original directory: %v", err)
+		}
+	}()
+
+	if err := config.SaveConfig(config.DefaultConfig()); err != nil {
+		t.Fatalf("Failed to save configuration: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "branch", "develop"); err != nil {
+		t.Fatalf("Failed to create develop: %v", err)
+	}
+	remoteDir, err := testutil.AddRemote(t, dir, "origin", true)
+	if err != nil {
+		t.Fatalf("Failed to add remote: %v", err)
+	}
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	cfgCtx, err := config.LoadContext()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	testFunc(dir, cfgCtx, commands.NewDeps(nil))
+}
+
+// startFeature creates feature/name with a commit through git-flow's own start
+func startFeatureInRepo(t *testing.T, dir string, cfgCtx *config.Context, deps *commands.Deps, name string) {
+	t.Helper()
+	noFetch := false
+	if err := commands.Start(context.Background(), deps, cfgCtx, "feature", name, commands.StartOptions{Fetch: &noFetch}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := testutil.WriteFile(t, dir, name+".txt", name); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "add", "."); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if _, err := testutil.RunGit(t, dir, "commit", "-m", "Add "+name); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+}
+
+// TestFinishCancelledDuringMerge tests cancelling the context while the merge of a finish runs.
+// Steps:
+// 1. Starts feature/cancel and adds a commit to develop, so the finish creates a merge commit
+// 2. Installs a pre-merge-commit hook that signals it started and then blocks
+// 3. Finishes feature/cancel and cancels the context once the hook runs
+// 4. Verifies the finish stops with an InterruptedError, develop is unchanged and no merge is left in progress
+// 5. Verifies the state is saved as interrupted at the merge step
+// 6. Removes the hook and continues the finish
+// 7. Verifies feature/cancel is merged into develop and no state is left
+func TestFinishCancelledDuringMerge(t *testing.T) {
+	withRepo(t, func(dir string, cfgCtx *config.Context, deps *commands.Deps) {
+		startFeatureInRepo(t, dir, cfgCtx, deps, "cancel")
+		// A change on develop makes the finish create a merge commit
+		if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+			t.Fatalf("Failed to checkout develop: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Develop change"); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		developBefore, err := testutil.RunGit(t, dir, "rev-parse", "develop")
+		if err != nil {
+			t.Fatalf("Failed to resolve develop: %v", err)
+		}
+
+		started := filepath.Join(dir, ".git", "hook-started")
+		hook := filepath.Join(dir, ".git", "hooks", "pre-merge-commit")
+		script := "#!/bin/sh\nexec >/dev/null 2>&1\ntouch '" + started + "'\nexec sleep 10\n"
+		if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write hook: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			for {
+				if _, err := os.Stat(started); err == nil {
+					cancel()
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(20 * time.Millisecond):
+				}
+			}
+		}()
+
+		noFetch := false
+		err = commands.Finish(ctx, deps, cfgCtx, "feature", "cancel", commands.FinishOptions{Fetch: &noFetch})
+		interrupted, ok := err.(*errors.InterruptedError)
+		if !ok {
+			t.Fatalf("Expected an InterruptedError, got %v", err)
+		}
+		if interrupted.Step != mergestate.StepMerge {
+			t.Errorf("Expected the finish to resume at step %s, got %s", mergestate.StepMerge, interrupted.Step)
+		}
+
+		developAfter, _ := testutil.RunGit(t, dir, "rev-parse", "develop")
+		if developAfter != developBefore {
+			t.Error("Expected develop to be unchanged")
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git", "MERGE_HEAD")); err == nil {
+			t.Error("Expected no merge to be left in progress")
+		}
+		status, _ := testutil.RunGit(t, dir, "status", "--porcelain", "--untracked-files=no")
+		if strings.TrimSpace(status) != "" {
+			t.Errorf("Expected a clean working tree, got %q", status)
+		}
+
+		state, err := mergestate.LoadMergeState()
+		if err != nil || state == nil {
+			t.Fatalf("Expected the merge state to be kept, got %v", err)
+		}
+		if !state.Interrupted || state.CurrentStep != mergestate.StepMerge {
+			t.Errorf("Expected an interrupted state at step %s, got step %s (interrupted %v)", mergestate.StepMerge, state.CurrentStep, state.Interrupted)
+		}
+
+		if err := os.Remove(hook); err != nil {
+			t.Fatalf("Failed to remove hook: %v", err)
+		}
+		if err := commands.Finish(context.Background(), deps, cfgCtx, "feature", "cancel", commands.FinishOptions{Continue: true}); err != nil {
+			t.Fatalf("Continue failed: %v", err)
+		}
+		log, _ := testutil.RunGit(t, dir, "log", "--format=%s", "develop")
+		if !strings.Contains(log, "Add cancel") {
+			t.Errorf("Expected the commit of feature/cancel on develop, got %q", log)
+		}
+		if mergestate.IsMergeInProgress() {
+			t.Error("Expected no merge state after the finish")
+		}
+	})
+}
+
+// TestUpdateCancelledBeforeStart tests an update whose context is cancelled before it runs.
+// Steps:
+// 1. Starts feature/idle and adds a commit to develop
+// 2. Updates feature/idle with a cancelled context
+// 3. Verifies the update stops with an InterruptedError and feature/idle is unchanged
+func TestUpdateCancelledBeforeStart(t *testing.T) {
+	withRepo(t, func(dir string, cfgCtx *config.Context, deps *commands.Deps) {
+		startFeatureInRepo(t, dir, cfgCtx, deps, "idle")
+		if _, err := testutil.RunGit(t, dir, "checkout", "develop"); err != nil {
+			t.Fatalf("Failed to checkout develop: %v", err)
+		}
+		if _, err := testutil.RunGit(t, dir, "commit", "--allow-empty", "-m", "Develop change"); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+		before, err := testutil.RunGit(t, dir, "rev-parse", "feature/idle")
+		if err != nil {
+			t.Fatalf("Failed to resolve feature/idle: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = commands.Update(ctx, deps, cfgCtx, "feature", "idle", commands.UpdateOptions{})
+		if _, ok := err.(*errors.InterruptedError); !ok {
+			t.Fatalf("Expected an InterruptedError, got %v", err)
+		}
+		after, _ := testutil.RunGit(t, dir, "rev-parse", "feature/idle")
+		if after != before {
+			t.Error("Expected feature/idle to be unchanged")
+		}
+	})
+}
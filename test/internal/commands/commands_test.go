@@ -0,0 +1,370 @@
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestRename tests renaming the current topic branch against a fake repository.
+// Steps:
+// 1. Renames feature/old, the current branch, to feature/new
+// 2. Verifies the branch is renamed, still current and the rename is reported
+func TestRename(t *testing.T) {
+	fake := testutil.NewFakeGit("feature/old", "main", "develop")
+	deps, out := testutil.NewFakeDeps(t, fake, nil)
+
+	if err := commands.Rename(deps, config.DefaultConfig(), "feature", "old", "new"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if fake.Current != "feature/new" || !fake.HasBranch("feature/new") || fake.HasBranch("feature/old") {
+		t.Errorf("Expected feature/old to be renamed to feature/new, got branches %v on %s", fake.Branches, fake.Current)
+	}
+	if !strings.Contains(out.String(), "Renamed branch 'feature/old' to 'feature/new'") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+}
+
+// TestRenameToExistingBranch tests that rename refuses to overwrite a branch.
+// Steps:
+// 1. Renames feature/old to feature/taken, which exists
+// 2. Verifies a GitError is returned and no branch is renamed
+func TestRenameToExistingBranch(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/old", "feature/taken")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	err := commands.Rename(deps, config.DefaultConfig(), "feature", "old", "taken")
+	if _, ok := err.(*errors.GitError); !ok {
+		t.Errorf("Expected a GitError for an existing target, got %v", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("Expected no changes, got %v", fake.Calls)
+	}
+}
+
+// TestRenameFailure tests that a failing git rename is reported as a Git error.
+// Steps:
+// 1. Makes RenameBranch fail with a permission error
+// 2. Renames feature/old to feature/new
+// 3. Verifies a GitError carrying the cause is returned and feature/old is kept
+func TestRenameFailure(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/old")
+	fake.Fail("RenameBranch", fmt.Errorf("unable to create '.git/refs/heads/feature/new.lock': Permission denied"))
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	err := commands.Rename(deps, config.DefaultConfig(), "feature", "old", "new")
+	gitErr, ok := err.(*errors.GitError)
+	if !ok {
+		t.Fatalf("Expected a GitError, got %v", err)
+	}
+	if gitErr.ExitCode() != errors.ExitCodeGitError || !strings.Contains(err.Error(), "Permission denied") {
+		t.Errorf("Expected the permission error with exit code %d, got %v", errors.ExitCodeGitError, err)
+	}
+	if !fake.HasBranch("feature/old") {
+		t.Error("Expected feature/old to be kept")
+	}
+}
+
+// TestCheckoutPrefixMatch tests resolving the checkout argument to a branch.
+// Steps:
+// 1. Checks out by exact name, unique prefix, ambiguous prefix and unknown name
+// 2. Verifies the matching branch is checked out or an error is returned
+func TestCheckoutPrefixMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      string
+		expected string
+		wantErr  bool
+	}{
+		{"exact", "login", "feature/login", false},
+		{"unique prefix", "log-", "feature/log-viewer", false},
+		{"ambiguous prefix", "lo", "", true},
+		{"no match", "x", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testutil.NewFakeGit("develop", "feature/login", "feature/log-viewer")
+			deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+			err := commands.Checkout(deps, config.DefaultConfig(), "feature", tt.arg, false)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, checked out %s", fake.Current)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Checkout failed: %v", err)
+			}
+			if fake.Current != tt.expected {
+				t.Errorf("Expected %s to be checked out, got %s", tt.expected, fake.Current)
+			}
+		})
+	}
+}
+
+// TestCheckoutListFailure tests that listing branches reports a failing git.
+// Steps:
+// 1. Makes ListBranches fail
+// 2. Runs checkout without a name
+// 3. Verifies a GitError is returned
+func TestCheckoutListFailure(t *testing.T) {
+	fake := testutil.NewFakeGit("develop")
+	fake.Fail("ListBranches", fmt.Errorf("fatal: not a git repository"))
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	err := commands.Checkout(deps, config.DefaultConfig(), "feature", "", false)
+	if _, ok := err.(*errors.GitError); !ok {
+		t.Errorf("Expected a GitError, got %v", err)
+	}
+}
+
+// TestDeleteCurrentBranch tests deleting the checked out topic branch.
+// Steps:
+// 1. Configures force deletion and remote deletion, and a stored base for feature/done
+// 2. Deletes feature/done, the current branch, with the remote flag set to false
+// 3. Verifies develop is checked out, the branch is force deleted, the remote branch is kept and the stored base is removed
+func TestDeleteCurrentBranch(t *testing.T) {
+	fake := testutil.NewFakeGit("feature/done", "develop")
+	fake.Remotes["origin"] = []string{"feature/done"}
+	cfg := testutil.FakeConfig{
+		"gitflow.feature.delete.force":        "true",
+		"gitflow.branch.feature/done.base":    "develop",
+		"gitflow.branch.feature.deleteRemote": "true",
+	}
+	deps, _ := testutil.NewFakeDeps(t, fake, cfg)
+
+	noRemote := false
+	if err := commands.Delete(context.Background(), deps, config.DefaultConfig(), "feature", "done", nil, &noRemote); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	expected := []string{"Checkout develop", "DeleteBranch feature/done force"}
+	if strings.Join(fake.Calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected calls %v, got %v", expected, fake.Calls)
+	}
+	if _, ok := cfg["gitflow.branch.feature/done.base"]; ok {
+		t.Error("Expected the base config to be removed")
+	}
+}
+
+// TestDeleteRemoteFailure tests a network failure while deleting the remote branch.
+// Steps:
+//  1. Makes DeleteRemoteBranch fail with a network error
+//  2. Deletes feature/done with the remote flag
+//  3. Verifies the error names the remote deletion, the local branch is gone and
+//     no success message is printed
+func TestDeleteRemoteFailure(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/done")
+	fake.Remotes["origin"] = []string{"feature/done"}
+	fake.Fail("DeleteRemoteBranch", fmt.Errorf("ssh: connect to host example.com port 22: Network is unreachable"))
+	deps, out := testutil.NewFakeDeps(t, fake, nil)
+
+	deleteRemote := true
+	err := commands.Delete(context.Background(), deps, config.DefaultConfig(), "feature", "done", nil, &deleteRemote)
+	if err == nil || !strings.Contains(err.Error(), "delete remote branch 'feature/done'") {
+		t.Fatalf("Expected the remote deletion to fail, got %v", err)
+	}
+	if fake.HasBranch("feature/done") {
+		t.Error("Expected the local branch to be deleted before the remote failed")
+	}
+	if strings.Contains(out.String(), "Deleted branch") {
+		t.Errorf("Expected no success message, got: %s", out.String())
+	}
+}
+
+// TestRenameToDuplicateTopicName tests that rename refuses a short name another topic type uses when gitflow.uniqueTopicNames is set.
+// Steps:
+// 1. Enables gitflow.uniqueTopicNames with bugfix/login existing
+// 2. Renames feature/old to feature/login
+// 3. Verifies a DuplicateTopicNameError naming bugfix/login is returned and no branch is renamed
+func TestRenameToDuplicateTopicName(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/old", "bugfix/login")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.uniquetopicnames"] = "true"
+
+	err := commands.Rename(deps, cfg, "feature", "old", "login")
+	dupErr, ok := err.(*errors.DuplicateTopicNameError)
+	if !ok {
+		t.Fatalf("Expected a DuplicateTopicNameError, got %v", err)
+	}
+	if dupErr.ExistingBranch != "bugfix/login" {
+		t.Errorf("Expected the conflict with bugfix/login, got %s", dupErr.ExistingBranch)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("Expected no changes, got %v", fake.Calls)
+	}
+}
+
+// deletedRecorder records the branch deletions reported on an event bus
+type deletedRecorder struct {
+	events.NopObserver
+	deleted []events.BranchDeleted
+}
+
+func (r *deletedRecorder) OnBranchDeleted(event events.BranchDeleted) {
+	r.deleted = append(r.deleted, event)
+}
+
+// TestDeleteReportsEvents tests that delete reports the deleted branches to the observers of Deps.Events.
+// Steps:
+//  1. Subscribes a recorder to the event bus of the dependencies
+//  2. Deletes feature/done with the remote flag
+//  3. Verifies the local and then the remote deletion were reported
+func TestDeleteReportsEvents(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/done")
+	fake.Remotes["origin"] = []string{"feature/done"}
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+	recorder := &deletedRecorder{}
+	deps.Events.Subscribe(recorder)
+
+	deleteRemote := true
+	if err := commands.Delete(context.Background(), deps, config.DefaultConfig(), "feature", "done", nil, &deleteRemote); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	expected := []events.BranchDeleted{{Branch: "feature/done"}, {Branch: "feature/done", Remote: "origin"}}
+	if fmt.Sprint(recorder.deleted) != fmt.Sprint(expected) {
+		t.Errorf("Expected events %v, got %v", expected, recorder.deleted)
+	}
+}
+
+// TestDeleteWithFullName tests that delete accepts the full branch name like the short one.
+// Steps:
+// 1. Deletes feature/done by its full name
+// 2. Verifies feature/done is deleted, not feature/feature/done looked up
+func TestDeleteWithFullName(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "feature/done")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	noRemote := false
+	if err := commands.Delete(context.Background(), deps, config.DefaultConfig(), "feature", "feature/done", nil, &noRemote); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if fake.HasBranch("feature/done") {
+		t.Errorf("Expected feature/done to be deleted, got branches %v", fake.Branches)
+	}
+}
+
+// TestRenameWithFullNames tests that rename accepts full names for both branches.
+// Steps:
+// 1. Renames feature/old to feature/new, both given with the prefix
+// 2. Verifies the branch is renamed without doubling the prefix
+func TestRenameWithFullNames(t *testing.T) {
+	fake := testutil.NewFakeGit("feature/old", "develop")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	if err := commands.Rename(deps, config.DefaultConfig(), "feature", "feature/old", "feature/new"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if !fake.HasBranch("feature/new") || fake.HasBranch("feature/old") {
+		t.Errorf("Expected feature/old to be renamed to feature/new, got branches %v", fake.Branches)
+	}
+}
+
+// TestCheckoutNameOfOtherType tests that checkout rejects a name with another type's prefix.
+// Steps:
+// 1. Checks out bugfix/crash as a feature
+// 2. Verifies a TopicTypeMismatchError naming the bugfix type is returned and nothing is checked out
+func TestCheckoutNameOfOtherType(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "bugfix/crash")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+
+	err := commands.Checkout(deps, config.DefaultConfig(), "feature", "bugfix/crash", false)
+	mismatch, ok := err.(*errors.TopicTypeMismatchError)
+	if !ok || mismatch.OtherType != "bugfix" {
+		t.Errorf("Expected a TopicTypeMismatchError for bugfix, got %v", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Errorf("Expected no changes, got %v", fake.Calls)
+	}
+}
+
+// TestFinishConflictContinue tests continuing a finish stopped on a merge conflict against a fake repository.
+// Steps:
+// 1. Starts feature/clash, adds commits to it and to develop and makes merging it conflict
+// 2. Finishes feature/clash and verifies it stops with unresolved conflicts and the state saved
+// 3. Resolves the conflict and continues the finish
+// 4. Verifies develop has a merge commit of feature/clash, the branch is deleted and no state is left
+func TestFinishConflictContinue(t *testing.T) {
+	fake := testutil.NewFakeGit("develop", "main")
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+	cfgCtx := &config.Context{Initialized: true, Config: config.DefaultConfig()}
+
+	noFetch := false
+	if err := commands.Start(context.Background(), deps, cfgCtx, "feature", "clash", commands.StartOptions{Fetch: &noFetch}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	fake.AddCommit("feature/clash", "Change the README")
+	fake.AddCommit("develop", "Change the README differently")
+	fake.Conflict("feature/clash", "README.md")
+
+	err := commands.Finish(context.Background(), deps, cfgCtx, "feature", "clash", commands.FinishOptions{Fetch: &noFetch})
+	if _, ok := err.(*errors.UnresolvedConflictsError); !ok {
+		t.Fatalf("Expected unresolved conflicts, got %v", err)
+	}
+	if !deps.State.InProgress() {
+		t.Fatal("Expected the merge state to be saved")
+	}
+
+	fake.Resolve()
+	if err := commands.Finish(context.Background(), deps, cfgCtx, "feature", "clash", commands.FinishOptions{Continue: true}); err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+	subjects := fake.Subjects("develop")
+	if len(subjects) == 0 || subjects[0] != "Merge branch 'feature/clash' into develop" {
+		t.Errorf("Expected a merge commit on develop, got %v", subjects)
+	}
+	if fake.HasBranch("feature/clash") {
+		t.Errorf("Expected feature/clash to be deleted, got branches %v", fake.Branches)
+	}
+	if deps.State.InProgress() {
+		t.Error("Expected no merge state after the finish")
+	}
+}
+
+// TestConcurrentFinishes tests that commands given separate dependencies run concurrently.
+// Steps:
+// 1. Creates four fake repositories, each with its own dependencies
+// 2. Starts, commits to and finishes a feature branch in each of them at the same time
+// 3. Verifies every finish succeeds and merges only its own feature branch
+func TestConcurrentFinishes(t *testing.T) {
+	names := []string{"one", "two", "three", "four"}
+	fakes := make([]*testutil.FakeGit, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		fakes[i] = testutil.NewFakeGit("develop", "main")
+		deps, _ := testutil.NewFakeDeps(t, fakes[i], nil)
+		wg.Add(1)
+		go func(i int, name string, deps *commands.Deps) {
+			defer wg.Done()
+			cfgCtx := &config.Context{Initialized: true, Config: config.DefaultConfig()}
+			noFetch := false
+			if errs[i] = commands.Start(context.Background(), deps, cfgCtx, "feature", name, commands.StartOptions{Fetch: &noFetch}); errs[i] != nil {
+				return
+			}
+			fakes[i].AddCommit("feature/"+name, "Add "+name)
+			errs[i] = commands.Finish(context.Background(), deps, cfgCtx, "feature", name, commands.FinishOptions{Fetch: &noFetch})
+		}(i, name, deps)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Errorf("Finish of feature/%s failed: %v", name, errs[i])
+			continue
+		}
+		subjects := fakes[i].Subjects("develop")
+		if len(subjects) == 0 || subjects[0] != "Add "+name {
+			t.Errorf("Expected the commit of feature/%s on develop, got %v", name, subjects)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package commands_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// newRemoteFake returns a fake repository on develop with main and develop,
+// both also on origin, and the default git-flow configuration
+func newRemoteFake(t *testing.T) (*testutil.FakeGit, *config.Context, *commands.Deps) {
+	t.Helper()
+	fake := testutil.NewFakeGit("develop", "main")
+	for _, branch := range []string{"main", "develop"} {
+		fake.SetRemoteBranch("origin", branch, fake.Heads[branch])
+	}
+	deps, _ := testutil.NewFakeDeps(t, fake, nil)
+	return fake, &config.Context{Initialized: true, Config: config.DefaultConfig()}, deps
+}
+
+// startFeature creates feature/name with a commit through git-flow's own start
+func startFeature(t *testing.T, fake *testutil.FakeGit, cfgCtx *config.Context, deps *commands.Deps, name string) {
+	t.Helper()
+	noFetch := false
+	if err := commands.Start(context.Background(), deps, cfgCtx, "feature", name, commands.StartOptions{Fetch: &noFetch}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	fake.AddCommit("feature/"+name, "Add "+name)
+}
+
+// TestStartFetchUnreachable tests starting a branch while the remote cannot be reached.
+// Steps:
+// 1. Makes Fetch fail as an unreachable remote
+// 2. Starts feature/offline with fetching enabled
+// 3. Verifies start continues without the fetch and creates the branch from the local develop
+func TestStartFetchUnreachable(t *testing.T) {
+	fake, cfgCtx, deps := newRemoteFake(t)
+	fake.Fail("Fetch", &errors.RemoteError{Remote: "origin", Operation: "fetch from", Kind: errors.RemoteUnreachable, Output: "ssh: Could not resolve hostname example.com"})
+	fake.AddCommit("develop", "Local change")
+
+	fetch := true
+	if err := commands.Start(context.Background(), deps, cfgCtx, "feature", "offline", commands.StartOptions{Fetch: &fetch}); err != nil {
+		t.Fatalf("Expected start to continue without the fetch, got %v", err)
+	}
+	if !fake.HasBranch("feature/offline") {
+		t.Fatal("Expected feature/offline to be created")
+	}
+	if fake.Heads["feature/offline"] != fake.Heads["develop"] {
+		t.Error("Expected feature/offline to start from the local develop")
+	}
+}
+
+// TestPublishPermissionDenied tests publishing when the remote refuses the credentials.
+// Steps:
+// 1. Makes PushBranch fail with an authentication error
+// 2. Publishes feature/denied
+// 3. Verifies the error carries the RemoteError with exit code 3 and nothing is on the remote
+func TestPublishPermissionDenied(t *testing.T) {
+	fake, cfgCtx, deps := newRemoteFake(t)
+	fake.Fail("PushBranch", &errors.RemoteError{Remote: "origin", Operation: "push to", Kind: errors.RemoteAuthFailed, Output: "git@example.com: Permission denied (publickey)."})
+	startFeature(t, fake, cfgCtx, deps, "denied")
+
+	err := commands.Publish(context.Background(), deps, cfgCtx, "feature", "denied", commands.PublishOptions{})
+	remoteErr := errors.RemoteFailure(err)
+	if remoteErr == nil || remoteErr.Kind != errors.RemoteAuthFailed {
+		t.Fatalf("Expected an authentication failure, got %v", err)
+	}
+	if flowErr, ok := err.(errors.Error); !ok || flowErr.ExitCode() != errors.ExitCodeGitError {
+		t.Errorf("Expected exit code %d, got %v", errors.ExitCodeGitError, err)
+	}
+	if fake.RemoteBranchExists("origin", "feature/denied") {
+		t.Error("Expected feature/denied not to be on the remote")
+	}
+	if _, err := fake.GetTrackingBranch("feature/denied"); err == nil {
+		t.Error("Expected feature/denied not to track a remote branch")
+	}
+}
+
+// TestFinishPushFailureKeepsState tests a finish whose push fails on the network.
+// Steps:
+// 1. Makes PushRefs fail as an unreachable remote
+// 2. Finishes feature/push with --push
+// 3. Verifies the error names --continue, the merge state stays at the push step and develop is merged locally
+// 4. Lets the push succeed and continues the finish
+// 5. Verifies develop was pushed with the merge, the branch is deleted and no state is left
+func TestFinishPushFailureKeepsState(t *testing.T) {
+	fake, cfgCtx, deps := newRemoteFake(t)
+	fake.Fail("PushRefs", &errors.RemoteError{Remote: "origin", Operation: "push to", Kind: errors.RemoteUnreachable, Output: "fatal: unable to access 'https://example.com/repo.git/': Connection timed out"})
+	startFeature(t, fake, cfgCtx, deps, "push")
+
+	noFetch, push := false, true
+	options := commands.FinishOptions{Fetch: &noFetch, Push: &push}
+	err := commands.Finish(context.Background(), deps, cfgCtx, "feature", "push", options)
+	if errors.RemoteFailure(err) == nil || !strings.Contains(err.Error(), "finish --continue push") {
+		t.Fatalf("Expected the push to fail with a hint to continue, got %v", err)
+	}
+	state, err := deps.State.Load()
+	if err != nil || state == nil {
+		t.Fatalf("Expected the merge state to be kept, got %v", err)
+	}
+	if state.CurrentStep != mergestate.StepPush {
+		t.Errorf("Expected the state to stay at step %s, got %s", mergestate.StepPush, state.CurrentStep)
+	}
+	if !fake.IsAncestor("feature/push", "develop") {
+		t.Error("Expected feature/push to be merged into develop")
+	}
+	if fake.IsAncestor("develop", "origin/develop") && fake.IsAncestor("origin/develop", "develop") {
+		t.Error("Expected origin/develop not to have the merge yet")
+	}
+
+	fake.Fail("PushRefs", nil)
+	if err := commands.Finish(context.Background(), deps, cfgCtx, "feature", "push", commands.FinishOptions{Continue: true}); err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+	if fake.RemoteHeads["origin/develop"] != fake.Heads["develop"] {
+		t.Errorf("Expected develop to be pushed, got calls %v", fake.Calls)
+	}
+	if fake.HasBranch("feature/push") {
+		t.Error("Expected feature/push to be deleted")
+	}
+	if deps.State.InProgress() {
+		t.Error("Expected no merge state after the finish")
+	}
+}
@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmendConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	err := config.AmendConfig(cfg, map[string]string{
+		"gitflow.branch.release.tagPrefix": "v",
+		"gitflow.release.finish.push":      "true",
+		"gitflow.origin":                   "upstream",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "v", cfg.Branches["release"].TagPrefix)
+	push, _ := cfg.GetBool(config.CommandKey("release", config.CommandFinish, config.OptPush))
+	assert.True(t, push)
+	assert.Equal(t, "upstream", cfg.Remote)
+}
+
+func TestAmendConfigRejectsInvalidValues(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	for key, value := range map[string]string{
+		"gitflow.release.finish.bogus":        "true",
+		"gitflow.release.finish.push":         "maybe",
+		"gitflow.release.finish.extra-tag":    "latest",
+		"gitflow.branch.unknown.tagprefix":    "v",
+		"gitflow.branch.release.tagprefix.xx": "v",
+	} {
+		err := config.AmendConfig(cfg, map[string]string{
+			"gitflow.branch.release.tagprefix": "v",
+			key:                                value,
+		})
+		assert.Error(t, err, key)
+	}
+	assert.Equal(t, "", cfg.Branches["release"].TagPrefix, "nothing is applied when a value is invalid")
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
 	"github.com/gittower/git-flow-next/internal/git"
 	"github.com/stretchr/testify/assert"
 )
@@ -461,3 +462,148 @@ func TestGitFlowAVHRemoteImport(t *testing.T) {
 	// Verify git-flow-avh remote is imported
 	assert.Equal(t, "avh-remote", cfg.Remote, "git-flow-avh remote should be imported")
 }
+
+func TestMatchTopicPrefix(t *testing.T) {
+	branchConfig := config.BranchConfig{Type: "topic", Prefix: "bugfix/", PrefixAliases: "bug/, fix/,,bugfix/"}
+
+	assert.Equal(t, []string{"bugfix/", "bug/", "fix/"}, config.TopicPrefixes(branchConfig))
+
+	prefix, ok := config.MatchTopicPrefix(branchConfig, "bugfix/crash")
+	assert.True(t, ok)
+	assert.Equal(t, "bugfix/", prefix)
+
+	prefix, ok = config.MatchTopicPrefix(branchConfig, "fix/login")
+	assert.True(t, ok)
+	assert.Equal(t, "fix/", prefix)
+
+	_, ok = config.MatchTopicPrefix(branchConfig, "feature/login")
+	assert.False(t, ok)
+}
+
+func TestTopicNameConflict(t *testing.T) {
+	cfg := config.DefaultConfig()
+	existing := map[string]bool{"feature/login": true, "bugfix/crash": true, "develop": true}
+	exists := func(branch string) bool { return existing[branch] }
+
+	// Another topic type uses the name
+	assert.Equal(t, "feature/login", config.TopicNameConflict(cfg, "bugfix", "login", exists))
+	// The type's own branches do not conflict
+	assert.Equal(t, "", config.TopicNameConflict(cfg, "feature", "login", exists))
+	// Base branches are not topic names
+	assert.Equal(t, "", config.TopicNameConflict(cfg, "feature", "develop", exists))
+	assert.Equal(t, "", config.TopicNameConflict(cfg, "feature", "logout", exists))
+}
+
+func TestResolveTopicName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	bugfix := cfg.Branches["bugfix"]
+	bugfix.PrefixAliases = "bug/"
+	cfg.Branches["bugfix"] = bugfix
+	existing := map[string]bool{"feature/login": true, "bug/crash": true, "feature/hotfix/typo": true}
+	exists := func(branch string) bool { return existing[branch] }
+
+	resolve := func(branchType, name string) (string, string, error) {
+		return config.ResolveTopicName(cfg, branchType, name, exists)
+	}
+
+	// The short and the full name resolve to the same branch
+	full, short, err := resolve("feature", "login")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"feature/login", "login"}, []string{full, short})
+	full, short, err = resolve("feature", "feature/login")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"feature/login", "login"}, []string{full, short})
+
+	// A branch with a prefix alias is found under the alias, a new one gets the prefix
+	full, short, err = resolve("bugfix", "bug/crash")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bug/crash", "crash"}, []string{full, short})
+	full, _, err = resolve("bugfix", "bug/leak")
+	assert.NoError(t, err)
+	assert.Equal(t, "bugfix/leak", full)
+
+	// A name with the prefix of another type is rejected, unless it exists as a branch of this type
+	_, _, err = resolve("feature", "bugfix/crash")
+	mismatch, ok := err.(*errors.TopicTypeMismatchError)
+	assert.True(t, ok, "expected a TopicTypeMismatchError, got %v", err)
+	if ok {
+		assert.Equal(t, "bugfix", mismatch.OtherType)
+		assert.Equal(t, "crash", mismatch.ShortName)
+	}
+	full, short, err = resolve("feature", "hotfix/typo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"feature/hotfix/typo", "hotfix/typo"}, []string{full, short})
+
+	// Without a check for existing branches, other types' prefixes are always rejected
+	_, _, err = config.ResolveTopicName(cfg, "feature", "hotfix/typo", nil)
+	assert.Error(t, err)
+
+	_, _, err = resolve("unknown", "login")
+	assert.IsType(t, &errors.InvalidBranchTypeError{}, err)
+}
+
+func TestSortByUpdateOrder(t *testing.T) {
+	cfg := config.DefaultConfig()
+	branches := []string{"staging", "develop", "qa", "preview"}
+
+	// Without gitflow.updateOrder the branches are sorted by name
+	config.SortByUpdateOrder(cfg, branches)
+	assert.Equal(t, []string{"develop", "preview", "qa", "staging"}, branches)
+
+	// Listed branches come first in the listed order; unknown names are ignored
+	cfg.CommandConfig["gitflow.updateorder"] = "staging, unknown, develop,staging"
+	config.SortByUpdateOrder(cfg, branches)
+	assert.Equal(t, []string{"staging", "develop", "preview", "qa"}, branches)
+}
+
+func TestVersionLines(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.line.2.x.base"] = "main"
+	cfg.CommandConfig["gitflow.line.1.x.base"] = "support/1.x"
+	cfg.CommandConfig["gitflow.line.1.x.tagprefix"] = "lts-"
+
+	lines := config.VersionLines(cfg)
+	assert.Equal(t, []config.VersionLine{
+		{Name: "1.x", Base: "support/1.x", TagPrefix: "lts-"},
+		{Name: "2.x", Base: "main"},
+	}, lines)
+
+	line, ok := config.VersionLineOfBase(cfg, "support/1.x")
+	assert.True(t, ok)
+	assert.Equal(t, "1.x", line.Name)
+	_, ok = config.VersionLineOfBase(cfg, "develop")
+	assert.False(t, ok)
+
+	// Lines named like 1.x hold the versions of that family
+	assert.True(t, line.Contains("1.5.0"))
+	assert.True(t, line.Contains("1"))
+	assert.False(t, line.Contains("10.0.0"))
+	assert.True(t, config.VersionLine{Name: "lts"}.Contains("10.0.0"))
+
+	// The line's tag prefix replaces the type's for branches started on it
+	cfg.CommandConfig["gitflow.branch.release/1.5.0.base"] = "support/1.x"
+	assert.Equal(t, "lts-1.5.0", config.ResolveFinishOptions(cfg, "release", "1.5.0", nil, nil, nil, nil, nil, nil).TagName)
+	assert.Equal(t, "lts-1.6.0", config.ResolveStartTagName(cfg, "release", "1.6.0", "support/1.x"))
+}
+
+func TestResolveFinishBackports(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	targets, openPR := config.ResolveFinishBackports(cfg, "hotfix", nil)
+	assert.Empty(t, targets)
+	assert.False(t, openPR)
+
+	cfg.CommandConfig["gitflow.hotfix.finish.backport"] = "support/1.x, support/2.x,,support/1.x"
+	cfg.CommandConfig["gitflow.hotfix.finish.backportpr"] = "true"
+	targets, openPR = config.ResolveFinishBackports(cfg, "hotfix", nil)
+	assert.Equal(t, []string{"support/1.x", "support/2.x"}, targets)
+	assert.True(t, openPR)
+
+	// --backport replaces the configured branches, --no-backport clears them
+	noPR := false
+	targets, openPR = config.ResolveFinishBackports(cfg, "hotfix", &config.MergeStrategyOptions{Backport: []string{"support/3.x"}, BackportPR: &noPR})
+	assert.Equal(t, []string{"support/3.x"}, targets)
+	assert.False(t, openPR)
+	targets, _ = config.ResolveFinishBackports(cfg, "hotfix", &config.MergeStrategyOptions{NoBackport: true})
+	assert.Empty(t, targets)
+}
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContextReloadAfterWrite tests that a loaded context only changes when it is reloaded.
+// Steps:
+// 1. Sets up an uninitialized repository
+// 2. Loads a context and verifies it reports git-flow as not initialized
+// 3. Saves a configuration with an extra base branch
+// 4. Verifies the context is unchanged until Reload is called
+// 5. Reloads and verifies the saved state is visible
+func TestContextReloadAfterWrite(t *testing.T) {
+	// Setup
+	dir := setupTestRepo(t)
+	defer cleanupTestRepo(t, dir)
+
+	cfgCtx, err := config.LoadContext()
+	if err != nil {
+		t.Fatalf("Failed to load context: %v", err)
+	}
+	assert.False(t, cfgCtx.Initialized, "Fresh repository should not be initialized")
+	assert.NotNil(t, cfgCtx.Config, "Uninitialized context should hold the default config")
+
+	// Save a configuration with an additional base branch
+	cfg := config.DefaultConfig()
+	cfg.Branches["staging"] = config.BranchConfig{
+		Type:               string(config.BranchTypeBase),
+		Parent:             "main",
+		UpstreamStrategy:   string(config.MergeStrategyMerge),
+		DownstreamStrategy: string(config.MergeStrategyMerge),
+	}
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	// The context keeps the state it was loaded with
+	assert.False(t, cfgCtx.Initialized, "Context should not change before Reload")
+	_, exists := cfgCtx.Config.Branches["staging"]
+	assert.False(t, exists, "Context should not see the new branch before Reload")
+
+	// Reload picks up the write
+	if err := cfgCtx.Reload(); err != nil {
+		t.Fatalf("Failed to reload context: %v", err)
+	}
+	assert.True(t, cfgCtx.Initialized, "Context should be initialized after Reload")
+	_, exists = cfgCtx.Config.Branches["staging"]
+	assert.True(t, exists, "Context should see the new branch after Reload")
+}
+
+// TestLoadContextWithRemote tests that the --remote override belongs to the context it is loaded with.
+// Steps:
+// 1. Sets up a repository with the default configuration
+// 2. Loads a context with the remote upstream and one without an override
+// 3. Verifies only the first reports upstream, also after reloading both
+func TestLoadContextWithRemote(t *testing.T) {
+	// Setup
+	dir := setupTestRepo(t)
+	defer cleanupTestRepo(t, dir)
+	if err := config.SaveConfig(config.DefaultConfig()); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	overridden, err := config.LoadContextWithRemote("upstream")
+	if err != nil {
+		t.Fatalf("Failed to load context: %v", err)
+	}
+	plain, err := config.LoadContext()
+	if err != nil {
+		t.Fatalf("Failed to load context: %v", err)
+	}
+	assert.Equal(t, "upstream", overridden.Config.Remote, "Context should report the override")
+	assert.Equal(t, "origin", plain.Config.Remote, "Context without override should report gitflow.origin")
+
+	// Reloading keeps each context's own remote
+	if err := overridden.Reload(); err != nil {
+		t.Fatalf("Failed to reload context: %v", err)
+	}
+	if err := plain.Reload(); err != nil {
+		t.Fatalf("Failed to reload context: %v", err)
+	}
+	assert.Equal(t, "upstream", overridden.Config.Remote, "Reloaded context should keep the override")
+	assert.Equal(t, "origin", plain.Config.Remote, "Reloaded context should not pick up another context's override")
+}
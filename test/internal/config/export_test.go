@@ -0,0 +1,62 @@
+package config_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.origin"] = "upstream"
+	cfg.CommandConfig["gitflow.feature.finish.rebase"] = "true"
+	cfg.CommandConfig["gitflow.releasenotes.file"] = "notes with \"quotes\".md"
+	cfg.CommandConfig["gitflow.branch.feature/login.base"] = "develop"
+
+	doc := config.NewDocument(cfg)
+	if doc.Remote != "upstream" {
+		t.Errorf("Expected remote 'upstream', got %q", doc.Remote)
+	}
+	if _, exists := doc.Settings["branch.feature/login.base"]; exists {
+		t.Error("Expected per-branch state to be left out of the settings")
+	}
+
+	for _, format := range config.ExportFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := config.EncodeDocument(doc, format)
+			if err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+			decoded, err := config.DecodeDocument(data, format)
+			if err != nil {
+				t.Fatalf("Failed to decode: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(doc, decoded) {
+				t.Errorf("Round trip changed the document:\nbefore: %+v\nafter:  %+v", doc, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeDocumentRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{"yaml unknown field", config.FormatYAML, "version: \"1.0\"\nbranchs: {}\n"},
+		{"json unknown branch field", config.FormatJSON, `{"branches": {"main": {"type": "base", "upstream": "merge"}}}`},
+		{"toml unsupported value", config.FormatTOML, "version = 1\n"},
+		{"toml unterminated string", config.FormatTOML, "version = \"1.0\n"},
+		{"toml duplicate key", config.FormatTOML, "[branches.main]\ntype = \"base\"\ntype = \"topic\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := config.DecodeDocument([]byte(tt.data), tt.format); err == nil {
+				t.Error("Expected decoding to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+)
+
+func TestRenderGraph(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{
+			format: config.GraphFormatDOT,
+			contains: []string{
+				"digraph gitflow {",
+				`"main" [shape=box, style=bold, label="main"];`,
+				`"develop" [shape=box, style=solid, label="develop\n(auto-update)"];`,
+				`"feature" [shape=ellipse, label="feature/*"];`,
+				`"main" -> "develop" [label="up: merge, down: merge"];`,
+				`"develop" -> "feature" [label="up: merge, down: rebase", style=dashed];`,
+				`"develop" -> "release" [label="start", style=dotted];`,
+			},
+		},
+		{
+			format: config.GraphFormatMermaid,
+			contains: []string{
+				"flowchart TD",
+				`n0["develop<br/>(auto-update)"]`,
+				`n1["main"]`,
+				`n3(["feature/*"])`,
+				`n1 ==>|"up: merge, down: merge"| n0`,
+				`n0 -->|"up: merge, down: rebase"| n3`,
+				`n0 -.->|"start"| n5`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			graph, err := config.RenderGraph(cfg, tt.format)
+			if err != nil {
+				t.Fatalf("Failed to render graph: %v", err)
+			}
+			for _, expected := range tt.contains {
+				if !strings.Contains(graph, expected) {
+					t.Errorf("Expected graph to contain %q, got:\n%s", expected, graph)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderGraphQuotesNames(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Branches["feature"] = config.BranchConfig{
+		Type:               string(config.BranchTypeTopic),
+		Parent:             "develop",
+		Prefix:             `feat"ure/`,
+		UpstreamStrategy:   string(config.MergeStrategyMerge),
+		DownstreamStrategy: string(config.MergeStrategyRebase),
+	}
+
+	dot, _ := config.RenderGraph(cfg, config.GraphFormatDOT)
+	if !strings.Contains(dot, `label="feat\"ure/*"`) {
+		t.Errorf("Expected quotes to be escaped in DOT, got:\n%s", dot)
+	}
+	mermaid, _ := config.RenderGraph(cfg, config.GraphFormatMermaid)
+	if !strings.Contains(mermaid, `(["feat#quot;ure/*"])`) {
+		t.Errorf("Expected quotes to be escaped in Mermaid, got:\n%s", mermaid)
+	}
+
+	if _, err := config.RenderGraph(cfg, "svg"); err == nil {
+		t.Error("Expected an unsupported format to fail")
+	}
+}
@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInferConfigRenamesBaseBranches tests that the inferred configuration uses
+// the base branch names found in the repository.
+// Steps:
+// 1. Infers a configuration from branches master and dev
+// 2. Verifies master and dev replace main and develop, including as parents
+func TestInferConfigRenamesBaseBranches(t *testing.T) {
+	cfg := config.InferConfig([]string{"master", "dev", "feature/login"})
+
+	assert.NotContains(t, cfg.Branches, "main")
+	assert.NotContains(t, cfg.Branches, "develop")
+	assert.Equal(t, "master", cfg.Branches["dev"].Parent)
+	assert.Equal(t, "dev", cfg.Branches["feature"].Parent)
+	assert.Equal(t, "dev", cfg.Branches["release"].StartPoint)
+	assert.Equal(t, "master", cfg.Branches["hotfix"].Parent)
+}
+
+// TestInferConfigTopicPrefixes tests that each topic type gets the prefix most
+// of its branches use and the other prefixes found as aliases.
+// Steps:
+// 1. Infers a configuration from branches with feat/, feature/ and fix/ prefixes
+// 2. Verifies feature uses feat/ with feature/ as alias
+// 3. Verifies bugfix uses fix/ and types without branches keep their defaults
+func TestInferConfigTopicPrefixes(t *testing.T) {
+	cfg := config.InferConfig([]string{"main", "feat/a", "feat/b", "feature/c", "fix/x"})
+
+	assert.Equal(t, "feat/", cfg.Branches["feature"].Prefix)
+	assert.Equal(t, "feature/", cfg.Branches["feature"].PrefixAliases)
+	assert.Equal(t, "fix/", cfg.Branches["bugfix"].Prefix)
+	assert.Equal(t, "", cfg.Branches["bugfix"].PrefixAliases)
+	assert.Equal(t, "release/", cfg.Branches["release"].Prefix)
+	assert.Contains(t, cfg.Branches, "main")
+	assert.Contains(t, cfg.Branches, "develop")
+}
+
+// TestInferFromHistory tests that init --infer counts merged branches towards
+// the topic prefixes and takes the tag prefix from the version tags.
+// Steps:
+// 1. Infers a configuration from branches, merged branch names and tags
+// 2. Verifies master and develop are the base branches and feature uses feat/
+// 3. Verifies release and hotfix use the tag prefix most version tags use
+// 4. Verifies the findings explain the guesses
+func TestInferFromHistory(t *testing.T) {
+	inference := config.InferFromHistory(
+		[]string{"master", "develop", "feat/open"},
+		[]string{"feat/a", "feat/a", "feature/b", "hotfix/1.0.1"},
+		[]string{"v1.0.0", "v1.1.0", "0.9", "release-notes"},
+	)
+	cfg := inference.Config
+
+	assert.Equal(t, "master", cfg.Branches["develop"].Parent)
+	assert.Equal(t, "feat/", cfg.Branches["feature"].Prefix)
+	assert.Equal(t, "feature/", cfg.Branches["feature"].PrefixAliases)
+	assert.Equal(t, "v", cfg.Branches["release"].TagPrefix)
+	assert.Equal(t, "v", cfg.Branches["hotfix"].TagPrefix)
+	assert.Contains(t, inference.Findings, "main branch: 'master' (existing branch)")
+	assert.Contains(t, inference.Findings, "feature prefix: 'feat/' (2 branch(es)), aliases feature/")
+	assert.Contains(t, inference.Findings, "release prefix: 'release/' (default, no branches found)")
+	assert.Contains(t, inference.Findings, "tag prefix: 'v' (2 of 3 version tags)")
+
+	inference = config.InferFromHistory([]string{"main"}, nil, nil)
+	assert.Contains(t, inference.Findings, "develop branch: 'develop' (no existing branch)")
+	assert.Contains(t, inference.Findings, "tag prefix: none (no version tags found)")
+	assert.Equal(t, "", inference.Config.Branches["release"].TagPrefix)
+}
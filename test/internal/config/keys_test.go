@@ -0,0 +1,147 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyBuilders(t *testing.T) {
+	assert.Equal(t, "gitflow.feature.finish.keep", config.CommandKey("feature", config.CommandFinish, config.OptKeep))
+	assert.Equal(t, "gitflow.finish.baseresolution", config.CommandKey("", config.CommandFinish, config.OptBaseResolution))
+	assert.Equal(t, "gitflow.branch.develop.autoUpdate", config.BranchKey("develop", config.PropAutoUpdate))
+	assert.Equal(t, "gitflow.branch.feature/login.base", config.BaseKey("feature/login"))
+}
+
+func TestTypedGetters(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig = map[string]string{
+		"gitflow.feature.finish.keep":       "yes",
+		"gitflow.finish.keep":               "false",
+		"gitflow.release.finish.push":       "maybe",
+		"gitflow.branch.develop.strategy":   "Rebase",
+		"gitflow.branch.main.strategy":      "octopus",
+		"gitflow.feature.finish.timeout":    "90s",
+		"gitflow.release.finish.timeout":    "soon",
+		"gitflow.feature.finish.signingkey": "ABC123",
+	}
+
+	// The first key that is set wins, and keys are matched case-insensitively
+	keep, ok := cfg.GetBool("gitflow.feature.finish.keep", "gitflow.finish.keep")
+	assert.True(t, ok)
+	assert.True(t, keep)
+	keep, ok = cfg.GetBool("gitflow.bugfix.finish.keep", "gitflow.finish.keep")
+	assert.True(t, ok)
+	assert.False(t, keep)
+	_, ok = cfg.GetBool("gitflow.hotfix.finish.keep")
+	assert.False(t, ok)
+	push, ok := cfg.GetBool("gitflow.release.finish.push")
+	assert.True(t, ok)
+	assert.False(t, push, "values that are not Git booleans read as false")
+
+	key, ok := cfg.GetString("gitflow.feature.finish.signingKey")
+	assert.True(t, ok)
+	assert.Equal(t, "ABC123", key)
+
+	strategy, err := cfg.GetStrategy("gitflow.branch.develop.strategy")
+	assert.NoError(t, err)
+	assert.Equal(t, config.MergeStrategyRebase, strategy)
+	_, err = cfg.GetStrategy("gitflow.branch.main.strategy")
+	invalid, ok := err.(*errors.InvalidConfigValueError)
+	if assert.True(t, ok, "expected an InvalidConfigValueError, got %v", err) {
+		assert.Equal(t, "gitflow.branch.main.strategy", invalid.Key)
+	}
+	strategy, err = cfg.GetStrategy("gitflow.branch.feature.strategy")
+	assert.NoError(t, err)
+	assert.Equal(t, config.MergeStrategy(""), strategy)
+
+	timeout, err := cfg.GetDuration("gitflow.feature.finish.timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+	_, err = cfg.GetDuration("gitflow.release.finish.timeout")
+	assert.Error(t, err)
+}
+
+func TestLookupKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		pattern string
+		known   bool
+	}{
+		{"gitflow.feature.finish.keep", "gitflow.<type>.finish.keep", true},
+		{"gitflow.branch.develop.autoupdate", "gitflow.branch.<type>.autoUpdate", true},
+		{"gitflow.branch.feature/my.fix.base", "gitflow.branch.<branch>.base", true},
+		{"gitflow.finish.requireUpToDateTopic", "gitflow.finish.requireuptodatetopic", true},
+		{"gitflow.hotfix.finish.requireuptodatetopic", "gitflow.<type>.finish.requireuptodatetopic", true},
+		{"gitflow.feature.finish.keeep", "", false},
+		{"gitflow.experimental.In-Memory-Merge", "gitflow.experimental.in-memory-merge", true},
+		{"gitflow.experimental.time-travel", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			spec, ok := config.LookupKey(tt.key)
+			assert.Equal(t, tt.known, ok)
+			assert.Equal(t, tt.pattern, spec.Pattern)
+		})
+	}
+}
+
+func TestKeySpecValidate(t *testing.T) {
+	spec, _ := config.LookupKey("gitflow.branch.develop.downstreamStrategy")
+	assert.NoError(t, spec.Validate("gitflow.branch.develop.downstreamStrategy", "squash"))
+	assert.Error(t, spec.Validate("gitflow.branch.develop.downstreamStrategy", "octopus"))
+
+	spec, _ = config.LookupKey("gitflow.feature.finish.push")
+	assert.NoError(t, spec.Validate("gitflow.feature.finish.push", "on"))
+	assert.Error(t, spec.Validate("gitflow.feature.finish.push", "sometimes"))
+}
+
+func TestTemplateLookup(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.template.shortlived.finish.squash"] = "true"
+	cfg.CommandConfig["gitflow.template.shortlived.start.fetch"] = "true"
+	cfg.CommandConfig["gitflow.finish.summary"] = "true"
+	cfg.CommandConfig["gitflow.template.shortlived.finish.summary"] = "false"
+
+	// Nothing is inherited until a type names the template
+	_, ok := cfg.GetBool(config.CommandKey("feature", config.CommandFinish, config.OptSquash))
+	assert.False(t, ok)
+
+	feature := cfg.Branches["feature"]
+	feature.Template = "shortlived"
+	cfg.Branches["feature"] = feature
+
+	squash, ok := cfg.GetBool(config.CommandKey("feature", config.CommandFinish, config.OptSquash))
+	assert.True(t, ok)
+	assert.True(t, squash)
+
+	// The type's own option takes precedence over the template
+	cfg.CommandConfig["gitflow.feature.start.fetch"] = "false"
+	fetch, _ := cfg.GetBool(config.CommandKey("feature", config.CommandStart, config.OptFetch))
+	assert.False(t, fetch)
+
+	// The template takes precedence over the global option
+	summary, _ := cfg.GetBool(config.CommandKey("feature", config.CommandFinish, config.OptSummary), config.CommandKey("", config.CommandFinish, config.OptSummary))
+	assert.False(t, summary)
+	summary, _ = cfg.GetBool(config.CommandKey("bugfix", config.CommandFinish, config.OptSummary), config.CommandKey("", config.CommandFinish, config.OptSummary))
+	assert.True(t, summary)
+}
+
+func TestApplyTemplate(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.template.shortlived.prefix"] = "exp/"
+	cfg.CommandConfig["gitflow.template.shortlived.upstreamstrategy"] = "squash"
+	cfg.CommandConfig["gitflow.template.shortlived.parent"] = "main"
+	cfg.CommandConfig["gitflow.template.shortlived.finish.keep"] = "true"
+
+	options := config.TemplateOptions(cfg, "shortlived")
+	assert.Len(t, options, 4)
+	assert.Empty(t, config.TemplateOptions(cfg, "missing"))
+
+	var branchConfig config.BranchConfig
+	config.ApplyTemplate(&branchConfig, options)
+	assert.Equal(t, config.BranchConfig{Prefix: "exp/", UpstreamStrategy: "squash"}, branchConfig)
+}
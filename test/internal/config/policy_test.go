@@ -0,0 +1,81 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0444))
+	return path
+}
+
+func TestReadPolicy(t *testing.T) {
+	policy, err := config.ReadPolicy(writePolicyFile(t, "pinned:\n  feature.finish.squash: \"yes\"\nprotected: [main]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"feature.finish.squash": "yes"}, policy.Pinned)
+	assert.Equal(t, []string{"main"}, policy.Protected)
+
+	_, err = config.ReadPolicy(writePolicyFile(t, "pinned:\n  feature.finish.sqash: \"true\"\n"))
+	assert.ErrorContains(t, err, "not a git-flow configuration key")
+
+	_, err = config.ReadPolicy(writePolicyFile(t, "pinned:\n  branch.feature.upstreamStrategy: octopus\n"))
+	assert.Error(t, err)
+
+	_, err = config.ReadPolicy(writePolicyFile(t, "protect: [main]\n"))
+	assert.Error(t, err)
+}
+
+func TestPolicyDrift(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CommandConfig["gitflow.branch.feature.upstreamstrategy"] = "merge"
+	cfg.CommandConfig["gitflow.feature.finish.squash"] = "on"
+
+	policy := &config.Policy{
+		Pinned: map[string]string{
+			"branch.feature.upstreamStrategy": "squash",
+			"feature.finish.squash":           "true",
+			"feature.finish.keep":             "false",
+			"release.finish.sign":             "true",
+		},
+		Protected: []string{"main", "production"},
+	}
+	assert.Equal(t, []string{
+		"gitflow.branch.feature.upstreamStrategy is 'merge', the policy pins it to 'squash'",
+		"gitflow.release.finish.sign is not set, the policy pins it to 'true'",
+		"protected branch 'production' is not configured",
+	}, policy.Drift(cfg))
+}
+
+func TestPolicyChangeViolations(t *testing.T) {
+	policy := &config.Policy{
+		Pinned:    map[string]string{"branch.feature.upstreamStrategy": "squash"},
+		Protected: []string{"main"},
+	}
+	current := config.DefaultConfig()
+	values := map[string]string{"gitflow.branch.feature.upstreamstrategy": "merge"}
+
+	// Unchanged drift is left to doctor
+	assert.Empty(t, policy.ChangeViolations(current, current, values, values))
+
+	planned := map[string]string{"gitflow.branch.feature.upstreamstrategy": "rebase"}
+	assert.Equal(t, []string{"gitflow.branch.feature.upstreamStrategy is pinned to 'squash'"},
+		policy.ChangeViolations(current, current, values, planned))
+
+	planned = map[string]string{"gitflow.branch.feature.upstreamstrategy": "Squash"}
+	assert.Empty(t, policy.ChangeViolations(current, current, values, planned))
+
+	changed := current.Clone()
+	main := changed.Branches["main"]
+	main.Prefix = "x/"
+	changed.Branches["main"] = main
+	assert.Equal(t, []string{"the configuration of 'main' is protected"},
+		policy.ChangeViolations(current, changed, values, values))
+}
@@ -0,0 +1,331 @@
+package configui_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gittower/git-flow-next/internal/config"
+	"github.com/gittower/git-flow-next/internal/configui"
+)
+
+// keys maps the names of special keys to their messages; other names are typed as runes
+var keys = map[string]tea.KeyMsg{
+	"enter":     {Type: tea.KeyEnter},
+	"esc":       {Type: tea.KeyEsc},
+	"up":        {Type: tea.KeyUp},
+	"down":      {Type: tea.KeyDown},
+	"left":      {Type: tea.KeyLeft},
+	"right":     {Type: tea.KeyRight},
+	"backspace": {Type: tea.KeyBackspace},
+	"ctrl+u":    {Type: tea.KeyCtrlU},
+}
+
+// press sends keys to the editor and reports whether the last one quit it
+func press(m *configui.Model, names ...string) bool {
+	var cmd tea.Cmd
+	for _, name := range names {
+		msg, ok := keys[name]
+		if !ok {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+		}
+		_, cmd = m.Update(msg)
+	}
+	if cmd == nil {
+		return false
+	}
+	_, quit := cmd().(tea.QuitMsg)
+	return quit
+}
+
+// selectedLine returns the line of the view marked as selected
+func selectedLine(m *configui.Model) string {
+	for _, line := range strings.Split(m.View(), "\n") {
+		if strings.HasPrefix(line, "> ") {
+			return line
+		}
+	}
+	return ""
+}
+
+// moveTo moves the cursor from the top down to the first row containing text
+func moveTo(t *testing.T, m *configui.Model, text string) {
+	t.Helper()
+	press(m, "g")
+	for i := 0; i < 100; i++ {
+		if strings.Contains(selectedLine(m), text) {
+			return
+		}
+		press(m, "down")
+	}
+	t.Fatalf("No row contains %q:\n%s", text, m.View())
+}
+
+// TestEditStrategyInline tests changing a merge strategy in place.
+// Steps:
+// 1. Expands feature and moves to its upstream strategy
+// 2. Changes the strategy to the next one with the right arrow key
+// 3. Saves and confirms the previewed changes
+// 4. Verifies the editor quits with the new strategy and bugfix unchanged
+func TestEditStrategyInline(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{
+		Preview: func(cfg *config.Config, removed []string) (string, error) {
+			return "+gitflow.branch.feature.upstreamstrategy=" + cfg.Branches["feature"].UpstreamStrategy + "\n", nil
+		},
+	})
+
+	moveTo(t, m, "feature  [topic]")
+	press(m, "enter")
+	moveTo(t, m, "Upstream strategy:")
+	press(m, "right")
+	if line := selectedLine(m); !strings.Contains(line, "rebase") {
+		t.Errorf("Expected the strategy to change to rebase in place, got %q", line)
+	}
+
+	press(m, "s")
+	if view := m.View(); !strings.Contains(view, "upstreamstrategy=rebase") || !strings.Contains(view, "Save this configuration?") {
+		t.Errorf("Expected the changes to be previewed for confirmation, got:\n%s", view)
+	}
+	if !press(m, "y") {
+		t.Fatal("Expected the editor to quit after saving")
+	}
+
+	result := m.Result()
+	if !result.Saved {
+		t.Error("Expected the configuration to be saved")
+	}
+	if got := result.Config.Branches["feature"].UpstreamStrategy; got != string(config.MergeStrategyRebase) {
+		t.Errorf("Expected upstream strategy 'rebase', got %q", got)
+	}
+	if got := result.Config.Branches["bugfix"].UpstreamStrategy; got != string(config.MergeStrategyMerge) {
+		t.Errorf("Expected bugfix to be unchanged, got %q", got)
+	}
+}
+
+// TestEditPrefixInline tests typing a prefix into the tree.
+// Steps:
+// 1. Expands feature, moves to its prefix and clears it
+// 2. Confirms the empty prefix and verifies it is rejected
+// 3. Types 'feat/' into the prefix row and confirms it
+// 4. Verifies the row shows and the configuration holds the new prefix
+func TestEditPrefixInline(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{})
+
+	moveTo(t, m, "feature  [topic]")
+	press(m, "enter")
+	moveTo(t, m, "Prefix:")
+
+	press(m, "enter", "ctrl+u", "enter")
+	if view := m.View(); !strings.Contains(view, "The prefix cannot be empty") {
+		t.Errorf("Expected the empty prefix to be rejected, got:\n%s", view)
+	}
+	if got := cfg.Branches["feature"].Prefix; got != "feature/" {
+		t.Errorf("Expected prefix to be unchanged, got %q", got)
+	}
+
+	press(m, "enter", "ctrl+u", "f", "e", "a", "t", "x", "backspace", "/")
+	if line := selectedLine(m); !strings.Contains(line, "feat/█") {
+		t.Errorf("Expected the typed prefix in the row, got %q", line)
+	}
+	press(m, "enter")
+	if got := cfg.Branches["feature"].Prefix; got != "feat/" {
+		t.Errorf("Expected prefix 'feat/', got %q", got)
+	}
+	if line := selectedLine(m); !strings.Contains(line, "feat/") {
+		t.Errorf("Expected the row to show the new prefix, got %q", line)
+	}
+}
+
+// TestAddTopicType tests adding a topic branch type under a base branch.
+// Steps:
+// 1. Selects develop and adds a topic branch type named docs
+// 2. Verifies its prefix row is opened for editing with the default prefix
+// 3. Accepts the prefix
+// 4. Verifies docs is configured under develop with the prefix 'docs/'
+func TestAddTopicType(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{})
+
+	moveTo(t, m, "develop  [base]")
+	press(m, "a", "d", "o", "c", "s")
+	if view := m.View(); !strings.Contains(view, "New topic branch under 'develop': docs") {
+		t.Errorf("Expected the name to be typed below the tree, got:\n%s", view)
+	}
+	press(m, "enter")
+	if line := selectedLine(m); !strings.Contains(line, "Prefix:") || !strings.Contains(line, "docs/█") {
+		t.Errorf("Expected the prefix of docs to be edited, got %q", line)
+	}
+	press(m, "enter")
+
+	docs, ok := cfg.Branches["docs"]
+	if !ok {
+		t.Fatal("Expected docs to be configured")
+	}
+	if docs.Type != string(config.BranchTypeTopic) || docs.Parent != "develop" || docs.StartPoint != "develop" || docs.Prefix != "docs/" {
+		t.Errorf("Expected a topic type under develop with prefix 'docs/', got %+v", docs)
+	}
+}
+
+// TestDeleteBranch tests deleting branches from the configuration.
+// Steps:
+// 1. Selects develop and tries to delete it
+// 2. Verifies it is kept because topic branch types depend on it
+// 3. Selects support, deletes it and confirms
+// 4. Verifies support is removed and reported for removal on save
+func TestDeleteBranch(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{})
+
+	moveTo(t, m, "develop  [base]")
+	press(m, "d")
+	if view := m.View(); !strings.Contains(view, "Cannot delete 'develop'") {
+		t.Errorf("Expected develop to be kept, got:\n%s", view)
+	}
+
+	moveTo(t, m, "support  [topic]")
+	press(m, "d", "y")
+	if _, ok := cfg.Branches["support"]; ok {
+		t.Error("Expected support to be removed")
+	}
+	if removed := m.Result().Removed; len(removed) != 1 || removed[0] != "support" {
+		t.Errorf("Expected support to be removed on save, got %v", removed)
+	}
+}
+
+// TestParentChoicesAvoidCycles tests that a branch cannot be moved under its own descendants.
+// Steps:
+// 1. Expands main and cycles its parent
+// 2. Verifies main stays at the top level, as develop descends from it
+// 3. Expands develop and cycles its parent to the top level
+// 4. Verifies develop has no parent and its merge strategies are reset to none
+func TestParentChoicesAvoidCycles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{})
+
+	moveTo(t, m, "main  [base]")
+	press(m, "enter")
+	moveTo(t, m, "Parent:")
+	press(m, "right")
+	if got := cfg.Branches["main"].Parent; got != "" {
+		t.Errorf("Expected main to stay at the top level, got parent %q", got)
+	}
+	moveTo(t, m, "main  [base]")
+	press(m, "enter")
+
+	moveTo(t, m, "develop  [base]")
+	press(m, "enter")
+	moveTo(t, m, "Parent:")
+	press(m, "right")
+	develop := cfg.Branches["develop"]
+	if develop.Parent != "" || develop.UpstreamStrategy != string(config.MergeStrategyNone) {
+		t.Errorf("Expected develop at the top level without strategies, got %+v", develop)
+	}
+	if line := selectedLine(m); !strings.Contains(line, "Parent:") {
+		t.Errorf("Expected the parent of develop to stay selected, got %q", line)
+	}
+}
+
+// TestQuitAsksToDiscard tests that quitting with unsaved changes asks first.
+// Steps:
+// 1. Changes the downstream strategy of feature
+// 2. Quits and declines to discard the changes, verifying the editor keeps running
+// 3. Quits and confirms
+// 4. Verifies the editor quits without saving
+func TestQuitAsksToDiscard(t *testing.T) {
+	cfg := config.DefaultConfig()
+	m := configui.New(cfg, configui.Options{})
+
+	moveTo(t, m, "feature  [topic]")
+	press(m, "enter")
+	moveTo(t, m, "Downstream strategy:")
+	press(m, "right")
+
+	if press(m, "q") {
+		t.Fatal("Expected to be asked before discarding changes")
+	}
+	if view := m.View(); !strings.Contains(view, "Discard unsaved changes?") {
+		t.Errorf("Expected the discard question, got:\n%s", view)
+	}
+	if press(m, "n") {
+		t.Fatal("Expected the editor to keep running")
+	}
+	if !press(m, "q", "y") {
+		t.Fatal("Expected the editor to quit")
+	}
+	if m.Result().Saved {
+		t.Error("Expected the configuration not to be saved")
+	}
+}
+
+// TestSaveValidates tests that an invalid configuration cannot be saved.
+// Steps:
+// 1. Creates an editor whose validation fails
+// 2. Saves
+// 3. Verifies the error is shown and the editor keeps running
+func TestSaveValidates(t *testing.T) {
+	m := configui.New(config.DefaultConfig(), configui.Options{
+		Validate: func(cfg *config.Config) error {
+			return fmt.Errorf("no branches are defined")
+		},
+	})
+
+	if press(m, "s") {
+		t.Fatal("Expected the editor to keep running")
+	}
+	if view := m.View(); !strings.Contains(view, "Cannot save: no branches are defined") {
+		t.Errorf("Expected the validation error, got:\n%s", view)
+	}
+	if m.Result().Saved {
+		t.Error("Expected the configuration not to be saved")
+	}
+}
+
+// TestStartSelection tests choosing the configuration to start from.
+// Steps:
+// 1. Creates an editor offering the classic and the GitHub preset
+// 2. Verifies the starts are listed before the tree
+// 3. Chooses the GitHub preset, saves and confirms
+// 4. Verifies the saved configuration is the GitHub preset
+func TestStartSelection(t *testing.T) {
+	m := configui.New(nil, configui.Options{Starts: []configui.Start{
+		{Name: "Classic", Config: config.PresetConfig(config.PresetClassic)},
+		{Name: "GitHub", Config: config.PresetConfig(config.PresetGitHub)},
+	}})
+
+	if view := m.View(); !strings.Contains(view, "Start from") || !strings.Contains(view, "GitHub") {
+		t.Errorf("Expected the starts to be listed, got:\n%s", view)
+	}
+	press(m, "down", "enter")
+	if view := m.View(); !strings.Contains(view, "Branch hierarchy") {
+		t.Errorf("Expected the tree after choosing a start, got:\n%s", view)
+	}
+	if !press(m, "s", "y") {
+		t.Fatal("Expected the editor to quit after saving")
+	}
+
+	result := m.Result()
+	if _, ok := result.Config.Branches["develop"]; ok || !result.Saved {
+		t.Errorf("Expected the GitHub preset to be saved, got %v", result.Config.Branches)
+	}
+}
+
+// TestFormatTree tests the plain rendering of the branch hierarchy.
+// Steps:
+// 1. Formats the default configuration
+// 2. Verifies main is at the top level and develop and its topic types are drawn below it
+func TestFormatTree(t *testing.T) {
+	tree := configui.FormatTree(config.DefaultConfig())
+	lines := strings.Split(strings.TrimSpace(tree), "\n")
+
+	if !strings.HasPrefix(lines[0], "main  [base]") {
+		t.Errorf("Expected main on the first line, got %q", lines[0])
+	}
+	for _, want := range []string{"├── develop  [base]", "│   └── feature  [topic]  prefix feature/"} {
+		if !strings.Contains(tree, want) {
+			t.Errorf("Expected %q in the tree, got:\n%s", want, tree)
+		}
+	}
+}
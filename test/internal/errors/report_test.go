@@ -0,0 +1,121 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+)
+
+func TestNewReportFromWrappedCommandError(t *testing.T) {
+	cause := &errors.CommandError{Args: []string{"branch", "-d", "feature/x"}, Err: fmt.Errorf("failed to delete branch: not fully merged\n")}
+	err := &errors.GitError{Operation: "delete branch 'feature/x'", Err: cause}
+
+	report := errors.NewReport(err)
+	if report.Code != "git_error" {
+		t.Errorf("Expected code git_error, got %s", report.Code)
+	}
+	if report.Command != "git branch -d feature/x" {
+		t.Errorf("Expected the failed command, got %q", report.Command)
+	}
+	if strings.HasSuffix(report.Message, "\n") {
+		t.Errorf("Expected the message to be trimmed, got %q", report.Message)
+	}
+}
+
+func TestNewReportForPlainError(t *testing.T) {
+	report := errors.NewReport(fmt.Errorf("something failed"))
+	if report.Code != "error" || report.Command != "" || report.Hint != "" {
+		t.Errorf("Expected a plain report, got %+v", report)
+	}
+}
+
+func TestRenderPrintsHint(t *testing.T) {
+	var out bytes.Buffer
+	errors.Render(&out, &errors.InvalidConfigValueError{Key: "gitflow.x", Value: "y", Allowed: []string{"a", "b"}})
+
+	expected := "Error: invalid value 'y' for gitflow.x (valid options: a, b)\nHint: set a valid value with 'git config gitflow.x <value>' or remove it with 'git config --unset gitflow.x'\n"
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderSkipsHintInMessage(t *testing.T) {
+	var out bytes.Buffer
+	errors.Render(&out, &errors.NotInitializedError{})
+
+	if out.String() != "Error: git flow is not initialized (run 'git flow init' first)\n" {
+		t.Errorf("Expected the hint not to be repeated, got:\n%s", out.String())
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var out bytes.Buffer
+	errors.RenderJSON(&out, &errors.DetachedHeadError{Usage: "git flow feature finish <name>"})
+
+	var document struct {
+		Error errors.Report `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", out.String(), err)
+	}
+	if document.Error.Code != "detached_head" || document.Error.Hint != "check out a branch first or name it: git flow feature finish <name>" {
+		t.Errorf("Unexpected report: %+v", document.Error)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Expected a single line, got %q", out.String())
+	}
+}
+
+func TestPushRejectionHints(t *testing.T) {
+	hook := &errors.PushRejectedError{Remote: "origin", Refs: []errors.RejectedRef{{Ref: "develop", Reason: "pre-receive hook declined", Remote: true}}, Messages: []string{"remote: develop is frozen"}}
+	if !strings.Contains(hook.Error(), "remote: develop is frozen") || !strings.Contains(hook.Hint(), "remote refused") {
+		t.Errorf("Expected the remote's message and a hook hint, got %q / %q", hook.Error(), hook.Hint())
+	}
+
+	diverged := &errors.PushRejectedError{Remote: "origin", Refs: []errors.RejectedRef{{Ref: "feature/x", Reason: "non-fast-forward"}}}
+	if strings.Contains(diverged.Hint(), "--force-with-lease") {
+		t.Errorf("Expected no --force-with-lease suggestion for unknown remote commits, got %q", diverged.Hint())
+	}
+	diverged.ForceWithLease = true
+	if !strings.Contains(diverged.Hint(), "--force-with-lease") {
+		t.Errorf("Expected a --force-with-lease suggestion for rebased commits, got %q", diverged.Hint())
+	}
+
+	wrapped := &errors.GitError{Operation: "push", Err: &errors.CommandError{Args: []string{"push"}, Err: hook}}
+	if errors.PushRejection(wrapped) != hook {
+		t.Error("Expected PushRejection to find the rejection in the chain")
+	}
+}
+
+// TestRemoteFailureHints tests that remote failures give a hint for their kind and are found in the error chain.
+func TestRemoteFailureHints(t *testing.T) {
+	httpsAuth := &errors.RemoteError{Remote: "origin", URL: "https://example.com/repo.git", Operation: "fetch from", Kind: errors.RemoteAuthFailed}
+	if !strings.Contains(httpsAuth.Hint(), "credential helper") {
+		t.Errorf("Expected a credentials hint for an HTTPS remote, got %q", httpsAuth.Hint())
+	}
+	sshAuth := &errors.RemoteError{Remote: "origin", URL: "git@example.com:repo.git", Operation: "push to", Kind: errors.RemoteAuthFailed}
+	if !strings.Contains(sshAuth.Hint(), "ssh-add") {
+		t.Errorf("Expected an SSH key hint for an SSH remote, got %q", sshAuth.Hint())
+	}
+	proxy := &errors.RemoteError{Remote: "origin", Operation: "fetch from", Kind: errors.RemoteProxyFailed}
+	if !strings.Contains(proxy.Hint(), "http.proxy") || proxy.Code() != "remote_proxy_failed" {
+		t.Errorf("Expected a proxy hint and code, got %q / %q", proxy.Hint(), proxy.Code())
+	}
+
+	unreachable := &errors.RemoteError{Remote: "origin", Operation: "fetch from", Kind: errors.RemoteUnreachable, Output: "fatal: unable to access\n\nCould not resolve host", Attempts: 3}
+	if !unreachable.Transient() || httpsAuth.Transient() {
+		t.Error("Expected only the unreachable remote to be transient")
+	}
+	if message := unreachable.Error(); !strings.Contains(message, "tried 3 times") || strings.Contains(message, "\n  \n") {
+		t.Errorf("Expected the attempts and the indented output without blank lines, got %q", message)
+	}
+
+	wrapped := &errors.GitError{Operation: "fetch", Err: &errors.CommandError{Args: []string{"fetch"}, Err: unreachable}}
+	if errors.RemoteFailure(wrapped) != unreachable {
+		t.Error("Expected RemoteFailure to find the remote failure in the chain")
+	}
+}
@@ -0,0 +1,60 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/events"
+)
+
+// stepRecorder records the steps and tags reported on a bus
+type stepRecorder struct {
+	events.NopObserver
+	steps []string
+	tags  []string
+}
+
+func (r *stepRecorder) OnStepStart(event events.StepStart) {
+	r.steps = append(r.steps, event.Step)
+}
+
+func (r *stepRecorder) OnTagCreated(event events.TagCreated) {
+	r.tags = append(r.tags, event.Tag)
+}
+
+// TestBusDeliversToEveryObserver tests that a bus delivers each event to all subscribed observers in order.
+// Steps:
+// 1. Subscribes two recorders to a new bus
+// 2. Reports two steps, a tag and a conflict nobody handles
+// 3. Verifies both recorders received the steps and the tag in order
+func TestBusDeliversToEveryObserver(t *testing.T) {
+	bus := events.NewBus()
+	first, second := &stepRecorder{}, &stepRecorder{}
+	bus.Subscribe(first)
+	bus.Subscribe(second)
+
+	bus.StepStart(events.StepStart{Operation: "finish", Branch: "feature/a", Step: "merge"})
+	bus.StepStart(events.StepStart{Operation: "finish", Branch: "feature/a", Step: "create_tag"})
+	bus.TagCreated(events.TagCreated{Tag: "1.0.0", Branch: "main"})
+	bus.Conflict(events.Conflict{Branch: "develop", Source: "main"})
+
+	for _, recorder := range []*stepRecorder{first, second} {
+		if len(recorder.steps) != 2 || recorder.steps[0] != "merge" || recorder.steps[1] != "create_tag" {
+			t.Errorf("Expected the merge and create_tag steps, got %v", recorder.steps)
+		}
+		if len(recorder.tags) != 1 || recorder.tags[0] != "1.0.0" {
+			t.Errorf("Expected the 1.0.0 tag, got %v", recorder.tags)
+		}
+	}
+}
+
+// TestNilBusDropsEvents tests that reporting to a nil bus does nothing.
+// Steps:
+// 1. Reports one event of each kind to a nil bus
+// 2. Verifies nothing panics
+func TestNilBusDropsEvents(t *testing.T) {
+	var bus *events.Bus
+	bus.StepStart(events.StepStart{Step: "merge"})
+	bus.Conflict(events.Conflict{})
+	bus.TagCreated(events.TagCreated{Tag: "1.0.0"})
+	bus.BranchDeleted(events.BranchDeleted{Branch: "feature/a"})
+}
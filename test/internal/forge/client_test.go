@@ -0,0 +1,162 @@
+package forge_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/forge"
+)
+
+// isolateTokens hides the tokens of the environment and the user's client
+// configuration from token discovery
+func isolateTokens(t *testing.T) string {
+	t.Helper()
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GITLAB_TOKEN", "BITBUCKET_TOKEN", "GH_CONFIG_DIR", "HUB_CONFIG", "GLAB_CONFIG_DIR"} {
+		t.Setenv(name, "")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(configHome, "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	return configHome
+}
+
+func githubRepo(t *testing.T) *forge.Repository {
+	t.Helper()
+	repo, err := forge.ParseRemoteURL("git@github.com:acme/app.git", "")
+	if err != nil {
+		t.Fatalf("ParseRemoteURL failed: %v", err)
+	}
+	return repo
+}
+
+func TestClientCachesResponses(t *testing.T) {
+	requests, revalidated := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"login": "octocat"}`)
+	}))
+	defer server.Close()
+
+	client := forge.NewClient(githubRepo(t), &forge.Token{Value: "secret"}, t.TempDir())
+	client.BaseURL = server.URL
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.Get("/user", &user); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if requests != 1 || user.Login != "octocat" {
+		t.Errorf("Expected the second response to come from the cache, got %d request(s) and login %q", requests, user.Login)
+	}
+
+	// A stale response is revalidated with its ETag
+	client.CacheTTL = 0
+	user.Login = ""
+	if err := client.Get("/user", &user); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if revalidated != 1 || user.Login != "octocat" {
+		t.Errorf("Expected the cached response to be revalidated, got %d revalidation(s) and login %q", revalidated, user.Login)
+	}
+
+	// Responses are cached per token
+	other := forge.NewClient(githubRepo(t), &forge.Token{Value: "other"}, client.CacheDir)
+	other.BaseURL = server.URL
+	err := other.Get("/user", &user)
+	if apiErr, ok := err.(*forge.APIError); !ok || !apiErr.Unauthorized() {
+		t.Errorf("Expected another token not to see the cached response, got %v", err)
+	}
+}
+
+func TestClientWaitsForRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "60")
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Unix()))
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := forge.NewClient(githubRepo(t), nil, "")
+	client.BaseURL = server.URL
+	if err := client.Get("/rate_limit", nil); err != nil {
+		t.Fatalf("Expected the request to be retried after the rate limit reset: %v", err)
+	}
+	if requests != 2 || client.RateLimit.Remaining != 59 || client.RateLimit.Limit != 60 {
+		t.Errorf("Expected 2 requests and 59 of 60 requests left, got %d and %+v", requests, client.RateLimit)
+	}
+
+	requests = 0
+	client.MaxRateLimitWait = 0
+	err := client.Get("/rate_limit", nil)
+	if _, ok := err.(*forge.RateLimitError); !ok {
+		t.Errorf("Expected a rate limit error without waiting, got %v", err)
+	}
+}
+
+func TestDiscoverToken(t *testing.T) {
+	configHome := isolateTokens(t)
+	repo := githubRepo(t)
+
+	if token := repo.DiscoverToken(); token != nil {
+		t.Fatalf("Expected no token, got one from %s", token.Source)
+	}
+
+	// hub's configuration lists accounts per host
+	hubConfig := "github.com:\n- user: octocat\n  oauth_token: hub-token\n"
+	if err := os.WriteFile(filepath.Join(configHome, "hub"), []byte(hubConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token := repo.DiscoverToken(); token == nil || token.Value != "hub-token" {
+		t.Errorf("Expected the token from the hub config, got %+v", token)
+	}
+
+	// gh takes precedence over hub
+	if err := os.MkdirAll(filepath.Join(configHome, "gh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	ghConfig := "github.com:\n    user: octocat\n    oauth_token: gh-token\n"
+	if err := os.WriteFile(filepath.Join(configHome, "gh", "hosts.yml"), []byte(ghConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if token := repo.DiscoverToken(); token == nil || token.Value != "gh-token" {
+		t.Errorf("Expected the token from the gh config, got %+v", token)
+	}
+
+	// The environment takes precedence over everything
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	if token := repo.DiscoverToken(); token == nil || token.Value != "env-token" || token.Source != "GITHUB_TOKEN" {
+		t.Errorf("Expected the token from GITHUB_TOKEN, got %+v", token)
+	}
+	if masked := (&forge.Token{Value: "ghp_1234567890abcd"}).Masked(); masked != "****abcd" {
+		t.Errorf("Expected the token to be masked, got %q", masked)
+	}
+}
@@ -0,0 +1,67 @@
+package forge_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/forge"
+)
+
+func TestCompareURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		remoteURL string
+		kind      forge.Kind
+		expected  string
+	}{
+		{
+			name:      "github scp-like",
+			remoteURL: "git@github.com:owner/repo.git",
+			expected:  "https://github.com/owner/repo/compare/develop...feature/login",
+		},
+		{
+			name:      "github https with user",
+			remoteURL: "https://user@github.com/owner/repo",
+			expected:  "https://github.com/owner/repo/compare/develop...feature/login",
+		},
+		{
+			name:      "gitlab ssh with port and subgroup",
+			remoteURL: "ssh://git@gitlab.com:2222/group/sub/repo.git",
+			expected:  "https://gitlab.com/group/sub/repo/-/compare/develop...feature/login",
+		},
+		{
+			name:      "bitbucket",
+			remoteURL: "git@bitbucket.org:team/repo.git",
+			expected:  "https://bitbucket.org/team/repo/branches/compare/feature/login%0Ddevelop",
+		},
+		{
+			name:      "self-hosted with explicit kind",
+			remoteURL: "https://git.example.com:8443/team/repo.git",
+			kind:      forge.GitLab,
+			expected:  "https://git.example.com:8443/team/repo/-/compare/develop...feature/login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := forge.ParseRemoteURL(tt.remoteURL, tt.kind)
+			if err != nil {
+				t.Fatalf("ParseRemoteURL(%q) failed: %v", tt.remoteURL, err)
+			}
+			if got := repo.CompareURL("develop", "feature/login"); got != tt.expected {
+				t.Errorf("CompareURL() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRemoteURLRejectsUnknown(t *testing.T) {
+	for _, remoteURL := range []string{
+		"/srv/git/repo.git",
+		"file:///srv/git/repo.git",
+		"git@git.example.com:team/repo.git",
+	} {
+		if _, err := forge.ParseRemoteURL(remoteURL, ""); err == nil {
+			t.Errorf("Expected ParseRemoteURL(%q) to fail", remoteURL)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestCommandCacheSeesNewBranches tests that the persistent ref check follows branch changes.
+// Steps:
+// 1. Sets up a test repository and creates a command cache
+// 2. Verifies a missing branch is reported as missing
+// 3. Creates and deletes the branch with plain git
+// 4. Verifies each change is reflected without restarting the cache
+func TestCommandCacheSeesNewBranches(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		cache := git.NewCache()
+		defer cache.Close()
+
+		if err := cache.BranchExists("cached"); err == nil {
+			t.Fatal("Expected branch 'cached' to be missing")
+		}
+
+		testutil.RunGit(t, dir, "branch", "cached")
+		if err := cache.BranchExists("cached"); err != nil {
+			t.Errorf("Expected new branch to be found: %v", err)
+		}
+
+		testutil.RunGit(t, dir, "branch", "-D", "cached")
+		if err := cache.BranchExists("cached"); err == nil {
+			t.Error("Expected deleted branch to be missing")
+		}
+	})
+}
+
+// TestCommandCacheConfigSnapshot tests that gitflow.* reads come from a snapshot refreshed on writes.
+// Steps:
+// 1. Sets up a test repository with a gitflow key and creates a command cache
+// 2. Reads the key using a different letter case
+// 3. Changes the key through git.SetConfig and verifies the new value is read
+// 4. Unsets the key and verifies reading it fails
+func TestCommandCacheConfigSnapshot(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.RunGit(t, dir, "config", "gitflow.feature.finish.squash", "true")
+
+	withGitRepo(t, dir, func() {
+		cache := git.NewCache()
+		defer cache.Close()
+
+		value, err := cache.GetConfig("gitflow.feature.finish.Squash")
+		if err != nil || value != "true" {
+			t.Fatalf("Expected 'true', got %q (%v)", value, err)
+		}
+
+		if err := git.SetConfig("gitflow.feature.finish.squash", "false"); err != nil {
+			t.Fatalf("Failed to set config: %v", err)
+		}
+		value, err = cache.GetConfig("gitflow.feature.finish.squash")
+		if err != nil || value != "false" {
+			t.Errorf("Expected 'false' after write, got %q (%v)", value, err)
+		}
+
+		if err := git.UnsetConfig("gitflow.feature.finish.squash"); err != nil {
+			t.Fatalf("Failed to unset config: %v", err)
+		}
+		if _, err := cache.GetConfig("gitflow.feature.finish.squash"); err == nil {
+			t.Error("Expected reading an unset key to fail")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestFetchStopsOnCancel tests that a cancelled context stops a running fetch.
+// Steps:
+// 1. Sets up a test repository with a remote whose upload-pack hangs
+// 2. Fetches from it with a context that times out shortly after
+// 3. Verifies the fetch returns well before the hanging command would finish
+// 4. Verifies the returned error wraps the context error
+func TestFetchStopsOnCancel(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	remoteDir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, remoteDir)
+
+	testutil.RunGit(t, dir, "remote", "add", "slow", remoteDir)
+	testutil.RunGit(t, dir, "config", "remote.slow.uploadpack", "sleep 60 #")
+
+	withGitRepo(t, dir, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		err := git.Fetch(ctx, "slow")
+		if err == nil {
+			t.Fatal("Expected cancelled fetch to fail")
+		}
+		if elapsed := time.Since(started); elapsed > 15*time.Second {
+			t.Errorf("Expected fetch to stop soon after cancellation, took %s", elapsed)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected error to wrap the context error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// setupConflict creates a merge of 'other' into the current branch that
+// conflicts in the middle line of file.txt and changes the first and last
+// line on one side each
+func setupConflict(t *testing.T, dir string) {
+	t.Helper()
+	testutil.WriteFile(t, dir, "file.txt", "a\n1\n2\nb\n3\n4\nc\n")
+	testutil.RunGit(t, dir, "add", "file.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Base")
+	testutil.RunGit(t, dir, "checkout", "-b", "other")
+	testutil.WriteFile(t, dir, "file.txt", "a\n1\n2\nother\n3\n4\nc-other\n")
+	testutil.RunGit(t, dir, "commit", "-am", "Other")
+	testutil.RunGit(t, dir, "checkout", "-")
+	testutil.WriteFile(t, dir, "file.txt", "a-ours\n1\n2\nours\n3\n4\nc\n")
+	testutil.RunGit(t, dir, "commit", "-am", "Ours")
+	if _, err := testutil.RunGit(t, dir, "merge", "other"); err == nil {
+		t.Fatal("Expected the merge to conflict")
+	}
+}
+
+func TestResolveConflictFavoring(t *testing.T) {
+	tests := []struct {
+		side     string
+		expected string
+	}{
+		{git.ConflictSideOurs, "a-ours\n1\n2\nours\n3\n4\nc-other\n"},
+		{git.ConflictSideTheirs, "a-ours\n1\n2\nother\n3\n4\nc-other\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.side, func(t *testing.T) {
+			dir := testutil.SetupTestRepo(t)
+			defer testutil.CleanupTestRepo(t, dir)
+			setupConflict(t, dir)
+
+			withGitRepo(t, dir, func() {
+				files, err := git.UnmergedFiles()
+				if err != nil || len(files) != 1 || files[0] != "file.txt" {
+					t.Fatalf("Expected file.txt to be unmerged, got %v (%v)", files, err)
+				}
+				if err := git.ResolveConflictFavoring("file.txt", tt.side); err != nil {
+					t.Fatalf("ResolveConflictFavoring failed: %v", err)
+				}
+				if files, _ := git.UnmergedFiles(); len(files) != 0 {
+					t.Errorf("Expected no unmerged files, got %v", files)
+				}
+			})
+
+			content, err := os.ReadFile(filepath.Join(dir, "file.txt"))
+			if err != nil {
+				t.Fatalf("Failed to read file.txt: %v", err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(content))
+			}
+		})
+	}
+}
+
+func TestResolveConflictFavoringDeletedFile(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	testutil.WriteFile(t, dir, "file.txt", "base\n")
+	testutil.RunGit(t, dir, "add", "file.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Base")
+	testutil.RunGit(t, dir, "checkout", "-b", "other")
+	testutil.RunGit(t, dir, "rm", "-q", "file.txt")
+	testutil.RunGit(t, dir, "commit", "-m", "Delete")
+	testutil.RunGit(t, dir, "checkout", "-")
+	testutil.WriteFile(t, dir, "file.txt", "changed\n")
+	testutil.RunGit(t, dir, "commit", "-am", "Change")
+	if _, err := testutil.RunGit(t, dir, "merge", "other"); err == nil {
+		t.Fatal("Expected the merge to conflict")
+	}
+
+	withGitRepo(t, dir, func() {
+		if err := git.ResolveConflictFavoring("file.txt", git.ConflictSideTheirs); err != nil {
+			t.Fatalf("ResolveConflictFavoring failed: %v", err)
+		}
+		if files, _ := git.UnmergedFiles(); len(files) != 0 {
+			t.Errorf("Expected no unmerged files, got %v", files)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(dir, "file.txt")); !os.IsNotExist(err) {
+		t.Error("Expected file.txt to be deleted as on the other side")
+	}
+}
@@ -1,6 +1,7 @@
 package git_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -126,7 +127,7 @@ func TestDeleteNonExistentRemoteBranch(t *testing.T) {
 	// Use our helper to change to the test directory and run the test
 	withGitRepo(t, dir, func() {
 		// Try to delete a non-existent branch
-		err = git.DeleteRemoteBranch("origin", "feature/non-existent")
+		err = git.DeleteRemoteBranch(context.Background(), "origin", "feature/non-existent")
 		if err == nil {
 			t.Error("Expected an error when deleting non-existent remote branch, got nil")
 		}
@@ -177,7 +178,7 @@ func TestDeleteExistingRemoteBranch(t *testing.T) {
 	// Use our helper to change to the test directory and run the test
 	withGitRepo(t, dir, func() {
 		// Delete the remote branch
-		err = git.DeleteRemoteBranch("origin", "feature/test")
+		err = git.DeleteRemoteBranch(context.Background(), "origin", "feature/test")
 		if err != nil {
 			t.Errorf("Expected no error when deleting existing remote branch, got: %v", err)
 		}
@@ -204,7 +205,7 @@ func TestDeleteBranchFromNonExistentRemote(t *testing.T) {
 	// Use our helper to change to the test directory and run the test
 	withGitRepo(t, dir, func() {
 		// Try to delete a branch from a non-existent remote
-		err := git.DeleteRemoteBranch("non-existent-remote", "feature/test")
+		err := git.DeleteRemoteBranch(context.Background(), "non-existent-remote", "feature/test")
 		if err == nil {
 			t.Error("Expected an error when deleting from non-existent remote, got nil")
 		}
@@ -628,7 +629,7 @@ func TestFetchBranch(t *testing.T) {
 
 	// Fetch the branch using FetchBranch
 	withGitRepo(t, dir, func() {
-		err := git.FetchBranch("origin", "main")
+		err := git.FetchBranch(context.Background(), "origin", "main")
 		if err != nil {
 			t.Fatalf("FetchBranch returned unexpected error: %v", err)
 		}
@@ -661,7 +662,7 @@ func TestFetchBranchNonExistent(t *testing.T) {
 	defer testutil.CleanupTestRepo(t, remoteDir)
 
 	withGitRepo(t, dir, func() {
-		err := git.FetchBranch("origin", "non-existent-branch")
+		err := git.FetchBranch(context.Background(), "origin", "non-existent-branch")
 		if err == nil {
 			t.Error("Expected error when fetching non-existent branch, got nil")
 		}
@@ -0,0 +1,50 @@
+package git_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/git"
+)
+
+// TestParseGitVersion tests parsing of 'git --version' output across platforms.
+func TestParseGitVersion(t *testing.T) {
+	tests := []struct {
+		output              string
+		major, minor, patch int
+		raw                 string
+	}{
+		{"git version 2.39.5\n", 2, 39, 5, "2.39.5"},
+		{"git version 2.45.1.windows.1", 2, 45, 1, "2.45.1.windows.1"},
+		{"git version 2.39.3 (Apple Git-146)", 2, 39, 3, "2.39.3 (Apple Git-146)"},
+		{"git version 2.40.0-rc1", 2, 40, 0, "2.40.0-rc1"},
+		{"git version 3.0", 3, 0, 0, "3.0"},
+	}
+
+	for _, test := range tests {
+		version, ok := git.ParseGitVersion(test.output)
+		if !ok {
+			t.Errorf("ParseGitVersion(%q) failed", test.output)
+			continue
+		}
+		if version.Major != test.major || version.Minor != test.minor || version.Patch != test.patch || version.Raw != test.raw {
+			t.Errorf("ParseGitVersion(%q) = %+v, expected %d.%d.%d (%q)", test.output, version, test.major, test.minor, test.patch, test.raw)
+		}
+	}
+
+	for _, output := range []string{"not git", ""} {
+		if _, ok := git.ParseGitVersion(output); ok {
+			t.Errorf("Expected ParseGitVersion(%q) to fail", output)
+		}
+	}
+}
+
+// TestGitVersionAtLeast tests the minimum version comparison used for feature detection.
+func TestGitVersionAtLeast(t *testing.T) {
+	version, _ := git.ParseGitVersion("git version 2.38.1")
+	if !version.AtLeast(2, 38) || !version.AtLeast(2, 5) || !version.AtLeast(1, 99) {
+		t.Errorf("Expected %s to satisfy older minimums", version)
+	}
+	if version.AtLeast(2, 39) || version.AtLeast(3, 0) {
+		t.Errorf("Expected %s not to satisfy newer minimums", version)
+	}
+}
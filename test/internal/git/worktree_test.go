@@ -219,3 +219,128 @@ func TestMergeStateNotSharedBetweenWorktrees(t *testing.T) {
 		}
 	})
 }
+
+func TestIsLinkedWorktree(t *testing.T) {
+	// Setup main repo
+	mainRepo := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, mainRepo)
+
+	worktreePath, err := os.MkdirTemp("", "git-flow-worktree-linked-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory for worktree: %v", err)
+	}
+	defer os.RemoveAll(worktreePath)
+	os.RemoveAll(worktreePath)
+
+	if _, err := testutil.RunGit(t, mainRepo, "worktree", "add", worktreePath, "-b", "worktree-branch"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	withGitRepo(t, mainRepo, func() {
+		linked, err := git.IsLinkedWorktree()
+		if err != nil || linked {
+			t.Errorf("Expected main working tree not to be linked, got %v (%v)", linked, err)
+		}
+	})
+
+	withGitRepo(t, worktreePath, func() {
+		linked, err := git.IsLinkedWorktree()
+		if err != nil || !linked {
+			t.Errorf("Expected worktree to be linked, got %v (%v)", linked, err)
+		}
+	})
+}
+
+func TestListWorktrees(t *testing.T) {
+	// Setup main repo
+	mainRepo := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, mainRepo)
+
+	worktreePath, err := os.MkdirTemp("", "git-flow-worktree-list-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory for worktree: %v", err)
+	}
+	defer os.RemoveAll(worktreePath)
+	os.RemoveAll(worktreePath)
+
+	if _, err := testutil.RunGit(t, mainRepo, "worktree", "add", worktreePath, "-b", "worktree-branch"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	withGitRepo(t, worktreePath, func() {
+		worktrees, err := git.ListWorktrees()
+		if err != nil {
+			t.Fatalf("ListWorktrees failed: %v", err)
+		}
+		if len(worktrees) != 2 {
+			t.Fatalf("Expected 2 worktrees, got %+v", worktrees)
+		}
+		if worktrees[0].Branch != "main" || worktrees[0].Current {
+			t.Errorf("Expected the main working tree on 'main' not to be current, got %+v", worktrees[0])
+		}
+		if worktrees[1].Branch != "worktree-branch" || !worktrees[1].Current {
+			t.Errorf("Expected the linked worktree on 'worktree-branch' to be current, got %+v", worktrees[1])
+		}
+		if !strings.Contains(worktrees[1].GitDir, "worktrees") {
+			t.Errorf("Expected the linked worktree's git dir, got %q", worktrees[1].GitDir)
+		}
+
+		if path := git.WorktreeOfBranch("main"); path == "" {
+			t.Error("Expected 'main' to be checked out in another worktree")
+		}
+		if path := git.WorktreeOfBranch("worktree-branch"); path != "" {
+			t.Errorf("Expected the current worktree's branch not to be reported, got %q", path)
+		}
+	})
+}
+
+func TestOtherWorktreeStates(t *testing.T) {
+	// Setup main repo
+	mainRepo := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, mainRepo)
+
+	worktreePath, err := os.MkdirTemp("", "git-flow-worktree-other-state-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory for worktree: %v", err)
+	}
+	defer os.RemoveAll(worktreePath)
+	os.RemoveAll(worktreePath)
+
+	if _, err := testutil.RunGit(t, mainRepo, "worktree", "add", worktreePath, "-b", "worktree-branch"); err != nil {
+		t.Fatalf("Failed to create worktree: %v", err)
+	}
+
+	// Save state in the linked worktree
+	withGitRepo(t, worktreePath, func() {
+		state := &mergestate.MergeState{
+			Action:         "finish",
+			BranchType:     "feature",
+			BranchName:     "login",
+			FullBranchName: "feature/login",
+			ParentBranch:   "develop",
+		}
+		if err := mergestate.SaveMergeState(state); err != nil {
+			t.Fatalf("SaveMergeState failed in worktree: %v", err)
+		}
+
+		// The worktree's own state is not another worktree's
+		states, err := mergestate.OtherWorktreeStates()
+		if err != nil || len(states) != 0 {
+			t.Errorf("Expected no states of other worktrees, got %+v (%v)", states, err)
+		}
+	})
+
+	// The main working tree sees it as another worktree's operation
+	withGitRepo(t, mainRepo, func() {
+		states, err := mergestate.OtherWorktreeStates()
+		if err != nil {
+			t.Fatalf("OtherWorktreeStates failed: %v", err)
+		}
+		if len(states) != 1 {
+			t.Fatalf("Expected 1 state of another worktree, got %+v", states)
+		}
+		if !states[0].State.Touches("develop") || !states[0].State.Touches("feature/login") || states[0].State.Touches("main") {
+			t.Errorf("Expected the operation to touch 'feature/login' and 'develop' only, got %+v", states[0].State)
+		}
+	})
+}
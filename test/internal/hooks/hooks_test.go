@@ -359,6 +359,60 @@ echo "VERSION=$VERSION"
 	}
 }
 
+// TestPostHookReceivesFinishResult tests that post-hooks receive the finish result
+// as environment variables and as JSON.
+func TestPostHookReceivesFinishResult(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	script := `#!/bin/sh
+echo "TAG_NAME=$TAG_NAME"
+echo "MERGE_COMMIT=$MERGE_COMMIT"
+echo "UPDATED_BRANCHES=$UPDATED_BRANCHES"
+echo "UPDATED_BRANCH_STRATEGIES=$UPDATED_BRANCH_STRATEGIES"
+echo "JSON=$GITFLOW_RESULT_JSON"
+`
+	createHookScript(t, dir, "post-flow-release-finish", script)
+
+	gitDir := filepath.Join(dir, ".git")
+	ctx := hooks.HookContext{
+		BranchType: "release",
+		BranchName: "2.0.0",
+		FullBranch: "release/2.0.0",
+		BaseBranch: "main",
+		Origin:     "origin",
+		Finish: &hooks.FinishResult{
+			BranchType:  "release",
+			BranchName:  "2.0.0",
+			FullBranch:  "release/2.0.0",
+			BaseBranch:  "main",
+			MergeCommit: "abc123",
+			TagName:     "v2.0.0",
+			ExtraTags:   []string{},
+			UpdatedBranches: []hooks.UpdatedBranch{
+				{Name: "develop", Strategy: "merge", Commit: "def456"},
+				{Name: "staging", Strategy: "rebase", Commit: "789abc"},
+			},
+		},
+	}
+
+	result := hooks.RunPostHook(gitDir, "release", hooks.HookActionFinish, ctx)
+	if !result.Executed {
+		t.Fatal("Expected post-hook to execute")
+	}
+	for _, expected := range []string{
+		"TAG_NAME=v2.0.0",
+		"MERGE_COMMIT=abc123",
+		"UPDATED_BRANCHES=develop staging",
+		"UPDATED_BRANCH_STRATEGIES=develop:merge staging:rebase",
+		`"updatedBranches":[{"name":"develop","strategy":"merge","commit":"def456"}`,
+	} {
+		if !strings.Contains(result.Output, expected) {
+			t.Errorf("Expected %s in hook output, got: %s", expected, result.Output)
+		}
+	}
+}
+
 // TestHookDifferentActions tests hooks for different actions.
 func TestHookDifferentActions(t *testing.T) {
 	dir := testutil.SetupTestRepo(t)
@@ -398,6 +452,67 @@ exit 0
 	}
 }
 
+// TestGenericTopicHookFallback tests that {pre,post}-flow-topic-{action} runs for
+// branch types without a type-specific hook.
+func TestGenericTopicHookFallback(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	createHookScript(t, dir, "pre-flow-topic-start", `#!/bin/sh
+echo "Error: no $BRANCH_TYPE branches today" >&2
+exit 1
+`)
+
+	gitDir := filepath.Join(dir, ".git")
+	ctx := hooks.HookContext{
+		BranchType: "spike",
+		BranchName: "cache",
+		FullBranch: "spike/cache",
+		BaseBranch: "develop",
+		Origin:     "origin",
+	}
+
+	err := hooks.RunPreHook(gitDir, "spike", hooks.HookActionStart, ctx)
+	if err == nil {
+		t.Fatal("Expected error from the generic pre-hook, got nil")
+	}
+	if !strings.Contains(err.Error(), "pre-flow-topic-start") || !strings.Contains(err.Error(), "no spike branches today") {
+		t.Errorf("Expected error to name the generic hook and include its output, got: %v", err)
+	}
+
+	result := hooks.RunPostHook(gitDir, "spike", hooks.HookActionStart, ctx)
+	if result.Executed {
+		t.Error("Expected no post-hook to run")
+	}
+}
+
+// TestTypeSpecificHookOverridesGenericHook tests that a type-specific hook runs
+// instead of the generic topic hook.
+func TestTypeSpecificHookOverridesGenericHook(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	createHookScript(t, dir, "post-flow-topic-finish", `#!/bin/sh
+echo "generic"
+`)
+	createHookScript(t, dir, "post-flow-spike-finish", `#!/bin/sh
+echo "spike"
+`)
+
+	gitDir := filepath.Join(dir, ".git")
+	ctx := hooks.HookContext{BranchType: "spike", BranchName: "cache", FullBranch: "spike/cache"}
+
+	result := hooks.RunPostHook(gitDir, "spike", hooks.HookActionFinish, ctx)
+	if !result.Executed || strings.TrimSpace(result.Output) != "spike" {
+		t.Errorf("Expected the type-specific hook to run, got: %+v", result)
+	}
+
+	result = hooks.RunPostHook(gitDir, "feature", hooks.HookActionFinish, ctx)
+	if !result.Executed || strings.TrimSpace(result.Output) != "generic" {
+		t.Errorf("Expected the generic hook to run for feature, got: %+v", result)
+	}
+}
+
 // TestHooksWorkInGitWorktree tests that hooks execute correctly within a git worktree.
 // Git worktrees have a separate git directory structure where the worktree-specific
 // git dir is at /main-repo/.git/worktrees/<worktree-name>/.
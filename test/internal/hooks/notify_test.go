@@ -0,0 +1,164 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// createNotifier creates an executable notifier plugin in dir that writes its
+// event argument and payload to <name>.event and <name>.json in outDir.
+func createNotifier(t *testing.T, dir, name, outDir string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	script := `#!/bin/sh
+echo "$1" > "` + filepath.Join(outDir, name+".event") + `"
+cat > "` + filepath.Join(outDir, name+".json") + `"
+`
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to create notifier %s: %v", name, err)
+	}
+	return path
+}
+
+// readNotification reads the event name and payload received by a notifier.
+func readNotification(t *testing.T, outDir, name string) (string, map[string]interface{}) {
+	t.Helper()
+	event, err := os.ReadFile(filepath.Join(outDir, name+".event"))
+	if err != nil {
+		t.Fatalf("Expected notifier %s to run: %v", name, err)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, name+".json"))
+	if err != nil {
+		t.Fatalf("Expected notifier %s to receive a payload: %v", name, err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Expected JSON payload, got %q: %v", string(data), err)
+	}
+	return strings.TrimSpace(string(event)), payload
+}
+
+// TestNotifyRunsPluginsOnPath tests that notifiers on PATH receive the event.
+// Steps:
+// 1. Places a gitflow-notify-test plugin and an unrelated executable on PATH
+// 2. Sends a feature finish event
+// 3. Verifies only the plugin ran and received the event name and JSON payload
+func TestNotifyRunsPluginsOnPath(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	binDir := t.TempDir()
+	outDir := t.TempDir()
+	createNotifier(t, binDir, "gitflow-notify-test", outDir)
+	createNotifier(t, binDir, "other-tool", outDir)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx := hooks.HookContext{
+		BranchType: "feature",
+		BranchName: "my-feature",
+		FullBranch: "feature/my-feature",
+		BaseBranch: "develop",
+		Origin:     "origin",
+		Finish:     &hooks.FinishResult{BranchType: "feature", MergeStrategy: "merge", MergeCommit: "abc123"},
+	}
+	results := hooks.Notify(filepath.Join(dir, ".git"), hooks.BranchEvent(hooks.HookActionFinish, ctx))
+	if len(results) != 1 || results[0].ExitCode != 0 {
+		t.Fatalf("Expected one successful notifier, got %+v", results)
+	}
+
+	event, payload := readNotification(t, outDir, "gitflow-notify-test")
+	if event != "feature.finish" {
+		t.Errorf("Expected event 'feature.finish', got %q", event)
+	}
+	if payload["protocol"] != float64(hooks.NotifyProtocolVersion) {
+		t.Errorf("Expected protocol %d, got %v", hooks.NotifyProtocolVersion, payload["protocol"])
+	}
+	if payload["branch"] != "feature/my-feature" || payload["baseBranch"] != "develop" || payload["success"] != true {
+		t.Errorf("Unexpected payload: %v", payload)
+	}
+	if finish, ok := payload["finish"].(map[string]interface{}); !ok || finish["mergeCommit"] != "abc123" {
+		t.Errorf("Expected finish result in payload, got %v", payload["finish"])
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "other-tool.event")); err == nil {
+		t.Error("Expected executables without the gitflow-notify- prefix to be ignored")
+	}
+}
+
+// TestNotifyConfiguredPlugin tests that plugins listed in gitflow.notify.plugin run
+// and that gitflow.notify.discover=false skips PATH.
+// Steps:
+// 1. Places a plugin on PATH and another one inside the repository
+// 2. Lists the repository plugin in gitflow.notify.plugin and disables discovery
+// 3. Sends a config event
+// 4. Verifies only the configured plugin ran and received the config change
+func TestNotifyConfiguredPlugin(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	binDir := t.TempDir()
+	outDir := t.TempDir()
+	createNotifier(t, binDir, "gitflow-notify-path", outDir)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := os.MkdirAll(filepath.Join(dir, "scripts"), 0755); err != nil {
+		t.Fatalf("Failed to create scripts directory: %v", err)
+	}
+	createNotifier(t, filepath.Join(dir, "scripts"), "deploy-notifier", outDir)
+	testutil.RunGit(t, dir, "config", "gitflow.notify.plugin", "scripts/deploy-notifier")
+	testutil.RunGit(t, dir, "config", "gitflow.notify.discover", "false")
+
+	ctx := hooks.RepoHookContext{Change: "rename-topic", Branch: "story", OldBranch: "feature", ExitCode: 1}
+	results := hooks.Notify(filepath.Join(dir, ".git"), hooks.RepoEvent(hooks.HookActionConfig, ctx))
+	if len(results) != 1 {
+		t.Fatalf("Expected one notifier, got %+v", results)
+	}
+
+	event, payload := readNotification(t, outDir, "deploy-notifier")
+	if event != "config" {
+		t.Errorf("Expected event 'config', got %q", event)
+	}
+	if payload["success"] != false || payload["exitCode"] != float64(1) {
+		t.Errorf("Expected failed operation in payload, got %v", payload)
+	}
+	config, ok := payload["config"].(map[string]interface{})
+	if !ok || config["change"] != "rename-topic" || config["oldBranch"] != "feature" {
+		t.Errorf("Expected config change in payload, got %v", payload["config"])
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "gitflow-notify-path.event")); err == nil {
+		t.Error("Expected plugins on PATH to be skipped when discovery is disabled")
+	}
+}
+
+// TestNotifyFailureIsReported tests that a failing notifier is reported but does
+// not stop the other notifiers.
+func TestNotifyFailureIsReported(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	binDir := t.TempDir()
+	outDir := t.TempDir()
+	failing := filepath.Join(binDir, "gitflow-notify-a-failing")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho 'webhook unreachable'\nexit 3\n"), 0755); err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+	createNotifier(t, binDir, "gitflow-notify-b-working", outDir)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx := hooks.HookContext{BranchType: "feature", BranchName: "x", FullBranch: "feature/x"}
+	results := hooks.Notify(filepath.Join(dir, ".git"), hooks.BranchEvent(hooks.HookActionStart, ctx))
+	if len(results) != 2 {
+		t.Fatalf("Expected two notifiers, got %+v", results)
+	}
+	if results[0].ExitCode != 3 || !strings.Contains(results[0].Output, "webhook unreachable") {
+		t.Errorf("Expected failing notifier result, got %+v", results[0])
+	}
+	if event, _ := readNotification(t, outDir, "gitflow-notify-b-working"); event != "feature.start" {
+		t.Errorf("Expected event 'feature.start', got %q", event)
+	}
+}
@@ -0,0 +1,110 @@
+package hooks_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/hooks"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// TestRepoPreHookFails tests that a failing repository pre-hook returns an error.
+func TestRepoPreHookFails(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	script := `#!/bin/sh
+echo "main must stay protected" >&2
+exit 1
+`
+	createHookScript(t, dir, "pre-flow-config", script)
+
+	gitDir := filepath.Join(dir, ".git")
+	ctx := hooks.RepoHookContext{Change: "delete-base", Branch: "main", Origin: "origin"}
+
+	err := hooks.RunRepoPreHook(gitDir, hooks.HookActionConfig, ctx)
+	if err == nil {
+		t.Fatal("Expected error for failing pre-hook, got nil")
+	}
+	if !strings.Contains(err.Error(), "pre-flow-config") || !strings.Contains(err.Error(), "main must stay protected") {
+		t.Errorf("Expected error to name the hook and include its output, got: %v", err)
+	}
+}
+
+// TestRepoPreHookNonExistent tests that a missing repository hook is skipped.
+func TestRepoPreHookNonExistent(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	gitDir := filepath.Join(dir, ".git")
+	if err := hooks.RunRepoPreHook(gitDir, hooks.HookActionInit, hooks.RepoHookContext{}); err != nil {
+		t.Fatalf("Expected no error for missing hook, got: %v", err)
+	}
+}
+
+// TestWithRepoHooksRunsPreAndPost tests that repository hooks run around the
+// operation and receive the change as arguments and environment variables.
+func TestWithRepoHooksRunsPreAndPost(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	markerFile := filepath.Join(dir, "hook-markers.txt")
+	createHookScript(t, dir, "pre-flow-config", `#!/bin/sh
+echo "pre $1 $2 $3 $CONFIG_CHANGE $CONFIG_BRANCH $CONFIG_OLD_BRANCH $CONFIG_FILE" >> "`+markerFile+`"
+`)
+	createHookScript(t, dir, "post-flow-config", `#!/bin/sh
+echo "post-$EXIT_CODE" >> "`+markerFile+`"
+`)
+
+	gitDir := filepath.Join(dir, ".git")
+	ctx := hooks.RepoHookContext{
+		Change:     "rename-base",
+		Branch:     "trunk",
+		OldBranch:  "main",
+		Origin:     "origin",
+		ConfigFile: "/tmp/config.yml",
+	}
+
+	err := hooks.WithRepoHooks(gitDir, hooks.HookActionConfig, ctx, func() error {
+		return fmt.Errorf("write failed")
+	})
+	if err == nil || err.Error() != "write failed" {
+		t.Fatalf("Expected the operation error, got: %v", err)
+	}
+
+	content, err := os.ReadFile(markerFile)
+	if err != nil {
+		t.Fatalf("Failed to read marker file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	expected := []string{
+		"pre rename-base trunk main rename-base trunk main /tmp/config.yml",
+		"post-1",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines in marker file, got %d: %v", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i+1, expected[i], lines[i])
+		}
+	}
+}
+
+// TestBuildRepoHookArgs tests the positional arguments of repository hooks.
+func TestBuildRepoHookArgs(t *testing.T) {
+	ctx := hooks.RepoHookContext{Change: "add-base", Branch: "staging", Origin: "upstream"}
+
+	args := hooks.BuildRepoHookArgs(hooks.HookActionInit, ctx)
+	if len(args) != 1 || args[0] != "upstream" {
+		t.Errorf("Expected init args [upstream], got %v", args)
+	}
+
+	args = hooks.BuildRepoHookArgs(hooks.HookActionConfig, ctx)
+	if len(args) != 3 || args[0] != "add-base" || args[1] != "staging" || args[2] != "" {
+		t.Errorf("Expected config args [add-base staging ''], got %v", args)
+	}
+}
@@ -0,0 +1,82 @@
+package journal_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/journal"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// withGitRepo changes to the provided directory, runs the testFunc, and changes back afterwards
+func withGitRepo(t *testing.T, dir string, testFunc func()) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(oldDir); err != nil {
+			t.Fatalf("Failed to change back to original directory: %v", err)
+		}
+	}()
+
+	testFunc()
+}
+
+func TestPendingUpdatesWithoutJournal(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		pending, err := journal.PendingUpdates()
+		if err != nil {
+			t.Fatalf("PendingUpdates failed: %v", err)
+		}
+		if len(pending) != 0 {
+			t.Errorf("Expected no pending updates, got %v", pending)
+		}
+	})
+}
+
+func TestPendingUpdatesSkipsReconciledUpdates(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	staging := journal.Update{Branch: "staging", Parent: "main"}
+	develop := journal.Update{Branch: "develop", Parent: "main"}
+	qa := journal.Update{Branch: "qa", Parent: "main"}
+
+	withGitRepo(t, dir, func() {
+		entries := []journal.Entry{
+			{Operation: journal.OperationFinish, Branch: "hotfix/1.0.1", SkippedUpdates: []journal.Update{staging, develop}},
+			{Operation: journal.OperationFinish, Branch: "hotfix/1.0.2", SkippedUpdates: []journal.Update{staging}},
+			{Operation: journal.OperationSyncBases, ReconciledUpdates: []journal.Update{develop}},
+			{Operation: journal.OperationFinish, Branch: "hotfix/1.0.3", SkippedUpdates: []journal.Update{qa}},
+		}
+		for _, entry := range entries {
+			if err := journal.Append(entry); err != nil {
+				t.Fatalf("Append failed: %v", err)
+			}
+		}
+
+		loaded, err := journal.Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(loaded) != len(entries) || loaded[0].Time.IsZero() {
+			t.Errorf("Expected %d time-stamped entries, got %v", len(entries), loaded)
+		}
+
+		pending, err := journal.PendingUpdates()
+		if err != nil {
+			t.Fatalf("PendingUpdates failed: %v", err)
+		}
+		if expected := []journal.Update{staging, qa}; !reflect.DeepEqual(pending, expected) {
+			t.Errorf("Expected pending updates %v, got %v", expected, pending)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package mergestate_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/errors"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+	"github.com/gittower/git-flow-next/test/testutil"
+)
+
+// withGitRepo changes to the provided directory, runs the testFunc, and changes back afterwards
+func withGitRepo(t *testing.T, dir string, testFunc func()) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to test directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(oldDir); err != nil {
+			t.Fatalf("Failed to change back to original directory: %v", err)
+		}
+	}()
+
+	testFunc()
+}
+
+// stateDir returns the merge state directory of the test repository
+func stateDir(dir string) string {
+	return filepath.Join(dir, ".git", "gitflow", "state")
+}
+
+func TestSaveMergeStateKeepsPreviousStateAsBackup(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		first := &mergestate.MergeState{Action: "finish", FullBranchName: "feature/a", CurrentStep: "merge"}
+		second := &mergestate.MergeState{Action: "finish", FullBranchName: "feature/a", CurrentStep: "create_tag"}
+
+		if err := mergestate.SaveMergeState(first); err != nil {
+			t.Fatalf("Failed to save first state: %v", err)
+		}
+		if err := mergestate.SaveMergeState(second); err != nil {
+			t.Fatalf("Failed to save second state: %v", err)
+		}
+
+		state, err := mergestate.LoadMergeState()
+		if err != nil || state == nil {
+			t.Fatalf("Failed to load state: %v", err)
+		}
+		if state.CurrentStep != "create_tag" {
+			t.Errorf("Expected current state at step 'create_tag', got '%s'", state.CurrentStep)
+		}
+
+		backup, err := mergestate.LoadBackupMergeState()
+		if err != nil || backup == nil {
+			t.Fatalf("Failed to load backup: %v", err)
+		}
+		if backup.CurrentStep != "merge" {
+			t.Errorf("Expected backup at step 'merge', got '%s'", backup.CurrentStep)
+		}
+	})
+}
+
+func TestSaveMergeStateLeavesNoTemporaryFiles(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		for _, step := range []string{"merge", "create_tag", "update_children"} {
+			if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: step}); err != nil {
+				t.Fatalf("Failed to save state: %v", err)
+			}
+		}
+	})
+
+	entries, err := os.ReadDir(stateDir(dir))
+	if err != nil {
+		t.Fatalf("Failed to read state directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("Expected no temporary files, found '%s'", entry.Name())
+		}
+	}
+}
+
+func TestLoadMergeStateReportsTruncatedFile(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "merge"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "update_children"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+
+		// Simulate a partial write of the current state
+		statePath := filepath.Join(stateDir(dir), "merge.json")
+		data, err := os.ReadFile(statePath)
+		if err != nil {
+			t.Fatalf("Failed to read state file: %v", err)
+		}
+		if err := os.WriteFile(statePath, data[:len(data)/2], 0644); err != nil {
+			t.Fatalf("Failed to truncate state file: %v", err)
+		}
+
+		// The backup is one step behind and must not be used in its place
+		state, err := mergestate.LoadMergeState()
+		if state != nil {
+			t.Fatalf("Expected no state to be loaded, got step '%s'", state.CurrentStep)
+		}
+		unreadable, ok := err.(*errors.UnreadableStateError)
+		if !ok {
+			t.Fatalf("Expected UnreadableStateError, got: %v", err)
+		}
+		if unreadable.BackupStep != "merge" {
+			t.Errorf("Expected the backup step 'merge' to be reported, got '%s'", unreadable.BackupStep)
+		}
+		if !strings.Contains(unreadable.Hint(), "git flow state repair") {
+			t.Errorf("Expected the hint to point to state repair, got: %s", unreadable.Hint())
+		}
+		if !mergestate.IsMergeInProgress() {
+			t.Error("Expected merge to still be in progress")
+		}
+	})
+}
+
+func TestLoadMergeStateTruncatedWithoutBackup(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	if err := os.MkdirAll(stateDir(dir), 0755); err != nil {
+		t.Fatalf("Failed to create state directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir(dir), "merge.json"), []byte(`{"action":"fin`), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	withGitRepo(t, dir, func() {
+		_, err := mergestate.LoadMergeState()
+		unreadable, ok := err.(*errors.UnreadableStateError)
+		if !ok {
+			t.Fatalf("Expected UnreadableStateError for truncated state without backup, got: %v", err)
+		}
+		if unreadable.BackupStep != "" {
+			t.Errorf("Expected no backup step, got '%s'", unreadable.BackupStep)
+		}
+	})
+}
+
+func TestSaveMergeStateIgnoresInterruptedWrite(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "merge"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+
+		// Simulate a crash after the temporary file was partially written but before the rename
+		leftover := filepath.Join(stateDir(dir), "merge.json.tmp-123")
+		if err := os.WriteFile(leftover, []byte(`{"action":"finish","currentSt`), 0644); err != nil {
+			t.Fatalf("Failed to write leftover temp file: %v", err)
+		}
+
+		state, err := mergestate.LoadMergeState()
+		if err != nil || state == nil {
+			t.Fatalf("Failed to load state: %v", err)
+		}
+		if state.CurrentStep != "merge" {
+			t.Errorf("Expected intact state at step 'merge', got '%s'", state.CurrentStep)
+		}
+	})
+}
+
+func TestSaveMergeStateDoesNotBackUpCorruptState(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "merge"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "create_tag"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+
+		// Corrupt the current state, then save again
+		if err := os.WriteFile(filepath.Join(stateDir(dir), "merge.json"), []byte(`{"act`), 0644); err != nil {
+			t.Fatalf("Failed to corrupt state file: %v", err)
+		}
+		if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: "update_children"}); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+
+		backup, err := mergestate.LoadBackupMergeState()
+		if err != nil || backup == nil {
+			t.Fatalf("Failed to load backup: %v", err)
+		}
+		if backup.CurrentStep != "merge" {
+			t.Errorf("Expected the last intact backup at step 'merge', got '%s'", backup.CurrentStep)
+		}
+	})
+}
+
+func TestClearMergeStateRemovesBackup(t *testing.T) {
+	dir := testutil.SetupTestRepo(t)
+	defer testutil.CleanupTestRepo(t, dir)
+
+	withGitRepo(t, dir, func() {
+		for _, step := range []string{"merge", "create_tag"} {
+			if err := mergestate.SaveMergeState(&mergestate.MergeState{Action: "finish", CurrentStep: step}); err != nil {
+				t.Fatalf("Failed to save state: %v", err)
+			}
+		}
+		if err := mergestate.ClearMergeState(); err != nil {
+			t.Fatalf("Failed to clear state: %v", err)
+		}
+		if mergestate.IsMergeInProgress() {
+			t.Error("Expected no merge in progress after clear")
+		}
+	})
+
+	for _, name := range []string{"merge.json", "merge.json.bak"} {
+		if _, err := os.Stat(filepath.Join(stateDir(dir), name)); !os.IsNotExist(err) {
+			t.Errorf("Expected '%s' to be removed", name)
+		}
+	}
+}
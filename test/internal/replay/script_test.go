@@ -0,0 +1,63 @@
+package replay_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/replay"
+)
+
+func TestParseScript(t *testing.T) {
+	script, err := replay.Parse(strings.NewReader(`# comment
+repo develop
+
+write 'notes file.txt' "line one\nline \"two\""
+flow feature finish login --push
+expect exit 3
+expect output "rejected"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(script.Steps) != 5 {
+		t.Fatalf("Expected 5 steps, got %d", len(script.Steps))
+	}
+
+	write := script.Steps[1]
+	if write.Line != 4 || !reflect.DeepEqual(write.Args, []string{"notes file.txt", "line one\nline \"two\""}) {
+		t.Errorf("Expected the quoted arguments on line 4, got line %d: %q", write.Line, write.Args)
+	}
+	finish := script.Steps[2]
+	if finish.Directive != "flow" || !finish.Checked {
+		t.Errorf("Expected the finish to be checked by the following expect exit, got %+v", finish)
+	}
+	if script.Steps[1].Checked {
+		t.Error("Expected the write not to be checked")
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	tests := []struct {
+		script string
+		error  string
+	}{
+		{"flow init\n", "line 1: the script must start with 'repo'"},
+		{"repo\nrepo\n", "line 2: the script must start with 'repo', and only once"},
+		{"repo\nrebase develop\n", "line 2: unknown directive 'rebase'"},
+		{"repo\nwrite a.txt\n", "line 2: wrong number of arguments for 'write'"},
+		{"repo\nwrite a.txt 'open\n", "line 2: unterminated single quote"},
+		{"repo\nremote ../up\n", "line 2: invalid remote name '../up'"},
+		{"repo\nremote origin flaky\n", "line 2: unknown remote state 'flaky'"},
+		{"repo\ncommit x\nexpect exit 0\n", "line 3: 'expect' must follow a git or flow command"},
+		{"repo\nflow init\nexpect output x\nexpect exit 0\n", "line 4: 'expect exit' must directly follow the command"},
+		{"repo\nflow init\nexpect exit one\n", "line 3: invalid exit code 'one'"},
+		{"# nothing\n", "the script has no directives"},
+	}
+	for _, test := range tests {
+		_, err := replay.Parse(strings.NewReader(test.script))
+		if err == nil || !strings.Contains(err.Error(), test.error) {
+			t.Errorf("Expected %q for script %q, got: %v", test.error, test.script, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package selfupdate_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/selfupdate"
+)
+
+// TestCompareVersions tests version ordering, including preview releases.
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.0.0", "1.0.0", 0},
+		{"v1.1.0", "1.0.0", 1},
+		{"1.0.0", "v1.0.1", -1},
+		{"v1.10.0", "v1.9.3", 1},
+		{"v2.0.0", "v2.0.0-rc.1", 1},
+		{"v2.0.0-rc.2", "v2.0.0-rc.10", -1},
+		{"v2.0.0-beta.1", "v2.0.0-alpha.3", 1},
+		{"v1.0", "v1.0.0", -1},
+	}
+
+	for _, test := range tests {
+		if got := selfupdate.CompareVersions(test.a, test.b); got != test.expected {
+			t.Errorf("CompareVersions(%q, %q) = %d, expected %d", test.a, test.b, got, test.expected)
+		}
+	}
+}
+
+// TestVerifyChecksum tests that downloads are checked against a shasum listing.
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("release archive")
+	sum := sha256.Sum256(data)
+	checksums := []byte(fmt.Sprintf("%s  git-flow-next-v1.1.0-linux-amd64.tar.gz\n0000  other.zip\n", hex.EncodeToString(sum[:])))
+
+	if err := selfupdate.VerifyChecksum(checksums, "git-flow-next-v1.1.0-linux-amd64.tar.gz", data); err != nil {
+		t.Errorf("Expected checksum to match: %v", err)
+	}
+	if err := selfupdate.VerifyChecksum(checksums, "git-flow-next-v1.1.0-linux-amd64.tar.gz", []byte("tampered")); err == nil {
+		t.Error("Expected a tampered archive to fail")
+	}
+	if err := selfupdate.VerifyChecksum(checksums, "git-flow-next-v1.1.0-darwin-arm64.tar.gz", data); err == nil {
+		t.Error("Expected an unlisted archive to fail")
+	}
+}
+
+// TestVerifySignature tests the Ed25519 signature check of the checksums file with
+// raw and base64-encoded signatures.
+func TestVerifySignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+	checksums := []byte("abc  git-flow-next-v1.1.0-linux-amd64.tar.gz\n")
+	signature := ed25519.Sign(privateKey, checksums)
+
+	if err := selfupdate.VerifySignature(checksums, signature, encodedKey); err != nil {
+		t.Errorf("Expected raw signature to verify: %v", err)
+	}
+	encodedSignature := []byte(base64.StdEncoding.EncodeToString(signature) + "\n")
+	if err := selfupdate.VerifySignature(checksums, encodedSignature, encodedKey); err != nil {
+		t.Errorf("Expected base64 signature to verify: %v", err)
+	}
+	if err := selfupdate.VerifySignature([]byte("tampered"), signature, encodedKey); err == nil {
+		t.Error("Expected tampered checksums to fail")
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if err := selfupdate.VerifySignature(checksums, signature, base64.StdEncoding.EncodeToString(otherKey)); err == nil {
+		t.Error("Expected a signature of another key to fail")
+	}
+}
+
+// TestExtractBinary tests that the binary is found in tar.gz and zip archives.
+func TestExtractBinary(t *testing.T) {
+	content := []byte("#!/bin/sh\necho new\n")
+
+	var tarGz bytes.Buffer
+	gz := gzip.NewWriter(&tarGz)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "git-flow-v1.1.0-linux-amd64", Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+
+	data, err := selfupdate.ExtractBinary(tarGz.Bytes(), "git-flow-next-v1.1.0-linux-amd64.tar.gz", "git-flow-v1.1.0-linux-amd64")
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("Expected binary from tar.gz, got %q: %v", data, err)
+	}
+	if _, err := selfupdate.ExtractBinary(tarGz.Bytes(), "git-flow-next-v1.1.0-linux-amd64.tar.gz", "git-flow-v1.1.0-linux-arm64"); err == nil {
+		t.Error("Expected a missing binary to fail")
+	}
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	w, _ := zw.Create("git-flow-v1.1.0-windows-amd64.exe")
+	w.Write(content)
+	zw.Close()
+
+	data, err = selfupdate.ExtractBinary(zipped.Bytes(), "git-flow-next-v1.1.0-windows-amd64.zip", "git-flow-v1.1.0-windows-amd64.exe")
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("Expected binary from zip, got %q: %v", data, err)
+	}
+}
+
+// TestPackageManager tests detection of package manager installations.
+func TestPackageManager(t *testing.T) {
+	tests := []struct {
+		installMethod, path, expected string
+	}{
+		{"", "/usr/local/bin/git-flow", ""},
+		{"", "/opt/homebrew/Cellar/git-flow-next/1.0.0/bin/git-flow", "homebrew"},
+		{"", "/home/linuxbrew/.linuxbrew/bin/git-flow", "homebrew"},
+		{"", `C:\Users\me\scoop\apps\git-flow-next\current\git-flow.exe`, "scoop"},
+		{"apt", "/usr/bin/git-flow", "apt"},
+		{"", "/usr/bin/git-flow", selfupdate.SystemPackageManager},
+		{"", "/snap/git-flow-next/12/bin/git-flow", selfupdate.SystemPackageManager},
+		{"", "/opt/git-flow/git-flow", ""},
+		{"", "/home/me/bin/git-flow", ""},
+	}
+
+	for _, test := range tests {
+		if got := selfupdate.PackageManager(test.installMethod, test.path); got != test.expected {
+			t.Errorf("PackageManager(%q, %q) = %q, expected %q", test.installMethod, test.path, got, test.expected)
+		}
+	}
+}
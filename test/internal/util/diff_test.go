@@ -0,0 +1,53 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   []string
+		after    []string
+		expected string
+	}{
+		{
+			name:     "equal",
+			before:   []string{"a", "b"},
+			after:    []string{"a", "b"},
+			expected: "",
+		},
+		{
+			name:   "changed line with context",
+			before: []string{"a", "b", "c", "d", "e"},
+			after:  []string{"a", "b", "x", "d", "e"},
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n a\n b\n-c\n+x\n d\n e\n",
+		},
+		{
+			name:   "separate hunks",
+			before: []string{"a", "1", "2", "3", "4", "5", "6", "7", "8", "b"},
+			after:  []string{"A", "1", "2", "3", "4", "5", "6", "7", "8", "B"},
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+				"@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name:   "into empty",
+			before: nil,
+			after:  []string{"a"},
+			expected: "--- old\n+++ new\n" +
+				"@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := util.UnifiedDiff("old", "new", tt.before, tt.after); got != tt.expected {
+				t.Errorf("UnifiedDiff() =\n%s\nexpected:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -153,3 +153,79 @@ func TestExpandMessagePlaceholdersEmpty(t *testing.T) {
 		t.Errorf("ExpandMessagePlaceholders(\"\", ...) = %q, want empty string", result)
 	}
 }
+
+func TestExpandTagPlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "fixed name",
+			template: "latest",
+			expected: "latest",
+		},
+		{
+			name:     "version placeholder",
+			template: "deploy/%v",
+			expected: "deploy/1.2.0",
+		},
+		{
+			name:     "tag placeholder",
+			template: "%t-stable",
+			expected: "v1.2.0-stable",
+		},
+		{
+			name:     "parent placeholder",
+			template: "%p-latest",
+			expected: "main-latest",
+		},
+		{
+			name:     "escaped percent",
+			template: "%%v-%v",
+			expected: "%v-1.2.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := util.ExpandTagPlaceholders(tt.template, "1.2.0", "v1.2.0", "main")
+			if result != tt.expected {
+				t.Errorf("ExpandTagPlaceholders(%q) = %q, want %q", tt.template, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandPrereleasePlaceholders(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "default format",
+			format:   "%t-%c.%n",
+			expected: "v1.3.0-beta.4",
+		},
+		{
+			name:     "version and date",
+			format:   "%v-%c.%d.%n",
+			expected: "1.3.0-beta.20240315.4",
+		},
+		{
+			name:     "escaped percent",
+			format:   "%%n-%n",
+			expected: "%n-4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := util.ExpandPrereleasePlaceholders(tt.format, "1.3.0", "v1.3.0", "beta", "4", "20240315")
+			if result != tt.expected {
+				t.Errorf("ExpandPrereleasePlaceholders(%q) = %q, want %q", tt.format, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Add Ümlaut Support", "add-umlaut-support"},
+		{"  Fix   crash on   login ", "fix-crash-on-login"},
+		{"Straße & Café", "strasse-cafe"},
+		{"Decomposed u\u0308ber", "decomposed-uber"},
+		{"1.2.0", "1.2.0"},
+		{"team/Login Page", "team/login-page"},
+		{"what?*[weird]~^:name", "what-weird-name"},
+		{"..hidden..dots..", "hidden.dots"},
+		{"ends.lock", "ends"},
+		{"snake_case stays", "snake_case-stays"},
+		{"!!!", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := util.Slugify(test.input); got != test.expected {
+				t.Errorf("Slugify(%q) = %q, expected %q", test.input, got, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package util_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+func TestClosestMatches(t *testing.T) {
+	candidates := []string{"feature/login", "feature/logout", "feature/signup", "release/1.0.0", "main"}
+
+	tests := []struct {
+		name     string
+		target   string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "typo",
+			target:   "feature/lgoin",
+			limit:    3,
+			expected: []string{"feature/login", "feature/logout"},
+		},
+		{
+			name:     "partial name",
+			target:   "signup",
+			limit:    3,
+			expected: []string{"feature/signup"},
+		},
+		{
+			name:     "limit applies",
+			target:   "feature/log",
+			limit:    1,
+			expected: []string{"feature/login"},
+		},
+		{
+			name:     "no match",
+			target:   "hotfix/urgent",
+			limit:    3,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := util.ClosestMatches(tt.target, candidates, tt.limit)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ClosestMatches(%q) = %v, expected %v", tt.target, result, tt.expected)
+			}
+		})
+	}
+}
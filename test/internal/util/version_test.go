@@ -0,0 +1,54 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/gittower/git-flow-next/internal/util"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2", "1.2.0", true},
+		{"10.0.12", "10.0.12", true},
+		{"1", "", false},
+		{"1.2.3.4", "", false},
+		{"1.2.3-beta.1", "", false},
+		{"01.2.3", "", false},
+		{"v1.2.3", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			version, ok := util.ParseVersion(test.input)
+			if ok != test.ok {
+				t.Fatalf("ParseVersion(%q) ok = %v, expected %v", test.input, ok, test.ok)
+			}
+			if ok && version.String() != test.expected {
+				t.Errorf("ParseVersion(%q) = %s, expected %s", test.input, version, test.expected)
+			}
+		})
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	version := util.Version{Major: 1, Minor: 2, Patch: 3}
+	tests := map[string]string{
+		util.VersionMajor: "2.0.0",
+		util.VersionMinor: "1.3.0",
+		util.VersionPatch: "1.2.4",
+	}
+
+	for part, expected := range tests {
+		if got := version.Bump(part).String(); got != expected {
+			t.Errorf("Bump(%q) = %s, expected %s", part, got, expected)
+		}
+	}
+	if !version.Less(version.Bump(util.VersionPatch)) {
+		t.Errorf("Expected %s to be less than its patch bump", version)
+	}
+}
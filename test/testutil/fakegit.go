@@ -0,0 +1,1213 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gittower/git-flow-next/internal/commands"
+	"github.com/gittower/git-flow-next/internal/events"
+	"github.com/gittower/git-flow-next/internal/git"
+	"github.com/gittower/git-flow-next/internal/mergestate"
+)
+
+// FakeGit is an in-memory implementation of commands.Git and commands.Remote.
+// It models a commit graph with local branches, tags and remote-tracking
+// branches, so start, finish, update and publish run against it without a
+// repository, and records the operations that change it or talk to a remote.
+// Errors set with Fail are returned by the named method, so error paths such
+// as network or permission failures can be tested deterministically.
+type FakeGit struct {
+	GitDir  string
+	Current string
+	// Branches are the local branches, Heads the commit each one points to
+	Branches []string
+	Heads    map[string]string
+	// Commits holds every commit by its ID
+	Commits map[string]*FakeCommit
+	Tags    map[string]string
+	// Remotes maps a remote name to its branches, RemoteHeads a
+	// remote-tracking branch such as "origin/develop" to its commit
+	Remotes     map[string][]string
+	RemoteHeads map[string]string
+	// Bases, Descriptions and Upstreams hold the stored base branch, the
+	// description and the upstream ("origin/feature/x") of a branch
+	Bases        map[string]string
+	Descriptions map[string]string
+	Upstreams    map[string]string
+	// Dirty reports uncommitted changes in the working tree
+	Dirty bool
+	// Calls records the operations that changed the repository, e.g.
+	// "DeleteBranch feature/x force"
+	Calls []string
+
+	conflicts map[string][]string
+	stopped   *stoppedOperation
+	errors    map[string]error
+}
+
+// FakeCommit is a commit of a FakeGit. Change identifies the change it
+// makes; a rebased or cherry-picked copy keeps the Change of its original.
+type FakeCommit struct {
+	ID      string
+	Parents []string
+	Subject string
+	Change  string
+}
+
+// stoppedOperation is a merge or rebase stopped on conflicts
+type stoppedOperation struct {
+	kind     string
+	branch   string
+	origHead string
+	source   string
+	squash   bool
+	unmerged []string
+	// replay are the commits a stopped rebase still applies onto source
+	replay []string
+}
+
+var (
+	_ commands.Git         = (*FakeGit)(nil)
+	_ commands.Remote      = (*FakeGit)(nil)
+	_ commands.ConfigStore = FakeConfig(nil)
+	_ commands.Prompter    = (*FakePrompter)(nil)
+	_ commands.Clock       = FakeClock{}
+)
+
+// NewFakeGit returns a fake repository with the given local branches, all on
+// one initial commit, checked out on the first one
+func NewFakeGit(branches ...string) *FakeGit {
+	fake := &FakeGit{
+		Heads:        make(map[string]string),
+		Commits:      make(map[string]*FakeCommit),
+		Tags:         make(map[string]string),
+		Remotes:      make(map[string][]string),
+		RemoteHeads:  make(map[string]string),
+		Bases:        make(map[string]string),
+		Descriptions: make(map[string]string),
+		Upstreams:    make(map[string]string),
+	}
+	root := fake.newCommit("Initial commit")
+	for _, branch := range branches {
+		fake.Branches = append(fake.Branches, branch)
+		fake.Heads[branch] = root
+	}
+	if len(branches) > 0 {
+		fake.Current = branches[0]
+	}
+	return fake
+}
+
+// Fail makes method (e.g. "DeleteRemoteBranch") return err from now on; a nil
+// err makes it succeed again
+func (g *FakeGit) Fail(method string, err error) {
+	if g.errors == nil {
+		g.errors = make(map[string]error)
+	}
+	g.errors[method] = err
+}
+
+// Conflict makes merging ref, or rebasing onto it, stop with files unmerged
+func (g *FakeGit) Conflict(ref string, files ...string) {
+	if g.conflicts == nil {
+		g.conflicts = make(map[string][]string)
+	}
+	g.conflicts[ref] = files
+}
+
+// HasBranch reports whether the local branch exists
+func (g *FakeGit) HasBranch(branch string) bool {
+	return indexOf(g.Branches, branch) >= 0
+}
+
+// AddCommit adds a commit with subject on top of branch and returns its ID
+func (g *FakeGit) AddCommit(branch, subject string) string {
+	id := g.newCommit(subject, g.Heads[branch])
+	g.Heads[branch] = id
+	return id
+}
+
+// SetRemoteBranch makes branch exist on remote at commit, as if it had been
+// fetched
+func (g *FakeGit) SetRemoteBranch(remote, branch, commit string) {
+	if indexOf(g.Remotes[remote], branch) < 0 {
+		g.Remotes[remote] = append(g.Remotes[remote], branch)
+	}
+	g.RemoteHeads[remote+"/"+branch] = commit
+}
+
+// Subjects returns the subjects of the commits reachable from ref, newest first
+func (g *FakeGit) Subjects(ref string) []string {
+	id, err := g.resolve(ref)
+	if err != nil {
+		return nil
+	}
+	commits := g.commitsBetween("", id)
+	subjects := make([]string, len(commits))
+	for i, commit := range commits {
+		subjects[len(commits)-1-i] = g.Commits[commit].Subject
+	}
+	return subjects
+}
+
+func (g *FakeGit) newCommit(subject string, parents ...string) string {
+	id := fmt.Sprintf("%040x", len(g.Commits)+1)
+	g.Commits[id] = &FakeCommit{ID: id, Parents: parents, Subject: subject, Change: id}
+	return id
+}
+
+// resolve returns the commit ref points to
+func (g *FakeGit) resolve(ref string) (string, error) {
+	ref = strings.TrimSuffix(ref, "^{commit}")
+	if ref == "HEAD" {
+		ref = g.Current
+	}
+	candidates := []string{
+		g.Heads[strings.TrimPrefix(ref, "refs/heads/")],
+		g.Tags[strings.TrimPrefix(ref, "refs/tags/")],
+		g.RemoteHeads[strings.TrimPrefix(ref, "refs/remotes/")],
+	}
+	for _, id := range candidates {
+		if id != "" {
+			return id, nil
+		}
+	}
+	if _, ok := g.Commits[ref]; ok {
+		return ref, nil
+	}
+	return "", fmt.Errorf("fatal: ambiguous argument '%s': unknown revision", ref)
+}
+
+// reachable returns the commits reachable from id
+func (g *FakeGit) reachable(id string) map[string]bool {
+	seen := make(map[string]bool)
+	pending := []string{id}
+	for len(pending) > 0 {
+		commit := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if commit == "" || seen[commit] {
+			continue
+		}
+		seen[commit] = true
+		pending = append(pending, g.Commits[commit].Parents...)
+	}
+	return seen
+}
+
+// commitsBetween returns the commits reachable from tip but not from base,
+// parents before their children
+func (g *FakeGit) commitsBetween(base, tip string) []string {
+	excluded := map[string]bool{}
+	if base != "" {
+		excluded = g.reachable(base)
+	}
+	var commits []string
+	visited := make(map[string]bool)
+	var visit func(string)
+	visit = func(commit string) {
+		if commit == "" || visited[commit] || excluded[commit] {
+			return
+		}
+		visited[commit] = true
+		for _, parent := range g.Commits[commit].Parents {
+			visit(parent)
+		}
+		commits = append(commits, commit)
+	}
+	visit(tip)
+	return commits
+}
+
+// rangeOf resolves base and tip and returns the commits between them
+func (g *FakeGit) rangeOf(base, tip string) ([]string, error) {
+	baseID, err := g.resolve(base)
+	if err != nil {
+		return nil, err
+	}
+	tipID, err := g.resolve(tip)
+	if err != nil {
+		return nil, err
+	}
+	return g.commitsBetween(baseID, tipID), nil
+}
+
+// applied reports whether target contains the change of commit
+func (g *FakeGit) applied(target, commit string) bool {
+	for id := range g.reachable(target) {
+		if g.Commits[id].Change == g.Commits[commit].Change {
+			return true
+		}
+	}
+	return false
+}
+
+// replay copies the non-merge commits onto onto and returns the new tip
+func (g *FakeGit) replay(onto string, commits []string) string {
+	tip := onto
+	for _, commit := range commits {
+		original := g.Commits[commit]
+		if len(original.Parents) > 1 || g.applied(tip, commit) {
+			continue
+		}
+		tip = g.newCommit(original.Subject, tip)
+		g.Commits[tip].Change = original.Change
+	}
+	return tip
+}
+
+func (g *FakeGit) record(format string, args ...interface{}) {
+	g.Calls = append(g.Calls, fmt.Sprintf(format, args...))
+}
+
+// Repository
+
+func (g *FakeGit) GetGitDir() (string, error) {
+	if err := g.errors["GetGitDir"]; err != nil {
+		return "", err
+	}
+	return g.GitDir, nil
+}
+
+func (g *FakeGit) GetTopLevelDir() (string, error) {
+	return g.GitDir, nil
+}
+
+func (g *FakeGit) GetGitVersion() (git.GitVersion, error) {
+	return git.GitVersion{Major: 2, Minor: 45, Patch: 0, Raw: "2.45.0"}, nil
+}
+
+func (g *FakeGit) ColorEnabled(slot string, stdoutIsTTY bool) bool {
+	return false
+}
+
+// EditMessage returns template unchanged, as an editor closed without changes
+func (g *FakeGit) EditMessage(name, template string) (string, error) {
+	if err := g.errors["EditMessage"]; err != nil {
+		return "", err
+	}
+	return template, nil
+}
+
+// Branches and worktrees
+
+func (g *FakeGit) GetCurrentBranch() (string, error) {
+	if err := g.errors["GetCurrentBranch"]; err != nil {
+		return "", err
+	}
+	return g.Current, nil
+}
+
+func (g *FakeGit) IsDetachedHead() bool {
+	return g.Current == ""
+}
+
+func (g *FakeGit) BranchExists(branch string) error {
+	if err := g.errors["BranchExists"]; err != nil {
+		return err
+	}
+	if !g.HasBranch(branch) {
+		return fmt.Errorf("branch '%s' does not exist", branch)
+	}
+	return nil
+}
+
+func (g *FakeGit) BranchOrCommitExists(ref string) error {
+	_, err := g.resolve(ref)
+	return err
+}
+
+func (g *FakeGit) ListBranches() ([]string, error) {
+	if err := g.errors["ListBranches"]; err != nil {
+		return nil, err
+	}
+	branches := append([]string(nil), g.Branches...)
+	sort.Strings(branches)
+	return branches, nil
+}
+
+func (g *FakeGit) Checkout(branch string) error {
+	if err := g.errors["Checkout"]; err != nil {
+		return err
+	}
+	if !g.HasBranch(branch) {
+		return fmt.Errorf("pathspec '%s' did not match any file(s) known to git", branch)
+	}
+	g.Current = branch
+	g.record("Checkout %s", branch)
+	return nil
+}
+
+func (g *FakeGit) CreateBranch(name string, startPoint string) error {
+	return g.createBranch("CreateBranch", name, startPoint)
+}
+
+func (g *FakeGit) CreateBranchNoTrack(name string, startPoint string) error {
+	return g.createBranch("CreateBranchNoTrack", name, startPoint)
+}
+
+func (g *FakeGit) CreateTrackingBranch(localBranch, remote, remoteBranch string) error {
+	if err := g.createBranch("CreateTrackingBranch", localBranch, remote+"/"+remoteBranch); err != nil {
+		return err
+	}
+	g.Upstreams[localBranch] = remote + "/" + remoteBranch
+	return nil
+}
+
+// createBranch creates name at startPoint and checks it out
+func (g *FakeGit) createBranch(method, name, startPoint string) error {
+	if err := g.errors[method]; err != nil {
+		return err
+	}
+	if g.HasBranch(name) {
+		return fmt.Errorf("a branch named '%s' already exists", name)
+	}
+	if startPoint == "" {
+		startPoint = "HEAD"
+	}
+	commit, err := g.resolve(startPoint)
+	if err != nil {
+		return err
+	}
+	g.Branches = append(g.Branches, name)
+	g.Heads[name] = commit
+	g.Current = name
+	g.record("%s %s %s", method, name, startPoint)
+	return nil
+}
+
+func (g *FakeGit) FastForwardBranch(branch, target string) error {
+	if err := g.errors["FastForwardBranch"]; err != nil {
+		return err
+	}
+	commit, err := g.resolve(target)
+	if err != nil {
+		return err
+	}
+	if !g.reachable(commit)[g.Heads[branch]] {
+		return fmt.Errorf("cannot fast-forward '%s' to '%s': non-fast-forward", branch, target)
+	}
+	g.Heads[branch] = commit
+	g.record("FastForwardBranch %s %s", branch, target)
+	return nil
+}
+
+func (g *FakeGit) RenameBranch(oldBranch, newBranch string) error {
+	if err := g.errors["RenameBranch"]; err != nil {
+		return err
+	}
+	i := indexOf(g.Branches, oldBranch)
+	if i < 0 {
+		return fmt.Errorf("no branch named '%s'", oldBranch)
+	}
+	g.Branches[i] = newBranch
+	g.Heads[newBranch] = g.Heads[oldBranch]
+	delete(g.Heads, oldBranch)
+	if g.Current == oldBranch {
+		g.Current = newBranch
+	}
+	g.record("RenameBranch %s %s", oldBranch, newBranch)
+	return nil
+}
+
+func (g *FakeGit) DeleteBranch(branch string, force bool) error {
+	if err := g.errors["DeleteBranch"]; err != nil {
+		return err
+	}
+	i := indexOf(g.Branches, branch)
+	if i < 0 {
+		return fmt.Errorf("branch '%s' not found", branch)
+	}
+	if branch == g.Current {
+		return fmt.Errorf("cannot delete branch '%s' checked out", branch)
+	}
+	g.Branches = append(g.Branches[:i], g.Branches[i+1:]...)
+	delete(g.Heads, branch)
+	call := "DeleteBranch " + branch
+	if force {
+		call += " force"
+	}
+	g.Calls = append(g.Calls, call)
+	return nil
+}
+
+func (g *FakeGit) GetBaseBranch(branchName string) (string, error) {
+	base, ok := g.Bases[branchName]
+	if !ok {
+		return "", fmt.Errorf("no base branch stored for '%s'", branchName)
+	}
+	return base, nil
+}
+
+func (g *FakeGit) SetBaseBranch(branchName, baseBranch string) error {
+	if err := g.errors["SetBaseBranch"]; err != nil {
+		return err
+	}
+	g.Bases[branchName] = baseBranch
+	return nil
+}
+
+func (g *FakeGit) GetBranchDescription(branchName string) string {
+	return g.Descriptions[branchName]
+}
+
+func (g *FakeGit) SetBranchDescription(branchName, description string) error {
+	g.Descriptions[branchName] = description
+	return nil
+}
+
+// ListWorktrees returns the one working tree of the fake
+func (g *FakeGit) ListWorktrees() ([]git.Worktree, error) {
+	return []git.Worktree{{Path: g.GitDir, GitDir: g.GitDir, Branch: g.Current, Current: true}}, nil
+}
+
+func (g *FakeGit) WorktreeOfBranch(branch string) string {
+	return ""
+}
+
+// Working tree
+
+func (g *FakeGit) HasUncommittedChanges() (bool, error) {
+	return g.Dirty, nil
+}
+
+func (g *FakeGit) GetOperationInProgress() (string, error) {
+	if g.stopped == nil {
+		return "", nil
+	}
+	return g.stopped.kind, nil
+}
+
+func (g *FakeGit) HasConflicts() bool {
+	return g.stopped != nil && len(g.stopped.unmerged) > 0
+}
+
+func (g *FakeGit) UnmergedFiles() ([]string, error) {
+	if g.stopped == nil {
+		return nil, nil
+	}
+	return append([]string(nil), g.stopped.unmerged...), nil
+}
+
+func (g *FakeGit) ResolveConflictFavoring(path, side string) error {
+	if g.stopped == nil || indexOf(g.stopped.unmerged, path) < 0 {
+		return fmt.Errorf("path '%s' is not unmerged", path)
+	}
+	g.Resolve(path)
+	return nil
+}
+
+// Resolve marks the unmerged files of a stopped merge or rebase resolved,
+// all of them when none are given
+func (g *FakeGit) Resolve(files ...string) {
+	if g.stopped == nil {
+		return
+	}
+	if len(files) == 0 {
+		g.stopped.unmerged = nil
+		return
+	}
+	for _, file := range files {
+		if i := indexOf(g.stopped.unmerged, file); i >= 0 {
+			g.stopped.unmerged = append(g.stopped.unmerged[:i], g.stopped.unmerged[i+1:]...)
+		}
+	}
+}
+
+func (g *FakeGit) UntrackedFilesIn(branch string) ([]string, error) {
+	return nil, nil
+}
+
+func (g *FakeGit) StashUntracked(message string) (string, error) {
+	return "", nil
+}
+
+func (g *FakeGit) RestoreStash(commit string) error {
+	return nil
+}
+
+// Commits and their history
+
+func (g *FakeGit) BranchCommit(branch string) (string, error) {
+	return g.resolve(branch)
+}
+
+func (g *FakeGit) IsAncestor(ancestor, descendant string) bool {
+	ancestorID, err := g.resolve(ancestor)
+	if err != nil {
+		return false
+	}
+	descendantID, err := g.resolve(descendant)
+	if err != nil {
+		return false
+	}
+	return g.reachable(descendantID)[ancestorID]
+}
+
+func (g *FakeGit) IsFirstParentAncestor(commit, branch string) bool {
+	commitID, err := g.resolve(commit)
+	if err != nil {
+		return false
+	}
+	id, err := g.resolve(branch)
+	if err != nil {
+		return false
+	}
+	for id != "" {
+		if id == commitID {
+			return true
+		}
+		parents := g.Commits[id].Parents
+		if len(parents) == 0 {
+			break
+		}
+		id = parents[0]
+	}
+	return false
+}
+
+func (g *FakeGit) AheadBehind(branch, other string) (int, int, error) {
+	ahead, err := g.rangeOf(other, branch)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := g.rangeOf(branch, other)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(ahead), len(behind), nil
+}
+
+func (g *FakeGit) CompareBranches(branch, other string) (git.BranchSyncStatus, int, error) {
+	ahead, behind, err := g.AheadBehind(branch, other)
+	if err != nil {
+		return "", 0, err
+	}
+	switch {
+	case ahead == 0 && behind == 0:
+		return git.SyncStatusEqual, 0, nil
+	case behind == 0:
+		return git.SyncStatusAhead, ahead, nil
+	case ahead == 0:
+		return git.SyncStatusBehind, behind, nil
+	default:
+		return git.SyncStatusDiverged, ahead + behind, nil
+	}
+}
+
+func (g *FakeGit) ChangesApplied(branch, target string) bool {
+	commits, err := g.rangeOf(target, branch)
+	if err != nil {
+		return false
+	}
+	targetID, _ := g.resolve(target)
+	for _, commit := range commits {
+		if !g.applied(targetID, commit) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *FakeGit) OnlyRebasedCommits(branch, upstream string) bool {
+	return g.ChangesApplied(upstream, branch)
+}
+
+func (g *FakeGit) BackMerges(branch, parent string) ([]string, error) {
+	commits, err := g.rangeOf(parent, branch)
+	if err != nil {
+		return nil, err
+	}
+	parentID, _ := g.resolve(parent)
+	inParent := g.reachable(parentID)
+	var merges []string
+	for _, commit := range commits {
+		for _, mergedIn := range g.Commits[commit].Parents[1:] {
+			if inParent[mergedIn] {
+				merges = append(merges, commit)
+				break
+			}
+		}
+	}
+	return merges, nil
+}
+
+func (g *FakeGit) CommitSubjects(base, branch string) ([]string, error) {
+	commits, err := g.rangeOf(base, branch)
+	if err != nil {
+		return nil, err
+	}
+	subjects := make([]string, len(commits))
+	for i, commit := range commits {
+		subjects[len(commits)-1-i] = g.Commits[commit].Subject
+	}
+	return subjects, nil
+}
+
+func (g *FakeGit) CommitTrailers(revRange string, key string) ([]string, error) {
+	return nil, nil
+}
+
+// DiffShortStat counts each commit as one changed file
+func (g *FakeGit) DiffShortStat(base, branch string) (git.DiffStat, error) {
+	commits, err := g.rangeOf(base, branch)
+	if err != nil {
+		return git.DiffStat{}, err
+	}
+	return git.DiffStat{Files: len(commits), Insertions: len(commits)}, nil
+}
+
+func (g *FakeGit) GetCommitSignature(rev string) (*git.CommitSignature, error) {
+	if _, err := g.resolve(rev); err != nil {
+		return nil, err
+	}
+	return &git.CommitSignature{Status: "N"}, nil
+}
+
+func (g *FakeGit) BackportCommits(base, branch string) ([]string, error) {
+	commits, err := g.rangeOf(base, branch)
+	if err != nil {
+		return nil, err
+	}
+	var backports []string
+	for _, commit := range commits {
+		if len(g.Commits[commit].Parents) < 2 {
+			backports = append(backports, commit)
+		}
+	}
+	return backports, nil
+}
+
+func (g *FakeGit) UnappliedCommits(target string, commits []string) ([]string, error) {
+	targetID, err := g.resolve(target)
+	if err != nil {
+		return nil, err
+	}
+	var unapplied []string
+	for _, commit := range commits {
+		if !g.applied(targetID, commit) {
+			unapplied = append(unapplied, commit)
+		}
+	}
+	return unapplied, nil
+}
+
+// Merges, rebases and commits
+
+func (g *FakeGit) Merge(ctx context.Context, branch string, noVerify bool) error {
+	return g.merge("Merge", branch, fmt.Sprintf("Merge branch '%s'", branch), false, false, false)
+}
+
+func (g *FakeGit) MergeWithOptions(ctx context.Context, branchName string, noFF bool, noVerify bool) error {
+	return g.merge("MergeWithOptions", branchName, fmt.Sprintf("Merge branch '%s'", branchName), noFF, false, false)
+}
+
+func (g *FakeGit) MergeWithMessage(ctx context.Context, branchName string, message string, noFF bool, noVerify bool) error {
+	return g.merge("MergeWithMessage", branchName, message, noFF, false, false)
+}
+
+func (g *FakeGit) MergeFastForwardOnly(ctx context.Context, branchName string) error {
+	return g.merge("MergeFastForwardOnly", branchName, "", false, true, false)
+}
+
+func (g *FakeGit) SquashMerge(ctx context.Context, branch string, noVerify bool) error {
+	return g.merge("SquashMerge", branch, fmt.Sprintf("Squashed commit of branch '%s'", branch), false, false, true)
+}
+
+func (g *FakeGit) MergeSquashWithMessage(ctx context.Context, branchName string, message string, noVerify bool) error {
+	return g.merge("MergeSquashWithMessage", branchName, message, false, false, true)
+}
+
+// merge merges source into the current branch. A merge stopped on conflicts
+// is completed by Commit.
+func (g *FakeGit) merge(method, source, message string, noFF, ffOnly, squash bool) error {
+	if err := g.errors[method]; err != nil {
+		return err
+	}
+	sourceID, err := g.resolve(source)
+	if err != nil {
+		return err
+	}
+	head := g.Heads[g.Current]
+	if g.reachable(head)[sourceID] {
+		return nil
+	}
+	fastForward := g.reachable(sourceID)[head]
+	if ffOnly && !fastForward {
+		return fmt.Errorf("fatal: Not possible to fast-forward, aborting")
+	}
+	if files, ok := g.conflicts[source]; ok {
+		g.stopped = &stoppedOperation{kind: "merge", branch: g.Current, origHead: head, source: sourceID, squash: squash, unmerged: append([]string(nil), files...)}
+		g.record("%s %s conflict", method, source)
+		return fmt.Errorf("merge conflict in %s", strings.Join(files, ", "))
+	}
+	switch {
+	case squash:
+		g.Heads[g.Current] = g.newCommit(message, head)
+	case fastForward && !noFF:
+		g.Heads[g.Current] = sourceID
+	default:
+		g.Heads[g.Current] = g.newCommit(message, head, sourceID)
+	}
+	g.record("%s %s", method, source)
+	return nil
+}
+
+// MergeInMemory merges without touching the working tree; conflicts leave
+// branch unchanged and report false
+func (g *FakeGit) MergeInMemory(ctx context.Context, branch, source, message string) (merged bool, err error) {
+	if err := g.errors["MergeInMemory"]; err != nil {
+		return false, err
+	}
+	sourceID, err := g.resolve(source)
+	if err != nil {
+		return false, err
+	}
+	head := g.Heads[branch]
+	if g.reachable(head)[sourceID] {
+		return true, nil
+	}
+	if _, conflicts := g.conflicts[source]; conflicts {
+		return false, nil
+	}
+	g.Heads[branch] = g.newCommit(message, head, sourceID)
+	g.record("MergeInMemory %s %s", branch, source)
+	return true, nil
+}
+
+func (g *FakeGit) MergeAbort() error {
+	if g.stopped == nil || g.stopped.kind != "merge" {
+		return fmt.Errorf("fatal: There is no merge to abort (MERGE_HEAD missing)")
+	}
+	return g.UndoStoppedOperation()
+}
+
+func (g *FakeGit) Rebase(ctx context.Context, branch string, noVerify bool) error {
+	return g.rebase("Rebase", branch)
+}
+
+func (g *FakeGit) RebaseWithOptions(ctx context.Context, targetBranch string, preserveMerges bool, noVerify bool) error {
+	return g.rebase("RebaseWithOptions", targetBranch)
+}
+
+// rebase replays the commits of the current branch onto target. A rebase
+// stopped on conflicts is completed by RebaseContinue.
+func (g *FakeGit) rebase(method, target string) error {
+	if err := g.errors[method]; err != nil {
+		return err
+	}
+	targetID, err := g.resolve(target)
+	if err != nil {
+		return err
+	}
+	head := g.Heads[g.Current]
+	commits := g.commitsBetween(targetID, head)
+	if files, ok := g.conflicts[target]; ok && len(commits) > 0 {
+		g.stopped = &stoppedOperation{kind: "rebase", branch: g.Current, origHead: head, source: targetID, unmerged: append([]string(nil), files...), replay: commits}
+		g.record("%s %s conflict", method, target)
+		return fmt.Errorf("rebase conflict in %s", strings.Join(files, ", "))
+	}
+	g.Heads[g.Current] = g.replay(targetID, commits)
+	g.record("%s %s", method, target)
+	return nil
+}
+
+func (g *FakeGit) RebaseContinue(ctx context.Context) error {
+	if err := g.errors["RebaseContinue"]; err != nil {
+		return err
+	}
+	if g.stopped == nil || g.stopped.kind != "rebase" {
+		return nil
+	}
+	if len(g.stopped.unmerged) > 0 {
+		return fmt.Errorf("rebase conflict: unmerged %s", strings.Join(g.stopped.unmerged, ", "))
+	}
+	g.Heads[g.stopped.branch] = g.replay(g.stopped.source, g.stopped.replay)
+	g.stopped = nil
+	g.record("RebaseContinue")
+	return nil
+}
+
+func (g *FakeGit) RebaseAbort() error {
+	if g.stopped == nil || g.stopped.kind != "rebase" {
+		return fmt.Errorf("fatal: No rebase in progress?")
+	}
+	return g.UndoStoppedOperation()
+}
+
+func (g *FakeGit) UndoStoppedOperation() error {
+	if g.stopped == nil {
+		return nil
+	}
+	g.Heads[g.stopped.branch] = g.stopped.origHead
+	g.Current = g.stopped.branch
+	g.record("Undo %s", g.stopped.kind)
+	g.stopped = nil
+	return nil
+}
+
+// Commit commits a merge stopped on conflicts once they are resolved, or
+// adds a commit to the current branch
+func (g *FakeGit) Commit(ctx context.Context, message string, noVerify bool) error {
+	if err := g.errors["Commit"]; err != nil {
+		return err
+	}
+	head := g.Heads[g.Current]
+	switch {
+	case g.stopped == nil:
+		g.Heads[g.Current] = g.newCommit(message, head)
+	case len(g.stopped.unmerged) > 0:
+		return fmt.Errorf("committing is not possible because you have unmerged files")
+	case g.stopped.squash:
+		g.Heads[g.Current] = g.newCommit(message, head)
+		g.stopped = nil
+	default:
+		g.Heads[g.Current] = g.newCommit(message, head, g.stopped.source)
+		g.stopped = nil
+	}
+	g.record("Commit %s", message)
+	return nil
+}
+
+func (g *FakeGit) CherryPickOnto(ctx context.Context, branch, startPoint string, commits []string) error {
+	if err := g.errors["CherryPickOnto"]; err != nil {
+		return err
+	}
+	start, err := g.resolve(startPoint)
+	if err != nil {
+		return err
+	}
+	if !g.HasBranch(branch) {
+		g.Branches = append(g.Branches, branch)
+	}
+	g.Heads[branch] = g.replay(start, commits)
+	g.record("CherryPickOnto %s %s", branch, startPoint)
+	return nil
+}
+
+// Tags
+
+func (g *FakeGit) TagExists(name string) bool {
+	_, ok := g.Tags[name]
+	return ok
+}
+
+func (g *FakeGit) TagCommit(name string) (string, error) {
+	commit, ok := g.Tags[name]
+	if !ok {
+		return "", fmt.Errorf("tag '%s' not found", name)
+	}
+	return commit, nil
+}
+
+func (g *FakeGit) IsValidTagName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " ~^:?*[\\") && !strings.Contains(name, "..")
+}
+
+func (g *FakeGit) CreateTag(tagName string, options *git.TagOptions) error {
+	if err := g.errors["CreateTag"]; err != nil {
+		return err
+	}
+	if g.TagExists(tagName) && !options.Force {
+		return nil
+	}
+	target := options.Target
+	if target == "" {
+		target = "HEAD"
+	}
+	commit, err := g.resolve(target)
+	if err != nil {
+		return err
+	}
+	g.Tags[tagName] = commit
+	g.record("CreateTag %s", tagName)
+	return nil
+}
+
+func (g *FakeGit) MoveTags(targets map[string]string) error {
+	for name, target := range targets {
+		commit, err := g.resolve(target)
+		if err != nil {
+			return err
+		}
+		g.Tags[name] = commit
+	}
+	return nil
+}
+
+// LatestTagExcluding returns the tag on the nearest commit reachable from
+// rev, ignoring the tags matching the exclude pattern
+func (g *FakeGit) LatestTagExcluding(rev string, exclude string) (string, error) {
+	id, err := g.resolve(rev)
+	if err != nil {
+		return "", err
+	}
+	commits := g.commitsBetween("", id)
+	for i := len(commits) - 1; i >= 0; i-- {
+		for name, commit := range g.Tags {
+			if commit != commits[i] {
+				continue
+			}
+			if matched, _ := path.Match(exclude, name); exclude == "" || !matched {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("fatal: No names found, cannot describe anything")
+}
+
+func (g *FakeGit) ReleaseCandidates(branchName string) ([]git.ReleaseCandidate, error) {
+	return nil, nil
+}
+
+func (g *FakeGit) ClearReleaseCandidates(branchName string) error {
+	return nil
+}
+
+// Remotes as the local repository knows them
+
+func (g *FakeGit) RemoteExists(remote string) bool {
+	_, ok := g.Remotes[remote]
+	return ok
+}
+
+func (g *FakeGit) RemoteBranchExists(remote, branch string) bool {
+	return indexOf(g.Remotes[remote], branch) >= 0
+}
+
+func (g *FakeGit) RemoteBranches(remote string) ([]string, error) {
+	branches := append([]string(nil), g.Remotes[remote]...)
+	sort.Strings(branches)
+	return branches, nil
+}
+
+func (g *FakeGit) GetRemoteURL(remote string) (string, error) {
+	if !g.RemoteExists(remote) {
+		return "", fmt.Errorf("no such remote '%s'", remote)
+	}
+	return fmt.Sprintf("https://example.com/%s.git", remote), nil
+}
+
+func (g *FakeGit) GetTrackingBranch(branch string) (string, error) {
+	upstream, ok := g.Upstreams[branch]
+	if !ok {
+		return "", fmt.Errorf("branch '%s' has no upstream tracking branch", branch)
+	}
+	return upstream, nil
+}
+
+func (g *FakeGit) SetUpstream(branch, remote, remoteBranch string) error {
+	g.Upstreams[branch] = remote + "/" + remoteBranch
+	return nil
+}
+
+func (g *FakeGit) PushDestination(branch, remote string) (string, error) {
+	return branch, nil
+}
+
+func (g *FakeGit) PushExclusion(remote, ref string) string {
+	return ""
+}
+
+// Remote
+
+func (g *FakeGit) CheckRemoteReachable(ctx context.Context, remote string) error {
+	if err := g.errors["CheckRemoteReachable"]; err != nil {
+		return err
+	}
+	if _, ok := g.Remotes[remote]; !ok {
+		return fmt.Errorf("'%s' does not appear to be a git repository", remote)
+	}
+	return nil
+}
+
+func (g *FakeGit) Fetch(ctx context.Context, remote string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.errors["Fetch"]; err != nil {
+		return err
+	}
+	g.record("Fetch %s", remote)
+	return nil
+}
+
+func (g *FakeGit) FetchBranch(ctx context.Context, remote, branch string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := g.errors["FetchBranch"]; err != nil {
+		return err
+	}
+	g.record("FetchBranch %s %s", remote, branch)
+	return nil
+}
+
+func (g *FakeGit) PushBranch(ctx context.Context, remote, branch, remoteBranch string, pushOptions []string, track, withLease bool) error {
+	if err := g.errors["PushBranch"]; err != nil {
+		return err
+	}
+	if commit, ok := g.Heads[branch]; ok {
+		g.SetRemoteBranch(remote, remoteBranch, commit)
+	} else if indexOf(g.Remotes[remote], remoteBranch) < 0 {
+		g.Remotes[remote] = append(g.Remotes[remote], remoteBranch)
+	}
+	if track {
+		g.Upstreams[branch] = remote + "/" + remoteBranch
+	}
+	call := fmt.Sprintf("PushBranch %s %s %s", remote, branch, remoteBranch)
+	if track {
+		call += " track"
+	}
+	if withLease {
+		call += " lease"
+	}
+	g.Calls = append(g.Calls, call)
+	return nil
+}
+
+func (g *FakeGit) PushRefs(ctx context.Context, remote string, refspecs, leased []string) error {
+	if err := g.errors["PushRefs"]; err != nil {
+		return err
+	}
+	pushed := append(append([]string(nil), refspecs...), leased...)
+	for _, refspec := range pushed {
+		src := strings.TrimPrefix(refspec, "+")
+		dst := src
+		if s, d, found := strings.Cut(src, ":"); found {
+			src, dst = s, d
+		}
+		if strings.HasPrefix(dst, "refs/tags/") {
+			continue
+		}
+		branch := strings.TrimPrefix(dst, "refs/heads/")
+		if commit, err := g.resolve(src); err == nil {
+			g.SetRemoteBranch(remote, branch, commit)
+		} else if indexOf(g.Remotes[remote], branch) < 0 {
+			g.Remotes[remote] = append(g.Remotes[remote], branch)
+		}
+	}
+	g.record("PushRefs %s %s", remote, strings.Join(pushed, " "))
+	return nil
+}
+
+func (g *FakeGit) DeleteRemoteBranch(ctx context.Context, remote, branch string) error {
+	if err := g.errors["DeleteRemoteBranch"]; err != nil {
+		return err
+	}
+	i := indexOf(g.Remotes[remote], branch)
+	if i < 0 {
+		return fmt.Errorf("unable to delete '%s': remote ref does not exist", branch)
+	}
+	g.Remotes[remote] = append(g.Remotes[remote][:i], g.Remotes[remote][i+1:]...)
+	delete(g.RemoteHeads, remote+"/"+branch)
+	g.record("DeleteRemoteBranch %s %s", remote, branch)
+	return nil
+}
+
+// FakeConfig is an in-memory implementation of commands.ConfigStore
+type FakeConfig map[string]string
+
+func (c FakeConfig) Get(key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", fmt.Errorf("config key '%s' not found", key)
+	}
+	return value, nil
+}
+
+func (c FakeConfig) GetAll(key string) ([]string, error) {
+	value, ok := c[key]
+	if !ok {
+		return nil, fmt.Errorf("config key '%s' not found", key)
+	}
+	return []string{value}, nil
+}
+
+func (c FakeConfig) Set(key, value string) error {
+	c[key] = value
+	return nil
+}
+
+func (c FakeConfig) Unset(key string) error {
+	delete(c, key)
+	return nil
+}
+
+// FakePrompter answers questions from a list and records the prompts
+type FakePrompter struct {
+	// Answers are returned by Confirm, Replies by Ask
+	Answers []bool
+	Replies []string
+	Prompts []string
+}
+
+// Confirm returns the next answer, or defaultYes when there is none left
+func (p *FakePrompter) Confirm(prompt string, defaultYes bool) bool {
+	p.Prompts = append(p.Prompts, prompt)
+	if len(p.Answers) == 0 {
+		return defaultYes
+	}
+	answer := p.Answers[0]
+	p.Answers = p.Answers[1:]
+	return answer
+}
+
+// Ask returns the next reply, or "" when there is none left
+func (p *FakePrompter) Ask(prompt string) string {
+	p.Prompts = append(p.Prompts, prompt)
+	if len(p.Replies) == 0 {
+		return ""
+	}
+	reply := p.Replies[0]
+	p.Replies = p.Replies[1:]
+	return reply
+}
+
+// FakeClock always returns Time
+type FakeClock struct {
+	Time time.Time
+}
+
+func (c FakeClock) Now() time.Time {
+	return c.Time
+}
+
+// NewFakeDeps returns command dependencies backed by fake and config, with an
+// empty Git directory so no hooks run and the merge state is kept apart, and
+// the buffer receiving the output
+func NewFakeDeps(t *testing.T, fake *FakeGit, config FakeConfig) (*commands.Deps, *bytes.Buffer) {
+	t.Helper()
+	if fake.GitDir == "" {
+		fake.GitDir = t.TempDir()
+	}
+	if config == nil {
+		config = FakeConfig{}
+	}
+	out := &bytes.Buffer{}
+	return &commands.Deps{
+		Git:      fake,
+		Remote:   fake,
+		Config:   config,
+		State:    mergestate.Store{GitDir: fake.GitDir},
+		Prompter: &FakePrompter{},
+		Clock:    FakeClock{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Events:   events.NewBus(),
+		Out:      out,
+		Err:      &bytes.Buffer{},
+	}, out
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
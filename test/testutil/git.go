@@ -30,6 +30,11 @@ func init() {
 	gitFlowPath = filepath.Join(wd, "git-flow")
 }
 
+// GitFlowPath returns the path of the git-flow binary under test
+func GitFlowPath() string {
+	return gitFlowPath
+}
+
 // RunGit runs a git command in the specified directory and returns its output
 func RunGit(t *testing.T, dir string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
@@ -85,6 +90,59 @@ func RunGitFlowWithInput(t *testing.T, dir string, input string, args ...string)
 	return string(output), nil
 }
 
+// RunGitFlowWithEditor runs a git-flow command with GIT_EDITOR set to editor
+// and returns its output. An empty editor leaves GIT_EDITOR unset, so that
+// core.editor is used.
+func RunGitFlowWithEditor(t *testing.T, dir string, editor string, args ...string) (string, error) {
+	cmd := exec.Command(gitFlowPath, args...)
+	cmd.Dir = dir
+	for _, entry := range os.Environ() {
+		if !strings.HasPrefix(entry, "GIT_EDITOR=") {
+			cmd.Env = append(cmd.Env, entry)
+		}
+	}
+	if editor != "" {
+		cmd.Env = append(cmd.Env, "GIT_EDITOR="+editor)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), &ExitError{
+				ExitCode: exitErr.ExitCode(),
+				Err:      fmt.Errorf("%s", output),
+			}
+		}
+		return string(output), err
+	}
+	return string(output), nil
+}
+
+// RunGitSubcommand runs git with the binary under test on PATH as git-flow, so
+// that 'git flow ...' is dispatched to it, and returns the output. The extra
+// environment entries are added to the command's environment.
+func RunGitSubcommand(t *testing.T, dir string, env []string, args ...string) (string, error) {
+	binDir := t.TempDir()
+	if err := os.Symlink(gitFlowPath, filepath.Join(binDir, "git-flow")); err != nil {
+		t.Fatalf("Failed to link git-flow into %s: %v", binDir, err)
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=:", "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	cmd.Env = append(cmd.Env, env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), &ExitError{
+				ExitCode: exitErr.ExitCode(),
+				Err:      fmt.Errorf("%s", output),
+			}
+		}
+		return string(output), err
+	}
+	return string(output), nil
+}
+
 // SetupTestRepo creates a temporary Git repository for testing
 func SetupTestRepo(t *testing.T) string {
 	// Create temporary directory
@@ -140,12 +198,52 @@ func WriteFile(t *testing.T, dir string, name string, content string) error {
 	return os.WriteFile(path, []byte(content), 0644)
 }
 
+// InitGitFlow initializes git-flow with the default configuration
+func InitGitFlow(t *testing.T, dir string) {
+	t.Helper()
+	if output, err := RunGitFlow(t, dir, "init", "--defaults"); err != nil {
+		t.Fatalf("Failed to initialize git-flow: %v\nOutput: %s", err, output)
+	}
+}
+
+// CommitFile writes content to file and commits it with message
+func CommitFile(t *testing.T, dir string, file string, content string, message string) {
+	t.Helper()
+	WriteFile(t, dir, file, content)
+	RunGit(t, dir, "add", file)
+	if output, err := RunGit(t, dir, "commit", "-m", message); err != nil {
+		t.Fatalf("Failed to commit %s: %v\nOutput: %s", file, err, output)
+	}
+}
+
+// StartBranchWithCommit starts the topic branch name of branchType with git-flow
+// and commits <name>.txt to it. startArgs follow the name on the command line,
+// e.g. the base branch.
+func StartBranchWithCommit(t *testing.T, dir string, branchType string, name string, startArgs ...string) {
+	t.Helper()
+	args := append([]string{branchType, "start", name}, startArgs...)
+	if output, err := RunGitFlow(t, dir, args...); err != nil {
+		t.Fatalf("Failed to start %s '%s': %v\nOutput: %s", branchType, name, err, output)
+	}
+	CommitFile(t, dir, name+".txt", name, "Add "+name)
+}
+
 // BranchExists checks if a branch exists in the repository
 func BranchExists(t *testing.T, dir string, branch string) bool {
 	_, err := RunGit(t, dir, "rev-parse", "--verify", branch)
 	return err == nil
 }
 
+// RevParse returns the commit a revision, such as a branch or an annotated tag, points to
+func RevParse(t *testing.T, dir string, rev string) string {
+	t.Helper()
+	output, err := RunGit(t, dir, "rev-parse", rev+"^{commit}")
+	if err != nil {
+		t.Fatalf("Failed to resolve '%s': %v\nOutput: %s", rev, err, output)
+	}
+	return strings.TrimSpace(output)
+}
+
 // GetCurrentBranch returns the name of the current Git branch
 func GetCurrentBranch(t *testing.T, dir string) string {
 	output, err := RunGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD")
@@ -196,6 +294,27 @@ func AddRemote(t *testing.T, dir string, remoteName string, pushAll bool) (strin
 	return bareDir, nil
 }
 
+// PushFromClone clones remoteDir, commits file to branch and pushes it,
+// simulating a teammate pushing to the remote
+func PushFromClone(t *testing.T, remoteDir string, branch string, file string) {
+	t.Helper()
+	cloneDir := t.TempDir()
+	if output, err := RunGit(t, cloneDir, "clone", remoteDir, "."); err != nil {
+		t.Fatalf("Failed to clone: %v\nOutput: %s", err, output)
+	}
+	RunGit(t, cloneDir, "config", "user.name", "Other User")
+	RunGit(t, cloneDir, "config", "user.email", "other@example.com")
+	if output, err := RunGit(t, cloneDir, "checkout", branch); err != nil {
+		t.Fatalf("Failed to checkout '%s' in clone: %v\nOutput: %s", branch, err, output)
+	}
+	WriteFile(t, cloneDir, file, file)
+	RunGit(t, cloneDir, "add", file)
+	RunGit(t, cloneDir, "commit", "-m", "Add "+file)
+	if output, err := RunGit(t, cloneDir, "push", "origin", branch); err != nil {
+		t.Fatalf("Failed to push '%s' from clone: %v\nOutput: %s", branch, err, output)
+	}
+}
+
 // RemoteBranchExists checks if a remote branch exists in the repository
 func RemoteBranchExists(t *testing.T, dir string, remote string, branch string) bool {
 	ref := fmt.Sprintf("refs/remotes/%s/%s", remote, branch)
@@ -12,6 +12,20 @@ const (
 	GitCommit = ""
 )
 
+// Distribution information, injected during packaging with
+// -ldflags "-X github.com/gittower/git-flow-next/version.<Name>=<value>"
+var (
+	// InstallMethod names the package manager the build is distributed with
+	// (e.g. "homebrew", "scoop"), set by scripts/build.sh from INSTALL_METHOD.
+	// self-update is disabled for such builds.
+	InstallMethod = ""
+
+	// ReleasePublicKey is the base64-encoded Ed25519 key the checksums of
+	// releases are signed with. self-update requires a valid signature and is
+	// disabled for builds without a key.
+	ReleasePublicKey = ""
+)
+
 // GetVersionInfo returns a formatted version string
 func GetVersionInfo() string {
 	if BuildTime != "" && GitCommit != "" {